@@ -0,0 +1,245 @@
+// Command a2a-call calls an A2A agent over JSON-RPC and pretty-prints the
+// result, for trying out deployed agents without hand-writing requests:
+//
+//	a2a-call -url https://agent.example.com send "What's the weather?"
+//	a2a-call -url https://agent.example.com get <task ID>
+//	a2a-call -url https://agent.example.com cancel <task ID>
+//	a2a-call -url https://agent.example.com stream "Summarize this"
+//
+// The agent card is fetched first, as pkg/client's Discover does, so the
+// endpoint and the credentials sent are the ones the card asks for; -discover
+// =false calls -url as the JSON-RPC endpoint with every credential given.
+// Secrets may be passed in the environment rather than on the command line.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/google/uuid"
+
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+// Commands, one per A2A method
+const (
+	commandSend   = "send"
+	commandGet    = "get"
+	commandCancel = "cancel"
+	commandStream = "stream"
+)
+
+// callOptions are the flags that shape a call rather than the connection
+type callOptions struct {
+	ContextID     string
+	TaskID        string
+	HistoryLength int
+	Compact       bool
+}
+
+// authFlags are the credentials to offer the agent
+type authFlags struct {
+	apiKey          string
+	apiKeyHeader    string
+	clientID        string
+	clientSecret    string
+	tokenURL        string
+	scopes          string
+	sigV4           bool
+	sigV4Service    string
+	signingKey      string
+	signatureHeader string
+}
+
+// credentials builds the credentials the flags name
+func (f authFlags) credentials(ctx context.Context) ([]client.Credentials, error) {
+	var credentials []client.Credentials
+	if f.apiKey != "" {
+		credentials = append(credentials, client.APIKeyCredentials{Key: f.apiKey, Header: f.apiKeyHeader})
+	}
+	if f.clientID != "" {
+		var scopes []string
+		if f.scopes != "" {
+			scopes = strings.Split(f.scopes, ",")
+		}
+		credentials = append(credentials, client.NewOAuth2ClientCredentials(f.clientID, f.clientSecret, f.tokenURL, scopes...))
+	}
+	if f.sigV4 {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		credentials = append(credentials, client.NewSigV4Credentials(cfg, f.sigV4Service))
+	}
+	if f.signingKey != "" {
+		if f.signatureHeader == "" {
+			return nil, errors.New("-signature-header is required with a signing key")
+		}
+		credentials = append(credentials, client.NewSignatureCredentials(f.signingKey, f.signatureHeader))
+	}
+	return credentials, nil
+}
+
+func main() {
+	agentURL := flag.String("url", os.Getenv("A2A_CALL_URL"), "Agent base URL, or its JSON-RPC endpoint with -discover=false (default $A2A_CALL_URL)")
+	discover := flag.Bool("discover", true, "Fetch the agent card to find the endpoint and the credentials it accepts")
+	timeout := flag.Duration("timeout", 30*time.Second, "Timeout for a call; a stream may run this long in total")
+	var opts callOptions
+	flag.StringVar(&opts.ContextID, "context-id", "", "Context to continue (send, stream)")
+	flag.StringVar(&opts.TaskID, "task-id", "", "Task to continue (send, stream)")
+	flag.IntVar(&opts.HistoryLength, "history", -1, "Most recent messages to return (get); all when negative")
+	flag.BoolVar(&opts.Compact, "compact", false, "Print one JSON document per line instead of indented JSON")
+	var auth authFlags
+	flag.StringVar(&auth.apiKey, "api-key", os.Getenv("A2A_CALL_API_KEY"), "API key to send (default $A2A_CALL_API_KEY)")
+	flag.StringVar(&auth.apiKeyHeader, "api-key-header", "", "Header for the API key; defaults to the one the card names")
+	flag.StringVar(&auth.clientID, "client-id", "", "OAuth2 client ID for the client credentials grant")
+	flag.StringVar(&auth.clientSecret, "client-secret", os.Getenv("A2A_CALL_CLIENT_SECRET"), "OAuth2 client secret (default $A2A_CALL_CLIENT_SECRET)")
+	flag.StringVar(&auth.tokenURL, "token-url", "", "OAuth2 token URL; defaults to the one the card names")
+	flag.StringVar(&auth.scopes, "scopes", "", "Comma-separated OAuth2 scopes")
+	flag.BoolVar(&auth.sigV4, "sigv4", false, "Sign calls with AWS SigV4 using the default AWS credentials")
+	flag.StringVar(&auth.sigV4Service, "sigv4-service", client.DefaultSigV4Service, `SigV4 signing name, "lambda" for function URLs`)
+	flag.StringVar(&auth.signingKey, "signing-key", os.Getenv("A2A_CALL_SIGNING_KEY"), "Shared key for signed requests (default $A2A_CALL_SIGNING_KEY)")
+	flag.StringVar(&auth.signatureHeader, "signature-header", "", "Header for request signatures, as in security.signature_header")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: a2a-call [flags] send|stream <text, or - for stdin>\n       a2a-call [flags] get|cancel <task ID>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *agentURL == "" || flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	credentials, err := auth.credentials(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	clientOpts := []client.Option{client.WithHTTPClient(&http.Client{Timeout: *timeout}), client.WithCredentials(credentials...)}
+	var c *client.Client
+	if *discover {
+		c, err = client.Discover(ctx, *agentURL, clientOpts...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		c = client.New(*agentURL, clientOpts...)
+	}
+
+	if err := run(ctx, c, os.Stdout, os.Stdin, flag.Arg(0), flag.Args()[1:], opts); err != nil {
+		var rpcErr *client.RPCError
+		if errors.As(err, &rpcErr) {
+			fmt.Fprintln(os.Stderr, "the agent returned an error:")
+			printJSON(os.Stderr, rpcErr, opts.Compact)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run makes the call command names and prints its result to out, or each
+// event as it arrives for stream
+func run(ctx context.Context, c *client.Client, out io.Writer, stdin io.Reader, command string, args []string, opts callOptions) error {
+	switch command {
+	case commandSend, commandStream:
+		text, err := messageText(args, stdin)
+		if err != nil {
+			return err
+		}
+		params := a2a.MessageSendParams{Message: newMessage(text, opts)}
+		if command == commandSend {
+			result, err := c.SendMessage(ctx, params)
+			if err != nil {
+				return err
+			}
+			return printJSON(out, result, opts.Compact)
+		}
+		for event, err := range c.SendStreamingMessage(ctx, params) {
+			if err != nil {
+				return err
+			}
+			if err := printJSON(out, event, opts.Compact); err != nil {
+				return err
+			}
+		}
+		return nil
+	case commandGet, commandCancel:
+		if len(args) != 1 {
+			return fmt.Errorf("%s takes one task ID", command)
+		}
+		var task a2a.Task
+		var err error
+		if command == commandGet {
+			params := a2a.TaskQueryParams{ID: a2a.TaskID(args[0])}
+			if opts.HistoryLength >= 0 {
+				params.HistoryLength = &opts.HistoryLength
+			}
+			task, err = c.GetTask(ctx, params)
+		} else {
+			task, err = c.CancelTask(ctx, a2a.TaskIDParams{ID: a2a.TaskID(args[0])})
+		}
+		if err != nil {
+			return err
+		}
+		return printJSON(out, task, opts.Compact)
+	default:
+		return fmt.Errorf("unknown command %q: expected send, get, cancel or stream", command)
+	}
+}
+
+// messageText joins args into the message text, or reads it from stdin
+// when the only argument is "-"
+func messageText(args []string, stdin io.Reader) (string, error) {
+	if len(args) == 1 && args[0] == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the message from stdin: %w", err)
+		}
+		return strings.TrimSuffix(string(data), "\n"), nil
+	}
+	if len(args) == 0 {
+		return "", errors.New("no message text given")
+	}
+	return strings.Join(args, " "), nil
+}
+
+// newMessage is a user text message, continuing the context and task opts name
+func newMessage(text string, opts callOptions) a2a.Message {
+	message := a2a.Message{
+		Kind:      "message",
+		MessageID: uuid.NewString(),
+		Role:      a2a.MessageRoleUser,
+		Parts:     []a2a.Part{a2a.TextPart{Kind: "text", Text: text}},
+	}
+	if opts.ContextID != "" {
+		message.ContextID = &opts.ContextID
+	}
+	if opts.TaskID != "" {
+		taskID := a2a.TaskID(opts.TaskID)
+		message.TaskID = &taskID
+	}
+	return message
+}
+
+// printJSON writes v indented, or on a single line when compact
+func printJSON(out io.Writer, v any, compact bool) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	if !compact {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
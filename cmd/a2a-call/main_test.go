@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+// newTestClient discovers an in-process agent that streams and requires an API key
+func newTestClient(t *testing.T) *client.Client {
+	var h *handler.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers := make(map[string]string)
+		for name := range r.Header {
+			headers[strings.ToLower(name)] = r.Header.Get(name)
+		}
+		resp := h.HandleRequest(r.Context(), handler.Request{Method: r.Method, URL: r.URL.Path, Headers: headers, Body: string(body)})
+		for name, value := range resp.Headers {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(resp.Status)
+		io.WriteString(w, resp.Body)
+	}))
+	t.Cleanup(server.Close)
+
+	security := a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}
+	secrets := a2aTypes.SecretsConfig{APIKey: "secret"}
+	card := agentcard.New("Agent", server.URL, agentcard.WithStreaming(true))
+	a2aTypes.WithSecurity(security)(&card)
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil)
+	h = handler.NewHandler(a2aHandler, card, handler.WithAuthenticator(a2aTypes.NewAuthenticator(security, secrets, nil)))
+
+	credentials, err := authFlags{apiKey: "secret"}.credentials(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := client.Discover(context.Background(), server.URL, client.WithHTTPClient(server.Client()), client.WithCredentials(credentials...), client.WithCardCache(client.NewCardCache(0)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestRun(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if err := run(ctx, c, &out, nil, commandSend, []string{"hello", "there"}, callOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var task struct {
+		ID      string
+		History []struct{ Parts []struct{ Text string } }
+	}
+	if err := json.Unmarshal(out.Bytes(), &task); err != nil || task.ID == "" {
+		t.Fatalf("expected the task as JSON, got %s", out.String())
+	}
+	if !strings.Contains(out.String(), "\n  \"") || task.History[0].Parts[0].Text != "hello there" {
+		t.Errorf("expected the indented task with the message, got %s", out.String())
+	}
+
+	out.Reset()
+	if err := run(ctx, c, &out, nil, commandGet, []string{task.ID}, callOptions{HistoryLength: 0, Compact: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out.String(), "\n") != 1 || strings.Contains(out.String(), "hello there") {
+		t.Errorf("expected the task on one line without its history, got %s", out.String())
+	}
+
+	out.Reset()
+	if err := run(ctx, c, &out, nil, commandCancel, []string{task.ID}, callOptions{Compact: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"State":"canceled"`) {
+		t.Errorf("expected the canceled task, got %s", out.String())
+	}
+
+	out.Reset()
+	if err := run(ctx, c, &out, strings.NewReader("from stdin\n"), commandStream, []string{"-"}, callOptions{Compact: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines < 1 || !strings.Contains(out.String(), `"Kind":"status-update"`) {
+		t.Errorf("expected one line per streamed event, got %s", out.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	c := newTestClient(t)
+	ctx := context.Background()
+
+	var rpcErr *client.RPCError
+	if err := run(ctx, c, io.Discard, nil, commandGet, []string{"missing"}, callOptions{HistoryLength: -1}); !errors.As(err, &rpcErr) {
+		t.Errorf("expected the agent's error, got %v", err)
+	}
+	if err := run(ctx, c, io.Discard, nil, commandSend, nil, callOptions{}); err == nil {
+		t.Error("expected a send without text to be refused")
+	}
+	if err := run(ctx, c, io.Discard, nil, "list", nil, callOptions{}); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("expected an unknown command to be refused, got %v", err)
+	}
+	if _, err := (authFlags{signingKey: "key"}).credentials(ctx); err == nil {
+		t.Error("expected a signing key without a header to be refused")
+	}
+}
@@ -0,0 +1,268 @@
+// Command agentcard prints the agent card the Lambda would serve with the
+// config in the current environment, checks it against the A2A AgentCard
+// schema and what the deployment serves, and with -diff compares it with the
+// card a deployed agent is serving:
+//
+//	agentcard -diff https://agent.example.com
+//
+// A difference means the deployed agent runs with other config than the one
+// checked, which is worth knowing before clients notice. With -openapi it
+// prints an OpenAPI document of the agent's HTTP endpoints instead of the
+// card, for API gateways and client generators.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+// maxCardBytes bounds how much of a deployed card is read
+const maxCardBytes = 1 << 20
+
+func main() {
+	file := flag.String("file", "", "Use a JSON config file, as the Lambda loads it from A2A_CONFIG_FILE")
+	diff := flag.String("diff", "", "Compare with the card served by the agent at this URL")
+	timeout := flag.Duration("timeout", 10*time.Second, "Timeout for fetching the deployed card")
+	openAPI := flag.Bool("openapi", false, "Print an OpenAPI document of the agent's endpoints instead of the card")
+	flag.Parse()
+
+	ctx := context.Background()
+	serverlessConfig, err := loadConfig(ctx, *file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if *openAPI {
+		output, err := json.MarshalIndent(openAPIDocument(serverlessConfig), "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to serialize OpenAPI document: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(output))
+		return
+	}
+	card := servedCard(serverlessConfig)
+
+	output, err := json.MarshalIndent(card, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to serialize agent card: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(output))
+
+	failed := false
+	if err := validateCard(serverlessConfig, card); err != nil {
+		var validationErrs a2aTypes.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			fmt.Fprintf(os.Stderr, "agent card check failed: %d invalid field(s)\n", len(validationErrs))
+			for _, fieldErr := range validationErrs {
+				fmt.Fprintf(os.Stderr, "  %s [%s]: %s\n", fieldErr.Path, fieldErr.Code, fieldErr.Message)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "agent card check failed: %v\n", err)
+		}
+		failed = true
+	}
+
+	if *diff != "" {
+		deployed, err := fetchCard(ctx, &http.Client{Timeout: *timeout}, *diff)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		differences, err := diffCards(card, deployed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if len(differences) > 0 {
+			fmt.Fprintf(os.Stderr, "the card served at %s differs in %d field(s):\n", *diff, len(differences))
+			for _, difference := range differences {
+				fmt.Fprintf(os.Stderr, "  %s\n", difference)
+			}
+			failed = true
+		} else {
+			fmt.Fprintf(os.Stderr, "the card served at %s matches\n", *diff)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// loadConfig loads config the same way cmd/lambda does. Secrets are left
+// unresolved: none of them appear on the card.
+func loadConfig(ctx context.Context, file string) (a2aTypes.ServerlessConfig, error) {
+	if file != "" {
+		return a2aTypes.NewFileConfigSource(file)(ctx)
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return a2aTypes.ServerlessConfig{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	source, err := a2aTypes.LoadConfigSourceFromEnv(a2aTypes.ConfigSourceClients{
+		SecretsManager: secretsmanager.NewFromConfig(cfg),
+		SSM:            ssm.NewFromConfig(cfg),
+		S3:             s3.NewFromConfig(cfg),
+	})
+	if err != nil {
+		return a2aTypes.ServerlessConfig{}, err
+	}
+	if source != nil {
+		return source(ctx)
+	}
+	return a2aTypes.LoadLambdaEnvConfig(cfg.Region)
+}
+
+// servedCard is the card the Lambda would serve anonymous callers with config
+func servedCard(serverlessConfig a2aTypes.ServerlessConfig) a2a.AgentCard {
+	authenticator := a2aTypes.NewAuthenticator(serverlessConfig.Security, serverlessConfig.Secrets, nil)
+	return handler.PublicAgentCard(serverlessConfig.AgentCard, authenticator)
+}
+
+// openAPIDocument describes the endpoints the Lambda would serve with
+// config. Admin methods backed by a table the Lambda finds in its own
+// environment, such as quotas or stats, are left off.
+func openAPIDocument(serverlessConfig a2aTypes.ServerlessConfig) map[string]any {
+	authenticator := a2aTypes.NewAuthenticator(serverlessConfig.Security, serverlessConfig.Secrets, nil)
+	// The handler is only described, so it needs no storage
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(serverlessConfig, nil, nil, nil)
+	opts := []handler.Option{handler.WithAuthenticator(authenticator)}
+	if len(serverlessConfig.Localization.Locales) > 0 || serverlessConfig.Localization.DefaultLocale != "" {
+		opts = append(opts, handler.WithLocalization(serverlessConfig.Localization))
+	}
+	if admin := a2aTypes.NewAdminAuthenticator(serverlessConfig.Admin, serverlessConfig.Secrets.AdminAPIKey); admin != nil {
+		opts = append(opts, handler.WithAdminAPI(admin))
+	}
+	return handler.NewHandler(a2aHandler, serverlessConfig.AgentCard, opts...).OpenAPI()
+}
+
+// validateCard checks card against the A2A schema, and the config's card
+// against what the Lambda deployment serves
+func validateCard(serverlessConfig a2aTypes.ServerlessConfig, card a2a.AgentCard) error {
+	var errs a2aTypes.ValidationErrors
+	errs.Merge("", a2aTypes.ValidateAgentCard(card))
+	errs.Merge("", a2aTypes.ValidateAgentCardDeployment(serverlessConfig.AgentCard, a2aTypes.LambdaDeploymentFeatures(serverlessConfig)))
+	return errs.Err()
+}
+
+// fetchCard fetches the card an agent serves, as raw JSON so fields this
+// build does not know about still show up in the diff. agentURL is the
+// agent's base URL or the card's own URL.
+func fetchCard(ctx context.Context, httpClient *http.Client, agentURL string) (map[string]any, error) {
+	cardURL := agentURL
+	if !strings.HasSuffix(cardURL, ".json") {
+		cardURL = strings.TrimSuffix(cardURL, "/") + client.AgentCardPath
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cardURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agent card: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent card request to %s returned HTTP %d", cardURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCardBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent card: %w", err)
+	}
+	var card map[string]any
+	if err := json.Unmarshal(data, &card); err != nil {
+		return nil, fmt.Errorf("invalid agent card JSON from %s: %w", cardURL, err)
+	}
+	return card, nil
+}
+
+// diffCards lists the fields where card and the deployed card differ, one
+// "path: ..." line each, in path order
+func diffCards(card a2a.AgentCard, deployed map[string]any) ([]string, error) {
+	data, err := json.Marshal(card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize agent card: %w", err)
+	}
+	var local map[string]any
+	if err := json.Unmarshal(data, &local); err != nil {
+		return nil, err
+	}
+	var differences []string
+	diffJSON("", local, deployed, &differences)
+	slices.Sort(differences)
+	return differences, nil
+}
+
+// diffJSON compares two decoded JSON values, descending into objects and
+// into arrays of the same length so a change is reported where it is
+func diffJSON(path string, local, deployed any, differences *[]string) {
+	switch l := local.(type) {
+	case map[string]any:
+		if d, ok := deployed.(map[string]any); ok {
+			keys := map[string]bool{}
+			for key := range l {
+				keys[key] = true
+			}
+			for key := range d {
+				keys[key] = true
+			}
+			for key := range keys {
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				diffJSON(child, l[key], d[key], differences)
+			}
+			return
+		}
+	case []any:
+		if d, ok := deployed.([]any); ok && len(d) == len(l) {
+			for i := range l {
+				diffJSON(fmt.Sprintf("%s[%d]", path, i), l[i], d[i], differences)
+			}
+			return
+		}
+	}
+	if reflect.DeepEqual(local, deployed) {
+		return
+	}
+	*differences = append(*differences, fmt.Sprintf("%s: config has %s, deployed has %s", path, describe(local), describe(deployed)))
+}
+
+// describe renders a decoded JSON value compactly, or "nothing" when absent
+func describe(value any) string {
+	if value == nil {
+		return "nothing"
+	}
+	var out strings.Builder
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestServedCard(t *testing.T) {
+	serverlessConfig := a2aTypes.ServerlessConfig{
+		AgentCard: agentcard.New("Agent", "https://agent.example.com", agentcard.WithSkills([]a2a.AgentSkill{
+			{ID: "public", Name: "Public"},
+			{ID: "admin", Name: "Admin", Security: []map[string][]string{{"apiKey": {}}}},
+		})),
+		Security: a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"},
+	}
+	card := servedCard(serverlessConfig)
+	if len(card.Skills) != 1 || card.Skills[0].ID != "public" {
+		t.Errorf("expected only the skill anonymous callers may use, got %v", card.Skills)
+	}
+	if card.SupportsAuthenticatedExtendedCard == nil || !*card.SupportsAuthenticatedExtendedCard {
+		t.Error("expected the card to point at the extended card")
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	serverlessConfig := a2aTypes.ServerlessConfig{
+		AgentCard: agentcard.New("Agent", "https://agent.example.com"),
+		Secrets:   a2aTypes.SecretsConfig{AdminAPIKey: "admin-secret"},
+	}
+	document := openAPIDocument(serverlessConfig)
+	paths, _ := document["paths"].(map[string]any)
+	for _, path := range []string{"/.well-known/agent.json", a2aTypes.DefaultAdminPath} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("expected %s to be described, got %v", path, slices.Collect(maps.Keys(paths)))
+		}
+	}
+	if info, _ := document["info"].(map[string]any); info["title"] != "Agent" {
+		t.Errorf("expected the card's name as the title, got %v", document["info"])
+	}
+}
+
+func TestFetchAndDiffCards(t *testing.T) {
+	card := agentcard.New("Agent", "https://agent.example.com", agentcard.WithDescription("Answers questions"),
+		agentcard.WithSkills([]a2a.AgentSkill{{ID: "qa", Name: "Q&A"}}))
+	deployed := card
+	deployed.Description = "Old description"
+	deployed.Skills = []a2a.AgentSkill{{ID: "qa", Name: "Questions"}}
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		body, _ := json.Marshal(deployed)
+		// A field this build does not know about
+		body = append(body[:len(body)-1], []byte(`,"Extra":true}`)...)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	served, err := fetchCard(context.Background(), server.Client(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested != "/.well-known/agent.json" {
+		t.Errorf("expected the well-known card path, got %s", requested)
+	}
+	differences, err := diffCards(card, served)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		`Description: config has "Answers questions", deployed has "Old description"`,
+		`Extra: config has nothing, deployed has true`,
+		`Skills[0].Name: config has "Q&A", deployed has "Questions"`,
+	}
+	if !slices.Equal(differences, expected) {
+		t.Errorf("expected %q, got %q", expected, differences)
+	}
+
+	same, err := diffCards(deployed, mustDecode(t, deployed))
+	if err != nil || len(same) != 0 {
+		t.Errorf("expected no differences, got %v %v", same, err)
+	}
+}
+
+func TestFetchCardError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	if _, err := fetchCard(context.Background(), server.Client(), server.URL+"/card.json"); err == nil || !strings.Contains(err.Error(), "/card.json returned HTTP 404") {
+		t.Errorf("expected the card URL and status, got %v", err)
+	}
+}
+
+func TestValidateCard(t *testing.T) {
+	// The env config leaves out the default modes the schema requires
+	serverlessConfig := a2aTypes.ServerlessConfig{AgentCard: agentcard.New("Agent", "https://agent.example.com", agentcard.WithStreaming(true))}
+	err := validateCard(serverlessConfig, servedCard(serverlessConfig))
+	for _, expect := range []string{"agent_card.DefaultInputModes", "agent_card.Capabilities.Streaming"} {
+		if err == nil || !strings.Contains(err.Error(), expect) {
+			t.Errorf("expected %s to be reported, got %v", expect, err)
+		}
+	}
+}
+
+func mustDecode(t *testing.T, card a2a.AgentCard) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
@@ -0,0 +1,138 @@
+// Command archive moves finished tasks out of the DynamoDB tables into S3,
+// keeping hot storage small, or restores one:
+//
+//	archive -s3 s3://my-bucket/archive -older-than 720h
+//	archive -s3 s3://my-bucket/archive -restore <task ID>
+//
+// A task is archived once it is completed, failed, canceled or rejected and
+// its last status is older than -older-than: the task and its events are
+// written to one object, then deleted from the tables. Run it on a schedule;
+// a run that stops part way can be run again. With A2A_ARCHIVE_S3_URI set,
+// the Lambda restores an archived task when it is asked for, so restoring
+// by hand is rarely needed.
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// archiveReadBudget lifts the event store's read budget, which protects API
+// Gateway responses and would otherwise keep long tasks from being archived
+const archiveReadBudget = 1 << 30
+
+// taskLister enumerates every task in the task table
+type taskLister interface {
+	TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error]
+}
+
+// archiveStats counts the tasks of one run
+type archiveStats struct {
+	Archived int
+	Kept     int
+	Failed   int
+}
+
+// archiveTasks offers every listed task to the archiver. A task that fails
+// is logged and counted, and the run goes on; a failed listing stops it.
+func archiveTasks(ctx context.Context, list taskLister, archiver *a2aTypes.Archiver, olderThan time.Duration, log io.Writer) (archiveStats, error) {
+	var stats archiveStats
+	for taskID, err := range list.TaskIDs(ctx) {
+		if err != nil {
+			return stats, err
+		}
+		archived, err := archiver.ArchiveTask(ctx, taskID, olderThan)
+		switch {
+		case err != nil:
+			fmt.Fprintf(log, "task %s: %v\n", taskID, err)
+			stats.Failed++
+		case archived:
+			stats.Archived++
+		default:
+			stats.Kept++
+		}
+	}
+	return stats, nil
+}
+
+func main() {
+	tasksTable := flag.String("tasks", cmp.Or(os.Getenv("DYNAMODB_TABLE"), "a2a-tasks"), "Task table")
+	eventsTable := flag.String("events", cmp.Or(os.Getenv("DYNAMODB_EVENTS_TABLE"), a2aTypes.DefaultEventsTable), "Event table")
+	prefix := flag.String("prefix", "", "Key prefix of the agent's items in shared tables")
+	region := flag.String("region", "", "AWS region, if not the default one")
+	uri := flag.String("s3", os.Getenv("A2A_ARCHIVE_S3_URI"), "S3 location of the archive, such as s3://my-bucket/archive")
+	olderThan := flag.Duration("older-than", 30*24*time.Hour, "Archive finished tasks whose last status is older than this")
+	restore := flag.String("restore", "", "Restore this task to the tables instead of archiving")
+	compressAbove := flag.Int("compress-above", 0, "Gzip restored task and event data over this many bytes, as DYNAMODB_COMPRESS_ABOVE")
+	flag.Parse()
+
+	if *uri == "" {
+		fmt.Fprintln(os.Stderr, "-s3 or A2A_ARCHIVE_S3_URI is required")
+		os.Exit(2)
+	}
+	if *olderThan <= 0 {
+		fmt.Fprintln(os.Stderr, "-older-than must be positive")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var loadOpts []func(*config.LoadOptions) error
+	if *region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(*region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load AWS config: %v\n", err)
+		os.Exit(1)
+	}
+	archive, err := a2aTypes.NewS3TaskArchive(s3.NewFromConfig(cfg), *uri, *prefix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "-s3: %v\n", err)
+		os.Exit(2)
+	}
+	client := dynamodb.NewFromConfig(cfg)
+	opts := []a2aTypes.RuntimeOption{
+		a2aTypes.WithStorageCompression(*compressAbove),
+		a2aTypes.WithEventReadBudget(archiveReadBudget),
+	}
+	archiver := a2aTypes.NewArchiver(
+		a2aTypes.NewAWSTaskStore(client, *tasksTable, *prefix, opts...),
+		a2aTypes.NewAWSEventStore(client, *eventsTable, *prefix, opts...),
+		archive,
+	)
+
+	if *restore != "" {
+		task, err := archiver.RestoreTask(ctx, a2a.TaskID(*restore))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "restore failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "task %s restored (%s)\n", task.ID, task.Status.State)
+		return
+	}
+
+	stats, err := archiveTasks(ctx, a2aTypes.NewAWSTaskLister(client, *tasksTable, *prefix), archiver, *olderThan, os.Stderr)
+	fmt.Fprintf(os.Stderr, "%d tasks archived, %d kept, %d failed\n", stats.Archived, stats.Kept, stats.Failed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "archival stopped: %v\n", err)
+		os.Exit(1)
+	}
+	if stats.Failed > 0 {
+		os.Exit(1)
+	}
+}
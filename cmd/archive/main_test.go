@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+// idLister lists fixed task IDs, then fails with err when set
+type idLister struct {
+	ids []a2a.TaskID
+	err error
+}
+
+func (l idLister) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return func(yield func(a2a.TaskID, error) bool) {
+		for _, id := range l.ids {
+			if !yield(id, nil) {
+				return
+			}
+		}
+		if l.err != nil {
+			yield("", l.err)
+		}
+	}
+}
+
+// memoryArchive keeps archived tasks by ID
+type memoryArchive map[a2a.TaskID]a2aTypes.ArchivedTask
+
+func (a memoryArchive) PutArchivedTask(ctx context.Context, archived a2aTypes.ArchivedTask) error {
+	a[archived.Task.ID] = archived
+	return nil
+}
+
+func (a memoryArchive) GetArchivedTask(ctx context.Context, taskID a2a.TaskID) (a2aTypes.ArchivedTask, error) {
+	archived, ok := a[taskID]
+	if !ok {
+		return a2aTypes.ArchivedTask{}, a2a.ErrTaskNotFound
+	}
+	return archived, nil
+}
+
+func (a memoryArchive) DeleteArchivedTask(ctx context.Context, taskID a2a.TaskID) error {
+	delete(a, taskID)
+	return nil
+}
+
+func TestArchiveTasks(t *testing.T) {
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+	tasks := a2atest.NewTaskStore(
+		a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Timestamp: &old}},
+		a2a.Task{ID: "task-2", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Timestamp: &old}},
+	)
+	events := a2atest.NewEventStore()
+	events.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1"})
+	archive := memoryArchive{}
+	archiver := a2aTypes.NewArchiver(tasks, events, archive)
+
+	// A task that cannot be read is reported and the run goes on
+	var log strings.Builder
+	stats, err := archiveTasks(ctx, idLister{ids: []a2a.TaskID{"task-1", "missing", "task-2"}}, archiver, 24*time.Hour, &log)
+	if err != nil || stats != (archiveStats{Archived: 1, Kept: 1, Failed: 1}) {
+		t.Fatalf("unexpected stats %+v, %v", stats, err)
+	}
+	if !strings.Contains(log.String(), "task missing") {
+		t.Errorf("expected the failed task logged, got %q", log.String())
+	}
+	if _, ok := archive["task-1"]; !ok || len(tasks.Tasks()) != 1 || len(events.Events()) != 0 {
+		t.Errorf("expected task-1 and its event moved to the archive, got %d tasks and %d events left", len(tasks.Tasks()), len(events.Events()))
+	}
+
+	if _, err := archiveTasks(ctx, idLister{err: errors.New("throttled")}, archiver, time.Hour, &log); err == nil {
+		t.Error("expected a failed listing to stop the run")
+	}
+}
@@ -0,0 +1,202 @@
+// Command bootstrap stands up the tables and queues an environment needs by
+// calling the AWS APIs directly, for ad-hoc environments where a
+// CloudFormation stack would be too slow:
+//
+//	bootstrap -env pr-123 -audit
+//
+// Missing resources are created; existing ones are checked against what the
+// stores expect and any drift is reported, but never changed. -dry-run only
+// reports. The environment variables for the entrypoints are printed to
+// stdout, ready to be evaluated.
+//
+// -backfill-events sets recorded_at on events saved before task_id-index
+// was sorted by it, which the index otherwise leaves out.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// options chooses the environment and its optional parts, as cmd/infra's do
+type options struct {
+	// Env prefixes every resource name
+	Env string
+	// Worker adds the notification queue, its dead-letter queue and the
+	// worker's idempotency table
+	Worker  bool
+	Audit   bool
+	Replay  bool
+	Capture bool
+	Quota   bool
+	Stats   bool
+	// Deliveries adds the webhook delivery attempts table; it needs Worker
+	Deliveries bool
+}
+
+// envName is a prefix valid in both table and queue names
+var envName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,40}$`)
+
+// resources are the tables and queues an environment needs, with the
+// environment variable each is passed to the entrypoints in
+type resources struct {
+	tables     []tableSpec
+	tableEnv   map[string]string
+	queue      queueSpec
+	deadLetter queueSpec
+}
+
+// plan returns the resources for opts. Keys, index names and TTL attributes
+// are the ones the stores use.
+func plan(opts options) resources {
+	name := func(suffix string) string { return opts.Env + "-a2a-" + suffix }
+	r := resources{
+		tables: []tableSpec{
+			// expires_at is written only under a retention policy
+			{Name: name("tasks"), Keys: []string{"task_id"}, Indexes: map[string][]string{"context_id-index": {"context_id"}}, TTL: "expires_at"},
+			{Name: name("events"), Keys: []string{"event_id"}, Indexes: map[string][]string{"task_id-index": {"task_id", "recorded_at"}}, TTL: "expires_at"},
+		},
+		tableEnv: map[string]string{"DYNAMODB_TABLE": name("tasks"), "DYNAMODB_EVENTS_TABLE": name("events")},
+	}
+	if opts.Audit {
+		r.tables = append(r.tables, tableSpec{Name: name("audit"), Keys: []string{"audit_key", "recorded_at"}, Indexes: map[string][]string{"subject-index": {"subject", "recorded_at"}}})
+		r.tableEnv["DYNAMODB_AUDIT_TABLE"] = name("audit")
+	}
+	if opts.Replay {
+		r.tables = append(r.tables, tableSpec{Name: name("replay"), Keys: []string{"nonce"}, TTL: "expires_at"})
+		r.tableEnv["A2A_AUTH_REPLAY_TABLE"] = name("replay")
+	}
+	if opts.Capture {
+		r.tables = append(r.tables, tableSpec{Name: name("capture"), Keys: []string{"request_id"}, TTL: "expires_at"})
+		r.tableEnv["A2A_CAPTURE_TABLE"] = name("capture")
+	}
+	if opts.Quota {
+		r.tables = append(r.tables, tableSpec{Name: name("quota"), Keys: []string{"quota_key"}, TTL: "expires_at"})
+		r.tableEnv["A2A_QUOTA_TABLE"] = name("quota")
+	}
+	if opts.Stats {
+		r.tables = append(r.tables, tableSpec{Name: name("stats"), Keys: []string{"stats_key", "bucket"}, TTL: "expires_at"})
+		r.tableEnv["A2A_STATS_TABLE"] = name("stats")
+	}
+	if opts.Worker {
+		r.tables = append(r.tables, tableSpec{Name: name("worker-idempotency"), Keys: []string{"message_id"}, TTL: "expires_at"})
+		r.tableEnv["A2A_WORKER_IDEMPOTENCY_TABLE"] = name("worker-idempotency")
+		if opts.Deliveries {
+			r.tables = append(r.tables, tableSpec{Name: name("deliveries"), Keys: []string{"task_id", "attempted_at"}, TTL: "expires_at"})
+			r.tableEnv["A2A_DELIVERY_TABLE"] = name("deliveries")
+		}
+		r.deadLetter = queueSpec{Name: name("notifications-dlq"), RetentionPeriod: 1209600}
+		// Six times the worker timeout, as Lambda recommends for SQS sources
+		r.queue = queueSpec{Name: name("notifications"), VisibilityTimeout: 180, DeadLetter: r.deadLetter.Name, MaxReceiveCount: 5}
+	}
+	return r
+}
+
+// run ensures every resource opts needs and returns the entrypoints'
+// environment variables for them
+func run(ctx context.Context, b *bootstrapper, opts options) (map[string]string, error) {
+	r := plan(opts)
+	for _, spec := range r.tables {
+		if err := b.ensureTable(ctx, spec); err != nil {
+			return nil, err
+		}
+	}
+	env := r.tableEnv
+	if opts.Worker {
+		deadLetterURL, deadLetterARN, err := b.ensureQueue(ctx, r.deadLetter, "")
+		if err != nil {
+			return nil, err
+		}
+		url, _, err := b.ensureQueue(ctx, r.queue, deadLetterARN)
+		if err != nil {
+			return nil, err
+		}
+		// SQS_QUEUE_URL turns push notifications on in the agent card
+		if url != "" {
+			env["SQS_QUEUE_URL"] = url
+		}
+		if deadLetterURL != "" {
+			env["A2A_NOTIFICATION_DLQ_URL"] = deadLetterURL
+		}
+	}
+	return env, nil
+}
+
+// writeEnv writes env as shell exports, in a stable order
+func writeEnv(out io.Writer, env map[string]string) {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "export %s=%q\n", name, env[name])
+	}
+}
+
+func main() {
+	env := flag.String("env", "", "Environment name every resource name starts with (required)")
+	dryRun := flag.Bool("dry-run", false, "Report what would be created and any drift without changing anything")
+	worker := flag.Bool("worker", true, "Include the notification queue, its dead-letter queue and the worker's idempotency table")
+	audit := flag.Bool("audit", false, "Include the audit log table")
+	replay := flag.Bool("replay", false, "Include the table recording signed requests and single-use tokens")
+	capture := flag.Bool("capture", false, "Include the request capture table")
+	quota := flag.Bool("quota", false, "Include the table counting usage against quotas")
+	stats := flag.Bool("stats", false, "Include the table counting task states and throughput for admin/stats/get")
+	deliveries := flag.Bool("deliveries", false, "Include the table recording webhook delivery attempts (needs -worker)")
+	backfillEvents := flag.Bool("backfill-events", false, "Set recorded_at on events saved without it, so task_id-index lists them")
+	tableWait := flag.Duration("table-wait", 2*time.Minute, "How long to wait for a new table to become active")
+	flag.Parse()
+
+	if !envName.MatchString(*env) {
+		fmt.Fprintln(os.Stderr, "-env is required: up to 40 letters, digits, hyphens or underscores")
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load AWS config: %v\n", err)
+		os.Exit(1)
+	}
+	dynamoClient := dynamodb.NewFromConfig(cfg)
+	b := &bootstrapper{
+		dynamoDB:  dynamoClient,
+		sqs:       sqs.NewFromConfig(cfg),
+		dryRun:    *dryRun,
+		out:       os.Stderr,
+		tableWait: *tableWait,
+	}
+	opts := options{Env: *env, Worker: *worker, Audit: *audit, Replay: *replay, Capture: *capture, Quota: *quota, Stats: *stats, Deliveries: *deliveries}
+	vars, err := run(ctx, b, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bootstrap failed: %v\n", err)
+		os.Exit(1)
+	}
+	if *backfillEvents && !*dryRun {
+		table := vars["DYNAMODB_EVENTS_TABLE"]
+		updated, err := a2aTypes.BackfillEventRecordedAt(ctx, dynamoClient, table)
+		fmt.Fprintf(os.Stderr, "%d events in %s backfilled with recorded_at\n", updated, table)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "backfill failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	writeEnv(os.Stdout, vars)
+	if b.drifted > 0 {
+		fmt.Fprintf(os.Stderr, "%d resource(s) differ from what the agent expects\n", b.drifted)
+		os.Exit(1)
+	}
+}
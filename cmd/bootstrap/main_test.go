@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeAWS keeps tables and queues in memory. New tables are active at once.
+type fakeAWS struct {
+	tables map[string]*dynamodbtypes.TableDescription
+	ttl    map[string]*dynamodbtypes.TimeToLiveDescription
+	queues map[string]map[string]string
+	// calls counts the calls that create or change something
+	calls int
+}
+
+func newFakeAWS() *fakeAWS {
+	return &fakeAWS{
+		tables: map[string]*dynamodbtypes.TableDescription{},
+		ttl:    map[string]*dynamodbtypes.TimeToLiveDescription{},
+		queues: map[string]map[string]string{},
+	}
+}
+
+func (f *fakeAWS) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	table, ok := f.tables[aws.ToString(params.TableName)]
+	if !ok {
+		return nil, &dynamodbtypes.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+	return &dynamodb.DescribeTableOutput{Table: table}, nil
+}
+
+func (f *fakeAWS) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	f.calls++
+	table := &dynamodbtypes.TableDescription{TableName: params.TableName, KeySchema: params.KeySchema, TableStatus: dynamodbtypes.TableStatusActive}
+	for _, gsi := range params.GlobalSecondaryIndexes {
+		table.GlobalSecondaryIndexes = append(table.GlobalSecondaryIndexes, dynamodbtypes.GlobalSecondaryIndexDescription{IndexName: gsi.IndexName, KeySchema: gsi.KeySchema})
+	}
+	f.tables[aws.ToString(params.TableName)] = table
+	return &dynamodb.CreateTableOutput{TableDescription: table}, nil
+}
+
+func (f *fakeAWS) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	description, ok := f.ttl[aws.ToString(params.TableName)]
+	if !ok {
+		description = &dynamodbtypes.TimeToLiveDescription{TimeToLiveStatus: dynamodbtypes.TimeToLiveStatusDisabled}
+	}
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: description}, nil
+}
+
+func (f *fakeAWS) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	f.calls++
+	f.ttl[aws.ToString(params.TableName)] = &dynamodbtypes.TimeToLiveDescription{
+		AttributeName:    params.TimeToLiveSpecification.AttributeName,
+		TimeToLiveStatus: dynamodbtypes.TimeToLiveStatusEnabled,
+	}
+	return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+func (f *fakeAWS) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	if _, ok := f.queues[aws.ToString(params.QueueName)]; !ok {
+		return nil, &sqstypes.QueueDoesNotExist{Message: aws.String("not found")}
+	}
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String("https://sqs.example.com/" + aws.ToString(params.QueueName))}, nil
+}
+
+func (f *fakeAWS) CreateQueue(ctx context.Context, params *sqs.CreateQueueInput, optFns ...func(*sqs.Options)) (*sqs.CreateQueueOutput, error) {
+	f.calls++
+	name := aws.ToString(params.QueueName)
+	attributes := map[string]string{"QueueArn": "arn:aws:sqs:us-east-1:123456789012:" + name}
+	for key, value := range params.Attributes {
+		attributes[key] = value
+	}
+	f.queues[name] = attributes
+	return &sqs.CreateQueueOutput{QueueUrl: aws.String("https://sqs.example.com/" + name)}, nil
+}
+
+func (f *fakeAWS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	name := strings.TrimPrefix(aws.ToString(params.QueueUrl), "https://sqs.example.com/")
+	return &sqs.GetQueueAttributesOutput{Attributes: f.queues[name]}, nil
+}
+
+func newTestBootstrapper(fake *fakeAWS, dryRun bool) (*bootstrapper, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &bootstrapper{dynamoDB: fake, sqs: fake, dryRun: dryRun, out: &out, tableWait: time.Second}, &out
+}
+
+func TestRun(t *testing.T) {
+	opts := options{Env: "dev", Worker: true, Audit: true, Replay: true, Stats: true, Deliveries: true}
+	fake := newFakeAWS()
+
+	// A dry run changes nothing and knows no queue URL yet
+	b, out := newTestBootstrapper(fake, true)
+	env, err := run(context.Background(), b, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.calls != 0 || strings.Count(out.String(), actionWouldCreate) != 9 {
+		t.Errorf("expected 9 resources to be reported and none created, got %d calls: %s", fake.calls, out)
+	}
+	if _, ok := env["SQS_QUEUE_URL"]; ok {
+		t.Errorf("expected no queue URL in a dry run, got %v", env)
+	}
+
+	b, out = newTestBootstrapper(fake, false)
+	env, err = run(context.Background(), b, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out.String(), actionCreated) != 9 {
+		t.Errorf("expected 9 resources to be created, got %s", out)
+	}
+	if env["DYNAMODB_TABLE"] != "dev-a2a-tasks" || env["A2A_AUTH_REPLAY_TABLE"] != "dev-a2a-replay" || env["A2A_DELIVERY_TABLE"] != "dev-a2a-deliveries" || env["A2A_STATS_TABLE"] != "dev-a2a-stats" || env["SQS_QUEUE_URL"] != "https://sqs.example.com/dev-a2a-notifications" || env["A2A_NOTIFICATION_DLQ_URL"] != "https://sqs.example.com/dev-a2a-notifications-dlq" {
+		t.Errorf("expected the environment for the new resources, got %v", env)
+	}
+	if ttl := fake.ttl["dev-a2a-replay"]; ttl == nil || aws.ToString(ttl.AttributeName) != "expires_at" {
+		t.Errorf("expected TTL on expires_at, got %v", ttl)
+	}
+	if redrive := fake.queues["dev-a2a-notifications"]["RedrivePolicy"]; !strings.Contains(redrive, ":dev-a2a-notifications-dlq") {
+		t.Errorf("expected the queue to redrive to its dead-letter queue, got %s", redrive)
+	}
+
+	// A second run finds everything in place
+	calls := fake.calls
+	b, out = newTestBootstrapper(fake, false)
+	if _, err := run(context.Background(), b, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.calls != calls || b.drifted != 0 || strings.Count(out.String(), actionOK) != 9 {
+		t.Errorf("expected every resource to be left as it is, got %s", out)
+	}
+}
+
+func TestRunReportsDrift(t *testing.T) {
+	opts := options{Env: "dev", Worker: true, Replay: true}
+	fake := newFakeAWS()
+	b, _ := newTestBootstrapper(fake, false)
+	if _, err := run(context.Background(), b, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fake.tables["dev-a2a-tasks"].GlobalSecondaryIndexes = nil
+	fake.ttl["dev-a2a-replay"].TimeToLiveStatus = dynamodbtypes.TimeToLiveStatusDisabled
+	fake.queues["dev-a2a-notifications"]["VisibilityTimeout"] = "30"
+	calls := fake.calls
+
+	b, out := newTestBootstrapper(fake, false)
+	if _, err := run(context.Background(), b, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.drifted != 3 || fake.calls != calls {
+		t.Errorf("expected 3 drifted resources left unchanged, got %d: %s", b.drifted, out)
+	}
+	for _, expect := range []string{"index context_id-index is missing", "TTL is disabled", "visibility timeout is 30s, expected 180s"} {
+		if !strings.Contains(out.String(), expect) {
+			t.Errorf("expected %q in %s", expect, out)
+		}
+	}
+}
+
+func TestWriteEnv(t *testing.T) {
+	var out bytes.Buffer
+	writeEnv(&out, map[string]string{"SQS_QUEUE_URL": "https://sqs.example.com/q", "DYNAMODB_TABLE": "dev-a2a-tasks"})
+	expected := "export DYNAMODB_TABLE=\"dev-a2a-tasks\"\nexport SQS_QUEUE_URL=\"https://sqs.example.com/q\"\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
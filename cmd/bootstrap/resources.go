@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// dynamoDBAdmin is the subset of the DynamoDB client that creates and
+// inspects tables
+type dynamoDBAdmin interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+// sqsAdmin is the subset of the SQS client that creates and inspects queues
+type sqsAdmin interface {
+	GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	CreateQueue(ctx context.Context, params *sqs.CreateQueueInput, optFns ...func(*sqs.Options)) (*sqs.CreateQueueOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// Report actions, one per resource
+const (
+	actionOK          = "ok"
+	actionCreated     = "created"
+	actionWouldCreate = "would create"
+	actionDrift       = "drift"
+)
+
+// tableSpec is a pay-per-request table as the stores expect it. Keys are
+// the string key attributes, hash first; Indexes map a GSI name to its keys
+// the same way. TTL names the TTL attribute, if any.
+type tableSpec struct {
+	Name    string
+	Keys    []string
+	Indexes map[string][]string
+	TTL     string
+}
+
+// queueSpec is a standard queue, with a dead-letter queue if DeadLetter
+// names one
+type queueSpec struct {
+	Name              string
+	VisibilityTimeout int
+	RetentionPeriod   int
+	DeadLetter        string
+	MaxReceiveCount   int
+}
+
+// bootstrapper creates missing resources and reports those that differ from
+// their spec. It never changes a resource that exists: a differing key
+// schema, for one, can only be fixed by replacing the table and its data.
+type bootstrapper struct {
+	dynamoDB dynamoDBAdmin
+	sqs      sqsAdmin
+	dryRun   bool
+	out      io.Writer
+	// tableWait bounds how long a new table may take to become active
+	tableWait time.Duration
+	// drifted counts the resources reported as drifted
+	drifted int
+}
+
+func (b *bootstrapper) report(kind, name, action string, details ...string) {
+	if action == actionDrift {
+		b.drifted++
+	}
+	fmt.Fprintf(b.out, "%-6s %-40s %s\n", kind, name, action)
+	for _, detail := range details {
+		fmt.Fprintf(b.out, "       - %s\n", detail)
+	}
+}
+
+// keySchema returns the schema for string keys, hash first, and their
+// attribute definitions
+func keySchema(keys []string) ([]dynamodbtypes.KeySchemaElement, []dynamodbtypes.AttributeDefinition) {
+	var schema []dynamodbtypes.KeySchemaElement
+	var attributes []dynamodbtypes.AttributeDefinition
+	for i, key := range keys {
+		keyType := dynamodbtypes.KeyTypeHash
+		if i > 0 {
+			keyType = dynamodbtypes.KeyTypeRange
+		}
+		schema = append(schema, dynamodbtypes.KeySchemaElement{AttributeName: aws.String(key), KeyType: keyType})
+		attributes = append(attributes, dynamodbtypes.AttributeDefinition{AttributeName: aws.String(key), AttributeType: dynamodbtypes.ScalarAttributeTypeS})
+	}
+	return schema, attributes
+}
+
+// keyNames lists a schema's attributes, hash first
+func keyNames(schema []dynamodbtypes.KeySchemaElement) []string {
+	names := make([]string, len(schema))
+	for _, element := range schema {
+		i := 0
+		if element.KeyType == dynamodbtypes.KeyTypeRange {
+			i = len(schema) - 1
+		}
+		names[i] = aws.ToString(element.AttributeName)
+	}
+	return names
+}
+
+// ensureTable creates spec's table if it is missing, or reports how it
+// differs from spec
+func (b *bootstrapper) ensureTable(ctx context.Context, spec tableSpec) error {
+	described, err := b.dynamoDB.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(spec.Name)})
+	var notFound *dynamodbtypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return b.createTable(ctx, spec)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", spec.Name, err)
+	}
+
+	var drift []string
+	table := described.Table
+	if keys := keyNames(table.KeySchema); !slices.Equal(keys, spec.Keys) {
+		drift = append(drift, fmt.Sprintf("key is %v, expected %v", keys, spec.Keys))
+	}
+	indexes := map[string][]string{}
+	for _, gsi := range table.GlobalSecondaryIndexes {
+		indexes[aws.ToString(gsi.IndexName)] = keyNames(gsi.KeySchema)
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.Indexes)) {
+		keys, ok := indexes[name]
+		switch {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("index %s is missing", name))
+		case !slices.Equal(keys, spec.Indexes[name]):
+			drift = append(drift, fmt.Sprintf("index %s key is %v, expected %v", name, keys, spec.Indexes[name]))
+		}
+	}
+	if spec.TTL != "" {
+		ttl, err := b.dynamoDB.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(spec.Name)})
+		if err != nil {
+			return fmt.Errorf("failed to describe TTL of table %s: %w", spec.Name, err)
+		}
+		description := ttl.TimeToLiveDescription
+		enabled := description != nil && (description.TimeToLiveStatus == dynamodbtypes.TimeToLiveStatusEnabled || description.TimeToLiveStatus == dynamodbtypes.TimeToLiveStatusEnabling)
+		switch {
+		case !enabled:
+			drift = append(drift, fmt.Sprintf("TTL is disabled, expected it on %s", spec.TTL))
+		case aws.ToString(description.AttributeName) != spec.TTL:
+			drift = append(drift, fmt.Sprintf("TTL is on %s, expected %s", aws.ToString(description.AttributeName), spec.TTL))
+		}
+	}
+
+	if len(drift) > 0 {
+		b.report("table", spec.Name, actionDrift, drift...)
+	} else {
+		b.report("table", spec.Name, actionOK)
+	}
+	return nil
+}
+
+func (b *bootstrapper) createTable(ctx context.Context, spec tableSpec) error {
+	if b.dryRun {
+		b.report("table", spec.Name, actionWouldCreate)
+		return nil
+	}
+
+	schema, attributes := keySchema(spec.Keys)
+	defined := map[string]bool{}
+	for _, key := range spec.Keys {
+		defined[key] = true
+	}
+	input := &dynamodb.CreateTableInput{
+		TableName:   aws.String(spec.Name),
+		BillingMode: dynamodbtypes.BillingModePayPerRequest,
+		KeySchema:   schema,
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.Indexes)) {
+		indexSchema, indexAttributes := keySchema(spec.Indexes[name])
+		for _, attribute := range indexAttributes {
+			if !defined[aws.ToString(attribute.AttributeName)] {
+				defined[aws.ToString(attribute.AttributeName)] = true
+				attributes = append(attributes, attribute)
+			}
+		}
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, dynamodbtypes.GlobalSecondaryIndex{
+			IndexName:  aws.String(name),
+			KeySchema:  indexSchema,
+			Projection: &dynamodbtypes.Projection{ProjectionType: dynamodbtypes.ProjectionTypeAll},
+		})
+	}
+	input.AttributeDefinitions = attributes
+	if _, err := b.dynamoDB.CreateTable(ctx, input); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", spec.Name, err)
+	}
+
+	if spec.TTL != "" {
+		// TTL can only be turned on once the table is active
+		waiter := dynamodb.NewTableExistsWaiter(b.dynamoDB, func(o *dynamodb.TableExistsWaiterOptions) { o.MinDelay = time.Second })
+		if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(spec.Name)}, b.tableWait); err != nil {
+			return fmt.Errorf("table %s did not become active: %w", spec.Name, err)
+		}
+		_, err := b.dynamoDB.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+			TableName:               aws.String(spec.Name),
+			TimeToLiveSpecification: &dynamodbtypes.TimeToLiveSpecification{AttributeName: aws.String(spec.TTL), Enabled: aws.Bool(true)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable TTL on table %s: %w", spec.Name, err)
+		}
+	}
+	b.report("table", spec.Name, actionCreated)
+	return nil
+}
+
+// redrivePolicy is an SQS RedrivePolicy attribute
+type redrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     int    `json:"maxReceiveCount"`
+}
+
+// ensureQueue creates spec's queue if it is missing, or reports how it
+// differs from spec, and returns its URL. The URL is empty in a dry run
+// for a queue that does not exist. deadLetterARN is the ARN of spec's
+// dead-letter queue, which must be ensured first.
+func (b *bootstrapper) ensureQueue(ctx context.Context, spec queueSpec, deadLetterARN string) (url string, arn string, err error) {
+	found, err := b.sqs.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(spec.Name)})
+	var notFound *sqstypes.QueueDoesNotExist
+	if errors.As(err, &notFound) {
+		return b.createQueue(ctx, spec, deadLetterARN)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up queue %s: %w", spec.Name, err)
+	}
+	url = aws.ToString(found.QueueUrl)
+
+	described, err := b.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       found.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameAll},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe queue %s: %w", spec.Name, err)
+	}
+	attributes := described.Attributes
+
+	var drift []string
+	if got := attributes[string(sqstypes.QueueAttributeNameVisibilityTimeout)]; spec.VisibilityTimeout != 0 && got != strconv.Itoa(spec.VisibilityTimeout) {
+		drift = append(drift, fmt.Sprintf("visibility timeout is %ss, expected %ds", got, spec.VisibilityTimeout))
+	}
+	if got := attributes[string(sqstypes.QueueAttributeNameMessageRetentionPeriod)]; spec.RetentionPeriod != 0 && got != strconv.Itoa(spec.RetentionPeriod) {
+		drift = append(drift, fmt.Sprintf("retention period is %ss, expected %ds", got, spec.RetentionPeriod))
+	}
+	if spec.DeadLetter != "" {
+		var redrive redrivePolicy
+		raw := attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)]
+		switch {
+		case raw == "":
+			drift = append(drift, fmt.Sprintf("no dead-letter queue, expected %s", spec.DeadLetter))
+		case json.Unmarshal([]byte(raw), &redrive) != nil:
+			drift = append(drift, fmt.Sprintf("unreadable redrive policy %s", raw))
+		default:
+			if deadLetterARN != "" && redrive.DeadLetterTargetArn != deadLetterARN {
+				drift = append(drift, fmt.Sprintf("dead-letter queue is %s, expected %s", redrive.DeadLetterTargetArn, deadLetterARN))
+			}
+			if redrive.MaxReceiveCount != spec.MaxReceiveCount {
+				drift = append(drift, fmt.Sprintf("max receive count is %d, expected %d", redrive.MaxReceiveCount, spec.MaxReceiveCount))
+			}
+		}
+	}
+
+	if len(drift) > 0 {
+		b.report("queue", spec.Name, actionDrift, drift...)
+	} else {
+		b.report("queue", spec.Name, actionOK)
+	}
+	return url, attributes[string(sqstypes.QueueAttributeNameQueueArn)], nil
+}
+
+func (b *bootstrapper) createQueue(ctx context.Context, spec queueSpec, deadLetterARN string) (string, string, error) {
+	if b.dryRun {
+		b.report("queue", spec.Name, actionWouldCreate)
+		return "", "", nil
+	}
+
+	attributes := map[string]string{}
+	if spec.VisibilityTimeout != 0 {
+		attributes[string(sqstypes.QueueAttributeNameVisibilityTimeout)] = strconv.Itoa(spec.VisibilityTimeout)
+	}
+	if spec.RetentionPeriod != 0 {
+		attributes[string(sqstypes.QueueAttributeNameMessageRetentionPeriod)] = strconv.Itoa(spec.RetentionPeriod)
+	}
+	if spec.DeadLetter != "" {
+		redrive, err := json.Marshal(redrivePolicy{DeadLetterTargetArn: deadLetterARN, MaxReceiveCount: spec.MaxReceiveCount})
+		if err != nil {
+			return "", "", err
+		}
+		attributes[string(sqstypes.QueueAttributeNameRedrivePolicy)] = string(redrive)
+	}
+	created, err := b.sqs.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: aws.String(spec.Name), Attributes: attributes})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create queue %s: %w", spec.Name, err)
+	}
+	described, err := b.sqs.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       created.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to describe queue %s: %w", spec.Name, err)
+	}
+	b.report("queue", spec.Name, actionCreated)
+	return aws.ToString(created.QueueUrl), described.Attributes[string(sqstypes.QueueAttributeNameQueueArn)], nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func main() {
+	file := flag.String("file", "", "Check a JSON config file, as the Lambda loads it from A2A_CONFIG_FILE")
+	skipSecrets := flag.Bool("skip-secrets", false, "Do not resolve Secrets Manager references (no AWS access needed)")
+	envFile := flag.String("env", "", "Check with the variables of a JSON object file (- for stdin), such as a function's Environment.Variables, in place of the current environment")
+	schema := flag.String("schema", "", "Print the JSON Schema for a config format (config or registry), or the AsyncAPI document of the queues and webhooks (asyncapi), and exit")
+	flag.Parse()
+
+	if *schema != "" {
+		if err := printSchema(*schema); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *envFile != "" {
+		if err := loadEnvFile(*envFile); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := run(context.Background(), os.Stdout, *file, *skipSecrets); err != nil {
+		// List each invalid field on its own line so every problem can be fixed in one pass
+		var validationErrs a2aTypes.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			fmt.Fprintf(os.Stderr, "config check failed: %d invalid field(s)\n", len(validationErrs))
+			schema := a2aTypes.ConfigJSONSchema()
+			for _, fieldErr := range validationErrs {
+				if pointer, ok := a2aTypes.SchemaPath(schema, fieldErr.Path); ok {
+					fmt.Fprintf(os.Stderr, "  %s [%s]: %s (schema: %s)\n", fieldErr.Path, fieldErr.Code, fieldErr.Message, pointer)
+				} else {
+					fmt.Fprintf(os.Stderr, "  %s [%s]: %s\n", fieldErr.Path, fieldErr.Code, fieldErr.Message)
+				}
+			}
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "config check failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "config OK")
+}
+
+// run loads config the same way cmd/lambda does, writes it redacted to out and
+// validates it. file is checked as if it were set in A2A_CONFIG_FILE.
+func run(ctx context.Context, out io.Writer, file string, skipSecrets bool) error {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	secretsClient := secretsmanager.NewFromConfig(cfg)
+	source, err := a2aTypes.LoadConfigSourceFromEnv(a2aTypes.ConfigSourceClients{
+		SecretsManager: secretsClient,
+		SSM:            ssm.NewFromConfig(cfg),
+		S3:             s3.NewFromConfig(cfg),
+	})
+	if err != nil {
+		return err
+	}
+	if file != "" {
+		source = a2aTypes.NewFileConfigSource(file)
+	}
+
+	// Both paths only decode, so the config is printed even when it is invalid
+	var serverlessConfig a2aTypes.ServerlessConfig
+	if source != nil {
+		if _, err := a2aTypes.LoadConfigCacheTTL(); err != nil {
+			return err
+		}
+		serverlessConfig, err = source(ctx)
+	} else {
+		serverlessConfig, err = a2aTypes.LoadLambdaEnvConfig(cfg.Region)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !skipSecrets {
+		resolver := a2aTypes.NewSecretResolver(secretsClient)
+		serverlessConfig, err = a2aTypes.ResolveConfigSecrets(ctx, resolver, serverlessConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Print before validating so the effective values are visible next to any problem
+	output, err := json.MarshalIndent(a2aTypes.RedactConfig(serverlessConfig), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
+	}
+	fmt.Fprintln(out, string(output))
+
+	return a2aTypes.ValidateLambdaConfig(serverlessConfig)
+}
+
+// loadEnvFile replaces the environment with the variables in a JSON object
+// read from path, or stdin for "-", as the function would run with them.
+// HOME and AWS_ variables are kept, so credentials and the region still load.
+func loadEnvFile(path string) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read environment: %w", err)
+	}
+	var vars map[string]string
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return fmt.Errorf("failed to parse environment %s: %w", path, err)
+	}
+
+	kept := make(map[string]string)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if name == "HOME" || strings.HasPrefix(name, "AWS_") {
+			kept[name] = value
+		}
+	}
+	os.Clearenv()
+	for _, env := range []map[string]string{kept, vars} {
+		for name, value := range env {
+			if err := os.Setenv(name, value); err != nil {
+				return fmt.Errorf("failed to set %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// printSchema writes the JSON Schema for the named config format, or the
+// AsyncAPI document, to stdout
+func printSchema(name string) error {
+	var schema map[string]any
+	switch name {
+	case "config":
+		schema = a2aTypes.ConfigJSONSchema()
+	case "registry":
+		schema = a2aTypes.AgentRegistryJSONSchema()
+	case "asyncapi":
+		schema = a2aTypes.AsyncAPIDocument()
+	default:
+		return fmt.Errorf("unknown schema %q: expected config, registry or asyncapi", name)
+	}
+
+	output, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize schema: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
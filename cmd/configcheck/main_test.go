@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	validFile := writeConfig("valid.json", `{
+		"agent_id": "file-agent",
+		"agent_card": {"Name": "File Agent", "URL": "https://agent.example.com"},
+		"cloud_config": {"provider": "aws", "aws": {"region": "us-east-1", "dynamodb_table": "tasks"}},
+		"security": {"api_key_header": "X-API-Key"},
+		"secrets": {"api_key": "plaintext-key"}
+	}`)
+	invalidFile := writeConfig("invalid.json", `{
+		"agent_id": "",
+		"agent_card": {"Name": "Invalid Agent", "URL": "https://agent.example.com"},
+		"cloud_config": {"provider": "local"}
+	}`)
+	garbageFile := writeConfig("garbage.json", `not json`)
+
+	tests := []struct {
+		name             string
+		file             string
+		envVars          map[string]string
+		expectError      bool
+		expectValidation bool
+		expectOutput     []string
+		rejectOutput     []string
+	}{
+		{
+			name:         "valid file",
+			file:         validFile,
+			expectOutput: []string{`"agent_id": "file-agent"`, a2aTypes.RedactedValue},
+			rejectOutput: []string{"plaintext-key"},
+		},
+		{
+			name:         "file from environment",
+			envVars:      map[string]string{"A2A_CONFIG_FILE": validFile},
+			expectOutput: []string{`"agent_id": "file-agent"`},
+		},
+		{
+			name:             "invalid file is printed before validation fails",
+			file:             invalidFile,
+			expectError:      true,
+			expectValidation: true,
+			expectOutput:     []string{"Invalid Agent"},
+		},
+		{name: "unparseable file", file: garbageFile, expectError: true},
+		{name: "missing file", file: filepath.Join(dir, "missing.json"), expectError: true},
+		{
+			name:         "environment defaults",
+			expectOutput: []string{`"agent_id": "serverless-agent-1"`},
+		},
+		{
+			name:         "environment overrides",
+			envVars:      map[string]string{"AGENT_ID": "env-agent", "SQS_QUEUE_URL": "https://sqs.us-east-1.amazonaws.com/123456789/queue"},
+			expectOutput: []string{`"agent_id": "env-agent"`},
+		},
+		{
+			name:             "invalid environment",
+			envVars:          map[string]string{"A2A_AGENT_SKILLS": "not json"},
+			expectError:      true,
+			expectValidation: true,
+		},
+		{
+			name:        "two config sources",
+			envVars:     map[string]string{"A2A_CONFIG_FILE": validFile, "A2A_CONFIG_SECRET_ID": "a2a-config"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Start every case from the Lambda's defaults, whatever the shell has set
+			for _, name := range []string{
+				"AGENT_ID", "AGENT_NAME", "AGENT_URL", "DYNAMODB_TABLE", "DYNAMODB_EVENTS_TABLE", "SQS_QUEUE_URL",
+				"A2A_AGENT_SKILLS", "A2A_AGENT_SKILLS_FILE", "A2A_API_KEY", "A2A_AUTH_API_KEY_HEADER",
+				"A2A_CONFIG_SECRET_ID", "A2A_CONFIG_SSM_PARAMETER", "A2A_CONFIG_S3_URI", "A2A_CONFIG_FILE",
+			} {
+				t.Setenv(name, "")
+			}
+			t.Setenv("AWS_REGION", "us-east-1")
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			var out bytes.Buffer
+			err := run(context.Background(), &out, tt.file, true)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+				var validationErrs a2aTypes.ValidationErrors
+				if tt.expectValidation != errors.As(err, &validationErrs) {
+					t.Errorf("expected validation error %v, got %T: %v", tt.expectValidation, err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.expectOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out.String())
+				}
+			}
+			for _, reject := range tt.rejectOutput {
+				if strings.Contains(out.String(), reject) {
+					t.Errorf("expected output not to contain %q", reject)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	environ := os.Environ()
+	t.Cleanup(func() {
+		os.Clearenv()
+		for _, entry := range environ {
+			name, value, _ := strings.Cut(entry, "=")
+			os.Setenv(name, value)
+		}
+	})
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AGENT_URL", "http://localhost:8080")
+
+	path := filepath.Join(t.TempDir(), "env.json")
+	os.WriteFile(path, []byte(`{"AGENT_ID": "deployed-agent", "DYNAMODB_TABLE": "tasks"}`), 0o600)
+	if err := loadEnvFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The function's variables replace the shell's, and AWS settings stay
+	if os.Getenv("AGENT_ID") != "deployed-agent" || os.Getenv("AGENT_URL") != "" || os.Getenv("AWS_REGION") != "eu-west-1" {
+		t.Errorf("unexpected environment %v", os.Environ())
+	}
+
+	os.WriteFile(path, []byte(`not json`), 0o600)
+	if err := loadEnvFile(path); err == nil {
+		t.Error("expected an error for an unparseable file")
+	}
+}
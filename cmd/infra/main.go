@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	agentName := flag.String("agent-name", "A2A Serverless Agent", "AGENT_NAME the agent's card is served with")
+	worker := flag.Bool("worker", true, "Include the SQS queue, dead-letter queue and webhook deliverer for push notifications")
+	audit := flag.Bool("audit", false, "Include the audit log table")
+	replay := flag.Bool("replay", false, "Include the table recording signed requests and single-use tokens")
+	capture := flag.Bool("capture", false, "Include the request capture table")
+	quota := flag.Bool("quota", false, "Include the table counting usage against quotas")
+	stats := flag.Bool("stats", false, "Include the table counting task states and throughput for admin/stats/get")
+	deliveries := flag.Bool("deliveries", false, "Include the table recording webhook delivery attempts (needs -worker)")
+	format := flag.String("format", "cloudformation", "Template format: cloudformation (JSON) or sam (YAML, built and deployed with the SAM CLI)")
+	output := flag.String("o", "", "Write the template to this file instead of stdout")
+	flag.Parse()
+
+	write := writeTemplate
+	switch *format {
+	case "cloudformation":
+	case "sam":
+		write = writeSAMTemplate
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q, expected cloudformation or sam\n", *format)
+		os.Exit(2)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	opts := options{AgentName: *agentName, Worker: *worker, Audit: *audit, Replay: *replay, Capture: *capture, Quota: *quota, Stats: *stats, Deliveries: *deliveries}
+	if err := write(out, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write template: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeTemplate writes the stack's CloudFormation template as JSON, which
+// CloudFormation reads as readily as YAML
+func writeTemplate(out io.Writer, opts options) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildTemplate(opts))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// samFunction is a function sam build compiles from the Go package at
+// codeURI
+func samFunction(codeURI string, timeout int, env object, statements list, events object) object {
+	props := object{
+		"CodeUri":       codeURI,
+		"Runtime":       "provided.al2023",
+		"Handler":       "bootstrap",
+		"Architectures": list{"x86_64"},
+		"MemorySize":    256,
+		"Timeout":       timeout,
+		"Environment":   object{"Variables": env},
+		"Policies":      list{object{"Version": "2012-10-17", "Statement": statements}},
+		"Events":        events,
+	}
+	return object{
+		"Type":       "AWS::Serverless::Function",
+		"Metadata":   object{"BuildMethod": "go1.x"},
+		"Properties": props,
+	}
+}
+
+// buildSAMTemplate returns the SAM template for the stack opts describes,
+// with the same tables, queues and permissions as the CloudFormation one.
+// SAM wires the functions to their event sources and builds them itself, so
+// there are no event source mappings, API methods or code parameters.
+func buildSAMTemplate(opts options) object {
+	s := newStack(opts)
+	resources, env := s.resources, s.env
+	// SAM's API names the function in its definition, so the function can
+	// not name the API back without a circular dependency
+	env["AGENT_URL"] = ref("AgentURL")
+
+	resources["Api"] = object{
+		"Type":       "AWS::Serverless::Api",
+		"Properties": object{"StageName": ref("Stage")},
+	}
+	apiEvent := func(path string) object {
+		return object{"Type": "Api", "Properties": object{"RestApiId": ref("Api"), "Path": path, "Method": "ANY"}}
+	}
+	// API Gateway gives up after 29 s
+	resources["AgentFunction"] = samFunction("./cmd/lambda", 29, env, s.agentStatements, object{
+		"Root":  apiEvent("/"),
+		"Proxy": apiEvent("/{proxy+}"),
+	})
+
+	if opts.Worker {
+		resources["WorkerFunction"] = samFunction("./cmd/worker", 30, s.workerEnv, s.workerStatements, object{
+			"Notifications": object{
+				"Type": "SQS",
+				"Properties": object{
+					"Queue":                 getAtt("NotificationQueue", "Arn"),
+					"BatchSize":             10,
+					"FunctionResponseTypes": list{"ReportBatchItemFailures"},
+				},
+			},
+		})
+	}
+
+	parameters := object{
+		"AgentURL": object{"Type": "String", "Description": "URL the agent card names, such as a custom domain or the ApiURL output of a first deploy"},
+		"Stage":    object{"Type": "String", "Default": "prod", "Description": "API Gateway stage the agent is served from"},
+	}
+	outputs := object{
+		"ApiURL":     object{"Description": "URL API Gateway serves the agent at", "Value": sub("https://${Api}.execute-api.${AWS::Region}.${AWS::URLSuffix}/${Stage}/")},
+		"TasksTable": object{"Value": ref("TasksTable")},
+	}
+	if opts.Worker {
+		outputs["NotificationQueueURL"] = object{"Value": ref("NotificationQueue")}
+		outputs["NotificationDeadLetterQueueURL"] = object{"Description": "Notifications that failed every delivery", "Value": ref("NotificationDeadLetterQueue")}
+	}
+
+	return object{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Transform":                "AWS::Serverless-2016-10-31",
+		"Description":              "A2A serverless agent: " + opts.AgentName,
+		"Parameters":               parameters,
+		"Resources":                resources,
+		"Outputs":                  outputs,
+	}
+}
+
+// writeSAMTemplate writes the stack's SAM template as YAML
+func writeSAMTemplate(out io.Writer, opts options) error {
+	encoded, err := yaml.Marshal(buildSAMTemplate(opts))
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(encoded)
+	return err
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"maps"
+	"slices"
+)
+
+// options chooses the optional parts of the stack
+type options struct {
+	// AgentName is the AGENT_NAME the function serves its card with
+	AgentName string
+	// Worker adds the SQS-triggered webhook deliverer, its dead-letter queue
+	// and its idempotency table, and turns push notifications on
+	Worker bool
+	// Audit adds the audit log table
+	Audit bool
+	// Replay adds the table recording accepted signatures and single-use tokens
+	Replay bool
+	// Capture adds the request capture table
+	Capture bool
+	// Quota adds the table counting usage against quotas
+	Quota bool
+	// Stats adds the table counting task states and throughput for
+	// admin/stats/get
+	Stats bool
+	// Deliveries adds the table the worker records webhook delivery attempts
+	// in; it needs Worker
+	Deliveries bool
+}
+
+// object and list keep the template literals below readable
+type (
+	object = map[string]any
+	list   = []any
+)
+
+func ref(name string) object          { return object{"Ref": name} }
+func getAtt(name, attr string) object { return object{"Fn::GetAtt": list{name, attr}} }
+func sub(format string) object        { return object{"Fn::Sub": format} }
+func resource(kind string, props object) object {
+	return object{"Type": kind, "Properties": props}
+}
+
+// table is a pay-per-request DynamoDB table. keys are the string key
+// attributes, hash first; indexes map a GSI name to its keys the same way.
+// ttl names the TTL attribute, if any.
+func table(keys []string, indexes map[string][]string, ttl string) object {
+	defined := map[string]bool{}
+	var attributes list
+	define := func(names []string) list {
+		schema := list{}
+		for i, name := range names {
+			if !defined[name] {
+				defined[name] = true
+				attributes = append(attributes, object{"AttributeName": name, "AttributeType": "S"})
+			}
+			keyType := "HASH"
+			if i > 0 {
+				keyType = "RANGE"
+			}
+			schema = append(schema, object{"AttributeName": name, "KeyType": keyType})
+		}
+		return schema
+	}
+
+	props := object{
+		"BillingMode": "PAY_PER_REQUEST",
+		"KeySchema":   define(keys),
+	}
+	if len(indexes) > 0 {
+		var gsis list
+		for _, name := range slices.Sorted(maps.Keys(indexes)) {
+			gsis = append(gsis, object{
+				"IndexName":  name,
+				"KeySchema":  define(indexes[name]),
+				"Projection": object{"ProjectionType": "ALL"},
+			})
+		}
+		props["GlobalSecondaryIndexes"] = gsis
+	}
+	props["AttributeDefinitions"] = attributes
+	if ttl != "" {
+		props["TimeToLiveSpecification"] = object{"AttributeName": ttl, "Enabled": true}
+	}
+	return resource("AWS::DynamoDB::Table", props)
+}
+
+// lambdaRole is an execution role allowed to write logs and make the calls
+// in statements
+func lambdaRole(statements list) object {
+	return resource("AWS::IAM::Role", object{
+		"AssumeRolePolicyDocument": object{
+			"Version": "2012-10-17",
+			"Statement": list{object{
+				"Effect":    "Allow",
+				"Principal": object{"Service": "lambda.amazonaws.com"},
+				"Action":    "sts:AssumeRole",
+			}},
+		},
+		"ManagedPolicyArns": list{sub("arn:${AWS::Partition}:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole")},
+		"Policies": list{object{
+			"PolicyName":     "access",
+			"PolicyDocument": object{"Version": "2012-10-17", "Statement": statements},
+		}},
+	})
+}
+
+// lambdaFunction runs a bootstrap binary from the code bucket
+func lambdaFunction(role, codeKey string, timeout int, env object) object {
+	return resource("AWS::Lambda::Function", object{
+		"Runtime":       "provided.al2023",
+		"Handler":       "bootstrap",
+		"Architectures": list{"x86_64"},
+		"MemorySize":    256,
+		"Timeout":       timeout,
+		"Role":          getAtt(role, "Arn"),
+		"Code":          object{"S3Bucket": ref("CodeBucket"), "S3Key": ref(codeKey)},
+		"Environment":   object{"Variables": env},
+	})
+}
+
+// tableAccess allows the DynamoDB calls the stores make on tables and
+// their indexes
+func tableAccess(actions list, tables ...string) object {
+	var resources list
+	for _, name := range tables {
+		resources = append(resources, getAtt(name, "Arn"), sub("${"+name+".Arn}/index/*"))
+	}
+	return object{"Effect": "Allow", "Action": actions, "Resource": resources}
+}
+
+// stack holds the parts of the template both formats share: the tables and
+// queues, what the functions are allowed to do and what they are told
+type stack struct {
+	resources object
+	// env is the agent's environment, bar AGENT_URL, which each format
+	// derives from its own API
+	env             object
+	agentStatements list
+	// workerStatements leave out the queue, whose access comes with the
+	// event source
+	workerStatements list
+	workerEnv        object
+}
+
+// newStack returns the shared parts of the stack opts describes. Table
+// keys, index names and environment variables are the ones the stores and
+// entrypoints read.
+func newStack(opts options) stack {
+	s := stack{
+		resources: object{
+			// expires_at is written only under a retention policy
+			"TasksTable":  table([]string{"task_id"}, map[string][]string{"context_id-index": {"context_id"}}, "expires_at"),
+			"EventsTable": table([]string{"event_id"}, map[string][]string{"task_id-index": {"task_id", "recorded_at"}}, "expires_at"),
+		},
+		env: object{
+			"DYNAMODB_TABLE":        ref("TasksTable"),
+			"DYNAMODB_EVENTS_TABLE": ref("EventsTable"),
+			"AGENT_NAME":            opts.AgentName,
+		},
+	}
+	agentTables := []string{"TasksTable", "EventsTable"}
+
+	if opts.Audit {
+		s.resources["AuditTable"] = table([]string{"audit_key", "recorded_at"}, map[string][]string{"subject-index": {"subject", "recorded_at"}}, "")
+		s.env["DYNAMODB_AUDIT_TABLE"] = ref("AuditTable")
+		agentTables = append(agentTables, "AuditTable")
+	}
+	if opts.Replay {
+		s.resources["ReplayTable"] = table([]string{"nonce"}, nil, "expires_at")
+		s.env["A2A_AUTH_REPLAY_TABLE"] = ref("ReplayTable")
+		agentTables = append(agentTables, "ReplayTable")
+	}
+	if opts.Capture {
+		s.resources["CaptureTable"] = table([]string{"request_id"}, nil, "expires_at")
+		s.env["A2A_CAPTURE_TABLE"] = ref("CaptureTable")
+		agentTables = append(agentTables, "CaptureTable")
+	}
+	if opts.Quota {
+		s.resources["QuotaTable"] = table([]string{"quota_key"}, nil, "expires_at")
+		s.env["A2A_QUOTA_TABLE"] = ref("QuotaTable")
+		agentTables = append(agentTables, "QuotaTable")
+	}
+	if opts.Stats {
+		s.resources["StatsTable"] = table([]string{"stats_key", "bucket"}, nil, "expires_at")
+		s.env["A2A_STATS_TABLE"] = ref("StatsTable")
+		agentTables = append(agentTables, "StatsTable")
+	}
+
+	if opts.Worker && opts.Deliveries {
+		s.resources["DeliveryTable"] = table([]string{"task_id", "attempted_at"}, nil, "expires_at")
+		s.env["A2A_DELIVERY_TABLE"] = ref("DeliveryTable")
+		agentTables = append(agentTables, "DeliveryTable")
+	}
+
+	s.agentStatements = list{tableAccess(list{"dynamodb:GetItem", "dynamodb:PutItem", "dynamodb:UpdateItem", "dynamodb:DeleteItem", "dynamodb:Query"}, agentTables...)}
+	if opts.Worker {
+		// SQS_QUEUE_URL turns push notifications on in the agent card
+		s.env["SQS_QUEUE_URL"] = ref("NotificationQueue")
+		s.agentStatements = append(s.agentStatements, object{"Effect": "Allow", "Action": "sqs:SendMessage", "Resource": getAtt("NotificationQueue", "Arn")})
+		// admin/notifications/replay moves failed notifications back
+		s.env["A2A_NOTIFICATION_DLQ_URL"] = ref("NotificationDeadLetterQueue")
+		s.agentStatements = append(s.agentStatements, object{"Effect": "Allow", "Action": list{"sqs:ReceiveMessage", "sqs:DeleteMessage", "sqs:ChangeMessageVisibility"}, "Resource": getAtt("NotificationDeadLetterQueue", "Arn")})
+		if opts.Stats {
+			// admin/stats/get reports the depth of both queues
+			s.agentStatements = append(s.agentStatements, object{"Effect": "Allow", "Action": "sqs:GetQueueAttributes", "Resource": list{getAtt("NotificationQueue", "Arn"), getAtt("NotificationDeadLetterQueue", "Arn")}})
+		}
+
+		s.resources["NotificationDeadLetterQueue"] = resource("AWS::SQS::Queue", object{"MessageRetentionPeriod": 1209600})
+		s.resources["NotificationQueue"] = resource("AWS::SQS::Queue", object{
+			// Six times the worker timeout, as Lambda recommends for SQS sources
+			"VisibilityTimeout": 180,
+			"RedrivePolicy": object{
+				"deadLetterTargetArn": getAtt("NotificationDeadLetterQueue", "Arn"),
+				"maxReceiveCount":     5,
+			},
+		})
+		s.resources["IdempotencyTable"] = table([]string{"message_id"}, nil, "expires_at")
+		s.workerStatements = list{tableAccess(list{"dynamodb:PutItem", "dynamodb:DeleteItem"}, "IdempotencyTable")}
+		s.workerEnv = object{"A2A_WORKER_IDEMPOTENCY_TABLE": ref("IdempotencyTable")}
+		if opts.Deliveries {
+			s.workerStatements = append(s.workerStatements, tableAccess(list{"dynamodb:PutItem"}, "DeliveryTable"))
+			s.workerEnv["A2A_DELIVERY_TABLE"] = ref("DeliveryTable")
+		}
+	}
+	return s
+}
+
+// buildTemplate returns the CloudFormation template for the stack opts
+// describes
+func buildTemplate(opts options) object {
+	s := newStack(opts)
+	resources, env, agentStatements := s.resources, s.env, s.agentStatements
+	env["AGENT_URL"] = sub("https://${Api}.execute-api.${AWS::Region}.${AWS::URLSuffix}/${Stage}/")
+
+	if opts.Worker {
+		resources["WorkerRole"] = lambdaRole(append(list{
+			object{
+				"Effect":   "Allow",
+				"Action":   list{"sqs:ReceiveMessage", "sqs:DeleteMessage", "sqs:GetQueueAttributes", "sqs:ChangeMessageVisibility"},
+				"Resource": getAtt("NotificationQueue", "Arn"),
+			},
+		}, s.workerStatements...))
+		resources["WorkerFunction"] = lambdaFunction("WorkerRole", "WorkerCodeKey", 30, s.workerEnv)
+		resources["WorkerEventSource"] = resource("AWS::Lambda::EventSourceMapping", object{
+			"EventSourceArn":        getAtt("NotificationQueue", "Arn"),
+			"FunctionName":          ref("WorkerFunction"),
+			"BatchSize":             10,
+			"FunctionResponseTypes": list{"ReportBatchItemFailures"},
+		})
+	}
+
+	resources["AgentRole"] = lambdaRole(agentStatements)
+	// API Gateway gives up after 29 s, so a longer timeout would only run on
+	// after the caller has been answered
+	resources["AgentFunction"] = lambdaFunction("AgentRole", "CodeKey", 29, env)
+
+	// A REST API, whose proxy events are what cmd/lambda decodes. The agent
+	// authenticates callers itself, so the methods do not.
+	integration := object{
+		"Type":                  "AWS_PROXY",
+		"IntegrationHttpMethod": "POST",
+		"Uri":                   sub("arn:${AWS::Partition}:apigateway:${AWS::Region}:lambda:path/2015-03-31/functions/${AgentFunction.Arn}/invocations"),
+	}
+	resources["Api"] = resource("AWS::ApiGateway::RestApi", object{"Name": sub("${AWS::StackName}-agent")})
+	resources["ApiProxyResource"] = resource("AWS::ApiGateway::Resource", object{
+		"RestApiId": ref("Api"),
+		"ParentId":  getAtt("Api", "RootResourceId"),
+		"PathPart":  "{proxy+}",
+	})
+	resources["ApiRootMethod"] = resource("AWS::ApiGateway::Method", object{
+		"RestApiId":         ref("Api"),
+		"ResourceId":        getAtt("Api", "RootResourceId"),
+		"HttpMethod":        "ANY",
+		"AuthorizationType": "NONE",
+		"Integration":       integration,
+	})
+	resources["ApiProxyMethod"] = resource("AWS::ApiGateway::Method", object{
+		"RestApiId":         ref("Api"),
+		"ResourceId":        ref("ApiProxyResource"),
+		"HttpMethod":        "ANY",
+		"AuthorizationType": "NONE",
+		"Integration":       integration,
+	})
+	resources["ApiDeployment"] = object{
+		"Type":       "AWS::ApiGateway::Deployment",
+		"DependsOn":  list{"ApiRootMethod", "ApiProxyMethod"},
+		"Properties": object{"RestApiId": ref("Api")},
+	}
+	resources["ApiStage"] = resource("AWS::ApiGateway::Stage", object{
+		"RestApiId":    ref("Api"),
+		"DeploymentId": ref("ApiDeployment"),
+		"StageName":    ref("Stage"),
+	})
+	resources["ApiPermission"] = resource("AWS::Lambda::Permission", object{
+		"Action":       "lambda:InvokeFunction",
+		"FunctionName": ref("AgentFunction"),
+		"Principal":    "apigateway.amazonaws.com",
+		"SourceArn":    sub("arn:${AWS::Partition}:execute-api:${AWS::Region}:${AWS::AccountId}:${Api}/*"),
+	})
+
+	parameters := object{
+		"CodeBucket": object{"Type": "String", "Description": "S3 bucket holding the deployment packages"},
+		"CodeKey":    object{"Type": "String", "Default": "lambda-deployment.zip", "Description": "Key of the agent package built by make deploy"},
+		"Stage":      object{"Type": "String", "Default": "prod", "Description": "API Gateway stage the agent is served from"},
+	}
+	outputs := object{
+		"AgentURL":   object{"Description": "URL the agent card is served from", "Value": env["AGENT_URL"]},
+		"TasksTable": object{"Value": ref("TasksTable")},
+	}
+	if opts.Worker {
+		parameters["WorkerCodeKey"] = object{"Type": "String", "Default": "worker-deployment.zip", "Description": "Key of the webhook deliverer package built by make deploy-worker"}
+		outputs["NotificationQueueURL"] = object{"Value": ref("NotificationQueue")}
+		outputs["NotificationDeadLetterQueueURL"] = object{"Description": "Notifications that failed every delivery", "Value": ref("NotificationDeadLetterQueue")}
+	}
+
+	return object{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              "A2A serverless agent: " + opts.AgentName,
+		"Parameters":               parameters,
+		"Resources":                resources,
+		"Outputs":                  outputs,
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// references collects every name a Ref, Fn::GetAtt or Fn::Sub points at
+func references(value any, names map[string]bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			switch key {
+			case "Ref":
+				names[inner.(string)] = true
+			case "Fn::GetAtt":
+				names[inner.([]any)[0].(string)] = true
+			case "Fn::Sub":
+				for _, match := range regexp.MustCompile(`\$\{([^}.]+)`).FindAllStringSubmatch(inner.(string), -1) {
+					names[match[1]] = true
+				}
+			default:
+				references(inner, names)
+			}
+		}
+	case []any:
+		for _, inner := range v {
+			references(inner, names)
+		}
+	}
+}
+
+// decodedTemplate writes the template and reads it back as plain JSON
+func decodedTemplate(t *testing.T, opts options) map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := writeTemplate(&out, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var template map[string]any
+	if err := json.Unmarshal(out.Bytes(), &template); err != nil {
+		t.Fatalf("expected JSON, got %v", err)
+	}
+	return template
+}
+
+func TestBuildTemplate(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            options
+		expectEnv       []string
+		expectResources []string
+		rejectResources []string
+	}{
+		{
+			name:            "full stack",
+			opts:            options{AgentName: "Agent", Worker: true, Audit: true, Replay: true, Capture: true, Quota: true, Stats: true, Deliveries: true},
+			expectEnv:       []string{"DYNAMODB_TABLE", "DYNAMODB_EVENTS_TABLE", "SQS_QUEUE_URL", "DYNAMODB_AUDIT_TABLE", "A2A_AUTH_REPLAY_TABLE", "A2A_CAPTURE_TABLE", "A2A_QUOTA_TABLE", "A2A_STATS_TABLE", "A2A_DELIVERY_TABLE", "A2A_NOTIFICATION_DLQ_URL", "AGENT_URL"},
+			expectResources: []string{"WorkerFunction", "NotificationDeadLetterQueue", "IdempotencyTable", "AuditTable", "ReplayTable", "CaptureTable", "QuotaTable", "StatsTable", "DeliveryTable"},
+		},
+		{
+			name:            "without push notifications",
+			opts:            options{AgentName: "Agent", Deliveries: true},
+			expectEnv:       []string{"DYNAMODB_TABLE", "DYNAMODB_EVENTS_TABLE", "AGENT_URL"},
+			rejectResources: []string{"WorkerFunction", "NotificationQueue", "AuditTable", "DeliveryTable"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := decodedTemplate(t, tt.opts)
+			resources := template["Resources"].(map[string]any)
+			parameters := template["Parameters"].(map[string]any)
+
+			names := map[string]bool{}
+			references(template, names)
+			for name := range names {
+				_, isResource := resources[name]
+				_, isParameter := parameters[name]
+				if !isResource && !isParameter && !strings.HasPrefix(name, "AWS::") {
+					t.Errorf("%s is referenced but not defined", name)
+				}
+			}
+
+			function := resources["AgentFunction"].(map[string]any)["Properties"].(map[string]any)
+			env := function["Environment"].(map[string]any)["Variables"].(map[string]any)
+			for _, name := range tt.expectEnv {
+				if _, ok := env[name]; !ok {
+					t.Errorf("expected the agent to be given %s", name)
+				}
+			}
+			if len(env) != len(tt.expectEnv)+1 { // and AGENT_NAME
+				t.Errorf("expected only %v and AGENT_NAME, got %v", tt.expectEnv, env)
+			}
+			for _, name := range tt.expectResources {
+				if _, ok := resources[name]; !ok {
+					t.Errorf("expected %s", name)
+				}
+			}
+			for _, name := range tt.rejectResources {
+				if _, ok := resources[name]; ok {
+					t.Errorf("expected no %s", name)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildTemplateMatchesStores(t *testing.T) {
+	template := buildTemplate(options{AgentName: "Agent", Worker: true, Audit: true})
+	resources := template["Resources"].(object)
+
+	// The index names the stores query, and the key of each table
+	indexes := func(name string) []string {
+		var names []string
+		gsis, _ := resources[name].(object)["Properties"].(object)["GlobalSecondaryIndexes"].(list)
+		for _, gsi := range gsis {
+			names = append(names, gsi.(object)["IndexName"].(string))
+		}
+		return names
+	}
+	if !slices.Equal(indexes("TasksTable"), []string{"context_id-index"}) || !slices.Equal(indexes("EventsTable"), []string{"task_id-index"}) ||
+		!slices.Equal(indexes("AuditTable"), []string{"subject-index"}) {
+		t.Errorf("expected the indexes the stores query, got %v %v %v", indexes("TasksTable"), indexes("EventsTable"), indexes("AuditTable"))
+	}
+	idempotency := resources["IdempotencyTable"].(object)["Properties"].(object)
+	if ttl := idempotency["TimeToLiveSpecification"].(object)["AttributeName"]; ttl != "expires_at" {
+		t.Errorf("expected TTL on expires_at, got %v", ttl)
+	}
+
+	// Without partial batch responses a failed record would be dropped
+	mapping := resources["WorkerEventSource"].(object)["Properties"].(object)
+	if !slices.Contains(mapping["FunctionResponseTypes"].(list), any("ReportBatchItemFailures")) {
+		t.Error("expected the worker to report partial batch failures")
+	}
+}
+
+func TestBuildSAMTemplate(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeSAMTemplate(&out, options{AgentName: "Agent", Worker: true, Capture: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]any
+	if err := yaml.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected YAML, got %v", err)
+	}
+	if decoded["Transform"] != "AWS::Serverless-2016-10-31" {
+		t.Errorf("expected the SAM transform, got %v", decoded["Transform"])
+	}
+
+	template := buildSAMTemplate(options{AgentName: "Agent", Worker: true, Capture: true})
+	resources := template["Resources"].(object)
+	parameters := template["Parameters"].(object)
+	names := map[string]bool{}
+	references(template, names)
+	for name := range names {
+		_, isResource := resources[name]
+		_, isParameter := parameters[name]
+		if !isResource && !isParameter && !strings.HasPrefix(name, "AWS::") {
+			t.Errorf("%s is referenced but not defined", name)
+		}
+	}
+
+	agent := resources["AgentFunction"].(object)["Properties"].(object)
+	if agent["CodeUri"] != "./cmd/lambda" {
+		t.Errorf("expected the agent to be built from cmd/lambda, got %v", agent["CodeUri"])
+	}
+	for _, event := range agent["Events"].(object) {
+		if event.(object)["Type"] != "Api" {
+			t.Errorf("expected the agent to be invoked by API Gateway, got %v", event)
+		}
+	}
+	if _, ok := agent["Environment"].(object)["Variables"].(object)["A2A_CAPTURE_TABLE"]; !ok {
+		t.Error("expected the agent to be given A2A_CAPTURE_TABLE")
+	}
+
+	worker := resources["WorkerFunction"].(object)["Properties"].(object)
+	queue := worker["Events"].(object)["Notifications"].(object)
+	if queue["Type"] != "SQS" || !slices.Contains(queue["Properties"].(object)["FunctionResponseTypes"].(list), any("ReportBatchItemFailures")) {
+		t.Errorf("expected an SQS event reporting partial batch failures, got %v", queue)
+	}
+	// The queue's permissions come with the event; the role adds only the ledger
+	statements := worker["Policies"].(list)[0].(object)["Statement"].(list)
+	if len(statements) != 1 || !strings.Contains(fmt.Sprint(statements[0]), "IdempotencyTable") {
+		t.Errorf("expected access to the idempotency table alone, got %v", statements)
+	}
+}
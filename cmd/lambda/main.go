@@ -2,117 +2,661 @@ package main
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 
-	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-serverless/internal/handler"
 	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/sentryreport"
+	serverless "github.com/a2aproject/a2a-serverless/pkg/handler"
 )
 
-var h *handler.Handler
+// requestHandler is a single agent's Handler or a registry's Router
+type requestHandler interface {
+	HandleRequest(ctx context.Context, req handler.Request) handler.Response
+}
+
+var (
+	h             requestHandler
+	logger        *slog.Logger
+	logLevel      slog.LevelVar
+	// logFilter redacts every record and is configured like logLevel
+	logFilter     = a2aTypes.NewLogFilter()
+	configCache   *a2aTypes.CachedConfig
+	// cardWatcher swaps in agent card changes on warm instances when the
+	// card is kept in Parameter Store or S3; servedConfig is the config the
+	// card is applied to
+	cardWatcher  *a2aTypes.CardWatcher
+	servedConfig a2aTypes.ServerlessConfig
+	// awsClients builds each AWS client on first use, so requests that reach
+	// no service, such as agent card fetches, do not pay for them
+	awsClients    *a2aTypes.AWSClients
+	tracing       *a2aTypes.Tracing
+	metricsConfig a2aTypes.MetricsConfig
+	errorReporter *sentryreport.ErrorReporter
+	// recorder keeps a redacted copy of each request for cmd/replay when
+	// A2A_RECORD_EVENTS is set
+	recorder      a2aTypes.EventRecorder
+	// captureConfig keeps redacted JSON-RPC exchanges for admin/capture/get
+	// when a capture table or S3 URI is set
+	captureConfig a2aTypes.CaptureConfig
+	// quotaConfig counts usage against daily and monthly quotas when a
+	// quota table is set
+	quotaConfig   a2aTypes.QuotaConfig
+	// archiveConfig restores tasks cmd/archive moved to S3 when they are
+	// asked for
+	archiveConfig a2aTypes.ArchiveConfig
+	// deliveryConfig serves the webhook deliveries the worker records when a
+	// delivery table is set
+	deliveryConfig a2aTypes.DeliveryConfig
+	// replayConfig names the dead-letter queue admin/notifications/replay
+	// moves failed notifications back from
+	replayConfig a2aTypes.NotificationReplayConfig
+	// statsConfig counts task transitions and events for admin/stats/get
+	// when a stats table is set
+	statsConfig a2aTypes.StatsConfig
+	// workQueueConfig names the queues messages are queued on for workers
+	// with an executor to run, when a work queue is set
+	workQueueConfig a2aTypes.WorkQueueConfig
+	// contextLockConfig and dedupConfig name the tables that serialize the
+	// executions of a context and remember the messages received
+	contextLockConfig a2aTypes.ContextLockConfig
+	dedupConfig       a2aTypes.DedupConfig
+	// scheduleConfig names the table delayed messages wait in until a
+	// maintenance pass releases them onto the work queue
+	scheduleConfig a2aTypes.ScheduleConfig
+	// maintained are the agents' A2A handlers a maintenance event runs a
+	// pass for
+	maintained []*a2aTypes.ServerlessA2AHandler
+	// coldStart starts timing when the package is initialized, before init runs
+	coldStart         = a2aTypes.NewInitTimer()
+	coldStartReported bool
+)
+
+// errorFlushTimeout bounds how long an invocation waits to deliver error reports
+const errorFlushTimeout = 2 * time.Second
 
 func init() {
+	// Startup failures are logged at the default level until the config says otherwise
+	a2aTypes.SetLogLevel(&logLevel, os.Getenv("A2A_LOG_LEVEL"))
+	logger = a2aTypes.NewFilteredLogger(os.Stdout, &logLevel, logFilter)
+	slog.SetDefault(logger)
+
+	tracingConfig, err := a2aTypes.LoadTracingConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load tracing config", err)
+	}
+	// Shutdown is unused: Lambda gives no shutdown hook, so spans are
+	// flushed after every invocation instead
+	tracing, err = a2aTypes.SetupTracing(context.TODO(), tracingConfig)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
+	metricsConfig, err = a2aTypes.LoadMetricsConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load metrics config", err)
+	}
+	// Nothing can scrape a function between invocations
+	if metricsConfig.Mode == a2aTypes.MetricsModePrometheus {
+		fatal("Failed to load metrics config", fmt.Errorf("A2A_METRICS=prometheus needs a long-running server; use emf on Lambda"))
+	}
+
+	// Errors go to Sentry when a DSN is configured, otherwise only to the logs
+	if os.Getenv("SENTRY_DSN") != "" {
+		errorReporter, err = sentryreport.NewErrorReporter(sentry.ClientOptions{})
+		if err != nil {
+			fatal("Failed to set up error reporting", err)
+		}
+	}
+
 	// Load AWS configuration
+	stopClients := coldStart.Track(a2aTypes.InitPhaseAWSClients)
 	cfg, err := config.LoadDefaultConfig(context.TODO())
 	if err != nil {
-		log.Fatalf("Failed to load AWS config: %v", err)
+		fatal("Failed to load AWS config", err)
+	}
+	// Every DynamoDB, SQS and Secrets Manager call gets a client span
+	if tracing != nil {
+		otelaws.AppendMiddlewares(&cfg.APIOptions,
+			otelaws.WithTracerProvider(tracing.Provider),
+			otelaws.WithTextMapPropagator(tracing.Propagator),
+		)
+	}
+
+	// Data plane clients are selected per config in newHandler, since the
+	// config may carry its own credentials
+	awsClients = a2aTypes.NewAWSClients(cfg)
+	sourceClients := a2aTypes.ConfigSourceClients{
+		SecretsManager: awsClients.SecretsManager(),
+		SSM:            awsClients.SSM(),
+		S3:             awsClients.S3(),
+	}
+	stopClients()
+
+	recorder, err = a2aTypes.LoadEventRecorderFromEnv(awsClients.S3())
+	if err != nil {
+		fatal("Failed to set up event recording", err)
+	}
+	captureConfig, err = a2aTypes.LoadCaptureConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load capture config", err)
+	}
+	quotaConfig, err = a2aTypes.LoadQuotaConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load quota config", err)
+	}
+	archiveConfig, err = a2aTypes.LoadArchiveConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load archive config", err)
+	}
+	deliveryConfig, err = a2aTypes.LoadDeliveryConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load delivery config", err)
+	}
+	replayConfig = a2aTypes.LoadNotificationReplayConfigFromEnv()
+	statsConfig = a2aTypes.LoadStatsConfigFromEnv()
+	workQueueConfig, err = a2aTypes.LoadWorkQueueConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load work queue config", err)
+	}
+	contextLockConfig, err = a2aTypes.LoadContextLockConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load context lock config", err)
 	}
+	dedupConfig, err = a2aTypes.LoadDedupConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load dedup config", err)
+	}
+	scheduleConfig = a2aTypes.LoadScheduleConfigFromEnv()
+	if scheduleConfig.Table != "" && workQueueConfig.URL == "" {
+		fatal("Invalid configuration", fmt.Errorf("A2A_SCHEDULE_TABLE needs A2A_WORK_QUEUE_URL to release messages onto"))
+	}
+
+	// Config stored in Secrets Manager, Parameter Store or S3 is cached and
+	// refreshed on TTL expiry, otherwise it is read from environment variables
+	// once at cold start
+	source, err := a2aTypes.LoadConfigSourceFromEnv(sourceClients)
+	if err != nil {
+		fatal("Failed to select config source", err)
+	}
+	cardSource, err := a2aTypes.LoadCardSourceFromEnv(sourceClients)
+	if err != nil {
+		fatal("Failed to select agent card source", err)
+	}
+
+	// A registry file serves several agents in place of the single-agent config
+	stopConfig := coldStart.Track(a2aTypes.InitPhaseConfig)
+	registry, err := a2aTypes.LoadAgentRegistryFromEnv()
+	stopConfig()
+	if err != nil {
+		fatal("Failed to load agent registry", err)
+	}
+	if registry != nil {
+		if cardSource != nil {
+			fatal("Invalid configuration", fmt.Errorf("an agent card source cannot be used with an agent registry"))
+		}
+		// A queued message does not say which agent it is for
+		if workQueueConfig.URL != "" || workQueueConfig.RetryURL != "" {
+			fatal("Invalid configuration", fmt.Errorf("A2A_WORK_QUEUE_URL cannot be used with an agent registry"))
+		}
+		a2aTypes.SetLogLevel(&logLevel, registry.LogLevel())
+		logFilter.Set(registry.Logging())
+		h, err = newRouter(context.TODO(), registry)
+		if err != nil {
+			fatal("Failed to create agent router", err)
+		}
+		return
+	}
+
+	if cardSource != nil {
+		interval, err := a2aTypes.LoadCardPollInterval()
+		if err != nil {
+			fatal("Failed to load agent card poll interval", err)
+		}
+		cardWatcher = a2aTypes.NewCardWatcher(cardSource, interval)
+		if _, _, err := cardWatcher.Check(context.TODO()); err != nil {
+			fatal("Failed to load agent card", err)
+		}
+	}
+
+	var serverlessConfig a2aTypes.ServerlessConfig
+	if source != nil {
+		ttl, err := a2aTypes.LoadConfigCacheTTL()
+		if err != nil {
+			fatal("Failed to load config cache TTL", err)
+		}
+		configCache = a2aTypes.NewCachedConfig(validatedConfigSource(source), ttl)
+		serverlessConfig, _, err = configCache.Get(context.TODO())
+		if err != nil {
+			fatal("Failed to load config", err)
+		}
+	} else {
+		stopConfig := coldStart.Track(a2aTypes.InitPhaseConfig)
+		serverlessConfig, err = a2aTypes.LoadLambdaEnvConfig(cfg.Region)
+		stopConfig()
+		if err != nil {
+			fatal("Failed to load config", err)
+		}
+		serverlessConfig, err = resolveSecrets(context.TODO(), serverlessConfig)
+		if err != nil {
+			fatal("Failed to resolve secrets", err)
+		}
+		if cardWatcher != nil {
+			serverlessConfig = a2aTypes.ApplyAgentCard(serverlessConfig, cardWatcher.Card())
+		}
+	}
+
+	if err := a2aTypes.ValidateLambdaConfig(serverlessConfig); err != nil {
+		fatal("Invalid configuration", err)
+	}
+
+	a2aTypes.SetLogLevel(&logLevel, serverlessConfig.LogLevel)
+	logFilter.Set(serverlessConfig.Logging)
+
+	var a2aHandler *a2aTypes.ServerlessA2AHandler
+	h, a2aHandler, err = newHandler(serverlessConfig, "")
+	if err != nil {
+		fatal("Failed to create handler", err)
+	}
+	maintained = []*a2aTypes.ServerlessA2AHandler{a2aHandler}
+	servedConfig = serverlessConfig
+}
+
+// fatal logs a startup failure and exits so Lambda reports the init error
+func fatal(msg string, err error) {
+	logger.Error(msg, a2aTypes.LogKeyError, err)
+	os.Exit(1)
+}
+
+// validatedConfigSource resolves the secrets a remotely loaded config references, then validates it
+func validatedConfigSource(load a2aTypes.ConfigSource) a2aTypes.ConfigSource {
+	return func(ctx context.Context) (a2aTypes.ServerlessConfig, error) {
+		stopConfig := coldStart.Track(a2aTypes.InitPhaseConfig)
+		serverlessConfig, err := load(ctx)
+		stopConfig()
+		if err != nil {
+			return a2aTypes.ServerlessConfig{}, err
+		}
+		// A separately stored card replaces the one in the config
+		if cardWatcher != nil {
+			serverlessConfig = a2aTypes.ApplyAgentCard(serverlessConfig, cardWatcher.Card())
+		}
+		// Validate what the handler will run with, so a resolved secret that
+		// is unusable is refused and the cached config kept
+		serverlessConfig, err = resolveSecrets(ctx, serverlessConfig)
+		if err != nil {
+			return a2aTypes.ServerlessConfig{}, err
+		}
+		if err := a2aTypes.ValidateLambdaConfig(serverlessConfig); err != nil {
+			return a2aTypes.ServerlessConfig{}, err
+		}
+		return serverlessConfig, nil
+	}
+}
+
+// resolveSecrets uses a fresh resolver so rotated secrets are picked up on every refresh
+func resolveSecrets(ctx context.Context, serverlessConfig a2aTypes.ServerlessConfig) (a2aTypes.ServerlessConfig, error) {
+	defer coldStart.Track(a2aTypes.InitPhaseSecrets)()
+	secretResolver := a2aTypes.NewSecretResolver(awsClients.SecretsManager())
+	return a2aTypes.ResolveConfigSecrets(ctx, secretResolver, serverlessConfig)
+}
+
+// newRouter builds one handler per registered agent, each storing its tasks
+// under the agent's storage prefix in the shared tables
+func newRouter(ctx context.Context, registry *a2aTypes.AgentRegistry) (*handler.Router, error) {
+	handlers := make(map[string]*handler.Handler)
+	for _, agent := range registry.Agents() {
+		serverlessConfig, _ := registry.ServerlessConfig(agent.ID)
+		serverlessConfig, err := resolveSecrets(ctx, serverlessConfig)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %w", agent.ID, err)
+		}
+		if err := a2aTypes.ValidateLambdaConfig(serverlessConfig); err != nil {
+			return nil, fmt.Errorf("agent %s: %w", agent.ID, err)
+		}
+		var a2aHandler *a2aTypes.ServerlessA2AHandler
+		handlers[agent.ID], a2aHandler, err = newHandler(serverlessConfig, agent.StoragePrefix)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s: %w", agent.ID, err)
+		}
+		maintained = append(maintained, a2aHandler)
+	}
+	return handler.NewRouter(handlers), nil
+}
 
-	// Create AWS clients
-	dynamoClient := dynamodb.NewFromConfig(cfg)
-	sqsClient := sqs.NewFromConfig(cfg)
+// newHandler wires storage and the A2A handler for a given config, returning
+// the A2A handler as well for maintenance passes. keyPrefix namespaces the
+// agent's keys when several agents share the tables.
+func newHandler(serverlessConfig a2aTypes.ServerlessConfig, keyPrefix string) (*handler.Handler, *a2aTypes.ServerlessA2AHandler, error) {
+	provider, err := a2aTypes.NewConfigLoader().CreateCloudProvider(serverlessConfig.CloudConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if provider.GetProviderType() != a2aTypes.CloudProviderAWS {
+		return nil, nil, fmt.Errorf("the Lambda entrypoint only supports the aws provider, got %s", provider.GetProviderType())
+	}
+	storageConfig := provider.GetStorageConfig()
+	eventConfig := provider.GetEventConfig()
 
-	// Get configuration from environment variables
-	tableName := getEnvOrDefault("DYNAMODB_TABLE", "a2a-tasks")
-	eventsTable := getEnvOrDefault("DYNAMODB_EVENTS_TABLE", "a2a-events")
-	sqsQueueURL := getEnvOrDefault("SQS_QUEUE_URL", "")
-	agentName := getEnvOrDefault("AGENT_NAME", "A2A Serverless Agent")
-	agentURL := getEnvOrDefault("AGENT_URL", "https://example.com/agent")
+	dataPlane := awsClients.DataPlane(serverlessConfig.CloudConfig.AWS)
+	// No request waits on a provisioned concurrency init, so the clients
+	// every task touches are built there instead of on the first request
+	if a2aTypes.ProvisionedConcurrency() {
+		stopClients := coldStart.Track(a2aTypes.InitPhaseAWSClients)
+		dataPlane.Warm()
+		stopClients()
+	}
+	dynamoClient := dataPlane.DynamoDB()
 
 	// Create storage implementations
-	taskStore := a2aTypes.NewAWSTaskStore(dynamoClient, tableName)
-	eventStore := a2aTypes.NewAWSEventStore(dynamoClient, eventsTable)
-	pushNotifier := a2aTypes.NewAWSSQSPushNotifier(sqsClient, sqsQueueURL)
-
-	// Create agent card
-	agentCard := a2a.AgentCard{
-		Name:               agentName,
-		URL:                agentURL,
-		Description:        "A serverless A2A agent running on AWS Lambda",
-		ProtocolVersion:    "1.0",
-		Version:            "1.0.0",
-		PreferredTransport: a2a.TransportProtocolJSONRPC,
-		Capabilities: a2a.AgentCapabilities{
-			Streaming:         &[]bool{false}[0], // Non-streaming for serverless
-			PushNotifications: &[]bool{true}[0],  // Support push notifications
-		},
-		Skills: []a2a.AgentSkill{
-			{
-				ID:          "general",
-				Name:        "General Assistant",
-				Description: "General purpose AI assistant capabilities",
-				Examples:    []string{"Answer questions", "Help with tasks"},
-				Tags:        []string{"assistant", "general"},
-			},
-		},
-	}
-
-	// Create serverless config
-	serverlessConfig := a2aTypes.ServerlessConfig{
-		AgentID:   getEnvOrDefault("AGENT_ID", "serverless-agent-1"),
-		AgentCard: agentCard,
-		CloudConfig: a2aTypes.CloudProviderConfig{
-			Provider: "aws",
-			AWS: &a2aTypes.AWSConfig{
-				Region:        cfg.Region,
-				SQSQueueURL:   sqsQueueURL,
-				DynamoDBTable: tableName,
-			},
-		},
-		LogLevel: getEnvOrDefault("LOG_LEVEL", "info"),
+	storeOpts := []a2aTypes.RuntimeOption{
+		a2aTypes.WithStorageCompression(storageConfig.DynamoDBCompressAbove),
+		a2aTypes.WithRetention(serverlessConfig.Retention.Policy()),
+	}
+	// With global tables every region writes the same items, so writes say
+	// which region made them and the newest task wins
+	if serverlessConfig.CloudConfig.AWS.GlobalTables {
+		storeOpts = append(storeOpts, a2aTypes.WithRegion(storageConfig.Region))
+	}
+	var taskStore a2aTypes.TaskStore = a2aTypes.NewAWSTaskStore(dynamoClient, storageConfig.DynamoDBTable, keyPrefix, storeOpts...)
+	var eventStore a2aTypes.EventStore = a2aTypes.NewAWSEventStore(dynamoClient, storageConfig.DynamoDBEventsTable, keyPrefix, storeOpts...)
+	// Archived tasks hold what the tables held, so they are restored below
+	// the encryption
+	if archiveConfig.Enabled() {
+		archive, err := a2aTypes.NewS3TaskArchive(dataPlane.S3(), archiveConfig.S3URI, keyPrefix)
+		if err != nil {
+			return nil, nil, fmt.Errorf("A2A_ARCHIVE_S3_URI: %w", err)
+		}
+		taskStore = a2aTypes.NewArchivingTaskStore(taskStore, eventStore, archive)
+	}
+	var contentCipher *a2aTypes.ContentCipher
+	if key := serverlessConfig.Secrets.ContentEncryptionKey; key != "" {
+		contentCipher, err = a2aTypes.NewContentCipher(key.Reveal())
+		if err != nil {
+			return nil, nil, fmt.Errorf("secrets.content_encryption_key: %w", err)
+		}
+		taskStore = a2aTypes.NewEncryptedTaskStore(taskStore, contentCipher)
+		eventStore = a2aTypes.NewEncryptedEventStore(eventStore, contentCipher)
+	}
+	var pushNotifier a2aTypes.PushNotifier
+	if eventConfig.SQSQueueURL != "" {
+		pushNotifier = a2aTypes.NewAWSSQSPushNotifier(dataPlane.SQS(), eventConfig.SQSQueueURL, serverlessConfig.Secrets.WebhookSigningKey.Reveal(), tracing).
+			WithFailover(serverlessConfig.CloudConfig.AWS.FailoverQueueURLs...)
+	}
+
+	var auditLog a2aTypes.AuditLog
+	switch awsSettings := serverlessConfig.CloudConfig.AWS; {
+	case awsSettings.AuditTable != "":
+		auditLog = a2aTypes.NewAWSAuditLog(dynamoClient, awsSettings.AuditTable, keyPrefix)
+	case awsSettings.AuditFirehoseStream != "":
+		auditLog = a2aTypes.NewFirehoseAuditLog(dataPlane.Firehose(), awsSettings.AuditFirehoseStream, serverlessConfig.AgentID)
+	}
+
+	// Each agent counts its own stats under its key prefix
+	var handlerOpts []a2aTypes.RuntimeOption
+	// Push configs are only kept when there is a queue to notify them through,
+	// as the card's capability says
+	if pushNotifier != nil && storageConfig.DynamoDBPushConfigTable != "" {
+		handlerOpts = append(handlerOpts, a2aTypes.WithPushConfigStore(a2aTypes.NewAWSPushConfigStore(dynamoClient, storageConfig.DynamoDBPushConfigTable, keyPrefix)))
+	}
+	var statsStore a2aTypes.StatsStore
+	if statsConfig.Table != "" {
+		statsStore = a2aTypes.NewAWSStatsStore(dynamoClient, statsConfig.Table, keyPrefix)
+		handlerOpts = append(handlerOpts, a2aTypes.WithStats(statsStore))
+	}
+	// Without an executor here, messages are queued for a worker with one
+	handlerOpts = append(handlerOpts, workQueueConfig.RuntimeOptions(dataPlane.SQS())...)
+	handlerOpts = append(handlerOpts, contextLockConfig.RuntimeOptions(dynamoClient)...)
+	handlerOpts = append(handlerOpts, dedupConfig.RuntimeOptions(dynamoClient, keyPrefix, contentCipher)...)
+	handlerOpts = append(handlerOpts, scheduleConfig.RuntimeOptions(dynamoClient, keyPrefix)...)
+	// Maintenance passes time out the tasks no client reads, and
+	// admin/tasks/list lists every task, from a scan of the task table
+	if scanner, ok := dynamoClient.(a2aTypes.DynamoDBScanAPI); ok {
+		lister := a2aTypes.NewAWSTaskLister(scanner, storageConfig.DynamoDBTable, keyPrefix)
+		handlerOpts = append(handlerOpts, a2aTypes.WithTaskSweep(lister), a2aTypes.WithTaskListing(lister))
 	}
 
 	// Create A2A handler
-	a2aHandler := a2aTypes.NewServerlessA2AHandler(serverlessConfig, taskStore, eventStore, pushNotifier)
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(serverlessConfig, taskStore, eventStore, pushNotifier, handlerOpts...)
+
+	var opts []handler.Option
+	if tracing != nil {
+		opts = append(opts, handler.WithTracing(tracing))
+	}
+	if auditLog != nil {
+		opts = append(opts, handler.WithAuditLog(auditLog))
+	}
+	var captureStore a2aTypes.CaptureStore
+	switch {
+	case captureConfig.Table != "":
+		captureStore = a2aTypes.NewAWSCaptureStore(dynamoClient, captureConfig.Table, keyPrefix, captureConfig.TTL)
+	case captureConfig.S3URI != "":
+		captureStore, err = a2aTypes.NewS3CaptureStore(dataPlane.S3(), captureConfig.S3URI, keyPrefix, captureConfig.TTL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("A2A_CAPTURE_S3_URI: %w", err)
+		}
+	}
+	if captureStore != nil {
+		// Captures hold whole request and response bodies
+		if contentCipher != nil {
+			captureStore = a2aTypes.NewEncryptedCaptureStore(captureStore, contentCipher)
+		}
+		opts = append(opts, handler.WithCapture(captureStore))
+	}
+
+	// Each agent counts its own usage under its key prefix
+	if quotaConfig.Enabled() {
+		quotaStore := a2aTypes.NewAWSQuotaStore(dynamoClient, quotaConfig.Table, keyPrefix)
+		opts = append(opts, handler.WithQuotas(a2aTypes.NewQuotas(quotaStore, quotaConfig, serverlessConfig.AgentID)))
+	}
+
+	// The worker records attempts by bare task ID; the handler only lists
+	// those of this agent's tasks
+	if deliveryConfig.Table != "" {
+		opts = append(opts, handler.WithDeliveryLog(a2aTypes.NewAWSDeliveryLog(dynamoClient, deliveryConfig.Table, deliveryConfig.TTL)))
+	}
+
+	if replayConfig.DeadLetterQueueURL != "" && eventConfig.SQSQueueURL != "" {
+		opts = append(opts, handler.WithNotificationReplay(a2aTypes.NewSQSNotificationReplayer(dataPlane.SQS(), replayConfig.DeadLetterQueueURL, eventConfig.SQSQueueURL)))
+	}
+
+	if statsStore != nil {
+		var queues a2aTypes.QueueDepthReader
+		if eventConfig.SQSQueueURL != "" {
+			queues = a2aTypes.NewSQSQueueDepths(dataPlane.SQS(), eventConfig.SQSQueueURL, replayConfig.DeadLetterQueueURL)
+		}
+		opts = append(opts, handler.WithStats(a2aTypes.NewStats(statsStore, queues)))
+	}
+
+	if serverlessConfig.Security.StrictJSONRPC {
+		opts = append(opts, handler.WithStrictJSONRPC())
+	}
+	if len(serverlessConfig.Localization.Locales) > 0 || serverlessConfig.Localization.DefaultLocale != "" {
+		opts = append(opts, handler.WithLocalization(serverlessConfig.Localization))
+	}
+
+	// Off unless an admin API key is configured
+	if admin := a2aTypes.NewAdminAuthenticator(serverlessConfig.Admin, serverlessConfig.Secrets.AdminAPIKey); admin != nil {
+		opts = append(opts, handler.WithAdminAPI(admin))
+	}
+	// Only a config loaded from a remote source can change without a deploy
+	if configCache != nil {
+		opts = append(opts, handler.WithConfigReload(reloadConfig))
+	}
+
+	// Signed requests and single-use tokens are checked against one table,
+	// so a copy sent to another instance is refused as well
+	var authOpts []a2aTypes.AuthenticatorOption
+	if table := serverlessConfig.Security.ReplayTable; table != "" {
+		authOpts = append(authOpts, a2aTypes.WithNonceStore(a2aTypes.NewAWSNonceStore(dynamoClient, table, keyPrefix)))
+	}
 
 	// Create HTTP handler
-	h = handler.NewHandler(a2aHandler, agentCard)
+	authenticator := a2aTypes.NewAuthenticator(serverlessConfig.Security, serverlessConfig.Secrets, nil, authOpts...)
+	opts = append(opts, handler.WithAuthenticator(authenticator))
+	return handler.NewHandler(a2aHandler, serverlessConfig.AgentCard, opts...), a2aHandler, nil
 }
 
-func handleLambda(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Convert Lambda request to internal format
-	req := handler.Request{
-		Method:  request.HTTPMethod,
-		URL:     request.Path,
-		Headers: request.Headers,
-		Body:    request.Body,
+// handleLambda serves API Gateway requests and answers warm-up pings, which
+// keep provisioned or scheduled-warm environments alive without reaching storage
+func handleLambda(ctx context.Context, payload json.RawMessage) (events.APIGatewayProxyResponse, error) {
+	ctx = a2aTypes.ContextWithLogger(ctx, logger)
+	if tracing != nil {
+		defer func() {
+			if err := tracing.Flush(ctx); err != nil {
+				a2aTypes.LoggerFromContext(ctx).Warn("failed to flush traces", a2aTypes.LogKeyError, err)
+			}
+		}()
 	}
 
-	// Process request using A2A handler
-	response := h.HandleRequest(req)
+	if errorReporter != nil {
+		ctx = a2aTypes.ContextWithErrorReporter(ctx, errorReporter)
+		defer errorReporter.Flush(errorFlushTimeout)
+	}
 
-	// Convert to Lambda response format
-	return events.APIGatewayProxyResponse{
-		StatusCode: response.Status,
-		Headers:    response.Headers,
-		Body:       response.Body,
-	}, nil
+	// EMF documents are log lines, so they are buffered per invocation and
+	// written to stdout where the Lambda log agent picks them up
+	if metricsConfig.Mode == a2aTypes.MetricsModeEMF {
+		metrics := a2aTypes.NewEMFMetrics(metricsConfig.Namespace)
+		ctx = a2aTypes.ContextWithMetrics(ctx, metrics)
+		defer func() {
+			if err := metrics.Flush(os.Stdout); err != nil {
+				a2aTypes.LoggerFromContext(ctx).Warn("failed to write metrics", a2aTypes.LogKeyError, err)
+			}
+		}()
+	}
+
+	if configCache != nil {
+		serverlessConfig, refreshed, err := configCache.Get(ctx)
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, err
+		}
+		if refreshed {
+			// Keep serving the previous handler rather than failing every request
+			if err := applyConfig(serverlessConfig); err != nil {
+				a2aTypes.LoggerFromContext(ctx).Warn("refreshed config not applied", a2aTypes.LogKeyError, err)
+			}
+		}
+	}
+
+	if cardWatcher != nil {
+		refreshCard(ctx)
+	}
+
+	// The first invocation carries the cold start it paid for
+	if !coldStartReported {
+		coldStartReported = true
+		coldStart.Record(a2aTypes.MetricsFromContext(ctx))
+		ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyColdStart, true)
+	}
+
+	// A scheduled rule with the input {"maintenance": true} runs the pass
+	// releasing scheduled messages and timing out tasks
+	if a2aTypes.IsMaintenanceEvent(payload) {
+		for _, a2aHandler := range maintained {
+			if _, err := a2aHandler.RunMaintenance(ctx); err != nil {
+				return events.APIGatewayProxyResponse{}, err
+			}
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}
+	// A ping still refreshes an expired config above, off the request path
+	if a2aTypes.IsWarmupEvent(payload) {
+		a2aTypes.LoggerFromContext(ctx).Debug("warm-up invocation")
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	}
+	var request events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return events.APIGatewayProxyResponse{}, fmt.Errorf("decoding API Gateway request: %w", err)
+	}
+
+	if recorder != nil {
+		recordRequest(ctx, request)
+	}
+
+	return serverless.LambdaHandler(h)(ctx, request)
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// applyConfig serves serverlessConfig from the next request on, keeping the
+// current handler if no handler can be built from it
+func applyConfig(serverlessConfig a2aTypes.ServerlessConfig) error {
+	refreshedHandler, a2aHandler, err := newHandler(serverlessConfig, "")
+	if err != nil {
+		return err
+	}
+	h = refreshedHandler
+	maintained = []*a2aTypes.ServerlessA2AHandler{a2aHandler}
+	servedConfig = serverlessConfig
+	a2aTypes.SetLogLevel(&logLevel, serverlessConfig.LogLevel)
+	logFilter.Set(serverlessConfig.Logging)
+	return nil
+}
+
+// reloadConfig serves admin/config/reload: the config is loaded from its
+// source now rather than when the cache expires. Only the execution
+// environment serving the call reloads; the others do when their cache
+// expires.
+func reloadConfig(ctx context.Context) error {
+	serverlessConfig, err := configCache.Reload(ctx)
+	if err != nil {
+		return err
+	}
+	return applyConfig(serverlessConfig)
+}
+
+// refreshCard serves a changed agent card without rebuilding the handler,
+// keeping the current card if the new one is invalid
+func refreshCard(ctx context.Context) {
+	card, changed, _ := cardWatcher.Check(ctx)
+	if !changed {
+		return
+	}
+	updated := a2aTypes.ApplyAgentCard(servedConfig, card)
+	err := a2aTypes.ValidateLambdaConfig(updated)
+	if err == nil {
+		err = h.(*handler.Handler).SetAgentCard(updated.AgentCard)
+	}
+	if err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("refreshed agent card not applied", a2aTypes.LogKeyError, err)
+		return
+	}
+	servedConfig = updated
+	a2aTypes.LoggerFromContext(ctx).Info("agent card refreshed")
+}
+
+// recordRequest saves the request with its credentials redacted. Recording
+// is best effort and never fails the request.
+func recordRequest(ctx context.Context, request events.APIGatewayProxyRequest) {
+	request.Headers = a2aTypes.RedactHeaders(request.Headers)
+	request.MultiValueHeaders = a2aTypes.RedactMultiValueHeaders(request.MultiValueHeaders)
+	request.RequestContext.Authorizer = nil
+	payload, err := json.Marshal(request)
+	if err == nil {
+		err = recorder.RecordEvent(ctx, a2aTypes.RecordedEvent{
+			Source:     a2aTypes.RecordedSourceAPIGateway,
+			RecordedAt: time.Now(),
+			RequestID:  request.RequestContext.RequestID,
+			Payload:    payload,
+		})
+	}
+	if err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("failed to record request", a2aTypes.LogKeyError, err)
 	}
-	return defaultValue
 }
 
 func main() {
+	coldStart.Finish()
+	logger.Info("cold start complete", coldStart.LogAttr())
 	lambda.Start(handleLambda)
-}
\ No newline at end of file
+}
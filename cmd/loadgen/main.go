@@ -0,0 +1,520 @@
+// Command loadgen fires concurrent JSON-RPC workloads at an agent and reports
+// latency percentiles per method, to guide capacity planning:
+//
+//	loadgen -url https://agent.example.com -concurrency 20 -duration 1m -mix send=60,get=30,cancel=10
+//
+// Without -url the handler runs in-process: against in-memory storage, or
+// with -provider aws against the DynamoDB tables in the Lambda's environment,
+// in which case the consumed DynamoDB capacity is reported too.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+// Workload operations selected by -mix
+const (
+	opSend   = "send"
+	opGet    = "get"
+	opCancel = "cancel"
+	opStream = "stream"
+)
+
+// opMethods maps each operation to the JSON-RPC method it calls
+var opMethods = map[string]string{
+	opSend:   "message/send",
+	opGet:    "tasks/get",
+	opCancel: "tasks/cancel",
+	opStream: "message/stream",
+}
+
+// maxKnownTasks bounds the task IDs kept for get and cancel calls
+const maxKnownTasks = 1000
+
+// inProcessURL is the endpoint used when the handler runs in-process
+const inProcessURL = "http://loadgen.local/"
+
+// headerFlags collects repeated -header "Name: value" flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string { return "" }
+
+func (h headerFlags) Set(value string) error {
+	name, headerValue, ok := strings.Cut(value, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected Name: value, got %q", value)
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+	return nil
+}
+
+func main() {
+	url := flag.String("url", "", "JSON-RPC endpoint of the agent; the handler runs in-process when unset")
+	provider := flag.String("provider", "memory", "storage for the in-process handler: memory, or aws with the Lambda's environment")
+	concurrency := flag.Int("concurrency", 10, "concurrent workers")
+	duration := flag.Duration("duration", 30*time.Second, "how long to run")
+	requests := flag.Int("requests", 0, "stop after this many requests (0 runs for -duration)")
+	mix := flag.String("mix", "send=60,get=30,cancel=10", "weighted operations: send, get, cancel and stream")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	headers := headerFlags{}
+	flag.Var(headers, "header", `header sent with every request, e.g. "X-API-Key: secret" (repeatable)`)
+	flag.Parse()
+
+	var logLevel slog.LevelVar
+	a2aTypes.SetLogLevel(&logLevel, getEnvOrDefault("A2A_LOG_LEVEL", "error"))
+	logger := a2aTypes.NewLogger(os.Stderr, &logLevel)
+	slog.SetDefault(logger)
+
+	weights, err := parseMix(*mix)
+	if err != nil {
+		logger.Error("invalid -mix", a2aTypes.LogKeyError, err)
+		os.Exit(2)
+	}
+
+	httpClient := &http.Client{Timeout: *timeout}
+	endpoint := *url
+	var meter *a2aTypes.CapacityMeter
+	if endpoint == "" {
+		var h *handler.Handler
+		h, meter, err = newInProcessHandler(context.Background(), *provider)
+		if err != nil {
+			logger.Error("failed to create handler", a2aTypes.LogKeyError, err)
+			os.Exit(1)
+		}
+		httpClient.Transport = handlerTransport{h: h, logger: logger}
+		endpoint = inProcessURL
+	}
+
+	g := &generator{
+		client:  httpClient,
+		url:     endpoint,
+		headers: headers,
+		weights: weights,
+		results: newResults(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+	elapsed := g.run(ctx, *concurrency, *requests)
+
+	g.results.report(os.Stdout, elapsed)
+	if meter != nil {
+		reportCapacity(os.Stdout, meter.Usage(), g.results.total())
+	}
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// newInProcessHandler builds the handler over the chosen storage. Push
+// notifications are never queued. The returned meter is nil for memory.
+func newInProcessHandler(ctx context.Context, provider string) (*handler.Handler, *a2aTypes.CapacityMeter, error) {
+	switch provider {
+	case "memory":
+		return a2atest.NewHandler(a2atest.NewTaskStore(), a2atest.NewEventStore(), nil), nil, nil
+	case "aws":
+	default:
+		return nil, nil, fmt.Errorf("unknown provider %q: expected memory or aws", provider)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	serverlessConfig, err := a2aTypes.LoadLambdaEnvConfig(cfg.Region)
+	if err != nil {
+		return nil, nil, err
+	}
+	cloudProvider, err := a2aTypes.NewConfigLoader().CreateCloudProvider(serverlessConfig.CloudConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cloudProvider.GetProviderType() != a2aTypes.CloudProviderAWS {
+		return nil, nil, fmt.Errorf("-provider aws needs CLOUD_PROVIDER=aws, got %s", cloudProvider.GetProviderType())
+	}
+	storageConfig := cloudProvider.GetStorageConfig()
+
+	meter := a2aTypes.NewCapacityMeter()
+	dynamoClient := dynamodb.NewFromConfig(a2aTypes.AWSDataPlaneConfig(cfg, serverlessConfig.CloudConfig.AWS), meter.ClientOption)
+	// Compressed as the Lambda would, so the capacity measured is what it uses
+	compression := a2aTypes.WithStorageCompression(storageConfig.DynamoDBCompressAbove)
+	taskStore := a2aTypes.NewAWSTaskStore(dynamoClient, storageConfig.DynamoDBTable, "", compression)
+	eventStore := a2aTypes.NewAWSEventStore(dynamoClient, storageConfig.DynamoDBEventsTable, "", compression)
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(serverlessConfig, taskStore, eventStore, nil)
+	return handler.NewHandler(a2aHandler, serverlessConfig.AgentCard), meter, nil
+}
+
+// handlerTransport serves HTTP requests with an in-process handler, shaped
+// like the requests API Gateway delivers
+type handlerTransport struct {
+	h      *handler.Handler
+	logger *slog.Logger
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	headers := make(map[string]string, len(req.Header))
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = req.Header.Get(name)
+	}
+
+	resp := t.h.HandleRequest(a2aTypes.ContextWithLogger(req.Context(), t.logger), handler.Request{
+		Method:  req.Method,
+		URL:     req.URL.Path,
+		Headers: headers,
+		Body:    string(body),
+	})
+
+	header := make(http.Header, len(resp.Headers))
+	for name, value := range resp.Headers {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		StatusCode: resp.Status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(resp.Body)),
+		Request:    req,
+	}, nil
+}
+
+// weightedOp is one operation of the mix with its share of requests
+type weightedOp struct {
+	op     string
+	weight int
+}
+
+// parseMix reads "send=60,get=30,cancel=10" into weighted operations
+func parseMix(mix string) ([]weightedOp, error) {
+	var weights []weightedOp
+	for _, entry := range strings.Split(mix, ",") {
+		op, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if _, known := opMethods[op]; !ok || !known {
+			return nil, fmt.Errorf("expected op=weight with op one of send, get, cancel or stream, got %q", entry)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", op, value)
+		}
+		if weight > 0 {
+			weights = append(weights, weightedOp{op: op, weight: weight})
+		}
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no operation has a positive weight")
+	}
+	return weights, nil
+}
+
+// pick chooses an operation with probability proportional to its weight
+func pick(weights []weightedOp, rng *rand.Rand) string {
+	total := 0
+	for _, w := range weights {
+		total += w.weight
+	}
+	n := rng.IntN(total)
+	for _, w := range weights {
+		if n < w.weight {
+			return w.op
+		}
+		n -= w.weight
+	}
+	return weights[len(weights)-1].op
+}
+
+// generator runs the workload and keeps the tasks it created so get and
+// cancel calls have real tasks to target
+type generator struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	weights []weightedOp
+	results *results
+
+	mu    sync.Mutex
+	tasks []a2a.TaskID
+}
+
+// run starts concurrency workers until ctx is done or maxRequests have been
+// sent, and returns how long they ran
+func (g *generator) run(ctx context.Context, concurrency, maxRequests int) time.Duration {
+	var sent atomic.Int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for worker := range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(start.UnixNano()), uint64(worker)))
+			for ctx.Err() == nil {
+				if maxRequests > 0 && sent.Add(1) > int64(maxRequests) {
+					return
+				}
+				g.do(ctx, pick(g.weights, rng), rng)
+			}
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// do sends one operation and records its outcome. Calls cut short by the
+// end of the run are not recorded.
+func (g *generator) do(ctx context.Context, op string, rng *rand.Rand) {
+	taskID, ok := g.knownTask(rng)
+	if !ok && (op == opGet || op == opCancel) {
+		// Nothing to read or cancel yet
+		op = opSend
+	}
+
+	var params any
+	switch op {
+	case opSend, opStream:
+		message := a2atest.UserMessage(fmt.Sprintf("loadgen-%d", rng.Uint64()), "load test message")
+		params = a2a.MessageSendParams{Message: message}
+	case opGet:
+		params = a2a.TaskQueryParams{ID: taskID}
+	case opCancel:
+		params = a2a.TaskIDParams{ID: taskID}
+	}
+
+	start := time.Now()
+	result, err := g.call(ctx, opMethods[op], params, op == opStream)
+	latency := time.Since(start)
+	if ctx.Err() != nil {
+		return
+	}
+	g.results.record(op, latency, err)
+
+	if err == nil && op == opCancel {
+		g.forgetTask(taskID)
+	}
+	if err == nil && op == opSend {
+		var created struct {
+			Kind string
+			ID   a2a.TaskID
+		}
+		if json.Unmarshal(result, &created) == nil && created.Kind == "task" {
+			g.rememberTask(created.ID)
+		}
+	}
+}
+
+func (g *generator) knownTask(rng *rand.Rand) (a2a.TaskID, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.tasks) == 0 {
+		return "", false
+	}
+	return g.tasks[rng.IntN(len(g.tasks))], true
+}
+
+func (g *generator) rememberTask(taskID a2a.TaskID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = append(g.tasks, taskID)
+	if len(g.tasks) > maxKnownTasks {
+		g.tasks = g.tasks[len(g.tasks)-maxKnownTasks:]
+	}
+}
+
+// forgetTask stops targeting a canceled task
+func (g *generator) forgetTask(taskID a2a.TaskID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = slices.DeleteFunc(g.tasks, func(id a2a.TaskID) bool { return id == taskID })
+}
+
+// call sends one JSON-RPC request and returns its result. A streamed
+// response is read to the end; its latency is the time to the last event.
+func (g *generator) call(ctx context.Context, method string, params any, stream bool) (json.RawMessage, error) {
+	body, err := a2aTypes.SerializeJSONRPCRequest(a2aTypes.NewJSONRPCRequest(method, params, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	for name, value := range g.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return nil, nil
+	}
+
+	rpcResp, err := a2aTypes.ParseJSONRPCResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s response: %v", method, err)
+	}
+	if rpcResp.Error != nil {
+		return nil, rpcResp.Error
+	}
+	return rpcResp.ResultJSON()
+}
+
+// opResults are the outcomes of one operation
+type opResults struct {
+	latencies []time.Duration
+	errors    int
+}
+
+// results collects latencies and errors by operation
+type results struct {
+	mu     sync.Mutex
+	ops    map[string]*opResults
+	errors map[string]int
+}
+
+func newResults() *results {
+	return &results{ops: make(map[string]*opResults), errors: make(map[string]int)}
+}
+
+func (r *results) record(op string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results, ok := r.ops[op]
+	if !ok {
+		results = &opResults{}
+		r.ops[op] = results
+	}
+	results.latencies = append(results.latencies, latency)
+	if err != nil {
+		results.errors++
+		r.errors[op+": "+errorKey(err)]++
+	}
+}
+
+// errorKey groups JSON-RPC errors by code, leaving out the per-task detail
+func errorKey(err error) string {
+	var rpcErr *a2aTypes.JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return fmt.Sprintf("JSON-RPC error %d: %s", rpcErr.Code, rpcErr.Message)
+	}
+	return err.Error()
+}
+
+// total returns how many requests were recorded
+func (r *results) total() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, results := range r.ops {
+		total += len(results.latencies)
+	}
+	return total
+}
+
+// report writes a latency table per operation followed by the distinct errors
+func (r *results) report(w io.Writer, elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "op\trequests\terrors\treq/s\tp50\tp90\tp99\tmax\t")
+	ops := make([]string, 0, len(r.ops))
+	for op := range r.ops {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		results := r.ops[op]
+		latencies := slices.Clone(results.latencies)
+		slices.Sort(latencies)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n", op, len(latencies), results.errors,
+			float64(len(latencies))/elapsed.Seconds(),
+			percentile(latencies, 0.5), percentile(latencies, 0.9), percentile(latencies, 0.99), percentile(latencies, 1))
+	}
+	tw.Flush()
+
+	if len(r.errors) > 0 {
+		fmt.Fprintln(w, "\nerrors:")
+		messages := make([]string, 0, len(r.errors))
+		for message := range r.errors {
+			messages = append(messages, message)
+		}
+		sort.Strings(messages)
+		for _, message := range messages {
+			fmt.Fprintf(w, "  %6d  %s\n", r.errors[message], message)
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile p (0-1] of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)].Round(time.Microsecond)
+}
+
+// reportCapacity writes the DynamoDB capacity consumed by each operation and
+// per A2A request
+func reportCapacity(w io.Writer, usage []a2aTypes.CapacityUsage, requests int) {
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Operation < usage[j].Operation })
+	fmt.Fprintln(w, "\nDynamoDB consumed capacity:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\tcalls\tunits\tunits/request\t")
+	var totalUnits float64
+	for _, u := range usage {
+		totalUnits += u.Units
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%.2f\t\n", u.Operation, u.Calls, u.Units, u.Units/float64(max(requests, 1)))
+	}
+	fmt.Fprintf(tw, "total\t\t%.1f\t%.2f\t\n", totalUnits, totalUnits/float64(max(requests, 1)))
+	tw.Flush()
+}
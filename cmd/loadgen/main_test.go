@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+func TestParseMix(t *testing.T) {
+	tests := []struct {
+		name        string
+		mix         string
+		expectOps   int
+		expectError bool
+	}{
+		{name: "default", mix: "send=60,get=30,cancel=10", expectOps: 3},
+		{name: "zero weights dropped", mix: "send=1, stream=0", expectOps: 1},
+		{name: "unknown op", mix: "send=1,delete=1", expectError: true},
+		{name: "missing weight", mix: "send", expectError: true},
+		{name: "negative weight", mix: "send=-1", expectError: true},
+		{name: "nothing to run", mix: "send=0", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights, err := parseMix(tt.mix)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(weights) != tt.expectOps {
+				t.Errorf("expected %d operations, got %+v", tt.expectOps, weights)
+			}
+		})
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	tasks := a2atest.NewTaskStore()
+	h := a2atest.NewHandler(tasks, a2atest.NewEventStore(), nil)
+	weights, _ := parseMix("send=2,get=1,cancel=1,stream=1")
+	g := &generator{
+		client:  &http.Client{Transport: handlerTransport{h: h, logger: slog.New(slog.DiscardHandler)}},
+		url:     inProcessURL,
+		weights: weights,
+		results: newResults(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	elapsed := g.run(ctx, 4, 200)
+
+	if got := g.results.total(); got != 200 {
+		t.Fatalf("expected 200 requests, got %d", got)
+	}
+	if sends := g.results.ops[opSend]; sends == nil || sends.errors != 0 || len(tasks.Tasks()) != len(sends.latencies) {
+		t.Errorf("expected every send to create a task, got %+v and %d tasks", sends, len(tasks.Tasks()))
+	}
+	if gets := g.results.ops[opGet]; gets == nil || gets.errors != 0 {
+		t.Errorf("expected gets of created tasks to succeed, got %+v", gets)
+	}
+
+	var out strings.Builder
+	g.results.report(&out, elapsed)
+	// This handler has no streaming, so stream calls are reported as errors
+	for _, want := range []string{"p99", "send", "stream: JSON-RPC error -32004: Unsupported operation"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in report:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	for p, want := range map[float64]time.Duration{0.5: 50 * time.Millisecond, 0.99: 99 * time.Millisecond, 1: 100 * time.Millisecond} {
+		if got := percentile(latencies, p); got != want {
+			t.Errorf("p%v: expected %s, got %s", p*100, want, got)
+		}
+	}
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("expected 0 for no latencies, got %s", got)
+	}
+}
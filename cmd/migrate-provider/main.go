@@ -0,0 +1,182 @@
+// Command migrate-provider copies every task, its events and its push
+// notification configs from one provider's stores to another's, for moving
+// an agent between providers without losing its tasks:
+//
+//	migrate-provider -from local -to aws -to-tasks prod-a2a-tasks -to-events prod-a2a-events
+//
+// Tasks are read and written through the TaskStore, EventStore and
+// PushConfigStore interfaces. Each copied task is recorded in the -checkpoint file, so an
+// interrupted copy resumes with the tasks it had not finished. Run it while
+// the source agent is stopped or read-only: tasks changed after they were
+// copied are not copied again.
+//
+// The providers with stores in this build are aws (DynamoDB) and local,
+// which keeps files under the local provider's storage and event paths.
+// There are no gcp or azure stores in this module, so moves to or from them
+// are refused until those stores exist.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// migrationReadBudget lifts the event store's read budget, which protects
+// API Gateway responses and would otherwise stop the copy of long tasks
+const migrationReadBudget = 1 << 30
+
+// providerFlags locate one side of the migration
+type providerFlags struct {
+	Provider string
+	// Tasks, Events and PushConfigs are table names for aws and directories
+	// for local. An aws agent without a push config table has no configs.
+	Tasks       string
+	Events      string
+	PushConfigs string
+	// Prefix is the key prefix of agents sharing a table (aws)
+	Prefix string
+	Region string
+	// CompressAbove gzips task and event data over this many bytes (aws)
+	CompressAbove int
+}
+
+// register adds the flags for side ("from" or "to") to fs
+func (f *providerFlags) register(fs *flag.FlagSet, side string) {
+	fs.StringVar(&f.Provider, side, "", "Provider to copy "+side+": aws or local")
+	fs.StringVar(&f.Tasks, side+"-tasks", "", "Task table (aws, default a2a-tasks) or directory (local, default ./local_storage)")
+	fs.StringVar(&f.Events, side+"-events", "", "Event table (aws, default "+a2aTypes.DefaultEventsTable+") or directory (local, default ./local_events)")
+	fs.StringVar(&f.PushConfigs, side+"-push-configs", "", "Push notification config table, as DYNAMODB_PUSH_CONFIG_TABLE (aws, default none), or directory (local, default ./local_push_configs)")
+	fs.StringVar(&f.Prefix, side+"-prefix", "", "Key prefix of the agent's items in shared tables (aws)")
+	fs.StringVar(&f.Region, side+"-region", "", "AWS region, if not the default one (aws)")
+}
+
+// withDefaults fills in the provider's default locations, the same ones the
+// entrypoints use
+func (f providerFlags) withDefaults() (providerFlags, error) {
+	switch a2aTypes.CloudProvider(f.Provider) {
+	case a2aTypes.CloudProviderAWS:
+		f.Tasks = defaultString(f.Tasks, "a2a-tasks")
+		f.Events = defaultString(f.Events, a2aTypes.DefaultEventsTable)
+	case a2aTypes.CloudProviderLocal:
+		f.Tasks = defaultString(f.Tasks, "./local_storage")
+		f.Events = defaultString(f.Events, "./local_events")
+		f.PushConfigs = defaultString(f.PushConfigs, "./local_push_configs")
+		if f.Prefix != "" {
+			return f, fmt.Errorf("key prefixes are only supported by aws")
+		}
+	case a2aTypes.CloudProviderGCP, a2aTypes.CloudProviderAzure:
+		return f, fmt.Errorf("no %s stores in this build, only aws and local are migrated", f.Provider)
+	default:
+		return f, fmt.Errorf("unknown provider %q: expected aws or local", f.Provider)
+	}
+	return f, nil
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// openStores opens the stores f locates
+func openStores(ctx context.Context, f providerFlags) (stores, error) {
+	if a2aTypes.CloudProvider(f.Provider) == a2aTypes.CloudProviderLocal {
+		store, err := newLocalStore(f.Tasks, f.Events, f.PushConfigs)
+		if err != nil {
+			return stores{}, err
+		}
+		return stores{tasks: store, events: store, pushConfigs: store, list: store}, nil
+	}
+
+	var loadOpts []func(*config.LoadOptions) error
+	if f.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(f.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return stores{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := dynamodb.NewFromConfig(cfg)
+	opts := []a2aTypes.RuntimeOption{
+		a2aTypes.WithStorageCompression(f.CompressAbove),
+		a2aTypes.WithEventReadBudget(migrationReadBudget),
+	}
+	s := stores{
+		tasks:  a2aTypes.NewAWSTaskStore(client, f.Tasks, f.Prefix, opts...),
+		events: a2aTypes.NewAWSEventStore(client, f.Events, f.Prefix, opts...),
+		list:   a2aTypes.NewAWSTaskLister(client, f.Tasks, f.Prefix),
+	}
+	if f.PushConfigs != "" {
+		s.pushConfigs = a2aTypes.NewAWSPushConfigStore(client, f.PushConfigs, f.Prefix)
+	}
+	return s, nil
+}
+
+func main() {
+	var from, to providerFlags
+	from.register(flag.CommandLine, "from")
+	to.register(flag.CommandLine, "to")
+	flag.IntVar(&to.CompressAbove, "to-compress-above", 0, "Gzip task and event data over this many bytes, as DYNAMODB_COMPRESS_ABOVE (aws)")
+	checkpointPath := flag.String("checkpoint", "migrate.checkpoint", "File recording the copied tasks; rerun with the same file to resume")
+	dryRun := flag.Bool("dry-run", false, "Read and count what would be copied, skipping the checkpoint's tasks, without writing")
+	flag.Parse()
+
+	var err error
+	if from, err = from.withDefaults(); err != nil {
+		fmt.Fprintf(os.Stderr, "-from: %v\n", err)
+		os.Exit(2)
+	}
+	if to, err = to.withDefaults(); err != nil {
+		fmt.Fprintf(os.Stderr, "-to: %v\n", err)
+		os.Exit(2)
+	}
+	if from.Provider == to.Provider && from.Tasks == to.Tasks && from.Events == to.Events && from.PushConfigs == to.PushConfigs && from.Prefix == to.Prefix && from.Region == to.Region {
+		fmt.Fprintln(os.Stderr, "-from and -to are the same stores")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	m := migration{dryRun: *dryRun, log: os.Stderr}
+	if m.from, err = openStores(ctx, from); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		if m.to, err = openStores(ctx, to); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *checkpointPath != "" {
+		if m.checkpoint, err = openCheckpoint(*checkpointPath); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		defer m.checkpoint.Close()
+	}
+
+	stats, err := m.run(ctx)
+	verb := "copied"
+	if *dryRun {
+		verb = "to copy"
+	}
+	fmt.Fprintf(os.Stderr, "%d tasks, %d events and %d push configs %s, %d already copied\n", stats.Tasks, stats.Events, stats.PushConfigs, verb, stats.Skipped)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migration stopped: %v\n", err)
+		if *checkpointPath != "" && !*dryRun {
+			fmt.Fprintf(os.Stderr, "run again with -checkpoint %s to resume\n", *checkpointPath)
+		}
+		m.checkpoint.Close()
+		os.Exit(1)
+	}
+}
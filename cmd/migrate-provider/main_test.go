@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+func newTask(id, contextID string) a2a.Task {
+	return a2a.Task{
+		Kind:      "task",
+		ID:        a2a.TaskID(id),
+		ContextID: contextID,
+		Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted},
+	}
+}
+
+func newStatusEvent(id string, state a2a.TaskState) a2a.TaskStatusUpdateEvent {
+	return a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: a2a.TaskID(id), ContextID: "ctx", Status: a2a.TaskStatus{State: state}}
+}
+
+// newLocalStores fills a local store with two tasks and their events, and a
+// push config on the first
+func newLocalStores(t *testing.T) (*localStore, stores) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := newLocalStore(filepath.Join(dir, "tasks"), filepath.Join(dir, "events"), filepath.Join(dir, "push-configs"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"task-1", "task-2"} {
+		if err := store.SaveTask(ctx, newTask(id, "ctx")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, state := range []a2a.TaskState{a2a.TaskStateWorking, a2a.TaskStateCompleted} {
+			if err := store.SaveEvent(ctx, newStatusEvent(id, state)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if err := store.SavePushConfig(ctx, a2a.TaskPushConfig{TaskID: "task-1", Config: a2a.PushConfig{URL: "https://hooks.example.com/a2a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return store, stores{tasks: store, events: store, pushConfigs: store, list: store}
+}
+
+func TestLocalStore(t *testing.T) {
+	store, _ := newLocalStores(t)
+	ctx := context.Background()
+
+	// Saving an event twice keeps one copy
+	if err := store.SaveEvent(ctx, newStatusEvent("task-1", a2a.TaskStateWorking)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events, err := store.GetEvents(ctx, "task-1")
+	if err != nil || len(events) != 2 {
+		t.Fatalf("expected 2 events, got %v %v", events, err)
+	}
+	if event, ok := events[1].(a2a.TaskStatusUpdateEvent); !ok || event.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected the events in the order saved, got %#v", events)
+	}
+
+	tasks, err := store.ListTasks(ctx, "ctx")
+	if err != nil || len(tasks) != 2 {
+		t.Errorf("expected both tasks in the context, got %v %v", tasks, err)
+	}
+	if err := store.DeleteTask(ctx, "task-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.GetTask(ctx, "task-1"); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("expected the deleted task to be not found, got %v", err)
+	}
+	// Saving a config again replaces the one of the same ID
+	configID := "second"
+	for _, url := range []string{"https://hooks.example.com/old", "https://hooks.example.com/new"} {
+		if err := store.SavePushConfig(ctx, a2a.TaskPushConfig{TaskID: "task-2", Config: a2a.PushConfig{ID: &configID, URL: url}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	config, err := store.GetPushConfig(ctx, "task-2", configID)
+	if err != nil || config.Config.URL != "https://hooks.example.com/new" {
+		t.Errorf("expected the config replaced, got %+v %v", config, err)
+	}
+	if err := store.DeletePushConfig(ctx, "task-2", configID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.GetPushConfig(ctx, "task-2", configID); !errors.Is(err, a2aTypes.ErrPushConfigNotFound) {
+		t.Errorf("expected the deleted config to be not found, got %v", err)
+	}
+
+	if err := store.SaveTask(ctx, newTask("..", "ctx")); err == nil {
+		t.Error("expected a task ID that is not a file name to be refused")
+	}
+	if err := store.SaveEvent(ctx, a2a.Message{Kind: "message", MessageID: "m"}); err == nil {
+		t.Error("expected an event outside any task to be refused")
+	}
+}
+
+func TestMigrationResumes(t *testing.T) {
+	_, from := newLocalStores(t)
+	ctx := context.Background()
+	tasks := a2atest.NewTaskStore()
+	events := a2atest.NewEventStore()
+	pushConfigs := a2aTypes.NewMemoryPushConfigStore()
+	to := stores{tasks: tasks, events: events, pushConfigs: pushConfigs}
+	cp, err := openCheckpoint(filepath.Join(t.TempDir(), "checkpoint"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cp.Close()
+
+	// The destination fails after the first task's events
+	tasks.SaveErr = errors.New("throttled")
+	var log strings.Builder
+	m := migration{from: from, to: to, checkpoint: cp, log: &log}
+	if _, err := m.run(ctx); err == nil || !strings.Contains(err.Error(), "task task-1: failed to copy task: throttled") {
+		t.Fatalf("expected the failed task to be named, got %v", err)
+	}
+	if cp.Done("task-1") {
+		t.Error("expected the failed task to be left out of the checkpoint")
+	}
+
+	tasks.SaveErr = nil
+	stats, err := m.run(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats != (migrationStats{Tasks: 2, Events: 4, PushConfigs: 1}) {
+		t.Errorf("expected 2 tasks, 4 events and 1 push config, got %+v", stats)
+	}
+	if len(tasks.Tasks()) != 2 || !cp.Done("task-1") || !cp.Done("task-2") {
+		t.Errorf("expected both tasks copied and recorded, got %v", tasks.Tasks())
+	}
+	copied, _ := events.GetEvents(ctx, "task-2")
+	if len(copied) != 2 {
+		t.Errorf("expected the task's events copied, got %v", copied)
+	}
+	config, err := pushConfigs.GetPushConfig(ctx, "task-1", "task-1")
+	if err != nil || config.Config.URL != "https://hooks.example.com/a2a" {
+		t.Errorf("expected the task's push config copied, got %+v %v", config, err)
+	}
+
+	// A later run picks up the checkpoint and copies nothing again
+	cp.Close()
+	cp, err = openCheckpoint(cp.file.Name())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.checkpoint = cp
+	stats, err = m.run(ctx)
+	if err != nil || stats != (migrationStats{Skipped: 2}) {
+		t.Errorf("expected both tasks skipped, got %+v %v", stats, err)
+	}
+}
+
+func TestMigrationDryRun(t *testing.T) {
+	_, from := newLocalStores(t)
+	m := migration{from: from, dryRun: true}
+	stats, err := m.run(context.Background())
+	if err != nil || stats != (migrationStats{Tasks: 2, Events: 4, PushConfigs: 1}) {
+		t.Errorf("expected the counts without writing, got %+v %v", stats, err)
+	}
+}
+
+func TestMigrationNeedsPushConfigStore(t *testing.T) {
+	_, from := newLocalStores(t)
+	tasks := a2atest.NewTaskStore()
+	m := migration{from: from, to: stores{tasks: tasks, events: a2atest.NewEventStore()}}
+	_, err := m.run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "task task-1: the task has push configs and the destination has no push config store") {
+		t.Errorf("expected the configs not to be dropped, got %v", err)
+	}
+	if len(tasks.Tasks()) != 0 {
+		t.Errorf("expected the task left uncopied, got %v", tasks.Tasks())
+	}
+}
+
+func TestProviderFlags(t *testing.T) {
+	f, err := providerFlags{Provider: "aws"}.withDefaults()
+	if err != nil || f.Tasks != "a2a-tasks" || f.Events != "a2a-events" {
+		t.Errorf("expected the Lambda's default tables, got %+v %v", f, err)
+	}
+	if _, err := (providerFlags{Provider: "gcp"}).withDefaults(); err == nil || !strings.Contains(err.Error(), "no gcp stores in this build, only aws and local are migrated") {
+		t.Errorf("expected gcp to be refused, got %v", err)
+	}
+	if _, err := (providerFlags{Provider: "local", Prefix: "agent#"}).withDefaults(); err == nil {
+		t.Error("expected a prefix to be refused for local")
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// progressEvery is how many tasks pass between progress lines
+const progressEvery = 100
+
+// checkpoint records the tasks already copied, one ID per line, so a copy
+// that stops can be resumed where it stopped. A nil checkpoint records
+// nothing.
+type checkpoint struct {
+	done map[a2a.TaskID]bool
+	file *os.File
+}
+
+// openCheckpoint reads the tasks path records, creating it when missing,
+// and opens it for recording more
+func openCheckpoint(path string) (*checkpoint, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint: %w", err)
+	}
+	c := &checkpoint{done: make(map[a2a.TaskID]bool), file: file}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if taskID := strings.TrimSpace(scanner.Text()); taskID != "" {
+			c.done[a2a.TaskID(taskID)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return c, nil
+}
+
+// Done reports whether taskID was copied in an earlier run
+func (c *checkpoint) Done(taskID a2a.TaskID) bool {
+	return c != nil && c.done[taskID]
+}
+
+// Record notes that taskID was copied. The line is synced before returning,
+// so a crash right after still leaves it recorded.
+func (c *checkpoint) Record(taskID a2a.TaskID) error {
+	if c == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(c.file, taskID); err != nil {
+		return fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	if err := c.file.Sync(); err != nil {
+		return fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	c.done[taskID] = true
+	return nil
+}
+
+// Close closes the checkpoint file
+func (c *checkpoint) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.file.Close()
+}
+
+// migration copies every task, its events and its push configs from one
+// provider's stores to another's
+type migration struct {
+	from, to   stores
+	checkpoint *checkpoint
+	// dryRun reads everything that would be copied and writes nothing
+	dryRun bool
+	// log receives progress lines
+	log io.Writer
+}
+
+// migrationStats counts what a migration did
+type migrationStats struct {
+	Tasks       int
+	Events      int
+	PushConfigs int
+	Skipped     int
+}
+
+// run copies the tasks not yet in the checkpoint. A task's events and push
+// configs are written before the task, so a task found at the destination
+// has its whole history and is notified as it was at the source. The first failure stops the run; the tasks it finished are in
+// the checkpoint, and running again redoes only the one that failed.
+func (m migration) run(ctx context.Context) (migrationStats, error) {
+	var stats migrationStats
+	log := m.log
+	if log == nil {
+		log = io.Discard
+	}
+	for taskID, err := range m.from.list.TaskIDs(ctx) {
+		if err != nil {
+			return stats, err
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		if m.checkpoint.Done(taskID) {
+			stats.Skipped++
+			continue
+		}
+		copied, err := m.copyTask(ctx, taskID)
+		if errors.Is(err, a2a.ErrTaskNotFound) {
+			// Deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return stats, fmt.Errorf("task %s: %w", taskID, err)
+		}
+		stats.Tasks++
+		stats.Events += copied.Events
+		stats.PushConfigs += copied.PushConfigs
+		if stats.Tasks%progressEvery == 0 {
+			fmt.Fprintf(log, "%d tasks, %d events copied\n", stats.Tasks, stats.Events)
+		}
+	}
+	return stats, nil
+}
+
+// copyTask copies one task, its events and its push configs, returning how
+// many events and configs it had. A source with configs needs a destination
+// that keeps them; a source without a push config store has none.
+func (m migration) copyTask(ctx context.Context, taskID a2a.TaskID) (migrationStats, error) {
+	task, err := m.from.tasks.GetTask(ctx, taskID)
+	if err != nil {
+		return migrationStats{}, err
+	}
+	events, err := m.from.events.GetEvents(ctx, taskID)
+	if err != nil {
+		return migrationStats{}, fmt.Errorf("failed to read events: %w", err)
+	}
+	var configs []a2a.TaskPushConfig
+	if m.from.pushConfigs != nil {
+		if configs, err = m.from.pushConfigs.ListPushConfigs(ctx, taskID); err != nil {
+			return migrationStats{}, fmt.Errorf("failed to read push configs: %w", err)
+		}
+	}
+	copied := migrationStats{Events: len(events), PushConfigs: len(configs)}
+	if m.dryRun {
+		return copied, nil
+	}
+	if len(configs) > 0 && m.to.pushConfigs == nil {
+		return migrationStats{}, errors.New("the task has push configs and the destination has no push config store")
+	}
+	for _, event := range events {
+		if err := m.to.events.SaveEvent(ctx, event); err != nil {
+			return migrationStats{}, fmt.Errorf("failed to copy event: %w", err)
+		}
+	}
+	for _, config := range configs {
+		if err := m.to.pushConfigs.SavePushConfig(ctx, config); err != nil {
+			return migrationStats{}, fmt.Errorf("failed to copy push config: %w", err)
+		}
+	}
+	if err := m.to.tasks.SaveTask(ctx, task); err != nil {
+		return migrationStats{}, fmt.Errorf("failed to copy task: %w", err)
+	}
+	if err := m.checkpoint.Record(taskID); err != nil {
+		return migrationStats{}, err
+	}
+	return copied, nil
+}
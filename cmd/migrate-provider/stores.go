@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+// taskLister enumerates every task in a store. The stores' interfaces only
+// list the tasks of one context, so each provider lists them its own way.
+type taskLister interface {
+	TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error]
+}
+
+// stores are one provider's task, event and push config stores.
+// pushConfigs is nil for an agent that keeps no push notification configs.
+type stores struct {
+	tasks       a2aTypes.TaskStore
+	events      a2aTypes.EventStore
+	pushConfigs a2aTypes.PushConfigStore
+	list        taskLister
+}
+
+// localStore keeps tasks, events and push configs as files in the local
+// provider's paths: <tasksDir>/<task ID>.json holds a task,
+// <eventsDir>/<task ID>.jsonl its events, one per line in the order saved,
+// and <pushConfigsDir>/<task ID>.json the JSON array of its push
+// notification configs. Saving an event already in the file does nothing,
+// and saving a config replaces the one of the same ID, so a copy that is
+// interrupted can be run again.
+type localStore struct {
+	tasksDir       string
+	eventsDir      string
+	pushConfigsDir string
+
+	mu sync.Mutex
+}
+
+// newLocalStore creates a local store, and its directories when missing
+func newLocalStore(tasksDir, eventsDir, pushConfigsDir string) (*localStore, error) {
+	for _, dir := range []string{tasksDir, eventsDir, pushConfigsDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+	}
+	return &localStore{tasksDir: tasksDir, eventsDir: eventsDir, pushConfigsDir: pushConfigsDir}, nil
+}
+
+// fileName is a task ID made safe to use as a file name
+func fileName(taskID a2a.TaskID) (string, error) {
+	name := url.PathEscape(string(taskID))
+	if name == "" || name == "." || name == ".." {
+		return "", fmt.Errorf("task ID %q cannot be stored locally", taskID)
+	}
+	return name, nil
+}
+
+func (s *localStore) taskPath(taskID a2a.TaskID) (string, error) {
+	name, err := fileName(taskID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.tasksDir, name+".json"), nil
+}
+
+func (s *localStore) eventsPath(taskID a2a.TaskID) (string, error) {
+	name, err := fileName(taskID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.eventsDir, name+".jsonl"), nil
+}
+
+func (s *localStore) pushConfigsPath(taskID a2a.TaskID) (string, error) {
+	name, err := fileName(taskID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.pushConfigsDir, name+".json"), nil
+}
+
+// GetTask implements TaskStore
+func (s *localStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	path, err := s.taskPath(taskID)
+	if err != nil {
+		return a2a.Task{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a2a.Task{}, fmt.Errorf("task %s: %w", taskID, a2a.ErrTaskNotFound)
+	}
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to read task: %w", err)
+	}
+	task, err := a2aTypes.UnmarshalTask(data)
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to unmarshal task %s: %w", taskID, err)
+	}
+	return task, nil
+}
+
+// SaveTask implements TaskStore. The file is replaced whole, so a reader
+// never sees half a task.
+func (s *localStore) SaveTask(ctx context.Context, task a2a.Task) error {
+	path, err := s.taskPath(task.ID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+	return nil
+}
+
+// DeleteTask implements TaskStore
+func (s *localStore) DeleteTask(ctx context.Context, taskID a2a.TaskID) error {
+	path, err := s.taskPath(taskID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// ListTasks implements TaskStore, reading every task to find the context's
+func (s *localStore) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	var tasks []a2a.Task
+	for taskID, err := range s.TaskIDs(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		task, err := s.GetTask(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.ContextID == contextID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// TaskIDs implements taskLister, in file name order
+func (s *localStore) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return func(yield func(a2a.TaskID, error) bool) {
+		entries, err := os.ReadDir(s.tasksDir)
+		if err != nil {
+			yield("", fmt.Errorf("failed to list tasks: %w", err))
+			return
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".json")
+			if entry.IsDir() || !ok {
+				continue
+			}
+			taskID, err := url.PathUnescape(name)
+			if err != nil {
+				continue
+			}
+			if !yield(a2a.TaskID(taskID), nil) {
+				return
+			}
+		}
+	}
+}
+
+// SaveEvent implements EventStore. Events outside any task are refused:
+// the layout has no place for them.
+func (s *localStore) SaveEvent(ctx context.Context, event a2a.Event) error {
+	taskID := a2atest.EventTaskID(event)
+	if taskID == "" {
+		return errors.New("event belongs to no task")
+	}
+	path, err := s.eventsPath(taskID)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(lines, func(saved []byte) bool { return bytes.Equal(saved, line) }) {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open events: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return file.Close()
+}
+
+// GetEvents implements EventStore
+func (s *localStore) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	path, err := s.eventsPath(taskID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	lines, err := readLines(path)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]a2a.Event, 0, len(lines))
+	for i, line := range lines {
+		event, err := a2aTypes.UnmarshalEvent(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// MarkEventProcessed implements EventStore. Nothing processes local events,
+// so there is nothing to mark.
+func (s *localStore) MarkEventProcessed(ctx context.Context, eventID string) error {
+	return nil
+}
+
+// SavePushConfig implements PushConfigStore, replacing the task's config
+// of the same ID
+func (s *localStore) SavePushConfig(ctx context.Context, config a2a.TaskPushConfig) error {
+	path, err := s.pushConfigsPath(config.TaskID)
+	if err != nil {
+		return err
+	}
+	configID := localPushConfigID(config)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs, err := readPushConfigs(path)
+	if err != nil {
+		return err
+	}
+	configs = slices.DeleteFunc(configs, func(saved a2a.PushConfig) bool {
+		return localPushConfigID(a2a.TaskPushConfig{TaskID: config.TaskID, Config: saved}) == configID
+	})
+	return writePushConfigs(path, append(configs, config.Config))
+}
+
+// GetPushConfig implements PushConfigStore
+func (s *localStore) GetPushConfig(ctx context.Context, taskID a2a.TaskID, configID string) (a2a.TaskPushConfig, error) {
+	configs, err := s.ListPushConfigs(ctx, taskID)
+	if err != nil {
+		return a2a.TaskPushConfig{}, err
+	}
+	for _, config := range configs {
+		if localPushConfigID(config) == configID {
+			return config, nil
+		}
+	}
+	return a2a.TaskPushConfig{}, fmt.Errorf("config %s of task %s: %w", configID, taskID, a2aTypes.ErrPushConfigNotFound)
+}
+
+// ListPushConfigs implements PushConfigStore, in the order saved
+func (s *localStore) ListPushConfigs(ctx context.Context, taskID a2a.TaskID) ([]a2a.TaskPushConfig, error) {
+	path, err := s.pushConfigsPath(taskID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	configs, err := readPushConfigs(path)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	taskConfigs := make([]a2a.TaskPushConfig, 0, len(configs))
+	for _, config := range configs {
+		taskConfigs = append(taskConfigs, a2a.TaskPushConfig{TaskID: taskID, Config: config})
+	}
+	return taskConfigs, nil
+}
+
+// DeletePushConfig implements PushConfigStore
+func (s *localStore) DeletePushConfig(ctx context.Context, taskID a2a.TaskID, configID string) error {
+	path, err := s.pushConfigsPath(taskID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs, err := readPushConfigs(path)
+	if err != nil {
+		return err
+	}
+	configs = slices.DeleteFunc(configs, func(saved a2a.PushConfig) bool {
+		return localPushConfigID(a2a.TaskPushConfig{TaskID: taskID, Config: saved}) == configID
+	})
+	return writePushConfigs(path, configs)
+}
+
+// localPushConfigID is the ID of a config, the task's ID for one set
+// without an ID, as the handler stores it
+func localPushConfigID(config a2a.TaskPushConfig) string {
+	if config.Config.ID == nil || *config.Config.ID == "" {
+		return string(config.TaskID)
+	}
+	return *config.Config.ID
+}
+
+// readPushConfigs reads the configs in path, none when it does not exist
+func readPushConfigs(path string) ([]a2a.PushConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read push configs: %w", err)
+	}
+	var configs []a2a.PushConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+	}
+	return configs, nil
+}
+
+// writePushConfigs replaces the configs in path whole, so a reader never
+// sees half of them
+func writePushConfigs(path string, configs []a2a.PushConfig) error {
+	data, err := json.Marshal(configs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push configs: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write push configs: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write push configs: %w", err)
+	}
+	return nil
+}
+
+// readLines reads the non-empty lines of path, none when it does not exist
+func readLines(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	var lines [][]byte
+	for line := range bytes.SplitSeq(data, []byte("\n")) {
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,185 @@
+// Command replay re-invokes the handler with events recorded by cmd/lambda
+// and cmd/worker under A2A_RECORD_EVENTS, for reproducing production bugs
+// locally:
+//
+//	replay [-provider memory|aws] [-webhook-url URL] <file, directory or s3://bucket/prefix>
+//
+// Requests run without authentication, since their credentials were
+// redacted when recorded. Push notification batches are only delivered
+// when -webhook-url points them somewhere other than the recorded URLs.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+func main() {
+	provider := flag.String("provider", "memory", "storage to replay against: memory, or aws with the Lambda's environment")
+	webhookURL := flag.String("webhook-url", "", "deliver recorded push notifications to this URL instead of skipping them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: replay [flags] <file, directory or s3://bucket/prefix>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var logLevel slog.LevelVar
+	a2aTypes.SetLogLevel(&logLevel, os.Getenv("A2A_LOG_LEVEL"))
+	logger := a2aTypes.NewLogger(os.Stderr, &logLevel)
+	slog.SetDefault(logger)
+	ctx := a2aTypes.ContextWithLogger(context.Background(), logger)
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		logger.Error("failed to load AWS config", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+	recorded, err := a2aTypes.LoadRecordedEvents(ctx, flag.Arg(0), s3.NewFromConfig(cfg))
+	if err != nil {
+		logger.Error("failed to load recording", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+
+	var h *handler.Handler
+	switch *provider {
+	case "memory":
+		h = a2atest.NewHandler(a2atest.NewTaskStore(), a2atest.NewEventStore(), nil)
+	case "aws":
+		h, err = newAWSHandler(cfg.Region, dynamodb.NewFromConfig(cfg))
+	default:
+		err = fmt.Errorf("unknown provider %q: expected memory or aws", *provider)
+	}
+	if err != nil {
+		logger.Error("failed to create handler", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+
+	r := &replayer{h: h, webhookURL: *webhookURL, out: os.Stdout}
+	if err := r.replay(ctx, recorded); err != nil {
+		logger.Error("replay failed", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+}
+
+// newAWSHandler serves from the tables in the Lambda's environment config.
+// Push notifications are not queued, so replays never reach real webhooks.
+func newAWSHandler(region string, client *dynamodb.Client) (*handler.Handler, error) {
+	serverlessConfig, err := a2aTypes.LoadLambdaEnvConfig(region)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := a2aTypes.NewConfigLoader().CreateCloudProvider(serverlessConfig.CloudConfig)
+	if err != nil {
+		return nil, err
+	}
+	if provider.GetProviderType() != a2aTypes.CloudProviderAWS {
+		return nil, fmt.Errorf("-provider aws needs CLOUD_PROVIDER=aws, got %s", provider.GetProviderType())
+	}
+	storageConfig := provider.GetStorageConfig()
+	compression := a2aTypes.WithStorageCompression(storageConfig.DynamoDBCompressAbove)
+	taskStore := a2aTypes.NewAWSTaskStore(client, storageConfig.DynamoDBTable, "", compression)
+	eventStore := a2aTypes.NewAWSEventStore(client, storageConfig.DynamoDBEventsTable, "", compression)
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(serverlessConfig, taskStore, eventStore, nil)
+	return handler.NewHandler(a2aHandler, serverlessConfig.AgentCard), nil
+}
+
+// replayer re-invokes the handler or the webhook deliverer with recorded events
+type replayer struct {
+	h          *handler.Handler
+	webhookURL string
+	out        io.Writer
+}
+
+func (r *replayer) replay(ctx context.Context, recorded []a2aTypes.RecordedEvent) error {
+	for _, event := range recorded {
+		eventCtx := a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyRequestID, event.RequestID)
+		var err error
+		switch event.Source {
+		case a2aTypes.RecordedSourceAPIGateway:
+			err = r.replayRequest(eventCtx, event)
+		case a2aTypes.RecordedSourceSQS:
+			err = r.replayBatch(eventCtx, event)
+		default:
+			err = fmt.Errorf("unknown source %q", event.Source)
+		}
+		if err != nil {
+			return fmt.Errorf("event %s recorded at %s: %w", event.RequestID, event.RecordedAt.Format(time.RFC3339), err)
+		}
+	}
+	return nil
+}
+
+// replayRequest runs a recorded API Gateway request through the handler as
+// cmd/lambda does and prints the response
+func (r *replayer) replayRequest(ctx context.Context, event a2aTypes.RecordedEvent) error {
+	var request events.APIGatewayProxyRequest
+	if err := json.Unmarshal(event.Payload, &request); err != nil {
+		return fmt.Errorf("invalid API Gateway request: %w", err)
+	}
+	response := r.h.HandleRequest(ctx, handler.Request{
+		Method:    request.HTTPMethod,
+		URL:       request.Path,
+		Headers:   request.Headers,
+		Body:      request.Body,
+		RequestID: request.RequestContext.RequestID,
+	})
+	fmt.Fprintf(r.out, "%s %s %s -> %d\n%s\n", event.RequestID, request.HTTPMethod, request.Path, response.Status, response.Body)
+	return nil
+}
+
+// replayBatch delivers a recorded SQS batch to webhookURL, or skips it
+func (r *replayer) replayBatch(ctx context.Context, event a2aTypes.RecordedEvent) error {
+	if r.webhookURL == "" {
+		a2aTypes.LoggerFromContext(ctx).Info("skipping push notification batch; set -webhook-url to deliver it")
+		return nil
+	}
+	var batch events.SQSEvent
+	if err := json.Unmarshal(event.Payload, &batch); err != nil {
+		return fmt.Errorf("invalid SQS event: %w", err)
+	}
+	// The operator chose the URL, often a local test server, so the
+	// restrictions on client-supplied webhooks do not apply
+	deliverer := a2aTypes.NewWebhookDeliverer(&http.Client{Timeout: 10 * time.Second}, nil)
+	for _, record := range batch.Records {
+		var notification a2aTypes.PushNotification
+		if err := json.Unmarshal([]byte(record.Body), &notification); err != nil {
+			return fmt.Errorf("invalid notification message %s: %w", record.MessageId, err)
+		}
+		notification.PushConfig.URL = r.webhookURL
+		body, err := json.Marshal(notification)
+		if err != nil {
+			return fmt.Errorf("failed to encode notification %s: %w", record.MessageId, err)
+		}
+		// The recorded signature no longer matches the redacted body
+		err = deliverer.Deliver(ctx, string(body), nil)
+		fmt.Fprintf(r.out, "%s notification -> %s: %v\n", record.MessageId, r.webhookURL, errorOrOK(err))
+	}
+	return nil
+}
+
+func errorOrOK(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-lambda-go/events"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+func TestReplay(t *testing.T) {
+	var delivered []string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = append(delivered, r.URL.Path)
+	}))
+	defer webhook.Close()
+
+	// Record as cmd/lambda and cmd/worker do, then load the recording back
+	dir := t.TempDir()
+	recorder := a2aTypes.NewFileEventRecorder(dir)
+	send := a2atest.SendMessageRequest(a2atest.UserMessage("m-1", "hello"))
+	request, _ := json.Marshal(events.APIGatewayProxyRequest{
+		HTTPMethod:     send.Method,
+		Path:           send.URL,
+		Headers:        a2aTypes.RedactHeaders(send.Headers),
+		Body:           send.Body,
+		RequestContext: events.APIGatewayProxyRequestContext{RequestID: "req-1"},
+	})
+	notification, _ := json.Marshal(a2aTypes.PushNotification{
+		PushConfig: a2a.PushConfig{URL: "https://agent.example.com/hook"},
+		Event:      json.RawMessage(`{}`),
+	})
+	batch, _ := json.Marshal(events.SQSEvent{Records: []events.SQSMessage{{MessageId: "msg-1", Body: string(notification)}}})
+	now := time.Now()
+	recorder.RecordEvent(context.Background(), a2aTypes.RecordedEvent{Source: a2aTypes.RecordedSourceAPIGateway, RecordedAt: now, RequestID: "req-1", Payload: request})
+	recorder.RecordEvent(context.Background(), a2aTypes.RecordedEvent{Source: a2aTypes.RecordedSourceSQS, RecordedAt: now.Add(time.Second), RequestID: "msg-1", Payload: batch})
+	recorded, err := a2aTypes.LoadRecordedEvents(context.Background(), dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		webhookURL      string
+		expectDelivered int
+	}{
+		{name: "notifications skipped", expectDelivered: 0},
+		{name: "notifications redirected", webhookURL: webhook.URL + "/replayed", expectDelivered: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivered = nil
+			tasks := a2atest.NewTaskStore()
+			var out strings.Builder
+			r := &replayer{h: a2atest.NewHandler(tasks, a2atest.NewEventStore(), nil), webhookURL: tt.webhookURL, out: &out}
+			if err := r.replay(context.Background(), recorded); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(out.String(), "req-1 POST / -> 200") || len(tasks.Tasks()) != 1 {
+				t.Errorf("expected the request to create a task, got output %q", out.String())
+			}
+			if len(delivered) != tt.expectDelivered {
+				t.Errorf("expected %d deliveries, got %v", tt.expectDelivered, delivered)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+// inspectorPath is where the inspector UI and its API are served
+const inspectorPath = "/_inspector/"
+
+// logTailSize is how many log lines the inspector keeps
+const logTailSize = 500
+
+//go:embed inspector.html
+var inspectorPage []byte
+
+// inspector serves the UI and the JSON endpoints it polls. Test messages are
+// sent by the page to the agent's own JSON-RPC endpoint.
+type inspector struct {
+	tasks  *a2atest.TaskStore
+	events *a2atest.EventStore
+	logs   *logTail
+}
+
+func newInspector(tasks *a2atest.TaskStore, events *a2atest.EventStore, logs *logTail) *inspector {
+	return &inspector{tasks: tasks, events: events, logs: logs}
+}
+
+// timelineEntry is an event with the kind the SDK types leave unset
+type timelineEntry struct {
+	Kind  string    `json:"kind"`
+	Event a2a.Event `json:"event"`
+}
+
+func (i *inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimPrefix(r.URL.Path, inspectorPath) {
+	case "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(inspectorPage)
+	case "api/tasks":
+		writeJSON(w, i.tasks.Tasks())
+	case "api/events":
+		taskID := a2a.TaskID(r.URL.Query().Get("task_id"))
+		timeline := []timelineEntry{}
+		for _, event := range i.events.Events() {
+			if a2atest.EventTaskID(event) == taskID {
+				timeline = append(timeline, timelineEntry{Kind: a2atest.EventKind(event), Event: event})
+			}
+		}
+		writeJSON(w, timeline)
+	case "api/logs":
+		// Clients pass the last sequence number they saw, so each poll only
+		// returns new lines
+		since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+		writeJSON(w, i.logs.since(since))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// logLine is one log record with its position in the tail
+type logLine struct {
+	Seq  int64  `json:"seq"`
+	Line string `json:"line"`
+}
+
+// logTail keeps the most recent log lines for the inspector. The JSON
+// handler writes one record per Write call.
+type logTail struct {
+	mu    sync.Mutex
+	size  int
+	seq   int64
+	lines []logLine
+}
+
+func newLogTail(size int) *logTail {
+	return &logTail{size: size}
+}
+
+// Write implements io.Writer
+func (t *logTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	t.lines = append(t.lines, logLine{Seq: t.seq, Line: strings.TrimRight(string(p), "\n")})
+	if len(t.lines) > t.size {
+		t.lines = t.lines[len(t.lines)-t.size:]
+	}
+	return len(p), nil
+}
+
+// since returns the kept lines after seq
+func (t *logTail) since(seq int64) []logLine {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lines := []logLine{}
+	for _, line := range t.lines {
+		if line.Seq > seq {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
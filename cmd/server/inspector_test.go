@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+	serverless "github.com/a2aproject/a2a-serverless/pkg/handler"
+)
+
+func TestInspector(t *testing.T) {
+	logs := newLogTail(logTailSize)
+	logger := slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	tasks := a2atest.NewTaskStore()
+	events := a2atest.NewEventStore()
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, events, nil)
+	h := handler.NewHandler(a2aHandler, agentcard.New("Local Agent", "http://localhost"))
+
+	mux := http.NewServeMux()
+	mux.Handle("/", serverless.NewHTTPHandler(h, logger))
+	mux.Handle(inspectorPath, newInspector(tasks, events, logs))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// A test message sent as the page sends it
+	body := `{"jsonrpc":"2.0","id":1,"method":"message/send","params":{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]}}}`
+	resp, err := http.Post(server.URL+"/", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	task := tasks.Tasks()[0]
+	a2aHandler.OnCancelTask(context.Background(), a2a.TaskIDParams{ID: task.ID})
+
+	get := func(path string, v any) {
+		t.Helper()
+		resp, err := http.Get(server.URL + inspectorPath + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if v == nil {
+			data, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(data), "A2A Inspector") {
+				t.Errorf("expected the inspector page, got %d", resp.StatusCode)
+			}
+			return
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", path, err)
+		}
+	}
+
+	get("", nil)
+
+	var listed []map[string]any
+	get("api/tasks", &listed)
+	if len(listed) != 1 || listed[0]["ID"] != string(task.ID) {
+		t.Errorf("expected task %s, got %v", task.ID, listed)
+	}
+
+	var timeline []struct {
+		Kind  string
+		Event map[string]any
+	}
+	get(fmt.Sprintf("api/events?task_id=%s", task.ID), &timeline)
+	if len(timeline) != 1 || timeline[0].Kind != "status-update" {
+		t.Errorf("expected a status update, got %+v", timeline)
+	}
+
+	var lines []logLine
+	get("api/logs?since=0", &lines)
+	if len(lines) == 0 || !strings.Contains(lines[0].Line, "local-1") {
+		t.Fatalf("expected request logs with the local request ID, got %+v", lines)
+	}
+	var newer []logLine
+	get(fmt.Sprintf("api/logs?since=%d", lines[len(lines)-1].Seq), &newer)
+	if len(newer) != 0 {
+		t.Errorf("expected no lines after the last seen, got %+v", newer)
+	}
+}
+
+func TestLogTailKeepsRecentLines(t *testing.T) {
+	logs := newLogTail(2)
+	for i := range 3 {
+		fmt.Fprintf(logs, "line %d\n", i)
+	}
+	lines := logs.since(0)
+	if len(lines) != 2 || lines[0].Line != "line 1" || lines[1].Seq != 3 {
+		t.Errorf("expected the last 2 lines, got %+v", lines)
+	}
+}
@@ -0,0 +1,123 @@
+// Command server runs an agent with in-memory storage, for developing agents
+// locally without AWS. With -inspect it also serves a web UI at /_inspector/
+// for browsing tasks, their events and the logs, and with -openapi an
+// OpenAPI document of its HTTP surface at /openapi.json. It serves plain HTTP unless
+// given a certificate (-tls-cert and -tls-key) or ACME domains, and with
+// -tls-client-ca requires clients to present a certificate.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+	serverless "github.com/a2aproject/a2a-serverless/pkg/handler"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	name := flag.String("name", "Local Agent", "agent name on the card")
+	inspect := flag.Bool("inspect", false, "serve the inspector UI at /_inspector/")
+	openAPI := flag.Bool("openapi", false, "serve an OpenAPI document of the agent's endpoints at "+handler.OpenAPIPath)
+	var tlsOpts tlsOptions
+	flag.StringVar(&tlsOpts.CertFile, "tls-cert", "", "PEM certificate chain to serve HTTPS with")
+	flag.StringVar(&tlsOpts.KeyFile, "tls-key", "", "PEM private key of -tls-cert")
+	acmeDomains := flag.String("acme-domains", "", "comma-separated domains to obtain certificates for from Let's Encrypt")
+	flag.StringVar(&tlsOpts.ACMECache, "acme-cache", "acme-cache", "directory ACME certificates are kept in")
+	flag.StringVar(&tlsOpts.ACMEEmail, "acme-email", "", "contact email for the ACME account")
+	flag.StringVar(&tlsOpts.ClientCAFile, "tls-client-ca", "", "PEM bundle of CAs client certificates must be issued by")
+	flag.Parse()
+	tlsOpts.ACMEDomains = strings.FieldsFunc(*acmeDomains, func(r rune) bool { return r == ',' || r == ' ' })
+
+	var logLevel slog.LevelVar
+	a2aTypes.SetLogLevel(&logLevel, os.Getenv("A2A_LOG_LEVEL"))
+	logs := newLogTail(logTailSize)
+	logger := a2aTypes.NewLogger(io.MultiWriter(os.Stderr, logs), &logLevel)
+	slog.SetDefault(logger)
+
+	tlsConfig, err := tlsOpts.config()
+	if err != nil {
+		logger.Error("invalid TLS options", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		logger.Error("failed to listen", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+	baseURL := "http://" + listener.Addr().String()
+	if tlsConfig != nil {
+		baseURL = "https://" + listener.Addr().String()
+		// The card must name the host certificates are issued for
+		if len(tlsOpts.ACMEDomains) > 0 {
+			baseURL = "https://" + tlsOpts.ACMEDomains[0]
+		}
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	tasks := a2atest.NewTaskStore()
+	events := a2atest.NewEventStore()
+	retention := a2aTypes.LoadRetentionConfigFromEnv()
+	if err := a2aTypes.ValidateRetentionConfig(retention); err != nil {
+		logger.Error("invalid retention", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+	// The in-memory stores have no TTL, so a sweeper enforces retention
+	if policy := retention.Policy(); policy.Enabled() {
+		sweeper := a2aTypes.NewSweeper(policy, []a2aTypes.ExpiringStore{tasks, events})
+		go sweeper.Run(a2aTypes.ContextWithLogger(context.Background(), logger), a2aTypes.DefaultSweepInterval)
+	}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, events, nil)
+	// The adapter streams SSE as it is written and the event store keeps
+	// every status update; nothing sends push notifications
+	features := a2aTypes.DeploymentFeatures{Streaming: true, StateTransitionHistory: true}
+	h := handler.NewHandler(a2aHandler, agentcard.New(*name, baseURL, a2aTypes.WithDeploymentCapabilities(features)))
+
+	mux := http.NewServeMux()
+	mux.Handle("/", serverless.NewHTTPHandler(h, logger))
+	if *inspect {
+		mux.Handle(inspectorPath, newInspector(tasks, events, logs))
+		logger.Info("inspector enabled", "url", baseURL+inspectorPath)
+	}
+	if *openAPI {
+		mux.Handle(handler.OpenAPIPath, openAPIHandler(h))
+		logger.Info("OpenAPI document enabled", "url", baseURL+handler.OpenAPIPath)
+	}
+
+	logger.Info("serving agent", "url", baseURL)
+	if err := http.Serve(listener, mux); err != nil {
+		logger.Error("server stopped", a2aTypes.LogKeyError, err)
+		os.Exit(1)
+	}
+}
+
+// openAPIHandler serves h's OpenAPI document, built per request so it
+// follows the card being served
+func openAPIHandler(h *handler.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := json.MarshalIndent(h.OpenAPI(), "", "  ")
+		if err != nil {
+			http.Error(w, "failed to serialize OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Write(body)
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsOptions are the flags that put the server behind HTTPS, which A2A
+// requires of any agent reachable beyond localhost
+type tlsOptions struct {
+	// CertFile and KeyFile are a PEM certificate chain and its private key
+	CertFile string
+	KeyFile  string
+	// ACMEDomains, instead of a certificate, obtains one from Let's Encrypt
+	// for each domain. ACME answers the TLS-ALPN challenge, so the server
+	// must be reachable on port 443 under those names.
+	ACMEDomains []string
+	ACMECache   string
+	ACMEEmail   string
+	// ClientCAFile is a PEM bundle of CAs. When set, clients must present a
+	// certificate one of them issued.
+	ClientCAFile string
+}
+
+// enabled reports whether any TLS option is set
+func (o tlsOptions) enabled() bool {
+	return o.CertFile != "" || o.KeyFile != "" || len(o.ACMEDomains) > 0 || o.ClientCAFile != ""
+}
+
+// config builds the server TLS config, or nil when TLS is not enabled
+func (o tlsOptions) config() (*tls.Config, error) {
+	if !o.enabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	switch {
+	case len(o.ACMEDomains) > 0:
+		if o.CertFile != "" || o.KeyFile != "" {
+			return nil, errors.New("-acme-domains cannot be combined with -tls-cert and -tls-key")
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(o.ACMEDomains...),
+			Cache:      autocert.DirCache(o.ACMECache),
+			Email:      o.ACMEEmail,
+		}
+		config.GetCertificate = manager.GetCertificate
+		config.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+	case o.CertFile != "" && o.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	case o.CertFile != "" || o.KeyFile != "":
+		return nil, errors.New("-tls-cert and -tls-key must be set together")
+	default:
+		return nil, errors.New("-tls-client-ca requires -tls-cert and -tls-key or -acme-domains")
+	}
+
+	if o.ClientCAFile != "" {
+		pem, err := os.ReadFile(o.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s holds no PEM certificates", o.ClientCAFile)
+		}
+		// The ACME challenge connection carries no client certificate
+		challenge := config.Clone()
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		if len(o.ACMEDomains) > 0 {
+			config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+				if slices.Contains(hello.SupportedProtos, acme.ALPNProto) {
+					return challenge, nil
+				}
+				return nil, nil
+			}
+		}
+	}
+	return config, nil
+}
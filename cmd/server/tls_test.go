@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and key, written as PEM files
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// newTestCert issues a certificate for 127.0.0.1, self-signed when parent is nil
+func newTestCert(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	c := &testCert{cert: cert, key: key, certFile: filepath.Join(dir, "cert.pem"), keyFile: filepath.Join(dir, "key.pem")}
+	os.WriteFile(c.certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(c.keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return c
+}
+
+func TestTLSOptionsConfig(t *testing.T) {
+	server := newTestCert(t, "server", nil, true)
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("no certificates"), 0o600)
+
+	tests := []struct {
+		name        string
+		options     tlsOptions
+		expectNil   bool
+		expectError string
+	}{
+		{name: "plain HTTP", expectNil: true},
+		{name: "certificate files", options: tlsOptions{CertFile: server.certFile, KeyFile: server.keyFile}},
+		{name: "mutual TLS", options: tlsOptions{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: server.certFile}},
+		{name: "ACME", options: tlsOptions{ACMEDomains: []string{"agent.example.com"}, ACMECache: t.TempDir()}},
+		{name: "certificate without key", options: tlsOptions{CertFile: server.certFile}, expectError: "must be set together"},
+		{name: "ACME and certificate", options: tlsOptions{CertFile: server.certFile, KeyFile: server.keyFile, ACMEDomains: []string{"agent.example.com"}}, expectError: "cannot be combined"},
+		{name: "client CA without a certificate", options: tlsOptions{ClientCAFile: server.certFile}, expectError: "-tls-client-ca requires"},
+		{name: "unreadable certificate", options: tlsOptions{CertFile: empty, KeyFile: empty}, expectError: "failed to load TLS certificate"},
+		{name: "client CA without certificates", options: tlsOptions{CertFile: server.certFile, KeyFile: server.keyFile, ClientCAFile: empty}, expectError: "holds no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.options.config()
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (config == nil) != tt.expectNil {
+				t.Errorf("expected nil config %v, got %+v", tt.expectNil, config)
+			}
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCert(t, "client CA", nil, true)
+	client := newTestCert(t, "client", ca, false)
+	stranger := newTestCert(t, "stranger", nil, false)
+	serverCert := newTestCert(t, "server", nil, true)
+
+	config, err := tlsOptions{CertFile: serverCert.certFile, KeyFile: serverCert.keyFile, ClientCAFile: ca.certFile}.config()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = config
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert.cert)
+	get := func(cert *testCert) error {
+		clientConfig := &tls.Config{RootCAs: roots}
+		if cert != nil {
+			clientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{cert.cert.Raw}, PrivateKey: cert.key}}
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig}}
+		resp, err := httpClient.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(client); err != nil {
+		t.Errorf("expected a client certificate from the CA to be accepted, got %v", err)
+	}
+	if err := get(nil); err == nil {
+		t.Error("expected a client without a certificate to be refused")
+	}
+	if err := get(stranger); err == nil {
+		t.Error("expected a certificate from another CA to be refused")
+	}
+}
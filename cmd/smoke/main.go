@@ -0,0 +1,118 @@
+// Command smoke probes a deployed agent end to end, for gating a deploy on
+// the agent actually working:
+//
+//	smoke -url https://agent.example.com -api-key "$KEY"
+//
+// It fetches the agent card, sends a message, polls the task until it
+// reaches -until, reads the task's stored events back with tasks/resubscribe
+// and, with -webhook-url, waits for the push notification the message asked
+// for. Each check prints one line, and any failure exits non-zero.
+//
+// The webhook check serves -webhook-listen and asks for notifications at
+// -webhook-url, which must reach that address from the deployment (a tunnel
+// or a load balancer in front of the CI runner) and be allowed by the
+// agent's webhook allowlist.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+func main() {
+	agentURL := flag.String("url", os.Getenv("A2A_SMOKE_URL"), "Agent base URL (default $A2A_SMOKE_URL)")
+	apiKey := flag.String("api-key", os.Getenv("A2A_SMOKE_API_KEY"), "API key to send (default $A2A_SMOKE_API_KEY)")
+	apiKeyHeader := flag.String("api-key-header", "", "Header for the API key; defaults to the one the card names")
+	sigV4 := flag.Bool("sigv4", false, "Sign calls with AWS SigV4 using the default AWS credentials")
+	sigV4Service := flag.String("sigv4-service", client.DefaultSigV4Service, `SigV4 signing name, "lambda" for function URLs`)
+	text := flag.String("message", "smoke test", "Text of the message sent to the agent")
+	until := flag.String("until", string(a2a.TaskStateCompleted), "Comma-separated task states that pass the task check")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Timeout for the whole probe")
+	interval := flag.Duration("poll", 2*time.Second, "Interval between polls of the task")
+	webhookURL := flag.String("webhook-url", "", "Public URL of -webhook-listen to ask for push notifications at; empty skips the webhook check")
+	webhookListen := flag.String("webhook-listen", ":8089", "Address to receive push notifications on")
+	flag.Parse()
+	if *agentURL == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var credentials []client.Credentials
+	if *apiKey != "" {
+		credentials = append(credentials, client.APIKeyCredentials{Key: *apiKey, Header: *apiKeyHeader})
+	}
+	if *sigV4 {
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load AWS config: %v\n", err)
+			os.Exit(2)
+		}
+		credentials = append(credentials, client.NewSigV4Credentials(cfg, *sigV4Service))
+	}
+
+	p := &probe{
+		agentURL:   *agentURL,
+		clientOpts: []client.Option{client.WithCredentials(credentials...)},
+		text:       *text,
+		until:      parseStates(*until),
+		interval:   *interval,
+		webhookURL: *webhookURL,
+	}
+	if *webhookURL != "" {
+		p.webhook = newWebhookReceiver()
+		listener, err := net.Listen("tcp", *webhookListen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to listen for webhooks: %v\n", err)
+			os.Exit(2)
+		}
+		server := &http.Server{Handler: p.webhook, ReadHeaderTimeout: 10 * time.Second}
+		go server.Serve(listener)
+		defer server.Close()
+	}
+
+	if !report(os.Stdout, p.run(ctx)) {
+		os.Exit(1)
+	}
+}
+
+// parseStates splits a comma-separated list of task states
+func parseStates(list string) []a2a.TaskState {
+	var states []a2a.TaskState
+	for state := range strings.SplitSeq(list, ",") {
+		if state = strings.TrimSpace(state); state != "" {
+			states = append(states, a2a.TaskState(state))
+		}
+	}
+	return states
+}
+
+// report prints one line per check and reports whether none failed
+func report(out io.Writer, results []result) bool {
+	passed := true
+	for _, r := range results {
+		duration := ""
+		if r.Status != statusSkip {
+			duration = r.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(out, "%-4s  %-7s  %8s  %s\n", r.Status, r.Check, duration, r.Detail)
+		if r.Status == statusFail {
+			passed = false
+		}
+	}
+	return passed
+}
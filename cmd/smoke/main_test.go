@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+// newProbe returns a probe of an in-process agent running executor, or
+// leaving tasks working without one
+func newProbe(t *testing.T, executor *a2atest.Executor, opts ...agentcard.Option) *probe {
+	var h *handler.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers := make(map[string]string)
+		for name := range r.Header {
+			headers[strings.ToLower(name)] = r.Header.Get(name)
+		}
+		resp := h.HandleRequest(r.Context(), handler.Request{Method: r.Method, URL: r.URL.Path, Headers: headers, Body: string(body)})
+		for name, value := range resp.Headers {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(resp.Status)
+		io.WriteString(w, resp.Body)
+	}))
+	t.Cleanup(server.Close)
+
+	var runtimeOpts []a2aTypes.RuntimeOption
+	if executor != nil {
+		runtimeOpts = append(runtimeOpts, a2aTypes.WithExecutor(executor))
+	}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil, runtimeOpts...)
+	h = handler.NewHandler(a2aHandler, agentcard.New("Agent", server.URL, opts...))
+
+	return &probe{
+		agentURL:   server.URL,
+		clientOpts: []client.Option{client.WithHTTPClient(server.Client()), client.WithCardCache(client.NewCardCache(0))},
+		text:       "smoke test",
+		until:      []a2a.TaskState{a2a.TaskStateCompleted},
+		interval:   10 * time.Millisecond,
+	}
+}
+
+// statuses maps each check to its outcome
+func statuses(results []result) map[string]string {
+	outcomes := make(map[string]string)
+	for _, r := range results {
+		outcomes[r.Check] = r.Status
+	}
+	return outcomes
+}
+
+func TestProbePasses(t *testing.T) {
+	p := newProbe(t, a2atest.Respond("done"), agentcard.WithStreaming(true))
+	results := p.run(context.Background())
+	expected := map[string]string{"card": statusPass, "send": statusPass, "task": statusPass, "events": statusPass, "webhook": statusSkip}
+	for check, status := range expected {
+		if statuses(results)[check] != status {
+			t.Errorf("expected %s to be %s, got %+v", check, status, results)
+		}
+	}
+
+	var out bytes.Buffer
+	if !report(&out, results) || !strings.Contains(out.String(), "PASS  events") {
+		t.Errorf("expected a passing report, got\n%s", out.String())
+	}
+}
+
+func TestProbeFails(t *testing.T) {
+	// Without an executor the task stays working
+	p := newProbe(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	results := p.run(ctx)
+	if outcomes := statuses(results); outcomes["task"] != statusFail || outcomes["events"] != statusSkip {
+		t.Errorf("expected the task check to fail and events to be skipped without streaming, got %+v", results)
+	}
+	if !strings.Contains(results[2].Detail, "still working") {
+		t.Errorf("expected the last state in the failure, got %q", results[2].Detail)
+	}
+	if report(io.Discard, results) {
+		t.Error("expected the report to fail")
+	}
+
+	// A task ending in another state fails at once
+	p = newProbe(t, &a2atest.Executor{Events: []a2a.Event{
+		a2a.TaskStatusUpdateEvent{Kind: "status-update", Status: a2a.TaskStatus{State: a2a.TaskStateFailed}, Final: true},
+	}})
+	results = p.run(context.Background())
+	if results[2].Status != statusFail || !strings.Contains(results[2].Detail, "ended failed") {
+		t.Errorf("expected the failed task to be reported, got %+v", results[2])
+	}
+
+	p = newProbe(t, nil)
+	p.agentURL += "/missing"
+	results = p.run(context.Background())
+	if outcomes := statuses(results); outcomes["card"] != statusFail || outcomes["send"] != statusSkip {
+		t.Errorf("expected the later checks to be skipped without a card, got %+v", results)
+	}
+}
+
+func TestWebhookReceiver(t *testing.T) {
+	receiver := newWebhookReceiver()
+	post := func(token string, taskID a2a.TaskID) int {
+		body := `{"push_config":{"URL":"https://ci.example.com"},"event":{"Kind":"status-update","TaskID":"` + string(taskID) + `","Status":{"State":"working"}}}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(a2aTypes.NotificationTokenHeader, token)
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("wrong", "task-1"); code != http.StatusUnauthorized {
+		t.Errorf("expected a notification with another token to be refused, got %d", code)
+	}
+	if code := post(receiver.token, "other-task"); code != http.StatusOK {
+		t.Errorf("expected the notification to be accepted, got %d", code)
+	}
+	if code := post(receiver.token, "task-1"); code != http.StatusOK {
+		t.Errorf("expected the notification to be accepted, got %d", code)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := receiver.wait(ctx, "task-1"); err != nil {
+		t.Errorf("expected the task's notification, got %v", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := receiver.wait(ctx, "task-2"); err == nil {
+		t.Error("expected a timeout without a notification")
+	}
+}
+
+func TestParseStates(t *testing.T) {
+	states := parseStates("completed, input-required,")
+	if len(states) != 2 || states[1] != a2a.TaskStateInputRequired {
+		t.Errorf("unexpected states %v", states)
+	}
+}
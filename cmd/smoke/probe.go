@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/google/uuid"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/client"
+)
+
+// Check outcomes
+const (
+	statusPass = "PASS"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+)
+
+// result is the outcome of one check
+type result struct {
+	Check    string
+	Status   string
+	Detail   string
+	Duration time.Duration
+}
+
+// probe runs the smoke test's checks against one deployed agent
+type probe struct {
+	agentURL   string
+	clientOpts []client.Option
+	// text is the message sent to the agent
+	text string
+	// until are the task states that pass the task check
+	until []a2a.TaskState
+	// interval is the time between polls of the task
+	interval time.Duration
+	// webhook receives the notifications sent to webhookURL; nil skips the
+	// webhook check
+	webhook    *webhookReceiver
+	webhookURL string
+}
+
+// run runs the checks in order. A check that fails skips those that need
+// its result, and the run stops when ctx ends.
+func (p *probe) run(ctx context.Context) []result {
+	var results []result
+	record := func(check string, start time.Time, detail string, err error) bool {
+		r := result{Check: check, Status: statusPass, Detail: detail, Duration: time.Since(start)}
+		if err != nil {
+			r.Status, r.Detail = statusFail, err.Error()
+		}
+		results = append(results, r)
+		return err == nil
+	}
+	skip := func(reason string, checks ...string) {
+		for _, check := range checks {
+			results = append(results, result{Check: check, Status: statusSkip, Detail: reason})
+		}
+	}
+
+	start := time.Now()
+	c, err := client.Discover(ctx, p.agentURL, p.clientOpts...)
+	if !record("card", start, describeCard(c), err) {
+		skip("no agent card", "send", "task", "events", "webhook")
+		return results
+	}
+
+	start = time.Now()
+	task, detail, err := p.send(ctx, c)
+	if !record("send", start, detail, err) {
+		skip("message not sent", "task", "events", "webhook")
+		return results
+	}
+	if task == nil {
+		skip("the agent replied without a task", "task", "events", "webhook")
+		return results
+	}
+
+	start = time.Now()
+	detail, err = p.waitForTask(ctx, c, task.ID)
+	record("task", start, detail, err)
+
+	if c.Card().Capabilities.Streaming == nil || !*c.Card().Capabilities.Streaming {
+		skip("the card does not advertise streaming, which tasks/resubscribe needs", "events")
+	} else {
+		start = time.Now()
+		detail, err = checkEvents(ctx, c, task.ID)
+		record("events", start, detail, err)
+	}
+
+	if p.webhook == nil {
+		skip("no -webhook-url given", "webhook")
+	} else {
+		start = time.Now()
+		detail, err = p.webhook.wait(ctx, task.ID)
+		record("webhook", start, detail, err)
+	}
+	return results
+}
+
+// describeCard names the agent a card describes
+func describeCard(c *client.Client) string {
+	if c == nil {
+		return ""
+	}
+	card := c.Card()
+	return fmt.Sprintf("%s %s at %s", card.Name, card.Version, card.URL)
+}
+
+// send sends the smoke test message, asking for notifications at the
+// webhook when there is one. The task is nil when the agent answered with a
+// message instead.
+func (p *probe) send(ctx context.Context, c *client.Client) (*a2a.Task, string, error) {
+	params := a2a.MessageSendParams{Message: a2a.Message{
+		Kind:      "message",
+		MessageID: uuid.NewString(),
+		Role:      a2a.MessageRoleUser,
+		Parts:     []a2a.Part{a2a.TextPart{Kind: "text", Text: p.text}},
+	}}
+	if p.webhook != nil {
+		params.Config = &a2a.MessageSendConfig{PushConfig: &a2a.PushConfig{URL: p.webhookURL, Token: &p.webhook.token}}
+	}
+	result, err := c.SendMessage(ctx, params)
+	if err != nil {
+		return nil, "", err
+	}
+	if task, ok := result.(a2a.Task); ok {
+		return &task, fmt.Sprintf("task %s is %s", task.ID, task.Status.State), nil
+	}
+	return nil, "the agent replied with a message", nil
+}
+
+// waitForTask polls the task until it reaches one of the states the probe
+// waits for. A task ending in any other terminal state fails at once.
+func (p *probe) waitForTask(ctx context.Context, c *client.Client, taskID a2a.TaskID) (string, error) {
+	historyLength := 0
+	var state a2a.TaskState
+	for {
+		task, err := c.GetTask(ctx, a2a.TaskQueryParams{ID: taskID, HistoryLength: &historyLength})
+		// A deadline passing mid-poll still reports the last state seen
+		if err != nil && state != "" && ctx.Err() != nil {
+			return "", fmt.Errorf("task %s still %s: %w", taskID, state, ctx.Err())
+		}
+		if err != nil {
+			return "", err
+		}
+		state = task.Status.State
+		if slices.Contains(p.until, state) {
+			return fmt.Sprintf("task %s is %s", taskID, state), nil
+		}
+		if terminal(state) {
+			return "", fmt.Errorf("task %s ended %s", taskID, state)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("task %s still %s: %w", taskID, state, ctx.Err())
+		case <-time.After(p.interval):
+		}
+	}
+}
+
+// terminal reports whether a task in state will not change again
+func terminal(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// checkEvents reads the task's stored events back with tasks/resubscribe,
+// which serves them from the event store
+func checkEvents(ctx context.Context, c *client.Client, taskID a2a.TaskID) (string, error) {
+	count := 0
+	for _, err := range c.ResubscribeTask(ctx, a2a.TaskIDParams{ID: taskID}) {
+		if err != nil {
+			return "", err
+		}
+		count++
+	}
+	if count == 0 {
+		return "", fmt.Errorf("no events stored for task %s", taskID)
+	}
+	return fmt.Sprintf("%d event(s) stored", count), nil
+}
+
+// webhookReceiver accepts the push notifications of one smoke test run,
+// recognized by the token its push config sets
+type webhookReceiver struct {
+	token  string
+	events chan a2a.Event
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	return &webhookReceiver{token: uuid.NewString(), events: make(chan a2a.Event, 16)}
+}
+
+// ServeHTTP accepts a notification as the webhook deliverer posts it
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Header.Get(a2aTypes.NotificationTokenHeader) != r.token {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var notification a2aTypes.PushNotification
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20)).Decode(&notification); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	event, err := a2aTypes.UnmarshalEvent(notification.Event)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		// The check only needs the first
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// wait waits for a notification about the task
+func (r *webhookReceiver) wait(ctx context.Context, taskID a2a.TaskID) (string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return "", errors.New("no notification received before the timeout")
+		case event := <-r.events:
+			if a2atest.EventTaskID(event) == taskID {
+				return "notification received", nil
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+var (
+	logger    *slog.Logger
+	logLevel  slog.LevelVar
+	logFilter = a2aTypes.NewLogFilter()
+	tracing   *a2aTypes.Tracing
+	deliverer *a2aTypes.WebhookDeliverer
+	processor *a2aTypes.BatchProcessor
+	recorder  a2aTypes.EventRecorder
+)
+
+func init() {
+	a2aTypes.SetLogLevel(&logLevel, os.Getenv("A2A_LOG_LEVEL"))
+	logger = a2aTypes.NewFilteredLogger(os.Stdout, &logLevel, logFilter)
+	slog.SetDefault(logger)
+
+	loggingConfig, err := a2aTypes.LoadLoggingConfigFromEnv()
+	if err == nil {
+		err = a2aTypes.ValidateLoggingConfig(loggingConfig)
+	}
+	if err != nil {
+		fatal("Failed to load logging config", err)
+	}
+	logFilter.Set(loggingConfig)
+
+	tracingConfig, err := a2aTypes.LoadTracingConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load tracing config", err)
+	}
+	tracing, err = a2aTypes.SetupTracing(context.TODO(), tracingConfig)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
+	webhookConfig, err := a2aTypes.LoadWebhookConfigFromEnv()
+	if err == nil {
+		err = a2aTypes.ValidateWebhookConfig(webhookConfig)
+	}
+	if err != nil {
+		fatal("Failed to load webhook config", err)
+	}
+	deliverer = a2aTypes.NewWebhookDeliverer(a2aTypes.NewWebhookClient(webhookConfig), tracing)
+
+	workerConfig, err := a2aTypes.LoadWorkerConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load worker config", err)
+	}
+
+	deliveryConfig, err := a2aTypes.LoadDeliveryConfigFromEnv()
+	if err != nil {
+		fatal("Failed to load delivery config", err)
+	}
+
+	// AWS is only needed for a shared ledger, the delivery log or to record
+	// to an s3:// location
+	var ledger a2aTypes.MessageLedger = a2aTypes.NewMemoryMessageLedger()
+	var recordingS3 a2aTypes.S3RecordingAPI
+	if recordToS3 := strings.HasPrefix(os.Getenv("A2A_RECORD_EVENTS"), "s3://"); recordToS3 || workerConfig.IdempotencyTable != "" || deliveryConfig.Table != "" {
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			fatal("Failed to load AWS config", err)
+		}
+		awsClients := a2aTypes.NewAWSClients(cfg)
+		if workerConfig.IdempotencyTable != "" {
+			ledger = a2aTypes.NewAWSMessageLedger(awsClients.DynamoDB(), workerConfig.IdempotencyTable)
+		}
+		if deliveryConfig.Table != "" {
+			deliverer.WithDeliveryLog(a2aTypes.NewAWSDeliveryLog(awsClients.DynamoDB(), deliveryConfig.Table, deliveryConfig.TTL))
+		}
+		if recordToS3 {
+			recordingS3 = awsClients.S3()
+		}
+	}
+	processor = a2aTypes.NewBatchProcessor(deliver, workerConfig.Concurrency, ledger)
+
+	recorder, err = a2aTypes.LoadEventRecorderFromEnv(recordingS3)
+	if err != nil {
+		fatal("Failed to set up event recording", err)
+	}
+}
+
+// fatal logs a startup failure and exits so Lambda reports the init error
+func fatal(msg string, err error) {
+	logger.Error(msg, a2aTypes.LogKeyError, err)
+	os.Exit(1)
+}
+
+// handleSQS delivers each queued push notification to its webhook. Records
+// are delivered concurrently, and only those that fail are reported back, so
+// SQS redelivers them rather than the whole batch. The event source mapping
+// must enable ReportBatchItemFailures.
+func handleSQS(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	ctx = a2aTypes.ContextWithLogger(ctx, logger)
+	if tracing != nil {
+		defer func() {
+			if err := tracing.Flush(ctx); err != nil {
+				a2aTypes.LoggerFromContext(ctx).Warn("failed to flush traces", a2aTypes.LogKeyError, err)
+			}
+		}()
+	}
+
+	if recorder != nil {
+		recordBatch(ctx, event)
+	}
+
+	records := make([]a2aTypes.BatchRecord, len(event.Records))
+	for i, record := range event.Records {
+		receiveCount, _ := strconv.Atoi(record.Attributes["ApproximateReceiveCount"])
+		headers := messageHeaders(record)
+		// A replayed message already carries the ID it was first sent as
+		if headers[a2aTypes.NotificationIDHeader] == "" {
+			headers[a2aTypes.NotificationIDHeader] = record.MessageId
+		}
+		records[i] = a2aTypes.BatchRecord{MessageID: record.MessageId, Body: record.Body, Headers: headers, ReceiveCount: receiveCount}
+	}
+	var response events.SQSEventResponse
+	for _, messageID := range processor.Process(ctx, records) {
+		response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: messageID})
+	}
+	return response, nil
+}
+
+// deliver posts one queued notification to its webhook
+func deliver(ctx context.Context, record a2aTypes.BatchRecord) error {
+	ctx = a2aTypes.ContextWithDeliveryAttempt(ctx, record.ReceiveCount)
+	return deliverer.Deliver(ctx, record.Body, record.Headers)
+}
+
+// messageHeaders turns the string attributes set by NotificationHeaders back into headers
+func messageHeaders(record events.SQSMessage) map[string]string {
+	headers := make(map[string]string, len(record.MessageAttributes))
+	for name, attribute := range record.MessageAttributes {
+		if attribute.StringValue != nil {
+			headers[name] = *attribute.StringValue
+		}
+	}
+	return headers
+}
+
+// recordBatch saves the batch with push config credentials redacted.
+// Recording is best effort and never fails the batch.
+func recordBatch(ctx context.Context, event events.SQSEvent) {
+	records := make([]events.SQSMessage, len(event.Records))
+	for i, record := range event.Records {
+		record.Body = a2aTypes.RedactNotificationBody(record.Body)
+		records[i] = record
+	}
+	event.Records = records
+	payload, err := json.Marshal(event)
+	if err == nil {
+		var requestID string
+		if len(records) > 0 {
+			requestID = records[0].MessageId
+		}
+		err = recorder.RecordEvent(ctx, a2aTypes.RecordedEvent{
+			Source:     a2aTypes.RecordedSourceSQS,
+			RecordedAt: time.Now(),
+			RequestID:  requestID,
+			Payload:    payload,
+		})
+	}
+	if err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("failed to record batch", a2aTypes.LogKeyError, err)
+	}
+}
+
+func main() {
+	lambda.Start(handleSQS)
+}
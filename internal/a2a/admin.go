@@ -0,0 +1,117 @@
+package a2a
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// Admin API defaults
+const (
+	DefaultAdminPath         = "/admin"
+	DefaultAdminAPIKeyHeader = "X-A2A-Admin-Key"
+)
+
+// SecuritySchemeAdminKey names the admin API key on the principals it
+// authenticates; it is never published on the agent card
+const SecuritySchemeAdminKey = "adminKey"
+
+// AdminSubject is the subject of every admin API caller, as audited
+const AdminSubject = "admin"
+
+// AdminMethodPrefix starts every method of the admin API
+const AdminMethodPrefix = "admin/"
+
+// IsAdminMethod reports whether method belongs to the admin API
+func IsAdminMethod(method string) bool {
+	return strings.HasPrefix(method, AdminMethodPrefix)
+}
+
+// AdminConfig places the admin API: the admin/* methods, served on a path
+// of their own under a credential of their own, secrets.admin_api_key. The
+// admin API is off unless that key is set, and admin methods are never
+// served on the agent's endpoint.
+type AdminConfig struct {
+	// Path is where the admin API is served (default DefaultAdminPath)
+	Path string `json:"path,omitempty"`
+	// APIKeyHeader carries the admin API key (default DefaultAdminAPIKeyHeader)
+	APIKeyHeader string `json:"api_key_header,omitempty"`
+}
+
+// path returns the configured path or the default
+func (c AdminConfig) path() string {
+	if c.Path == "" {
+		return DefaultAdminPath
+	}
+	return c.Path
+}
+
+// apiKeyHeader returns the configured header or the default
+func (c AdminConfig) apiKeyHeader() string {
+	if c.APIKeyHeader == "" {
+		return DefaultAdminAPIKeyHeader
+	}
+	return c.APIKeyHeader
+}
+
+// LoadAdminConfigFromEnv reads A2A_ADMIN_PATH and A2A_ADMIN_API_KEY_HEADER.
+// The key itself is A2A_ADMIN_API_KEY, read with the other secrets.
+func LoadAdminConfigFromEnv() AdminConfig {
+	return AdminConfig{
+		Path:         getEnvOrDefault("A2A_ADMIN_PATH", ""),
+		APIKeyHeader: getEnvOrDefault("A2A_ADMIN_API_KEY_HEADER", ""),
+	}
+}
+
+// ValidateAdminConfig checks the admin API does not shadow the agent's own paths
+func ValidateAdminConfig(config AdminConfig) error {
+	var errs ValidationErrors
+	switch path := config.path(); {
+	case !strings.HasPrefix(path, "/"):
+		errs.Add("path", ValidationCodeInvalid, fmt.Sprintf("'%s' must start with /", path))
+	case path == "/" || path == "/agent-card" || path == "/.well-known/agent.json":
+		errs.Add("path", ValidationCodeConflict, fmt.Sprintf("'%s' is served to every caller", path))
+	}
+	return errs.Err()
+}
+
+// AdminAuthenticator checks the admin API key. It shares nothing with the
+// Authenticator of the agent's endpoint, so no credential of that endpoint
+// reaches the admin API.
+type AdminAuthenticator struct {
+	path   string
+	header string
+	key    string
+}
+
+// NewAdminAuthenticator returns the authenticator of the admin API config
+// and key describe, or nil when no key is set and the admin API is off
+func NewAdminAuthenticator(config AdminConfig, key SecretString) *AdminAuthenticator {
+	if key == "" {
+		return nil
+	}
+	return &AdminAuthenticator{path: config.path(), header: config.apiKeyHeader(), key: key.Reveal()}
+}
+
+// Path returns where the admin API is served
+func (a *AdminAuthenticator) Path() string {
+	return a.path
+}
+
+// Header returns the header the admin API key is sent in
+func (a *AdminAuthenticator) Header() string {
+	return a.header
+}
+
+// Authenticate checks the admin API key in headers
+func (a *AdminAuthenticator) Authenticate(headers map[string]string) (Principal, error) {
+	key := HeaderValue(headers, a.header)
+	if key == "" {
+		return Principal{}, fmt.Errorf("%w: no admin API key provided", ErrUnauthenticated)
+	}
+	// Constant-time so the key cannot be recovered byte by byte from response timing
+	if subtle.ConstantTimeCompare([]byte(key), []byte(a.key)) != 1 {
+		return Principal{}, fmt.Errorf("%w: invalid admin API key", ErrUnauthenticated)
+	}
+	return Principal{Scheme: SecuritySchemeAdminKey, Subject: AdminSubject}, nil
+}
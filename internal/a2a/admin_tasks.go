@@ -0,0 +1,165 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// Task listing limits of admin/tasks/list
+const (
+	DefaultTaskListLimit = 50
+	MaxTaskListLimit     = 500
+)
+
+// ErrTaskListingUnsupported is returned for a task listing without a lister
+var ErrTaskListingUnsupported = errors.New("tasks cannot be listed here")
+
+// TaskLister enumerates every task of a task store, which itself only lists
+// the tasks of one context
+type TaskLister interface {
+	TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error]
+}
+
+// WithTaskListing serves admin/tasks/list from lister
+func WithTaskListing(lister TaskLister) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.taskLister = lister
+	}
+}
+
+// ListsTasks reports whether ListAllTasks is served
+func (h *ServerlessA2AHandler) ListsTasks() bool {
+	return h.taskLister != nil
+}
+
+// TaskListParams are the params of admin/tasks/list
+type TaskListParams struct {
+	// State only lists the tasks in this state
+	State a2a.TaskState `json:"state,omitempty"`
+	// Cursor continues from the page that returned it
+	Cursor string `json:"cursor,omitempty"`
+	// Limit bounds the tasks of a page (default DefaultTaskListLimit, at
+	// most MaxTaskListLimit)
+	Limit int `json:"limit,omitempty"`
+}
+
+// Validate checks the params of a task listing
+func (p TaskListParams) Validate() error {
+	var errs ValidationErrors
+	if p.Limit < 0 || p.Limit > MaxTaskListLimit {
+		errs.Add("limit", ValidationCodeInvalid, fmt.Sprintf("must be between 0 and %d", MaxTaskListLimit))
+	}
+	if _, err := p.offset(); err != nil {
+		errs.Add("cursor", ValidationCodeInvalid, "is not a cursor admin/tasks/list returned")
+	}
+	return errs.Err()
+}
+
+// offset is how many task IDs the pages before this one went through
+func (p TaskListParams) offset() (int, error) {
+	if p.Cursor == "" {
+		return 0, nil
+	}
+	offset, err := strconv.Atoi(p.Cursor)
+	if err == nil && offset < 0 {
+		err = errors.New("negative offset")
+	}
+	return offset, err
+}
+
+// TaskPage is a page of admin/tasks/list. NextCursor is empty on the last
+// page.
+type TaskPage struct {
+	Tasks      []a2a.Task `json:"tasks"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// ListAllTasks returns a page of every task, across contexts. Each page
+// goes through the task IDs the pages before it did, so tasks created or
+// deleted between pages may be listed twice or missed; it is meant for
+// operators, not for clients paging through large tables.
+func (h *ServerlessA2AHandler) ListAllTasks(ctx context.Context, params TaskListParams) (TaskPage, error) {
+	if h.taskLister == nil {
+		return TaskPage{}, ErrTaskListingUnsupported
+	}
+	offset, err := params.offset()
+	if err != nil {
+		return TaskPage{}, fmt.Errorf("cursor: %w", err)
+	}
+	limit := params.Limit
+	if limit == 0 {
+		limit = DefaultTaskListLimit
+	}
+
+	page := TaskPage{Tasks: []a2a.Task{}}
+	seen := 0
+	for taskID, err := range h.taskLister.TaskIDs(ctx) {
+		if err != nil {
+			return TaskPage{}, err
+		}
+		seen++
+		if seen <= offset {
+			continue
+		}
+		if len(page.Tasks) == limit {
+			page.NextCursor = strconv.Itoa(seen - 1)
+			break
+		}
+		task, err := h.taskStore.GetTask(ctx, taskID)
+		if errors.Is(err, a2a.ErrTaskNotFound) {
+			// Deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return TaskPage{}, fmt.Errorf("failed to get task %s: %w", taskID, err)
+		}
+		if params.State == "" || task.Status.State == params.State {
+			page.Tasks = append(page.Tasks, task)
+		}
+	}
+	return page, nil
+}
+
+// TaskExportParams are the params of admin/tasks/export
+type TaskExportParams struct {
+	TaskID a2a.TaskID `json:"task_id"`
+}
+
+// TaskExport is everything stored about a task, as admin/tasks/export
+// returns it
+type TaskExport struct {
+	Task   a2a.Task    `json:"task"`
+	Events []a2a.Event `json:"events"`
+	// PushConfigs are only stored with a push config store
+	PushConfigs []a2a.TaskPushConfig `json:"push_configs"`
+	ExportedAt  time.Time            `json:"exported_at"`
+}
+
+// ExportTask returns a task with its events and push notification configs,
+// for support cases and moving a single task by hand
+func (h *ServerlessA2AHandler) ExportTask(ctx context.Context, taskID a2a.TaskID) (TaskExport, error) {
+	task, err := h.taskStore.GetTask(ctx, taskID)
+	if err != nil {
+		return TaskExport{}, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	events, err := h.eventStore.GetEvents(ctx, taskID)
+	if err != nil {
+		return TaskExport{}, fmt.Errorf("failed to get events of task %s: %w", taskID, err)
+	}
+	export := TaskExport{Task: task, Events: events, PushConfigs: []a2a.TaskPushConfig{}, ExportedAt: h.clock.Now()}
+	if export.Events == nil {
+		export.Events = []a2a.Event{}
+	}
+	if h.pushConfigs != nil {
+		if export.PushConfigs, err = h.pushConfigs.ListPushConfigs(ctx, taskID); err != nil {
+			return TaskExport{}, err
+		}
+	}
+	return export, nil
+}
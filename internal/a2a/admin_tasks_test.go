@@ -0,0 +1,68 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestListAllTasks(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{tasks: []a2a.Task{
+		{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+		{ID: "task-2", ContextID: "ctx-2", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+		{ID: "task-3", ContextID: "ctx-3", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+	}}
+	// task-gone was deleted after it was listed
+	lister := taskIDList{"task-1", "task-gone", "task-2", "task-3"}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithTaskListing(lister))
+
+	// Pages follow each other across contexts
+	page, err := h.ListAllTasks(ctx, TaskListParams{Limit: 2})
+	if err != nil || len(page.Tasks) != 2 || page.Tasks[1].ID != "task-2" || page.NextCursor != "3" {
+		t.Fatalf("expected task-1 and task-2 with a cursor, got %+v: %v", page, err)
+	}
+	page, err = h.ListAllTasks(ctx, TaskListParams{Limit: 2, Cursor: page.NextCursor})
+	if err != nil || len(page.Tasks) != 1 || page.Tasks[0].ID != "task-3" || page.NextCursor != "" {
+		t.Errorf("expected task-3 on the last page, got %+v: %v", page, err)
+	}
+
+	page, err = h.ListAllTasks(ctx, TaskListParams{State: a2a.TaskStateCompleted})
+	if err != nil || len(page.Tasks) != 2 || page.Tasks[1].ID != "task-3" {
+		t.Errorf("expected the completed tasks, got %+v: %v", page, err)
+	}
+
+	if err := (TaskListParams{Cursor: "later"}).Validate(); err == nil {
+		t.Error("expected a cursor that was not returned to be refused")
+	}
+	_, err = NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil).ListAllTasks(ctx, TaskListParams{})
+	if !errors.Is(err, ErrTaskListingUnsupported) {
+		t.Errorf("expected ErrTaskListingUnsupported, got %v", err)
+	}
+}
+
+func TestExportTask(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	stores := &memoryStores{}
+	configs := NewMemoryPushConfigStore()
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithPushConfigStore(configs), WithClock(fixedClock{now: now}))
+	stores.SaveTask(ctx, a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}})
+	stores.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{TaskID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}})
+	configs.SavePushConfig(ctx, a2a.TaskPushConfig{TaskID: "task-1", Config: a2a.PushConfig{URL: "https://hooks.example.com/a2a"}})
+
+	export, err := h.ExportTask(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.Task.ID != "task-1" || len(export.Events) != 1 || len(export.PushConfigs) != 1 || !export.ExportedAt.Equal(now) {
+		t.Errorf("expected the task, its event and its config, got %+v", export)
+	}
+
+	if _, err := h.ExportTask(ctx, "missing"); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
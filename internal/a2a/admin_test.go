@@ -0,0 +1,83 @@
+package a2a
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAdminAuthenticator(t *testing.T) {
+	if NewAdminAuthenticator(AdminConfig{Path: "/ops"}, "") != nil {
+		t.Fatal("expected the admin API off without a key")
+	}
+
+	admin := NewAdminAuthenticator(AdminConfig{}, "admin-secret")
+	if admin.Path() != DefaultAdminPath {
+		t.Errorf("expected the default path, got %s", admin.Path())
+	}
+	principal, err := admin.Authenticate(map[string]string{"x-a2a-admin-key": "admin-secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.Scheme != SecuritySchemeAdminKey || principal.Subject != AdminSubject {
+		t.Errorf("expected the admin principal, got %+v", principal)
+	}
+
+	for name, headers := range map[string]map[string]string{
+		"no key":    {},
+		"wrong key": {DefaultAdminAPIKeyHeader: "admin-guess"},
+		"agent key": {"X-API-Key": "admin-secret"},
+	} {
+		if _, err := admin.Authenticate(headers); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: expected ErrUnauthenticated, got %v", name, err)
+		}
+	}
+
+	custom := NewAdminAuthenticator(AdminConfig{Path: "/ops", APIKeyHeader: "X-Ops-Key"}, "admin-secret")
+	if _, err := custom.Authenticate(map[string]string{"X-Ops-Key": "admin-secret"}); err != nil || custom.Path() != "/ops" {
+		t.Errorf("expected the configured path and header, got %s: %v", custom.Path(), err)
+	}
+}
+
+func TestValidateAdminConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     AdminConfig
+		expectPath string
+	}{
+		{name: "defaults", config: AdminConfig{}},
+		{name: "custom path", config: AdminConfig{Path: "/ops/admin"}},
+		{name: "relative path", config: AdminConfig{Path: "admin"}, expectPath: "path"},
+		{name: "agent endpoint", config: AdminConfig{Path: "/"}, expectPath: "path"},
+		{name: "card path", config: AdminConfig{Path: "/.well-known/agent.json"}, expectPath: "path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAdminConfig(tt.config)
+			if tt.expectPath == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectPath) {
+				t.Errorf("expected an error at %s, got %v", tt.expectPath, err)
+			}
+		})
+	}
+}
+
+func TestLoadAdminConfigFromEnv(t *testing.T) {
+	t.Setenv("A2A_ADMIN_PATH", "/ops")
+	t.Setenv("A2A_ADMIN_API_KEY_HEADER", "")
+	config := LoadAdminConfigFromEnv()
+	if config.Path != "/ops" || config.apiKeyHeader() != DefaultAdminAPIKeyHeader {
+		t.Errorf("expected the path with the default header, got %+v", config)
+	}
+
+	// The key is a secret, redacted with the others
+	redacted := RedactConfig(ServerlessConfig{Secrets: SecretsConfig{AdminAPIKey: "admin-secret"}})
+	if redacted.Secrets.AdminAPIKey != RedactedValue {
+		t.Errorf("expected the admin key redacted, got %q", redacted.Secrets.AdminAPIKey.Reveal())
+	}
+}
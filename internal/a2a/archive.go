@@ -0,0 +1,190 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// ArchivedTask is a task moved out of the task and event stores, with its
+// events as they were stored
+type ArchivedTask struct {
+	Task       a2a.Task          `json:"task"`
+	Events     []json.RawMessage `json:"events"`
+	ArchivedAt time.Time         `json:"archived_at"`
+}
+
+// TaskArchive keeps archived tasks in storage cheaper than the task table
+type TaskArchive interface {
+	PutArchivedTask(ctx context.Context, archived ArchivedTask) error
+	// GetArchivedTask returns a2a.ErrTaskNotFound for tasks never archived
+	GetArchivedTask(ctx context.Context, taskID a2a.TaskID) (ArchivedTask, error)
+	// DeleteArchivedTask deletes a task's archived copy, if there is one
+	DeleteArchivedTask(ctx context.Context, taskID a2a.TaskID) error
+}
+
+// ArchivedTaskStore is a task store that restores tasks from an archive,
+// so purges erase the archived copies too
+type ArchivedTaskStore interface {
+	// Archive returns the archive, nil when the store has none
+	Archive() TaskArchive
+}
+
+// ArchiveConfig selects where archived tasks are kept. Archival is off
+// unless S3URI is set.
+type ArchiveConfig struct {
+	S3URI string
+}
+
+// Enabled reports whether an archive is configured
+func (c ArchiveConfig) Enabled() bool {
+	return c.S3URI != ""
+}
+
+// LoadArchiveConfigFromEnv reads A2A_ARCHIVE_S3_URI
+func LoadArchiveConfigFromEnv() (ArchiveConfig, error) {
+	config := ArchiveConfig{S3URI: getEnvOrDefault("A2A_ARCHIVE_S3_URI", "")}
+	if config.S3URI != "" {
+		if _, _, err := parseS3Location(config.S3URI); err != nil {
+			return ArchiveConfig{}, fmt.Errorf("A2A_ARCHIVE_S3_URI: %w", err)
+		}
+	}
+	return config, nil
+}
+
+// Archiver moves finished tasks and their events to an archive, and back
+type Archiver struct {
+	tasks   TaskStore
+	events  EventStore
+	archive TaskArchive
+	runtimeDeps
+}
+
+// NewArchiver creates an archiver between the stores and archive. The event
+// store must be an EventPurger for tasks to be archived.
+func NewArchiver(tasks TaskStore, events EventStore, archive TaskArchive, opts ...RuntimeOption) *Archiver {
+	return &Archiver{tasks: tasks, events: events, archive: archive, runtimeDeps: newRuntimeDeps(opts)}
+}
+
+// archivable reports whether task finished more than olderThan before now.
+// A task without a status timestamp cannot be dated, so it is kept.
+func archivable(task a2a.Task, now time.Time, olderThan time.Duration) bool {
+	switch task.Status.State {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+	default:
+		return false
+	}
+	return task.Status.Timestamp != nil && !task.Status.Timestamp.After(now.Add(-olderThan))
+}
+
+// ArchiveTask moves a task to the archive when it is in a terminal state
+// whose last status is more than olderThan old, reporting whether it was
+// moved. The archive is written before anything is deleted, and the task
+// last, so an archival that fails part way can be run again.
+func (a *Archiver) ArchiveTask(ctx context.Context, taskID a2a.TaskID, olderThan time.Duration) (bool, error) {
+	purger, ok := a.events.(EventPurger)
+	if !ok {
+		return false, fmt.Errorf("events: %w", ErrPurgeUnsupported)
+	}
+	task, err := a.tasks.GetTask(ctx, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	now := a.clock.Now()
+	if !archivable(task, now, olderThan) {
+		return false, nil
+	}
+
+	events, err := a.events.GetEvents(ctx, taskID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get events of task %s: %w", taskID, err)
+	}
+	archived := ArchivedTask{Task: task, Events: make([]json.RawMessage, 0, len(events)), ArchivedAt: now}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal event of task %s: %w", taskID, err)
+		}
+		archived.Events = append(archived.Events, data)
+	}
+	if err := a.archive.PutArchivedTask(ctx, archived); err != nil {
+		return false, err
+	}
+
+	if _, err := purger.DeleteEvents(ctx, taskID); err != nil {
+		return false, fmt.Errorf("failed to delete events of task %s: %w", taskID, err)
+	}
+	if err := a.tasks.DeleteTask(ctx, taskID); err != nil {
+		return false, fmt.Errorf("failed to delete task %s: %w", taskID, err)
+	}
+	return true, nil
+}
+
+// RestoreTask writes an archived task and its events back to the stores,
+// events first so the task is never served without them. The archived copy
+// is kept; archiving the task again overwrites it.
+func (a *Archiver) RestoreTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	defer observeStorage(ctx, "RestoreTask", time.Now())
+
+	archived, err := a.archive.GetArchivedTask(ctx, taskID)
+	if err != nil {
+		return a2a.Task{}, err
+	}
+	for _, data := range archived.Events {
+		event := decodeStoredEvent(string(data))
+		if event == nil {
+			continue
+		}
+		if err := a.events.SaveEvent(ctx, event); err != nil {
+			return a2a.Task{}, fmt.Errorf("failed to restore events of task %s: %w", taskID, err)
+		}
+	}
+	if err := a.tasks.SaveTask(ctx, archived.Task); err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to restore task %s: %w", taskID, err)
+	}
+	LoggerFromContext(ctx).Info("task restored from archive", LogKeyTaskID, string(taskID), "archived_at", archived.ArchivedAt)
+	return archived.Task, nil
+}
+
+// archivingTaskStore restores archived tasks when they are asked for
+type archivingTaskStore struct {
+	TaskStore
+	archiver *Archiver
+}
+
+// NewArchivingTaskStore wraps a task store so that getting a task missing
+// from it restores the task and its events from archive first. The task is
+// then served from the stores again until it is next archived.
+func NewArchivingTaskStore(tasks TaskStore, events EventStore, archive TaskArchive, opts ...RuntimeOption) TaskStore {
+	return &archivingTaskStore{TaskStore: tasks, archiver: NewArchiver(tasks, events, archive, opts...)}
+}
+
+// GetTask implements TaskStore
+func (s *archivingTaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	task, err := s.TaskStore.GetTask(ctx, taskID)
+	if !errors.Is(err, a2a.ErrTaskNotFound) {
+		return task, err
+	}
+	restored, restoreErr := s.archiver.RestoreTask(ctx, taskID)
+	if errors.Is(restoreErr, a2a.ErrTaskNotFound) {
+		return task, err
+	}
+	return restored, restoreErr
+}
+
+// Archive implements ArchivedTaskStore
+func (s *archivingTaskStore) Archive() TaskArchive {
+	return s.archiver.archive
+}
+
+// SaveTaskHeartbeat implements TaskHeartbeatStore when the wrapped store does
+func (s *archivingTaskStore) SaveTaskHeartbeat(ctx context.Context, task a2a.Task) error {
+	if store, ok := s.TaskStore.(TaskHeartbeatStore); ok {
+		return store.SaveTaskHeartbeat(ctx, task)
+	}
+	return s.SaveTask(ctx, task)
+}
@@ -0,0 +1,72 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestArchiveAndRestore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	finished, recent := now.Add(-48*time.Hour), now.Add(-time.Hour)
+	stores := &memoryStores{}
+	stores.SaveTask(ctx, a2a.Task{ID: "task/1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted, Timestamp: &finished}})
+	stores.SaveTask(ctx, a2a.Task{ID: "task-2", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Timestamp: &finished}})
+	stores.SaveTask(ctx, a2a.Task{ID: "task-3", Status: a2a.TaskStatus{State: a2a.TaskStateFailed, Timestamp: &recent}})
+	for range 2 {
+		stores.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task/1"})
+	}
+
+	objects := &fakeS3{objects: map[string]string{}}
+	archive, err := NewS3TaskArchive(objects, "s3://bucket/archive", "billing#")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	archiver := NewArchiver(stores, stores, archive, WithClock(fixedClock{now: now}))
+
+	// Only a finished task past the threshold is moved
+	for _, id := range []a2a.TaskID{"task/1", "task-2", "task-3"} {
+		archived, err := archiver.ArchiveTask(ctx, id, 24*time.Hour)
+		if err != nil || archived != (id == "task/1") {
+			t.Errorf("%s: unexpected archived %v, %v", id, archived, err)
+		}
+	}
+	if !slices.Equal(stores.taskIDs(), []a2a.TaskID{"task-2", "task-3"}) || len(stores.events) != 0 {
+		t.Errorf("expected the task and its events deleted, got %v and %d events", stores.taskIDs(), len(stores.events))
+	}
+	// IDs are escaped, so task/1 and task_1 cannot share an object
+	object, ok := objects.objects["bucket/archive/billing%23task%2F1.json"]
+	if !ok || !strings.Contains(object, `"archived_at":"2025-03-01T00:00:00Z"`) {
+		t.Fatalf("expected the archived task in S3, got %v", objects.objects)
+	}
+
+	// Getting the task through the archiving store restores it and its events
+	tasks := NewArchivingTaskStore(stores, stores, archive)
+	task, err := tasks.GetTask(ctx, "task/1")
+	if err != nil || task.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("expected the task restored, got %+v, %v", task, err)
+	}
+	if !slices.Contains(stores.taskIDs(), "task/1") || len(stores.events) != 2 {
+		t.Errorf("expected the task and its 2 events back in the stores, got %v and %d events", stores.taskIDs(), len(stores.events))
+	}
+	if _, err := tasks.GetTask(ctx, "task-4"); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("expected a task never archived to be not found, got %v", err)
+	}
+}
+
+func TestLoadArchiveConfigFromEnv(t *testing.T) {
+	t.Setenv("A2A_ARCHIVE_S3_URI", "")
+	if config, err := LoadArchiveConfigFromEnv(); err != nil || config.Enabled() {
+		t.Errorf("expected archival off by default, got %+v, %v", config, err)
+	}
+	t.Setenv("A2A_ARCHIVE_S3_URI", "bucket/archive")
+	if _, err := LoadArchiveConfigFromEnv(); err == nil {
+		t.Error("expected an invalid URI refused")
+	}
+}
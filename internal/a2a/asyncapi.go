@@ -0,0 +1,142 @@
+package a2a
+
+import (
+	"reflect"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// AsyncAPIVersion is the AsyncAPI version of the document AsyncAPIDocument builds
+const AsyncAPIVersion = "3.0.0"
+
+// notificationEventTypes are the events a push notification can carry
+var notificationEventTypes = []reflect.Type{
+	reflect.TypeOf(a2a.Task{}),
+	reflect.TypeOf(a2a.Message{}),
+	reflect.TypeOf(a2a.TaskStatusUpdateEvent{}),
+	reflect.TypeOf(a2a.TaskArtifactUpdateEvent{}),
+}
+
+// AsyncAPIDocument describes the messages this package sends outside a
+// request: push notifications queued on SQS, the webhook calls the worker
+// makes with them, and messages queued for a work or retry queue. Payload
+// schemas are derived from the Go types that are serialized, as
+// ConfigJSONSchema does for the config, so consumers can generate code
+// that reads exactly what is sent.
+func AsyncAPIDocument() map[string]any {
+	schemas := map[string]any{
+		"PushNotification":  pushNotificationSchema(),
+		"PushConfig":        reflectSchema(reflect.TypeOf(a2a.PushConfig{}), nil),
+		"MessageSendParams": reflectSchema(reflect.TypeOf(a2a.MessageSendParams{}), nil),
+	}
+	for _, t := range notificationEventTypes {
+		schemas[t.Name()] = reflectSchema(t, nil)
+	}
+
+	return map[string]any{
+		"asyncapi": AsyncAPIVersion,
+		"info": map[string]any{
+			"title":       "A2A serverless queues and webhooks",
+			"version":     DefaultProtocolVersion,
+			"description": "Messages sent outside a request. Payloads follow the A2A protocol version above, serialized with Go field names.",
+		},
+		"channels": map[string]any{
+			"notifications": map[string]any{
+				"address":     "{queueUrl}",
+				"title":       "Push notification queue",
+				"description": "The SQS queue named by SQS_QUEUE_URL, or a failover queue. The worker reads it and calls each notification's webhook.",
+				"parameters":  map[string]any{"queueUrl": map[string]any{"description": "SQS queue URL"}},
+				"messages":    map[string]any{"pushNotification": map[string]any{"$ref": "#/components/messages/QueuedPushNotification"}},
+			},
+			"webhook": map[string]any{
+				"address":     "{webhookUrl}",
+				"title":       "Push notification webhook",
+				"description": "The URL of the client's push config, called with POST. The body is the queued message unchanged, so its signature still verifies.",
+				"parameters":  map[string]any{"webhookUrl": map[string]any{"description": "push_config.URL of the notification"}},
+				"messages":    map[string]any{"pushNotification": map[string]any{"$ref": "#/components/messages/WebhookPushNotification"}},
+			},
+			"messageQueue": map[string]any{
+				"address":     "{queueUrl}",
+				"title":       "Work and retry queue",
+				"description": "The SQS queue given to WithWorkQueue or WithRetryQueue. Its consumer passes each body to ProcessQueuedMessage.",
+				"parameters":  map[string]any{"queueUrl": map[string]any{"description": "SQS queue URL"}},
+				"messages":    map[string]any{"queuedMessage": map[string]any{"$ref": "#/components/messages/QueuedMessage"}},
+			},
+		},
+		"operations": map[string]any{
+			"enqueuePushNotification": asyncAPISendOperation("notifications", "pushNotification", "Queue a notification of a task event for its push config"),
+			"deliverPushNotification": asyncAPISendOperation("webhook", "pushNotification", "Deliver a queued notification to its webhook"),
+			"queueMessage":            asyncAPISendOperation("messageQueue", "queuedMessage", "Queue a message to execute on its task, after a delay for retries"),
+		},
+		"components": map[string]any{
+			"messages": map[string]any{
+				"QueuedPushNotification": map[string]any{
+					"name":        "QueuedPushNotification",
+					"contentType": "application/json",
+					"summary":     "A task event and the push config to deliver it to",
+					"headers":     asyncAPIHeaders("SQS message attributes, which the worker turns back into headers", false),
+					"payload":     map[string]any{"$ref": "#/components/schemas/PushNotification"},
+				},
+				"WebhookPushNotification": map[string]any{
+					"name":        "WebhookPushNotification",
+					"contentType": "application/json",
+					"summary":     "A task event, posted to the client's webhook",
+					"headers":     asyncAPIHeaders("HTTP headers of the webhook call", true),
+					"payload":     map[string]any{"$ref": "#/components/schemas/PushNotification"},
+				},
+				"QueuedMessage": map[string]any{
+					"name":        "QueuedMessage",
+					"contentType": "application/json",
+					"summary":     "A message already in its task's history, to execute on the task",
+					"payload":     map[string]any{"$ref": "#/components/schemas/MessageSendParams"},
+				},
+			},
+			"schemas": schemas,
+		},
+	}
+}
+
+// pushNotificationSchema describes PushNotification, whose event is kept
+// raw when read back but is one of notificationEventTypes when sent
+func pushNotificationSchema() map[string]any {
+	events := make([]any, 0, len(notificationEventTypes))
+	for _, t := range notificationEventTypes {
+		events = append(events, map[string]any{"$ref": "#/components/schemas/" + t.Name()})
+	}
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"push_config", "event"},
+		"properties": map[string]any{
+			"push_config": map[string]any{"$ref": "#/components/schemas/PushConfig"},
+			"event":       map[string]any{"oneOf": events},
+		},
+	}
+}
+
+// asyncAPIHeaders describes the headers NotificationHeaders sets, and with
+// webhook those the deliverer adds for the receiver
+func asyncAPIHeaders(description string, webhook bool) map[string]any {
+	headers := map[string]any{
+		"traceparent":        map[string]any{"type": "string", "description": "W3C trace context of the request that caused the notification"},
+		"tracestate":         map[string]any{"type": "string", "description": "W3C trace state"},
+		CorrelationIDHeader:  map[string]any{"type": "string", "description": "ID of the request that caused the notification"},
+		SignatureHeader:      map[string]any{"type": "string", "description": "HMAC-SHA256 of the body with the webhook signing key, as " + signaturePrefix + "<hex>"},
+		NotificationIDHeader: map[string]any{"type": "string", "description": "ID of the queue message, set on notifications replayed from the dead-letter queue"},
+	}
+	if webhook {
+		headers["Content-Type"] = map[string]any{"const": "application/json"}
+		headers[NotificationTokenHeader] = map[string]any{"type": "string", "description": "push_config.Token, when the client set one"}
+		headers["Authorization"] = map[string]any{"type": "string", "description": "Bearer push_config.Auth.Credentials, when its schemes include Bearer"}
+	}
+	return map[string]any{"type": "object", "description": description, "properties": headers}
+}
+
+// asyncAPISendOperation describes sending message on channel
+func asyncAPISendOperation(channel, message, summary string) map[string]any {
+	return map[string]any{
+		"action":   "send",
+		"summary":  summary,
+		"channel":  map[string]any{"$ref": "#/channels/" + channel},
+		"messages": []any{map[string]any{"$ref": "#/channels/" + channel + "/messages/" + message}},
+	}
+}
@@ -0,0 +1,82 @@
+package a2a
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestAsyncAPIDocumentReferencesResolve(t *testing.T) {
+	data, err := json.Marshal(AsyncAPIDocument())
+	if err != nil {
+		t.Fatalf("failed to serialize document: %v", err)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("failed to decode document: %v", err)
+	}
+
+	var check func(node any)
+	check = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok {
+				if _, ok := resolvePointer(document, ref); !ok {
+					t.Errorf("unresolved reference %s", ref)
+				}
+			}
+			for _, child := range node {
+				check(child)
+			}
+		case []any:
+			for _, child := range node {
+				check(child)
+			}
+		}
+	}
+	check(document)
+}
+
+// resolvePointer follows a local JSON pointer such as #/components/schemas/Task
+func resolvePointer(document map[string]any, ref string) (any, bool) {
+	var node any = document
+	for _, segment := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if node, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func TestAsyncAPISchemasMatchSerializedTypes(t *testing.T) {
+	schemas := AsyncAPIDocument()["components"].(map[string]any)["schemas"].(map[string]any)
+
+	// The queued body has exactly the properties described
+	data, _ := json.Marshal(PushNotification{PushConfig: a2a.PushConfig{URL: "https://client.example.com"}, Event: json.RawMessage(`{}`)})
+	var body map[string]any
+	json.Unmarshal(data, &body)
+	properties := schemas["PushNotification"].(map[string]any)["properties"].(map[string]any)
+	for key := range body {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("serialized notification has %s, which the schema lacks", key)
+		}
+	}
+
+	status := schemas["TaskStatusUpdateEvent"].(map[string]any)["properties"].(map[string]any)
+	if kind := status["Kind"].(map[string]any)["enum"]; !slices.Equal(kind.([]string), []string{"status-update"}) {
+		t.Errorf("expected the status update kind, got %v", kind)
+	}
+
+	message := schemas["Message"].(map[string]any)["properties"].(map[string]any)
+	parts := message["Parts"].(map[string]any)["items"].(map[string]any)
+	if variants, _ := parts["oneOf"].([]any); len(variants) != 3 {
+		t.Errorf("expected text, file and data parts, got %v", parts)
+	}
+}
@@ -0,0 +1,60 @@
+package a2a
+
+import (
+	"context"
+	"time"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeOK    = "ok"
+	AuditOutcomeError = "error"
+	// AuditOutcomeDenied is a call an authenticated caller was not allowed to make
+	AuditOutcomeDenied = "denied"
+)
+
+// Audit query limits
+const (
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 1000
+)
+
+// AuditRecord is one A2A protocol operation: who called which method on which
+// task, and how it ended. Message content is never recorded.
+type AuditRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id,omitempty"`
+	Scheme    string    `json:"scheme,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	Method    string    `json:"method"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Outcome   string    `json:"outcome"`
+	ErrorCode int       `json:"error_code,omitempty"`
+}
+
+// AuditQuery selects audit records for one task or one caller, newest first
+type AuditQuery struct {
+	TaskID  string `json:"task_id,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+// AuditLog persists audit records
+type AuditLog interface {
+	RecordAudit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditReader is an audit log that can be queried by the admin method.
+// Firehose delivers to S3 or a SIEM, so it is queried there instead.
+type AuditReader interface {
+	AuditLog
+	ListAudit(ctx context.Context, query AuditQuery) ([]AuditRecord, error)
+}
+
+// auditLimit clamps a requested page size
+func auditLimit(limit int) int32 {
+	if limit <= 0 {
+		return DefaultAuditLimit
+	}
+	return int32(min(limit, MaxAuditLimit))
+}
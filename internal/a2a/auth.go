@@ -0,0 +1,543 @@
+package a2a
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnauthenticated is returned when a request carries no acceptable credential
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// jwksCacheTTL is how long fetched signing keys are trusted before refetching
+const jwksCacheTTL = time.Hour
+
+// jwksRefetchInterval limits refetches triggered by unknown key IDs, so a
+// stream of forged tokens cannot hammer the identity provider
+const jwksRefetchInterval = time.Minute
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Scheme is the security scheme name that accepted the credential
+	Scheme  string
+	Subject string
+	Scopes  []string
+	// Claims are the claims of a bearer token, for method policies
+	Claims map[string]json.RawMessage
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a context carrying the authenticated caller
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+// Authenticator enforces the security schemes published on the agent card.
+// A request is accepted when any one configured scheme accepts it, matching
+// the alternatives listed in the card's security requirements.
+type Authenticator struct {
+	apiKeyHeader  string
+	apiKey        string
+	bearer        []*jwtVerifier
+	signature     *signatureVerifier
+	iam           bool
+	adminSubjects []string
+	// methodPolicies and ownTasksOnly authorize authenticated callers
+	methodPolicies []MethodPolicy
+	ownTasksOnly   bool
+	// nonces, when set, rejects replayed signed requests and single-use tokens
+	nonces NonceStore
+}
+
+// NewAuthenticator creates an authenticator for the schemes in config.
+// secrets holds the resolved API key and request signing key inbound
+// credentials are checked against. client fetches OIDC metadata and JWKS;
+// nil uses a client with a short timeout.
+func NewAuthenticator(config SecurityConfig, secrets SecretsConfig, client *http.Client, opts ...AuthenticatorOption) *Authenticator {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	a := &Authenticator{
+		apiKeyHeader:   config.APIKeyHeader,
+		apiKey:         secrets.APIKey.Reveal(),
+		iam:            config.IAM,
+		adminSubjects:  config.AdminSubjects,
+		methodPolicies: config.MethodPolicies,
+		ownTasksOnly:   config.OwnTasksOnly,
+	}
+	if config.SignatureHeader != "" {
+		a.signature = &signatureVerifier{
+			header:    config.SignatureHeader,
+			key:       secrets.RequestSigningKey.Reveal(),
+			tolerance: config.signatureTolerance(),
+			now:       time.Now,
+		}
+	}
+	if config.OAuth2TokenURL != "" {
+		a.bearer = append(a.bearer, &jwtVerifier{
+			scheme:         SecuritySchemeOAuth2,
+			jwksURL:        config.OAuth2JWKSURL,
+			issuer:         config.OAuth2Issuer,
+			audience:       config.Audience,
+			requiredScopes: config.OAuth2Scopes,
+			client:         client,
+			now:            time.Now,
+		})
+	}
+	if config.OIDCMetadataURL != "" {
+		a.bearer = append(a.bearer, &jwtVerifier{
+			scheme:      SecuritySchemeOIDC,
+			metadataURL: config.OIDCMetadataURL,
+			audience:    config.Audience,
+			client:      client,
+			now:         time.Now,
+		})
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	for _, verifier := range a.bearer {
+		verifier.singleUse = config.SingleUseTokens
+		verifier.nonces = a.nonces
+	}
+	return a
+}
+
+// Enabled reports whether any scheme is configured. Without one every request is accepted.
+func (a *Authenticator) Enabled() bool {
+	return a != nil && (a.apiKeyHeader != "" || len(a.bearer) > 0 || a.signature != nil || a.iam)
+}
+
+// APIKeyHeader returns the header carrying the API key, or "" when API keys are not accepted
+func (a *Authenticator) APIKeyHeader() string {
+	if a == nil {
+		return ""
+	}
+	return a.apiKeyHeader
+}
+
+// AcceptsBearer reports whether bearer tokens are accepted, for WWW-Authenticate
+func (a *Authenticator) AcceptsBearer() bool {
+	return a != nil && len(a.bearer) > 0
+}
+
+// IsAdmin reports whether principal is one of the admin subjects, who may
+// access every task. Admin methods are served only on the admin API, see
+// AdminAuthenticator.
+func (a *Authenticator) IsAdmin(principal Principal) bool {
+	if !a.Enabled() || principal.Subject == "" {
+		return false
+	}
+	return slices.Contains(a.adminSubjects, principal.Subject)
+}
+
+// Authenticate checks the request headers, and for a request signature the
+// body, against every configured scheme
+func (a *Authenticator) Authenticate(ctx context.Context, headers map[string]string, body []byte) (Principal, error) {
+	if !a.Enabled() {
+		return Principal{}, nil
+	}
+
+	var failures []string
+
+	if a.apiKeyHeader != "" {
+		if key := HeaderValue(headers, a.apiKeyHeader); key != "" {
+			// Constant-time so the key cannot be recovered byte by byte from response timing
+			if a.apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(a.apiKey)) == 1 {
+				return Principal{Scheme: SecuritySchemeAPIKey, Subject: SecuritySchemeAPIKey}, nil
+			}
+			failures = append(failures, "invalid API key")
+		}
+	}
+
+	if len(a.bearer) > 0 {
+		if token, ok := bearerToken(HeaderValue(headers, "Authorization")); ok {
+			for _, verifier := range a.bearer {
+				principal, err := verifier.verify(ctx, token)
+				if err == nil {
+					return principal, nil
+				}
+				failures = append(failures, fmt.Sprintf("%s: %v", verifier.scheme, err))
+			}
+		}
+	}
+
+	if a.signature != nil && HeaderValue(headers, a.signature.header) != "" {
+		if err := a.signature.verify(ctx, headers, body, a.nonces); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", SecuritySchemeSignature, err))
+		} else {
+			return Principal{Scheme: SecuritySchemeSignature, Subject: SecuritySchemeSignature}, nil
+		}
+	}
+
+	if len(failures) == 0 {
+		return Principal{}, fmt.Errorf("%w: no credential provided", ErrUnauthenticated)
+	}
+	return Principal{}, fmt.Errorf("%w: %s", ErrUnauthenticated, strings.Join(failures, "; "))
+}
+
+// HeaderValue looks a header up case-insensitively, since API Gateway passes
+// header names through as the client sent them
+func HeaderValue(headers map[string]string, name string) string {
+	if value, ok := headers[name]; ok {
+		return value
+	}
+	canonical := textproto.CanonicalMIMEHeaderKey(name)
+	for key, value := range headers {
+		if textproto.CanonicalMIMEHeaderKey(key) == canonical {
+			return value
+		}
+	}
+	return ""
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" value
+func bearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// jwtVerifier verifies JWT access tokens against a JWKS, discovered from
+// OIDC metadata when metadataURL is set
+type jwtVerifier struct {
+	scheme         string
+	metadataURL    string
+	jwksURL        string
+	issuer         string
+	audience       string
+	requiredScopes []string
+	client         *http.Client
+	now            func() time.Time
+	// singleUse accepts each token once, by its jti, recorded in nonces
+	singleUse bool
+	nonces    NonceStore
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// verify checks a token's signature and claims and returns its principal
+func (v *jwtVerifier) verify(ctx context.Context, token string) (Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Principal{}, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return Principal{}, fmt.Errorf("malformed token header: %w", err)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, errors.New("malformed token signature")
+	}
+	if err := verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return Principal{}, err
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return Principal{}, err
+	}
+	if v.singleUse {
+		if claims.ID == "" {
+			return Principal{}, errors.New("single-use token has no jti")
+		}
+		// The token is refused after exp anyway, so its jti is kept until then
+		expiresAt := time.Unix(int64(*claims.ExpiresAt), 0).Add(clockSkew)
+		if err := useNonce(ctx, v.nonces, v.scheme+"#"+claims.Issuer+"#"+claims.ID, expiresAt); err != nil {
+			return Principal{}, err
+		}
+	}
+	// Kept whole so method policies can match any claim, such as groups
+	var allClaims map[string]json.RawMessage
+	if err := decodeJWTSegment(parts[1], &allClaims); err != nil {
+		return Principal{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	return Principal{Scheme: v.scheme, Subject: claims.Subject, Scopes: claims.scopes(), Claims: allClaims}, nil
+}
+
+// jwtClaims are the registered claims checked on access tokens
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"`
+	Scp       json.RawMessage `json:"scp"`
+	ID        string          `json:"jti"`
+}
+
+// scopes reads OAuth2 "scope" (space-separated) or the "scp" claim some providers use
+func (c jwtClaims) scopes() []string {
+	if c.Scope != "" {
+		return strings.Fields(c.Scope)
+	}
+	return stringOrStrings(c.Scp)
+}
+
+// stringOrStrings decodes a claim that may be a string or an array of strings
+func stringOrStrings(raw json.RawMessage) []string {
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil && single != "" {
+		return strings.Fields(single)
+	}
+	return nil
+}
+
+// clockSkew tolerates small clock differences between the issuer and Lambda
+const clockSkew = time.Minute
+
+// checkClaims validates expiry, issuer, audience and required scopes
+func (v *jwtVerifier) checkClaims(claims jwtClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == nil {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(*claims.ExpiresAt), 0).Add(clockSkew)) {
+		return errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(clockSkew).Before(time.Unix(int64(*claims.NotBefore), 0)) {
+		return errors.New("token not yet valid")
+	}
+
+	v.mu.Lock()
+	issuer := v.issuer
+	v.mu.Unlock()
+	if issuer != "" && claims.Issuer != issuer {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(stringOrStrings(claims.Audience), v.audience) {
+		return errors.New("token audience does not include this agent")
+	}
+
+	scopes := claims.scopes()
+	for _, required := range v.requiredScopes {
+		if !slices.Contains(scopes, required) {
+			return fmt.Errorf("token is missing scope %q", required)
+		}
+	}
+	return nil
+}
+
+// key returns the signing key for kid, fetching the JWKS on a cache miss
+func (v *jwtVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	stale := now.Sub(v.fetchedAt) > jwksCacheTTL
+	if key, ok := v.keys[kid]; ok && !stale {
+		return key, nil
+	}
+	if !stale && now.Sub(v.fetchedAt) < jwksRefetchInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if err := v.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	v.fetchedAt = now
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// fetchKeys loads the JWKS, resolving it through OIDC discovery first when needed.
+// Called with v.mu held.
+func (v *jwtVerifier) fetchKeys(ctx context.Context) error {
+	if v.metadataURL != "" && v.jwksURL == "" {
+		var metadata struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := getJSON(ctx, v.client, v.metadataURL, &metadata); err != nil {
+			return fmt.Errorf("failed to load OIDC metadata: %w", err)
+		}
+		if metadata.JWKSURI == "" {
+			return errors.New("OIDC metadata has no jwks_uri")
+		}
+		v.jwksURL = metadata.JWKSURI
+		v.issuer = metadata.Issuer
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, v.client, v.jwksURL, &jwks); err != nil {
+		return fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// One unsupported key should not disable the others
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	v.keys = keys
+	return nil
+}
+
+// getJSON fetches and decodes a JSON document
+func getJSON(ctx context.Context, client *http.Client, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jsonWebKey is an RSA or EC public key from a JWKS
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey converts the JWK into a Go public key
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("EC key is not on P-256")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+// verifyJWTSignature checks an RS256 or ES256 signature. Other algorithms,
+// including "none", are rejected.
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	digest := sha256.Sum256(signed)
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 token signed with a non-RSA key")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return errors.New("invalid ES256 token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported token algorithm %q", alg)
+	}
+}
+
+// decodeJWTSegment decodes a base64url JSON segment of a JWT
+func decodeJWTSegment(segment string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// decodeBigInt decodes a base64url big-endian integer from a JWK
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid JWK integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
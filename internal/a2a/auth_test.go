@@ -0,0 +1,276 @@
+package a2a
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// testIssuer serves OIDC metadata and a JWKS for one RSA key and signs tokens with it
+type testIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+// sign returns an RS256 JWT carrying claims
+func (i *testIssuer) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestAuthenticator(t *testing.T) {
+	issuer := newTestIssuer(t)
+	now := time.Now().Unix()
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":   issuer.server.URL,
+			"sub":   "client-1",
+			"aud":   "agent",
+			"exp":   now + 300,
+			"scope": "a2a.read a2a.write",
+		}
+	}
+	withClaim := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	apiKeyConfig := SecurityConfig{APIKeyHeader: "X-API-Key"}
+	oauth2Config := SecurityConfig{
+		OAuth2TokenURL: "https://auth.example.com/token",
+		OAuth2JWKSURL:  issuer.server.URL + "/jwks",
+		OAuth2Issuer:   issuer.server.URL,
+		OAuth2Scopes:   []string{"a2a.write"},
+		Audience:       "agent",
+	}
+	oidcConfig := SecurityConfig{
+		OIDCMetadataURL: issuer.server.URL + "/.well-known/openid-configuration",
+		Audience:        "agent",
+	}
+
+	tests := []struct {
+		name          string
+		config        SecurityConfig
+		headers       map[string]string
+		expectScheme  string
+		expectSubject string
+		expectError   bool
+	}{
+		{name: "no schemes accepts anything", config: SecurityConfig{}, headers: map[string]string{}},
+		{
+			name:          "api key",
+			config:        apiKeyConfig,
+			headers:       map[string]string{"x-api-key": "secret"},
+			expectScheme:  SecuritySchemeAPIKey,
+			expectSubject: SecuritySchemeAPIKey,
+		},
+		{name: "wrong api key", config: apiKeyConfig, headers: map[string]string{"X-API-Key": "guess"}, expectError: true},
+		{name: "missing credential", config: apiKeyConfig, headers: map[string]string{}, expectError: true},
+		{
+			name:          "oauth2 token",
+			config:        oauth2Config,
+			headers:       map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", validClaims())},
+			expectScheme:  SecuritySchemeOAuth2,
+			expectSubject: "client-1",
+		},
+		{
+			name:          "oidc discovers jwks and issuer",
+			config:        oidcConfig,
+			headers:       map[string]string{"authorization": "bearer " + issuer.sign(t, "test-key", validClaims())},
+			expectScheme:  SecuritySchemeOIDC,
+			expectSubject: "client-1",
+		},
+		{
+			name:        "expired token",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("exp", now-600))},
+			expectError: true,
+		},
+		{
+			name:        "token without expiry",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("exp", nil))},
+			expectError: true,
+		},
+		{
+			name:        "wrong audience",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("aud", "other"))},
+			expectError: true,
+		},
+		{
+			name:        "wrong issuer",
+			config:      oidcConfig,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("iss", "https://evil.example.com"))},
+			expectError: true,
+		},
+		{
+			name:        "missing scope",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("scope", "a2a.read"))},
+			expectError: true,
+		},
+		{
+			name:        "unknown key",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "other-key", validClaims())},
+			expectError: true,
+		},
+		{
+			name:        "tampered token",
+			config:      oauth2Config,
+			headers:     map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", validClaims()) + "x"},
+			expectError: true,
+		},
+		{name: "not a jwt", config: oauth2Config, headers: map[string]string{"Authorization": "Bearer opaque"}, expectError: true},
+		{
+			name: "either scheme may accept",
+			config: SecurityConfig{
+				APIKeyHeader:    "X-API-Key",
+				OIDCMetadataURL: oidcConfig.OIDCMetadataURL,
+			},
+			headers:       map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", withClaim("aud", nil))},
+			expectScheme:  SecuritySchemeOIDC,
+			expectSubject: "client-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := NewAuthenticator(tt.config, SecretsConfig{APIKey: "secret"}, issuer.server.Client())
+			principal, err := authenticator.Authenticate(context.Background(), tt.headers, nil)
+			if tt.expectError {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("expected ErrUnauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.Scheme != tt.expectScheme || principal.Subject != tt.expectSubject {
+				t.Errorf("expected %s/%s, got %+v", tt.expectScheme, tt.expectSubject, principal)
+			}
+		})
+	}
+}
+
+func TestAuthenticateSignature(t *testing.T) {
+	now := time.Unix(1754049600, 0)
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":{"id":"task-1"}}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signed := func(key string, at time.Time, body []byte) map[string]string {
+		return map[string]string{
+			"x-signature":           SignRequest(key, at.Unix(), body),
+			"x-signature-timestamp": strconv.FormatInt(at.Unix(), 10),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		config      SecurityConfig
+		headers     map[string]string
+		body        []byte
+		expectError bool
+	}{
+		{name: "valid signature", headers: signed("signing-key", now, body), body: body},
+		{name: "within tolerance", headers: signed("signing-key", now.Add(-4*time.Minute), body), body: body},
+		{name: "custom tolerance", config: SecurityConfig{SignatureTolerance: "30s"}, headers: signed("signing-key", now.Add(-time.Minute), body), body: body, expectError: true},
+		{name: "expired", headers: signed("signing-key", now.Add(-6*time.Minute), body), body: body, expectError: true},
+		{name: "from the future", headers: signed("signing-key", now.Add(6*time.Minute), body), body: body, expectError: true},
+		{name: "wrong key", headers: signed("guess", now, body), body: body, expectError: true},
+		{name: "tampered body", headers: signed("signing-key", now, body), body: []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/cancel","params":{"id":"task-1"}}`), expectError: true},
+		{name: "missing timestamp", headers: map[string]string{"X-Signature": SignRequest("signing-key", now.Unix(), body)}, body: body, expectError: true},
+		{name: "timestamp not signed", headers: map[string]string{"X-Signature": SignPayload("signing-key", body), "X-Signature-Timestamp": timestamp}, body: body, expectError: true},
+		{name: "invalid timestamp", headers: map[string]string{"X-Signature": SignRequest("signing-key", now.Unix(), body), "X-Signature-Timestamp": "yesterday"}, body: body, expectError: true},
+		{name: "unsigned", headers: map[string]string{}, body: body, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.SignatureHeader = "X-Signature"
+			authenticator := NewAuthenticator(tt.config, SecretsConfig{RequestSigningKey: "signing-key"}, nil)
+			authenticator.signature.now = func() time.Time { return now }
+
+			principal, err := authenticator.Authenticate(context.Background(), tt.headers, tt.body)
+			if tt.expectError {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("expected ErrUnauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if principal.Scheme != SecuritySchemeSignature || principal.Subject != SecuritySchemeSignature {
+				t.Errorf("expected the signature principal, got %+v", principal)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorNil(t *testing.T) {
+	// A handler built without security config passes a nil authenticator
+	var authenticator *Authenticator
+	if authenticator.Enabled() || authenticator.AcceptsBearer() || authenticator.APIKeyHeader() != "" {
+		t.Error("nil authenticator should have no schemes")
+	}
+	if _, err := authenticator.Authenticate(context.Background(), nil, nil); err != nil {
+		t.Errorf("nil authenticator should accept requests, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// SecuritySchemeIAM is the scheme of callers API Gateway authenticated with
+// IAM authorization. It is enforced by the gateway, not published on the card.
+const SecuritySchemeIAM = "iam"
+
+// TaskOwnerMetadataKey is the task metadata key holding the subject of the
+// caller that created the task. It is kept for the handler's own checks and
+// left off every task sent out, by PublicTask.
+const TaskOwnerMetadataKey = "owner"
+
+// PublicTask returns task without its owner, for a task leaving the
+// handler: the subject is a caller's identity, which neither the caller's
+// clients, SSE streams nor third-party webhooks are to see
+func PublicTask(task a2a.Task) a2a.Task {
+	if _, ok := task.Metadata[TaskOwnerMetadataKey]; !ok {
+		return task
+	}
+	// The stored task's metadata may be shared with the caller's copy
+	task.Metadata = maps.Clone(task.Metadata)
+	delete(task.Metadata, TaskOwnerMetadataKey)
+	return task
+}
+
+// PublicEvent is PublicTask for an event, which may be a whole task
+func PublicEvent(event a2a.Event) a2a.Event {
+	if task, ok := event.(a2a.Task); ok {
+		return PublicTask(task)
+	}
+	return event
+}
+
+// SkillMetadataKey is the message metadata key naming the skill a message
+// is for
+const SkillMetadataKey = "skillId"
+
+// RolesClaim is the token claim listing the caller's roles
+const RolesClaim = "roles"
+
+// MethodPolicy grants JSON-RPC methods to the callers it matches. A caller
+// matches when it meets every condition the policy sets.
+type MethodPolicy struct {
+	// Subjects match the caller's subject: a JWT sub, an IAM ARN, or apiKey
+	// and signature for those schemes. A trailing * matches any suffix, e.g.
+	// arn:aws:iam::123456789012:role/*.
+	Subjects []string `json:"subjects,omitempty"`
+	// Schemes restrict the policy to callers authenticated by these schemes
+	Schemes []string `json:"schemes,omitempty"`
+	// Claims must each match a claim of the caller's token. A claim holding a
+	// list matches when it contains the value.
+	Claims map[string]string `json:"claims,omitempty"`
+	// Scopes must all be granted to the caller's token
+	Scopes []string `json:"scopes,omitempty"`
+	// Methods are the methods granted; * grants every method
+	Methods []string `json:"methods"`
+}
+
+// parseMethodPolicies decodes the JSON array in A2A_AUTH_METHOD_POLICIES
+func parseMethodPolicies(value string) ([]MethodPolicy, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var policies []MethodPolicy
+	if err := json.Unmarshal([]byte(value), &policies); err != nil {
+		return nil, fmt.Errorf("A2A_AUTH_METHOD_POLICIES must be a JSON array of policies: %w", err)
+	}
+	return policies, nil
+}
+
+// securitySchemeNames are the schemes a caller can be authenticated by
+var securitySchemeNames = []string{SecuritySchemeAPIKey, SecuritySchemeOAuth2, SecuritySchemeOIDC, SecuritySchemeSignature, SecuritySchemeIAM}
+
+// validateMethodPolicies checks each policy grants something to someone it
+// can recognize
+func validateMethodPolicies(policies []MethodPolicy) error {
+	var errs ValidationErrors
+	for i, policy := range policies {
+		path := fmt.Sprintf("method_policies[%d]", i)
+		if len(policy.Methods) == 0 {
+			errs.Add(path+".methods", ValidationCodeRequired, "is required")
+		}
+		for j, method := range policy.Methods {
+			if strings.TrimSpace(method) == "" {
+				errs.Add(fmt.Sprintf("%s.methods[%d]", path, j), ValidationCodeRequired, "must not be empty")
+			}
+		}
+		for j, scheme := range policy.Schemes {
+			if !slices.Contains(securitySchemeNames, scheme) {
+				errs.Add(fmt.Sprintf("%s.schemes[%d]", path, j), ValidationCodeInvalid,
+					fmt.Sprintf("'%s' must be one of %s", scheme, strings.Join(securitySchemeNames, ", ")))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// matches reports whether principal meets every condition of the policy
+func (p MethodPolicy) matches(principal Principal) bool {
+	if len(p.Subjects) > 0 && !slices.ContainsFunc(p.Subjects, func(pattern string) bool {
+		return matchesSubject(pattern, principal.Subject)
+	}) {
+		return false
+	}
+	if len(p.Schemes) > 0 && !slices.Contains(p.Schemes, principal.Scheme) {
+		return false
+	}
+	for _, scope := range p.Scopes {
+		if !slices.Contains(principal.Scopes, scope) {
+			return false
+		}
+	}
+	for name, want := range p.Claims {
+		if !slices.Contains(claimValues(principal.Claims[name]), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// grants reports whether the policy lists method
+func (p MethodPolicy) grants(method string) bool {
+	return slices.Contains(p.Methods, "*") || slices.Contains(p.Methods, method)
+}
+
+// matchesSubject compares a subject to a pattern with an optional trailing *
+func matchesSubject(pattern, subject string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return subject != "" && strings.HasPrefix(subject, prefix)
+	}
+	return subject == pattern
+}
+
+// claimValues reads a claim as a list of strings. A string claim is one
+// value; numbers and booleans are compared as written.
+func claimValues(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+	return []string{string(raw)}
+}
+
+// IAMPrincipal is the caller API Gateway verified with IAM authorization
+func IAMPrincipal(callerARN string) Principal {
+	return Principal{Scheme: SecuritySchemeIAM, Subject: callerARN}
+}
+
+// AcceptsIAM reports whether callers authenticated by API Gateway IAM
+// authorization are accepted
+func (a *Authenticator) AcceptsIAM() bool {
+	return a != nil && a.iam
+}
+
+// AuthorizeMethod reports whether principal may call method. Without method
+// policies every authenticated caller may call every method. Admin methods
+// are not served to these callers at all, see AdminAuthenticator.
+func (a *Authenticator) AuthorizeMethod(principal Principal, method string) bool {
+	if a == nil || len(a.methodPolicies) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(a.methodPolicies, func(policy MethodPolicy) bool {
+		return policy.matches(principal) && policy.grants(method)
+	})
+}
+
+// OwnTasksOnly reports whether callers are limited to the tasks they
+// created, so handlers know to load a task before acting on it
+func (a *Authenticator) OwnTasksOnly() bool {
+	return a != nil && a.ownTasksOnly
+}
+
+// CanAccessTask reports whether principal may read, cancel or continue task.
+// With own_tasks_only set only the caller that created a task, or an admin,
+// may; a task created without an authenticated caller has no owner.
+func (a *Authenticator) CanAccessTask(principal Principal, task a2a.Task) bool {
+	if a == nil || !a.ownTasksOnly || a.IsAdmin(principal) {
+		return true
+	}
+	owner, _ := task.Metadata[TaskOwnerMetadataKey].(string)
+	return owner != "" && owner == principal.Subject
+}
+
+// CanUseSkill reports whether principal may use skill. A skill without
+// security requirements is open to every caller. Otherwise the caller must
+// meet one of them: a requirement such as {"oauth2": ["billing.write"]}
+// is met by a caller authenticated by that scheme whose token grants every
+// value listed as a scope or lists it in its roles claim. A requirement
+// naming several schemes can never be met, since a request carries one
+// credential.
+func (a *Authenticator) CanUseSkill(principal Principal, skill a2a.AgentSkill) bool {
+	if len(skill.Security) == 0 {
+		return true
+	}
+	roles := claimValues(principal.Claims[RolesClaim])
+	return slices.ContainsFunc(skill.Security, func(requirement map[string][]string) bool {
+		for scheme, values := range requirement {
+			if scheme != principal.Scheme {
+				return false
+			}
+			for _, value := range values {
+				if !slices.Contains(principal.Scopes, value) && !slices.Contains(roles, value) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// SkillsFor returns the skills principal may use, for the card shown to it
+func (a *Authenticator) SkillsFor(principal Principal, skills []a2a.AgentSkill) []a2a.AgentSkill {
+	usable := make([]a2a.AgentSkill, 0, len(skills))
+	for _, skill := range skills {
+		if a.CanUseSkill(principal, skill) {
+			usable = append(usable, skill)
+		}
+	}
+	return usable
+}
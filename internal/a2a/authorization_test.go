@@ -0,0 +1,188 @@
+package a2a
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestAuthorizeMethod(t *testing.T) {
+	policies := []MethodPolicy{
+		{Subjects: []string{"arn:aws:iam::123456789012:role/reader-*"}, Methods: []string{"tasks/get"}},
+		{Schemes: []string{SecuritySchemeOIDC}, Claims: map[string]string{"groups": "agents"}, Methods: []string{"message/send", "tasks/get"}},
+		{Schemes: []string{SecuritySchemeOAuth2}, Scopes: []string{"a2a.admin"}, Methods: []string{"*"}},
+	}
+	authenticator := NewAuthenticator(SecurityConfig{IAM: true, MethodPolicies: policies}, SecretsConfig{}, nil)
+
+	groups := func(values string) map[string]json.RawMessage {
+		return map[string]json.RawMessage{"groups": json.RawMessage(values)}
+	}
+	tests := []struct {
+		name      string
+		principal Principal
+		method    string
+		expect    bool
+	}{
+		{name: "subject prefix", principal: IAMPrincipal("arn:aws:iam::123456789012:role/reader-billing"), method: "tasks/get", expect: true},
+		{name: "method not granted to subject", principal: IAMPrincipal("arn:aws:iam::123456789012:role/reader-billing"), method: "tasks/cancel"},
+		{name: "subject outside prefix", principal: IAMPrincipal("arn:aws:iam::123456789012:role/writer"), method: "tasks/get"},
+		{name: "claim in a list", principal: Principal{Scheme: SecuritySchemeOIDC, Subject: "user-1", Claims: groups(`["staff","agents"]`)}, method: "message/send", expect: true},
+		{name: "claim as a string", principal: Principal{Scheme: SecuritySchemeOIDC, Subject: "user-1", Claims: groups(`"agents"`)}, method: "tasks/get", expect: true},
+		{name: "claim missing", principal: Principal{Scheme: SecuritySchemeOIDC, Subject: "user-1", Claims: groups(`["staff"]`)}, method: "message/send"},
+		{name: "claim from another scheme", principal: Principal{Scheme: SecuritySchemeOAuth2, Subject: "user-1", Claims: groups(`"agents"`)}, method: "message/send"},
+		{name: "wildcard with scope", principal: Principal{Scheme: SecuritySchemeOAuth2, Subject: "ops", Scopes: []string{"a2a.admin"}}, method: "admin/audit/list", expect: true},
+		{name: "unauthenticated", principal: Principal{}, method: "tasks/get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authenticator.AuthorizeMethod(tt.principal, tt.method); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+
+	// Without policies every method is granted
+	if !NewAuthenticator(SecurityConfig{APIKeyHeader: "X-API-Key"}, SecretsConfig{APIKey: "secret"}, nil).AuthorizeMethod(Principal{}, "tasks/cancel") {
+		t.Error("expected every method to be granted without policies")
+	}
+}
+
+func TestCanAccessTask(t *testing.T) {
+	config := SecurityConfig{APIKeyHeader: "X-API-Key", AdminSubjects: []string{"ops"}, OwnTasksOnly: true}
+	authenticator := NewAuthenticator(config, SecretsConfig{APIKey: "secret"}, nil)
+	owned := a2a.Task{ID: "task-1", Metadata: map[string]any{TaskOwnerMetadataKey: "client-1"}}
+	unowned := a2a.Task{ID: "task-2"}
+
+	tests := []struct {
+		name    string
+		subject string
+		task    a2a.Task
+		expect  bool
+	}{
+		{name: "owner", subject: "client-1", task: owned, expect: true},
+		{name: "another caller", subject: "client-2", task: owned},
+		{name: "admin", subject: "ops", task: owned, expect: true},
+		{name: "task without an owner", subject: "client-1", task: unowned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authenticator.CanAccessTask(Principal{Scheme: SecuritySchemeOIDC, Subject: tt.subject}, tt.task); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+
+	config.OwnTasksOnly = false
+	if !NewAuthenticator(config, SecretsConfig{APIKey: "secret"}, nil).CanAccessTask(Principal{Subject: "client-2"}, owned) {
+		t.Error("expected every task to be accessible without own_tasks_only")
+	}
+}
+
+func TestPublicTask(t *testing.T) {
+	task := a2a.Task{ID: "task-1", Metadata: map[string]any{TaskOwnerMetadataKey: "client-1", "team": "blue"}}
+
+	public := PublicTask(task)
+	if _, ok := public.Metadata[TaskOwnerMetadataKey]; ok {
+		t.Errorf("expected the owner to be removed, got %v", public.Metadata)
+	}
+	if public.Metadata["team"] != "blue" {
+		t.Errorf("expected other metadata to be kept, got %v", public.Metadata)
+	}
+	if task.Metadata[TaskOwnerMetadataKey] != "client-1" {
+		t.Error("expected the stored task to keep its owner")
+	}
+
+	event, ok := PublicEvent(task).(a2a.Task)
+	if !ok || event.Metadata[TaskOwnerMetadataKey] != nil {
+		t.Errorf("expected a task event without its owner, got %#v", event)
+	}
+}
+
+func TestValidateSecurityConfigAuthorization(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      SecurityConfig
+		expectError string
+	}{
+		{name: "policies with iam", config: SecurityConfig{IAM: true, MethodPolicies: []MethodPolicy{{Schemes: []string{SecuritySchemeIAM}, Methods: []string{"tasks/get"}}}, OwnTasksOnly: true}},
+		{name: "policies without a scheme", config: SecurityConfig{MethodPolicies: []MethodPolicy{{Methods: []string{"*"}}}}, expectError: "method_policies require an authentication scheme"},
+		{name: "own tasks without a scheme", config: SecurityConfig{OwnTasksOnly: true}, expectError: "own_tasks_only requires an authentication scheme"},
+		{name: "policy without methods", config: SecurityConfig{IAM: true, MethodPolicies: []MethodPolicy{{Subjects: []string{"arn:*"}}}}, expectError: "method_policies[0].methods is required"},
+		{name: "unknown scheme", config: SecurityConfig{IAM: true, MethodPolicies: []MethodPolicy{{Schemes: []string{"basic"}, Methods: []string{"*"}}}}, expectError: "method_policies[0].schemes[0] 'basic' must be one of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecurityConfig(tt.config)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestLoadSecurityConfigFromEnvAuthorization(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	os.Setenv("A2A_AUTH_IAM", "true")
+	os.Setenv("A2A_AUTH_OWN_TASKS_ONLY", "true")
+	os.Setenv("A2A_AUTH_METHOD_POLICIES", `[{"subjects":["arn:aws:iam::123456789012:role/*"],"methods":["tasks/get"]}]`)
+
+	config, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.IAM || !config.OwnTasksOnly || len(config.MethodPolicies) != 1 || config.MethodPolicies[0].Methods[0] != "tasks/get" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+
+	os.Setenv("A2A_AUTH_METHOD_POLICIES", `{"methods":["*"]}`)
+	if _, err := LoadSecurityConfigFromEnv(); err == nil {
+		t.Error("expected an error for policies that are not an array")
+	}
+}
+
+func TestCanUseSkill(t *testing.T) {
+	authenticator := NewAuthenticator(SecurityConfig{IAM: true}, SecretsConfig{}, nil)
+	skill := a2a.AgentSkill{ID: "billing", Security: []map[string][]string{
+		{SecuritySchemeOAuth2: {"billing.write"}},
+		{SecuritySchemeOIDC: {"billing-admin"}},
+		{SecuritySchemeIAM: {}},
+	}}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		skill     a2a.AgentSkill
+		expect    bool
+	}{
+		{name: "unrestricted skill", principal: Principal{}, skill: a2a.AgentSkill{ID: "open"}, expect: true},
+		{name: "scope granted", principal: Principal{Scheme: SecuritySchemeOAuth2, Scopes: []string{"billing.read", "billing.write"}}, skill: skill, expect: true},
+		{name: "scope missing", principal: Principal{Scheme: SecuritySchemeOAuth2, Scopes: []string{"billing.read"}}, skill: skill},
+		{name: "role in claim", principal: Principal{Scheme: SecuritySchemeOIDC, Claims: map[string]json.RawMessage{RolesClaim: json.RawMessage(`["billing-admin"]`)}}, skill: skill, expect: true},
+		{name: "role from another scheme", principal: Principal{Scheme: SecuritySchemeOAuth2, Claims: map[string]json.RawMessage{RolesClaim: json.RawMessage(`["billing-admin"]`)}}, skill: skill},
+		{name: "scheme alone", principal: IAMPrincipal("arn:aws:iam::123456789012:role/billing"), skill: skill, expect: true},
+		{name: "unauthenticated", principal: Principal{}, skill: skill},
+		{name: "several schemes in one requirement", principal: Principal{Scheme: SecuritySchemeAPIKey}, skill: a2a.AgentSkill{Security: []map[string][]string{{SecuritySchemeAPIKey: {}, SecuritySchemeIAM: {}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authenticator.CanUseSkill(tt.principal, tt.skill); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+
+	usable := authenticator.SkillsFor(Principal{}, []a2a.AgentSkill{{ID: "open"}, skill})
+	if len(usable) != 1 || usable[0].ID != "open" {
+		t.Errorf("expected only the open skill, got %v", usable)
+	}
+}
@@ -0,0 +1,109 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3ArchiveAPI is the subset of the S3 client used to write, read and
+// purge archived tasks
+type S3ArchiveAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3TaskArchive implements TaskArchive with one JSON object per task under
+// a prefix
+type S3TaskArchive struct {
+	client    S3ArchiveAPI
+	bucket    string
+	prefix    string
+	keyPrefix string
+}
+
+// NewS3TaskArchive creates an archive writing to an s3://bucket/prefix URI.
+// keyPrefix namespaces object names as for NewAWSTaskStore.
+func NewS3TaskArchive(client S3ArchiveAPI, uri string, keyPrefix string) (*S3TaskArchive, error) {
+	bucket, prefix, err := parseS3Location(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &S3TaskArchive{client: client, bucket: bucket, prefix: prefix, keyPrefix: keyPrefix}, nil
+}
+
+// archiveKey names a task's object. IDs are escaped rather than sanitized,
+// so two tasks never share an object.
+func (s *S3TaskArchive) archiveKey(taskID a2a.TaskID) string {
+	return path.Join(s.prefix, url.PathEscape(s.keyPrefix+string(taskID))+".json")
+}
+
+// PutArchivedTask implements TaskArchive
+func (s *S3TaskArchive) PutArchivedTask(ctx context.Context, archived ArchivedTask) error {
+	defer observeStorage(ctx, "PutArchivedTask", time.Now())
+
+	data, err := json.Marshal(archived)
+	if err != nil {
+		return fmt.Errorf("failed to marshal archived task: %w", err)
+	}
+	key := s.archiveKey(archived.Task.ID)
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put archived task s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
+
+// GetArchivedTask implements TaskArchive
+func (s *S3TaskArchive) GetArchivedTask(ctx context.Context, taskID a2a.TaskID) (ArchivedTask, error) {
+	defer observeStorage(ctx, "GetArchivedTask", time.Now())
+
+	key := s.archiveKey(taskID)
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return ArchivedTask{}, fmt.Errorf("task %s: %w", taskID, a2a.ErrTaskNotFound)
+		}
+		return ArchivedTask{}, fmt.Errorf("failed to get archived task s3://%s/%s: %w", s.bucket, key, err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return ArchivedTask{}, fmt.Errorf("failed to read archived task s3://%s/%s: %w", s.bucket, key, err)
+	}
+	var archived ArchivedTask
+	if err := json.Unmarshal(data, &archived); err != nil {
+		return ArchivedTask{}, fmt.Errorf("failed to unmarshal archived task: %w", err)
+	}
+	return archived, nil
+}
+
+// DeleteArchivedTask implements TaskArchive
+func (s *S3TaskArchive) DeleteArchivedTask(ctx context.Context, taskID a2a.TaskID) error {
+	defer observeStorage(ctx, "DeleteArchivedTask", time.Now())
+
+	key := s.archiveKey(taskID)
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("failed to delete archived task s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
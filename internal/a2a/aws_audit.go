@@ -0,0 +1,179 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	firehosetypes "github.com/aws/aws-sdk-go-v2/service/firehose/types"
+)
+
+// auditNoTask partitions records of calls that named no task, such as a
+// message/send that failed before creating one
+const auditNoTask = "-"
+
+// AWSAuditLog implements AuditReader using DynamoDB. Records are keyed by
+// task (audit_key) and time (recorded_at), with a subject-index GSI on
+// subject and recorded_at for listing a caller's operations.
+type AWSAuditLog struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSAuditLog creates a DynamoDB audit log. keyPrefix namespaces keys as
+// for NewAWSTaskStore.
+func NewAWSAuditLog(client DynamoDBAPI, tableName string, keyPrefix string) *AWSAuditLog {
+	return &AWSAuditLog{
+		client:    client,
+		tableName: tableName,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// RecordAudit writes an audit record to DynamoDB
+func (l *AWSAuditLog) RecordAudit(ctx context.Context, record AuditRecord) error {
+	defer observeStorage(ctx, "RecordAudit", time.Now())
+
+	recordData, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	item := l.key(record)
+	item["record"] = &types.AttributeValueMemberS{Value: string(recordData)}
+	// Unauthenticated calls stay out of the subject index
+	if record.Subject != "" {
+		item["subject"] = &types.AttributeValueMemberS{Value: l.keyPrefix + record.Subject}
+	}
+
+	_, err = l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save audit record to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// key returns the table key of a record
+func (l *AWSAuditLog) key(record AuditRecord) map[string]types.AttributeValue {
+	taskKey := record.TaskID
+	if taskKey == "" {
+		taskKey = auditNoTask
+	}
+	return map[string]types.AttributeValue{
+		"audit_key": &types.AttributeValueMemberS{Value: l.keyPrefix + taskKey},
+		// The request ID keeps two records in the same instant apart
+		"recorded_at": &types.AttributeValueMemberS{Value: record.Timestamp.UTC().Format(time.RFC3339Nano) + "#" + record.RequestID},
+	}
+}
+
+// DeleteAudit deletes records from DynamoDB
+func (l *AWSAuditLog) DeleteAudit(ctx context.Context, records []AuditRecord) error {
+	defer observeStorage(ctx, "DeleteAudit", time.Now())
+
+	for _, record := range records {
+		_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(l.tableName),
+			Key:       l.key(record),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete audit record from DynamoDB: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListAudit queries audit records for a task or a subject, newest first
+func (l *AWSAuditLog) ListAudit(ctx context.Context, query AuditQuery) ([]AuditRecord, error) {
+	defer observeStorage(ctx, "ListAudit", time.Now())
+
+	input := &dynamodb.QueryInput{
+		TableName:        aws.String(l.tableName),
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(auditLimit(query.Limit)),
+	}
+	switch {
+	case query.TaskID != "":
+		input.KeyConditionExpression = aws.String("audit_key = :key")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":key": &types.AttributeValueMemberS{Value: l.keyPrefix + query.TaskID},
+		}
+	case query.Subject != "":
+		input.IndexName = aws.String("subject-index")
+		input.KeyConditionExpression = aws.String("subject = :key")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":key": &types.AttributeValueMemberS{Value: l.keyPrefix + query.Subject},
+		}
+	default:
+		return nil, errors.New("task_id or subject is required")
+	}
+
+	result, err := l.client.Query(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit records from DynamoDB: %w", err)
+	}
+
+	records := make([]AuditRecord, 0, len(result.Items))
+	for _, item := range result.Items {
+		recordAttr, ok := item["record"].(*types.AttributeValueMemberS)
+		if !ok {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal([]byte(recordAttr.Value), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// FirehoseAuditLog implements AuditLog by streaming records to Kinesis Data
+// Firehose, one JSON line each, for delivery to S3 or a SIEM
+type FirehoseAuditLog struct {
+	client     FirehoseAPI
+	streamName string
+	agentID    string
+}
+
+// NewFirehoseAuditLog creates a Firehose audit log. agentID is added to each
+// line, since agents may share a delivery stream.
+func NewFirehoseAuditLog(client FirehoseAPI, streamName string, agentID string) *FirehoseAuditLog {
+	return &FirehoseAuditLog{
+		client:     client,
+		streamName: streamName,
+		agentID:    agentID,
+	}
+}
+
+// RecordAudit puts an audit record on the delivery stream
+func (l *FirehoseAuditLog) RecordAudit(ctx context.Context, record AuditRecord) error {
+	defer observeStorage(ctx, "RecordAudit", time.Now())
+
+	line, err := json.Marshal(struct {
+		AgentID string `json:"agent_id,omitempty"`
+		AuditRecord
+	}{l.agentID, record})
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	// Firehose concatenates records, so each needs its own line for Athena
+	_, err = l.client.PutRecord(ctx, &firehose.PutRecordInput{
+		DeliveryStreamName: aws.String(l.streamName),
+		Record:             &firehosetypes.Record{Data: append(line, '\n')},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put audit record to Firehose: %w", err)
+	}
+	return nil
+}
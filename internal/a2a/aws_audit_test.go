@@ -0,0 +1,75 @@
+package a2a
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAWSAuditLogKeys(t *testing.T) {
+	ctx := context.Background()
+	recordedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		record        AuditRecord
+		query         AuditQuery
+		expectTaskKey string
+		expectSubject string
+		expectIndex   string
+		expectQuery   string
+	}{
+		{
+			name:          "call on a task",
+			record:        AuditRecord{Timestamp: recordedAt, RequestID: "req-1", Subject: "ops@example.com", Method: "tasks/get", TaskID: "task-1", Outcome: AuditOutcomeOK},
+			query:         AuditQuery{TaskID: "task-1"},
+			expectTaskKey: "billing#task-1",
+			expectSubject: "billing#ops@example.com",
+			expectQuery:   "billing#task-1",
+		},
+		{
+			name:          "unauthenticated call without a task",
+			record:        AuditRecord{Timestamp: recordedAt, Method: "message/send", Outcome: AuditOutcomeError},
+			query:         AuditQuery{Subject: "ops@example.com"},
+			expectTaskKey: "billing#-",
+			expectIndex:   "subject-index",
+			expectQuery:   "billing#ops@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := recordingDynamoDB(t)
+			auditLog := NewAWSAuditLog(client, "audit", "billing#")
+
+			if err := auditLog.RecordAudit(ctx, tt.record); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := auditLog.ListAudit(ctx, tt.query); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			put, query := (*requests)[0], (*requests)[1]
+			if got := attributeS(put, "Item", "audit_key"); got != tt.expectTaskKey {
+				t.Errorf("expected audit_key %q, got %q", tt.expectTaskKey, got)
+			}
+			if got := attributeS(put, "Item", "subject"); got != tt.expectSubject {
+				t.Errorf("expected subject %q, got %q", tt.expectSubject, got)
+			}
+			if got, _ := query["IndexName"].(string); got != tt.expectIndex {
+				t.Errorf("expected index %q, got %q", tt.expectIndex, got)
+			}
+			if got := attributeS(query, "ExpressionAttributeValues", ":key"); got != tt.expectQuery {
+				t.Errorf("expected query key %q, got %q", tt.expectQuery, got)
+			}
+			if query["ScanIndexForward"] != false || query["Limit"] != float64(DefaultAuditLimit) {
+				t.Errorf("expected newest %d records first, got %v", DefaultAuditLimit, query)
+			}
+		})
+	}
+
+	client, _ := recordingDynamoDB(t)
+	if _, err := NewAWSAuditLog(client, "audit", "").ListAudit(ctx, AuditQuery{}); err == nil {
+		t.Error("expected error for a query without task_id or subject")
+	}
+}
@@ -0,0 +1,229 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// maxCaptureItemBytes keeps a captured exchange under DynamoDB's 400 KB item
+// limit, leaving room for the key and TTL attributes
+const maxCaptureItemBytes = 390 << 10
+
+// AWSCaptureStore implements CaptureStore using DynamoDB. Items are keyed by
+// request_id and carry an expires_at number attribute, which should be the
+// table's TTL attribute.
+type AWSCaptureStore struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+	ttl       time.Duration
+	runtimeDeps
+}
+
+// NewAWSCaptureStore creates a DynamoDB capture store keeping exchanges for
+// ttl. keyPrefix namespaces keys as for NewAWSTaskStore.
+func NewAWSCaptureStore(client DynamoDBAPI, tableName string, keyPrefix string, ttl time.Duration, opts ...RuntimeOption) *AWSCaptureStore {
+	return &AWSCaptureStore{
+		client:      client,
+		tableName:   tableName,
+		keyPrefix:   keyPrefix,
+		ttl:         ttl,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// SaveCapture writes an exchange to DynamoDB. Exchanges too large for an
+// item are rejected; capture to S3 to keep those.
+func (s *AWSCaptureStore) SaveCapture(ctx context.Context, exchange CapturedExchange) error {
+	defer observeStorage(ctx, "SaveCapture", time.Now())
+
+	exchangeData, err := json.Marshal(exchange)
+	if err != nil {
+		return fmt.Errorf("failed to marshal captured exchange: %w", err)
+	}
+	if len(exchangeData) > maxCaptureItemBytes {
+		return fmt.Errorf("captured exchange of %d bytes is too large for DynamoDB; use A2A_CAPTURE_S3_URI", len(exchangeData))
+	}
+
+	expiresAt := exchange.CapturedAt.Add(s.ttl).Unix()
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]types.AttributeValue{
+			"request_id": &types.AttributeValueMemberS{Value: s.keyPrefix + exchange.RequestID},
+			"exchange":   &types.AttributeValueMemberS{Value: string(exchangeData)},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save captured exchange to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// GetCapture reads an exchange from DynamoDB. DynamoDB deletes expired items
+// lazily, so expiry is checked here too.
+func (s *AWSCaptureStore) GetCapture(ctx context.Context, requestID string) (CapturedExchange, error) {
+	defer observeStorage(ctx, "GetCapture", time.Now())
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"request_id": &types.AttributeValueMemberS{Value: s.keyPrefix + requestID},
+		},
+	})
+	if err != nil {
+		return CapturedExchange{}, fmt.Errorf("failed to get captured exchange from DynamoDB: %w", err)
+	}
+	if result.Item == nil {
+		return CapturedExchange{}, fmt.Errorf("request %s: %w", requestID, ErrCaptureNotFound)
+	}
+
+	if expiresAttr, ok := result.Item["expires_at"].(*types.AttributeValueMemberN); ok {
+		expiresAt, err := strconv.ParseInt(expiresAttr.Value, 10, 64)
+		if err == nil && s.clock.Now().Unix() >= expiresAt {
+			return CapturedExchange{}, fmt.Errorf("request %s: %w", requestID, ErrCaptureNotFound)
+		}
+	}
+
+	exchangeAttr, ok := result.Item["exchange"].(*types.AttributeValueMemberS)
+	if !ok {
+		return CapturedExchange{}, fmt.Errorf("exchange not found in DynamoDB item")
+	}
+	var exchange CapturedExchange
+	if err := UnmarshalJSONString(exchangeAttr.Value, &exchange); err != nil {
+		return CapturedExchange{}, fmt.Errorf("failed to unmarshal captured exchange: %w", err)
+	}
+	return exchange, nil
+}
+
+// DeleteCapture deletes a request's capture, if there is one, for purges
+func (s *AWSCaptureStore) DeleteCapture(ctx context.Context, requestID string) error {
+	defer observeStorage(ctx, "DeleteCapture", time.Now())
+
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"request_id": &types.AttributeValueMemberS{Value: s.keyPrefix + requestID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete captured exchange from DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// S3CaptureAPI is the subset of the S3 client used to write, read and
+// purge captures
+type S3CaptureAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3CaptureStore implements CaptureStore with one object per request under a
+// prefix. S3 cannot expire single objects, so a lifecycle rule on the prefix
+// should delete them; reads ignore objects older than the TTL meanwhile.
+type S3CaptureStore struct {
+	client    S3CaptureAPI
+	bucket    string
+	prefix    string
+	keyPrefix string
+	ttl       time.Duration
+	runtimeDeps
+}
+
+// NewS3CaptureStore creates a capture store writing to an s3://bucket/prefix
+// URI. keyPrefix namespaces object names as for NewAWSTaskStore.
+func NewS3CaptureStore(client S3CaptureAPI, uri string, keyPrefix string, ttl time.Duration, opts ...RuntimeOption) (*S3CaptureStore, error) {
+	bucket, prefix, err := parseS3Location(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &S3CaptureStore{
+		client:      client,
+		bucket:      bucket,
+		prefix:      prefix,
+		keyPrefix:   keyPrefix,
+		ttl:         ttl,
+		runtimeDeps: newRuntimeDeps(opts),
+	}, nil
+}
+
+// captureKey names a request's object; request IDs are sanitized as for recordings
+func (s *S3CaptureStore) captureKey(requestID string) string {
+	return path.Join(s.prefix, unsafeKeyChars.ReplaceAllString(s.keyPrefix+requestID, "_")+".json")
+}
+
+// SaveCapture implements CaptureStore
+func (s *S3CaptureStore) SaveCapture(ctx context.Context, exchange CapturedExchange) error {
+	defer observeStorage(ctx, "SaveCapture", time.Now())
+
+	data, err := json.Marshal(exchange)
+	if err != nil {
+		return fmt.Errorf("failed to marshal captured exchange: %w", err)
+	}
+	key := s.captureKey(exchange.RequestID)
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put captured exchange s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
+
+// GetCapture implements CaptureStore
+func (s *S3CaptureStore) GetCapture(ctx context.Context, requestID string) (CapturedExchange, error) {
+	defer observeStorage(ctx, "GetCapture", time.Now())
+
+	key := s.captureKey(requestID)
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return CapturedExchange{}, fmt.Errorf("request %s: %w", requestID, ErrCaptureNotFound)
+		}
+		return CapturedExchange{}, fmt.Errorf("failed to get captured exchange s3://%s/%s: %w", s.bucket, key, err)
+	}
+	defer output.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(output.Body, maxRecordingBytes))
+	if err != nil {
+		return CapturedExchange{}, fmt.Errorf("failed to read captured exchange s3://%s/%s: %w", s.bucket, key, err)
+	}
+	var exchange CapturedExchange
+	if err := json.Unmarshal(data, &exchange); err != nil {
+		return CapturedExchange{}, fmt.Errorf("failed to unmarshal captured exchange: %w", err)
+	}
+	if !s.clock.Now().Before(exchange.CapturedAt.Add(s.ttl)) {
+		return CapturedExchange{}, fmt.Errorf("request %s: %w", requestID, ErrCaptureNotFound)
+	}
+	return exchange, nil
+}
+
+// DeleteCapture deletes a request's capture, if there is one, for purges
+func (s *S3CaptureStore) DeleteCapture(ctx context.Context, requestID string) error {
+	defer observeStorage(ctx, "DeleteCapture", time.Now())
+
+	key := s.captureKey(requestID)
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("failed to delete captured exchange s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// DynamoDBAPI is the subset of the DynamoDB client the stores use
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// SQSAPI is the subset of the SQS client used to enqueue push notifications,
+// replay dead-lettered ones and report queue depths
+type SQSAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// FirehoseAPI is the subset of the Firehose client used to stream audit records
+type FirehoseAPI interface {
+	PutRecord(ctx context.Context, params *firehose.PutRecordInput, optFns ...func(*firehose.Options)) (*firehose.PutRecordOutput, error)
+}
+
+// DataPlaneRoleSessionName names the sessions of an assumed data plane role
+// in the data account's CloudTrail
+const DataPlaneRoleSessionName = "a2a-serverless"
+
+// AWSDataPlaneConfig returns the SDK config for DynamoDB and SQS clients.
+// Explicit access keys and region in the serverless config override the
+// execution role, so a deployment can reach tables in another account. With
+// a role ARN, the clients assume that role instead, using the keys or the
+// execution role to do so, and refresh its credentials before they expire.
+// Secrets Manager keeps using base, since it resolves those keys.
+func AWSDataPlaneConfig(base aws.Config, config *AWSConfig) aws.Config {
+	cfg := base.Copy()
+	if config == nil {
+		return cfg
+	}
+	if config.Region != "" {
+		cfg.Region = config.Region
+	}
+	if config.AccessKeyID != "" && config.SecretAccessKey != "" {
+		cfg.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(config.AccessKeyID.Reveal(), config.SecretAccessKey.Reveal(), ""))
+	}
+	if config.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), config.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = DataPlaneRoleSessionName
+			if config.ExternalID != "" {
+				o.ExternalID = aws.String(config.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg
+}
+
+// AWSClients creates AWS service clients on first use rather than up front,
+// so an invocation that never reaches a service, such as an agent card
+// request, does not pay to build its client. All clients share one HTTP
+// client, and with it one connection pool.
+type AWSClients struct {
+	config         aws.Config
+	opts           []AWSClientsOption
+	dynamoDB       lazyClient[DynamoDBAPI]
+	sqs            lazyClient[SQSAPI]
+	firehose       lazyClient[FirehoseAPI]
+	s3             lazyClient[S3RecordingAPI]
+	secretsManager lazyClient[SecretsManagerAPI]
+	ssm            lazyClient[SSMParameterAPI]
+}
+
+// AWSClientsOption replaces a client AWSClients would build, so tests can
+// substitute a fake for a service
+type AWSClientsOption func(*AWSClients)
+
+// WithDynamoDBClient uses client for DynamoDB
+func WithDynamoDBClient(client DynamoDBAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.dynamoDB.build = func() DynamoDBAPI { return client } }
+}
+
+// WithSQSClient uses client for SQS
+func WithSQSClient(client SQSAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.sqs.build = func() SQSAPI { return client } }
+}
+
+// WithFirehoseClient uses client for Firehose
+func WithFirehoseClient(client FirehoseAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.firehose.build = func() FirehoseAPI { return client } }
+}
+
+// WithS3Client uses client for S3
+func WithS3Client(client S3RecordingAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.s3.build = func() S3RecordingAPI { return client } }
+}
+
+// WithSecretsManagerClient uses client for Secrets Manager
+func WithSecretsManagerClient(client SecretsManagerAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.secretsManager.build = func() SecretsManagerAPI { return client } }
+}
+
+// WithSSMClient uses client for SSM Parameter Store
+func WithSSMClient(client SSMParameterAPI) AWSClientsOption {
+	return func(c *AWSClients) { c.ssm.build = func() SSMParameterAPI { return client } }
+}
+
+// NewAWSClients creates the lazy clients for cfg. Creating them is cheap: no
+// client is built until its first call.
+func NewAWSClients(cfg aws.Config, opts ...AWSClientsOption) *AWSClients {
+	// Without an HTTP client in the config, each service would build its own
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = awshttp.NewBuildableClient()
+	}
+	c := &AWSClients{config: cfg, opts: opts}
+	c.dynamoDB.build = func() DynamoDBAPI { return dynamodb.NewFromConfig(c.config) }
+	c.sqs.build = func() SQSAPI { return sqs.NewFromConfig(c.config) }
+	c.firehose.build = func() FirehoseAPI { return firehose.NewFromConfig(c.config) }
+	c.s3.build = func() S3RecordingAPI { return s3.NewFromConfig(c.config) }
+	c.secretsManager.build = func() SecretsManagerAPI { return secretsmanager.NewFromConfig(c.config) }
+	c.ssm.build = func() SSMParameterAPI { return ssm.NewFromConfig(c.config) }
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// DataPlane returns the clients for a serverless config's AWS section, as
+// AWSDataPlaneConfig describes. They share this set's HTTP client and any
+// client it was given.
+func (c *AWSClients) DataPlane(config *AWSConfig) *AWSClients {
+	return NewAWSClients(AWSDataPlaneConfig(c.config, config), c.opts...)
+}
+
+// Warm builds the DynamoDB and SQS clients now rather than on their first
+// call. The Lambda entrypoint calls it during a provisioned concurrency init,
+// which no request waits on.
+func (c *AWSClients) Warm() {
+	c.dynamoDB.get()
+	c.sqs.get()
+}
+
+// DynamoDB returns a DynamoDB client built on its first call
+func (c *AWSClients) DynamoDB() DynamoDBAPI { return lazyDynamoDB{&c.dynamoDB} }
+
+// SQS returns an SQS client built on its first call
+func (c *AWSClients) SQS() SQSAPI { return lazySQS{&c.sqs} }
+
+// Firehose returns a Firehose client built on its first call
+func (c *AWSClients) Firehose() FirehoseAPI { return lazyFirehose{&c.firehose} }
+
+// S3API is the S3 client AWSClients hands out, which also deletes the
+// captures and archived tasks a purge erases
+type S3API interface {
+	S3RecordingAPI
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3 returns an S3 client built on its first call
+func (c *AWSClients) S3() S3API { return lazyS3{&c.s3} }
+
+// SecretsManager returns a Secrets Manager client built on its first call
+func (c *AWSClients) SecretsManager() SecretsManagerAPI { return lazySecretsManager{&c.secretsManager} }
+
+// SSM returns an SSM client built on its first call
+func (c *AWSClients) SSM() SSMParameterAPI { return lazySSM{&c.ssm} }
+
+// lazyClient builds a client once, when it is first needed
+type lazyClient[T any] struct {
+	once   sync.Once
+	build  func() T
+	client T
+}
+
+func (l *lazyClient[T]) get() T {
+	l.once.Do(func() { l.client = l.build() })
+	return l.client
+}
+
+// The lazy* types forward each call to a client built on the first one
+
+type lazyDynamoDB struct{ *lazyClient[DynamoDBAPI] }
+
+// Scan forwards to the client when it scans, which the SDK's does, so task
+// sweeps can list the table
+func (l lazyDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	scanner, ok := l.get().(DynamoDBScanAPI)
+	if !ok {
+		return nil, errors.New("the DynamoDB client cannot scan")
+	}
+	return scanner.Scan(ctx, params, optFns...)
+}
+
+func (l lazyDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return l.get().GetItem(ctx, params, optFns...)
+}
+
+func (l lazyDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return l.get().PutItem(ctx, params, optFns...)
+}
+
+func (l lazyDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return l.get().UpdateItem(ctx, params, optFns...)
+}
+
+func (l lazyDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return l.get().DeleteItem(ctx, params, optFns...)
+}
+
+func (l lazyDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return l.get().Query(ctx, params, optFns...)
+}
+
+type lazySQS struct{ *lazyClient[SQSAPI] }
+
+func (l lazySQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	return l.get().SendMessage(ctx, params, optFns...)
+}
+
+func (l lazySQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	return l.get().ReceiveMessage(ctx, params, optFns...)
+}
+
+func (l lazySQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	return l.get().DeleteMessage(ctx, params, optFns...)
+}
+
+func (l lazySQS) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	return l.get().ChangeMessageVisibilityBatch(ctx, params, optFns...)
+}
+
+func (l lazySQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	return l.get().GetQueueAttributes(ctx, params, optFns...)
+}
+
+type lazyFirehose struct{ *lazyClient[FirehoseAPI] }
+
+func (l lazyFirehose) PutRecord(ctx context.Context, params *firehose.PutRecordInput, optFns ...func(*firehose.Options)) (*firehose.PutRecordOutput, error) {
+	return l.get().PutRecord(ctx, params, optFns...)
+}
+
+type lazyS3 struct{ *lazyClient[S3RecordingAPI] }
+
+func (l lazyS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	return l.get().PutObject(ctx, params, optFns...)
+}
+
+func (l lazyS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return l.get().GetObject(ctx, params, optFns...)
+}
+
+// DeleteObject forwards to the client when it deletes, which the SDK's
+// does, so purges can erase captures and archived tasks
+func (l lazyS3) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	deleter, ok := l.get().(S3API)
+	if !ok {
+		return nil, errors.New("the S3 client cannot delete")
+	}
+	return deleter.DeleteObject(ctx, params, optFns...)
+}
+
+func (l lazyS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	return l.get().ListObjectsV2(ctx, params, optFns...)
+}
+
+type lazySecretsManager struct{ *lazyClient[SecretsManagerAPI] }
+
+func (l lazySecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	return l.get().GetSecretValue(ctx, params, optFns...)
+}
+
+type lazySSM struct{ *lazyClient[SSMParameterAPI] }
+
+func (l lazySSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	return l.get().GetParameter(ctx, params, optFns...)
+}
@@ -0,0 +1,140 @@
+package a2a
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestAWSDataPlaneConfig(t *testing.T) {
+	base := aws.Config{Region: "us-east-1"}
+
+	tests := []struct {
+		name           string
+		config         *AWSConfig
+		expectedRegion string
+		expectedKeyID  string
+	}{
+		{
+			name:           "no aws section keeps the base config",
+			expectedRegion: "us-east-1",
+		},
+		{
+			name:           "region override without keys",
+			config:         &AWSConfig{Region: "eu-west-1"},
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name:           "static keys replace the execution role",
+			config:         &AWSConfig{Region: "us-east-1", AccessKeyID: "AKIAEXAMPLE", SecretAccessKey: "secret"},
+			expectedRegion: "us-east-1",
+			expectedKeyID:  "AKIAEXAMPLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := AWSDataPlaneConfig(base, tt.config)
+			if cfg.Region != tt.expectedRegion {
+				t.Errorf("expected region %s, got %s", tt.expectedRegion, cfg.Region)
+			}
+
+			if tt.expectedKeyID == "" {
+				if cfg.Credentials != nil {
+					t.Error("expected base credentials to be kept")
+				}
+				return
+			}
+			creds, err := cfg.Credentials.Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if creds.AccessKeyID != tt.expectedKeyID {
+				t.Errorf("expected access key %s, got %s", tt.expectedKeyID, creds.AccessKeyID)
+			}
+		})
+	}
+
+	if base.Region != "us-east-1" {
+		t.Error("base config was mutated")
+	}
+}
+
+func TestAWSDataPlaneConfigAssumesRole(t *testing.T) {
+	var assumed url.Values
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		assumed = r.PostForm
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult><Credentials>` +
+			`<AccessKeyId>ASIADATA</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken>` +
+			`<Expiration>` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `</Expiration>` +
+			`</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer sts.Close()
+
+	base := aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(sts.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIAAGENT", "secret", ""),
+	}
+	cfg := AWSDataPlaneConfig(base, &AWSConfig{RoleARN: "arn:aws:iam::123456789012:role/a2a-data", ExternalID: "agent-1"})
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "ASIADATA" || creds.SessionToken != "token" {
+		t.Errorf("expected the assumed role's credentials, got %s", creds.AccessKeyID)
+	}
+	if assumed.Get("Action") != "AssumeRole" || assumed.Get("RoleArn") != "arn:aws:iam::123456789012:role/a2a-data" ||
+		assumed.Get("ExternalId") != "agent-1" || assumed.Get("RoleSessionName") != DataPlaneRoleSessionName {
+		t.Errorf("unexpected AssumeRole call %v", assumed)
+	}
+}
+
+func TestAWSClientsLazy(t *testing.T) {
+	client, requests := recordingDynamoDB(t)
+	clients := NewAWSClients(aws.Config{Region: "us-east-1"}, WithDynamoDBClient(client))
+
+	// Handing a client to a store builds nothing
+	store := NewAWSTaskStore(clients.DynamoDB(), "tasks", "")
+	if clients.dynamoDB.client != nil || clients.sqs.client != nil {
+		t.Fatal("expected no client to be built before its first call")
+	}
+	if err := store.SaveTask(context.Background(), a2a.Task{ID: "task-1", ContextID: "ctx-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if clients.dynamoDB.client != client || len(*requests) != 1 {
+		t.Errorf("expected the injected client to serve the first call, got %d requests", len(*requests))
+	}
+	if clients.sqs.client != nil {
+		t.Error("expected unused clients to stay unbuilt")
+	}
+
+	dataPlane := clients.DataPlane(&AWSConfig{Region: "eu-west-1"})
+	if dataPlane.config.Region != "eu-west-1" || dataPlane.config.HTTPClient != clients.config.HTTPClient {
+		t.Errorf("expected the data plane to share the HTTP client in its own region, got %s", dataPlane.config.Region)
+	}
+	if dataPlane.dynamoDB.get() != client {
+		t.Error("expected the data plane to keep the injected client")
+	}
+}
+
+func TestAWSClientsWarm(t *testing.T) {
+	client, requests := recordingDynamoDB(t)
+	clients := NewAWSClients(aws.Config{Region: "us-east-1"}, WithDynamoDBClient(client))
+	clients.Warm()
+	if clients.dynamoDB.client != client || clients.sqs.client == nil {
+		t.Error("expected warming to build the DynamoDB and SQS clients")
+	}
+	if len(*requests) != 0 || clients.s3.client != nil {
+		t.Errorf("expected warming to call nothing and build no other client, got %d requests", len(*requests))
+	}
+}
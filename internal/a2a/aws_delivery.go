@@ -0,0 +1,146 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AWSDeliveryLog implements DeliveryLog using DynamoDB. Items are keyed by
+// task (task_id) and time (attempted_at), hold the attempt as JSON with its
+// config_id beside it, and carry an expires_at number attribute, which
+// should be the table's TTL attribute.
+//
+// The webhook deliverer does not know which agent queued a notification, so
+// task IDs are not prefixed; the handler lists only tasks the agent has.
+type AWSDeliveryLog struct {
+	client    DynamoDBAPI
+	tableName string
+	ttl       time.Duration
+}
+
+// NewAWSDeliveryLog creates a DynamoDB delivery log keeping attempts for ttl
+func NewAWSDeliveryLog(client DynamoDBAPI, tableName string, ttl time.Duration) *AWSDeliveryLog {
+	return &AWSDeliveryLog{
+		client:    client,
+		tableName: tableName,
+		ttl:       ttl,
+	}
+}
+
+// RecordDelivery implements DeliveryLog
+func (l *AWSDeliveryLog) RecordDelivery(ctx context.Context, attempt DeliveryAttempt) error {
+	defer observeStorage(ctx, "RecordDelivery", time.Now())
+
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delivery attempt: %w", err)
+	}
+	item := map[string]types.AttributeValue{
+		"task_id": &types.AttributeValueMemberS{Value: string(attempt.TaskID)},
+		// Attempts of two messages at the same instant keep their own items
+		"attempted_at": &types.AttributeValueMemberS{Value: attempt.AttemptedAt.UTC().Format(time.RFC3339Nano) + "#" + attempt.MessageID},
+		"config_id":    &types.AttributeValueMemberS{Value: attempt.ConfigID},
+		"attempt":      &types.AttributeValueMemberS{Value: string(data)},
+		"expires_at":   &types.AttributeValueMemberN{Value: strconv.FormatInt(attempt.AttemptedAt.Add(l.ttl).Unix(), 10)},
+	}
+	_, err = l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write delivery attempt to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries implements DeliveryLog. A config filter is applied after
+// DynamoDB's page limit, so pages are read until enough attempts match.
+func (l *AWSDeliveryLog) ListDeliveries(ctx context.Context, params TaskPushDeliveriesParams) ([]DeliveryAttempt, error) {
+	defer observeStorage(ctx, "ListDeliveries", time.Now())
+
+	limit := deliveryLimit(params.Limit)
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(l.tableName),
+		KeyConditionExpression: aws.String("task_id = :task_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":task_id": &types.AttributeValueMemberS{Value: string(params.TaskID)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	}
+	if params.ConfigID != nil {
+		input.FilterExpression = aws.String("config_id = :config_id")
+		input.ExpressionAttributeValues[":config_id"] = &types.AttributeValueMemberS{Value: *params.ConfigID}
+	}
+
+	attempts := []DeliveryAttempt{}
+	for len(attempts) < limit {
+		result, err := l.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query delivery attempts from DynamoDB: %w", err)
+		}
+		for _, item := range result.Items {
+			attemptAttr, ok := item["attempt"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			var attempt DeliveryAttempt
+			if err := json.Unmarshal([]byte(attemptAttr.Value), &attempt); err != nil {
+				continue
+			}
+			attempts = append(attempts, attempt)
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+	if len(attempts) > limit {
+		attempts = attempts[:limit]
+	}
+	return attempts, nil
+}
+
+// DeleteDeliveries deletes every recorded attempt of a task, for purges
+func (l *AWSDeliveryLog) DeleteDeliveries(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	defer observeStorage(ctx, "DeleteDeliveries", time.Now())
+
+	deleted := 0
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := l.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(l.tableName),
+			KeyConditionExpression: aws.String("task_id = :task_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":task_id": &types.AttributeValueMemberS{Value: string(taskID)},
+			},
+			ProjectionExpression: aws.String("task_id, attempted_at"),
+			ExclusiveStartKey:    startKey,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query delivery attempts from DynamoDB: %w", err)
+		}
+		for _, item := range result.Items {
+			_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(l.tableName),
+				Key:       map[string]types.AttributeValue{"task_id": item["task_id"], "attempted_at": item["attempted_at"]},
+			})
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete delivery attempt from DynamoDB: %w", err)
+			}
+			deleted++
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return deleted, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
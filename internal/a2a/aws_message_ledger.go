@@ -0,0 +1,92 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AWSMessageLedger implements MessageLedger using DynamoDB, so a message
+// delivered to two worker instances is still processed once. Items are
+// keyed by message_id and carry an expires_at number attribute, which
+// should be the table's TTL attribute.
+type AWSMessageLedger struct {
+	client    DynamoDBAPI
+	tableName string
+	runtimeDeps
+}
+
+// NewAWSMessageLedger creates a DynamoDB message ledger
+func NewAWSMessageLedger(client DynamoDBAPI, tableName string, opts ...RuntimeOption) *AWSMessageLedger {
+	return &AWSMessageLedger{
+		client:      client,
+		tableName:   tableName,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// Claim writes the message unless an unexpired item already holds it.
+// DynamoDB deletes expired items lazily, so an expired item is overwritten.
+func (l *AWSMessageLedger) Claim(ctx context.Context, id string, expiresAt time.Time) error {
+	defer observeStorage(ctx, "ClaimMessage", time.Now())
+
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(l.tableName),
+		Item:                l.item(id, expiresAt),
+		ConditionExpression: aws.String("attribute_not_exists(message_id) OR expires_at <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(l.clock.Now().Unix(), 10)},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrReplayed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to claim message in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// Complete implements MessageLedger
+func (l *AWSMessageLedger) Complete(ctx context.Context, id string, expiresAt time.Time) error {
+	defer observeStorage(ctx, "CompleteMessage", time.Now())
+
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item:      l.item(id, expiresAt),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record processed message in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// Release implements MessageLedger
+func (l *AWSMessageLedger) Release(ctx context.Context, id string) error {
+	defer observeStorage(ctx, "ReleaseMessage", time.Now())
+
+	_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"message_id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release message in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+func (l *AWSMessageLedger) item(id string, expiresAt time.Time) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"message_id": &types.AttributeValueMemberS{Value: id},
+		"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+}
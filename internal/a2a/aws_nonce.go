@@ -0,0 +1,60 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AWSNonceStore implements NonceStore using DynamoDB, so every Lambda
+// instance sees the same nonces. Items are keyed by nonce and carry an
+// expires_at number attribute, which should be the table's TTL attribute.
+type AWSNonceStore struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+	runtimeDeps
+}
+
+// NewAWSNonceStore creates a DynamoDB nonce store. keyPrefix namespaces keys
+// as for NewAWSTaskStore.
+func NewAWSNonceStore(client DynamoDBAPI, tableName string, keyPrefix string, opts ...RuntimeOption) *AWSNonceStore {
+	return &AWSNonceStore{
+		client:      client,
+		tableName:   tableName,
+		keyPrefix:   keyPrefix,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// UseNonce writes the nonce unless an unexpired item already holds it.
+// DynamoDB deletes expired items lazily, so an expired item is overwritten.
+func (s *AWSNonceStore) UseNonce(ctx context.Context, nonce string, expiresAt time.Time) error {
+	defer observeStorage(ctx, "UseNonce", time.Now())
+
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]types.AttributeValue{
+			"nonce":      &types.AttributeValueMemberS{Value: s.keyPrefix + nonce},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(nonce) OR expires_at <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.clock.Now().Unix(), 10)},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrReplayed
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record nonce in DynamoDB: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Replay scan bounds. The dead-lettered messages a replay looks at are
+// hidden while it runs, so it never sees one twice, and those it leaves are
+// made visible again before it returns.
+const (
+	replayVisibilityTimeout = 60
+	maxReplayScan           = 500
+)
+
+// SQSNotificationReplayer implements NotificationReplayer by moving
+// messages from the notification dead-letter queue back to the queue the
+// worker reads. Bodies and attributes are sent unchanged, so signatures
+// still verify, with NotificationIDHeader set to the dead-lettered message's
+// ID. The worker's ledger claims that ID, so a notification replayed twice
+// is still posted once.
+type SQSNotificationReplayer struct {
+	client        SQSAPI
+	deadLetterURL string
+	queueURL      string
+}
+
+// NewSQSNotificationReplayer creates a replayer from deadLetterURL to queueURL
+func NewSQSNotificationReplayer(client SQSAPI, deadLetterURL, queueURL string) *SQSNotificationReplayer {
+	return &SQSNotificationReplayer{client: client, deadLetterURL: deadLetterURL, queueURL: queueURL}
+}
+
+// ReplayNotifications implements NotificationReplayer. A replay that fails
+// part way reports what it re-delivered; running it again continues.
+func (r *SQSNotificationReplayer) ReplayNotifications(ctx context.Context, params NotificationReplayParams, owned func(ctx context.Context, taskID a2a.TaskID) bool) (NotificationReplayReport, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultNotificationReplayLimit
+	}
+	report := NotificationReplayReport{Replayed: []ReplayedNotification{}, DryRun: params.DryRun}
+	var left []sqstypes.Message
+	defer func() { r.release(ctx, left) }()
+
+	for report.Scanned < maxReplayScan && len(report.Replayed) < limit {
+		output, err := r.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(r.deadLetterURL),
+			MaxNumberOfMessages:         10,
+			VisibilityTimeout:           replayVisibilityTimeout,
+			MessageAttributeNames:       []string{"All"},
+			MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameSentTimestamp},
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to receive dead-lettered notifications: %w", err)
+		}
+		if len(output.Messages) == 0 {
+			break
+		}
+		for i, message := range output.Messages {
+			report.Scanned++
+			notification, ok := selectDeadLettered(ctx, message, params, owned)
+			if !ok || params.DryRun || len(report.Replayed) >= limit {
+				if ok && len(report.Replayed) < limit {
+					report.Replayed = append(report.Replayed, notification)
+				}
+				left = append(left, message)
+				continue
+			}
+			if err := r.replay(ctx, message, notification.NotificationID); err != nil {
+				left = append(left, output.Messages[i:]...)
+				return report, err
+			}
+			report.Replayed = append(report.Replayed, notification)
+		}
+	}
+	return report, nil
+}
+
+// selectDeadLettered reads a dead-lettered message and reports whether the
+// params select it
+func selectDeadLettered(ctx context.Context, message sqstypes.Message, params NotificationReplayParams, owned func(ctx context.Context, taskID a2a.TaskID) bool) (ReplayedNotification, bool) {
+	var notification PushNotification
+	if err := json.Unmarshal([]byte(aws.ToString(message.Body)), &notification); err != nil {
+		return ReplayedNotification{}, false
+	}
+	replayed := ReplayedNotification{
+		NotificationID: aws.ToString(message.MessageId),
+		TaskID:         notificationTaskID(notification.Event),
+	}
+	if id := message.MessageAttributes[NotificationIDHeader].StringValue; id != nil && *id != "" {
+		replayed.NotificationID = *id
+	}
+	if notification.PushConfig.ID != nil {
+		replayed.ConfigID = *notification.PushConfig.ID
+	}
+	if millis, err := strconv.ParseInt(message.Attributes[string(sqstypes.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		replayed.SentAt = time.UnixMilli(millis).UTC()
+	}
+	if replayed.TaskID == "" || !params.matches(replayed.TaskID, replayed.ConfigID, replayed.SentAt) {
+		return ReplayedNotification{}, false
+	}
+	if owned != nil && !owned(ctx, replayed.TaskID) {
+		return ReplayedNotification{}, false
+	}
+	return replayed, true
+}
+
+// replay sends a dead-lettered message back to the notification queue, then
+// deletes it from the dead-letter queue. Should the delete fail the message
+// returns to the dead-letter queue, and replaying it again is caught by the
+// worker's ledger.
+func (r *SQSNotificationReplayer) replay(ctx context.Context, message sqstypes.Message, notificationID string) error {
+	attributes := make(map[string]sqstypes.MessageAttributeValue, len(message.MessageAttributes)+1)
+	for name, value := range message.MessageAttributes {
+		attributes[name] = value
+	}
+	attributes[NotificationIDHeader] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(notificationID)}
+	_, err := r.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:          aws.String(r.queueURL),
+		MessageBody:       message.Body,
+		MessageAttributes: attributes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay notification %s: %w", notificationID, err)
+	}
+	_, err = r.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{QueueUrl: aws.String(r.deadLetterURL), ReceiptHandle: message.ReceiptHandle})
+	if err != nil {
+		LoggerFromContext(ctx).Warn("failed to delete replayed notification from the dead-letter queue", "notification_id", notificationID, LogKeyError, err)
+	}
+	return nil
+}
+
+// release makes the messages a replay left visible again. A message that
+// cannot be released reappears once its visibility timeout passes.
+func (r *SQSNotificationReplayer) release(ctx context.Context, messages []sqstypes.Message) {
+	for start := 0; start < len(messages); start += 10 {
+		batch := messages[start:min(start+10, len(messages))]
+		entries := make([]sqstypes.ChangeMessageVisibilityBatchRequestEntry, len(batch))
+		for i, message := range batch {
+			entries[i] = sqstypes.ChangeMessageVisibilityBatchRequestEntry{Id: aws.String(strconv.Itoa(i)), ReceiptHandle: message.ReceiptHandle}
+		}
+		output, err := r.client.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{QueueUrl: aws.String(r.deadLetterURL), Entries: entries})
+		if err == nil && len(output.Failed) > 0 {
+			err = fmt.Errorf("%d of %d messages not released", len(output.Failed), len(entries))
+		}
+		if err != nil {
+			LoggerFromContext(ctx).Warn("failed to release dead-lettered notifications", LogKeyError, err)
+		}
+	}
+}
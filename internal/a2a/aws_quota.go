@@ -0,0 +1,186 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// AWSQuotaStore implements QuotaStore using DynamoDB. Items are keyed by
+// quota_key, holding requests, tasks and bytes number attributes and an
+// expires_at number attribute, which should be the table's TTL attribute.
+type AWSQuotaStore struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSQuotaStore creates a DynamoDB quota store. keyPrefix namespaces keys
+// as for NewAWSTaskStore.
+func NewAWSQuotaStore(client DynamoDBAPI, tableName string, keyPrefix string) *AWSQuotaStore {
+	return &AWSQuotaStore{
+		client:    client,
+		tableName: tableName,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// key returns the table key of a usage count
+func (s *AWSQuotaStore) key(key UsageKey) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"quota_key": &types.AttributeValueMemberS{Value: s.keyPrefix + key.Scope + "#" + key.ID + "#" + key.Window},
+	}
+}
+
+// AddUsage adds delta to each counter in turn, each update conditional on
+// the counter staying within its limits, so concurrent calls cannot both
+// take the last of a quota. When one is refused, the counters already
+// updated are given back.
+func (s *AWSQuotaStore) AddUsage(ctx context.Context, counters []UsageCounter, delta Usage) error {
+	defer observeStorage(ctx, "AddUsage", time.Now())
+
+	for i, counter := range counters {
+		if err := s.addUsage(ctx, counter, delta); err != nil {
+			s.giveBack(ctx, counters[:i], delta)
+			return err
+		}
+	}
+	return nil
+}
+
+// addUsage adds delta to one counter unless that passes its limits
+func (s *AWSQuotaStore) addUsage(ctx context.Context, counter UsageCounter, delta Usage) error {
+	// A charge larger than a whole limit is refused without a write
+	if exceeded := counter.Limits.exceededBy(Usage{}, delta); exceeded != "" {
+		return fmt.Errorf("%s: %s: %w", counter.Key, exceeded, ErrQuotaExceeded)
+	}
+
+	values := usageValues(delta)
+	values[":expires_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(counter.ExpiresAt.Unix(), 10)}
+	input := &dynamodb.UpdateItemInput{
+		TableName:                           aws.String(s.tableName),
+		Key:                                 s.key(counter.Key),
+		UpdateExpression:                    aws.String("ADD #requests :requests, #tasks :tasks, #bytes :bytes SET expires_at = :expires_at"),
+		ExpressionAttributeNames:            usageNames(),
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	}
+
+	// Each counter charged must hold at most its limit less the charge
+	condition := ""
+	for _, limit := range []struct {
+		name         string
+		limit, delta int64
+	}{
+		{"requests", counter.Limits.Requests, delta.Requests},
+		{"tasks", counter.Limits.Tasks, delta.Tasks},
+		{"bytes", counter.Limits.Bytes, delta.Bytes},
+	} {
+		if limit.limit == 0 || limit.delta == 0 {
+			continue
+		}
+		if condition != "" {
+			condition += " AND "
+		}
+		condition += fmt.Sprintf("(attribute_not_exists(#%[1]s) OR #%[1]s <= :max_%[1]s)", limit.name)
+		values[":max_"+limit.name] = &types.AttributeValueMemberN{Value: strconv.FormatInt(limit.limit-limit.delta, 10)}
+	}
+	if condition != "" {
+		input.ConditionExpression = aws.String(condition)
+	}
+
+	_, err := s.client.UpdateItem(ctx, input)
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		exceeded := counter.Limits.exceededBy(usageFromItem(conditionFailed.Item), delta)
+		if exceeded == "" {
+			exceeded = "limit reached"
+		}
+		return fmt.Errorf("%s: %s: %w", counter.Key, exceeded, ErrQuotaExceeded)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add usage to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// giveBack subtracts delta from counters already charged by a refused call.
+// A failure is logged, leaving the usage overcounted rather than refusing
+// the call for a reason other than its quota.
+func (s *AWSQuotaStore) giveBack(ctx context.Context, counters []UsageCounter, delta Usage) {
+	for _, counter := range counters {
+		_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(s.tableName),
+			Key:                       s.key(counter.Key),
+			UpdateExpression:          aws.String("ADD #requests :requests, #tasks :tasks, #bytes :bytes"),
+			ExpressionAttributeNames:  usageNames(),
+			ExpressionAttributeValues: usageValues(Usage{Requests: -delta.Requests, Tasks: -delta.Tasks, Bytes: -delta.Bytes}),
+		})
+		if err != nil {
+			LoggerFromContext(ctx).Warn("failed to give back refused usage", "quota", counter.Key.String(), LogKeyError, err)
+		}
+	}
+}
+
+// GetUsage reads a usage count from DynamoDB
+func (s *AWSQuotaStore) GetUsage(ctx context.Context, key UsageKey) (Usage, error) {
+	defer observeStorage(ctx, "GetUsage", time.Now())
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            s.key(key),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to get usage from DynamoDB: %w", err)
+	}
+	return usageFromItem(result.Item), nil
+}
+
+// ResetUsage deletes a usage count from DynamoDB
+func (s *AWSQuotaStore) ResetUsage(ctx context.Context, key UsageKey) error {
+	defer observeStorage(ctx, "ResetUsage", time.Now())
+
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       s.key(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reset usage in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// usageNames aliases the counter attributes, since DynamoDB reserves many
+// plain words
+func usageNames() map[string]string {
+	return map[string]string{"#requests": "requests", "#tasks": "tasks", "#bytes": "bytes"}
+}
+
+// usageValues returns the expression values adding usage
+func usageValues(usage Usage) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		":requests": &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Requests, 10)},
+		":tasks":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Tasks, 10)},
+		":bytes":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Bytes, 10)},
+	}
+}
+
+// usageFromItem reads the counters of a usage item; missing ones are zero
+func usageFromItem(item map[string]types.AttributeValue) Usage {
+	count := func(name string) int64 {
+		value, _ := item[name].(*types.AttributeValueMemberN)
+		if value == nil {
+			return 0
+		}
+		n, _ := strconv.ParseInt(value.Value, 10, 64)
+		return n
+	}
+	return Usage{Requests: count("requests"), Tasks: count("tasks"), Bytes: count("bytes")}
+}
@@ -0,0 +1,249 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// statsHourLayout names an hour's stats item, sorting in time order
+const statsHourLayout = "2006-01-02T15"
+
+// AWSStatsStore implements StatsStore using DynamoDB. Items are keyed by
+// stats_key and bucket: the state counts are one item, stats_key "states"
+// and bucket "current", holding a number attribute per state; each hour is
+// one item under stats_key "hours" with the hour as its bucket, holding
+// events, finished, completion_ms and timed number attributes and an
+// expires_at number attribute, which should be the table's TTL attribute.
+// Every write is an atomic ADD, so instances count into the same items and
+// a report reads two partitions, never the task table.
+type AWSStatsStore struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSStatsStore creates a DynamoDB stats store. keyPrefix namespaces keys
+// as for NewAWSTaskStore.
+func NewAWSStatsStore(client DynamoDBAPI, tableName string, keyPrefix string) *AWSStatsStore {
+	return &AWSStatsStore{
+		client:    client,
+		tableName: tableName,
+		keyPrefix: keyPrefix,
+	}
+}
+
+// statesKey returns the table key of the state counts
+func (s *AWSStatsStore) statesKey() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"stats_key": &types.AttributeValueMemberS{Value: s.keyPrefix + "states"},
+		"bucket":    &types.AttributeValueMemberS{Value: "current"},
+	}
+}
+
+// hoursPartition is the stats_key of the hourly items
+func (s *AWSStatsStore) hoursPartition() string {
+	return s.keyPrefix + "hours"
+}
+
+// AddStats implements StatsStore. The state counts and the hour are two
+// updates; should the second fail, the transition is still counted.
+func (s *AWSStatsStore) AddStats(ctx context.Context, at time.Time, delta StatsDelta) error {
+	defer observeStorage(ctx, "AddStats", time.Now())
+
+	if delta.From != delta.To {
+		// States are aliased, since input-required and auth-required are
+		// not valid attribute names in an expression
+		update := "ADD #to :one"
+		names := map[string]string{"#to": string(delta.To)}
+		values := map[string]types.AttributeValue{":one": &types.AttributeValueMemberN{Value: "1"}}
+		if delta.From != "" {
+			update += ", #from :minus_one"
+			names["#from"] = string(delta.From)
+			values[":minus_one"] = &types.AttributeValueMemberN{Value: "-1"}
+		}
+		_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(s.tableName),
+			Key:                       s.statesKey(),
+			UpdateExpression:          aws.String(update),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add task states to DynamoDB: %w", err)
+		}
+	}
+
+	hour := HourlyStats{Events: delta.Events}
+	if delta.finished() {
+		hour.Finished = 1
+		if delta.Timed {
+			hour.CompletionMillis, hour.Timed = delta.Completion.Milliseconds(), 1
+		}
+	}
+	if hour == (HourlyStats{}) {
+		return nil
+	}
+	values := hourlyValues(hour)
+	values[":expires_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(at.Add(statsTTL).Unix(), 10)}
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"stats_key": &types.AttributeValueMemberS{Value: s.hoursPartition()},
+			"bucket":    &types.AttributeValueMemberS{Value: at.UTC().Format(statsHourLayout)},
+		},
+		UpdateExpression:          aws.String("ADD #events :events, #finished :finished, #completion_ms :completion_ms, #timed :timed SET expires_at = :expires_at"),
+		ExpressionAttributeNames:  hourlyNames(),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add hourly stats to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// GetStats implements StatsStore
+func (s *AWSStatsStore) GetStats(ctx context.Context, from, to time.Time) (map[a2a.TaskState]int64, []HourlyStats, error) {
+	defer observeStorage(ctx, "GetStats", time.Now())
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		Key:            s.statesKey(),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get task states from DynamoDB: %w", err)
+	}
+	states := map[a2a.TaskState]int64{}
+	for name, value := range result.Item {
+		if n, ok := numberAttribute(value); ok && name != "stats_key" && name != "bucket" {
+			states[a2a.TaskState(name)] = n
+		}
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                aws.String(s.tableName),
+		KeyConditionExpression:   aws.String("stats_key = :stats_key AND #bucket BETWEEN :from AND :to"),
+		ExpressionAttributeNames: map[string]string{"#bucket": "bucket"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":stats_key": &types.AttributeValueMemberS{Value: s.hoursPartition()},
+			":from":      &types.AttributeValueMemberS{Value: from.UTC().Format(statsHourLayout)},
+			":to":        &types.AttributeValueMemberS{Value: to.UTC().Format(statsHourLayout)},
+		},
+	}
+	var hours []HourlyStats
+	for {
+		page, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query hourly stats from DynamoDB: %w", err)
+		}
+		for _, item := range page.Items {
+			bucket, _ := item["bucket"].(*types.AttributeValueMemberS)
+			if bucket == nil {
+				continue
+			}
+			hour, err := time.Parse(statsHourLayout, bucket.Value)
+			if err != nil {
+				continue
+			}
+			count := func(name string) int64 {
+				n, _ := numberAttribute(item[name])
+				return n
+			}
+			hours = append(hours, HourlyStats{
+				Hour:             hour,
+				Events:           count("events"),
+				Finished:         count("finished"),
+				CompletionMillis: count("completion_ms"),
+				Timed:            count("timed"),
+			})
+		}
+		if len(page.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = page.LastEvaluatedKey
+	}
+	return states, hours, nil
+}
+
+// hourlyNames aliases the hourly counters, since DynamoDB reserves many
+// plain words
+func hourlyNames() map[string]string {
+	return map[string]string{"#events": "events", "#finished": "finished", "#completion_ms": "completion_ms", "#timed": "timed"}
+}
+
+// hourlyValues returns the expression values adding hour
+func hourlyValues(hour HourlyStats) map[string]types.AttributeValue {
+	number := func(n int64) types.AttributeValue {
+		return &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+	}
+	return map[string]types.AttributeValue{
+		":events":        number(hour.Events),
+		":finished":      number(hour.Finished),
+		":completion_ms": number(hour.CompletionMillis),
+		":timed":         number(hour.Timed),
+	}
+}
+
+// numberAttribute reads an integer number attribute
+func numberAttribute(value types.AttributeValue) (int64, bool) {
+	number, ok := value.(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(number.Value, 10, 64)
+	return n, err == nil
+}
+
+// SQSQueueDepths implements QueueDepthReader with the approximate counts SQS
+// keeps for the notification queue and its dead-letter queue
+type SQSQueueDepths struct {
+	client SQSAPI
+	queues [][2]string
+}
+
+// NewSQSQueueDepths reads the depth of queueURL and, when set, deadLetterURL
+func NewSQSQueueDepths(client SQSAPI, queueURL, deadLetterURL string) *SQSQueueDepths {
+	queues := [][2]string{{QueueNotifications, queueURL}}
+	if deadLetterURL != "" {
+		queues = append(queues, [2]string{QueueDeadLetter, deadLetterURL})
+	}
+	return &SQSQueueDepths{client: client, queues: queues}
+}
+
+// QueueDepths implements QueueDepthReader
+func (d *SQSQueueDepths) QueueDepths(ctx context.Context) ([]QueueDepth, error) {
+	depths := make([]QueueDepth, 0, len(d.queues))
+	for _, queue := range d.queues {
+		output, err := d.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl: aws.String(queue[1]),
+			AttributeNames: []sqstypes.QueueAttributeName{
+				sqstypes.QueueAttributeNameApproximateNumberOfMessages,
+				sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+				sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get attributes of the %s queue: %w", queue[0], err)
+		}
+		count := func(name sqstypes.QueueAttributeName) int64 {
+			n, _ := strconv.ParseInt(output.Attributes[string(name)], 10, 64)
+			return n
+		}
+		depths = append(depths, QueueDepth{
+			Queue:    queue[0],
+			Visible:  count(sqstypes.QueueAttributeNameApproximateNumberOfMessages),
+			InFlight: count(sqstypes.QueueAttributeNameApproximateNumberOfMessagesNotVisible),
+			Delayed:  count(sqstypes.QueueAttributeNameApproximateNumberOfMessagesDelayed),
+		})
+	}
+	return depths, nil
+}
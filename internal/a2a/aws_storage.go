@@ -3,7 +3,13 @@ package a2a
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
@@ -11,28 +17,40 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // AWSTaskStore implements TaskStore using DynamoDB
 type AWSTaskStore struct {
-	client    *dynamodb.Client
+	client    DynamoDBAPI
 	tableName string
+	keyPrefix string
+	runtimeDeps
 }
 
-// NewAWSTaskStore creates a new AWS DynamoDB-based task store
-func NewAWSTaskStore(client *dynamodb.Client, tableName string) *AWSTaskStore {
+// NewAWSTaskStore creates a new AWS DynamoDB-based task store. keyPrefix is
+// prepended to every task and context key so agents can share a table; a
+// single-agent deployment passes "". WithStorageCompression is the only
+// option it reads.
+func NewAWSTaskStore(client DynamoDBAPI, tableName string, keyPrefix string, opts ...RuntimeOption) *AWSTaskStore {
 	return &AWSTaskStore{
-		client:    client,
-		tableName: tableName,
+		client:      client,
+		tableName:   tableName,
+		keyPrefix:   keyPrefix,
+		runtimeDeps: newRuntimeDeps(opts),
 	}
 }
 
 // GetTask retrieves a task from DynamoDB
 func (s *AWSTaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	defer observeStorage(ctx, "GetTask", time.Now())
+
 	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(s.tableName),
 		Key: map[string]types.AttributeValue{
-			"task_id": &types.AttributeValueMemberS{Value: string(taskID)},
+			"task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
 		},
 	})
 	if err != nil {
@@ -40,45 +58,71 @@ func (s *AWSTaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task
 	}
 
 	if result.Item == nil {
-		return a2a.Task{}, fmt.Errorf("task %s not found", taskID)
+		return a2a.Task{}, fmt.Errorf("task %s: %w", taskID, a2a.ErrTaskNotFound)
 	}
 
 	// Extract task data from DynamoDB item
-	taskDataAttr, ok := result.Item["task_data"]
+	taskData, ok := readStoredData(result.Item, "task_data")
 	if !ok {
 		return a2a.Task{}, fmt.Errorf("task_data not found in DynamoDB item")
 	}
-
-	taskDataStr, ok := taskDataAttr.(*types.AttributeValueMemberS)
-	if !ok {
-		return a2a.Task{}, fmt.Errorf("task_data is not a string")
+	taskJSON, err := taskData.json()
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("task_data: %w", err)
 	}
 
 	var task a2a.Task
-	err = json.Unmarshal([]byte(taskDataStr.Value), &task)
+	err = UnmarshalJSONString(taskJSON, &task)
 	if err != nil {
 		return a2a.Task{}, fmt.Errorf("failed to unmarshal task data: %w", err)
 	}
+	readHeartbeat(result.Item, &task)
 
 	return task, nil
 }
 
-// SaveTask saves a task to DynamoDB
+// SaveTask saves a task to DynamoDB. The write is conditional on the
+// stored task's status, so a finished task is never replaced by another
+// state, as TaskStore requires.
 func (s *AWSTaskStore) SaveTask(ctx context.Context, task a2a.Task) error {
-	taskData, err := json.Marshal(task)
+	defer observeStorage(ctx, "SaveTask", time.Now())
+
+	taskData, err := MarshalJSONString(task)
 	if err != nil {
 		return fmt.Errorf("failed to marshal task: %w", err)
 	}
 
+	item := map[string]types.AttributeValue{
+		"task_id":    &types.AttributeValueMemberS{Value: s.keyPrefix + string(task.ID)},
+		"context_id": &types.AttributeValueMemberS{Value: s.keyPrefix + task.ContextID},
+		"status":     &types.AttributeValueMemberS{Value: string(task.Status.State)},
+	}
+	if err := s.storedDataItem(item, "task_data", taskData); err != nil {
+		return err
+	}
+	retention, _ := s.retention.TaskRetention(task)
+	s.expireItem(item, retention)
+	regionCondition, names, values := s.tagRegion(item)
+	condition, names, values := unfinishedCondition(task.Status.State, names, values)
+	if regionCondition != nil {
+		condition = "(" + *regionCondition + ") AND (" + condition + ")"
+	}
+
 	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(s.tableName),
-		Item: map[string]types.AttributeValue{
-			"task_id": &types.AttributeValueMemberS{Value: string(task.ID)},
-			"context_id": &types.AttributeValueMemberS{Value: task.ContextID},
-			"task_data": &types.AttributeValueMemberS{Value: string(taskData)},
-			"status": &types.AttributeValueMemberS{Value: string(task.Status.State)},
-		},
+		TableName:                           aws.String(s.tableName),
+		Item:                                item,
+		ConditionExpression:                 aws.String(condition),
+		ExpressionAttributeNames:            names,
+		ExpressionAttributeValues:           values,
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
 	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		if stored, ok := conditionFailed.Item["status"].(*types.AttributeValueMemberS); ok && isFinalState(a2a.TaskState(stored.Value)) && stored.Value != string(task.Status.State) {
+			return fmt.Errorf("task %s is %s: %w", task.ID, stored.Value, ErrTaskFinished)
+		}
+		return fmt.Errorf("task %s: %w", task.ID, ErrStaleWrite)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to save task to DynamoDB: %w", err)
 	}
@@ -86,12 +130,100 @@ func (s *AWSTaskStore) SaveTask(ctx context.Context, task a2a.Task) error {
 	return nil
 }
 
+// unfinishedCondition returns the condition that lets a task write in
+// state replace a stored task only while that task is unfinished, or
+// finished in the same state, adding the names and values it uses to
+// those given
+func unfinishedCondition(state a2a.TaskState, names map[string]string, values map[string]types.AttributeValue) (string, map[string]string, map[string]types.AttributeValue) {
+	if names == nil {
+		names = make(map[string]string)
+	}
+	if values == nil {
+		values = make(map[string]types.AttributeValue)
+	}
+	// STATUS is a DynamoDB reserved word
+	names["#status"] = "status"
+	values[":status"] = &types.AttributeValueMemberS{Value: string(state)}
+	finished := make([]string, len(finishedStates))
+	for i, finishedState := range finishedStates {
+		finished[i] = fmt.Sprintf(":finished%d", i)
+		values[finished[i]] = &types.AttributeValueMemberS{Value: string(finishedState)}
+	}
+	return "attribute_not_exists(task_id) OR NOT #status IN (" + strings.Join(finished, ", ") + ") OR #status = :status", names, values
+}
+
+// heartbeatAttributes are the task metadata entries SaveTaskHeartbeat
+// stores as attributes of their own, beside the task's data
+var heartbeatAttributes = []string{TaskLastSeenMetadataKey, TaskDeadlineMetadataKey}
+
+// SaveTaskHeartbeat implements TaskHeartbeatStore by updating the task's
+// heartbeat attributes alone, on condition that it is submitted or working.
+// GetTask reads them back into the task's metadata, and the next SaveTask,
+// replacing the item, folds them into its data.
+func (s *AWSTaskStore) SaveTaskHeartbeat(ctx context.Context, task a2a.Task) error {
+	defer observeStorage(ctx, "SaveTaskHeartbeat", time.Now())
+
+	// STATUS is a DynamoDB reserved word
+	names := map[string]string{"#status": "status"}
+	values := map[string]types.AttributeValue{
+		":submitted": &types.AttributeValueMemberS{Value: string(a2a.TaskStateSubmitted)},
+		":working":   &types.AttributeValueMemberS{Value: string(a2a.TaskStateWorking)},
+	}
+	var sets []string
+	for _, key := range heartbeatAttributes {
+		if value, ok := task.Metadata[key].(string); ok {
+			names["#"+key] = key
+			values[":"+key] = &types.AttributeValueMemberS{Value: value}
+			sets = append(sets, "#"+key+" = :"+key)
+		}
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(task.ID)},
+		},
+		UpdateExpression:          aws.String("SET " + strings.Join(sets, ", ")),
+		ConditionExpression:       aws.String("attribute_exists(task_id) AND #status IN (:submitted, :working)"),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return fmt.Errorf("task %s: %w", task.ID, ErrTaskNotRunning)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save task heartbeat to DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// readHeartbeat sets the heartbeat attributes stored beside a task's data
+// in its metadata, where they are newer than the data's own
+func readHeartbeat(item map[string]types.AttributeValue, task *a2a.Task) {
+	for _, key := range heartbeatAttributes {
+		value, ok := item[key].(*types.AttributeValueMemberS)
+		if !ok {
+			continue
+		}
+		if task.Metadata == nil {
+			task.Metadata = make(map[string]any)
+		}
+		task.Metadata[key] = value.Value
+	}
+}
+
 // DeleteTask deletes a task from DynamoDB
 func (s *AWSTaskStore) DeleteTask(ctx context.Context, taskID a2a.TaskID) error {
+	defer observeStorage(ctx, "DeleteTask", time.Now())
+
 	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(s.tableName),
 		Key: map[string]types.AttributeValue{
-			"task_id": &types.AttributeValueMemberS{Value: string(taskID)},
+			"task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
 		},
 	})
 	if err != nil {
@@ -103,12 +235,14 @@ func (s *AWSTaskStore) DeleteTask(ctx context.Context, taskID a2a.TaskID) error
 
 // ListTasks lists tasks by context ID from DynamoDB
 func (s *AWSTaskStore) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	defer observeStorage(ctx, "ListTasks", time.Now())
+
 	result, err := s.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(s.tableName),
 		IndexName:              aws.String("context_id-index"), // Assumes GSI exists
 		KeyConditionExpression: aws.String("context_id = :context_id"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":context_id": &types.AttributeValueMemberS{Value: contextID},
+			":context_id": &types.AttributeValueMemberS{Value: s.keyPrefix + contextID},
 		},
 	})
 	if err != nil {
@@ -117,22 +251,22 @@ func (s *AWSTaskStore) ListTasks(ctx context.Context, contextID string) ([]a2a.T
 
 	var tasks []a2a.Task
 	for _, item := range result.Items {
-		taskDataAttr, ok := item["task_data"]
+		taskData, ok := readStoredData(item, "task_data")
 		if !ok {
 			continue
 		}
-
-		taskDataStr, ok := taskDataAttr.(*types.AttributeValueMemberS)
-		if !ok {
+		taskJSON, err := taskData.json()
+		if err != nil {
 			continue
 		}
 
 		var task a2a.Task
-		err = json.Unmarshal([]byte(taskDataStr.Value), &task)
+		err = UnmarshalJSONString(taskJSON, &task)
 		if err != nil {
 			// Log error but continue with other tasks
 			continue
 		}
+		readHeartbeat(item, &task)
 
 		tasks = append(tasks, task)
 	}
@@ -140,55 +274,194 @@ func (s *AWSTaskStore) ListTasks(ctx context.Context, contextID string) ([]a2a.T
 	return tasks, nil
 }
 
+// DynamoDBScanAPI is the DynamoDB call that lists a table's keys. Only
+// tools reading whole tables need it, so DynamoDBAPI leaves it out.
+type DynamoDBScanAPI interface {
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+// AWSTaskLister lists every task of an AWS task table by scanning its keys,
+// only those under keyPrefix when the table is shared. TaskStore only lists
+// the tasks of one context.
+type AWSTaskLister struct {
+	client    DynamoDBScanAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSTaskLister creates a lister for the table an AWSTaskStore with the
+// same keyPrefix writes
+func NewAWSTaskLister(client DynamoDBScanAPI, tableName string, keyPrefix string) *AWSTaskLister {
+	return &AWSTaskLister{client: client, tableName: tableName, keyPrefix: keyPrefix}
+}
+
+// TaskIDs yields the ID of every task in the table, page by page
+func (l *AWSTaskLister) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return l.scan(ctx, "", nil, nil)
+}
+
+// RunningTaskIDs implements RunningTaskLister, yielding the ID of every
+// submitted or working task in the table
+func (l *AWSTaskLister) RunningTaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return l.scan(ctx, "#status IN (:submitted, :working)", map[string]string{"#status": "status"}, map[string]types.AttributeValue{
+		":submitted": &types.AttributeValueMemberS{Value: string(a2a.TaskStateSubmitted)},
+		":working":   &types.AttributeValueMemberS{Value: string(a2a.TaskStateWorking)},
+	})
+}
+
+// scan yields the IDs of the tasks under keyPrefix matching filter, every
+// one when it is empty
+func (l *AWSTaskLister) scan(ctx context.Context, filter string, names map[string]string, values map[string]types.AttributeValue) iter.Seq2[a2a.TaskID, error] {
+	return func(yield func(a2a.TaskID, error) bool) {
+		input := &dynamodb.ScanInput{
+			TableName:            aws.String(l.tableName),
+			ProjectionExpression: aws.String("task_id"),
+		}
+		if l.keyPrefix != "" {
+			filter = strings.TrimPrefix(filter+" AND begins_with(task_id, :prefix)", " AND ")
+			values = maps.Clone(values)
+			if values == nil {
+				values = make(map[string]types.AttributeValue)
+			}
+			values[":prefix"] = &types.AttributeValueMemberS{Value: l.keyPrefix}
+		}
+		if filter != "" {
+			input.FilterExpression = aws.String(filter)
+			input.ExpressionAttributeNames = names
+			input.ExpressionAttributeValues = values
+		}
+		for {
+			result, err := l.client.Scan(ctx, input)
+			if err != nil {
+				yield("", fmt.Errorf("failed to scan %s: %w", l.tableName, err))
+				return
+			}
+			for _, item := range result.Items {
+				key, ok := item["task_id"].(*types.AttributeValueMemberS)
+				if !ok {
+					continue
+				}
+				if !yield(a2a.TaskID(strings.TrimPrefix(key.Value, l.keyPrefix)), nil) {
+					return
+				}
+			}
+			if len(result.LastEvaluatedKey) == 0 {
+				return
+			}
+			input.ExclusiveStartKey = result.LastEvaluatedKey
+		}
+	}
+}
+
+// DefaultEventReadBudget caps the event data one GetEvents call reads. A
+// Lambda response holds at most 6 MB, so a replay larger than this could not
+// be returned anyway.
+const DefaultEventReadBudget = 5 << 20
+
+// eventDecodeChunk is how many events one goroutine decodes
+const eventDecodeChunk = 64
+
+// ErrEventBudgetExceeded is returned when a task's events are larger than
+// the event store's read budget
+var ErrEventBudgetExceeded = errors.New("task events exceed the read budget")
+
+// WithEventReadBudget sets how many bytes of event data one
+// AWSEventStore.GetEvents call reads. The default is DefaultEventReadBudget.
+func WithEventReadBudget(bytes int) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.eventReadBudget = bytes
+	}
+}
+
 // AWSEventStore implements EventStore using DynamoDB
 type AWSEventStore struct {
-	client    *dynamodb.Client
+	client    DynamoDBAPI
 	tableName string
+	keyPrefix string
+	runtimeDeps
 }
 
-// NewAWSEventStore creates a new AWS DynamoDB-based event store
-func NewAWSEventStore(client *dynamodb.Client, tableName string) *AWSEventStore {
+// NewAWSEventStore creates a new AWS DynamoDB-based event store. keyPrefix is
+// prepended to event and task keys, as for NewAWSTaskStore. The options set
+// the clock, the generator of IDs for events that carry none and the read
+// budget of GetEvents.
+func NewAWSEventStore(client DynamoDBAPI, tableName string, keyPrefix string, opts ...RuntimeOption) *AWSEventStore {
 	return &AWSEventStore{
-		client:    client,
-		tableName: tableName,
+		client:      client,
+		tableName:   tableName,
+		keyPrefix:   keyPrefix,
+		runtimeDeps: newRuntimeDeps(opts),
 	}
 }
 
+// eventTimeLayout keeps save times one width, so they sort as strings
+const eventTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// EventID is the ID an event is stored under, which tasks/resubscribe
+// sends as its SSE id. A status update is keyed by its own timestamp, so a
+// retried save overwrites rather than duplicates, and each chunk of a
+// streamed artifact keeps an ID of its own. A status update without a
+// timestamp, which the handler never saves, and an event of another kind
+// get a new ID from the store, so they have none here.
+func EventID(event a2a.Event) string {
+	switch e := event.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		if e.Status.Timestamp == nil {
+			return ""
+		}
+		return fmt.Sprintf("status_%s_%d", e.TaskID, e.Status.Timestamp.UnixNano())
+	case a2a.TaskArtifactUpdateEvent:
+		id := fmt.Sprintf("artifact_%s_%s", e.TaskID, e.Artifact.ArtifactID)
+		if chunk, ok := artifactChunk(e); ok {
+			id += fmt.Sprintf("_%d", chunk)
+		}
+		return id
+	case a2a.Message:
+		return e.MessageID
+	}
+	return ""
+}
+
 // SaveEvent saves an event to DynamoDB
 func (s *AWSEventStore) SaveEvent(ctx context.Context, event a2a.Event) error {
-	eventData, err := json.Marshal(event)
+	defer observeStorage(ctx, "SaveEvent", time.Now())
+
+	eventData, err := MarshalJSONString(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// Generate event ID based on event type
-	var eventID string
-	var taskID a2a.TaskID
-
-	switch e := event.(type) {
-	case a2a.TaskStatusUpdateEvent:
-		eventID = fmt.Sprintf("status_%s_%d", e.TaskID, e.Status.Timestamp.UnixNano())
-		taskID = e.TaskID
-	case a2a.TaskArtifactUpdateEvent:
-		eventID = fmt.Sprintf("artifact_%s_%s", e.TaskID, e.Artifact.ArtifactID)
-		taskID = e.TaskID
-	case a2a.Message:
-		eventID = e.MessageID
-		if e.TaskID != nil {
-			taskID = *e.TaskID
+	eventID := EventID(event)
+	if eventID == "" {
+		// The clock alone would collide across instances
+		id := s.ids.NewID()
+		eventID = "event_" + id
+		if e, ok := event.(a2a.TaskStatusUpdateEvent); ok {
+			eventID = fmt.Sprintf("status_%s_%s", e.TaskID, id)
 		}
-	default:
-		eventID = fmt.Sprintf("event_%d", time.Now().UnixNano())
 	}
+	taskID := eventTaskID(event)
+
+	item := map[string]types.AttributeValue{
+		"event_id": &types.AttributeValueMemberS{Value: s.keyPrefix + eventID},
+		"task_id":  &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
+		// The sort key of task_id-index, so a task's events read back in
+		// the order they were saved
+		"recorded_at": &types.AttributeValueMemberS{Value: s.clock.Now().UTC().Format(eventTimeLayout) + "#" + eventID},
+		"processed":   &types.AttributeValueMemberBOOL{Value: false},
+	}
+	if err := s.storedDataItem(item, "event_data", eventData); err != nil {
+		return err
+	}
+	retention, _ := s.retention.EventRetention(event)
+	s.expireItem(item, retention)
+	// Events are never rewritten with other content, so they need the tag
+	// but not the condition
+	s.tagRegion(item)
 
 	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(s.tableName),
-		Item: map[string]types.AttributeValue{
-			"event_id": &types.AttributeValueMemberS{Value: eventID},
-			"task_id": &types.AttributeValueMemberS{Value: string(taskID)},
-			"event_data": &types.AttributeValueMemberS{Value: string(eventData)},
-			"processed": &types.AttributeValueMemberBOOL{Value: false},
-		},
+		Item:      item,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to save event to DynamoDB: %w", err)
@@ -197,84 +470,155 @@ func (s *AWSEventStore) SaveEvent(ctx context.Context, event a2a.Event) error {
 	return nil
 }
 
-// GetEvents retrieves events for a task from DynamoDB
+// GetEvents retrieves events for a task from DynamoDB, in the order they
+// were saved, which task_id-index sorts them in by recorded_at. Each page names the next, so pages are fetched one after
+// another, but the events of earlier pages are decoded concurrently while
+// later pages load. Reading stops with ErrEventBudgetExceeded once the event
+// data passes the store's read budget, rather than running into the API
+// Gateway timeout on tasks with thousands of events.
 func (s *AWSEventStore) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
-	result, err := s.client.Query(ctx, &dynamodb.QueryInput{
-		TableName:              aws.String(s.tableName),
-		IndexName:              aws.String("task_id-index"), // Assumes GSI exists
-		KeyConditionExpression: aws.String("task_id = :task_id"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":task_id": &types.AttributeValueMemberS{Value: string(taskID)},
-		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to query events from DynamoDB: %w", err)
-	}
+	defer observeStorage(ctx, "GetEvents", time.Now())
 
-	var events []a2a.Event
-	for _, item := range result.Items {
-		eventDataAttr, ok := item["event_data"]
-		if !ok {
-			continue
-		}
-
-		eventDataStr, ok := eventDataAttr.(*types.AttributeValueMemberS)
-		if !ok {
-			continue
-		}
-
-		// Parse the event data to determine type
-		var eventData map[string]interface{}
-		err = json.Unmarshal([]byte(eventDataStr.Value), &eventData)
-		if err != nil {
-			continue
-		}
-
-		// Convert to appropriate event type based on "kind" field
-		kind, ok := eventData["kind"].(string)
-		if !ok {
-			continue
-		}
+	// Stops the page fetch when reading ends early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		var event a2a.Event
-		switch kind {
-		case "status-update":
-			var statusEvent a2a.TaskStatusUpdateEvent
-			err = json.Unmarshal([]byte(eventDataStr.Value), &statusEvent)
+	type page struct {
+		items []map[string]types.AttributeValue
+		err   error
+	}
+	pages := make(chan page, 1)
+	go func() {
+		defer close(pages)
+		var startKey map[string]types.AttributeValue
+		for {
+			result, err := s.client.Query(ctx, &dynamodb.QueryInput{
+				TableName:              aws.String(s.tableName),
+				IndexName:              aws.String("task_id-index"), // Assumes GSI exists
+				KeyConditionExpression: aws.String("task_id = :task_id"),
+				ScanIndexForward:       aws.Bool(true),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
+				},
+				ExclusiveStartKey: startKey,
+			})
+			next := page{err: err}
 			if err == nil {
-				event = statusEvent
+				next.items = result.Items
 			}
-		case "artifact-update":
-			var artifactEvent a2a.TaskArtifactUpdateEvent
-			err = json.Unmarshal([]byte(eventDataStr.Value), &artifactEvent)
-			if err == nil {
-				event = artifactEvent
+			select {
+			case pages <- next:
+			case <-ctx.Done():
+				return
 			}
-		case "message":
-			var message a2a.Message
-			err = json.Unmarshal([]byte(eventDataStr.Value), &message)
-			if err == nil {
-				event = message
+			if err != nil || len(result.LastEvaluatedKey) == 0 {
+				return
 			}
-		default:
-			// Skip unknown event types
-			continue
+			startKey = result.LastEvaluatedKey
 		}
+	}()
 
-		if event != nil {
-			events = append(events, event)
+	budget := s.eventReadBudget
+	if budget <= 0 {
+		budget = DefaultEventReadBudget
+	}
+	// Each chunk decodes into its own slot, so the order survives the concurrency
+	var chunks []*[]a2a.Event
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+	read := 0
+	for p := range pages {
+		if p.err != nil {
+			return nil, fmt.Errorf("failed to query events from DynamoDB: %w", p.err)
+		}
+		var data []storedData
+		for _, item := range p.items {
+			eventData, ok := readStoredData(item, "event_data")
+			if !ok {
+				continue
+			}
+			// Compressed events count at their decompressed size, which is
+			// what the response has to carry
+			read += eventData.size()
+			if read > budget {
+				return nil, fmt.Errorf("%w: task %s has more than %d bytes of events", ErrEventBudgetExceeded, taskID, budget)
+			}
+			data = append(data, eventData)
+		}
+		for len(data) > 0 {
+			n := min(len(data), eventDecodeChunk)
+			chunk, decoded := data[:n], new([]a2a.Event)
+			data = data[n:]
+			chunks = append(chunks, decoded)
+			workers <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+				for _, eventData := range chunk {
+					eventJSON, err := eventData.json()
+					if err != nil {
+						continue
+					}
+					if event := decodeStoredEvent(eventJSON); event != nil {
+						*decoded = append(*decoded, event)
+					}
+				}
+			}()
 		}
 	}
+	wg.Wait()
 
+	var events []a2a.Event
+	for _, decoded := range chunks {
+		events = append(events, *decoded...)
+	}
 	return events, nil
 }
 
+// decodeStoredEvent decodes one stored event by its kind. Unknown kinds and
+// malformed data decode to nil and are skipped.
+func decodeStoredEvent(data string) a2a.Event {
+	// One pooled copy serves the probe and the decode
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	buf.WriteString(data)
+	raw := buf.Bytes()
+
+	var probe struct{ Kind string }
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil
+	}
+
+	switch probe.Kind {
+	case "status-update":
+		var statusEvent a2a.TaskStatusUpdateEvent
+		if err := json.Unmarshal(raw, &statusEvent); err == nil {
+			return statusEvent
+		}
+	case "artifact-update":
+		var artifactEvent a2a.TaskArtifactUpdateEvent
+		if err := json.Unmarshal(raw, &artifactEvent); err == nil {
+			return artifactEvent
+		}
+	case "message":
+		var message a2a.Message
+		if err := json.Unmarshal(raw, &message); err == nil {
+			return message
+		}
+	}
+	return nil
+}
+
 // MarkEventProcessed marks an event as processed in DynamoDB
 func (s *AWSEventStore) MarkEventProcessed(ctx context.Context, eventID string) error {
+	defer observeStorage(ctx, "MarkEventProcessed", time.Now())
+
 	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(s.tableName),
 		Key: map[string]types.AttributeValue{
-			"event_id": &types.AttributeValueMemberS{Value: eventID},
+			"event_id": &types.AttributeValueMemberS{Value: s.keyPrefix + eventID},
 		},
 		UpdateExpression: aws.String("SET processed = :processed"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
@@ -288,22 +632,193 @@ func (s *AWSEventStore) MarkEventProcessed(ctx context.Context, eventID string)
 	return nil
 }
 
+// DeleteEvents deletes every event of a task from DynamoDB and returns how
+// many it deleted
+func (s *AWSEventStore) DeleteEvents(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	defer observeStorage(ctx, "DeleteEvents", time.Now())
+
+	deleted := 0
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			IndexName:              aws.String("task_id-index"),
+			KeyConditionExpression: aws.String("task_id = :task_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
+			},
+			ProjectionExpression: aws.String("event_id"),
+			ExclusiveStartKey:    startKey,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query events from DynamoDB: %w", err)
+		}
+		for _, item := range result.Items {
+			// The stored key already carries the prefix
+			_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(s.tableName),
+				Key:       map[string]types.AttributeValue{"event_id": item["event_id"]},
+			})
+			if err != nil {
+				return deleted, fmt.Errorf("failed to delete event from DynamoDB: %w", err)
+			}
+			deleted++
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return deleted, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// DynamoDBBackfillAPI is what BackfillEventRecordedAt calls: a scan for
+// the events to update and an update of each
+type DynamoDBBackfillAPI interface {
+	DynamoDBScanAPI
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+// legacyEventTime is the recorded_at time given to events saved before
+// recorded_at was written. They were saved before every event that has one,
+// so they sort first, in event ID order among themselves.
+var legacyEventTime = time.Unix(0, 0)
+
+// BackfillEventRecordedAt sets recorded_at on every event in tableName saved
+// without it, and returns how many it set. task_id-index is sorted by
+// recorded_at, so DynamoDB leaves those events out of it, and GetEvents and
+// DeleteEvents cannot find them until this has run. Items written since are
+// left as they are, so it is safe to run while the agent serves.
+func BackfillEventRecordedAt(ctx context.Context, client DynamoDBBackfillAPI, tableName string) (int, error) {
+	updated := 0
+	input := &dynamodb.ScanInput{
+		TableName:            aws.String(tableName),
+		ProjectionExpression: aws.String("event_id"),
+		FilterExpression:     aws.String("attribute_not_exists(recorded_at)"),
+	}
+	for {
+		result, err := client.Scan(ctx, input)
+		if err != nil {
+			return updated, fmt.Errorf("failed to scan %s: %w", tableName, err)
+		}
+		for _, item := range result.Items {
+			key, ok := item["event_id"].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:           aws.String(tableName),
+				Key:                 map[string]types.AttributeValue{"event_id": key},
+				UpdateExpression:    aws.String("SET recorded_at = :recorded_at"),
+				ConditionExpression: aws.String("attribute_exists(event_id) AND attribute_not_exists(recorded_at)"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":recorded_at": &types.AttributeValueMemberS{Value: legacyEventTime.UTC().Format(eventTimeLayout) + "#" + key.Value},
+				},
+			})
+			// Deleted or saved again since the scan read it
+			var conditionFailed *types.ConditionalCheckFailedException
+			if errors.As(err, &conditionFailed) {
+				continue
+			}
+			if err != nil {
+				return updated, fmt.Errorf("failed to backfill event %s: %w", key.Value, err)
+			}
+			updated++
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return updated, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
 // AWSSQSPushNotifier implements PushNotifier using SQS
 type AWSSQSPushNotifier struct {
-	client   *sqs.Client
-	queueURL string
+	client     SQSAPI
+	queueURL   string
+	signingKey string
+	tracing    *Tracing
+
+	// queues are the primary queue then the failover queues
+	queues   []*notificationQueue
+	cooldown time.Duration
+	clock    Clock
+	mu       sync.Mutex
 }
 
-// NewAWSSQSPushNotifier creates a new AWS SQS-based push notifier
-func NewAWSSQSPushNotifier(client *sqs.Client, queueURL string) *AWSSQSPushNotifier {
+// NewAWSSQSPushNotifier creates a new AWS SQS-based push notifier. With a
+// signing key, each message carries an HMAC of its body so the webhook
+// deliverer can forward it and receivers can verify the payload. Messages
+// also carry the trace context from tracing and the request's correlation ID,
+// so the delivery joins the trace of the call that caused it.
+func NewAWSSQSPushNotifier(client SQSAPI, queueURL string, signingKey string, tracing *Tracing) *AWSSQSPushNotifier {
 	return &AWSSQSPushNotifier{
-		client:   client,
-		queueURL: queueURL,
+		client:     client,
+		queueURL:   queueURL,
+		signingKey: signingKey,
+		tracing:    tracing,
+		queues:     newNotificationQueues(queueURL, nil),
+		cooldown:   DefaultQueueCooldown,
+		clock:      SystemClock{},
+	}
+}
+
+// WithFailover adds queues, usually in other regions, to send notifications
+// to when the primary queue fails. A queue that fails is passed over for
+// DefaultQueueCooldown, so an outage costs one failed send per cooldown
+// rather than one per notification. Each queue is called in the region its
+// URL names.
+func (n *AWSSQSPushNotifier) WithFailover(queueURLs ...string) *AWSSQSPushNotifier {
+	n.queues = newNotificationQueues(n.queueURL, queueURLs)
+	return n
+}
+
+// orderedQueues returns the healthy queues in order, then those cooling down
+func (n *AWSSQSPushNotifier) orderedQueues() []*notificationQueue {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := n.clock.Now()
+	healthy := make([]*notificationQueue, 0, len(n.queues))
+	var coolingDown []*notificationQueue
+	for _, queue := range n.queues {
+		if now.Before(queue.failedUntil) {
+			coolingDown = append(coolingDown, queue)
+		} else {
+			healthy = append(healthy, queue)
+		}
+	}
+	return append(healthy, coolingDown...)
+}
+
+// markQueue records whether a send to queue succeeded
+func (n *AWSSQSPushNotifier) markQueue(queue *notificationQueue, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err != nil {
+		queue.failedUntil = n.clock.Now().Add(n.cooldown)
+	} else {
+		queue.failedUntil = time.Time{}
 	}
 }
 
 // SendNotification sends a push notification via SQS
-func (n *AWSSQSPushNotifier) SendNotification(ctx context.Context, config a2a.PushConfig, event a2a.Event) error {
+func (n *AWSSQSPushNotifier) SendNotification(ctx context.Context, config a2a.PushConfig, event a2a.Event) (err error) {
+	// This span covers only handing the notification to SQS; the webhook
+	// deliverer reading the queue makes the HTTP call. The SQS request itself
+	// is traced by the SDK middleware as a child of this span.
+	ctx, endSpan := StartSpan(ctx, "push_notification enqueue", trace.SpanKindProducer,
+		attribute.String("messaging.system", "aws_sqs"),
+		attribute.String("messaging.operation.type", "send"),
+		attribute.String("messaging.destination.name", n.queueURL),
+	)
+	defer func() {
+		endSpan(err)
+		outcome := NotificationOutcomeSent
+		if err != nil {
+			outcome = NotificationOutcomeFailed
+		}
+		MetricsFromContext(ctx).RecordNotification(outcome)
+	}()
+
 	notification := map[string]interface{}{
 		"push_config": config,
 		"event":       event,
@@ -314,13 +829,38 @@ func (n *AWSSQSPushNotifier) SendNotification(ctx context.Context, config a2a.Pu
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 
-	_, err = n.client.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    aws.String(n.queueURL),
-		MessageBody: aws.String(string(notificationData)),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to send notification to SQS: %w", err)
+	attributes := notificationAttributes(NotificationHeaders(ctx, n.tracing, n.signingKey, notificationData))
+	for i, queue := range n.orderedQueues() {
+		if i > 0 {
+			LoggerFromContext(ctx).Warn("failing over push notification", "queue", queue.url, LogKeyError, err)
+		}
+		input := &sqs.SendMessageInput{
+			QueueUrl:          aws.String(queue.url),
+			MessageBody:       aws.String(string(notificationData)),
+			MessageAttributes: attributes,
+		}
+		_, err = n.client.SendMessage(ctx, input, queue.sendOptions()...)
+		n.markQueue(queue, err)
+		if err == nil {
+			return nil
+		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	return fmt.Errorf("failed to send notification to SQS: %w", err)
+}
+
+// notificationAttributes carries notification headers as SQS message
+// attributes, which the worker turns back into headers
+func notificationAttributes(headers map[string]string) map[string]sqstypes.MessageAttributeValue {
+	if len(headers) == 0 {
+		return nil
+	}
+	attributes := make(map[string]sqstypes.MessageAttributeValue, len(headers))
+	for name, value := range headers {
+		attributes[name] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	return attributes
+}
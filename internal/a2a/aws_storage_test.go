@@ -0,0 +1,287 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// recordingDynamoDB returns a DynamoDB client whose requests are captured
+// instead of sent to AWS. Every call gets an empty successful response.
+func recordingDynamoDB(t *testing.T) (*dynamodb.Client, *[]map[string]any) {
+	t.Helper()
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request map[string]any
+		json.Unmarshal(body, &request)
+		requests = append(requests, request)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	})
+	return client, &requests
+}
+
+// attributeS reads a string attribute from a captured DynamoDB request
+func attributeS(request map[string]any, field, name string) string {
+	attrs, _ := request[field].(map[string]any)
+	attr, _ := attrs[name].(map[string]any)
+	value, _ := attr["S"].(string)
+	return value
+}
+
+func TestAWSStoresPrefixKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	task := a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Timestamp: &now}}
+
+	tests := []struct {
+		name   string
+		prefix string
+	}{
+		{"single agent keeps bare keys", ""},
+		{"registry agent", "billing#"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := recordingDynamoDB(t)
+			tasks := NewAWSTaskStore(client, "tasks", tt.prefix)
+			events := NewAWSEventStore(client, "events", tt.prefix)
+
+			tasks.SaveTask(ctx, task)
+			tasks.GetTask(ctx, task.ID)
+			tasks.DeleteTask(ctx, task.ID)
+			tasks.ListTasks(ctx, task.ContextID)
+			events.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: task.ID, Status: task.Status})
+			events.GetEvents(ctx, task.ID)
+			events.MarkEventProcessed(ctx, "event-1")
+
+			if len(*requests) != 7 {
+				t.Fatalf("expected 7 DynamoDB calls, got %d", len(*requests))
+			}
+			got := []string{
+				attributeS((*requests)[0], "Item", "task_id"),
+				attributeS((*requests)[0], "Item", "context_id"),
+				attributeS((*requests)[1], "Key", "task_id"),
+				attributeS((*requests)[2], "Key", "task_id"),
+				attributeS((*requests)[3], "ExpressionAttributeValues", ":context_id"),
+				attributeS((*requests)[4], "Item", "task_id"),
+				attributeS((*requests)[5], "ExpressionAttributeValues", ":task_id"),
+				attributeS((*requests)[6], "Key", "event_id"),
+			}
+			want := []string{"task-1", "ctx-1", "task-1", "task-1", "ctx-1", "task-1", "task-1", "event-1"}
+			for i := range want {
+				if got[i] != tt.prefix+want[i] {
+					t.Errorf("key %d: expected %q, got %q", i, tt.prefix+want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+// pagedEventsDynamoDB returns a DynamoDB client whose event query yields count
+// status updates, pageSize to a page, each with its index as the context ID
+func pagedEventsDynamoDB(t *testing.T, count, pageSize int) *dynamodb.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ExclusiveStartKey map[string]map[string]string
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		start := 0
+		if key := request.ExclusiveStartKey["event_id"]["S"]; key != "" {
+			fmt.Sscanf(key, "e-%d", &start)
+			start++
+		}
+		end := min(start+pageSize, count)
+
+		var items []map[string]any
+		for i := start; i < end; i++ {
+			data, _ := json.Marshal(a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1", ContextID: fmt.Sprint(i)})
+			items = append(items, map[string]any{
+				"event_id":   map[string]string{"S": fmt.Sprintf("e-%d", i)},
+				"event_data": map[string]string{"S": string(data)},
+			})
+		}
+		response := map[string]any{"Items": items, "Count": len(items)}
+		if end < count {
+			response["LastEvaluatedKey"] = map[string]any{"event_id": map[string]string{"S": fmt.Sprintf("e-%d", end-1)}}
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	})
+}
+
+func TestAWSEventStoreGetEventsPaged(t *testing.T) {
+	ctx := context.Background()
+	client := pagedEventsDynamoDB(t, 1000, 150)
+
+	events, err := NewAWSEventStore(client, "events", "").GetEvents(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 1000 {
+		t.Fatalf("expected every page to be read, got %d events", len(events))
+	}
+	for i, event := range events {
+		if status, ok := event.(a2a.TaskStatusUpdateEvent); !ok || status.ContextID != fmt.Sprint(i) {
+			t.Fatalf("expected event %d in query order, got %+v", i, event)
+		}
+	}
+
+	// About 100 bytes an event, so the budget runs out on a later page
+	_, err = NewAWSEventStore(client, "events", "", WithEventReadBudget(20000)).GetEvents(ctx, "task-1")
+	if !errors.Is(err, ErrEventBudgetExceeded) {
+		t.Errorf("expected ErrEventBudgetExceeded, got %v", err)
+	}
+}
+
+func TestAWSEventStoreOrdersByRecordedAt(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	client, requests := recordingDynamoDB(t)
+	events := NewAWSEventStore(client, "events", "billing#", WithClock(fixedClock{now: now}))
+	status := a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Timestamp: &now}}
+	events.SaveEvent(ctx, status)
+	events.GetEvents(ctx, "task-1")
+
+	id := EventID(status)
+	if id != fmt.Sprintf("status_task-1_%d", now.UnixNano()) || attributeS((*requests)[0], "Item", "event_id") != "billing#"+id {
+		t.Errorf("expected the event stored under its EventID, got %s", attributeS((*requests)[0], "Item", "event_id"))
+	}
+	if got := attributeS((*requests)[0], "Item", "recorded_at"); got != "2025-08-01T12:00:00.000000000Z#"+id {
+		t.Errorf("expected a fixed-width save time, got %s", got)
+	}
+	if query := (*requests)[1]; query["IndexName"] != "task_id-index" || query["ScanIndexForward"] != true {
+		t.Errorf("expected the index read forward, got %v", query)
+	}
+}
+
+// backfillDynamoDB keeps events by ID, scanning those without recorded_at
+// one per page
+type backfillDynamoDB struct {
+	DynamoDBAPI
+	items map[string]map[string]types.AttributeValue
+}
+
+func (d *backfillDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	var ids []string
+	for id, item := range d.items {
+		if _, ok := item["recorded_at"]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	output := &dynamodb.ScanOutput{}
+	if len(ids) > 0 {
+		output.Items = []map[string]types.AttributeValue{{"event_id": &types.AttributeValueMemberS{Value: ids[0]}}}
+		output.LastEvaluatedKey = output.Items[0]
+	}
+	return output, nil
+}
+
+func (d *backfillDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	id := params.Key["event_id"].(*types.AttributeValueMemberS).Value
+	d.items[id]["recorded_at"] = params.ExpressionAttributeValues[":recorded_at"]
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func TestBackfillEventRecordedAt(t *testing.T) {
+	recorded := &types.AttributeValueMemberS{Value: "2025-08-01T12:00:00.000000000Z#status_task-1_2"}
+	client := &backfillDynamoDB{items: map[string]map[string]types.AttributeValue{
+		"status_task-1_1":   {},
+		"status_task-1_2":   {"recorded_at": recorded},
+		"artifact_task-1_a": {},
+	}}
+	updated, err := BackfillEventRecordedAt(context.Background(), client, "events")
+	if err != nil || updated != 2 {
+		t.Fatalf("expected 2 events backfilled, got %d: %v", updated, err)
+	}
+	if got := client.items["status_task-1_1"]["recorded_at"].(*types.AttributeValueMemberS).Value; got != "1970-01-01T00:00:00.000000000Z#status_task-1_1" {
+		t.Errorf("expected an old event to sort before new ones, got %s", got)
+	}
+	if client.items["status_task-1_2"]["recorded_at"] != recorded {
+		t.Error("expected an event with recorded_at left as it was")
+	}
+}
+
+// fakeScanner returns pages of task keys
+type fakeScanner struct {
+	pages  [][]string
+	inputs []*dynamodb.ScanInput
+}
+
+func (f *fakeScanner) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	input := *params
+	f.inputs = append(f.inputs, &input)
+	page := f.pages[len(f.inputs)-1]
+	output := &dynamodb.ScanOutput{}
+	for _, key := range page {
+		output.Items = append(output.Items, map[string]types.AttributeValue{"task_id": &types.AttributeValueMemberS{Value: key}})
+	}
+	if len(f.inputs) < len(f.pages) {
+		output.LastEvaluatedKey = output.Items[len(output.Items)-1]
+	}
+	return output, nil
+}
+
+func TestAWSTaskLister(t *testing.T) {
+	scanner := &fakeScanner{pages: [][]string{{"agent#a", "agent#b"}, {"agent#c"}}}
+	lister := NewAWSTaskLister(scanner, "tasks", "agent#")
+	var ids []a2a.TaskID
+	for id, err := range lister.TaskIDs(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if !slices.Equal(ids, []a2a.TaskID{"a", "b", "c"}) {
+		t.Errorf("expected every page's IDs without the prefix, got %v", ids)
+	}
+	if len(scanner.inputs) != 2 || scanner.inputs[1].ExclusiveStartKey == nil {
+		t.Errorf("expected the second scan to start after the first page, got %d scans", len(scanner.inputs))
+	}
+	if filter := scanner.inputs[0].FilterExpression; filter == nil || *filter != "begins_with(task_id, :prefix)" {
+		t.Errorf("expected the scan filtered by the prefix, got %v", filter)
+	}
+
+	// Running tasks are those submitted or working, still under the prefix
+	scanner = &fakeScanner{pages: [][]string{{"agent#a"}}}
+	for _, err := range NewAWSTaskLister(scanner, "tasks", "agent#").RunningTaskIDs(context.Background()) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	input := scanner.inputs[0]
+	if *input.FilterExpression != "#status IN (:submitted, :working) AND begins_with(task_id, :prefix)" || len(input.ExpressionAttributeValues) != 3 || input.ExpressionAttributeNames["#status"] != "status" {
+		t.Errorf("expected the scan filtered by state and prefix, got %s %v", *input.FilterExpression, input.ExpressionAttributeValues)
+	}
+}
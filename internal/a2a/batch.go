@@ -0,0 +1,226 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is how many records of one SQS batch the worker
+// delivers at once, SQS's default batch size
+const DefaultBatchConcurrency = 10
+
+// LogKeyMessageID is the log field for the queue message being processed
+const LogKeyMessageID = "message_id"
+
+// Message ledger timings. A claim first lasts as long as a Lambda invocation
+// can run, so a worker that times out mid-delivery leaves a message SQS can
+// hand to another; a delivered message then stays claimed long enough to
+// outlast SQS's duplicate deliveries.
+const (
+	messageClaimLease      = 15 * time.Minute
+	processedMessageWindow = 24 * time.Hour
+)
+
+// WorkerConfig configures how cmd/worker processes SQS batches
+type WorkerConfig struct {
+	// Concurrency bounds the records delivered at once
+	Concurrency int
+	// IdempotencyTable is a DynamoDB table recording delivered messages
+	// across instances. Without it each instance remembers its own.
+	IdempotencyTable string
+}
+
+// LoadWorkerConfigFromEnv reads the worker's batch settings
+func LoadWorkerConfigFromEnv() (WorkerConfig, error) {
+	config := WorkerConfig{
+		Concurrency:      DefaultBatchConcurrency,
+		IdempotencyTable: getEnvOrDefault("A2A_WORKER_IDEMPOTENCY_TABLE", ""),
+	}
+	if value := getEnvOrDefault("A2A_WORKER_CONCURRENCY", ""); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			return WorkerConfig{}, fmt.Errorf("A2A_WORKER_CONCURRENCY must be a positive integer, got %q", value)
+		}
+		config.Concurrency = concurrency
+	}
+	return config, nil
+}
+
+// MessageLedger records which queued messages have been processed, so a
+// message SQS delivers more than once is processed once
+type MessageLedger interface {
+	// Claim takes id until expiresAt, or returns ErrReplayed when an
+	// unexpired claim already holds it
+	Claim(ctx context.Context, id string, expiresAt time.Time) error
+	// Complete keeps a claimed id until expiresAt once its message is processed
+	Complete(ctx context.Context, id string, expiresAt time.Time) error
+	// Release drops the claim on a message that failed, so its redelivery
+	// is processed
+	Release(ctx context.Context, id string) error
+}
+
+// MemoryMessageLedger implements MessageLedger in memory. On Lambda it only
+// catches duplicates delivered to the same instance.
+type MemoryMessageLedger struct {
+	mu       sync.Mutex
+	messages map[string]time.Time
+	runtimeDeps
+}
+
+// NewMemoryMessageLedger creates an empty in-memory ledger
+func NewMemoryMessageLedger(opts ...RuntimeOption) *MemoryMessageLedger {
+	return &MemoryMessageLedger{
+		messages:    make(map[string]time.Time),
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// Claim implements MessageLedger, dropping expired claims as it goes
+func (l *MemoryMessageLedger) Claim(ctx context.Context, id string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	for seen, expiry := range l.messages {
+		if !now.Before(expiry) {
+			delete(l.messages, seen)
+		}
+	}
+	if _, ok := l.messages[id]; ok {
+		return ErrReplayed
+	}
+	l.messages[id] = expiresAt
+	return nil
+}
+
+// Complete implements MessageLedger
+func (l *MemoryMessageLedger) Complete(ctx context.Context, id string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages[id] = expiresAt
+	return nil
+}
+
+// Release implements MessageLedger
+func (l *MemoryMessageLedger) Release(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.messages, id)
+	return nil
+}
+
+// BatchRecord is one message of a queued batch
+type BatchRecord struct {
+	MessageID string
+	Body      string
+	Headers   map[string]string
+	// ReceiveCount is how many times the queue has delivered the message,
+	// this time included; 0 when unknown
+	ReceiveCount int
+	// Source is the ARN of the queue the message came from, when known
+	Source string
+}
+
+// ledgerKey is what the ledger claims for the record: the notification ID a
+// replayed message carries, so a replay of a delivered notification is
+// skipped, or else the message ID
+func (r BatchRecord) ledgerKey() string {
+	if id := r.Headers[NotificationIDHeader]; id != "" {
+		return id
+	}
+	return r.MessageID
+}
+
+// BatchProcessor processes the records of a batch concurrently. A record
+// that fails is reported rather than failing the batch, so SQS redelivers
+// only that record instead of every record beside it.
+type BatchProcessor struct {
+	process     func(ctx context.Context, record BatchRecord) error
+	concurrency int
+	ledger      MessageLedger
+	runtimeDeps
+}
+
+// NewBatchProcessor creates a processor running process for at most
+// concurrency records at once. A nil ledger processes every delivery.
+func NewBatchProcessor(process func(ctx context.Context, record BatchRecord) error, concurrency int, ledger MessageLedger, opts ...RuntimeOption) *BatchProcessor {
+	if concurrency < 1 {
+		concurrency = DefaultBatchConcurrency
+	}
+	return &BatchProcessor{
+		process:     process,
+		concurrency: concurrency,
+		ledger:      ledger,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// Process runs every record and returns the IDs of those that failed, in
+// batch order
+func (p *BatchProcessor) Process(ctx context.Context, records []BatchRecord) []string {
+	failed := make([]bool, len(records))
+	semaphore := make(chan struct{}, p.concurrency)
+	var wg sync.WaitGroup
+	for i, record := range records {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			failed[i] = p.processRecord(ctx, record) != nil
+		}()
+	}
+	wg.Wait()
+
+	var failures []string
+	for i, record := range records {
+		if failed[i] {
+			failures = append(failures, record.MessageID)
+		}
+	}
+	return failures
+}
+
+// processRecord processes one record unless the ledger shows it was already
+// processed. Only a failure to process, or to claim, fails the record.
+func (p *BatchProcessor) processRecord(ctx context.Context, record BatchRecord) error {
+	ctx = WithLogAttrs(ctx, LogKeyMessageID, record.MessageID)
+	logger := LoggerFromContext(ctx)
+
+	if p.ledger != nil {
+		err := p.ledger.Claim(ctx, record.ledgerKey(), p.clock.Now().Add(messageClaimLease))
+		if errors.Is(err, ErrReplayed) {
+			logger.Info("duplicate message skipped")
+			return nil
+		}
+		if err != nil {
+			logger.Error("failed to claim message", LogKeyError, err)
+			return err
+		}
+	}
+
+	if err := p.process(ctx, record); err != nil {
+		logger.Error("message processing failed", LogKeyError, err)
+		if p.ledger != nil {
+			if releaseErr := p.ledger.Release(ctx, record.ledgerKey()); releaseErr != nil {
+				logger.Warn("failed to release message claim", LogKeyError, releaseErr)
+			}
+		}
+		return err
+	}
+
+	// The message was processed, so failing to record that only risks a
+	// duplicate if SQS delivers it again after the claim lapses
+	if p.ledger != nil {
+		if err := p.ledger.Complete(ctx, record.ledgerKey(), p.clock.Now().Add(processedMessageWindow)); err != nil {
+			logger.Warn("failed to record processed message", LogKeyError, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatchProcessorReportsOnlyFailures(t *testing.T) {
+	var running, peak atomic.Int32
+	process := func(ctx context.Context, record BatchRecord) error {
+		now := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if record.Body == "bad" {
+			return errors.New("webhook returned 500")
+		}
+		return nil
+	}
+	processor := NewBatchProcessor(process, 2, nil)
+
+	records := []BatchRecord{
+		{MessageID: "m-1", Body: "ok"},
+		{MessageID: "m-2", Body: "bad"},
+		{MessageID: "m-3", Body: "ok"},
+		{MessageID: "m-4", Body: "bad"},
+		{MessageID: "m-5", Body: "ok"},
+	}
+	failures := processor.Process(context.Background(), records)
+	if !slices.Equal(failures, []string{"m-2", "m-4"}) {
+		t.Errorf("expected only the failed records in batch order, got %v", failures)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 records at once, got %d", peak.Load())
+	}
+	if processor.Process(context.Background(), nil) != nil {
+		t.Error("expected an empty batch to report no failures")
+	}
+}
+
+func TestBatchProcessorIdempotency(t *testing.T) {
+	var mu sync.Mutex
+	processed := map[string]int{}
+	fail := map[string]bool{"m-2": true}
+	process := func(ctx context.Context, record BatchRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		processed[record.MessageID]++
+		if fail[record.MessageID] {
+			return errors.New("webhook returned 500")
+		}
+		return nil
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ledger := NewMemoryMessageLedger(WithClock(fixedClock{now: now}))
+	processor := NewBatchProcessor(process, 4, ledger, WithClock(fixedClock{now: now}))
+
+	// A duplicate within the batch is processed once
+	failures := processor.Process(context.Background(), []BatchRecord{{MessageID: "m-1"}, {MessageID: "m-2"}, {MessageID: "m-1"}})
+	if !slices.Equal(failures, []string{"m-2"}) || processed["m-1"] != 1 {
+		t.Fatalf("expected m-1 once and m-2 to fail, got failures %v and counts %v", failures, processed)
+	}
+
+	// Redelivered, the failed record is processed again and the delivered one is not
+	fail["m-2"] = false
+	failures = processor.Process(context.Background(), []BatchRecord{{MessageID: "m-1"}, {MessageID: "m-2"}})
+	if len(failures) != 0 || processed["m-1"] != 1 || processed["m-2"] != 2 {
+		t.Errorf("expected only the released record to be retried, got failures %v and counts %v", failures, processed)
+	}
+
+	// A replay of a delivered notification is a new message with the same
+	// notification ID, and is not posted again
+	replayed := BatchRecord{MessageID: "m-3", Headers: map[string]string{NotificationIDHeader: "m-1"}}
+	failures = processor.Process(context.Background(), []BatchRecord{replayed})
+	if len(failures) != 0 || processed["m-3"] != 0 {
+		t.Errorf("expected the replay of a delivered notification to be skipped, got failures %v and counts %v", failures, processed)
+	}
+}
+
+type failingLedger struct{ MessageLedger }
+
+func (failingLedger) Claim(ctx context.Context, id string, expiresAt time.Time) error {
+	return errors.New("table unavailable")
+}
+
+func TestBatchProcessorClaimFailure(t *testing.T) {
+	called := false
+	processor := NewBatchProcessor(func(ctx context.Context, record BatchRecord) error {
+		called = true
+		return nil
+	}, 1, failingLedger{})
+	failures := processor.Process(context.Background(), []BatchRecord{{MessageID: "m-1"}})
+	if called || !slices.Equal(failures, []string{"m-1"}) {
+		t.Errorf("expected an unclaimed record to fail without being processed, got %v", failures)
+	}
+}
+
+func TestMemoryMessageLedger(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	ledger := NewMemoryMessageLedger(WithClock(fixedClock{now: now}))
+
+	if err := ledger.Claim(ctx, "m-1", now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ledger.Claim(ctx, "m-1", now.Add(time.Minute)); !errors.Is(err, ErrReplayed) {
+		t.Errorf("expected a held claim to be refused, got %v", err)
+	}
+	ledger.Release(ctx, "m-1")
+	if err := ledger.Claim(ctx, "m-1", now.Add(-time.Second)); err != nil {
+		t.Errorf("expected a released message to be claimable, got %v", err)
+	}
+	if err := ledger.Claim(ctx, "m-1", now.Add(time.Minute)); err != nil {
+		t.Errorf("expected an expired claim to be overwritten, got %v", err)
+	}
+}
+
+func TestLoadWorkerConfigFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	config, err := LoadWorkerConfigFromEnv()
+	if err != nil || config.Concurrency != DefaultBatchConcurrency || config.IdempotencyTable != "" {
+		t.Errorf("expected defaults, got %+v, %v", config, err)
+	}
+
+	t.Setenv("A2A_WORKER_CONCURRENCY", "25")
+	t.Setenv("A2A_WORKER_IDEMPOTENCY_TABLE", "a2a-delivered")
+	config, err = LoadWorkerConfigFromEnv()
+	if err != nil || config.Concurrency != 25 || config.IdempotencyTable != "a2a-delivered" {
+		t.Errorf("expected the environment to be read, got %+v, %v", config, err)
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		t.Setenv("A2A_WORKER_CONCURRENCY", value)
+		if _, err := LoadWorkerConfigFromEnv(); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
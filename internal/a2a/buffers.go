@@ -0,0 +1,52 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// maxPooledBufferSize caps the buffers kept for reuse. A buffer grown for
+// one large file part is left to the garbage collector rather than pinning
+// that memory for the life of the environment.
+const maxPooledBufferSize = 4 << 20
+
+var bufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// GetBuffer returns an empty buffer from a pool shared by the handler and
+// storage. Bodies and stored items are encoded and decoded through these
+// rather than a fresh slice each time, which keeps the garbage collector
+// quiet on small Lambda memory sizes.
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer returns buf to the pool. Nothing may refer to its bytes afterwards.
+func PutBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// MarshalJSONString encodes v as json.Marshal does, into the string a
+// DynamoDB attribute or response body takes. The encoding is copied once,
+// where string(json.Marshal(v)) copies it twice.
+func MarshalJSONString(v any) (string, error) {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+// UnmarshalJSONString decodes JSON held in a string, such as a stored item,
+// through a pooled buffer rather than a fresh []byte copy
+func UnmarshalJSONString(data string, v any) error {
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	buf.WriteString(data)
+	return json.Unmarshal(buf.Bytes(), v)
+}
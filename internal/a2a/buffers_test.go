@@ -0,0 +1,74 @@
+package a2a
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestMarshalJSONString(t *testing.T) {
+	values := []any{
+		a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+		map[string]string{"html": "<b>&</b>"},
+		json.RawMessage(`{"raw":true}`),
+		nil,
+	}
+	for _, value := range values {
+		expected, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := MarshalJSONString(value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != string(expected) {
+			t.Errorf("expected %s, got %s", expected, got)
+		}
+	}
+
+	if _, err := MarshalJSONString(func() {}); err == nil {
+		t.Error("expected an unencodable value to fail")
+	}
+}
+
+func TestUnmarshalJSONString(t *testing.T) {
+	var task a2a.Task
+	if err := UnmarshalJSONString(`{"ID":"task-1","ContextID":"ctx-1"}`, &task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second decode reuses the buffer, which must not leak into the first
+	var other a2a.Task
+	if err := UnmarshalJSONString(`{"ID":"task-2","ContextID":"ctx-2"}`, &other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.ID != "task-1" || task.ContextID != "ctx-1" || other.ID != "task-2" {
+		t.Errorf("expected each decode to keep its own values, got %+v and %+v", task, other)
+	}
+	if err := UnmarshalJSONString(`{"ID":`, &task); err == nil {
+		t.Error("expected malformed JSON to fail")
+	}
+}
+
+func TestPutBufferDropsLargeBuffers(t *testing.T) {
+	buf := GetBuffer()
+	buf.WriteString(strings.Repeat("x", maxPooledBufferSize+1))
+	PutBuffer(buf)
+	if buf.Len() == 0 {
+		t.Error("expected an oversized buffer to be left as it was rather than reset for reuse")
+	}
+}
+
+func BenchmarkMarshalJSONString(b *testing.B) {
+	task := a2a.Task{ID: "task-1", ContextID: "ctx-1", History: []a2a.Message{
+		{MessageID: "m-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.FilePart{Kind: "file", File: a2a.FilePartFile{Bytes: strings.Repeat("QUJD", 64<<10)}}}},
+	}}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := MarshalJSONString(task); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
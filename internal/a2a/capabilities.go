@@ -0,0 +1,20 @@
+package a2a
+
+import (
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// WithDeploymentCapabilities publishes the capabilities a deployment
+// actually serves, replacing whatever the card claimed: streaming with a
+// streaming entrypoint, push notifications with a notification queue, and
+// state transition history with an event store keeping each status update
+// for tasks/resubscribe to replay
+func WithDeploymentCapabilities(features DeploymentFeatures) agentcard.Option {
+	return func(card *a2a.AgentCard) {
+		streaming, push, history := features.Streaming, features.PushNotifications, features.StateTransitionHistory
+		card.Capabilities.Streaming = &streaming
+		card.Capabilities.PushNotifications = &push
+		card.Capabilities.StateTransitionHistory = &history
+	}
+}
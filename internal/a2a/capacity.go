@@ -0,0 +1,124 @@
+package a2a
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// CapacityMeter sums the DynamoDB capacity units consumed through a client,
+// by operation, for load tests and capacity planning. Register it with
+// dynamodb.NewFromConfig(cfg, meter.ClientOption).
+type CapacityMeter struct {
+	mu    sync.Mutex
+	units map[string]float64
+	calls map[string]int
+}
+
+// CapacityUsage is the capacity consumed by one DynamoDB operation
+type CapacityUsage struct {
+	Operation string
+	Calls     int
+	Units     float64
+}
+
+// NewCapacityMeter creates an empty meter
+func NewCapacityMeter() *CapacityMeter {
+	return &CapacityMeter{units: make(map[string]float64), calls: make(map[string]int)}
+}
+
+// ClientOption asks DynamoDB to return the consumed capacity of every call
+// made by the client and records it
+func (m *CapacityMeter) ClientOption(o *dynamodb.Options) {
+	o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("A2ACapacityMeter", m.handleInitialize), middleware.After)
+	})
+}
+
+func (m *CapacityMeter) handleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	operation := requestConsumedCapacity(in.Parameters)
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	if err == nil && operation != "" {
+		m.record(operation, consumedCapacity(out.Result))
+	}
+	return out, metadata, err
+}
+
+func (m *CapacityMeter) record(operation string, consumed []types.ConsumedCapacity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[operation]++
+	for _, capacity := range consumed {
+		if capacity.CapacityUnits != nil {
+			m.units[operation] += *capacity.CapacityUnits
+		}
+	}
+}
+
+// Usage returns the capacity consumed so far by each operation
+func (m *CapacityMeter) Usage() []CapacityUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage := make([]CapacityUsage, 0, len(m.calls))
+	for operation, calls := range m.calls {
+		usage = append(usage, CapacityUsage{Operation: operation, Calls: calls, Units: m.units[operation]})
+	}
+	return usage
+}
+
+// requestConsumedCapacity sets ReturnConsumedCapacity on the operations the
+// stores use and returns the operation name, or "" for any other input
+func requestConsumedCapacity(input any) string {
+	switch input := input.(type) {
+	case *dynamodb.GetItemInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "GetItem"
+	case *dynamodb.PutItemInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "PutItem"
+	case *dynamodb.UpdateItemInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "UpdateItem"
+	case *dynamodb.DeleteItemInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "DeleteItem"
+	case *dynamodb.QueryInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "Query"
+	case *dynamodb.BatchWriteItemInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "BatchWriteItem"
+	case *dynamodb.TransactWriteItemsInput:
+		input.ReturnConsumedCapacity = types.ReturnConsumedCapacityTotal
+		return "TransactWriteItems"
+	}
+	return ""
+}
+
+// consumedCapacity returns the capacity reported in an operation's output
+func consumedCapacity(output any) []types.ConsumedCapacity {
+	var single *types.ConsumedCapacity
+	switch output := output.(type) {
+	case *dynamodb.GetItemOutput:
+		single = output.ConsumedCapacity
+	case *dynamodb.PutItemOutput:
+		single = output.ConsumedCapacity
+	case *dynamodb.UpdateItemOutput:
+		single = output.ConsumedCapacity
+	case *dynamodb.DeleteItemOutput:
+		single = output.ConsumedCapacity
+	case *dynamodb.QueryOutput:
+		single = output.ConsumedCapacity
+	case *dynamodb.BatchWriteItemOutput:
+		return output.ConsumedCapacity
+	case *dynamodb.TransactWriteItemsOutput:
+		return output.ConsumedCapacity
+	}
+	if single == nil {
+		return nil
+	}
+	return []types.ConsumedCapacity{*single}
+}
@@ -0,0 +1,57 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestCapacityMeter(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request struct {
+			ReturnConsumedCapacity string
+		}
+		json.Unmarshal(body, &request)
+		requested = append(requested, request.ReturnConsumedCapacity)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.Write([]byte(`{"ConsumedCapacity":{"TableName":"tasks","CapacityUnits":1.5}}`))
+	}))
+	defer server.Close()
+
+	meter := NewCapacityMeter()
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}, meter.ClientOption)
+	store := NewAWSTaskStore(client, "tasks", "")
+
+	ctx := context.Background()
+	store.SaveTask(ctx, a2a.Task{ID: "task-1", ContextID: "ctx-1"})
+	store.SaveTask(ctx, a2a.Task{ID: "task-2", ContextID: "ctx-1"})
+	store.GetTask(ctx, "task-1")
+
+	for _, value := range requested {
+		if value != "TOTAL" {
+			t.Errorf("expected every call to ask for consumed capacity, got %v", requested)
+			break
+		}
+	}
+	usage := make(map[string]CapacityUsage)
+	for _, u := range meter.Usage() {
+		usage[u.Operation] = u
+	}
+	if len(usage) != 2 || usage["PutItem"].Calls != 2 || usage["PutItem"].Units != 3 || usage["GetItem"].Units != 1.5 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
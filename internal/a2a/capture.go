@@ -0,0 +1,137 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCaptureNotFound is returned when no capture exists for a request ID,
+// either because it was never captured or because it expired
+var ErrCaptureNotFound = errors.New("capture not found")
+
+// CapturedExchange is one JSON-RPC request and the response it got, with
+// credentials redacted, kept for support investigations
+type CapturedExchange struct {
+	RequestID  string          `json:"request_id"`
+	CapturedAt time.Time       `json:"captured_at"`
+	Method     string          `json:"method,omitempty"`
+	Request    json.RawMessage `json:"request"`
+	Status     int             `json:"status"`
+	Response   json.RawMessage `json:"response"`
+}
+
+// CaptureStore persists captured exchanges until their TTL passes
+type CaptureStore interface {
+	SaveCapture(ctx context.Context, exchange CapturedExchange) error
+	// GetCapture returns ErrCaptureNotFound for unknown or expired request IDs
+	GetCapture(ctx context.Context, requestID string) (CapturedExchange, error)
+}
+
+// CaptureConfig selects where captured exchanges are kept. Capture is off
+// unless Table or S3URI is set.
+type CaptureConfig struct {
+	Table string
+	S3URI string
+	TTL   time.Duration
+}
+
+// Enabled reports whether a capture destination is configured
+func (c CaptureConfig) Enabled() bool {
+	return c.Table != "" || c.S3URI != ""
+}
+
+// LoadCaptureConfigFromEnv reads A2A_CAPTURE_TABLE or A2A_CAPTURE_S3_URI, and
+// A2A_CAPTURE_TTL (default 24h). Captures hold message content, so turn them
+// on only while investigating.
+func LoadCaptureConfigFromEnv() (CaptureConfig, error) {
+	config := CaptureConfig{
+		Table: getEnvOrDefault("A2A_CAPTURE_TABLE", ""),
+		S3URI: getEnvOrDefault("A2A_CAPTURE_S3_URI", ""),
+		TTL:   24 * time.Hour,
+	}
+	if config.Table != "" && config.S3URI != "" {
+		return CaptureConfig{}, errors.New("set only one of A2A_CAPTURE_TABLE and A2A_CAPTURE_S3_URI")
+	}
+	if config.S3URI != "" {
+		if _, _, err := parseS3Location(config.S3URI); err != nil {
+			return CaptureConfig{}, fmt.Errorf("A2A_CAPTURE_S3_URI: %w", err)
+		}
+	}
+
+	if value := getEnvOrDefault("A2A_CAPTURE_TTL", ""); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return CaptureConfig{}, fmt.Errorf("A2A_CAPTURE_TTL must be a positive duration, got %q", value)
+		}
+		config.TTL = ttl
+	}
+
+	return config, nil
+}
+
+// NewCapturedExchange builds a capture from a raw request and response body.
+// Values under credential keys are replaced with RedactedValue; a body that
+// is not JSON is kept as a JSON string.
+func NewCapturedExchange(requestID string, capturedAt time.Time, requestBody string, status int, responseBody string) CapturedExchange {
+	exchange := CapturedExchange{
+		RequestID:  requestID,
+		CapturedAt: capturedAt,
+		Status:     status,
+	}
+
+	request, ok := decodeCaptureBody(requestBody)
+	if object, isObject := request.(map[string]interface{}); ok && isObject {
+		if method, isString := object["method"].(string); isString {
+			exchange.Method = method
+		}
+	}
+	exchange.Request = encodeCaptureBody(request)
+	response, _ := decodeCaptureBody(responseBody)
+	exchange.Response = encodeCaptureBody(response)
+	return exchange
+}
+
+// decodeCaptureBody decodes and redacts a JSON body, or returns the body as
+// a string when it is not JSON
+func decodeCaptureBody(body string) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(body)))
+	// Keep large IDs and numbers in messages exactly as sent
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return body, false
+	}
+	return redactCaptureValue(value), true
+}
+
+func encodeCaptureBody(value interface{}) json.RawMessage {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// redactCaptureValue replaces the values of credential keys, at any depth.
+// Keys match case-insensitively, so Go-named fields such as Token match too.
+func redactCaptureValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if (&logRules{}).isRedactedKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactCaptureValue(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactCaptureValue(nested)
+		}
+	}
+	return value
+}
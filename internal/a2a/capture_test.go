@@ -0,0 +1,215 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestNewCapturedExchange(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	request := `{"jsonrpc":"2.0","id":12345678901234567890,"method":"message/send","params":{"Message":{"Parts":[{"Kind":"text","Text":"hi"}]},"Configuration":{"PushNotificationConfig":{"URL":"https://hook.example.com","Token":"push-token","Authentication":{"Schemes":["Bearer"],"Credentials":"hook-secret"}}},"metadata":{"api_key":"k"}}}`
+	response := `{"jsonrpc":"2.0","id":12345678901234567890,"result":{"ID":"task-1"}}`
+
+	exchange := NewCapturedExchange("req-1", now, request, http.StatusOK, response)
+
+	if exchange.RequestID != "req-1" || !exchange.CapturedAt.Equal(now) || exchange.Method != "message/send" || exchange.Status != http.StatusOK {
+		t.Errorf("unexpected exchange fields: %+v", exchange)
+	}
+	for _, secret := range []string{"push-token", "hook-secret", `"k"`} {
+		if strings.Contains(string(exchange.Request), secret) {
+			t.Errorf("expected %s to be redacted from %s", secret, exchange.Request)
+		}
+	}
+	for _, kept := range []string{"12345678901234567890", `"Text":"hi"`, "https://hook.example.com", RedactedValue} {
+		if !strings.Contains(string(exchange.Request), kept) {
+			t.Errorf("expected %s in %s", kept, exchange.Request)
+		}
+	}
+	if !strings.Contains(string(exchange.Response), `"ID":"task-1"`) {
+		t.Errorf("expected the response to be kept, got %s", exchange.Response)
+	}
+
+	// A body that is not JSON is kept as a string
+	exchange = NewCapturedExchange("req-2", now, "not json", http.StatusOK, "")
+	if string(exchange.Request) != `"not json"` || exchange.Method != "" {
+		t.Errorf("expected the raw body as a string, got %s", exchange.Request)
+	}
+}
+
+func TestLoadCaptureConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectEnabled bool
+		expectTTL     time.Duration
+		expectError   bool
+	}{
+		{name: "off by default", expectTTL: 24 * time.Hour},
+		{name: "table", env: map[string]string{"A2A_CAPTURE_TABLE": "captures"}, expectEnabled: true, expectTTL: 24 * time.Hour},
+		{name: "s3 with ttl", env: map[string]string{"A2A_CAPTURE_S3_URI": "s3://bucket/captures", "A2A_CAPTURE_TTL": "2h"}, expectEnabled: true, expectTTL: 2 * time.Hour},
+		{name: "both destinations", env: map[string]string{"A2A_CAPTURE_TABLE": "captures", "A2A_CAPTURE_S3_URI": "s3://bucket/captures"}, expectError: true},
+		{name: "invalid s3 uri", env: map[string]string{"A2A_CAPTURE_S3_URI": "bucket/captures"}, expectError: true},
+		{name: "invalid ttl", env: map[string]string{"A2A_CAPTURE_TABLE": "captures", "A2A_CAPTURE_TTL": "a day"}, expectError: true},
+		{name: "zero ttl", env: map[string]string{"A2A_CAPTURE_TABLE": "captures", "A2A_CAPTURE_TTL": "0s"}, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.env {
+				os.Setenv(key, value)
+			}
+
+			config, err := LoadCaptureConfigFromEnv()
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.Enabled() != tt.expectEnabled || config.TTL != tt.expectTTL {
+				t.Errorf("unexpected config: %+v", config)
+			}
+		})
+	}
+}
+
+func TestS3CaptureStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	objects := &fakeS3{objects: map[string]string{}}
+	exchange := NewCapturedExchange("req/1", now, `{"method":"tasks/get"}`, http.StatusOK, `{}`)
+
+	store, err := NewS3CaptureStore(objects, "s3://bucket/captures", "billing#", time.Hour, WithClock(fixedClock{now: now.Add(time.Minute)}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.SaveCapture(ctx, exchange); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := objects.objects["bucket/captures/billing_req_1.json"]; !ok {
+		t.Errorf("expected a sanitized, prefixed object name, got %v", objects.objects)
+	}
+
+	got, err := store.GetCapture(ctx, "req/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Method != "tasks/get" || !got.CapturedAt.Equal(now) {
+		t.Errorf("unexpected capture: %+v", got)
+	}
+
+	if _, err := store.GetCapture(ctx, "req-2"); !errors.Is(err, ErrCaptureNotFound) {
+		t.Errorf("expected ErrCaptureNotFound for a missing capture, got %v", err)
+	}
+
+	expired, _ := NewS3CaptureStore(objects, "s3://bucket/captures", "billing#", time.Hour, WithClock(fixedClock{now: now.Add(time.Hour)}))
+	if _, err := expired.GetCapture(ctx, "req/1"); !errors.Is(err, ErrCaptureNotFound) {
+		t.Errorf("expected ErrCaptureNotFound once the TTL passed, got %v", err)
+	}
+
+	if err := store.DeleteCapture(ctx, "req/1"); err != nil || len(objects.objects) != 0 {
+		t.Errorf("expected the capture deleted, got %v, %v", objects.objects, err)
+	}
+}
+
+// captureItemDynamoDB answers every GetItem with item, or no item when nil
+func captureItemDynamoDB(t *testing.T, item map[string]any) *dynamodb.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		body := map[string]any{}
+		if item != nil {
+			body["Item"] = item
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	})
+}
+
+func TestAWSCaptureStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	exchange := NewCapturedExchange("req-1", now, `{"method":"tasks/get"}`, http.StatusOK, `{}`)
+
+	client, requests := recordingDynamoDB(t)
+	if err := NewAWSCaptureStore(client, "captures", "billing#", time.Hour).SaveCapture(ctx, exchange); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	put := (*requests)[0]
+	if got := attributeS(put, "Item", "request_id"); got != "billing#req-1" {
+		t.Errorf("expected a prefixed key, got %s", got)
+	}
+	expiresAt, _ := put["Item"].(map[string]any)["expires_at"].(map[string]any)["N"].(string)
+	if want := "1754053200"; expiresAt != want {
+		t.Errorf("expected expires_at %s, got %s", want, expiresAt)
+	}
+
+	if err := NewAWSCaptureStore(client, "captures", "billing#", time.Hour).DeleteCapture(ctx, "req-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := attributeS((*requests)[1], "Key", "request_id"); got != "billing#req-1" {
+		t.Errorf("expected the prefixed key deleted, got %s", got)
+	}
+
+	// Too large for an item
+	large := NewCapturedExchange("req-2", now, `"`+strings.Repeat("x", maxCaptureItemBytes)+`"`, http.StatusOK, `{}`)
+	if err := NewAWSCaptureStore(client, "captures", "", time.Hour).SaveCapture(ctx, large); err == nil {
+		t.Error("expected an error for an exchange over the item limit")
+	}
+
+	exchangeData, _ := json.Marshal(exchange)
+	item := map[string]any{
+		"request_id": map[string]any{"S": "req-1"},
+		"exchange":   map[string]any{"S": string(exchangeData)},
+		"expires_at": map[string]any{"N": "1754053200"},
+	}
+	tests := []struct {
+		name           string
+		item           map[string]any
+		now            time.Time
+		expectNotFound bool
+	}{
+		{name: "found", item: item, now: now.Add(time.Minute)},
+		{name: "missing", now: now, expectNotFound: true},
+		{name: "expired but not yet deleted", item: item, now: now.Add(time.Hour), expectNotFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewAWSCaptureStore(captureItemDynamoDB(t, tt.item), "captures", "", time.Hour, WithClock(fixedClock{now: tt.now}))
+			got, err := store.GetCapture(ctx, "req-1")
+			if tt.expectNotFound {
+				if !errors.Is(err, ErrCaptureNotFound) {
+					t.Errorf("expected ErrCaptureNotFound, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.RequestID != "req-1" || got.Method != "tasks/get" {
+				t.Errorf("unexpected capture: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// DefaultCardPollInterval is how often warm instances check an agent card
+// source for changes
+const DefaultCardPollInterval = time.Minute
+
+// ErrCardUnchanged is returned by a CardSource whose card is still at the
+// version the caller has
+var ErrCardUnchanged = errors.New("agent card unchanged")
+
+// CardSource loads an agent card document kept apart from the config, so
+// the card can change without a deployment. version is the one the caller
+// already has, empty at first; latest identifies the card returned.
+type CardSource func(ctx context.Context, version string) (card a2a.AgentCard, latest string, err error)
+
+// Environment variables selecting where the agent card document is loaded from
+var cardSourceEnvVars = []string{
+	"A2A_AGENT_CARD_SSM_PARAMETER",
+	"A2A_AGENT_CARD_S3_URI",
+}
+
+// LoadCardSourceFromEnv returns the card source selected by the
+// A2A_AGENT_CARD_* variables, or nil when the card comes with the config
+func LoadCardSourceFromEnv(clients ConfigSourceClients) (CardSource, error) {
+	var selected []string
+	for _, name := range cardSourceEnvVars {
+		if os.Getenv(name) != "" {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) > 1 {
+		return nil, fmt.Errorf("only one agent card source may be set, got %s", strings.Join(selected, ", "))
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	value := os.Getenv(selected[0])
+	if selected[0] == "A2A_AGENT_CARD_SSM_PARAMETER" {
+		return NewSSMCardSource(clients.SSM, value), nil
+	}
+	bucket, key, err := parseS3URI("A2A_AGENT_CARD_S3_URI", value)
+	if err != nil {
+		return nil, err
+	}
+	return NewS3CardSource(clients.S3, bucket, key), nil
+}
+
+// LoadCardPollInterval reads A2A_AGENT_CARD_POLL_INTERVAL
+func LoadCardPollInterval() (time.Duration, error) {
+	value := getEnvOrDefault("A2A_AGENT_CARD_POLL_INTERVAL", "")
+	if value == "" {
+		return DefaultCardPollInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("A2A_AGENT_CARD_POLL_INTERVAL must be a positive duration such as 30s, got %q", value)
+	}
+	return interval, nil
+}
+
+// NewSSMCardSource loads a JSON agent card stored in a Parameter Store
+// parameter, versioned by the parameter's version
+func NewSSMCardSource(client SSMParameterAPI, name string) CardSource {
+	return func(ctx context.Context, version string) (a2a.AgentCard, string, error) {
+		output, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("failed to get agent card parameter %s: %w", name, err)
+		}
+		if output.Parameter == nil || output.Parameter.Value == nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("agent card parameter %s has no value", name)
+		}
+		latest := strconv.FormatInt(output.Parameter.Version, 10)
+		if latest == version {
+			return a2a.AgentCard{}, version, ErrCardUnchanged
+		}
+
+		card, err := DecodeAgentCard([]byte(*output.Parameter.Value))
+		if err != nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("agent card parameter %s: %w", name, err)
+		}
+		return card, latest, nil
+	}
+}
+
+// NewS3CardSource loads a JSON agent card stored as an S3 object, versioned
+// by its ETag. An unchanged object is not downloaded again.
+func NewS3CardSource(client S3ObjectAPI, bucket, key string) CardSource {
+	return func(ctx context.Context, version string) (a2a.AgentCard, string, error) {
+		input := &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}
+		if version != "" {
+			input.IfNoneMatch = aws.String(version)
+		}
+		output, err := client.GetObject(ctx, input)
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotModified {
+			return a2a.AgentCard{}, version, ErrCardUnchanged
+		}
+		if err != nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("failed to get agent card object s3://%s/%s: %w", bucket, key, err)
+		}
+		defer output.Body.Close()
+
+		data, err := io.ReadAll(output.Body)
+		if err != nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("failed to read agent card object s3://%s/%s: %w", bucket, key, err)
+		}
+		card, err := DecodeAgentCard(data)
+		if err != nil {
+			return a2a.AgentCard{}, "", fmt.Errorf("agent card object s3://%s/%s: %w", bucket, key, err)
+		}
+		return card, aws.ToString(output.ETag), nil
+	}
+}
+
+// DecodeAgentCard decodes an agent card document, written as the
+// agent_card of a config file
+func DecodeAgentCard(data []byte) (a2a.AgentCard, error) {
+	var card a2a.AgentCard
+	if err := FromJSON(data, &card); err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("invalid agent card JSON: %w", err)
+	}
+	return card, nil
+}
+
+// ApplyAgentCard returns config serving card, with the config's security
+// schemes and the interfaces and capabilities its deployment serves, as
+// for a card in the config itself
+func ApplyAgentCard(config ServerlessConfig, card a2a.AgentCard) ServerlessConfig {
+	config.AgentCard = card
+	if config.Security.hasSchemes() {
+		WithSecurity(config.Security)(&config.AgentCard)
+	}
+	features := LambdaDeploymentFeatures(config)
+	WithDeploymentInterfaces(features)(&config.AgentCard)
+	WithDeploymentCapabilities(features)(&config.AgentCard)
+	return config
+}
+
+// CardWatcher keeps the latest agent card from a source on a warm instance,
+// checking for a new version at most once per interval
+type CardWatcher struct {
+	source    CardSource
+	interval  time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	card      a2a.AgentCard
+	version   string
+	checkedAt time.Time
+	loaded    bool
+}
+
+// NewCardWatcher creates a watcher checking source every interval
+func NewCardWatcher(source CardSource, interval time.Duration) *CardWatcher {
+	return &CardWatcher{
+		source:   source,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Check returns the latest card, loading it when the interval has passed.
+// changed reports whether this call loaded a new version. Only the first
+// load can fail; later failures keep the card already loaded.
+func (w *CardWatcher) Check(ctx context.Context) (card a2a.AgentCard, changed bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if w.loaded && now.Sub(w.checkedAt) < w.interval {
+		return w.card, false, nil
+	}
+
+	fresh, version, err := w.source(ctx, w.version)
+	switch {
+	case errors.Is(err, ErrCardUnchanged):
+		w.checkedAt = now
+		return w.card, false, nil
+	case err != nil:
+		if !w.loaded {
+			return a2a.AgentCard{}, false, fmt.Errorf("failed to load agent card: %w", err)
+		}
+		// The served card stays up while the store is unavailable
+		LoggerFromContext(ctx).Warn("agent card refresh failed, serving the current card", LogKeyError, err)
+		w.checkedAt = now
+		return w.card, false, nil
+	}
+
+	w.card, w.version, w.checkedAt, w.loaded = fresh, version, now, true
+	return w.card, true, nil
+}
+
+// Card returns the card last loaded
+func (w *CardWatcher) Card() a2a.AgentCard {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.card
+}
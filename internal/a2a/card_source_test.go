@@ -0,0 +1,226 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const testCardDocument = `{"Name": "Remote Agent", "Description": "v1", "URL": "https://remote.example.com"}`
+
+// versionedSSM serves one parameter with a version
+type versionedSSM struct {
+	value   string
+	version int64
+	err     error
+}
+
+func (f *versionedSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String(f.value), Version: f.version}}, nil
+}
+
+// etagS3 serves one object with an ETag, answering 304 when it matches
+type etagS3 struct {
+	body      string
+	etag      string
+	downloads int
+}
+
+func (f *etagS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	if aws.ToString(params.IfNoneMatch) == f.etag {
+		return nil, &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusNotModified}},
+			Err:      errors.New("not modified"),
+		}}
+	}
+	f.downloads++
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(f.body)), ETag: aws.String(f.etag)}, nil
+}
+
+func TestSSMCardSource(t *testing.T) {
+	client := &versionedSSM{value: testCardDocument, version: 1}
+	source := NewSSMCardSource(client, "/a2a/card")
+
+	card, version, err := source(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.Name != "Remote Agent" || version != "1" {
+		t.Errorf("expected Remote Agent at version 1, got %s at %s", card.Name, version)
+	}
+
+	if _, _, err := source(context.Background(), "1"); !errors.Is(err, ErrCardUnchanged) {
+		t.Errorf("expected ErrCardUnchanged, got %v", err)
+	}
+
+	client.value, client.version = "not json", 2
+	if _, _, err := source(context.Background(), "1"); err == nil || errors.Is(err, ErrCardUnchanged) {
+		t.Errorf("expected a decoding error, got %v", err)
+	}
+}
+
+func TestS3CardSourceSkipsUnchangedObject(t *testing.T) {
+	client := &etagS3{body: testCardDocument, etag: `"abc"`}
+	source := NewS3CardSource(client, "configs", "card.json")
+
+	card, version, err := source(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.Description != "v1" || version != `"abc"` {
+		t.Errorf("expected v1 with the object's ETag, got %s at %s", card.Description, version)
+	}
+
+	if _, _, err := source(context.Background(), version); !errors.Is(err, ErrCardUnchanged) {
+		t.Errorf("expected ErrCardUnchanged, got %v", err)
+	}
+	if client.downloads != 1 {
+		t.Errorf("expected the unchanged object not to be downloaded again, got %d downloads", client.downloads)
+	}
+}
+
+func TestLoadCardSourceFromEnv(t *testing.T) {
+	clients := ConfigSourceClients{
+		SSM: &versionedSSM{value: testCardDocument, version: 1},
+		S3:  &etagS3{body: testCardDocument, etag: `"abc"`},
+	}
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		expectNil   bool
+		expectError bool
+	}{
+		{name: "no source means the config's card", expectNil: true},
+		{name: "parameter", envVars: map[string]string{"A2A_AGENT_CARD_SSM_PARAMETER": "/a2a/card"}},
+		{name: "object", envVars: map[string]string{"A2A_AGENT_CARD_S3_URI": "s3://configs/card.json"}},
+		{name: "bad s3 uri", envVars: map[string]string{"A2A_AGENT_CARD_S3_URI": "configs/card.json"}, expectError: true},
+		{
+			name:        "two sources",
+			envVars:     map[string]string{"A2A_AGENT_CARD_SSM_PARAMETER": "/a2a/card", "A2A_AGENT_CARD_S3_URI": "s3://configs/card.json"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			source, err := LoadCardSourceFromEnv(clients)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectNil {
+				if source != nil {
+					t.Error("expected no source")
+				}
+				return
+			}
+
+			card, _, err := source(context.Background(), "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if card.Name != "Remote Agent" {
+				t.Errorf("expected Remote Agent, got %s", card.Name)
+			}
+		})
+	}
+}
+
+func TestLoadCardPollInterval(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	if interval, err := LoadCardPollInterval(); err != nil || interval != DefaultCardPollInterval {
+		t.Errorf("expected the default interval, got %v, %v", interval, err)
+	}
+	os.Setenv("A2A_AGENT_CARD_POLL_INTERVAL", "30s")
+	if interval, err := LoadCardPollInterval(); err != nil || interval != 30*time.Second {
+		t.Errorf("expected 30s, got %v, %v", interval, err)
+	}
+	os.Setenv("A2A_AGENT_CARD_POLL_INTERVAL", "-1s")
+	if _, err := LoadCardPollInterval(); err == nil {
+		t.Error("expected a negative interval to be rejected")
+	}
+}
+
+func TestCardWatcher(t *testing.T) {
+	client := &versionedSSM{value: testCardDocument, version: 1}
+	watcher := NewCardWatcher(NewSSMCardSource(client, "/a2a/card"), time.Minute)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	watcher.now = func() time.Time { return now }
+
+	card, changed, err := watcher.Check(context.Background())
+	if err != nil || !changed || card.Description != "v1" {
+		t.Fatalf("expected the first check to load v1, got %q changed=%v err=%v", card.Description, changed, err)
+	}
+
+	// A new version is not looked for until the interval passes
+	client.value, client.version = strings.Replace(testCardDocument, "v1", "v2", 1), 2
+	now = now.Add(30 * time.Second)
+	if card, changed, _ := watcher.Check(context.Background()); changed || card.Description != "v1" {
+		t.Errorf("expected v1 within the interval, got %q changed=%v", card.Description, changed)
+	}
+	now = now.Add(time.Minute)
+	if card, changed, _ := watcher.Check(context.Background()); !changed || card.Description != "v2" {
+		t.Errorf("expected v2 after the interval, got %q changed=%v", card.Description, changed)
+	}
+
+	// An unavailable store keeps the card already loaded
+	client.err = errors.New("throttled")
+	now = now.Add(time.Minute)
+	card, changed, err = watcher.Check(context.Background())
+	if err != nil || changed || card.Description != "v2" {
+		t.Errorf("expected v2 to be kept, got %q changed=%v err=%v", card.Description, changed, err)
+	}
+}
+
+func TestCardWatcherFirstLoadFails(t *testing.T) {
+	watcher := NewCardWatcher(NewSSMCardSource(&versionedSSM{err: errors.New("denied")}, "/a2a/card"), time.Minute)
+	if _, _, err := watcher.Check(context.Background()); err == nil {
+		t.Error("expected the first load to fail")
+	}
+}
+
+func TestApplyAgentCardKeepsDeploymentCapabilities(t *testing.T) {
+	config := ServerlessConfig{CloudConfig: CloudProviderConfig{Provider: "aws", AWS: &AWSConfig{DynamoDBTable: "tasks"}}}
+	card, err := DecodeAgentCard([]byte(testCardDocument))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	applied := ApplyAgentCard(config, card)
+	if applied.AgentCard.Name != "Remote Agent" {
+		t.Errorf("expected the stored card, got %s", applied.AgentCard.Name)
+	}
+	history := applied.AgentCard.Capabilities.StateTransitionHistory
+	if history == nil || !*history {
+		t.Error("expected state transition history from the AWS deployment")
+	}
+}
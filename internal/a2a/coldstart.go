@@ -0,0 +1,104 @@
+package a2a
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Cold start phases timed by InitTimer
+const (
+	InitPhaseAWSClients = "aws_clients"
+	InitPhaseConfig     = "config"
+	InitPhaseSecrets    = "secrets"
+	InitPhaseTotal      = "total"
+)
+
+// Log attribute keys for cold start reporting
+const (
+	LogKeyInitMS    = "init_ms"
+	LogKeyColdStart = "cold_start"
+)
+
+// InitTimer measures how long cold start spends constructing AWS clients,
+// loading config and resolving secrets, so the cost of a config choice such
+// as a remote config source shows up as a number rather than a guess
+type InitTimer struct {
+	now      func() time.Time
+	start    time.Time
+	mu       sync.Mutex
+	phases   map[string]time.Duration
+	order    []string
+	total    time.Duration
+	finished bool
+}
+
+// NewInitTimer starts timing cold start
+func NewInitTimer() *InitTimer {
+	return newInitTimer(time.Now)
+}
+
+func newInitTimer(now func() time.Time) *InitTimer {
+	return &InitTimer{now: now, start: now(), phases: make(map[string]time.Duration)}
+}
+
+// Track starts timing phase and returns the function that stops it. Repeated
+// runs of a phase add up, e.g. resolving secrets for every registry agent.
+// After Finish it times nothing, so code shared with warm-start config
+// refreshes can call it unconditionally.
+// Use as: defer timer.Track(InitPhaseSecrets)()
+func (t *InitTimer) Track(phase string) func() {
+	start := t.now()
+	return func() {
+		elapsed := t.now().Sub(start)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.finished {
+			return
+		}
+		if _, ok := t.phases[phase]; !ok {
+			t.order = append(t.order, phase)
+		}
+		t.phases[phase] += elapsed
+	}
+}
+
+// Finish ends cold start. Phases stopped afterwards are not counted.
+func (t *InitTimer) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.finished {
+		t.total = t.now().Sub(t.start)
+		t.finished = true
+	}
+}
+
+// LogAttr returns the phase durations in milliseconds as one init_ms group,
+// e.g. {"init_ms":{"aws_clients":12.5,"config":80.1,"total":95}}
+func (t *InitTimer) LogAttr() slog.Attr {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	args := make([]any, 0, 2*len(t.order)+2)
+	for _, phase := range t.order {
+		args = append(args, phase, milliseconds(t.phases[phase]))
+	}
+	args = append(args, InitPhaseTotal, milliseconds(t.total))
+	return slog.Group(LogKeyInitMS, args...)
+}
+
+// Record reports every phase and the total to metrics. The Lambda
+// entrypoint calls it on the first invocation, since metrics are flushed
+// per invocation and init has none.
+func (t *InitTimer) Record(metrics Metrics) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, phase := range t.order {
+		metrics.RecordInitDuration(phase, t.phases[phase])
+	}
+	metrics.RecordInitDuration(InitPhaseTotal, t.total)
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,71 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// recordingInitMetrics keeps every init duration recorded
+type recordingInitMetrics struct {
+	NoopMetrics
+	durations map[string]time.Duration
+}
+
+func (m *recordingInitMetrics) RecordInitDuration(phase string, duration time.Duration) {
+	m.durations[phase] = duration
+}
+
+func TestInitTimer(t *testing.T) {
+	clock := time.Unix(0, 0)
+	timer := newInitTimer(func() time.Time { return clock })
+	advance := func(d time.Duration) { clock = clock.Add(d) }
+
+	stop := timer.Track(InitPhaseAWSClients)
+	advance(10 * time.Millisecond)
+	stop()
+
+	// Two agents' secrets add up to one phase
+	for i := 0; i < 2; i++ {
+		stop = timer.Track(InitPhaseSecrets)
+		advance(15 * time.Millisecond)
+		stop()
+	}
+
+	advance(5 * time.Millisecond)
+	timer.Finish()
+
+	// A warm config refresh after cold start is not counted
+	stop = timer.Track(InitPhaseConfig)
+	advance(time.Second)
+	stop()
+
+	metrics := &recordingInitMetrics{durations: map[string]time.Duration{}}
+	timer.Record(metrics)
+	expected := map[string]time.Duration{
+		InitPhaseAWSClients: 10 * time.Millisecond,
+		InitPhaseSecrets:    30 * time.Millisecond,
+		InitPhaseTotal:      45 * time.Millisecond,
+	}
+	if len(metrics.durations) != len(expected) {
+		t.Errorf("expected phases %v, got %v", expected, metrics.durations)
+	}
+	for phase, duration := range expected {
+		if metrics.durations[phase] != duration {
+			t.Errorf("expected %s to take %v, got %v", phase, duration, metrics.durations[phase])
+		}
+	}
+
+	var buf bytes.Buffer
+	NewLogger(&buf, nil).Info("cold start complete", timer.LogAttr())
+	var record struct {
+		InitMS map[string]float64 `json:"init_ms"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid log line %q: %v", buf.String(), err)
+	}
+	if record.InitMS[InitPhaseSecrets] != 30 || record.InitMS[InitPhaseTotal] != 45 {
+		t.Errorf("unexpected init_ms %v", record.InitMS)
+	}
+}
@@ -0,0 +1,115 @@
+package a2a
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DataEncodingGzip marks a task_data or event_data attribute holding
+// gzip-compressed JSON as binary
+const DataEncodingGzip = "gzip"
+
+// dataEncodingAttr names the attribute saying how an item's data is encoded.
+// Items without it hold plain JSON, so items written before compression was
+// turned on, or under the threshold, read as they always have.
+const dataEncodingAttr = "data_encoding"
+
+// WithStorageCompression gzips the task and event JSON the DynamoDB stores
+// write when it is longer than threshold bytes, which cuts the item size
+// and write capacity of long conversations. 0 turns compression off.
+// Compressed items are read whatever the setting.
+func WithStorageCompression(threshold int) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.compressAbove = threshold
+	}
+}
+
+// gzipWriters keeps compressors for reuse, since each holds several hundred
+// kilobytes of state
+var gzipWriters = sync.Pool{New: func() any {
+	w, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+	return w
+}}
+
+// storedDataItem adds data to item under name, compressed when it is over
+// the threshold and compressing saves space
+func (d runtimeDeps) storedDataItem(item map[string]types.AttributeValue, name, data string) error {
+	if d.compressAbove <= 0 || len(data) <= d.compressAbove {
+		item[name] = &types.AttributeValueMemberS{Value: data}
+		return nil
+	}
+
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	w := gzipWriters.Get().(*gzip.Writer)
+	defer gzipWriters.Put(w)
+	w.Reset(buf)
+	if _, err := io.WriteString(w, data); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", name, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress %s: %w", name, err)
+	}
+	if buf.Len() >= len(data) {
+		item[name] = &types.AttributeValueMemberS{Value: data}
+		return nil
+	}
+	item[name] = &types.AttributeValueMemberB{Value: bytes.Clone(buf.Bytes())}
+	item[dataEncodingAttr] = &types.AttributeValueMemberS{Value: DataEncodingGzip}
+	return nil
+}
+
+// storedData is an item's data attribute as read, before decompression
+type storedData struct {
+	plain      string
+	compressed []byte
+}
+
+// readStoredData returns the data attribute of item, or false when it has
+// none or holds something other than what storedDataItem writes
+func readStoredData(item map[string]types.AttributeValue, name string) (storedData, bool) {
+	switch value := item[name].(type) {
+	case *types.AttributeValueMemberS:
+		return storedData{plain: value.Value}, true
+	case *types.AttributeValueMemberB:
+		if encoding, ok := item[dataEncodingAttr].(*types.AttributeValueMemberS); ok && encoding.Value == DataEncodingGzip {
+			return storedData{compressed: value.Value}, true
+		}
+	}
+	return storedData{}, false
+}
+
+// size is the length of the JSON, which for gzip is in the stream's
+// trailer, so a read budget can be checked before anything is decompressed
+func (s storedData) size() int {
+	if s.compressed == nil {
+		return len(s.plain)
+	}
+	if len(s.compressed) < 4 {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(s.compressed[len(s.compressed)-4:]))
+}
+
+// json returns the stored JSON, decompressing it if need be
+func (s storedData) json() (string, error) {
+	if s.compressed == nil {
+		return s.plain, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(s.compressed))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress stored data: %w", err)
+	}
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	if _, err := buf.ReadFrom(r); err != nil {
+		return "", fmt.Errorf("failed to decompress stored data: %w", err)
+	}
+	return buf.String(), nil
+}
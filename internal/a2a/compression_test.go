@@ -0,0 +1,111 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// itemsDynamoDB keeps every item put, and returns the last one from
+// GetItem and all of them from Query
+type itemsDynamoDB struct {
+	DynamoDBAPI
+	items []map[string]types.AttributeValue
+}
+
+func (d *itemsDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	d.items = append(d.items, params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (d *itemsDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: d.items[len(d.items)-1]}, nil
+}
+
+func (d *itemsDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{Items: d.items}, nil
+}
+
+func TestAWSTaskStoreCompression(t *testing.T) {
+	ctx := context.Background()
+	long := a2a.Task{ID: "task-1", ContextID: "ctx-1", Metadata: map[string]any{"transcript": strings.Repeat("hello ", 500)}}
+	short := a2a.Task{ID: "task-2", ContextID: "ctx-1"}
+
+	tests := []struct {
+		name           string
+		threshold      int
+		task           a2a.Task
+		expectEncoding string
+	}{
+		{name: "over the threshold", threshold: 1024, task: long, expectEncoding: DataEncodingGzip},
+		{name: "under the threshold", threshold: 1024, task: short},
+		{name: "compression off", threshold: 0, task: long},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &itemsDynamoDB{}
+			store := NewAWSTaskStore(client, "tasks", "", WithStorageCompression(tt.threshold))
+			if err := store.SaveTask(ctx, tt.task); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			item := client.items[0]
+			encoding, _ := item[dataEncodingAttr].(*types.AttributeValueMemberS)
+			switch {
+			case tt.expectEncoding == "" && encoding != nil:
+				t.Errorf("expected plain JSON, got %s", encoding.Value)
+			case tt.expectEncoding != "" && (encoding == nil || encoding.Value != tt.expectEncoding):
+				t.Errorf("expected %s encoding, got %v", tt.expectEncoding, item[dataEncodingAttr])
+			}
+			if data, ok := item["task_data"].(*types.AttributeValueMemberB); ok && len(data.Value) >= 1024 {
+				t.Errorf("expected the compressed task to be smaller, got %d bytes", len(data.Value))
+			}
+
+			got, err := store.GetTask(ctx, tt.task.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			listed, err := store.ListTasks(ctx, tt.task.ContextID)
+			if err != nil || len(listed) != 1 {
+				t.Fatalf("expected the task to be listed, got %d tasks, %v", len(listed), err)
+			}
+			if got.Metadata["transcript"] != tt.task.Metadata["transcript"] || listed[0].Metadata["transcript"] != tt.task.Metadata["transcript"] {
+				t.Errorf("expected the task to read back whole, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestAWSEventStoreCompression(t *testing.T) {
+	ctx := context.Background()
+	client := &itemsDynamoDB{}
+	store := NewAWSEventStore(client, "events", "", WithStorageCompression(256))
+	for _, text := range []string{strings.Repeat("a", 4000), "short", strings.Repeat("b", 4000)} {
+		event := a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1", Metadata: map[string]any{"text": text}}
+		if err := store.SaveEvent(ctx, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := client.items[1]["event_data"].(*types.AttributeValueMemberS); !ok {
+		t.Error("expected the short event to stay plain JSON")
+	}
+
+	events, err := store.GetEvents(ctx, "task-1")
+	if err != nil || len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d, %v", len(events), err)
+	}
+	if text := events[2].(a2a.TaskStatusUpdateEvent).Metadata["text"]; text != strings.Repeat("b", 4000) {
+		t.Errorf("expected the compressed event to read back whole, got %v", text)
+	}
+
+	// The budget is spent at the decompressed size, not the stored one
+	budgeted := NewAWSEventStore(client, "events", "", WithEventReadBudget(6000))
+	if _, err := budgeted.GetEvents(ctx, "task-1"); !errors.Is(err, ErrEventBudgetExceeded) {
+		t.Errorf("expected compressed events to count in full against the budget, got %v", err)
+	}
+}
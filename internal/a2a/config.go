@@ -15,6 +15,7 @@ type CloudProvider string
 const (
 	CloudProviderAWS   CloudProvider = "aws"
 	CloudProviderGCP   CloudProvider = "gcp"
+	CloudProviderAzure CloudProvider = "azure"
 	CloudProviderLocal CloudProvider = "local"
 )
 
@@ -27,12 +28,65 @@ type CloudProviderInterface interface {
 	ValidateConfig() error
 	
 	// GetStorageConfig returns storage configuration for the provider
-	GetStorageConfig() interface{}
+	GetStorageConfig() StorageConfig
 	
 	// GetEventConfig returns event queue configuration for the provider
-	GetEventConfig() interface{}
+	GetEventConfig() EventConfig
 }
 
+// StorageConfig locates the task store of a provider. Only the fields of the
+// Provider's backend are set.
+type StorageConfig struct {
+	Provider CloudProvider
+	Region   string
+
+	// AWS DynamoDB
+	DynamoDBTable       string
+	DynamoDBEventsTable string
+	// DynamoDBPushConfigTable is empty when push configs are not stored
+	DynamoDBPushConfigTable string
+	// DynamoDBCompressAbove is the JSON length in bytes over which task and
+	// event data is gzipped, 0 for never
+	DynamoDBCompressAbove int
+
+	// GCP Firestore
+	ProjectID       string
+	FirestoreDB     string
+	CredentialsPath string
+
+	// Azure Cosmos DB
+	CosmosDBEndpoint string
+	CosmosDBDatabase string
+
+	// Local filesystem
+	Path string
+}
+
+// EventConfig locates the event queue of a provider. Only the fields of the
+// Provider's backend are set.
+type EventConfig struct {
+	Provider CloudProvider
+	Region   string
+
+	// AWS SQS
+	SQSQueueURL string
+
+	// GCP Pub/Sub
+	ProjectID       string
+	PubSubTopic     string
+	CredentialsPath string
+
+	// Azure Service Bus
+	ServiceBusNamespace string
+	ServiceBusQueue     string
+
+	// Local filesystem
+	Path string
+}
+
+// DefaultEventsTable is the DynamoDB event table used when the config names none
+const DefaultEventsTable = "a2a-events"
+
 // AWSProvider implements CloudProviderInterface for AWS
 type AWSProvider struct {
 	Config AWSConfig
@@ -49,28 +103,33 @@ func (p *AWSProvider) ValidateConfig() error {
 }
 
 // GetStorageConfig returns AWS DynamoDB configuration
-func (p *AWSProvider) GetStorageConfig() interface{} {
-	return map[string]string{
-		"table_name": p.Config.DynamoDBTable,
-		"region":     p.Config.Region,
+func (p *AWSProvider) GetStorageConfig() StorageConfig {
+	eventsTable := p.Config.EventsTable
+	if eventsTable == "" {
+		eventsTable = DefaultEventsTable
+	}
+	return StorageConfig{
+		Provider:                CloudProviderAWS,
+		Region:                  p.Config.Region,
+		DynamoDBTable:           p.Config.DynamoDBTable,
+		DynamoDBEventsTable:     eventsTable,
+		DynamoDBCompressAbove:   p.Config.CompressAbove,
+		DynamoDBPushConfigTable: p.Config.PushConfigTable,
 	}
 }
 
 // GetEventConfig returns AWS SQS configuration
-func (p *AWSProvider) GetEventConfig() interface{} {
-	return map[string]string{
-		"queue_url": p.Config.SQSQueueURL,
-		"region":    p.Config.Region,
+func (p *AWSProvider) GetEventConfig() EventConfig {
+	return EventConfig{
+		Provider:    CloudProviderAWS,
+		Region:      p.Config.Region,
+		SQSQueueURL: p.Config.SQSQueueURL,
 	}
 }
 
 // GCPProvider implements CloudProviderInterface for GCP
 type GCPProvider struct {
-	ProjectID     string
-	FirestoreDB   string
-	PubSubTopic   string
-	Region        string
-	CredentialsPath string
+	Config GCPConfig
 }
 
 // GetProviderType returns GCP provider type
@@ -80,38 +139,63 @@ func (p *GCPProvider) GetProviderType() CloudProvider {
 
 // ValidateConfig validates GCP configuration
 func (p *GCPProvider) ValidateConfig() error {
-	if p.ProjectID == "" {
-		return fmt.Errorf("gcp project_id is required")
-	}
-	if p.FirestoreDB == "" {
-		return fmt.Errorf("gcp firestore_db is required")
-	}
-	if p.PubSubTopic == "" {
-		return fmt.Errorf("gcp pubsub_topic is required")
-	}
-	if p.Region == "" {
-		return fmt.Errorf("gcp region is required")
-	}
-	return nil
+	return ValidateGCPConfig(p.Config)
 }
 
 // GetStorageConfig returns GCP Firestore configuration
-func (p *GCPProvider) GetStorageConfig() interface{} {
-	return map[string]string{
-		"project_id":       p.ProjectID,
-		"firestore_db":     p.FirestoreDB,
-		"region":           p.Region,
-		"credentials_path": p.CredentialsPath,
+func (p *GCPProvider) GetStorageConfig() StorageConfig {
+	return StorageConfig{
+		Provider:        CloudProviderGCP,
+		Region:          p.Config.Region,
+		ProjectID:       p.Config.ProjectID,
+		FirestoreDB:     p.Config.FirestoreDB,
+		CredentialsPath: p.Config.CredentialsPath,
 	}
 }
 
 // GetEventConfig returns GCP Pub/Sub configuration
-func (p *GCPProvider) GetEventConfig() interface{} {
-	return map[string]string{
-		"project_id":       p.ProjectID,
-		"pubsub_topic":     p.PubSubTopic,
-		"region":           p.Region,
-		"credentials_path": p.CredentialsPath,
+func (p *GCPProvider) GetEventConfig() EventConfig {
+	return EventConfig{
+		Provider:        CloudProviderGCP,
+		Region:          p.Config.Region,
+		ProjectID:       p.Config.ProjectID,
+		PubSubTopic:     p.Config.PubSubTopic,
+		CredentialsPath: p.Config.CredentialsPath,
+	}
+}
+
+// AzureProvider implements CloudProviderInterface for Azure
+type AzureProvider struct {
+	Config AzureConfig
+}
+
+// GetProviderType returns Azure provider type
+func (p *AzureProvider) GetProviderType() CloudProvider {
+	return CloudProviderAzure
+}
+
+// ValidateConfig validates Azure configuration
+func (p *AzureProvider) ValidateConfig() error {
+	return ValidateAzureConfig(p.Config)
+}
+
+// GetStorageConfig returns Azure Cosmos DB configuration
+func (p *AzureProvider) GetStorageConfig() StorageConfig {
+	return StorageConfig{
+		Provider:         CloudProviderAzure,
+		Region:           p.Config.Region,
+		CosmosDBEndpoint: p.Config.CosmosDBEndpoint,
+		CosmosDBDatabase: p.Config.CosmosDBDatabase,
+	}
+}
+
+// GetEventConfig returns Azure Service Bus configuration
+func (p *AzureProvider) GetEventConfig() EventConfig {
+	return EventConfig{
+		Provider:            CloudProviderAzure,
+		Region:              p.Config.Region,
+		ServiceBusNamespace: p.Config.ServiceBusNamespace,
+		ServiceBusQueue:     p.Config.ServiceBusQueue,
 	}
 }
 
@@ -139,16 +223,18 @@ func (p *LocalProvider) ValidateConfig() error {
 }
 
 // GetStorageConfig returns local storage configuration
-func (p *LocalProvider) GetStorageConfig() interface{} {
-	return map[string]string{
-		"storage_path": p.StoragePath,
+func (p *LocalProvider) GetStorageConfig() StorageConfig {
+	return StorageConfig{
+		Provider: CloudProviderLocal,
+		Path:     p.StoragePath,
 	}
 }
 
 // GetEventConfig returns local event configuration
-func (p *LocalProvider) GetEventConfig() interface{} {
-	return map[string]string{
-		"event_path": p.EventPath,
+func (p *LocalProvider) GetEventConfig() EventConfig {
+	return EventConfig{
+		Provider: CloudProviderLocal,
+		Path:     p.EventPath,
 	}
 }
 
@@ -174,6 +260,13 @@ func (cl *ConfigLoader) LoadServerlessConfig() (ServerlessConfig, error) {
 		return ServerlessConfig{}, fmt.Errorf("failed to load agent card: %w", err)
 	}
 
+	// The card advertises exactly the auth schemes the handler is configured for
+	security, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+	agentCard.SecuritySchemes, agentCard.Security = BuildSecuritySchemes(security)
+
 	// Load cloud provider configuration
 	cloudConfig, err := cl.LoadCloudProviderConfig()
 	if err != nil {
@@ -182,12 +275,62 @@ func (cl *ConfigLoader) LoadServerlessConfig() (ServerlessConfig, error) {
 
 	// Load logging configuration
 	logLevel := getEnvOrDefault("A2A_LOG_LEVEL", "info")
+	logging, err := LoadLoggingConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	// Load push notification webhook restrictions
+	webhooks, err := LoadWebhookConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	retries, err := parseRetryPolicies(getEnvOrDefault("A2A_RETRY_POLICIES", ""))
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+	priorities, err := parseSkillPriorities(getEnvOrDefault("A2A_SKILL_PRIORITIES", ""))
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+	outputSchemas, err := parseOutputSchemas(getEnvOrDefault("A2A_SKILL_OUTPUT_SCHEMAS", ""))
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+	locales, err := parseLocales(getEnvOrDefault("A2A_AGENT_CARD_LOCALES", ""))
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	// Secrets may be ARNs that are resolved later by ResolveConfigSecrets
+	secrets := SecretsConfig{
+		APIKey:               SecretString(getEnvOrDefault("A2A_API_KEY", "")),
+		WebhookSigningKey:    SecretString(getEnvOrDefault("A2A_WEBHOOK_SIGNING_KEY", "")),
+		RequestSigningKey:    SecretString(getEnvOrDefault("A2A_REQUEST_SIGNING_KEY", "")),
+		ContentEncryptionKey: SecretString(getEnvOrDefault("A2A_CONTENT_ENCRYPTION_KEY", "")),
+		AdminAPIKey:          SecretString(getEnvOrDefault("A2A_ADMIN_API_KEY", "")),
+	}
 
 	config := ServerlessConfig{
-		AgentID:     agentID,
-		AgentCard:   agentCard,
-		CloudConfig: cloudConfig,
-		LogLevel:    logLevel,
+		AgentID:       agentID,
+		AgentCard:     agentCard,
+		CloudConfig:   cloudConfig,
+		LogLevel:      logLevel,
+		Logging:       logging,
+		Webhooks:      webhooks,
+		Secrets:       secrets,
+		Security:      security,
+		Retention:     LoadRetentionConfigFromEnv(),
+		TaskTimeout:   getEnvOrDefault("A2A_TASK_TIMEOUT", ""),
+		Retries:       retries,
+		Priorities:    priorities,
+		OutputSchemas: outputSchemas,
+		Localization: LocalizationConfig{
+			DefaultLocale: getEnvOrDefault("A2A_AGENT_CARD_DEFAULT_LOCALE", ""),
+			Locales:       locales,
+		},
+		Admin: LoadAdminConfigFromEnv(),
 	}
 
 	// Validate the complete configuration
@@ -198,33 +341,42 @@ func (cl *ConfigLoader) LoadServerlessConfig() (ServerlessConfig, error) {
 	return config, nil
 }
 
-// LoadCloudProviderConfig loads cloud provider configuration from environment
+// LoadCloudProviderConfig loads cloud provider configuration from environment.
+// The selected provider's section is validated here, so a missing region or
+// table is reported by its config path rather than filled in with a guess.
 func (cl *ConfigLoader) LoadCloudProviderConfig() (CloudProviderConfig, error) {
 	provider := getEnvOrDefault("CLOUD_PROVIDER", "local")
-	
+
+	var config CloudProviderConfig
 	switch CloudProvider(provider) {
 	case CloudProviderAWS:
 		awsConfig, err := cl.loadAWSConfig()
 		if err != nil {
 			return CloudProviderConfig{}, fmt.Errorf("failed to load AWS config: %w", err)
 		}
-		return CloudProviderConfig{
-			Provider: provider,
-			AWS:      &awsConfig,
-		}, nil
-		
+		config = CloudProviderConfig{Provider: provider, AWS: &awsConfig}
+
 	case CloudProviderGCP:
-		// GCP configuration will be implemented in future tasks
-		return CloudProviderConfig{}, fmt.Errorf("GCP provider not yet implemented")
-		
+		gcpConfig := cl.loadGCPConfig()
+		config = CloudProviderConfig{Provider: provider, GCP: &gcpConfig}
+
+	case CloudProviderAzure:
+		azureConfig := cl.loadAzureConfig()
+		config = CloudProviderConfig{Provider: provider, Azure: &azureConfig}
+
 	case CloudProviderLocal:
-		return CloudProviderConfig{
-			Provider: provider,
-		}, nil
-		
+		config = CloudProviderConfig{Provider: provider}
+
 	default:
 		return CloudProviderConfig{}, fmt.Errorf("unsupported cloud provider: %s", provider)
 	}
+
+	var errs ValidationErrors
+	errs.Merge("cloud_config", ValidateCloudProviderConfig(config))
+	if err := errs.Err(); err != nil {
+		return CloudProviderConfig{}, err
+	}
+	return config, nil
 }
 
 // CreateCloudProvider creates a cloud provider instance based on configuration
@@ -241,8 +393,24 @@ func (cl *ConfigLoader) CreateCloudProvider(config CloudProviderConfig) (CloudPr
 		return provider, nil
 		
 	case CloudProviderGCP:
-		// GCP provider will be implemented in future tasks
-		return nil, fmt.Errorf("GCP provider not yet implemented")
+		if config.GCP == nil {
+			return nil, fmt.Errorf("GCP configuration is required for GCP provider")
+		}
+		provider := &GCPProvider{Config: *config.GCP}
+		if err := provider.ValidateConfig(); err != nil {
+			return nil, fmt.Errorf("GCP provider validation failed: %w", err)
+		}
+		return provider, nil
+
+	case CloudProviderAzure:
+		if config.Azure == nil {
+			return nil, fmt.Errorf("Azure configuration is required for Azure provider")
+		}
+		provider := &AzureProvider{Config: *config.Azure}
+		if err := provider.ValidateConfig(); err != nil {
+			return nil, fmt.Errorf("Azure provider validation failed: %w", err)
+		}
+		return provider, nil
 		
 	case CloudProviderLocal:
 		provider := &LocalProvider{
@@ -294,12 +462,18 @@ func (cl *ConfigLoader) loadAgentCard() (a2a.AgentCard, error) {
 		capabilities.Streaming = &streaming
 	}
 
+	skills, err := LoadSkillsFromEnv()
+	if err != nil {
+		return a2a.AgentCard{}, err
+	}
+
 	return a2a.AgentCard{
 		Name:         name,
 		URL:          url,
 		Description:  description,
 		Version:      version,
 		Capabilities: capabilities,
+		Skills:       skills,
 	}, nil
 }
 
@@ -317,13 +491,41 @@ func (cl *ConfigLoader) loadAWSConfig() (AWSConfig, error) {
 		Region:          region,
 		SQSQueueURL:     sqsQueueURL,
 		DynamoDBTable:   dynamoDBTable,
-		AccessKeyID:     accessKeyID,
-		SecretAccessKey: secretAccessKey,
+		AccessKeyID:     SecretString(accessKeyID),
+		SecretAccessKey: SecretString(secretAccessKey),
 	}
 
 	return config, nil
 }
 
+// loadGCPConfig loads GCP configuration from environment variables
+func (cl *ConfigLoader) loadGCPConfig() GCPConfig {
+	return GCPConfig{
+		ProjectID:       getEnvOrDefault("GCP_PROJECT_ID", ""),
+		FirestoreDB:     getEnvOrDefault("GCP_FIRESTORE_DB", ""),
+		PubSubTopic:     getEnvOrDefault("GCP_PUBSUB_TOPIC", ""),
+		// No default region: data residency should be chosen, not inherited
+		Region:          getEnvOrDefault("GCP_REGION", ""),
+		CredentialsPath: getEnvOrDefault("GOOGLE_APPLICATION_CREDENTIALS", ""),
+	}
+}
+
+// loadAzureConfig loads Azure configuration from environment variables
+func (cl *ConfigLoader) loadAzureConfig() AzureConfig {
+	return AzureConfig{
+		// No default region: data residency should be chosen, not inherited
+		Region:              getEnvOrDefault("AZURE_REGION", ""),
+		CosmosDBEndpoint:    getEnvOrDefault("AZURE_COSMOSDB_ENDPOINT", ""),
+		CosmosDBDatabase:    getEnvOrDefault("AZURE_COSMOSDB_DATABASE", ""),
+		ServiceBusNamespace: getEnvOrDefault("AZURE_SERVICEBUS_NAMESPACE", ""),
+		ServiceBusQueue:     getEnvOrDefault("AZURE_SERVICEBUS_QUEUE", ""),
+		// Optional credentials (can use managed identity instead)
+		TenantID:     getEnvOrDefault("AZURE_TENANT_ID", ""),
+		ClientID:     getEnvOrDefault("AZURE_CLIENT_ID", ""),
+		ClientSecret: SecretString(getEnvOrDefault("AZURE_CLIENT_SECRET", "")),
+	}
+}
+
 // getEnvOrDefault gets environment variable value or returns default
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -388,6 +590,13 @@ func ValidateEnvironmentVariables() error {
 				missing = append(missing, env)
 			}
 		}
+	case CloudProviderAzure:
+		azureRequired := []string{"AZURE_COSMOSDB_ENDPOINT", "AZURE_COSMOSDB_DATABASE", "AZURE_SERVICEBUS_NAMESPACE", "AZURE_SERVICEBUS_QUEUE"}
+		for _, env := range azureRequired {
+			if os.Getenv(env) == "" {
+				missing = append(missing, env)
+			}
+		}
 	}
 
 	if len(missing) > 0 {
@@ -0,0 +1,101 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultConfigCacheTTL is how long a remotely loaded config is reused before refreshing
+const DefaultConfigCacheTTL = 5 * time.Minute
+
+// ConfigSource loads a complete configuration from a remote store
+type ConfigSource func(ctx context.Context) (ServerlessConfig, error)
+
+// CachedConfig keeps a remotely loaded config in the execution environment so
+// warm invocations skip the remote call, while still picking up changes once
+// the TTL expires
+type CachedConfig struct {
+	source   ConfigSource
+	ttl      time.Duration
+	now      func() time.Time
+	mu       sync.Mutex
+	config   ServerlessConfig
+	loadedAt time.Time
+	loaded   bool
+}
+
+// NewCachedConfig creates a config cache that refreshes from source after ttl
+func NewCachedConfig(source ConfigSource, ttl time.Duration) *CachedConfig {
+	return &CachedConfig{
+		source: source,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Get returns the cached config, reloading it when the TTL has expired.
+// refreshed reports whether a new config was loaded by this call.
+func (c *CachedConfig) Get(ctx context.Context) (config ServerlessConfig, refreshed bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if c.loaded && now.Sub(c.loadedAt) < c.ttl {
+		return c.config, false, nil
+	}
+
+	fresh, err := c.source(ctx)
+	if err != nil {
+		if !c.loaded {
+			return ServerlessConfig{}, false, fmt.Errorf("failed to load config: %w", err)
+		}
+		// A store outage should not take down warm instances, so keep serving
+		// the last good config and retry after another TTL
+		LoggerFromContext(ctx).Warn("config refresh failed, using cached config", LogKeyError, err)
+		c.loadedAt = now
+		return c.config, false, nil
+	}
+
+	c.config = fresh
+	c.loadedAt = now
+	c.loaded = true
+	return c.config, true, nil
+}
+
+// Reload loads the config now, whatever the TTL, for admin/config/reload.
+// On failure the cached config is kept and the error returned, so the
+// caller learns the new config was not applied.
+func (c *CachedConfig) Reload(ctx context.Context) (ServerlessConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fresh, err := c.source(ctx)
+	if err != nil {
+		return ServerlessConfig{}, fmt.Errorf("failed to load config: %w", err)
+	}
+	c.config = fresh
+	c.loadedAt = c.now()
+	c.loaded = true
+	return c.config, nil
+}
+
+// LoadConfigCacheTTL reads the config cache TTL from A2A_CONFIG_CACHE_TTL
+func LoadConfigCacheTTL() (time.Duration, error) {
+	value := getEnvOrDefault("A2A_CONFIG_CACHE_TTL", "")
+	if value == "" {
+		return DefaultConfigCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid A2A_CONFIG_CACHE_TTL %q: %w", value, err)
+	}
+	// A zero TTL would reload the config and rebuild the handler on every request
+	if ttl <= 0 {
+		return 0, fmt.Errorf("A2A_CONFIG_CACHE_TTL must be positive, got %s", value)
+	}
+
+	return ttl, nil
+}
@@ -0,0 +1,141 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCachedConfig_Get(t *testing.T) {
+	loads := 0
+	failNext := false
+	source := func(ctx context.Context) (ServerlessConfig, error) {
+		if failNext {
+			return ServerlessConfig{}, fmt.Errorf("store unavailable")
+		}
+		loads++
+		return ServerlessConfig{AgentID: fmt.Sprintf("agent-v%d", loads)}, nil
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCachedConfig(source, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// First call loads
+	config, refreshed, err := cache.Get(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !refreshed || config.AgentID != "agent-v1" {
+		t.Errorf("expected fresh agent-v1, got %s (refreshed=%v)", config.AgentID, refreshed)
+	}
+
+	// Within TTL the cached config is served
+	now = now.Add(30 * time.Second)
+	config, refreshed, _ = cache.Get(ctx)
+	if refreshed || config.AgentID != "agent-v1" || loads != 1 {
+		t.Errorf("expected cached agent-v1 with 1 load, got %s (refreshed=%v, loads=%d)", config.AgentID, refreshed, loads)
+	}
+
+	// After TTL the config is reloaded
+	now = now.Add(time.Minute)
+	config, refreshed, _ = cache.Get(ctx)
+	if !refreshed || config.AgentID != "agent-v2" {
+		t.Errorf("expected refreshed agent-v2, got %s (refreshed=%v)", config.AgentID, refreshed)
+	}
+
+	// A failed refresh keeps serving the last good config
+	failNext = true
+	now = now.Add(2 * time.Minute)
+	config, refreshed, err = cache.Get(ctx)
+	if err != nil {
+		t.Errorf("expected stale config instead of error, got %v", err)
+	}
+	if refreshed || config.AgentID != "agent-v2" {
+		t.Errorf("expected stale agent-v2, got %s (refreshed=%v)", config.AgentID, refreshed)
+	}
+}
+
+func TestCachedConfig_Reload(t *testing.T) {
+	loads := 0
+	var loadErr error
+	source := func(ctx context.Context) (ServerlessConfig, error) {
+		if loadErr != nil {
+			return ServerlessConfig{}, loadErr
+		}
+		loads++
+		return ServerlessConfig{AgentID: fmt.Sprintf("agent-v%d", loads)}, nil
+	}
+	cache := NewCachedConfig(source, time.Hour)
+	ctx := context.Background()
+	cache.Get(ctx)
+
+	// A reload loads within the TTL, and Get serves what it loaded
+	config, err := cache.Reload(ctx)
+	if err != nil || config.AgentID != "agent-v2" {
+		t.Fatalf("expected agent-v2, got %s: %v", config.AgentID, err)
+	}
+	if config, refreshed, _ := cache.Get(ctx); refreshed || config.AgentID != "agent-v2" {
+		t.Errorf("expected cached agent-v2, got %s (refreshed=%v)", config.AgentID, refreshed)
+	}
+
+	// A failed reload is reported and the cached config kept
+	loadErr = fmt.Errorf("store unavailable")
+	if _, err := cache.Reload(ctx); err == nil {
+		t.Error("expected the failed reload reported")
+	}
+	if config, _, _ := cache.Get(ctx); config.AgentID != "agent-v2" {
+		t.Errorf("expected agent-v2 kept, got %s", config.AgentID)
+	}
+}
+
+func TestCachedConfig_InitialLoadError(t *testing.T) {
+	source := func(ctx context.Context) (ServerlessConfig, error) {
+		return ServerlessConfig{}, fmt.Errorf("store unavailable")
+	}
+
+	cache := NewCachedConfig(source, time.Minute)
+	if _, _, err := cache.Get(context.Background()); err == nil {
+		t.Error("expected error when no config has ever loaded")
+	}
+}
+
+func TestLoadConfigCacheTTL(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{"default", "", DefaultConfigCacheTTL, false},
+		{"custom", "30s", 30 * time.Second, false},
+		{"zero would reload every request", "0s", 0, true},
+		{"invalid", "soon", 0, true},
+		{"negative", "-1m", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("A2A_CONFIG_CACHE_TTL", tt.value)
+			defer os.Unsetenv("A2A_CONFIG_CACHE_TTL")
+
+			ttl, err := LoadConfigCacheTTL()
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if ttl != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, ttl)
+			}
+		})
+	}
+}
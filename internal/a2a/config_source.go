@@ -0,0 +1,170 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// SSMParameterAPI is the subset of the SSM client used to load config
+type SSMParameterAPI interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// S3ObjectAPI is the subset of the S3 client used to load config
+type S3ObjectAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// ConfigSourceClients holds the clients the remote config sources call.
+// Only the client of the selected source needs to be set.
+type ConfigSourceClients struct {
+	SecretsManager SecretsManagerAPI
+	SSM            SSMParameterAPI
+	S3             S3ObjectAPI
+}
+
+// Environment variables selecting where the whole config document is loaded from
+var configSourceEnvVars = []string{
+	"A2A_CONFIG_SECRET_ID",
+	"A2A_CONFIG_SSM_PARAMETER",
+	"A2A_CONFIG_S3_URI",
+	"A2A_CONFIG_FILE",
+}
+
+// LoadConfigSourceFromEnv returns the config source selected by the A2A_CONFIG_*
+// variables, or nil when config comes from individual environment variables.
+// Sources only decode the document; callers validate it.
+func LoadConfigSourceFromEnv(clients ConfigSourceClients) (ConfigSource, error) {
+	var selected []string
+	for _, name := range configSourceEnvVars {
+		if os.Getenv(name) != "" {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) > 1 {
+		return nil, fmt.Errorf("only one config source may be set, got %s", strings.Join(selected, ", "))
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	value := os.Getenv(selected[0])
+	switch selected[0] {
+	case "A2A_CONFIG_SECRET_ID":
+		return NewSecretsManagerConfigSource(clients.SecretsManager, value), nil
+	case "A2A_CONFIG_SSM_PARAMETER":
+		return NewSSMParameterConfigSource(clients.SSM, value), nil
+	case "A2A_CONFIG_FILE":
+		return NewFileConfigSource(value), nil
+	default:
+		bucket, key, err := parseS3URI("A2A_CONFIG_S3_URI", value)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3ConfigSource(clients.S3, bucket, key), nil
+	}
+}
+
+// NewSecretsManagerConfigSource loads a JSON-encoded ServerlessConfig stored in a secret
+func NewSecretsManagerConfigSource(client SecretsManagerAPI, secretID string) ConfigSource {
+	return func(ctx context.Context) (ServerlessConfig, error) {
+		output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretID),
+		})
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("failed to get config secret %s: %w", secretID, err)
+		}
+		if output.SecretString == nil {
+			return ServerlessConfig{}, fmt.Errorf("config secret %s has no string value", secretID)
+		}
+
+		config, err := DecodeServerlessConfig([]byte(*output.SecretString))
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("config secret %s: %w", secretID, err)
+		}
+		return config, nil
+	}
+}
+
+// NewSSMParameterConfigSource loads a JSON-encoded ServerlessConfig stored in
+// a Parameter Store parameter. SecureString parameters are decrypted.
+func NewSSMParameterConfigSource(client SSMParameterAPI, name string) ConfigSource {
+	return func(ctx context.Context) (ServerlessConfig, error) {
+		output, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(name),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("failed to get config parameter %s: %w", name, err)
+		}
+		if output.Parameter == nil || output.Parameter.Value == nil {
+			return ServerlessConfig{}, fmt.Errorf("config parameter %s has no value", name)
+		}
+
+		config, err := DecodeServerlessConfig([]byte(*output.Parameter.Value))
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("config parameter %s: %w", name, err)
+		}
+		return config, nil
+	}
+}
+
+// NewS3ConfigSource loads a JSON-encoded ServerlessConfig stored as an S3 object
+func NewS3ConfigSource(client S3ObjectAPI, bucket, key string) ConfigSource {
+	return func(ctx context.Context) (ServerlessConfig, error) {
+		output, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("failed to get config object s3://%s/%s: %w", bucket, key, err)
+		}
+		defer output.Body.Close()
+
+		data, err := io.ReadAll(output.Body)
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("failed to read config object s3://%s/%s: %w", bucket, key, err)
+		}
+
+		config, err := DecodeServerlessConfig(data)
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("config object s3://%s/%s: %w", bucket, key, err)
+		}
+		return config, nil
+	}
+}
+
+// NewFileConfigSource loads a JSON-encoded ServerlessConfig from a file, such
+// as one bundled into the deployment package next to the bootstrap binary
+func NewFileConfigSource(path string) ConfigSource {
+	return func(ctx context.Context) (ServerlessConfig, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		config, err := DecodeServerlessConfig(data)
+		if err != nil {
+			return ServerlessConfig{}, fmt.Errorf("config file %s: %w", path, err)
+		}
+		return config, nil
+	}
+}
+
+// parseS3URI splits an s3://bucket/key URI read from the variable name
+func parseS3URI(name, value string) (string, string, error) {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "s3" || parsed.Host == "" || strings.TrimPrefix(parsed.Path, "/") == "" {
+		return "", "", fmt.Errorf("%s must look like s3://bucket/key, got %q", name, value)
+	}
+	return parsed.Host, strings.TrimPrefix(parsed.Path, "/"), nil
+}
@@ -0,0 +1,174 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+const testConfigDocument = `{
+	"agent_id": "remote-agent",
+	"agent_card": {"Name": "Remote Agent", "URL": "https://remote.example.com"},
+	"cloud_config": {"provider": "local"}
+}`
+
+// fakeSSM serves parameters from a map
+type fakeSSM struct {
+	parameters map[string]string
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	if !aws.ToBool(params.WithDecryption) {
+		return nil, fmt.Errorf("expected WithDecryption for SecureString parameters")
+	}
+	value, ok := f.parameters[aws.ToString(params.Name)]
+	if !ok {
+		return nil, fmt.Errorf("ParameterNotFound")
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String(value)}}, nil
+}
+
+// fakeS3 serves objects from a map keyed by bucket/key
+type fakeS3 struct {
+	objects map[string]string
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	body, ok := f.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(body))}, nil
+}
+
+func TestConfigSources(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	garbageFile := filepath.Join(dir, "garbage.json")
+	os.WriteFile(configFile, []byte(testConfigDocument), 0o600)
+	os.WriteFile(garbageFile, []byte("not json"), 0o600)
+
+	secrets := &fakeSecretsManager{secrets: map[string]string{"valid": testConfigDocument, "garbage": "not json"}}
+	parameters := &fakeSSM{parameters: map[string]string{"/a2a/config": testConfigDocument, "/a2a/garbage": "not json"}}
+	objects := &fakeS3{objects: map[string]string{"configs/agent.json": testConfigDocument, "configs/garbage.json": "not json"}}
+
+	tests := []struct {
+		name        string
+		source      ConfigSource
+		expectError bool
+	}{
+		{"secret", NewSecretsManagerConfigSource(secrets, "valid"), false},
+		{"secret not json", NewSecretsManagerConfigSource(secrets, "garbage"), true},
+		{"secret missing", NewSecretsManagerConfigSource(secrets, "missing"), true},
+		{"parameter", NewSSMParameterConfigSource(parameters, "/a2a/config"), false},
+		{"parameter not json", NewSSMParameterConfigSource(parameters, "/a2a/garbage"), true},
+		{"parameter missing", NewSSMParameterConfigSource(parameters, "/a2a/missing"), true},
+		{"object", NewS3ConfigSource(objects, "configs", "agent.json"), false},
+		{"object not json", NewS3ConfigSource(objects, "configs", "garbage.json"), true},
+		{"object missing", NewS3ConfigSource(objects, "configs", "missing.json"), true},
+		{"file", NewFileConfigSource(configFile), false},
+		{"file not json", NewFileConfigSource(garbageFile), true},
+		{"file missing", NewFileConfigSource(filepath.Join(dir, "missing.json")), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.source(context.Background())
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.AgentID != "remote-agent" || config.AgentCard.Name != "Remote Agent" {
+				t.Errorf("unexpected config: %+v", config)
+			}
+		})
+	}
+}
+
+func TestConfigSourcesDoNotValidate(t *testing.T) {
+	// Callers validate, so tools can print an invalid config before listing its problems
+	secrets := &fakeSecretsManager{secrets: map[string]string{"invalid": `{"agent_id": ""}`}}
+	if _, err := NewSecretsManagerConfigSource(secrets, "invalid")(context.Background()); err != nil {
+		t.Errorf("expected invalid config to decode, got %v", err)
+	}
+}
+
+func TestLoadConfigSourceFromEnv(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(configFile, []byte(testConfigDocument), 0o600)
+
+	clients := ConfigSourceClients{
+		SecretsManager: &fakeSecretsManager{secrets: map[string]string{"a2a-config": testConfigDocument}},
+		SSM:            &fakeSSM{parameters: map[string]string{"/a2a/config": testConfigDocument}},
+		S3:             &fakeS3{objects: map[string]string{"configs/agent.json": testConfigDocument}},
+	}
+
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		expectNil   bool
+		expectError bool
+	}{
+		{name: "no source means env config", expectNil: true},
+		{name: "secret", envVars: map[string]string{"A2A_CONFIG_SECRET_ID": "a2a-config"}},
+		{name: "parameter", envVars: map[string]string{"A2A_CONFIG_SSM_PARAMETER": "/a2a/config"}},
+		{name: "object", envVars: map[string]string{"A2A_CONFIG_S3_URI": "s3://configs/agent.json"}},
+		{name: "file", envVars: map[string]string{"A2A_CONFIG_FILE": configFile}},
+		{name: "bad s3 uri", envVars: map[string]string{"A2A_CONFIG_S3_URI": "configs/agent.json"}, expectError: true},
+		{
+			name:        "two sources",
+			envVars:     map[string]string{"A2A_CONFIG_SECRET_ID": "a2a-config", "A2A_CONFIG_SSM_PARAMETER": "/a2a/config"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			source, err := LoadConfigSourceFromEnv(clients)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectNil {
+				if source != nil {
+					t.Error("expected no source")
+				}
+				return
+			}
+
+			config, err := source(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.AgentID != "remote-agent" {
+				t.Errorf("expected remote-agent, got %s", config.AgentID)
+			}
+		})
+	}
+}
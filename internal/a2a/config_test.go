@@ -72,7 +72,7 @@ func TestConfigLoader_LoadServerlessConfig(t *testing.T) {
 			errorMsg:    "A2A_AGENT_URL environment variable is required",
 		},
 		{
-			name: "AWS missing SQS queue URL",
+			name: "AWS without SQS queue URL",
 			envVars: map[string]string{
 				"A2A_AGENT_ID":       "test-agent-aws",
 				"A2A_AGENT_NAME":     "AWS Test Agent",
@@ -81,8 +81,7 @@ func TestConfigLoader_LoadServerlessConfig(t *testing.T) {
 				"AWS_REGION":         "us-west-2",
 				"AWS_DYNAMODB_TABLE": "test-table",
 			},
-			expectError: true,
-			errorMsg:    "sqs_queue_url is required",
+			expectError: false,
 		},
 		{
 			name: "AWS missing DynamoDB table",
@@ -177,20 +176,76 @@ func TestConfigLoader_LoadCloudProviderConfig(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name: "unsupported provider",
+			name: "GCP provider",
+			envVars: map[string]string{
+				"CLOUD_PROVIDER":   "gcp",
+				"GCP_PROJECT_ID":   "test-project",
+				"GCP_FIRESTORE_DB": "test-db",
+				"GCP_PUBSUB_TOPIC": "test-topic",
+				"GCP_REGION":       "europe-west1",
+			},
+			expectError: false,
+		},
+		{
+			name: "GCP provider without region",
 			envVars: map[string]string{
-				"CLOUD_PROVIDER": "azure",
+				"CLOUD_PROVIDER":   "gcp",
+				"GCP_PROJECT_ID":   "test-project",
+				"GCP_FIRESTORE_DB": "test-db",
+				"GCP_PUBSUB_TOPIC": "test-topic",
 			},
 			expectError: true,
-			errorMsg:    "unsupported cloud provider: azure",
+			errorMsg:    "cloud_config.gcp.region is required",
 		},
 		{
-			name: "GCP provider (not implemented)",
+			name: "GCP provider missing project",
 			envVars: map[string]string{
 				"CLOUD_PROVIDER": "gcp",
+				"GCP_REGION":     "europe-west1",
+			},
+			expectError: true,
+			errorMsg:    "cloud_config.gcp.project_id is required",
+		},
+		{
+			name: "Azure provider without region",
+			envVars: map[string]string{
+				"CLOUD_PROVIDER":             "azure",
+				"AZURE_COSMOSDB_ENDPOINT":    "https://test.documents.azure.com:443/",
+				"AZURE_COSMOSDB_DATABASE":    "a2a",
+				"AZURE_SERVICEBUS_NAMESPACE": "test-ns",
+				"AZURE_SERVICEBUS_QUEUE":     "a2a-events",
+			},
+			expectError: true,
+			errorMsg:    "cloud_config.azure.region is required",
+		},
+		{
+			name: "AWS provider missing table",
+			envVars: map[string]string{
+				"CLOUD_PROVIDER": "aws",
+				"AWS_REGION":     "us-east-1",
+			},
+			expectError: true,
+			errorMsg:    "cloud_config.aws.dynamodb_table is required",
+		},
+		{
+			name: "Azure provider",
+			envVars: map[string]string{
+				"CLOUD_PROVIDER":             "azure",
+				"AZURE_REGION":               "westeurope",
+				"AZURE_COSMOSDB_ENDPOINT":    "https://test.documents.azure.com:443/",
+				"AZURE_COSMOSDB_DATABASE":    "a2a",
+				"AZURE_SERVICEBUS_NAMESPACE": "test-ns",
+				"AZURE_SERVICEBUS_QUEUE":     "a2a-events",
+			},
+			expectError: false,
+		},
+		{
+			name: "unsupported provider",
+			envVars: map[string]string{
+				"CLOUD_PROVIDER": "digitalocean",
 			},
 			expectError: true,
-			errorMsg:    "GCP provider not yet implemented",
+			errorMsg:    "unsupported cloud provider: digitalocean",
 		},
 	}
 
@@ -281,12 +336,58 @@ func TestConfigLoader_CreateCloudProvider(t *testing.T) {
 			errorMsg:    "AWS provider validation failed",
 		},
 		{
-			name: "unsupported provider",
+			name: "GCP provider",
+			config: CloudProviderConfig{
+				Provider: "gcp",
+				GCP: &GCPConfig{
+					ProjectID:   "test-project",
+					FirestoreDB: "test-db",
+					PubSubTopic: "test-topic",
+					Region:      "us-central1",
+				},
+			},
+			expectError: false,
+			expectType:  CloudProviderGCP,
+		},
+		{
+			name: "GCP provider missing config",
+			config: CloudProviderConfig{
+				Provider: "gcp",
+			},
+			expectError: true,
+			errorMsg:    "GCP configuration is required for GCP provider",
+		},
+		{
+			name: "Azure provider",
 			config: CloudProviderConfig{
 				Provider: "azure",
+				Azure: &AzureConfig{
+					Region:              "eastus",
+					CosmosDBEndpoint:    "https://test.documents.azure.com:443/",
+					CosmosDBDatabase:    "a2a",
+					ServiceBusNamespace: "test-ns",
+					ServiceBusQueue:     "a2a-events",
+				},
+			},
+			expectError: false,
+			expectType:  CloudProviderAzure,
+		},
+		{
+			name: "Azure provider invalid config",
+			config: CloudProviderConfig{
+				Provider: "azure",
+				Azure:    &AzureConfig{Region: "eastus"},
+			},
+			expectError: true,
+			errorMsg:    "Azure provider validation failed",
+		},
+		{
+			name: "unsupported provider",
+			config: CloudProviderConfig{
+				Provider: "digitalocean",
 			},
 			expectError: true,
-			errorMsg:    "unsupported cloud provider: azure",
+			errorMsg:    "unsupported cloud provider: digitalocean",
 		},
 	}
 
@@ -344,13 +445,12 @@ func TestAWSProvider(t *testing.T) {
 			errorMsg:    "region is required",
 		},
 		{
-			name: "missing SQS queue URL",
+			name: "SQS queue URL is optional",
 			config: AWSConfig{
 				Region:        "us-east-1",
 				DynamoDBTable: "test-table",
 			},
-			expectError: true,
-			errorMsg:    "sqs_queue_url is required",
+			expectError: false,
 		},
 		{
 			name: "missing DynamoDB table",
@@ -390,13 +490,13 @@ func TestAWSProvider(t *testing.T) {
 			}
 
 			storageConfig := provider.GetStorageConfig()
-			if storageConfig == nil {
-				t.Errorf("expected storage config, got nil")
+			if storageConfig.DynamoDBTable != tt.config.DynamoDBTable || storageConfig.Region != tt.config.Region {
+				t.Errorf("expected storage config for table %s, got %+v", tt.config.DynamoDBTable, storageConfig)
 			}
 
 			eventConfig := provider.GetEventConfig()
-			if eventConfig == nil {
-				t.Errorf("expected event config, got nil")
+			if eventConfig.SQSQueueURL != tt.config.SQSQueueURL {
+				t.Errorf("expected event config for queue %s, got %+v", tt.config.SQSQueueURL, eventConfig)
 			}
 		})
 	}
@@ -411,53 +511,53 @@ func TestGCPProvider(t *testing.T) {
 	}{
 		{
 			name: "valid GCP config",
-			provider: GCPProvider{
+			provider: GCPProvider{Config: GCPConfig{
 				ProjectID:     "test-project",
 				FirestoreDB:   "test-db",
 				PubSubTopic:   "test-topic",
 				Region:        "us-central1",
-			},
+			}},
 			expectError: false,
 		},
 		{
 			name: "missing project ID",
-			provider: GCPProvider{
+			provider: GCPProvider{Config: GCPConfig{
 				FirestoreDB: "test-db",
 				PubSubTopic: "test-topic",
 				Region:      "us-central1",
-			},
+			}},
 			expectError: true,
-			errorMsg:    "gcp project_id is required",
+			errorMsg:    "project_id is required",
 		},
 		{
 			name: "missing firestore DB",
-			provider: GCPProvider{
+			provider: GCPProvider{Config: GCPConfig{
 				ProjectID:   "test-project",
 				PubSubTopic: "test-topic",
 				Region:      "us-central1",
-			},
+			}},
 			expectError: true,
-			errorMsg:    "gcp firestore_db is required",
+			errorMsg:    "firestore_db is required",
 		},
 		{
 			name: "missing pubsub topic",
-			provider: GCPProvider{
+			provider: GCPProvider{Config: GCPConfig{
 				ProjectID:   "test-project",
 				FirestoreDB: "test-db",
 				Region:      "us-central1",
-			},
+			}},
 			expectError: true,
-			errorMsg:    "gcp pubsub_topic is required",
+			errorMsg:    "pubsub_topic is required",
 		},
 		{
 			name: "missing region",
-			provider: GCPProvider{
+			provider: GCPProvider{Config: GCPConfig{
 				ProjectID:   "test-project",
 				FirestoreDB: "test-db",
 				PubSubTopic: "test-topic",
-			},
+			}},
 			expectError: true,
-			errorMsg:    "gcp region is required",
+			errorMsg:    "region is required",
 		},
 	}
 
@@ -487,13 +587,13 @@ func TestGCPProvider(t *testing.T) {
 			}
 
 			storageConfig := tt.provider.GetStorageConfig()
-			if storageConfig == nil {
-				t.Errorf("expected storage config, got nil")
+			if storageConfig.ProjectID != tt.provider.Config.ProjectID || storageConfig.FirestoreDB != tt.provider.Config.FirestoreDB {
+				t.Errorf("expected storage config for project %s, got %+v", tt.provider.Config.ProjectID, storageConfig)
 			}
 
 			eventConfig := tt.provider.GetEventConfig()
-			if eventConfig == nil {
-				t.Errorf("expected event config, got nil")
+			if eventConfig.PubSubTopic != tt.provider.Config.PubSubTopic {
+				t.Errorf("expected event config for topic %s, got %+v", tt.provider.Config.PubSubTopic, eventConfig)
 			}
 		})
 	}
@@ -523,13 +623,13 @@ func TestLocalProvider(t *testing.T) {
 	}
 
 	storageConfig := provider.GetStorageConfig()
-	if storageConfig == nil {
-		t.Errorf("expected storage config, got nil")
+	if storageConfig.Provider != CloudProviderLocal || storageConfig.Path != "./local_storage" {
+		t.Errorf("expected local storage config, got %+v", storageConfig)
 	}
 
 	eventConfig := provider.GetEventConfig()
-	if eventConfig == nil {
-		t.Errorf("expected event config, got nil")
+	if eventConfig.Provider != CloudProviderLocal || eventConfig.Path != "./local_events" {
+		t.Errorf("expected local event config, got %+v", eventConfig)
 	}
 }
 
@@ -740,8 +840,32 @@ func clearTestEnv() {
 		"A2A_AGENT_STREAMING", "A2A_LOG_LEVEL",
 		"CLOUD_PROVIDER", "AWS_REGION", "AWS_SQS_QUEUE_URL", "AWS_DYNAMODB_TABLE",
 		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY",
-		"GCP_PROJECT_ID", "GCP_FIRESTORE_DB", "GCP_PUBSUB_TOPIC",
+		"GCP_PROJECT_ID", "GCP_FIRESTORE_DB", "GCP_PUBSUB_TOPIC", "GCP_REGION", "GOOGLE_APPLICATION_CREDENTIALS",
+		"AZURE_REGION", "AZURE_COSMOSDB_ENDPOINT", "AZURE_COSMOSDB_DATABASE", "AZURE_SERVICEBUS_NAMESPACE",
+		"AZURE_SERVICEBUS_QUEUE", "AZURE_TENANT_ID", "AZURE_CLIENT_ID", "AZURE_CLIENT_SECRET",
 		"LOCAL_STORAGE_PATH", "LOCAL_EVENT_PATH",
+		"A2A_API_KEY", "A2A_WEBHOOK_SIGNING_KEY", "A2A_GRPC_URL", "A2A_REST_URL",
+		"A2A_AGENT_SKILLS", "A2A_AGENT_SKILLS_FILE",
+		"A2A_AUTH_API_KEY_HEADER", "A2A_AUTH_OAUTH2_TOKEN_URL", "A2A_AUTH_OAUTH2_AUTHORIZATION_URL",
+		"A2A_AUTH_OAUTH2_SCOPES", "A2A_AUTH_OIDC_METADATA_URL",
+		"A2A_AGENT_REGISTRY_FILE", "A2A_TRACING", "A2A_TRACING_SAMPLE_RATIO", "OTEL_SERVICE_NAME",
+		"A2A_METRICS", "A2A_METRICS_NAMESPACE",
+		"A2A_CONFIG_SECRET_ID", "A2A_CONFIG_SSM_PARAMETER", "A2A_CONFIG_S3_URI", "A2A_CONFIG_FILE",
+		"A2A_AGENT_CARD_SSM_PARAMETER", "A2A_AGENT_CARD_S3_URI", "A2A_AGENT_CARD_POLL_INTERVAL",
+		"A2A_AGENT_CARD_LOCALES", "A2A_AGENT_CARD_DEFAULT_LOCALE",
+		"A2A_AUTH_OAUTH2_JWKS_URL", "A2A_AUTH_OAUTH2_ISSUER", "A2A_AUTH_AUDIENCE", "A2A_AUTH_ADMIN_SUBJECTS",
+		"DYNAMODB_AUDIT_TABLE", "AUDIT_FIREHOSE_STREAM", "DYNAMODB_COMPRESS_ABOVE",
+		"A2A_AUTH_IAM", "A2A_AUTH_METHOD_POLICIES", "A2A_AUTH_OWN_TASKS_ONLY",
+		"A2A_AUTH_REPLAY_TABLE", "A2A_AUTH_SINGLE_USE_TOKENS", "A2A_STRICT_JSONRPC",
+		"A2A_AUTH_SIGNATURE_HEADER", "A2A_AUTH_SIGNATURE_TOLERANCE", "A2A_REQUEST_SIGNING_KEY", "A2A_CONTENT_ENCRYPTION_KEY",
+		"A2A_LOG_REDACT_KEYS", "A2A_LOG_DEBUG_SAMPLE_RATIO", "A2A_RECORD_EVENTS",
+		"A2A_WEBHOOK_ALLOWED_HOSTS", "A2A_WEBHOOK_DENIED_HOSTS", "A2A_WEBHOOK_MAX_REDIRECTS", "A2A_WEBHOOK_PROXY_URL",
+		"A2A_WORKER_CONCURRENCY", "A2A_WORKER_IDEMPOTENCY_TABLE",
+		"A2A_WORK_QUEUE_URL", "A2A_RETRY_QUEUE_URL", "A2A_WORK_CONCURRENCY",
+		"A2A_WORK_QUEUE_HIGH_URL", "A2A_WORK_QUEUE_LOW_URL", "A2A_WORK_CONCURRENCY_HIGH", "A2A_WORK_CONCURRENCY_LOW",
+		"A2A_CONTEXT_LOCK_TABLE", "A2A_CONTEXT_LOCK_WAIT", "A2A_DEDUP_TABLE", "A2A_DEDUP_WINDOW",
+		"A2A_SCHEDULE_TABLE", "DYNAMODB_PUSH_CONFIG_TABLE",
+		"A2A_CAPTURE_TABLE", "A2A_CAPTURE_S3_URI", "A2A_CAPTURE_TTL",
 	}
 	
 	for _, env := range envVars {
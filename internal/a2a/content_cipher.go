@@ -0,0 +1,457 @@
+package a2a
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// encryptedPrefix marks a value sealed by ContentCipher and bound to the
+// task it belongs to. Values without a prefix are read back unchanged, so
+// rows written before encryption was turned on stay readable.
+const encryptedPrefix = "a2aenc:v1:"
+
+// ContentCipherKeySize is the length of the AES-256 key in
+// secrets.content_encryption_key, before base64 encoding
+const ContentCipherKeySize = 32
+
+// ContentCipher seals message content with AES-256-GCM before it is stored,
+// for deployments that may not keep plaintext prompts in shared tables even
+// when the tables are encrypted at rest. It covers the text of text parts and
+// the bytes and URI of file parts; data parts, IDs and metadata stay readable
+// so tasks can still be queried.
+type ContentCipher struct {
+	aead cipher.AEAD
+}
+
+// NewContentCipher creates a cipher from a base64-encoded 32-byte key
+func NewContentCipher(key string) (*ContentCipher, error) {
+	raw, err := decodeContentKey(key)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &ContentCipher{aead: aead}, nil
+}
+
+// decodeContentKey decodes a base64 content key and checks its length
+func decodeContentKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("content encryption key must be base64")
+	}
+	if len(raw) != ContentCipherKeySize {
+		return nil, fmt.Errorf("content encryption key must be %d bytes, got %d", ContentCipherKeySize, len(raw))
+	}
+	return raw, nil
+}
+
+// sealer returns a transform that encrypts values bound to scope, the task
+// or capture they belong to, so a sealed value copied into another one does
+// not open. Every value but "" is sealed, including user content that
+// happens to start with a prefix.
+func (c *ContentCipher) sealer(scope string) func(string) (string, error) {
+	return func(value string) (string, error) {
+		if value == "" {
+			return value, nil
+		}
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		sealed := c.aead.Seal(nonce, nonce, []byte(value), []byte(scope))
+		return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+	}
+}
+
+// opener returns a transform that decrypts values sealed by sealer(scope),
+// returning values without a prefix unchanged
+func (c *ContentCipher) opener(scope string) func(string) (string, error) {
+	return func(value string) (string, error) {
+		encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+		if !ok {
+			return value, nil
+		}
+		return c.open(encoded, []byte(scope))
+	}
+}
+
+// open decrypts an encoded sealed value bound to scope
+func (c *ContentCipher) open(encoded string, scope []byte) (string, error) {
+	// Sealed file parts can be large, so they are decoded and opened in
+	// place in a pooled buffer, and only the plaintext string is allocated
+	decodedLen := base64.StdEncoding.DecodedLen(len(encoded))
+	buf := GetBuffer()
+	defer PutBuffer(buf)
+	buf.Grow(len(encoded) + decodedLen)
+	buf.WriteString(encoded)
+	src := buf.Bytes()
+	sealed := buf.AvailableBuffer()[:decodedLen]
+	n, err := base64.StdEncoding.Decode(sealed, src)
+	if err != nil || n < c.aead.NonceSize() {
+		return "", errors.New("encrypted content is malformed")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():n]
+	plaintext, err := c.aead.Open(ciphertext[:0], nonce, ciphertext, scope)
+	if err != nil {
+		return "", errors.New("failed to decrypt content, check secrets.content_encryption_key and that it belongs to this task")
+	}
+	return string(plaintext), nil
+}
+
+// parts applies transform to the content of each text and file part,
+// returning a new slice so the caller's parts are not modified
+func (c *ContentCipher) parts(parts []a2a.Part, transform func(string) (string, error)) ([]a2a.Part, error) {
+	if parts == nil {
+		return nil, nil
+	}
+	out := make([]a2a.Part, len(parts))
+	for i, part := range parts {
+		var err error
+		switch p := part.(type) {
+		case a2a.TextPart:
+			p.Text, err = transform(p.Text)
+			part = p
+		case a2a.FilePart:
+			if p.File.Bytes, err = transform(p.File.Bytes); err == nil {
+				p.File.URI, err = transform(p.File.URI)
+			}
+			part = p
+		}
+		if err != nil {
+			return nil, err
+		}
+		out[i] = part
+	}
+	return out, nil
+}
+
+func (c *ContentCipher) message(message a2a.Message, transform func(string) (string, error)) (a2a.Message, error) {
+	parts, err := c.parts(message.Parts, transform)
+	if err != nil {
+		return a2a.Message{}, err
+	}
+	message.Parts = parts
+	return message, nil
+}
+
+func (c *ContentCipher) status(status a2a.TaskStatus, transform func(string) (string, error)) (a2a.TaskStatus, error) {
+	if status.Message == nil {
+		return status, nil
+	}
+	message, err := c.message(*status.Message, transform)
+	if err != nil {
+		return a2a.TaskStatus{}, err
+	}
+	status.Message = &message
+	return status, nil
+}
+
+func (c *ContentCipher) artifact(artifact a2a.Artifact, transform func(string) (string, error)) (a2a.Artifact, error) {
+	parts, err := c.parts(artifact.Parts, transform)
+	if err != nil {
+		return a2a.Artifact{}, err
+	}
+	artifact.Parts = parts
+	return artifact, nil
+}
+
+func (c *ContentCipher) task(task a2a.Task, transform func(string) (string, error)) (a2a.Task, error) {
+	var err error
+	if task.Status, err = c.status(task.Status, transform); err != nil {
+		return a2a.Task{}, err
+	}
+	if task.History != nil {
+		history := make([]a2a.Message, len(task.History))
+		for i, message := range task.History {
+			if history[i], err = c.message(message, transform); err != nil {
+				return a2a.Task{}, err
+			}
+		}
+		task.History = history
+	}
+	if task.Artifacts != nil {
+		artifacts := make([]a2a.Artifact, len(task.Artifacts))
+		for i, artifact := range task.Artifacts {
+			if artifacts[i], err = c.artifact(artifact, transform); err != nil {
+				return a2a.Task{}, err
+			}
+		}
+		task.Artifacts = artifacts
+	}
+	return task, nil
+}
+
+func (c *ContentCipher) event(event a2a.Event, transform func(string) (string, error)) (a2a.Event, error) {
+	switch e := event.(type) {
+	case a2a.Message:
+		return c.message(e, transform)
+	case a2a.Task:
+		return c.task(e, transform)
+	case a2a.TaskStatusUpdateEvent:
+		status, err := c.status(e.Status, transform)
+		e.Status = status
+		return e, err
+	case a2a.TaskArtifactUpdateEvent:
+		artifact, err := c.artifact(e.Artifact, transform)
+		e.Artifact = artifact
+		return e, err
+	}
+	return event, nil
+}
+
+// EncryptTask returns a copy of the task with its message and artifact
+// content sealed and bound to the task's ID
+func (c *ContentCipher) EncryptTask(task a2a.Task) (a2a.Task, error) {
+	return c.task(task, c.sealer(string(task.ID)))
+}
+
+// DecryptTask reverses EncryptTask
+func (c *ContentCipher) DecryptTask(task a2a.Task) (a2a.Task, error) {
+	return c.task(task, c.opener(string(task.ID)))
+}
+
+// EncryptEvent returns a copy of the event with its message or artifact
+// content sealed and bound to the ID of its task
+func (c *ContentCipher) EncryptEvent(event a2a.Event) (a2a.Event, error) {
+	return c.event(event, c.sealer(string(eventTaskID(event))))
+}
+
+// DecryptEvent reverses EncryptEvent
+func (c *ContentCipher) DecryptEvent(event a2a.Event) (a2a.Event, error) {
+	return c.event(event, c.opener(string(eventTaskID(event))))
+}
+
+// encryptedTaskStore seals content on the way into a TaskStore and opens it
+// on the way out
+type encryptedTaskStore struct {
+	TaskStore
+	cipher *ContentCipher
+}
+
+// NewEncryptedTaskStore wraps store so message and artifact content is only
+// ever written encrypted
+func NewEncryptedTaskStore(store TaskStore, cipher *ContentCipher) TaskStore {
+	return &encryptedTaskStore{TaskStore: store, cipher: cipher}
+}
+
+func (s *encryptedTaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	task, err := s.TaskStore.GetTask(ctx, taskID)
+	if err != nil {
+		return a2a.Task{}, err
+	}
+	return s.cipher.DecryptTask(task)
+}
+
+func (s *encryptedTaskStore) SaveTask(ctx context.Context, task a2a.Task) error {
+	encrypted, err := s.cipher.EncryptTask(task)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt task %s: %w", task.ID, err)
+	}
+	return s.TaskStore.SaveTask(ctx, encrypted)
+}
+
+// SaveTaskHeartbeat implements TaskHeartbeatStore, passing the heartbeat,
+// which holds no content, straight to the wrapped store when it can take it
+func (s *encryptedTaskStore) SaveTaskHeartbeat(ctx context.Context, task a2a.Task) error {
+	if store, ok := s.TaskStore.(TaskHeartbeatStore); ok {
+		return store.SaveTaskHeartbeat(ctx, task)
+	}
+	return s.SaveTask(ctx, task)
+}
+
+// Archive implements ArchivedTaskStore when the wrapped store does
+func (s *encryptedTaskStore) Archive() TaskArchive {
+	if store, ok := s.TaskStore.(ArchivedTaskStore); ok {
+		return store.Archive()
+	}
+	return nil
+}
+
+func (s *encryptedTaskStore) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	tasks, err := s.TaskStore.ListTasks(ctx, contextID)
+	if err != nil {
+		return nil, err
+	}
+	for i, task := range tasks {
+		if tasks[i], err = s.cipher.DecryptTask(task); err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.ID, err)
+		}
+	}
+	return tasks, nil
+}
+
+// encryptedEventStore seals content on the way into an EventStore and opens
+// it on the way out
+type encryptedEventStore struct {
+	EventStore
+	cipher *ContentCipher
+}
+
+// NewEncryptedEventStore wraps store so event content is only ever written encrypted
+func NewEncryptedEventStore(store EventStore, cipher *ContentCipher) EventStore {
+	return &encryptedEventStore{EventStore: store, cipher: cipher}
+}
+
+func (s *encryptedEventStore) SaveEvent(ctx context.Context, event a2a.Event) error {
+	encrypted, err := s.cipher.EncryptEvent(event)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt event: %w", err)
+	}
+	return s.EventStore.SaveEvent(ctx, encrypted)
+}
+
+func (s *encryptedEventStore) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	events, err := s.EventStore.GetEvents(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for i, event := range events {
+		if events[i], err = s.cipher.DecryptEvent(event); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// DeleteEvents deletes through to the wrapped store, which holds nothing
+// the cipher needs to open
+func (s *encryptedEventStore) DeleteEvents(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	purger, ok := s.EventStore.(EventPurger)
+	if !ok {
+		return 0, ErrPurgeUnsupported
+	}
+	return purger.DeleteEvents(ctx, taskID)
+}
+
+// encryptedCaptureStore seals whole captured bodies, which carry the
+// message content of the request and response
+type encryptedCaptureStore struct {
+	CaptureStore
+	cipher *ContentCipher
+}
+
+// NewEncryptedCaptureStore wraps store so captured request and response
+// bodies are only ever written encrypted. The method, status and request ID
+// stay readable.
+func NewEncryptedCaptureStore(store CaptureStore, cipher *ContentCipher) CaptureStore {
+	return &encryptedCaptureStore{CaptureStore: store, cipher: cipher}
+}
+
+func (s *encryptedCaptureStore) SaveCapture(ctx context.Context, exchange CapturedExchange) error {
+	var err error
+	seal := s.cipher.sealer(exchange.RequestID)
+	if exchange.Request, err = s.body(exchange.Request, seal); err == nil {
+		exchange.Response, err = s.body(exchange.Response, seal)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encrypt capture %s: %w", exchange.RequestID, err)
+	}
+	return s.CaptureStore.SaveCapture(ctx, exchange)
+}
+
+func (s *encryptedCaptureStore) GetCapture(ctx context.Context, requestID string) (CapturedExchange, error) {
+	exchange, err := s.CaptureStore.GetCapture(ctx, requestID)
+	if err != nil {
+		return CapturedExchange{}, err
+	}
+	open := s.cipher.opener(requestID)
+	if exchange.Request, err = s.body(exchange.Request, open); err == nil {
+		exchange.Response, err = s.body(exchange.Response, open)
+	}
+	if err != nil {
+		return CapturedExchange{}, fmt.Errorf("capture %s: %w", requestID, err)
+	}
+	return exchange, nil
+}
+
+// DeleteCapture deletes through to the wrapped store
+func (s *encryptedCaptureStore) DeleteCapture(ctx context.Context, requestID string) error {
+	purger, ok := s.CaptureStore.(CapturePurger)
+	if !ok {
+		return ErrPurgeUnsupported
+	}
+	return purger.DeleteCapture(ctx, requestID)
+}
+
+// body transforms a captured body. A sealed body is kept as a JSON string;
+// opening one that is not a string leaves it unchanged.
+func (s *encryptedCaptureStore) body(raw json.RawMessage, transform func(string) (string, error)) (json.RawMessage, error) {
+	value := string(raw)
+	var sealed string
+	if json.Unmarshal(raw, &sealed) == nil && strings.HasPrefix(sealed, encryptedPrefix) {
+		value = sealed
+	}
+	transformed, err := transform(value)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(transformed, encryptedPrefix) {
+		return json.Marshal(transformed)
+	}
+	return json.RawMessage(transformed), nil
+}
+
+// encryptedMessageDeduplicator seals the direct replies a deduplicator
+// remembers, which carry message content like any task
+type encryptedMessageDeduplicator struct {
+	MessageDeduplicator
+	cipher *ContentCipher
+}
+
+// NewEncryptedMessageDeduplicator wraps dedup so remembered replies are only
+// ever written encrypted, bound to the key they are remembered under
+func NewEncryptedMessageDeduplicator(dedup MessageDeduplicator, cipher *ContentCipher) MessageDeduplicator {
+	return &encryptedMessageDeduplicator{MessageDeduplicator: dedup, cipher: cipher}
+}
+
+func (d *encryptedMessageDeduplicator) Claim(ctx context.Context, key string, expiresAt time.Time) (SentMessage, error) {
+	sent, err := d.MessageDeduplicator.Claim(ctx, key, expiresAt)
+	if sent.Reply != nil {
+		reply, openErr := d.cipher.message(*sent.Reply, d.cipher.opener(key))
+		if openErr != nil {
+			return SentMessage{}, fmt.Errorf("sent message %s: %w", key, openErr)
+		}
+		sent.Reply = &reply
+	}
+	return sent, err
+}
+
+func (d *encryptedMessageDeduplicator) Complete(ctx context.Context, key string, sent SentMessage, expiresAt time.Time) error {
+	if sent.Reply != nil {
+		reply, err := d.cipher.message(*sent.Reply, d.cipher.sealer(key))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt sent message %s: %w", key, err)
+		}
+		sent.Reply = &reply
+	}
+	return d.MessageDeduplicator.Complete(ctx, key, sent, expiresAt)
+}
+
+// validateContentEncryptionKey checks a configured key decodes to an AES-256
+// key. A Secrets Manager ARN is checked once it has been resolved.
+func validateContentEncryptionKey(key SecretString) error {
+	if key == "" || IsSecretReference(key.Reveal()) {
+		return nil
+	}
+	if _, err := decodeContentKey(key.Reveal()); err != nil {
+		return FieldError{Path: "secrets.content_encryption_key", Code: ValidationCodeInvalid, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package a2a
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+var testContentKey = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+// lastTaskStore keeps the last task saved, as a store would hold it
+type lastTaskStore struct {
+	TaskStore
+	task a2a.Task
+}
+
+func (s *lastTaskStore) SaveTask(ctx context.Context, task a2a.Task) error {
+	s.task = task
+	return nil
+}
+
+func (s *lastTaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	return s.task, nil
+}
+
+// lastCaptureStore keeps the last exchange saved
+type lastCaptureStore struct {
+	exchange CapturedExchange
+}
+
+func (s *lastCaptureStore) SaveCapture(ctx context.Context, exchange CapturedExchange) error {
+	s.exchange = exchange
+	return nil
+}
+
+func (s *lastCaptureStore) GetCapture(ctx context.Context, requestID string) (CapturedExchange, error) {
+	return s.exchange, nil
+}
+
+func TestNewContentCipher(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		expectError string
+	}{
+		{name: "32-byte key", key: testContentKey},
+		{name: "not base64", key: "not a key!", expectError: "must be base64"},
+		{name: "short key", key: base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")), expectError: "must be 32 bytes, got 16"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewContentCipher(tt.key)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestEncryptedTaskStore(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewContentCipher(testContentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := a2a.Message{
+		MessageID: "m-1",
+		Role:      a2a.MessageRoleUser,
+		Parts: []a2a.Part{
+			a2a.TextPart{Kind: "text", Text: "my account number is 1234"},
+			a2a.FilePart{Kind: "file", File: a2a.FilePartFile{Bytes: "cGF5c2xpcA=="}},
+			a2a.DataPart{Kind: "data", Data: map[string]any{"step": 1}},
+		},
+	}
+	task := a2a.Task{
+		ID:        "task-1",
+		ContextID: "ctx-1",
+		History:   []a2a.Message{message},
+		Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &message},
+		Artifacts: []a2a.Artifact{{ArtifactID: "a-1", Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "balance: 10"}}}},
+	}
+
+	inner := &lastTaskStore{}
+	store := NewEncryptedTaskStore(inner, cipher)
+	if err := store.SaveTask(ctx, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, _ := json.Marshal(inner.task)
+	for _, plaintext := range []string{"1234", "cGF5c2xpcA==", "balance"} {
+		if strings.Contains(string(stored), plaintext) {
+			t.Errorf("expected %q to be encrypted, got %s", plaintext, stored)
+		}
+	}
+	if inner.task.ID != "task-1" || !strings.Contains(string(stored), `"step":1`) {
+		t.Errorf("expected IDs and data parts to stay readable, got %s", stored)
+	}
+	if task.History[0].Parts[0].(a2a.TextPart).Text != "my account number is 1234" {
+		t.Error("expected the caller's task to be left unchanged")
+	}
+
+	got, err := store.GetTask(ctx, "task-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := got.History[0].Parts[0].(a2a.TextPart).Text; text != "my account number is 1234" {
+		t.Errorf("expected the history to decrypt, got %q", text)
+	}
+	if bytes := got.Status.Message.Parts[1].(a2a.FilePart).File.Bytes; bytes != "cGF5c2xpcA==" {
+		t.Errorf("expected the file bytes to decrypt, got %q", bytes)
+	}
+	if text := got.Artifacts[0].Parts[0].(a2a.TextPart).Text; text != "balance: 10" {
+		t.Errorf("expected the artifact to decrypt, got %q", text)
+	}
+
+	other, _ := NewContentCipher(base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+	if _, err := NewEncryptedTaskStore(inner, other).GetTask(ctx, "task-1"); err == nil {
+		t.Error("expected a different key to fail")
+	}
+	inner.task = a2a.Task{ID: "task-2", History: []a2a.Message{{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "written before"}}}}}
+	if got, err := store.GetTask(ctx, "task-2"); err != nil || got.History[0].Parts[0].(a2a.TextPart).Text != "written before" {
+		t.Errorf("expected plaintext rows to read unchanged, got %+v, %v", got, err)
+	}
+}
+
+func TestContentCipherBinding(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewContentCipher(testContentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := &lastTaskStore{}
+	store := NewEncryptedTaskStore(inner, cipher)
+
+	// User content that looks sealed is sealed like any other content
+	text := encryptedPrefix + "not actually sealed"
+	task := a2a.Task{ID: "task-1", History: []a2a.Message{{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: text}}}}}
+	if err := store.SaveTask(ctx, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored, _ := json.Marshal(inner.task); strings.Contains(string(stored), "not actually sealed") {
+		t.Errorf("expected prefixed content to be encrypted, got %s", stored)
+	}
+	got, err := store.GetTask(ctx, "task-1")
+	if err != nil || got.History[0].Parts[0].(a2a.TextPart).Text != text {
+		t.Errorf("expected prefixed content to read back, got %+v, %v", got, err)
+	}
+
+	// Content copied into another task does not open
+	inner.task.ID = "task-2"
+	if _, err := store.GetTask(ctx, "task-2"); err == nil {
+		t.Error("expected content sealed for another task to fail")
+	}
+
+	// Content sealed without a task does not open in one either
+	sealed, err := cipher.sealer("")("sealed without a task")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner.task = a2a.Task{ID: "task-3", History: []a2a.Message{{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: sealed}}}}}
+	if _, err := store.GetTask(ctx, "task-3"); err == nil {
+		t.Error("expected content sealed without a task to fail")
+	}
+}
+
+func TestContentCipherEvents(t *testing.T) {
+	cipher, err := NewContentCipher(testContentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []a2a.Event{
+		a2a.Message{MessageID: "m-1", Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "secret"}}},
+		a2a.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2a.TaskStatus{Message: &a2a.Message{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "secret"}}}}},
+		a2a.TaskArtifactUpdateEvent{TaskID: "task-1", Artifact: a2a.Artifact{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "secret"}}}},
+	}
+	for _, event := range events {
+		encrypted, err := cipher.EncryptEvent(event)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data, _ := json.Marshal(encrypted); strings.Contains(string(data), "secret") {
+			t.Errorf("expected %T content to be encrypted, got %s", event, data)
+		}
+		decrypted, err := cipher.DecryptEvent(encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if data, _ := json.Marshal(decrypted); !strings.Contains(string(data), "secret") {
+			t.Errorf("expected %T content to decrypt, got %s", event, data)
+		}
+	}
+}
+
+func TestEncryptedCaptureStore(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := NewContentCipher(testContentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchange := NewCapturedExchange("req-1", fixedClock{}.Now(), `{"method":"message/send","params":{"Message":{"Parts":[{"Text":"secret"}]}}}`, 200, `{"result":{}}`)
+
+	inner := &lastCaptureStore{}
+	store := NewEncryptedCaptureStore(inner, cipher)
+	if err := store.SaveCapture(ctx, exchange); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(inner.exchange.Request), "secret") || inner.exchange.Method != "message/send" {
+		t.Errorf("expected only the bodies to be encrypted, got %+v", inner.exchange)
+	}
+
+	got, err := store.GetCapture(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got.Request) != string(exchange.Request) || string(got.Response) != string(exchange.Response) {
+		t.Errorf("expected the bodies to decrypt, got %s and %s", got.Request, got.Response)
+	}
+}
+
+func TestValidateContentEncryptionKey(t *testing.T) {
+	if err := validateContentEncryptionKey(SecretString(testContentKey)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateContentEncryptionKey("arn:aws:secretsmanager:us-east-1:123456789012:secret:content-key"); err != nil {
+		t.Errorf("expected a secret reference to be checked after resolution, got %v", err)
+	}
+	if err := validateContentEncryptionKey("short"); err == nil || !strings.Contains(err.Error(), "secrets.content_encryption_key") {
+		t.Errorf("expected a field error, got %v", err)
+	}
+}
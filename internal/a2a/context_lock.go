@@ -0,0 +1,225 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrContextBusy is returned when another execution held a context's lock
+// for longer than the handler would wait
+var ErrContextBusy = errors.New("context is busy with another task")
+
+// contextLockLease is how long a context lock lasts, as long as a Lambda
+// invocation can run, so an instance that dies holding it blocks the
+// context no longer than that
+const contextLockLease = 15 * time.Minute
+
+// contextLockPoll is how often a held context lock is tried again
+const contextLockPoll = 250 * time.Millisecond
+
+// DefaultContextLockWait is how long a message waits for its context's
+// lock by default
+const DefaultContextLockWait = 10 * time.Second
+
+// ContextLockConfig names the DynamoDB table context locks are kept in.
+// Locking is off unless Table is set.
+type ContextLockConfig struct {
+	Table string
+	Wait  time.Duration
+}
+
+// LoadContextLockConfigFromEnv reads A2A_CONTEXT_LOCK_TABLE and
+// A2A_CONTEXT_LOCK_WAIT (default 10s)
+func LoadContextLockConfigFromEnv() (ContextLockConfig, error) {
+	config := ContextLockConfig{
+		Table: getEnvOrDefault("A2A_CONTEXT_LOCK_TABLE", ""),
+		Wait:  DefaultContextLockWait,
+	}
+	if value := getEnvOrDefault("A2A_CONTEXT_LOCK_WAIT", ""); value != "" {
+		wait, err := time.ParseDuration(value)
+		if err != nil || wait < 0 {
+			return ContextLockConfig{}, fmt.Errorf("A2A_CONTEXT_LOCK_WAIT must be a duration of 0 or more, got %q", value)
+		}
+		config.Wait = wait
+	}
+	return config, nil
+}
+
+// RuntimeOptions returns the option locking contexts in the table through
+// client, none when no table is set
+func (c ContextLockConfig) RuntimeOptions(client DynamoDBAPI) []RuntimeOption {
+	if c.Table == "" {
+		return nil
+	}
+	return []RuntimeOption{WithContextLock(NewAWSContextLock(client, c.Table), c.Wait)}
+}
+
+// ContextLock lets one execution at a time work within a context, so the
+// messages of a conversation do not interleave
+type ContextLock interface {
+	// Acquire takes contextID for owner until expiresAt, or returns
+	// ErrContextBusy while another owner holds it unexpired
+	Acquire(ctx context.Context, contextID, owner string, expiresAt time.Time) error
+	// Release drops owner's lock on contextID. A lock another owner took
+	// after this one expired is left.
+	Release(ctx context.Context, contextID, owner string) error
+}
+
+// WithContextLock has the handler run the executor on a message only while
+// holding lock on its task's context, waiting up to wait for another
+// execution to finish before failing with ErrContextBusy. A new task takes
+// the lock when it joins a context the client names; one in a context of
+// its own has nothing to wait for.
+func WithContextLock(lock ContextLock, wait time.Duration) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.contextLock = lock
+		d.contextLockWait = wait
+	}
+}
+
+// lockContext takes the context lock, polling until it is free or the wait
+// is over, and returns the function releasing it
+func (h *ServerlessA2AHandler) lockContext(ctx context.Context, contextID string) (unlock func(), err error) {
+	owner := h.ids.NewID()
+	// Waited by the wall clock: polling needs time to pass
+	giveUp := time.Now().Add(h.contextLockWait)
+	for {
+		err := h.contextLock.Acquire(ctx, contextID, owner, h.clock.Now().Add(contextLockLease))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrContextBusy) || !time.Now().Before(giveUp) {
+			return nil, fmt.Errorf("failed to lock context %s: %w", contextID, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(contextLockPoll):
+		}
+	}
+	return func() {
+		// Released even when the request's context is done, or the context
+		// stays locked until the lease runs out
+		if err := h.contextLock.Release(context.WithoutCancel(ctx), contextID, owner); err != nil {
+			LoggerFromContext(ctx).Warn("failed to release context lock", LogKeyContextID, contextID, LogKeyError, err)
+		}
+	}, nil
+}
+
+// MemoryContextLock implements ContextLock in memory. On Lambda it only
+// serializes executions on the same instance.
+type MemoryContextLock struct {
+	mu    sync.Mutex
+	locks map[string]memoryContextLease
+	runtimeDeps
+}
+
+type memoryContextLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// NewMemoryContextLock creates an in-memory context lock
+func NewMemoryContextLock(opts ...RuntimeOption) *MemoryContextLock {
+	return &MemoryContextLock{locks: make(map[string]memoryContextLease), runtimeDeps: newRuntimeDeps(opts)}
+}
+
+// Acquire implements ContextLock
+func (l *MemoryContextLock) Acquire(ctx context.Context, contextID, owner string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lease, ok := l.locks[contextID]; ok && lease.owner != owner && l.clock.Now().Before(lease.expiresAt) {
+		return ErrContextBusy
+	}
+	l.locks[contextID] = memoryContextLease{owner: owner, expiresAt: expiresAt}
+	return nil
+}
+
+// Release implements ContextLock
+func (l *MemoryContextLock) Release(ctx context.Context, contextID, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks[contextID].owner == owner {
+		delete(l.locks, contextID)
+	}
+	return nil
+}
+
+// AWSContextLock implements ContextLock with conditional writes to a
+// DynamoDB table, so executions on every instance are serialized. Items are
+// keyed by context_id and carry an expires_at number attribute, which
+// should be the table's TTL attribute.
+type AWSContextLock struct {
+	client    DynamoDBAPI
+	tableName string
+	runtimeDeps
+}
+
+// NewAWSContextLock creates a DynamoDB context lock
+func NewAWSContextLock(client DynamoDBAPI, tableName string, opts ...RuntimeOption) *AWSContextLock {
+	return &AWSContextLock{
+		client:      client,
+		tableName:   tableName,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// Acquire writes the lock unless another owner holds an unexpired one.
+// DynamoDB deletes expired items lazily, so an expired item is overwritten.
+func (l *AWSContextLock) Acquire(ctx context.Context, contextID, owner string, expiresAt time.Time) error {
+	defer observeStorage(ctx, "AcquireContextLock", time.Now())
+
+	_, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item: map[string]types.AttributeValue{
+			"context_id": &types.AttributeValueMemberS{Value: contextID},
+			"owner":      &types.AttributeValueMemberS{Value: owner},
+			"expires_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+		ConditionExpression:      aws.String("attribute_not_exists(context_id) OR expires_at <= :now OR #owner = :owner"),
+		ExpressionAttributeNames: map[string]string{"#owner": "owner"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":   &types.AttributeValueMemberN{Value: strconv.FormatInt(l.clock.Now().Unix(), 10)},
+			":owner": &types.AttributeValueMemberS{Value: owner},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrContextBusy
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire context lock in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// Release deletes the lock if owner still holds it
+func (l *AWSContextLock) Release(ctx context.Context, contextID, owner string) error {
+	defer observeStorage(ctx, "ReleaseContextLock", time.Now())
+
+	_, err := l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"context_id": &types.AttributeValueMemberS{Value: contextID},
+		},
+		ConditionExpression:       aws.String("#owner = :owner"),
+		ExpressionAttributeNames:  map[string]string{"#owner": "owner"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":owner": &types.AttributeValueMemberS{Value: owner}},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release context lock in DynamoDB: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestMemoryContextLock(t *testing.T) {
+	ctx := context.Background()
+	clock := &fixedClock{now: time.Unix(1754049600, 0)}
+	lock := NewMemoryContextLock(WithClock(clock))
+	expiresAt := clock.now.Add(time.Minute)
+
+	if err := lock.Acquire(ctx, "ctx-1", "a", expiresAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lock.Acquire(ctx, "ctx-1", "b", expiresAt); !errors.Is(err, ErrContextBusy) {
+		t.Errorf("expected ErrContextBusy, got %v", err)
+	}
+	if err := lock.Acquire(ctx, "ctx-2", "b", expiresAt); err != nil {
+		t.Errorf("expected another context to be free, got %v", err)
+	}
+	// Only the owner releases a lock
+	lock.Release(ctx, "ctx-1", "b")
+	if err := lock.Acquire(ctx, "ctx-1", "b", expiresAt); !errors.Is(err, ErrContextBusy) {
+		t.Errorf("expected the lock kept, got %v", err)
+	}
+	clock.now = expiresAt
+	if err := lock.Acquire(ctx, "ctx-1", "b", expiresAt.Add(time.Minute)); err != nil {
+		t.Errorf("expected an expired lock to be taken over, got %v", err)
+	}
+}
+
+func TestAWSContextLock(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1754049600, 0)
+
+	client, requests := recordingDynamoDB(t)
+	lock := NewAWSContextLock(client, "locks", WithClock(fixedClock{now}))
+	if err := lock.Acquire(ctx, "ctx-1", "owner-1", now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := lock.Release(ctx, "ctx-1", "owner-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acquire, release := (*requests)[0], (*requests)[1]
+	if attributeS(acquire, "Item", "context_id") != "ctx-1" || attributeS(acquire, "Item", "owner") != "owner-1" {
+		t.Errorf("expected the context locked for its owner, got %v", acquire["Item"])
+	}
+	if acquire["ConditionExpression"] != "attribute_not_exists(context_id) OR expires_at <= :now OR #owner = :owner" {
+		t.Errorf("expected a conditional put, got %v", acquire["ConditionExpression"])
+	}
+	if release["ConditionExpression"] != "#owner = :owner" {
+		t.Errorf("expected a delete by the owner only, got %v", release["ConditionExpression"])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+	}))
+	defer server.Close()
+	held := NewAWSContextLock(dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}), "locks")
+	if err := held.Acquire(ctx, "ctx-1", "owner-2", now.Add(time.Minute)); !errors.Is(err, ErrContextBusy) {
+		t.Errorf("expected ErrContextBusy, got %v", err)
+	}
+	if err := held.Release(ctx, "ctx-1", "owner-2"); err != nil {
+		t.Errorf("expected a lock held by another to be left quietly, got %v", err)
+	}
+}
+
+func TestHandlerSerializesContexts(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	lock := NewMemoryContextLock()
+	executor := &failingExecutor{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor), WithContextLock(lock, 0))
+
+	result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	follow := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-2", Role: a2a.MessageRoleUser, TaskID: &task.ID}}
+
+	// Another execution holds the context
+	lock.Acquire(ctx, task.ContextID, "other", time.Now().Add(time.Minute))
+	if _, err := h.OnSendMessage(ctx, follow); !errors.Is(err, ErrContextBusy) || executor.calls != 1 {
+		t.Fatalf("expected ErrContextBusy without executing, got %v after %d executions", err, executor.calls)
+	}
+
+	// Waiting, the message runs once the other execution is done
+	h = NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor), WithContextLock(lock, 5*time.Second))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock.Release(ctx, task.ContextID, "other")
+	}()
+	if _, err := h.OnSendMessage(ctx, follow); err != nil || executor.calls != 2 {
+		t.Fatalf("expected the message executed after the wait, got %v after %d executions", err, executor.calls)
+	}
+	// And the handler's own lock is released
+	if err := lock.Acquire(ctx, task.ContextID, "other", time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("expected the context released, got %v", err)
+	}
+}
+
+func TestLoadContextLockConfigFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	config, err := LoadContextLockConfigFromEnv()
+	if err != nil || config.Table != "" || config.Wait != DefaultContextLockWait || config.RuntimeOptions(nil) != nil {
+		t.Errorf("expected no lock by default, got %+v, %v", config, err)
+	}
+
+	t.Setenv("A2A_CONTEXT_LOCK_TABLE", "a2a-context-locks")
+	t.Setenv("A2A_CONTEXT_LOCK_WAIT", "0s")
+	config, err = LoadContextLockConfigFromEnv()
+	if err != nil || config.Table != "a2a-context-locks" || config.Wait != 0 || len(config.RuntimeOptions(nil)) != 1 {
+		t.Errorf("expected the environment to be read, got %+v, %v", config, err)
+	}
+
+	for _, value := range []string{"-1s", "soon"} {
+		t.Setenv("A2A_CONTEXT_LOCK_WAIT", value)
+		if _, err := LoadContextLockConfigFromEnv(); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+// overlapExecutor counts executions that began while another was running
+type overlapExecutor struct {
+	running  atomic.Int32
+	overlaps atomic.Int32
+}
+
+func (e *overlapExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	if e.running.Add(1) > 1 {
+		e.overlaps.Add(1)
+	}
+	time.Sleep(50 * time.Millisecond)
+	e.running.Add(-1)
+	return nil
+}
+
+func (e *overlapExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestHandlerSerializesNewTasksInContext(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	executor := &overlapExecutor{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor), WithContextLock(NewMemoryContextLock(), 5*time.Second))
+
+	contextID := "ctx-1"
+	results := make(chan a2a.SendMessageResult, 2)
+	errs := make(chan error, 2)
+	var wg sync.WaitGroup
+	for _, messageID := range []string{"m-1", "m-2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: messageID, Role: a2a.MessageRoleUser, ContextID: &contextID}})
+			results <- result
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if overlaps := executor.overlaps.Load(); overlaps != 0 {
+		t.Errorf("expected executions in one context serialized, got %d overlapping", overlaps)
+	}
+	ids := map[a2a.TaskID]bool{}
+	for result := range results {
+		task := result.(a2a.Task)
+		if task.ContextID != contextID {
+			t.Errorf("expected the task in the client's context, got %q", task.ContextID)
+		}
+		ids[task.ID] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("expected two tasks, got %v", ids)
+	}
+}
@@ -0,0 +1,348 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrDuplicateMessage is returned for a message that was already received
+// and is still being handled
+var ErrDuplicateMessage = errors.New("message already received")
+
+// DefaultDedupWindow is how long a message ID is remembered by default
+const DefaultDedupWindow = time.Hour
+
+// QueueIDMetadataKey is the params metadata entry identifying one queueing
+// of a message, so a queue's duplicate deliveries of it are executed once
+// while each retry still is
+const QueueIDMetadataKey = "queueId"
+
+// SentMessage is what handling a message produced: the task it went to,
+// or the agent's direct reply
+type SentMessage struct {
+	TaskID a2a.TaskID   `json:"task_id,omitempty"`
+	Reply  *a2a.Message `json:"reply,omitempty"`
+}
+
+// MessageDeduplicator remembers the messages handled, by key, for a while
+type MessageDeduplicator interface {
+	// Claim takes key until expiresAt. While an unexpired claim holds it,
+	// Claim returns ErrDuplicateMessage and what the earlier message
+	// produced, which is empty while it is still being handled.
+	Claim(ctx context.Context, key string, expiresAt time.Time) (SentMessage, error)
+	// Complete records what the message produced
+	Complete(ctx context.Context, key string, sent SentMessage, expiresAt time.Time) error
+	// Release drops the claim of a message that failed, so a retry of it
+	// is handled
+	Release(ctx context.Context, key string) error
+}
+
+// DedupConfig names the DynamoDB table received messages are remembered
+// in. Deduplication is off unless Table is set.
+type DedupConfig struct {
+	Table  string
+	Window time.Duration
+}
+
+// LoadDedupConfigFromEnv reads A2A_DEDUP_TABLE and A2A_DEDUP_WINDOW
+// (default 1h)
+func LoadDedupConfigFromEnv() (DedupConfig, error) {
+	config := DedupConfig{
+		Table:  getEnvOrDefault("A2A_DEDUP_TABLE", ""),
+		Window: DefaultDedupWindow,
+	}
+	if value := getEnvOrDefault("A2A_DEDUP_WINDOW", ""); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			return DedupConfig{}, fmt.Errorf("A2A_DEDUP_WINDOW must be a positive duration, got %q", value)
+		}
+		config.Window = window
+	}
+	return config, nil
+}
+
+// RuntimeOptions returns the option remembering messages in the table
+// through client, under keyPrefix, none when no table is set. Remembered
+// replies are sealed with cipher when it is not nil.
+func (c DedupConfig) RuntimeOptions(client DynamoDBAPI, keyPrefix string, cipher *ContentCipher) []RuntimeOption {
+	if c.Table == "" {
+		return nil
+	}
+	var dedup MessageDeduplicator = NewAWSMessageDeduplicator(client, c.Table, keyPrefix)
+	if cipher != nil {
+		dedup = NewEncryptedMessageDeduplicator(dedup, cipher)
+	}
+	return []RuntimeOption{WithMessageDeduplication(dedup, c.Window)}
+}
+
+// WithMessageDeduplication has message/send return the earlier result for
+// a messageId already received within window, DefaultDedupWindow when 0,
+// instead of handling the message again, and has a queue's duplicate
+// deliveries executed once
+func WithMessageDeduplication(dedup MessageDeduplicator, window time.Duration) RuntimeOption {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return func(d *runtimeDeps) {
+		d.dedup = dedup
+		d.dedupWindow = window
+	}
+}
+
+// sendOnce handles a message unless its messageId was received before, in
+// which case the earlier result is returned as it now is. A failure to
+// claim the message is logged and the message handled, as if unseen.
+func (h *ServerlessA2AHandler) sendOnce(ctx context.Context, message a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	key := sentMessageKey(ctx, message.Message)
+	earlier, err := h.dedup.Claim(ctx, key, h.clock.Now().Add(h.dedupWindow))
+	if errors.Is(err, ErrDuplicateMessage) {
+		LoggerFromContext(ctx).Info("duplicate message", "message_id", message.Message.MessageID)
+		return h.earlierResult(ctx, message, earlier)
+	}
+	if err != nil {
+		LoggerFromContext(ctx).Warn("failed to check message for duplicates", LogKeyError, err)
+		return h.sendMessage(ctx, message, false)
+	}
+
+	result, err := h.sendMessage(ctx, message, false)
+	if err != nil {
+		if err := h.dedup.Release(ctx, key); err != nil {
+			LoggerFromContext(ctx).Warn("failed to release message", LogKeyError, err)
+		}
+		return nil, err
+	}
+	var sent SentMessage
+	switch r := result.(type) {
+	case a2a.Task:
+		sent.TaskID = r.ID
+	case a2a.Message:
+		sent.Reply = &r
+	}
+	if err := h.dedup.Complete(ctx, key, sent, h.clock.Now().Add(h.dedupWindow)); err != nil {
+		LoggerFromContext(ctx).Warn("failed to record message", LogKeyError, err)
+	}
+	return result, nil
+}
+
+// sentMessageKey is what identifies a message as a duplicate. A message ID
+// is the sender's, so it is only a duplicate from the same caller, in the
+// same context and on the same task; another caller reusing the ID is not
+// answered with the first caller's task.
+func sentMessageKey(ctx context.Context, message a2a.Message) string {
+	principal, _ := PrincipalFromContext(ctx)
+	var contextID, taskID string
+	if message.ContextID != nil {
+		contextID = *message.ContextID
+	}
+	if message.TaskID != nil {
+		taskID = string(*message.TaskID)
+	}
+	return strings.Join([]string{principal.Subject, contextID, taskID, message.MessageID}, "/")
+}
+
+// earlierResult returns what an earlier copy of a message produced: its
+// reply, or its task as it is now
+func (h *ServerlessA2AHandler) earlierResult(ctx context.Context, message a2a.MessageSendParams, earlier SentMessage) (a2a.SendMessageResult, error) {
+	if earlier.Reply != nil {
+		return *earlier.Reply, nil
+	}
+	if earlier.TaskID == "" {
+		return nil, fmt.Errorf("message %s: %w", message.Message.MessageID, ErrDuplicateMessage)
+	}
+	task, err := h.OnGetTask(ctx, a2a.TaskQueryParams{ID: earlier.TaskID})
+	if err != nil {
+		return nil, err
+	}
+	if message.Config != nil {
+		task = limitHistory(task, message.Config.HistoryLength)
+	}
+	return task, nil
+}
+
+// processOnce executes a queued message unless this queueing of it was
+// executed before
+func (h *ServerlessA2AHandler) processOnce(ctx context.Context, params a2a.MessageSendParams) error {
+	queueID, _ := params.Metadata[QueueIDMetadataKey].(string)
+	if h.dedup == nil || queueID == "" {
+		_, err := h.sendMessage(ctx, params, true)
+		return err
+	}
+	key := "queued/" + queueID
+	_, err := h.dedup.Claim(ctx, key, h.clock.Now().Add(h.dedupWindow))
+	if errors.Is(err, ErrDuplicateMessage) {
+		LoggerFromContext(ctx).Info("duplicate queued message", "queue_id", queueID)
+		return nil
+	}
+	if err != nil {
+		LoggerFromContext(ctx).Warn("failed to check message for duplicates", LogKeyError, err)
+	}
+	if _, err := h.sendMessage(ctx, params, true); err != nil {
+		if err := h.dedup.Release(ctx, key); err != nil {
+			LoggerFromContext(ctx).Warn("failed to release message", LogKeyError, err)
+		}
+		return err
+	}
+	if err := h.dedup.Complete(ctx, key, SentMessage{TaskID: *params.Message.TaskID}, h.clock.Now().Add(h.dedupWindow)); err != nil {
+		LoggerFromContext(ctx).Warn("failed to record message", LogKeyError, err)
+	}
+	return nil
+}
+
+// MemoryMessageDeduplicator implements MessageDeduplicator in memory. On
+// Lambda it only catches duplicates sent to the same instance.
+type MemoryMessageDeduplicator struct {
+	mu       sync.Mutex
+	messages map[string]memorySentMessage
+	runtimeDeps
+}
+
+type memorySentMessage struct {
+	sent      SentMessage
+	expiresAt time.Time
+}
+
+// NewMemoryMessageDeduplicator creates an empty in-memory deduplicator
+func NewMemoryMessageDeduplicator(opts ...RuntimeOption) *MemoryMessageDeduplicator {
+	return &MemoryMessageDeduplicator{messages: make(map[string]memorySentMessage), runtimeDeps: newRuntimeDeps(opts)}
+}
+
+// Claim implements MessageDeduplicator, dropping expired claims as it goes
+func (d *MemoryMessageDeduplicator) Claim(ctx context.Context, key string, expiresAt time.Time) (SentMessage, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	for seen, message := range d.messages {
+		if !now.Before(message.expiresAt) {
+			delete(d.messages, seen)
+		}
+	}
+	if message, ok := d.messages[key]; ok {
+		return message.sent, ErrDuplicateMessage
+	}
+	d.messages[key] = memorySentMessage{expiresAt: expiresAt}
+	return SentMessage{}, nil
+}
+
+// Complete implements MessageDeduplicator
+func (d *MemoryMessageDeduplicator) Complete(ctx context.Context, key string, sent SentMessage, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages[key] = memorySentMessage{sent: sent, expiresAt: expiresAt}
+	return nil
+}
+
+// Release implements MessageDeduplicator
+func (d *MemoryMessageDeduplicator) Release(ctx context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.messages, key)
+	return nil
+}
+
+// AWSMessageDeduplicator implements MessageDeduplicator using DynamoDB, so
+// duplicates sent to different instances are caught. Items are keyed by
+// message_key, hold the result as JSON once the message is handled, and
+// carry an expires_at number attribute, which should be the table's TTL
+// attribute.
+type AWSMessageDeduplicator struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+	runtimeDeps
+}
+
+// NewAWSMessageDeduplicator creates a DynamoDB deduplicator. keyPrefix
+// namespaces the keys of one agent in a shared table.
+func NewAWSMessageDeduplicator(client DynamoDBAPI, tableName, keyPrefix string, opts ...RuntimeOption) *AWSMessageDeduplicator {
+	return &AWSMessageDeduplicator{
+		client:      client,
+		tableName:   tableName,
+		keyPrefix:   keyPrefix,
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// Claim writes the key unless an unexpired item already holds it, whose
+// result is returned. DynamoDB deletes expired items lazily, so an expired
+// item is overwritten.
+func (d *AWSMessageDeduplicator) Claim(ctx context.Context, key string, expiresAt time.Time) (SentMessage, error) {
+	defer observeStorage(ctx, "ClaimSentMessage", time.Now())
+
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.tableName),
+		Item:                d.item(key, expiresAt),
+		ConditionExpression: aws.String("attribute_not_exists(message_key) OR expires_at <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(d.clock.Now().Unix(), 10)},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		var sent SentMessage
+		if result, ok := conditionFailed.Item["result"].(*types.AttributeValueMemberS); ok {
+			if err := FromJSON([]byte(result.Value), &sent); err != nil {
+				return SentMessage{}, fmt.Errorf("failed to unmarshal sent message: %w", err)
+			}
+		}
+		return sent, ErrDuplicateMessage
+	}
+	if err != nil {
+		return SentMessage{}, fmt.Errorf("failed to claim message in DynamoDB: %w", err)
+	}
+	return SentMessage{}, nil
+}
+
+// Complete implements MessageDeduplicator
+func (d *AWSMessageDeduplicator) Complete(ctx context.Context, key string, sent SentMessage, expiresAt time.Time) error {
+	defer observeStorage(ctx, "CompleteSentMessage", time.Now())
+
+	result, err := MarshalJSONString(sent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sent message: %w", err)
+	}
+	item := d.item(key, expiresAt)
+	item["result"] = &types.AttributeValueMemberS{Value: result}
+	_, err = d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record sent message in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// Release implements MessageDeduplicator
+func (d *AWSMessageDeduplicator) Release(ctx context.Context, key string) error {
+	defer observeStorage(ctx, "ReleaseSentMessage", time.Now())
+
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.tableName),
+		Key: map[string]types.AttributeValue{
+			"message_key": &types.AttributeValueMemberS{Value: d.keyPrefix + key},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release message in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+func (d *AWSMessageDeduplicator) item(key string, expiresAt time.Time) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"message_key": &types.AttributeValueMemberS{Value: d.keyPrefix + key},
+		"expires_at":  &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+}
@@ -0,0 +1,200 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// replyExecutor answers every message directly
+type replyExecutor struct {
+	calls int
+}
+
+func (e *replyExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	e.calls++
+	return queue.Write(ctx, a2a.Message{MessageID: "reply", Role: a2a.MessageRoleAgent})
+}
+
+func (e *replyExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestHandlerSuppressesDuplicateMessages(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	dedup := NewMemoryMessageDeduplicator()
+	executor := &failingExecutor{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor), WithMessageDeduplication(dedup, 0))
+	message := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}}
+
+	first, err := h.OnSendMessage(ctx, message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := h.OnSendMessage(ctx, message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := again.(a2a.Task)
+	if task.ID != first.(a2a.Task).ID || executor.calls != 1 || len(stores.tasks) != 1 || len(task.History) != 1 {
+		t.Errorf("expected the first task back without handling the message again, got %+v after %d executions", task, executor.calls)
+	}
+
+	// The same ID on the task is a follow-up of its own
+	followUp := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser, TaskID: &task.ID}}
+	if _, err := h.OnSendMessage(ctx, followUp); err != nil || executor.calls != 2 {
+		t.Errorf("expected the follow-up handled, got %v after %d executions", err, executor.calls)
+	}
+
+	// Another caller reusing the ID sends a message of its own
+	other := ContextWithPrincipal(ctx, Principal{Scheme: "bearer", Subject: "mallory"})
+	if theirs, err := h.OnSendMessage(other, message); err != nil || theirs.(a2a.Task).ID == task.ID || executor.calls != 3 {
+		t.Errorf("expected another caller's message handled as new, got %+v after %d executions: %v", theirs, executor.calls, err)
+	}
+
+	// A message still being handled is refused, and a failed one handled again
+	dedup.Claim(ctx, "///m-2", h.clock.Now().Add(DefaultDedupWindow))
+	if _, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-2", Role: a2a.MessageRoleUser}}); !errors.Is(err, ErrDuplicateMessage) {
+		t.Errorf("expected ErrDuplicateMessage, got %v", err)
+	}
+	failing := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(&failingExecutor{errs: []error{ErrTransient}}), WithMessageDeduplication(dedup, 0))
+	failed := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-3", Role: a2a.MessageRoleUser}}
+	if _, err := failing.OnSendMessage(ctx, failed); err == nil {
+		t.Fatal("expected the executor's error")
+	}
+	if _, err := h.OnSendMessage(ctx, failed); err != nil {
+		t.Errorf("expected a failed message handled again, got %v", err)
+	}
+
+	// A direct reply is returned again as it was
+	replies := &replyExecutor{}
+	h = NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(replies), WithMessageDeduplication(dedup, 0))
+	ask := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-4", Role: a2a.MessageRoleUser}}
+	h.OnSendMessage(ctx, ask)
+	if reply, err := h.OnSendMessage(ctx, ask); err != nil || reply.(a2a.Message).MessageID != "reply" || replies.calls != 1 {
+		t.Errorf("expected the earlier reply, got %+v after %d executions: %v", reply, replies.calls, err)
+	}
+}
+
+func TestHandlerExecutesQueuedMessagesOnce(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	front := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithWorkQueue(NewSQSMessageQueue(queue, "work")))
+	if _, err := front.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	executor := &failingExecutor{}
+	worker := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor), WithMessageDeduplication(NewMemoryMessageDeduplicator(), 0))
+	for range 2 {
+		if err := worker.ProcessQueuedMessage(ctx, *queue.sent[0].MessageBody); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if executor.calls != 1 {
+		t.Errorf("expected a duplicate delivery executed once, got %d executions", executor.calls)
+	}
+}
+
+func TestAWSMessageDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Unix(1754049600, 0)
+	client, requests := recordingDynamoDB(t)
+	dedup := NewAWSMessageDeduplicator(client, "messages", "billing#")
+	if _, err := dedup.Claim(ctx, "task-1/m-1", expiresAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := dedup.Complete(ctx, "task-1/m-1", SentMessage{TaskID: "task-1"}, expiresAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	claim, complete := (*requests)[0], (*requests)[1]
+	if attributeS(claim, "Item", "message_key") != "billing#task-1/m-1" || claim["ReturnValuesOnConditionCheckFailure"] != "ALL_OLD" {
+		t.Errorf("expected a prefixed claim returning the earlier item, got %v", claim)
+	}
+	if claim["ConditionExpression"] != "attribute_not_exists(message_key) OR expires_at <= :now" {
+		t.Errorf("expected a conditional put, got %v", claim["ConditionExpression"])
+	}
+	if attributeS(complete, "Item", "result") != `{"task_id":"task-1"}` {
+		t.Errorf("expected the result recorded, got %v", complete["Item"])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed","Item":{"message_key":{"S":"task-1/m-1"},"result":{"S":"{\"task_id\":\"task-1\"}"}}}`))
+	}))
+	defer server.Close()
+	seen := NewAWSMessageDeduplicator(dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}), "messages", "")
+	if sent, err := seen.Claim(ctx, "task-1/m-1", expiresAt); !errors.Is(err, ErrDuplicateMessage) || sent.TaskID != "task-1" {
+		t.Errorf("expected the earlier task and ErrDuplicateMessage, got %+v %v", sent, err)
+	}
+}
+
+func TestEncryptedMessageDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Unix(1754049600, 0)
+	cipher, err := NewContentCipher(testContentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, requests := recordingDynamoDB(t)
+	reply := &a2a.Message{MessageID: "reply", Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "the secret answer"}}}
+	dedup := NewEncryptedMessageDeduplicator(NewAWSMessageDeduplicator(client, "messages", ""), cipher)
+	if err := dedup.Complete(ctx, "///m-1", SentMessage{Reply: reply}, expiresAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result := attributeS((*requests)[0], "Item", "result"); strings.Contains(result, "the secret answer") || !strings.Contains(result, encryptedPrefix) {
+		t.Errorf("expected the reply encrypted in the item, got %s", result)
+	}
+
+	later := time.Now().Add(time.Hour)
+	memory := NewEncryptedMessageDeduplicator(NewMemoryMessageDeduplicator(), cipher)
+	memory.Claim(ctx, "///m-1", later)
+	memory.Complete(ctx, "///m-1", SentMessage{Reply: reply}, later)
+	sent, err := memory.Claim(ctx, "///m-1", later)
+	if !errors.Is(err, ErrDuplicateMessage) || sent.Reply.Parts[0].(a2a.TextPart).Text != "the secret answer" {
+		t.Errorf("expected the reply opened, got %+v, %v", sent, err)
+	}
+	if reply.Parts[0].(a2a.TextPart).Text != "the secret answer" {
+		t.Errorf("expected the caller's reply unchanged, got %+v", reply)
+	}
+}
+
+func TestLoadDedupConfigFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	config, err := LoadDedupConfigFromEnv()
+	if err != nil || config.Table != "" || config.Window != DefaultDedupWindow || config.RuntimeOptions(nil, "", nil) != nil {
+		t.Errorf("expected no deduplication by default, got %+v, %v", config, err)
+	}
+
+	t.Setenv("A2A_DEDUP_TABLE", "a2a-messages")
+	t.Setenv("A2A_DEDUP_WINDOW", "10m")
+	config, err = LoadDedupConfigFromEnv()
+	if err != nil || config.Table != "a2a-messages" || config.Window != 10*time.Minute || len(config.RuntimeOptions(nil, "", nil)) != 1 {
+		t.Errorf("expected the environment to be read, got %+v, %v", config, err)
+	}
+
+	for _, value := range []string{"0s", "soon"} {
+		t.Setenv("A2A_DEDUP_WINDOW", value)
+		if _, err := LoadDedupConfigFromEnv(); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
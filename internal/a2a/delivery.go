@@ -0,0 +1,156 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// DeliveryAttempt records one attempt to post a push notification to its
+// webhook
+type DeliveryAttempt struct {
+	TaskID a2a.TaskID `json:"task_id"`
+	// ConfigID is the push config's ID, empty when the client set none
+	ConfigID string `json:"config_id,omitempty"`
+	// MessageID is the queue message, shared by every attempt to deliver it
+	MessageID string `json:"message_id,omitempty"`
+	// URL is the webhook without its query string or user info, which may
+	// hold credentials
+	URL         string    `json:"url"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	// Attempt counts the deliveries of the message from 1, so above 1 the
+	// attempt is a retry; 0 when the queue did not say
+	Attempt int `json:"attempt"`
+	// StatusCode is the webhook's response status, 0 when it did not answer
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Delivered reports whether the webhook accepted the notification
+func (a DeliveryAttempt) Delivered() bool {
+	return a.Error == ""
+}
+
+// Bounds of the delivery history one call lists
+const (
+	DefaultDeliveryListLimit = 20
+	MaxDeliveryListLimit     = 100
+)
+
+// TaskPushDeliveriesParams are the params of the push config deliveries
+// method. Members use the Go field names, like the A2A params.
+type TaskPushDeliveriesParams struct {
+	TaskID a2a.TaskID
+	// ConfigID limits the history to one push config
+	ConfigID *string
+	// Limit bounds the attempts listed, newest first (default
+	// DefaultDeliveryListLimit, at most MaxDeliveryListLimit)
+	Limit int
+}
+
+// Validate checks the params of a deliveries call
+func (p TaskPushDeliveriesParams) Validate() error {
+	var errs ValidationErrors
+	if p.TaskID == "" {
+		errs.Add("TaskID", ValidationCodeRequired, "is required")
+	}
+	if p.Limit < 0 || p.Limit > MaxDeliveryListLimit {
+		errs.Add("Limit", ValidationCodeInvalid, fmt.Sprintf("must be between 0 and %d", MaxDeliveryListLimit))
+	}
+	return errs.Err()
+}
+
+// deliveryLimit applies the default and maximum to a requested limit
+func deliveryLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultDeliveryListLimit
+	}
+	return min(limit, MaxDeliveryListLimit)
+}
+
+// DeliveryLog persists webhook delivery attempts, so a client can tell
+// whether its webhook was ever called
+type DeliveryLog interface {
+	RecordDelivery(ctx context.Context, attempt DeliveryAttempt) error
+	// ListDeliveries returns a task's attempts newest first
+	ListDeliveries(ctx context.Context, params TaskPushDeliveriesParams) ([]DeliveryAttempt, error)
+}
+
+// DeliveryConfig selects where delivery attempts are recorded. Recording is
+// off unless Table is set.
+type DeliveryConfig struct {
+	Table string
+	TTL   time.Duration
+}
+
+// DefaultDeliveryTTL is how long delivery attempts are kept
+const DefaultDeliveryTTL = 30 * 24 * time.Hour
+
+// LoadDeliveryConfigFromEnv reads A2A_DELIVERY_TABLE and A2A_DELIVERY_TTL
+// (default 720h)
+func LoadDeliveryConfigFromEnv() (DeliveryConfig, error) {
+	config := DeliveryConfig{
+		Table: getEnvOrDefault("A2A_DELIVERY_TABLE", ""),
+		TTL:   DefaultDeliveryTTL,
+	}
+	if value := getEnvOrDefault("A2A_DELIVERY_TTL", ""); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return DeliveryConfig{}, fmt.Errorf("A2A_DELIVERY_TTL must be a positive duration, got %q", value)
+		}
+		config.TTL = ttl
+	}
+	return config, nil
+}
+
+type deliveryAttemptKey struct{}
+
+// ContextWithDeliveryAttempt records which delivery of its queue message a
+// webhook call is, for the delivery log
+func ContextWithDeliveryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, deliveryAttemptKey{}, attempt)
+}
+
+// deliveryAttemptFromContext returns the attempt set by ContextWithDeliveryAttempt, or 0
+func deliveryAttemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(deliveryAttemptKey{}).(int)
+	return attempt
+}
+
+// notificationTaskID reads the task ID of a queued notification's event,
+// which is the ID of a task and the TaskID of the other events
+func notificationTaskID(event json.RawMessage) a2a.TaskID {
+	var probe struct {
+		Kind   string
+		ID     string
+		TaskID *string
+	}
+	if err := json.Unmarshal(event, &probe); err != nil {
+		return ""
+	}
+	if probe.Kind == "task" {
+		return a2a.TaskID(probe.ID)
+	}
+	if probe.TaskID == nil {
+		return ""
+	}
+	return a2a.TaskID(*probe.TaskID)
+}
+
+// webhookLogURL strips what may be secret from a webhook URL before it is
+// recorded
+func webhookLogURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
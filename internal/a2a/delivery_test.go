@@ -0,0 +1,192 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// memoryDeliveryLog keeps the attempts recorded, in order
+type memoryDeliveryLog struct {
+	attempts []DeliveryAttempt
+}
+
+func (l *memoryDeliveryLog) RecordDelivery(ctx context.Context, attempt DeliveryAttempt) error {
+	l.attempts = append(l.attempts, attempt)
+	return nil
+}
+
+func (l *memoryDeliveryLog) ListDeliveries(ctx context.Context, params TaskPushDeliveriesParams) ([]DeliveryAttempt, error) {
+	return l.attempts, nil
+}
+
+func TestWebhookDelivererRecordsDeliveries(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	configID := "c-1"
+	notification := func(url string, event string) string {
+		body, err := json.Marshal(PushNotification{PushConfig: a2a.PushConfig{ID: &configID, URL: url}, Event: json.RawMessage(event)})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(body)
+	}
+	statusEvent := `{"kind":"status-update","taskId":"task-1"}`
+
+	deliveries := &memoryDeliveryLog{}
+	deliverer := NewWebhookDeliverer(server.Client(), nil).WithDeliveryLog(deliveries)
+	ctx := ContextWithDeliveryAttempt(WithLogAttrs(context.Background(), LogKeyMessageID, "msg-1"), 2)
+
+	if err := deliverer.Deliver(ctx, notification(server.URL+"/hook?token=secret", statusEvent), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status = http.StatusServiceUnavailable
+	if err := deliverer.Deliver(ctx, notification(server.URL+"/hook", `{"kind":"task","id":"task-2"}`), nil); err == nil {
+		t.Fatal("expected the failed delivery to return an error")
+	}
+	unreachable := strings.Replace(server.URL, "127.0.0.1", "user:pass@127.0.0.1", 1) + "/closed?token=secret"
+	server.Close()
+	if err := deliverer.Deliver(ctx, notification(unreachable, statusEvent), nil); err == nil {
+		t.Fatal("expected the unreachable webhook to return an error")
+	}
+	// Notifications that do not name a task cannot be listed, so are not kept
+	if err := deliverer.Deliver(ctx, notification(server.URL, `{}`), nil); err == nil {
+		t.Fatal("expected the unreachable webhook to return an error")
+	}
+
+	if len(deliveries.attempts) != 3 {
+		t.Fatalf("expected 3 attempts recorded, got %+v", deliveries.attempts)
+	}
+	delivered, refused, failed := deliveries.attempts[0], deliveries.attempts[1], deliveries.attempts[2]
+	if delivered.TaskID != "task-1" || delivered.ConfigID != "c-1" || delivered.MessageID != "msg-1" || delivered.Attempt != 2 {
+		t.Errorf("expected the attempt to name its task, config, message and retry, got %+v", delivered)
+	}
+	if !delivered.Delivered() || delivered.StatusCode != http.StatusOK || delivered.URL != server.URL+"/hook" {
+		t.Errorf("expected a delivery without its query string, got %+v", delivered)
+	}
+	if refused.TaskID != "task-2" || refused.Delivered() || refused.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the refused delivery with its status, got %+v", refused)
+	}
+	if failed.Delivered() || failed.StatusCode != 0 {
+		t.Errorf("expected the failed delivery without a status, got %+v", failed)
+	}
+	for _, secret := range []string{"secret", "pass"} {
+		if strings.Contains(failed.URL, secret) || strings.Contains(failed.Error, secret) {
+			t.Errorf("expected %q to be left out of the attempt, got %+v", secret, failed)
+		}
+	}
+}
+
+// pagingDynamoDB answers each Query with one of items, as DynamoDB pages
+// do when a filter or limit stops them short
+type pagingDynamoDB struct {
+	DynamoDBAPI
+	items   []map[string]types.AttributeValue
+	queries []*dynamodb.QueryInput
+}
+
+func (d *pagingDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	d.queries = append(d.queries, params)
+	page := len(d.queries) - 1
+	if page >= len(d.items) {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{d.items[page]}}
+	if page < len(d.items)-1 {
+		output.LastEvaluatedKey = map[string]types.AttributeValue{"task_id": &types.AttributeValueMemberS{Value: "task-1"}}
+	}
+	return output, nil
+}
+
+func TestAWSDeliveryLog(t *testing.T) {
+	ctx := context.Background()
+	recorder := &itemsDynamoDB{}
+	log := NewAWSDeliveryLog(recorder, "deliveries", time.Hour)
+	attemptedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, configID := range []string{"c-1", "c-2", "c-1"} {
+		attempt := DeliveryAttempt{TaskID: "task-1", ConfigID: configID, MessageID: "msg-1", AttemptedAt: attemptedAt.Add(time.Duration(i) * time.Second), Attempt: i + 1}
+		if err := log.RecordDelivery(ctx, attempt); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	first := recorder.items[0]
+	if got := first["attempted_at"].(*types.AttributeValueMemberS).Value; got != "2026-01-02T03:04:05Z#msg-1" {
+		t.Errorf("expected the sort key to order by time, got %q", got)
+	}
+	if got := first["expires_at"].(*types.AttributeValueMemberN).Value; got != "1767326645" {
+		t.Errorf("expected the attempt to expire an hour after it was made, got %s", got)
+	}
+
+	client := &pagingDynamoDB{items: recorder.items}
+	configID := "c-1"
+	attempts, err := NewAWSDeliveryLog(client, "deliveries", time.Hour).ListDeliveries(ctx, TaskPushDeliveriesParams{TaskID: "task-1", ConfigID: &configID, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attempts) != 2 || len(client.queries) != 2 {
+		t.Fatalf("expected 2 attempts from 2 pages, got %+v from %d", attempts, len(client.queries))
+	}
+	query := client.queries[0]
+	if query.ScanIndexForward == nil || *query.ScanIndexForward || query.FilterExpression == nil {
+		t.Errorf("expected a newest-first query filtered by config, got %+v", query)
+	}
+	if client.queries[1].ExclusiveStartKey == nil {
+		t.Error("expected the second page to continue from the first")
+	}
+}
+
+func TestTaskPushDeliveriesParamsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     TaskPushDeliveriesParams
+		expectPath string
+	}{
+		{name: "valid", params: TaskPushDeliveriesParams{TaskID: "task-1", Limit: MaxDeliveryListLimit}},
+		{name: "missing task", params: TaskPushDeliveriesParams{}, expectPath: "TaskID"},
+		{name: "limit too large", params: TaskPushDeliveriesParams{TaskID: "task-1", Limit: MaxDeliveryListLimit + 1}, expectPath: "Limit"},
+		{name: "negative limit", params: TaskPushDeliveriesParams{TaskID: "task-1", Limit: -1}, expectPath: "Limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectPath == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectPath) {
+				t.Errorf("expected an error at %s, got %v", tt.expectPath, err)
+			}
+		})
+	}
+}
+
+func TestLoadDeliveryConfigFromEnv(t *testing.T) {
+	t.Setenv("A2A_DELIVERY_TABLE", "deliveries")
+	t.Setenv("A2A_DELIVERY_TTL", "")
+	config, err := LoadDeliveryConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Table != "deliveries" || config.TTL != DefaultDeliveryTTL {
+		t.Errorf("expected the table with the default TTL, got %+v", config)
+	}
+
+	t.Setenv("A2A_DELIVERY_TTL", "-1h")
+	if _, err := LoadDeliveryConfigFromEnv(); err == nil {
+		t.Error("expected a negative TTL to be refused")
+	}
+}
@@ -0,0 +1,182 @@
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// emfMaxValues is the most values CloudWatch accepts for one metric in one document
+const emfMaxValues = 100
+
+// EMFMetrics buffers metrics and writes them as CloudWatch Embedded Metric
+// Format log lines, which CloudWatch turns into metrics without any API calls
+// or sidecar. Create one per invocation and Flush before returning.
+type EMFMetrics struct {
+	namespace string
+	now       func() time.Time
+	mu        sync.Mutex
+	series    map[string]*emfSeries
+	order     []string
+}
+
+// emfSeries is one metric with a fixed set of dimension values
+type emfSeries struct {
+	name       string
+	unit       string
+	dimensions map[string]string
+	values     []float64
+}
+
+// NewEMFMetrics creates an EMF recorder for a CloudWatch namespace
+func NewEMFMetrics(namespace string) *EMFMetrics {
+	return &EMFMetrics{
+		namespace: namespace,
+		now:       time.Now,
+		series:    make(map[string]*emfSeries),
+	}
+}
+
+// RecordRequest implements Metrics
+func (m *EMFMetrics) RecordRequest(method string) {
+	m.add(MetricRequests, "Count", 1, map[string]string{"Method": method})
+}
+
+// RecordError implements Metrics
+func (m *EMFMetrics) RecordError(method string, code int) {
+	m.add(MetricErrors, "Count", 1, map[string]string{"Method": method, "Code": strconv.Itoa(code)})
+}
+
+// RecordTaskTransition implements Metrics
+func (m *EMFMetrics) RecordTaskTransition(from, to a2a.TaskState) {
+	m.add(MetricTaskTransitions, "Count", 1, map[string]string{"FromState": taskStateLabel(from), "ToState": taskStateLabel(to)})
+}
+
+// RecordStorageLatency implements Metrics
+func (m *EMFMetrics) RecordStorageLatency(operation string, latency time.Duration) {
+	m.add(MetricStorageLatency, "Milliseconds", float64(latency.Microseconds())/1000, map[string]string{"Operation": operation})
+}
+
+// RecordNotification implements Metrics
+func (m *EMFMetrics) RecordNotification(outcome string) {
+	m.add(MetricNotificationOutcomes, "Count", 1, map[string]string{"Outcome": outcome})
+}
+
+// RecordInitDuration implements Metrics
+func (m *EMFMetrics) RecordInitDuration(phase string, duration time.Duration) {
+	m.add(MetricInitDuration, "Milliseconds", float64(duration.Microseconds())/1000, map[string]string{"Phase": phase})
+}
+
+// RecordRetentionPurge implements Metrics
+func (m *EMFMetrics) RecordRetentionPurge(kind string, count int) {
+	m.add(MetricRetentionPurged, "Count", float64(count), map[string]string{"Kind": kind})
+}
+
+// add appends a value to the series identified by name and dimensions
+func (m *EMFMetrics) add(name, unit string, value float64, dimensions map[string]string) {
+	key := name + emfDimensionKey(dimensions)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.series[key]
+	if !ok {
+		series = &emfSeries{name: name, unit: unit, dimensions: dimensions}
+		m.series[key] = series
+		m.order = append(m.order, key)
+	}
+	// Counters collapse to one sum; latencies keep every sample for percentiles
+	if unit == "Count" && len(series.values) > 0 {
+		series.values[0] += value
+		return
+	}
+	series.values = append(series.values, value)
+}
+
+// Flush writes one EMF document per series and clears the buffer
+func (m *EMFMetrics) Flush(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timestamp := m.now().UnixMilli()
+	for _, key := range m.order {
+		series := m.series[key]
+		for start := 0; start < len(series.values); start += emfMaxValues {
+			end := min(start+emfMaxValues, len(series.values))
+			line, err := series.document(m.namespace, timestamp, series.values[start:end])
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				return fmt.Errorf("failed to write EMF metrics: %w", err)
+			}
+		}
+	}
+
+	m.series = make(map[string]*emfSeries)
+	m.order = nil
+	return nil
+}
+
+// document renders the series as an EMF JSON document
+func (s *emfSeries) document(namespace string, timestamp int64, values []float64) ([]byte, error) {
+	dimensionNames := make([]string, 0, len(s.dimensions))
+	for name := range s.dimensions {
+		dimensionNames = append(dimensionNames, name)
+	}
+	sort.Strings(dimensionNames)
+
+	doc := map[string]any{
+		"_aws": map[string]any{
+			"Timestamp": timestamp,
+			"CloudWatchMetrics": []map[string]any{{
+				"Namespace":  namespace,
+				"Dimensions": [][]string{dimensionNames},
+				"Metrics":    []map[string]string{{"Name": s.name, "Unit": s.unit}},
+			}},
+		},
+	}
+	for name, value := range s.dimensions {
+		doc[name] = value
+	}
+	if len(values) == 1 {
+		doc[s.name] = values[0]
+	} else {
+		doc[s.name] = values
+	}
+
+	line, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode EMF document: %w", err)
+	}
+	return line, nil
+}
+
+// emfDimensionKey builds a stable key for a dimension set
+func emfDimensionKey(dimensions map[string]string) string {
+	names := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := ""
+	for _, name := range names {
+		key += "|" + name + "=" + dimensions[name]
+	}
+	return key
+}
+
+// taskStateLabel names the state a new task starts from
+func taskStateLabel(state a2a.TaskState) string {
+	if state == "" {
+		return "none"
+	}
+	return string(state)
+}
@@ -0,0 +1,176 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestLoadMetricsConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		expected    MetricsConfig
+		expectError bool
+	}{
+		{
+			name:     "defaults to off",
+			expected: MetricsConfig{Mode: MetricsModeOff, Namespace: DefaultMetricsNamespace},
+		},
+		{
+			name:     "emf with namespace",
+			envVars:  map[string]string{"A2A_METRICS": "emf", "A2A_METRICS_NAMESPACE": "Agents/Billing"},
+			expected: MetricsConfig{Mode: MetricsModeEMF, Namespace: "Agents/Billing"},
+		},
+		{
+			name:     "prometheus",
+			envVars:  map[string]string{"A2A_METRICS": "prometheus"},
+			expected: MetricsConfig{Mode: MetricsModePrometheus, Namespace: DefaultMetricsNamespace},
+		},
+		{
+			name:        "unknown mode",
+			envVars:     map[string]string{"A2A_METRICS": "statsd"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			config, err := LoadMetricsConfigFromEnv()
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestEMFMetricsFlush(t *testing.T) {
+	metrics := NewEMFMetrics("A2AServerless")
+	metrics.now = func() time.Time { return time.UnixMilli(1700000000000) }
+
+	metrics.RecordRequest("tasks/get")
+	metrics.RecordRequest("tasks/get")
+	metrics.RecordError("tasks/get", -32000)
+	metrics.RecordTaskTransition("", a2a.TaskStateSubmitted)
+	metrics.RecordStorageLatency("GetTask", 1500*time.Microsecond)
+	metrics.RecordStorageLatency("GetTask", 3*time.Millisecond)
+	metrics.RecordNotification(NotificationOutcomeFailed)
+	metrics.RecordInitDuration(InitPhaseConfig, 40*time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := metrics.Flush(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 EMF documents, got %d:\n%s", len(lines), buf.String())
+	}
+
+	docs := make(map[string]map[string]any)
+	for _, line := range lines {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(line), &doc); err != nil {
+			t.Fatalf("invalid EMF line %q: %v", line, err)
+		}
+		directive := doc["_aws"].(map[string]any)
+		if directive["Timestamp"] != float64(1700000000000) {
+			t.Errorf("unexpected timestamp %v", directive["Timestamp"])
+		}
+		metric := directive["CloudWatchMetrics"].([]any)[0].(map[string]any)
+		if metric["Namespace"] != "A2AServerless" {
+			t.Errorf("unexpected namespace %v", metric["Namespace"])
+		}
+		name := metric["Metrics"].([]any)[0].(map[string]any)["Name"].(string)
+		docs[name] = doc
+	}
+
+	if got := docs[MetricRequests][MetricRequests]; got != 2.0 {
+		t.Errorf("expected requests summed to 2, got %v", got)
+	}
+	if got := docs[MetricErrors]["Code"]; got != "-32000" {
+		t.Errorf("expected error code dimension -32000, got %v", got)
+	}
+	if got := docs[MetricTaskTransitions]["FromState"]; got != "none" {
+		t.Errorf("expected FromState none for a new task, got %v", got)
+	}
+	if got := docs[MetricStorageLatency][MetricStorageLatency]; !equalValues(got, []any{1.5, 3.0}) {
+		t.Errorf("expected latencies [1.5 3], got %v", got)
+	}
+	if got := docs[MetricNotificationOutcomes]["Outcome"]; got != NotificationOutcomeFailed {
+		t.Errorf("expected outcome failed, got %v", got)
+	}
+	if got := docs[MetricInitDuration]; got["Phase"] != InitPhaseConfig || got[MetricInitDuration] != 40.0 {
+		t.Errorf("expected config phase of 40ms, got %v", got)
+	}
+
+	// Flushing clears the buffer so the next invocation starts empty
+	buf.Reset()
+	if err := metrics.Flush(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected empty second flush, got %s", buf.String())
+	}
+}
+
+func TestEMFMetricsSplitsLargeSeries(t *testing.T) {
+	metrics := NewEMFMetrics("A2AServerless")
+	for i := 0; i < emfMaxValues+1; i++ {
+		metrics.RecordStorageLatency("SaveTask", time.Millisecond)
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.Flush(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("expected 2 documents for %d values, got %d", emfMaxValues+1, lines)
+	}
+}
+
+func TestMetricsFromContext(t *testing.T) {
+	if _, ok := MetricsFromContext(context.Background()).(NoopMetrics); !ok {
+		t.Error("expected NoopMetrics without metrics in context")
+	}
+
+	metrics := NewEMFMetrics("A2AServerless")
+	ctx := ContextWithMetrics(context.Background(), metrics)
+	if MetricsFromContext(ctx) != metrics {
+		t.Error("expected metrics attached to context")
+	}
+}
+
+func equalValues(got any, expected []any) bool {
+	values, ok := got.([]any)
+	if !ok || len(values) != len(expected) {
+		return false
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrorReport is an internal error or recovered panic with the request it
+// happened in
+type ErrorReport struct {
+	Err error
+	// Panic is the value recovered from a panic, nil for returned errors
+	Panic any
+	// Stack is the goroutine stack at the panic
+	Stack []byte
+	// Fields are the request's log fields, e.g. request_id, method and task_id
+	Fields map[string]string
+}
+
+// ErrorReporter sends internal errors and panics to an error tracker, so
+// failures are alerted on instead of scrolling past in the logs
+type ErrorReporter interface {
+	ReportError(ctx context.Context, report ErrorReport)
+}
+
+// NoopErrorReporter discards every report
+type NoopErrorReporter struct{}
+
+func (NoopErrorReporter) ReportError(context.Context, ErrorReport) {}
+
+type errorReporterContextKey struct{}
+
+// ContextWithErrorReporter returns a context carrying reporter
+func ContextWithErrorReporter(ctx context.Context, reporter ErrorReporter) context.Context {
+	return context.WithValue(ctx, errorReporterContextKey{}, reporter)
+}
+
+// ErrorReporterFromContext returns the reporter in ctx, or NoopErrorReporter when none was attached
+func ErrorReporterFromContext(ctx context.Context) ErrorReporter {
+	if reporter, ok := ctx.Value(errorReporterContextKey{}).(ErrorReporter); ok {
+		return reporter
+	}
+	return NoopErrorReporter{}
+}
+
+// ReportError reports an internal error to the reporter in ctx
+func ReportError(ctx context.Context, err error) {
+	ErrorReporterFromContext(ctx).ReportError(ctx, ErrorReport{
+		Err:    err,
+		Fields: LogFieldsFromContext(ctx),
+	})
+}
+
+// ReportPanic reports a value recovered from a panic, with the stack it
+// unwound from, to the reporter in ctx
+func ReportPanic(ctx context.Context, recovered any, stack []byte) {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	ErrorReporterFromContext(ctx).ReportError(ctx, ErrorReport{
+		Err:    fmt.Errorf("panic: %w", err),
+		Panic:  recovered,
+		Stack:  stack,
+		Fields: LogFieldsFromContext(ctx),
+	})
+}
@@ -0,0 +1,61 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// recordingErrorReporter keeps every report
+type recordingErrorReporter struct {
+	reports []ErrorReport
+}
+
+func (r *recordingErrorReporter) ReportError(ctx context.Context, report ErrorReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestReportErrorCarriesLogFields(t *testing.T) {
+	reporter := &recordingErrorReporter{}
+	ctx := ContextWithErrorReporter(context.Background(), reporter)
+	ctx = WithLogAttrs(ctx, LogKeyRequestID, "req-1", LogKeyMethod, "tasks/get")
+	ctx = WithLogAttrs(ctx, LogKeyTaskID, "task-1")
+
+	ReportError(ctx, errors.New("store unavailable"))
+	ReportPanic(ctx, "nil map", []byte("goroutine 1"))
+
+	if len(reporter.reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reporter.reports))
+	}
+	returned, panicked := reporter.reports[0], reporter.reports[1]
+	if returned.Err.Error() != "store unavailable" || returned.Panic != nil {
+		t.Errorf("unexpected error report: %+v", returned)
+	}
+	if panicked.Err.Error() != "panic: nil map" || panicked.Panic != "nil map" || string(panicked.Stack) != "goroutine 1" {
+		t.Errorf("unexpected panic report: %+v", panicked)
+	}
+	want := map[string]string{LogKeyRequestID: "req-1", LogKeyMethod: "tasks/get", LogKeyTaskID: "task-1"}
+	for key, value := range want {
+		if returned.Fields[key] != value {
+			t.Errorf("expected field %s=%s, got %v", key, value, returned.Fields)
+		}
+	}
+}
+
+func TestReportErrorWithoutReporter(t *testing.T) {
+	// Nothing attached: reports are dropped rather than panicking
+	ReportError(context.Background(), errors.New("ignored"))
+}
+
+func TestLogFieldsFromContextSiblings(t *testing.T) {
+	parent := WithLogAttrs(context.Background(), LogKeyRequestID, "req-1")
+	first := WithLogAttrs(parent, LogKeyTaskID, "task-1")
+	second := WithLogAttrs(parent, LogKeyTaskID, "task-2")
+
+	if got := LogFieldsFromContext(first)[LogKeyTaskID]; got != "task-1" {
+		t.Errorf("expected first context to keep task-1, got %s", got)
+	}
+	if got := LogFieldsFromContext(second)[LogKeyTaskID]; got != "task-2" {
+		t.Errorf("expected second context to keep task-2, got %s", got)
+	}
+}
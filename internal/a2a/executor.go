@@ -0,0 +1,316 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// WithExecutor has the handler run executor on every message/send. An
+// executor that answers a message outside any task with a Message as its
+// first event replies directly, and no task is stored. Otherwise the events
+// it writes are applied to the task before the task is saved. Without an
+// executor a message leaves its task working for another function to pick up.
+func WithExecutor(executor a2asrv.AgentExecutor) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.executor = executor
+	}
+}
+
+// ErrTaskCanceled is the cause of an executor's context once its task is
+// canceled while it runs, and what writing to its queue returns from then
+// on. Executors stop work by watching ctx.Done(), or at their next write.
+var ErrTaskCanceled = errors.New("task canceled")
+
+// DefaultCancelPollInterval is how often the task of a running executor is
+// read to see whether it was canceled
+const DefaultCancelPollInterval = time.Second
+
+// WithCancelPolling sets how often the task of a running executor is read to
+// see whether tasks/cancel was called on it, from this or any other
+// instance. Zero turns polling off; the task is still read once before the
+// executor's events are saved, so a canceled task is never overwritten.
+func WithCancelPolling(interval time.Duration) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.cancelPoll = interval
+	}
+}
+
+// ChunkMetadataKey is the artifact-update event metadata entry numbering
+// the chunks of a streamed artifact in the order they were written. The
+// assembled artifact is stored as its last chunk.
+const ChunkMetadataKey = "chunk"
+
+// eventCollector is the EventWriter an executor writes to during
+// message/send. The events are applied once Execute returns, except for
+// artifact chunks, which are stored as they are written.
+type eventCollector struct {
+	// ctx is the execution's, canceled with ErrTaskCanceled
+	ctx    context.Context
+	events []a2a.Event
+	// stream stores an artifact chunk
+	stream func(ctx context.Context, chunk a2a.TaskArtifactUpdateEvent)
+	// artifacts are those being streamed, as assembled so far
+	artifacts map[string]*streamedArtifact
+}
+
+// streamedArtifact is an artifact assembled from its chunks, and the number
+// of its next chunk
+type streamedArtifact struct {
+	artifact a2a.Artifact
+	next     int
+}
+
+func (c *eventCollector) Write(ctx context.Context, event a2a.Event) error {
+	if errors.Is(context.Cause(c.ctx), ErrTaskCanceled) {
+		return ErrTaskCanceled
+	}
+	if e, ok := event.(a2a.TaskArtifactUpdateEvent); ok && isChunk(e) {
+		c.writeChunk(ctx, e)
+		return nil
+	}
+	c.events = append(c.events, event)
+	return nil
+}
+
+// isChunk reports whether an artifact update is part of a streamed artifact:
+// appended to one, or the start of one with more to come
+func isChunk(e a2a.TaskArtifactUpdateEvent) bool {
+	return e.Append != nil && *e.Append || e.LastChunk != nil && !*e.LastChunk
+}
+
+// writeChunk stores a chunk and adds it to its artifact. On the last chunk
+// the assembled artifact is collected, to replace the chunks on the task.
+func (c *eventCollector) writeChunk(ctx context.Context, chunk a2a.TaskArtifactUpdateEvent) {
+	if c.artifacts == nil {
+		c.artifacts = make(map[string]*streamedArtifact)
+	}
+	streamed := c.artifacts[chunk.Artifact.ArtifactID]
+	if streamed == nil || chunk.Append == nil || !*chunk.Append {
+		streamed = &streamedArtifact{artifact: chunk.Artifact, next: nextChunk(streamed)}
+		streamed.artifact.Parts = slices.Clone(chunk.Artifact.Parts)
+		c.artifacts[chunk.Artifact.ArtifactID] = streamed
+	} else {
+		streamed.artifact.Parts = append(streamed.artifact.Parts, chunk.Artifact.Parts...)
+	}
+	chunk.Metadata = maps.Clone(chunk.Metadata)
+	if chunk.Metadata == nil {
+		chunk.Metadata = make(map[string]any)
+	}
+	chunk.Metadata[ChunkMetadataKey] = streamed.next
+	streamed.next++
+	c.stream(ctx, chunk)
+
+	if chunk.LastChunk != nil && *chunk.LastChunk {
+		c.assemble(chunk.Artifact.ArtifactID, true)
+	}
+}
+
+// nextChunk continues the numbering of an artifact restarted mid-stream
+func nextChunk(streamed *streamedArtifact) int {
+	if streamed == nil {
+		return 0
+	}
+	return streamed.next
+}
+
+// assemble collects the artifact streamed so far as one update
+func (c *eventCollector) assemble(artifactID string, last bool) {
+	streamed := c.artifacts[artifactID]
+	delete(c.artifacts, artifactID)
+	c.events = append(c.events, a2a.TaskArtifactUpdateEvent{
+		Artifact:  streamed.artifact,
+		LastChunk: &last,
+		Metadata:  map[string]any{ChunkMetadataKey: streamed.next},
+	})
+}
+
+// finish collects the artifacts whose last chunk never came, so the task
+// keeps what they streamed
+func (c *eventCollector) finish() {
+	for _, artifactID := range slices.Sorted(maps.Keys(c.artifacts)) {
+		c.assemble(artifactID, false)
+	}
+}
+
+// execute runs the executor on a message. A direct reply is returned as a
+// Message; otherwise the events the executor wrote are returned to be
+// applied to the task. An existing task canceled before the executor's
+// events are saved returns ErrTaskCanceled, and the events are dropped. New
+// tasks are not stored until the call ends, so they cannot be canceled.
+func (h *ServerlessA2AHandler) execute(ctx context.Context, params a2a.MessageSendParams, task a2a.Task) (*a2a.Message, []a2a.Event, error) {
+	reqCtx := a2asrv.RequestContext{
+		Request:   params,
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+	}
+	if params.Message.TaskID != nil {
+		reqCtx.Task = &task
+	}
+
+	execCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	existing := params.Message.TaskID != nil
+	if existing && h.cancelPoll > 0 {
+		stop := h.watchCancel(execCtx, task.ID, cancel)
+		defer stop()
+	}
+
+	queue := &eventCollector{ctx: execCtx, stream: func(ctx context.Context, chunk a2a.TaskArtifactUpdateEvent) {
+		chunk.Kind, chunk.TaskID, chunk.ContextID = "artifact-update", task.ID, task.ContextID
+		// A lost chunk is made up for by the assembled artifact, which is
+		// also the one pushed to webhooks
+		if err := h.eventStore.SaveEvent(ctx, chunk); err != nil {
+			LoggerFromContext(ctx).Warn("failed to save artifact chunk", LogKeyError, err)
+		}
+	}}
+	err := h.executor.Execute(execCtx, reqCtx, queue)
+	queue.finish()
+	// Checked before the error, which is likely the cancellation's own
+	if errors.Is(context.Cause(execCtx), ErrTaskCanceled) || existing && h.storedCanceled(ctx, task.ID) {
+		return nil, nil, ErrTaskCanceled
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("agent failed on task %s: %w", task.ID, err)
+	}
+	if len(queue.events) > 0 && params.Message.TaskID == nil {
+		if reply, ok := queue.events[0].(a2a.Message); ok {
+			reply.Kind = "message"
+			if reply.ContextID == nil {
+				reply.ContextID = params.Message.ContextID
+			}
+			return &reply, nil, nil
+		}
+	}
+	return nil, queue.events, nil
+}
+
+// watchCancel reads the task every cancelPoll until stop is called, and
+// cancels the execution with ErrTaskCanceled once the task is canceled
+func (h *ServerlessA2AHandler) watchCancel(ctx context.Context, taskID a2a.TaskID, cancel context.CancelCauseFunc) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(h.cancelPoll)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if h.storedCanceled(ctx, taskID) {
+				cancel(ErrTaskCanceled)
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// storedCanceled reports whether the stored task is canceled. A failed read
+// is logged and taken as not canceled, so a store hiccup does not stop work.
+func (h *ServerlessA2AHandler) storedCanceled(ctx context.Context, taskID a2a.TaskID) bool {
+	task, err := h.taskStore.GetTask(ctx, taskID)
+	if err != nil {
+		if ctx.Err() == nil {
+			LoggerFromContext(ctx).Warn("failed to check task for cancellation", LogKeyError, err)
+		}
+		return false
+	}
+	return task.Status.State == a2a.TaskStateCanceled
+}
+
+// applyEvent records an executor's event on the task, returning the event
+// stamped with the task's identifiers for the event store
+func applyEvent(task *a2a.Task, event a2a.Event, now time.Time) a2a.Event {
+	switch e := event.(type) {
+	case a2a.Message:
+		e.Kind = "message"
+		if e.TaskID == nil {
+			e.TaskID = &task.ID
+		}
+		if e.ContextID == nil {
+			e.ContextID = &task.ContextID
+		}
+		task.History = append(task.History, e)
+		return e
+	case a2a.TaskStatusUpdateEvent:
+		e.Kind, e.TaskID, e.ContextID = "status-update", task.ID, task.ContextID
+		if e.Status.Timestamp == nil {
+			e.Status.Timestamp = &now
+		}
+		task.Status = e.Status
+		return e
+	case a2a.TaskArtifactUpdateEvent:
+		e.Kind, e.TaskID, e.ContextID = "artifact-update", task.ID, task.ContextID
+		for i, artifact := range task.Artifacts {
+			if artifact.ArtifactID == e.Artifact.ArtifactID {
+				if e.Append != nil && *e.Append {
+					task.Artifacts[i].Parts = append(task.Artifacts[i].Parts, e.Artifact.Parts...)
+				} else {
+					task.Artifacts[i] = e.Artifact
+				}
+				return e
+			}
+		}
+		task.Artifacts = append(task.Artifacts, e.Artifact)
+		return e
+	}
+	return event
+}
+
+// artifactChunk returns the chunk number of a streamed artifact's update,
+// false for an artifact sent whole. A number read back from JSON is a
+// float64.
+func artifactChunk(e a2a.TaskArtifactUpdateEvent) (int, bool) {
+	switch chunk := e.Metadata[ChunkMetadataKey].(type) {
+	case int:
+		return chunk, true
+	case float64:
+		return int(chunk), true
+	}
+	return 0, false
+}
+
+// orderChunks puts the chunks of each streamed artifact in order, in the
+// places its chunks hold among the events. Stores need not return events in
+// the order they were saved.
+func orderChunks(events []a2a.Event) {
+	places := make(map[string][]int)
+	for i, event := range events {
+		if e, ok := event.(a2a.TaskArtifactUpdateEvent); ok {
+			if _, ok := artifactChunk(e); ok {
+				places[e.Artifact.ArtifactID] = append(places[e.Artifact.ArtifactID], i)
+			}
+		}
+	}
+	for _, indexes := range places {
+		chunks := make([]a2a.TaskArtifactUpdateEvent, len(indexes))
+		for i, index := range indexes {
+			chunks[i] = events[index].(a2a.TaskArtifactUpdateEvent)
+		}
+		slices.SortStableFunc(chunks, func(a, b a2a.TaskArtifactUpdateEvent) int {
+			x, _ := artifactChunk(a)
+			y, _ := artifactChunk(b)
+			return x - y
+		})
+		for i, index := range indexes {
+			events[index] = chunks[i]
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// eventLog is an EventStore keeping every event in the order saved
+type eventLog struct {
+	events []a2a.Event
+}
+
+func (s *eventLog) SaveEvent(ctx context.Context, event a2a.Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *eventLog) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	return s.events, nil
+}
+
+func (s *eventLog) MarkEventProcessed(ctx context.Context, eventID string) error {
+	return nil
+}
+
+// streamingExecutor writes an artifact a word at a time, recording how many
+// events were stored before each write
+type streamingExecutor struct {
+	words  []string
+	last   bool
+	events *eventLog
+	seen   []int
+}
+
+func (e *streamingExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	for i, word := range e.words {
+		e.seen = append(e.seen, len(e.events.events))
+		appended, last := i > 0, e.last && i == len(e.words)-1
+		chunk := a2a.TaskArtifactUpdateEvent{
+			Artifact:  a2a.Artifact{ArtifactID: "answer", Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: word}}},
+			Append:    &appended,
+			LastChunk: &last,
+		}
+		if err := queue.Write(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *streamingExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func artifactText(artifact a2a.Artifact) string {
+	var text string
+	for _, part := range artifact.Parts {
+		text += part.(a2a.TextPart).Text
+	}
+	return text
+}
+
+func TestExecutorStreamsArtifactChunks(t *testing.T) {
+	ctx := context.Background()
+	events := &eventLog{}
+	executor := &streamingExecutor{words: []string{"one ", "two ", "three"}, last: true, events: events}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, &memoryStores{}, events, nil, WithExecutor(executor))
+
+	result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+
+	// Each chunk is stored before the next is written
+	if len(executor.seen) != 3 || executor.seen[1] != 1 || executor.seen[2] != 2 {
+		t.Errorf("expected each chunk stored as written, got %v", executor.seen)
+	}
+	if len(task.Artifacts) != 1 || artifactText(task.Artifacts[0]) != "one two three" {
+		t.Fatalf("expected the assembled artifact on the task, got %+v", task.Artifacts)
+	}
+
+	var chunks []a2a.TaskArtifactUpdateEvent
+	for _, event := range events.events {
+		if e, ok := event.(a2a.TaskArtifactUpdateEvent); ok {
+			chunks = append(chunks, e)
+		}
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("expected three chunks and the assembled artifact, got %+v", chunks)
+	}
+	for i, chunk := range chunks {
+		if n, ok := artifactChunk(chunk); !ok || n != i || chunk.TaskID != task.ID || chunk.ContextID != task.ContextID {
+			t.Errorf("expected chunk %d of the task, got %+v", i, chunk)
+		}
+	}
+	final := chunks[3]
+	if (final.Append != nil && *final.Append) || final.LastChunk == nil || !*final.LastChunk || artifactText(final.Artifact) != "one two three" {
+		t.Errorf("expected the assembled artifact as the last chunk, got %+v", final)
+	}
+
+	// Resubscribing replays the chunks in order, whatever order they are read in
+	events.events[0], events.events[2] = events.events[2], events.events[0]
+	var replayed []int
+	for event, err := range h.OnResubscribeToTask(ctx, a2a.TaskIDParams{ID: task.ID}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e, ok := event.(a2a.TaskArtifactUpdateEvent); ok {
+			n, _ := artifactChunk(e)
+			replayed = append(replayed, n)
+		}
+	}
+	if len(replayed) != 4 || replayed[0] != 0 || replayed[1] != 1 || replayed[2] != 2 || replayed[3] != 3 {
+		t.Errorf("expected the chunks replayed in order, got %v", replayed)
+	}
+}
+
+func TestExecutorKeepsUnfinishedArtifact(t *testing.T) {
+	ctx := context.Background()
+	events := &eventLog{}
+	executor := &streamingExecutor{words: []string{"one ", "two"}, events: events}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, &memoryStores{}, events, nil, WithExecutor(executor))
+
+	result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	if len(task.Artifacts) != 1 || artifactText(task.Artifacts[0]) != "one two" {
+		t.Errorf("expected the streamed output kept on the task, got %+v", task.Artifacts)
+	}
+}
+
+// transitionStore keeps the latest copy of each task and refuses to move a
+// finished one to another state, as the AWS store does. afterGet runs once,
+// after the next read, standing in for another instance's write landing
+// between a read and the save that follows it.
+type transitionStore struct {
+	eventLog
+	tasks    map[a2a.TaskID]a2a.Task
+	afterGet func()
+}
+
+func (s *transitionStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return a2a.Task{}, a2a.ErrTaskNotFound
+	}
+	if after := s.afterGet; after != nil {
+		s.afterGet = nil
+		after()
+	}
+	return task, nil
+}
+
+func (s *transitionStore) SaveTask(ctx context.Context, task a2a.Task) error {
+	if stored, ok := s.tasks[task.ID]; ok {
+		if err := CheckTaskSave(stored, task); err != nil {
+			return err
+		}
+	}
+	s.tasks[task.ID] = task
+	return nil
+}
+
+func (s *transitionStore) DeleteTask(ctx context.Context, taskID a2a.TaskID) error {
+	delete(s.tasks, taskID)
+	return nil
+}
+
+func (s *transitionStore) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	return nil, nil
+}
+
+func TestTransitionsLosingToAFinish(t *testing.T) {
+	ctx := context.Background()
+	working := a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}
+	finish := func(store *transitionStore, state a2a.TaskState) func() {
+		return func() {
+			finished := store.tasks["task-1"]
+			finished.Status = a2a.TaskStatus{State: state}
+			store.tasks["task-1"] = finished
+		}
+	}
+
+	// A cancel landing after the executor's last cancellation check stands,
+	// and the sender is told its message was not taken
+	store := &transitionStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": working}}
+	executor := &failingExecutor{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, store, store, nil, WithExecutor(executor))
+	store.afterGet = func() { store.afterGet = finish(store, a2a.TaskStateCanceled) }
+	message := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser, TaskID: &working.ID}}
+	_, err := h.OnSendMessage(ctx, message)
+	if !errors.Is(err, ErrTaskNotResumable) || store.tasks["task-1"].Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected the cancel to stand and the message refused, got %s: %v", store.tasks["task-1"].Status.State, err)
+	}
+	if code, _, ok := A2AErrorCode(err); !ok || code != JSONRPCErrorInvalidParams {
+		t.Errorf("expected an invalid params error for the client, got %d", code)
+	}
+
+	// A message to a task already finished is refused without executing
+	message.Message.MessageID = "m-2"
+	if _, err := h.OnSendMessage(ctx, message); !errors.Is(err, ErrTaskNotResumable) || executor.calls != 1 {
+		t.Errorf("expected the message refused without executing, got %v after %d executions", err, executor.calls)
+	}
+
+	// A cancel losing to a completion fails as one of a completed task does
+	store = &transitionStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": working}}
+	h = NewServerlessA2AHandler(ServerlessConfig{}, store, store, nil)
+	store.afterGet = finish(store, a2a.TaskStateCompleted)
+	if _, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: "task-1"}); !errors.Is(err, a2a.ErrTaskNotCancelable) || store.tasks["task-1"].Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected the completion to stand, got %s: %v", store.tasks["task-1"].Status.State, err)
+	}
+
+	// Two cancels both succeed
+	store = &transitionStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": working}}
+	h = NewServerlessA2AHandler(ServerlessConfig{}, store, store, nil)
+	store.afterGet = finish(store, a2a.TaskStateCanceled)
+	if task, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: "task-1"}); err != nil || task.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected the task canceled, got %s: %v", task.Status.State, err)
+	}
+}
@@ -0,0 +1,129 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// ErrTaskNotRunning is returned by a heartbeat for a task that is not
+// waiting on the agent: finished, timed out, or waiting on the client
+var ErrTaskNotRunning = errors.New("task is not running")
+
+// TaskHeartbeatStore is implemented by task stores that can store a
+// heartbeat without rewriting the rest of the task
+type TaskHeartbeatStore interface {
+	// SaveTaskHeartbeat stores the last-seen time and deadline in the task's
+	// metadata, and nothing else of it, only while the stored task is
+	// submitted or working. Otherwise it fails with ErrTaskNotRunning.
+	SaveTaskHeartbeat(ctx context.Context, task a2a.Task) error
+}
+
+// UpdateTaskHeartbeat records that work on a submitted or working task is
+// still alive, for executors and workers that run longer than one call. Its
+// last-seen time becomes now and its deadline, if it has one, moves to the
+// task's timeout after that, so slow work is not timed out while dead work
+// still is. A progress message, when given, becomes the task's status
+// message and is stored as a status update for streaming clients.
+//
+// A heartbeat for a canceled task returns ErrTaskCanceled, so the caller
+// can stop; one for any other task not waiting on the agent returns
+// ErrTaskNotRunning. Both return the task as it is. Without progress, a
+// store implementing TaskHeartbeatStore only has the heartbeat written, so
+// a cancel landing meanwhile is never undone.
+func (h *ServerlessA2AHandler) UpdateTaskHeartbeat(ctx context.Context, taskID a2a.TaskID, progress *a2a.Message) (a2a.Task, error) {
+	task, err := h.taskStore.GetTask(ctx, taskID)
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	// A task already past its deadline is not revived
+	if task, err = h.timeOut(ctx, task); err != nil {
+		return a2a.Task{}, err
+	}
+	if err := heartbeatRefused(task); err != nil {
+		return task, err
+	}
+
+	now := h.clock.Now()
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]any)
+	}
+	if deadline, ok := taskDeadline(task); ok {
+		// The deadline keeps its distance from the last sign of life
+		lastSeen, ok := metadataTime(task, TaskLastSeenMetadataKey)
+		if timeout := deadline.Sub(lastSeen); ok && timeout > 0 {
+			task.Metadata[TaskDeadlineMetadataKey] = now.Add(timeout).UTC().Format(time.RFC3339)
+		}
+	}
+	task.Metadata[TaskLastSeenMetadataKey] = now.UTC().Format(time.RFC3339)
+
+	if store, ok := h.taskStore.(TaskHeartbeatStore); ok && progress == nil {
+		if err := store.SaveTaskHeartbeat(ctx, task); errors.Is(err, ErrTaskNotRunning) {
+			return h.lostHeartbeat(ctx, taskID)
+		} else if err != nil {
+			return a2a.Task{}, fmt.Errorf("failed to save heartbeat of task %s: %w", taskID, err)
+		}
+		return task, nil
+	}
+
+	if progress != nil {
+		message := *progress
+		message.Kind, message.TaskID, message.ContextID = "message", &task.ID, &task.ContextID
+		if message.MessageID == "" {
+			message.MessageID = h.ids.NewID()
+		}
+		if message.Role == "" {
+			message.Role = a2a.MessageRoleAgent
+		}
+		task.Status = a2a.TaskStatus{State: task.Status.State, Message: &message, Timestamp: &now}
+	}
+	if err := h.taskStore.SaveTask(ctx, task); errors.Is(err, ErrTaskFinished) {
+		return h.lostHeartbeat(ctx, taskID)
+	} else if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to save heartbeat of task %s: %w", taskID, err)
+	}
+
+	if progress != nil {
+		saved := 1
+		if err := h.saveEvent(ctx, a2a.TaskStatusUpdateEvent{
+			Kind:      "status-update",
+			TaskID:    task.ID,
+			ContextID: task.ContextID,
+			Status:    task.Status,
+		}); err != nil {
+			// Log error but don't fail the request
+			LoggerFromContext(ctx).Warn("failed to save status event", LogKeyTaskID, task.ID, LogKeyError, err)
+			saved = 0
+		}
+		h.recordStats(ctx, task.Status.State, task, saved)
+	}
+	return task, nil
+}
+
+// heartbeatRefused returns the error a heartbeat for task gets, nil while
+// the task waits on the agent
+func heartbeatRefused(task a2a.Task) error {
+	switch {
+	case task.Status.State == a2a.TaskStateCanceled:
+		return fmt.Errorf("task %s: %w", task.ID, ErrTaskCanceled)
+	case !awaitsAgent(task.Status.State):
+		return fmt.Errorf("task %s is %s: %w", task.ID, task.Status.State, ErrTaskNotRunning)
+	}
+	return nil
+}
+
+// lostHeartbeat re-reads a task whose state changed while a heartbeat was
+// being stored, returning it with the error the heartbeat gets
+func (h *ServerlessA2AHandler) lostHeartbeat(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	task, err := h.taskStore.GetTask(ctx, taskID)
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	if err := heartbeatRefused(task); err != nil {
+		return task, err
+	}
+	return task, fmt.Errorf("task %s: %w", taskID, ErrStaleWrite)
+}
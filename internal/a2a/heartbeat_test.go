@@ -0,0 +1,118 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestUpdateTaskHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	at := func(stores *memoryStores, offset time.Duration) *ServerlessA2AHandler {
+		return NewServerlessA2AHandler(ServerlessConfig{TaskTimeout: "15m"}, stores, stores, nil, WithClock(fixedClock{now: now.Add(offset)}))
+	}
+	// latest keeps only the last saved copy of each task, as a table would
+	latest := func(stores *memoryStores) {
+		stores.tasks = stores.tasks[len(stores.tasks)-1:]
+	}
+
+	stores := &memoryStores{}
+	result, err := at(stores, 0).OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sent := result.(a2a.Task)
+	if lastSeen := sent.Metadata[TaskLastSeenMetadataKey]; lastSeen != "2025-08-01T12:00:00Z" {
+		t.Fatalf("expected the task last seen when sent, got %v", lastSeen)
+	}
+
+	// A heartbeat moves the deadline on and attaches its progress
+	progress := &a2a.Message{Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "halfway"}}}
+	task, err := at(stores, 10*time.Minute).UpdateTaskHeartbeat(ctx, sent.ID, progress)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Metadata[TaskLastSeenMetadataKey] != "2025-08-01T12:10:00Z" || task.Metadata[TaskDeadlineMetadataKey] != "2025-08-01T12:25:00Z" {
+		t.Errorf("expected the task last seen at 12:10 with a deadline at 12:25, got %v", task.Metadata)
+	}
+	if task.Status.State != a2a.TaskStateWorking || task.Status.Message == nil || task.Status.Message.Role != a2a.MessageRoleAgent || task.Status.Message.MessageID == "" {
+		t.Errorf("expected the progress as the working status's agent message, got %+v", task.Status)
+	}
+	if len(stores.events) != 1 || stores.events[0].Final || stores.events[0].Status.Message == nil {
+		t.Errorf("expected one progress event, got %+v", stores.events)
+	}
+	latest(stores)
+
+	// Past the first deadline the task is still alive, until the new one
+	if task, err := at(stores, 20*time.Minute).OnGetTask(ctx, a2a.TaskQueryParams{ID: sent.ID}); err != nil || task.Status.State != a2a.TaskStateWorking {
+		t.Fatalf("expected the task working after its heartbeat, got %s: %v", task.Status.State, err)
+	}
+	task, err = at(stores, 26*time.Minute).UpdateTaskHeartbeat(ctx, sent.ID, nil)
+	if !errors.Is(err, ErrTaskNotRunning) || task.Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected a heartbeat past the deadline to find the task failed, got %s: %v", task.Status.State, err)
+	}
+
+	// A heartbeat for a canceled task tells its worker to stop
+	stores = &memoryStores{}
+	h := at(stores, 0)
+	result, _ = h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if _, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: result.(a2a.Task).ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	latest(stores)
+	if _, err := h.UpdateTaskHeartbeat(ctx, result.(a2a.Task).ID, nil); !errors.Is(err, ErrTaskCanceled) {
+		t.Errorf("expected ErrTaskCanceled, got %v", err)
+	}
+	if _, err := h.UpdateTaskHeartbeat(ctx, "missing", nil); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestAWSTaskStoreHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	client, requests := recordingDynamoDB(t)
+	task := a2a.Task{ID: "task-1", Metadata: map[string]any{TaskLastSeenMetadataKey: "2025-08-01T12:10:00Z", TaskDeadlineMetadataKey: "2025-08-01T12:25:00Z"}}
+	if err := NewAWSTaskStore(client, "tasks", "billing#").SaveTaskHeartbeat(ctx, task); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := (*requests)[0]
+	if attributeS(update, "Key", "task_id") != "billing#task-1" || update["UpdateExpression"] != "SET #last_seen = :last_seen, #deadline = :deadline" {
+		t.Errorf("expected only the heartbeat attributes updated, got %v", update)
+	}
+	if update["ConditionExpression"] != "attribute_exists(task_id) AND #status IN (:submitted, :working)" || attributeS(update, "ExpressionAttributeValues", ":deadline") != "2025-08-01T12:25:00Z" {
+		t.Errorf("expected the update conditional on the task running, got %v", update)
+	}
+
+	// A task finished first is left as it is, and reads back its heartbeat
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if strings.HasSuffix(r.Header.Get("X-Amz-Target"), "UpdateItem") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+			return
+		}
+		w.Write([]byte(`{"Item":{"task_id":{"S":"task-1"},"status":{"S":"working"},"last_seen":{"S":"2025-08-01T12:10:00Z"},"task_data":{"S":"{\"id\":\"task-1\",\"metadata\":{\"last_seen\":\"2025-08-01T12:00:00Z\"}}"}}}`))
+	}))
+	defer server.Close()
+	store := NewAWSTaskStore(dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	}), "tasks", "")
+	if err := store.SaveTaskHeartbeat(ctx, task); !errors.Is(err, ErrTaskNotRunning) {
+		t.Errorf("expected ErrTaskNotRunning, got %v", err)
+	}
+	if stored, err := store.GetTask(ctx, "task-1"); err != nil || stored.Metadata[TaskLastSeenMetadataKey] != "2025-08-01T12:10:00Z" {
+		t.Errorf("expected the heartbeat over the task data's, got %v: %v", stored.Metadata, err)
+	}
+}
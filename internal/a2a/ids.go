@@ -0,0 +1,171 @@
+package a2a
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/uuid"
+)
+
+// Clock tells the handler and stores the time, so tests can fix it
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+type SystemClock struct{}
+
+// Now implements Clock
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// IDGenerator creates the task, context and event IDs the handler and stores
+// assign
+type IDGenerator interface {
+	NewID() string
+}
+
+// UUIDv7Generator creates RFC 9562 version 7 UUIDs. They start with the
+// millisecond they were created in, so IDs sort by creation time, and the
+// rest is random, so concurrent invocations cannot collide. IDs from one
+// generator are strictly increasing even within a millisecond.
+type UUIDv7Generator struct {
+	clock Clock
+
+	mu     sync.Mutex
+	lastMS int64
+	seq    uint16
+}
+
+// NewUUIDv7Generator creates a generator reading the time from clock
+func NewUUIDv7Generator(clock Clock) *UUIDv7Generator {
+	return &UUIDv7Generator{clock: clock}
+}
+
+// NewID implements IDGenerator
+func (g *UUIDv7Generator) NewID() string {
+	var id uuid.UUID
+	rand.Read(id[6:])
+
+	g.mu.Lock()
+	ms := g.clock.Now().UnixMilli()
+	if ms > g.lastMS {
+		g.lastMS = ms
+		// Start low in the 12-bit counter so a burst has room to count up
+		g.seq = binary.BigEndian.Uint16(id[6:8]) & 0x1ff
+	} else {
+		// Same millisecond, or the clock went back: keep counting from the last ID
+		g.seq++
+		if g.seq > 0xfff {
+			g.lastMS++
+			g.seq = 0
+		}
+	}
+	ms, seq := g.lastMS, g.seq
+	g.mu.Unlock()
+
+	// 48-bit big-endian timestamp, then version 7 and the 12-bit counter
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	id[6] = 0x70 | byte(seq>>8)
+	id[7] = byte(seq)
+	id[8] = 0x80 | id[8]&0x3f
+	return id.String()
+}
+
+// idTime returns the millisecond a UUIDv7 ID was created in, or false for
+// IDs of another kind, such as those of a custom IDGenerator
+func idTime(id string) (time.Time, bool) {
+	parsed, err := uuid.Parse(id)
+	if err != nil || parsed.Version() != 7 {
+		return time.Time{}, false
+	}
+	var ms int64
+	for _, b := range parsed[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms), true
+}
+
+// RuntimeOption sets the clock or ID generator of the handler or the stores
+type RuntimeOption func(*runtimeDeps)
+
+// WithClock sets the clock. The default is SystemClock.
+func WithClock(clock Clock) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.clock = clock
+	}
+}
+
+// WithIDGenerator sets the ID generator. The default is a UUIDv7Generator on
+// the configured clock.
+func WithIDGenerator(ids IDGenerator) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.ids = ids
+	}
+}
+
+// runtimeDeps are the sources of time and identity shared by the handler and
+// the stores, the executor only the handler uses, and the event store's read
+// budget and the DynamoDB stores' compression threshold
+type runtimeDeps struct {
+	clock    Clock
+	ids      IDGenerator
+	executor a2asrv.AgentExecutor
+	// eventReadBudget bounds the event data AWSEventStore.GetEvents reads
+	eventReadBudget int
+	// compressAbove is the JSON length over which stored data is gzipped
+	compressAbove int
+	// region tags stored items for multi-region deployments
+	region string
+	// retention sets when stored tasks and events expire
+	retention RetentionPolicy
+	// stats counts task transitions and events for admin/stats/get
+	stats StatsStore
+	// cancelPoll is how often a running executor's task is checked for
+	// cancellation
+	cancelPoll time.Duration
+	// retryQueue takes messages whose execution failed transiently, to be
+	// executed again after a delay
+	retryQueue MessageQueue
+	// workQueue takes messages for workers to execute, when the handler
+	// has no executor
+	workQueue MessageQueue
+	// scheduler queues messages starting beyond the work queue's delay
+	scheduler MessageScheduler
+	// contextLock serializes executions within a context, waiting up to
+	// contextLockWait for it
+	contextLock     ContextLock
+	contextLockWait time.Duration
+	// dedup remembers the messages handled for dedupWindow
+	dedup       MessageDeduplicator
+	dedupWindow time.Duration
+	// skillProviders define skills to publish on the agent card, besides
+	// an executor that does
+	skillProviders []SkillProvider
+	// sweep lists the tasks maintenance passes check for missed deadlines
+	sweep RunningTaskLister
+	// pushConfigs keeps the tasks' push notification configs
+	pushConfigs PushConfigStore
+	// taskLister enumerates every task for admin/tasks/list
+	taskLister TaskLister
+}
+
+func newRuntimeDeps(opts []RuntimeOption) runtimeDeps {
+	deps := runtimeDeps{clock: SystemClock{}, cancelPoll: DefaultCancelPollInterval}
+	for _, opt := range opts {
+		opt(&deps)
+	}
+	if deps.ids == nil {
+		deps.ids = NewUUIDv7Generator(deps.clock)
+	}
+	return deps
+}
@@ -0,0 +1,114 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/google/uuid"
+)
+
+// fixedClock always returns the same time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+// sequenceIDs returns id-1, id-2, ...
+type sequenceIDs struct {
+	n int
+}
+
+func (s *sequenceIDs) NewID() string {
+	s.n++
+	return fmt.Sprintf("id-%d", s.n)
+}
+
+func TestUUIDv7Generator(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	ids := NewUUIDv7Generator(fixedClock{now: now})
+
+	// More IDs than the counter holds in one millisecond
+	var previous string
+	for i := range 5000 {
+		id := ids.NewID()
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			t.Fatalf("invalid UUID %q: %v", id, err)
+		}
+		if parsed.Version() != 7 || parsed.Variant() != uuid.RFC4122 {
+			t.Fatalf("expected a version 7 RFC 9562 UUID, got version %d variant %s", parsed.Version(), parsed.Variant())
+		}
+		if i == 0 {
+			if got := time.Unix(parsed.Time().UnixTime()); !got.Equal(now) {
+				t.Errorf("expected the clock's time in the ID, got %s", got)
+			}
+		}
+		if id <= previous {
+			t.Fatalf("expected increasing IDs, got %s after %s", id, previous)
+		}
+		previous = id
+	}
+}
+
+func TestUUIDv7GeneratorConcurrent(t *testing.T) {
+	ids := NewUUIDv7Generator(SystemClock{})
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				id := ids.NewID()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHandlerUsesClockAndIDs(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	client, requests := recordingDynamoDB(t)
+	opts := []RuntimeOption{WithClock(fixedClock{now: now}), WithIDGenerator(&sequenceIDs{})}
+	events := NewAWSEventStore(client, "events", "", opts...)
+	h := NewServerlessA2AHandler(ServerlessConfig{}, NewAWSTaskStore(client, "tasks", ""), events, nil, opts...)
+
+	result, err := h.OnSendMessage(context.Background(), a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	if task.ID != "id-1" || task.ContextID != "id-2" || !task.Status.Timestamp.Equal(now) {
+		t.Errorf("expected IDs and time from the options, got %s %s %v", task.ID, task.ContextID, task.Status.Timestamp)
+	}
+
+	// A status update with a timestamp is keyed by it, so a retried save
+	// overwrites; one without gets a new ID rather than the clock's time
+	if err := events.SaveEvent(context.Background(), a2a.TaskStatusUpdateEvent{TaskID: "task-1", Status: a2a.TaskStatus{Timestamp: &now}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := fmt.Sprintf("status_task-1_%d", now.UnixNano())
+	if got := attributeS((*requests)[len(*requests)-1], "Item", "event_id"); got != want {
+		t.Errorf("expected event ID %s, got %s", want, got)
+	}
+	for _, want := range []string{"status_task-1_id-3", "status_task-1_id-4"} {
+		if err := events.SaveEvent(context.Background(), a2a.TaskStatusUpdateEvent{TaskID: "task-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := attributeS((*requests)[len(*requests)-1], "Item", "event_id"); got != want {
+			t.Errorf("expected event ID %s, got %s", want, got)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package a2a
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"sort"
+
+	"github.com/a2aproject/a2a-go/a2a"
 )
 
 // JSON-RPC 2.0 error codes as defined in the specification
@@ -17,8 +22,109 @@ const (
 	
 	// Server error range: -32000 to -32099
 	JSONRPCErrorServerError = -32000 // Generic server error
+
+	// A2A error codes, from the server error range
+	JSONRPCErrorTaskNotFound                 = -32001 // The task does not exist
+	JSONRPCErrorTaskNotCancelable            = -32002 // The task is in a terminal state
+	JSONRPCErrorPushNotificationNotSupported = -32003 // The agent does not support push notifications
+	JSONRPCErrorUnsupportedOperation         = -32004 // The operation is not supported
+	JSONRPCErrorContentTypeNotSupported      = -32005 // Incompatible content types
+	JSONRPCErrorInvalidAgentResponse         = -32006 // The agent returned an invalid response
+	JSONRPCErrorVersionNotSupported          = -32009 // The requested protocol version is not served
+	JSONRPCErrorQuotaExceeded                = -32010 // The call would pass a daily or monthly quota
 )
 
+// a2aErrors maps the SDK's sentinel errors to their A2A codes and messages
+var a2aErrors = []struct {
+	err     error
+	code    int
+	message string
+}{
+	{a2a.ErrTaskNotFound, JSONRPCErrorTaskNotFound, "Task not found"},
+	{a2a.ErrTaskNotCancelable, JSONRPCErrorTaskNotCancelable, "Task cannot be canceled"},
+	{a2a.ErrPushNotificationNotSupported, JSONRPCErrorPushNotificationNotSupported, "Push Notification is not supported"},
+	{a2a.ErrUnsupportedOperation, JSONRPCErrorUnsupportedOperation, "This operation is not supported"},
+	{a2a.ErrUnsupportedContentType, JSONRPCErrorContentTypeNotSupported, "Incompatible content types"},
+	{a2a.ErrInvalidAgentResponse, JSONRPCErrorInvalidAgentResponse, "Invalid agent response"},
+	{ErrInvalidWebhookURL, JSONRPCErrorInvalidParams, "Invalid params"},
+	{ErrPushConfigNotFound, JSONRPCErrorInvalidParams, "Invalid params"},
+	{ErrTaskNotResumable, JSONRPCErrorInvalidParams, "Invalid params"},
+	{ErrVersionNotSupported, JSONRPCErrorVersionNotSupported, "Version not supported"},
+	{ErrQuotaExceeded, JSONRPCErrorQuotaExceeded, "Quota exceeded"},
+}
+
+// A2AErrorCode returns the A2A error code and message for an error wrapping
+// one of the SDK's sentinel errors, such as a2a.ErrTaskNotFound
+func A2AErrorCode(err error) (int, string, bool) {
+	for _, e := range a2aErrors {
+		if errors.Is(err, e.err) {
+			return e.code, e.message, true
+		}
+	}
+	return 0, "", false
+}
+
+// UnmarshalJSON matches member names exactly, as JSON-RPC requires, where
+// encoding/json would also fill Method from "METHOD". Params are kept as a
+// json.RawMessage, so DecodeParams reads them once into their own type
+// rather than through a generic map.
+func (r *JSONRPCRequest) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	var req JSONRPCRequest
+	fields := []struct {
+		name   string
+		target interface{}
+	}{
+		{"jsonrpc", &req.JSONRPC},
+		{"method", &req.Method},
+	}
+	for _, field := range fields {
+		if raw, ok := members[field.name]; ok {
+			if err := json.Unmarshal(raw, field.target); err != nil {
+				return fmt.Errorf("invalid %s: %w", field.name, err)
+			}
+		}
+	}
+	if raw, ok := members["params"]; ok && string(raw) != "null" {
+		req.Params = raw
+	}
+	if raw, ok := members["id"]; ok {
+		id, err := decodeRequestID(raw)
+		if err != nil {
+			return fmt.Errorf("invalid id: %w", err)
+		}
+		req.ID = id
+	}
+	*r = req
+	return nil
+}
+
+// decodeRequestID decodes an id, keeping a number as a json.Number so it is
+// echoed exactly as sent rather than rounded through float64
+func decodeRequestID(raw json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var id interface{}
+	if err := decoder.Decode(&id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ResponseID returns the id a response to a request with id should carry:
+// the id itself when it is a string or number, and nil, encoded as
+// "id": null, for any other value
+func ResponseID(id interface{}) interface{} {
+	switch id.(type) {
+	case string, json.Number, float64, float32, int, int32, int64, uint, uint32, uint64:
+		return id
+	}
+	return nil
+}
+
 // ParseJSONRPCRequest parses raw JSON bytes into a JSONRPCRequest
 func ParseJSONRPCRequest(data []byte) (JSONRPCRequest, error) {
 	var req JSONRPCRequest
@@ -38,6 +144,40 @@ func ParseJSONRPCRequest(data []byte) (JSONRPCRequest, error) {
 	return req, nil
 }
 
+// UnmarshalJSON keeps the result as a json.RawMessage, so a caller decodes it
+// once into its own type rather than re-encoding a generic map
+func (r *JSONRPCResponse) UnmarshalJSON(data []byte) error {
+	type response JSONRPCResponse
+	var decoded struct {
+		response
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = JSONRPCResponse(decoded.response)
+	if len(decoded.Result) > 0 && string(decoded.Result) != "null" {
+		r.Result = decoded.Result
+	}
+	return nil
+}
+
+// ResultJSON returns the result as JSON, without encoding it again when it
+// was decoded from JSON
+func (r JSONRPCResponse) ResultJSON() (json.RawMessage, error) {
+	if raw, ok := r.Result.(json.RawMessage); ok {
+		return raw, nil
+	}
+	return json.Marshal(r.Result)
+}
+
+// WriteJSONRPCResponse encodes resp to w. Unlike json.Marshal it keeps no
+// copy of the encoding beyond the encoder's own buffer, which matters for
+// results such as long task histories.
+func WriteJSONRPCResponse(w io.Writer, resp JSONRPCResponse) error {
+	return json.NewEncoder(w).Encode(resp)
+}
+
 // ParseJSONRPCResponse parses raw JSON bytes into a JSONRPCResponse
 func ParseJSONRPCResponse(data []byte) (JSONRPCResponse, error) {
 	var resp JSONRPCResponse
@@ -106,27 +246,99 @@ func SerializeJSONRPCResponse(resp JSONRPCResponse) ([]byte, error) {
 	return data, nil
 }
 
-// IsJSONRPCRequest checks if the given data appears to be a JSON-RPC request
+// IsJSONRPCRequest checks if the given data appears to be a JSON-RPC request:
+// a JSON object whose "jsonrpc" member is 2.0 and whose "method" member is a
+// string. Member names are matched exactly, unlike encoding/json field
+// matching, so text that merely mentions them is not a request.
 func IsJSONRPCRequest(data []byte) bool {
-	// Quick check for JSON-RPC structure without full parsing
-	dataStr := strings.TrimSpace(string(data))
-	return strings.Contains(dataStr, `"jsonrpc"`) && 
-		   strings.Contains(dataStr, `"method"`) &&
-		   (strings.Contains(dataStr, `"2.0"`) || strings.Contains(dataStr, "2.0"))
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return false
+	}
+	var method string
+	if err := json.Unmarshal(object["method"], &method); err != nil {
+		return false
+	}
+	// Versions sent as the number 2.0 are lenient clients, not other protocols
+	var version any
+	if err := json.Unmarshal(object["jsonrpc"], &version); err != nil {
+		return false
+	}
+	return version == "2.0" || version == 2.0
+}
+
+// jsonrpcRequestMembers are the members JSON-RPC 2.0 defines for a request
+var jsonrpcRequestMembers = map[string]bool{"jsonrpc": true, "method": true, "params": true, "id": true}
+
+// CheckStrictJSONRPCRequest applies strict mode to a request body: it must
+// be an object with no members but jsonrpc, method, params and id, its id
+// a string, number or null, and its params an object or array when present.
+// Problems are listed as FieldErrors.
+func CheckStrictJSONRPCRequest(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil || members == nil {
+		return errors.New("request must be a JSON object")
+	}
+
+	var errs ValidationErrors
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !jsonrpcRequestMembers[name] {
+			errs.Add(name, ValidationCodeUnsupported, "is not a JSON-RPC request member")
+		}
+	}
+	if raw, ok := members["id"]; ok {
+		switch jsonKind(raw) {
+		case '"', 'n', '0':
+		default:
+			errs.Add("id", ValidationCodeInvalid, "must be a string, number or null")
+		}
+	}
+	if raw, ok := members["params"]; ok {
+		switch jsonKind(raw) {
+		case '{', '[':
+		default:
+			errs.Add("params", ValidationCodeInvalid, "must be an object or array")
+		}
+	}
+	return errs.Err()
+}
+
+// jsonKind classifies a JSON value by its first byte: '"', '{', '[', 't',
+// 'f' or 'n', and '0' for any number
+func jsonKind(raw json.RawMessage) byte {
+	trimmed := bytes.TrimSpace(raw)
+	switch {
+	case len(trimmed) == 0:
+		return 0
+	case trimmed[0] == '-' || trimmed[0] >= '0' && trimmed[0] <= '9':
+		return '0'
+	}
+	return trimmed[0]
 }
 
 // ExtractRequestID attempts to extract the ID from a JSON-RPC request/response
-// This is useful for error handling when parsing fails
+// This is useful for error handling when parsing fails. Numbers are returned
+// as json.Number, and an id that is missing, unreadable or neither a string
+// nor a number is nil, so a response echoes it exactly or as null.
 func ExtractRequestID(data []byte) interface{} {
-	var partial struct {
-		ID interface{} `json:"id"`
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil
 	}
-	
-	if err := json.Unmarshal(data, &partial); err != nil {
+	raw, ok := members["id"]
+	if !ok {
 		return nil
 	}
-	
-	return partial.ID
+	id, err := decodeRequestID(raw)
+	if err != nil {
+		return nil
+	}
+	return ResponseID(id)
 }
 
 // NewJSONRPCParseError creates a JSON-RPC parse error
@@ -3,7 +3,11 @@ package a2a
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
 )
 
 func TestParseJSONRPCRequest(t *testing.T) {
@@ -70,6 +74,24 @@ func TestParseJSONRPCRequest(t *testing.T) {
 			expectError: true,
 			errorType:   JSONRPCErrorInvalidRequest,
 		},
+		{
+			name:        "member names differ in case",
+			input:       []byte(`{"JSONRPC":"2.0","Method":"test","id":1}`),
+			expectError: true,
+			errorType:   JSONRPCErrorInvalidRequest,
+		},
+		{
+			name:        "object id",
+			input:       []byte(`{"jsonrpc":"2.0","method":"test","id":{"n":1}}`),
+			expectError: true,
+			errorType:   JSONRPCErrorInvalidRequest,
+		},
+		{
+			name:        "boolean id",
+			input:       []byte(`{"jsonrpc":"2.0","method":"test","id":true}`),
+			expectError: true,
+			errorType:   JSONRPCErrorInvalidRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -191,6 +213,28 @@ func TestParseJSONRPCResponse(t *testing.T) {
 	}
 }
 
+func TestJSONRPCResponseRawResult(t *testing.T) {
+	var body strings.Builder
+	if err := WriteJSONRPCResponse(&body, NewJSONRPCResponse(map[string]string{"ID": "task-1"}, 7)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.String() != `{"jsonrpc":"2.0","result":{"ID":"task-1"},"id":7}`+"\n" {
+		t.Errorf("unexpected encoding %q", body.String())
+	}
+
+	resp, err := ParseJSONRPCResponse([]byte(body.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, ok := resp.Result.(json.RawMessage)
+	if !ok || string(raw) != `{"ID":"task-1"}` {
+		t.Errorf("expected the result as sent, got %T %v", resp.Result, resp.Result)
+	}
+	if result, err := resp.ResultJSON(); err != nil || string(result) != `{"ID":"task-1"}` {
+		t.Errorf("unexpected result JSON %s: %v", result, err)
+	}
+}
+
 func TestSerializeJSONRPCRequest(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -333,6 +377,52 @@ func TestSerializeJSONRPCResponse(t *testing.T) {
 	}
 }
 
+func TestCheckStrictJSONRPCRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		expectPath []string
+	}{
+		{name: "well formed", input: `{"jsonrpc":"2.0","id":"req-1","method":"tasks/get","params":{"ID":"task-1"}}`},
+		{name: "numeric id and positional params", input: `{"jsonrpc":"2.0","id":-1.5,"method":"tasks/get","params":["task-1"]}`},
+		{name: "null id without params", input: `{"jsonrpc":"2.0","id":null,"method":"tasks/get"}`},
+		{name: "unknown members", input: `{"jsonrpc":"2.0","id":1,"method":"tasks/get","zeta":1,"Method":"x"}`, expectPath: []string{"Method", "zeta"}},
+		{name: "boolean id", input: `{"jsonrpc":"2.0","id":true,"method":"tasks/get"}`, expectPath: []string{"id"}},
+		{name: "object id", input: `{"jsonrpc":"2.0","id":{},"method":"tasks/get"}`, expectPath: []string{"id"}},
+		{name: "string params", input: `{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":"task-1"}`, expectPath: []string{"params"}},
+		{name: "null params", input: `{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":null}`, expectPath: []string{"params"}},
+		{name: "not an object", input: `[1]`, expectPath: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckStrictJSONRPCRequest([]byte(tt.input))
+			if len(tt.expectPath) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				if tt.expectPath[0] != "" {
+					t.Errorf("expected field errors, got %v", err)
+				}
+				return
+			}
+			var paths []string
+			for _, fieldErr := range errs {
+				paths = append(paths, fieldErr.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.expectPath, ",") {
+				t.Errorf("expected errors at %v, got %v", tt.expectPath, err)
+			}
+		})
+	}
+}
+
 func TestIsJSONRPCRequest(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -379,6 +469,26 @@ func TestIsJSONRPCRequest(t *testing.T) {
 			input:    []byte(`{"jsonrpc":"2.0","result":{"status":"ok"},"id":1}`),
 			expected: false,
 		},
+		{
+			name:     "member names only mentioned in a string",
+			input:    []byte(`{"note":"\"jsonrpc\" \"method\" 2.0"}`),
+			expected: false,
+		},
+		{
+			name:     "batch array",
+			input:    []byte(`[{"jsonrpc":"2.0","method":"test","id":1}]`),
+			expected: false,
+		},
+		{
+			name:     "method is not a string",
+			input:    []byte(`{"jsonrpc":"2.0","method":{"name":"test"},"id":1}`),
+			expected: false,
+		},
+		{
+			name:     "member names differ in case",
+			input:    []byte(`{"JSONRPC":"2.0","METHOD":"test","id":1}`),
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -400,7 +510,22 @@ func TestExtractRequestID(t *testing.T) {
 		{
 			name:     "numeric ID",
 			input:    []byte(`{"jsonrpc":"2.0","method":"test","id":123}`),
-			expected: float64(123), // JSON unmarshaling converts numbers to float64
+			expected: json.Number("123"),
+		},
+		{
+			name:     "large numeric ID keeps its digits",
+			input:    []byte(`{"jsonrpc":"2.0","method":"test","id":12345678901234567890}`),
+			expected: json.Number("12345678901234567890"),
+		},
+		{
+			name:     "boolean ID",
+			input:    []byte(`{"jsonrpc":"2.0","method":"test","id":true}`),
+			expected: nil,
+		},
+		{
+			name:     "not an object",
+			input:    []byte(`[{"jsonrpc":"2.0","method":"test","id":1}]`),
+			expected: nil,
 		},
 		{
 			name:     "string ID",
@@ -582,4 +707,91 @@ func containsSubstring(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func TestA2AErrorCode(t *testing.T) {
+	code, _, ok := A2AErrorCode(fmt.Errorf("failed to get task t-1: %w", a2a.ErrTaskNotFound))
+	if !ok || code != JSONRPCErrorTaskNotFound {
+		t.Errorf("expected %d, got %d (ok %v)", JSONRPCErrorTaskNotFound, code, ok)
+	}
+	if _, _, ok := A2AErrorCode(errors.New("throttled")); ok {
+		t.Error("expected no code for an unrelated error")
+	}
+}
+
+// jsonrpcFuzzSeeds are well-formed and malformed bodies the fuzzers start from
+var jsonrpcFuzzSeeds = []string{
+	`{"jsonrpc":"2.0","method":"tasks/get","params":{"id":"task-1"},"id":1}`,
+	`{"jsonrpc":"2.0","method":"message/send","params":{"Message":{"Parts":[{"Kind":"text","Text":"hi"}]}},"id":"a"}`,
+	`{"jsonrpc":"2.0","result":{"status":"ok"},"id":1}`,
+	`{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`,
+	`{"jsonrpc":2.0,"method":"test","id":1}`,
+	`{"note":"\"jsonrpc\" \"method\" 2.0"}`,
+	`[{"jsonrpc":"2.0","method":"test","id":1}]`,
+	`{"jsonrpc":"2.0","method":"test","id":{"nested":[1,2]}}`,
+	`{"jsonrpc":"2.0"`,
+	``,
+}
+
+func FuzzParseJSONRPCRequest(f *testing.F) {
+	for _, seed := range jsonrpcFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		req, err := ParseJSONRPCRequest(data)
+		if err != nil {
+			var rpcErr *JSONRPCError
+			if !errors.As(err, &rpcErr) {
+				t.Fatalf("expected a JSON-RPC error, got %T: %v", err, err)
+			}
+			return
+		}
+		if !IsJSONRPCRequest(data) {
+			t.Errorf("parsed request not recognized by IsJSONRPCRequest: %q", data)
+		}
+		// Anything accepted must survive a round trip
+		serialized, err := SerializeJSONRPCRequest(req)
+		if err != nil {
+			t.Fatalf("accepted request does not serialize: %v", err)
+		}
+		if _, err := ParseJSONRPCRequest(serialized); err != nil {
+			t.Fatalf("serialized request does not parse: %v", err)
+		}
+	})
+}
+
+func FuzzParseJSONRPCResponse(f *testing.F) {
+	for _, seed := range jsonrpcFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		resp, err := ParseJSONRPCResponse(data)
+		if err != nil {
+			return
+		}
+		if err := ValidateJSONRPCResponse(resp); err != nil {
+			t.Fatalf("accepted response is invalid: %v", err)
+		}
+		if resp.Error != nil {
+			_ = resp.Error.Error()
+		}
+	})
+}
+
+func FuzzIsJSONRPCRequest(f *testing.F) {
+	for _, seed := range jsonrpcFuzzSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if !IsJSONRPCRequest(data) {
+			return
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			t.Fatalf("recognized a request that is not a JSON object: %q", data)
+		}
+		if _, ok := object["method"]; !ok {
+			t.Fatalf("recognized a request without a method: %q", data)
+		}
+		ExtractRequestID(data)
+	})
+}
@@ -0,0 +1,171 @@
+package a2a
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// LoadLambdaEnvConfig builds the serverless config the Lambda entrypoint uses
+// when no config secret is set. It lives here rather than in cmd/lambda so
+// tools like cmd/configcheck load config exactly the way the Lambda does.
+func LoadLambdaEnvConfig(region string) (ServerlessConfig, error) {
+	tableName := getEnvOrDefault("DYNAMODB_TABLE", "a2a-tasks")
+	sqsQueueURL := getEnvOrDefault("SQS_QUEUE_URL", "")
+	agentName := getEnvOrDefault("AGENT_NAME", "A2A Serverless Agent")
+	agentURL := getEnvOrDefault("AGENT_URL", "https://example.com/agent")
+
+	// Configured skills replace the default general skill
+	skills, err := LoadSkillsFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+	if skills == nil {
+		skills = []a2a.AgentSkill{
+			{
+				ID:          "general",
+				Name:        "General Assistant",
+				Description: "General purpose AI assistant capabilities",
+				Examples:    []string{"Answer questions", "Help with tasks"},
+				Tags:        []string{"assistant", "general"},
+			},
+		}
+	}
+
+	security, err := LoadSecurityConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	logging, err := LoadLoggingConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	webhooks, err := LoadWebhookConfigFromEnv()
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	var compressAbove int
+	if value := getEnvOrDefault("DYNAMODB_COMPRESS_ABOVE", ""); value != "" {
+		compressAbove, err = strconv.Atoi(value)
+		if err != nil || compressAbove < 0 {
+			return ServerlessConfig{}, fmt.Errorf("DYNAMODB_COMPRESS_ABOVE must be a number of bytes, got %q", value)
+		}
+	}
+
+	agentCard := agentcard.New(agentName, agentURL,
+		agentcard.WithDescription("A serverless A2A agent running on AWS Lambda"),
+		agentcard.WithProtocolVersion("1.0"),
+		agentcard.WithSkills(skills),
+		WithSecurity(security),
+	)
+
+	config := ServerlessConfig{
+		AgentID:   getEnvOrDefault("AGENT_ID", "serverless-agent-1"),
+		AgentCard: agentCard,
+		CloudConfig: CloudProviderConfig{
+			Provider: "aws",
+			AWS: &AWSConfig{
+				Region:              region,
+				SQSQueueURL:         sqsQueueURL,
+				DynamoDBTable:       tableName,
+				EventsTable:         getEnvOrDefault("DYNAMODB_EVENTS_TABLE", DefaultEventsTable),
+				PushConfigTable:     getEnvOrDefault("DYNAMODB_PUSH_CONFIG_TABLE", ""),
+				CompressAbove:       compressAbove,
+				AuditTable:          getEnvOrDefault("DYNAMODB_AUDIT_TABLE", ""),
+				AuditFirehoseStream: getEnvOrDefault("AUDIT_FIREHOSE_STREAM", ""),
+				GlobalTables:        getEnvOrDefaultBool("DYNAMODB_GLOBAL_TABLES", false),
+				FailoverQueueURLs:   splitList(getEnvOrDefault("SQS_FAILOVER_QUEUE_URLS", "")),
+				RoleARN:             getEnvOrDefault("DATA_ROLE_ARN", ""),
+				ExternalID:          getEnvOrDefault("DATA_ROLE_EXTERNAL_ID", ""),
+			},
+		},
+		// LOG_LEVEL is the older name, kept so existing deployments keep their level
+		LogLevel:   getEnvOrDefault("A2A_LOG_LEVEL", getEnvOrDefault("LOG_LEVEL", "info")),
+		Logging:    logging,
+		Webhooks:   webhooks,
+		Transports: LoadTransportConfigFromEnv(),
+		Secrets: SecretsConfig{
+			APIKey:               SecretString(getEnvOrDefault("A2A_API_KEY", "")),
+			WebhookSigningKey:    SecretString(getEnvOrDefault("A2A_WEBHOOK_SIGNING_KEY", "")),
+			RequestSigningKey:    SecretString(getEnvOrDefault("A2A_REQUEST_SIGNING_KEY", "")),
+			ContentEncryptionKey: SecretString(getEnvOrDefault("A2A_CONTENT_ENCRYPTION_KEY", "")),
+			AdminAPIKey:          SecretString(getEnvOrDefault("A2A_ADMIN_API_KEY", "")),
+		},
+		Security: security,
+		Admin:    LoadAdminConfigFromEnv(),
+	}
+	features := LambdaDeploymentFeatures(config)
+	WithDeploymentInterfaces(features)(&config.AgentCard)
+	WithDeploymentCapabilities(features)(&config.AgentCard)
+	return config, nil
+}
+
+// LambdaDeploymentFeatures describes what the Lambda entrypoint serves for a config
+func LambdaDeploymentFeatures(config ServerlessConfig) DeploymentFeatures {
+	awsConfig := config.CloudConfig.AWS
+	features := DeploymentFeatures{
+		Transports: []a2a.TransportProtocol{a2a.TransportProtocolJSONRPC},
+		// API Gateway proxy integrations buffer the whole response, so SSE is not possible here
+		Streaming: false,
+		// Push notifications are enqueued to SQS for the configs clients set,
+		// so they are only offered with a queue and a table keeping configs
+		PushNotifications: awsConfig != nil && awsConfig.SQSQueueURL != "" && awsConfig.PushConfigTable != "",
+		// The DynamoDB event store keeps every status update
+		StateTransitionHistory: awsConfig != nil,
+		// The Lambda itself is the JSON-RPC interface at the card URL
+		Interfaces: []a2a.AgentInterface{{Transport: string(a2a.TransportProtocolJSONRPC), URL: config.AgentCard.URL}},
+	}
+	for _, iface := range config.Transports.interfaces() {
+		features.Transports = append(features.Transports, a2a.TransportProtocol(iface.Transport))
+		features.Interfaces = append(features.Interfaces, iface)
+	}
+	return features
+}
+
+// ValidateLambdaConfig runs every check the Lambda entrypoint applies at startup
+func ValidateLambdaConfig(config ServerlessConfig) error {
+	var errs ValidationErrors
+	errs.Merge("", ValidateServerlessConfig(config))
+	errs.Merge("", ValidateAgentCardDeployment(config.AgentCard, LambdaDeploymentFeatures(config)))
+	return errs.Err()
+}
+
+// DecodeServerlessConfig decodes a JSON-encoded ServerlessConfig without
+// validating it, so tools can show a config before reporting its errors
+func DecodeServerlessConfig(data []byte) (ServerlessConfig, error) {
+	var config ServerlessConfig
+	if err := FromJSON(data, &config); err != nil {
+		return ServerlessConfig{}, fmt.Errorf("invalid config JSON: %w", err)
+	}
+	// The security section is the source of truth for the published schemes,
+	// as it is for the env config
+	if config.Security.hasSchemes() {
+		WithSecurity(config.Security)(&config.AgentCard)
+	}
+	// Likewise the deployed transports are the source of truth for the interfaces
+	if config.Transports.configured() {
+		WithDeploymentInterfaces(LambdaDeploymentFeatures(config))(&config.AgentCard)
+	}
+	// and the wired subsystems for the capabilities, whatever the card claims
+	WithDeploymentCapabilities(LambdaDeploymentFeatures(config))(&config.AgentCard)
+	return config, nil
+}
+
+// ParseServerlessConfig parses and validates a JSON-encoded ServerlessConfig
+func ParseServerlessConfig(data []byte) (ServerlessConfig, error) {
+	config, err := DecodeServerlessConfig(data)
+	if err != nil {
+		return ServerlessConfig{}, err
+	}
+
+	if err := ValidateServerlessConfig(config); err != nil {
+		return ServerlessConfig{}, fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,235 @@
+package a2a
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestLoadLambdaEnvConfig(t *testing.T) {
+	lambdaEnv := []string{"AGENT_ID", "AGENT_NAME", "AGENT_URL", "DYNAMODB_TABLE", "SQS_QUEUE_URL", "DYNAMODB_PUSH_CONFIG_TABLE", "LOG_LEVEL"}
+	reset := func() {
+		clearTestEnv()
+		for _, env := range lambdaEnv {
+			os.Unsetenv(env)
+		}
+	}
+	reset()
+	defer reset()
+
+	// Defaults
+	config, err := LoadLambdaEnvConfig("us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AgentID != "serverless-agent-1" || config.CloudConfig.AWS.DynamoDBTable != "a2a-tasks" {
+		t.Errorf("unexpected defaults: %+v", config)
+	}
+	if config.CloudConfig.AWS.Region != "us-west-2" {
+		t.Errorf("expected region us-west-2, got %s", config.CloudConfig.AWS.Region)
+	}
+	if len(config.AgentCard.Skills) != 1 || config.AgentCard.Skills[0].ID != "general" {
+		t.Errorf("expected default general skill, got %v", config.AgentCard.Skills)
+	}
+
+	// Without a queue the defaults are valid and push notifications are not offered
+	if err := ValidateLambdaConfig(config); err != nil {
+		t.Errorf("expected defaults to validate, got %v", err)
+	}
+	if push := config.AgentCard.Capabilities.PushNotifications; push == nil || *push {
+		t.Errorf("expected push notifications off without SQS_QUEUE_URL, got %v", push)
+	}
+	if history := config.AgentCard.Capabilities.StateTransitionHistory; history == nil || !*history {
+		t.Errorf("expected state transition history from the event store, got %v", history)
+	}
+
+	os.Setenv("AGENT_ID", "lambda-agent")
+	os.Setenv("SQS_QUEUE_URL", "https://sqs.us-west-2.amazonaws.com/123456789/queue")
+	if config, _ := LoadLambdaEnvConfig("us-west-2"); *config.AgentCard.Capabilities.PushNotifications {
+		t.Error("expected push notifications off without a push config table")
+	}
+	os.Setenv("DYNAMODB_PUSH_CONFIG_TABLE", "a2a-push-configs")
+	os.Setenv("A2A_AGENT_SKILLS", `[{"id": "custom", "name": "Custom"}]`)
+	os.Setenv("A2A_AUTH_API_KEY_HEADER", "X-API-Key")
+	os.Setenv("A2A_API_KEY", "inbound-key")
+	config, err = LoadLambdaEnvConfig("us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AgentID != "lambda-agent" || config.AgentCard.Skills[0].ID != "custom" {
+		t.Errorf("expected env overrides, got %+v", config)
+	}
+	if push := config.AgentCard.Capabilities.PushNotifications; push == nil || !*push {
+		t.Errorf("expected push notifications with SQS_QUEUE_URL and DYNAMODB_PUSH_CONFIG_TABLE, got %v", push)
+	}
+	if config.AgentCard.SecuritySchemes[SecuritySchemeAPIKey] == nil {
+		t.Error("expected apiKey security scheme on agent card")
+	}
+	if err := ValidateLambdaConfig(config); err != nil {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	os.Setenv("DYNAMODB_COMPRESS_ABOVE", "4096")
+	config, err = LoadLambdaEnvConfig("us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if storage := (&AWSProvider{Config: *config.CloudConfig.AWS}).GetStorageConfig(); storage.DynamoDBCompressAbove != 4096 {
+		t.Errorf("expected the compression threshold to reach the storage config, got %d", storage.DynamoDBCompressAbove)
+	}
+	os.Setenv("DYNAMODB_COMPRESS_ABOVE", "4k")
+	if _, err := LoadLambdaEnvConfig("us-west-2"); err == nil {
+		t.Error("expected a threshold that is not a number to be rejected")
+	}
+	os.Unsetenv("DYNAMODB_COMPRESS_ABOVE")
+
+	// Companion transports are published from the config, never by hand
+	os.Setenv("A2A_GRPC_URL", "https://grpc.example.com")
+	config, err = LoadLambdaEnvConfig("us-west-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AgentCard.PreferredTransport != a2a.TransportProtocolJSONRPC || len(config.AgentCard.AdditionalInterfaces) != 2 ||
+		config.AgentCard.AdditionalInterfaces[1].URL != "https://grpc.example.com" {
+		t.Errorf("expected JSONRPC preferred with the gRPC interface listed, got %s %v", config.AgentCard.PreferredTransport, config.AgentCard.AdditionalInterfaces)
+	}
+	if err := ValidateLambdaConfig(config); err != nil {
+		t.Errorf("expected the derived interfaces to validate, got %v", err)
+	}
+
+	os.Setenv("A2A_AGENT_SKILLS", `not json`)
+	if _, err := LoadLambdaEnvConfig("us-west-2"); err == nil {
+		t.Error("expected error for invalid skills")
+	}
+}
+
+func TestDecodeServerlessConfigTransports(t *testing.T) {
+	tests := []struct {
+		name             string
+		data             string
+		expectInterfaces []string
+		expectError      string
+	}{
+		{
+			name:             "hand-maintained card left alone without transports",
+			data:             `{"agent_card": {"URL": "https://a.example.com", "AdditionalInterfaces": [{"Transport": "GRPC", "URL": "https://stale.example.com"}]}}`,
+			expectInterfaces: []string{"GRPC https://stale.example.com"},
+		},
+		{
+			name:             "transports replace stale interfaces",
+			data:             `{"agent_card": {"URL": "https://a.example.com", "PreferredTransport": "GRPC", "AdditionalInterfaces": [{"Transport": "GRPC", "URL": "https://stale.example.com"}]}, "transports": {"rest_url": "https://a.example.com/rest"}}`,
+			expectInterfaces: []string{"JSONRPC https://a.example.com", "HTTP+JSON https://a.example.com/rest"},
+		},
+		{
+			name:        "relative transport URL",
+			data:        `{"agent_card": {"URL": "https://a.example.com"}, "transports": {"grpc_url": "grpc.example.com"}}`,
+			expectError: "transports.grpc_url",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := DecodeServerlessConfig([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var interfaces []string
+			for _, iface := range config.AgentCard.AdditionalInterfaces {
+				interfaces = append(interfaces, iface.Transport+" "+iface.URL)
+			}
+			if tt.expectError != "" {
+				if err := ValidateServerlessConfig(config); err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("expected error containing %s, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if strings.Join(interfaces, ",") != strings.Join(tt.expectInterfaces, ",") {
+				t.Errorf("expected interfaces %v, got %v", tt.expectInterfaces, interfaces)
+			}
+		})
+	}
+}
+
+func TestDecodeServerlessConfigCapabilities(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		expectStreaming bool
+		expectPush      bool
+		expectHistory   bool
+	}{
+		{
+			name:          "claims the deployment cannot back",
+			data:          `{"agent_card": {"URL": "https://a.example.com", "Capabilities": {"Streaming": true, "PushNotifications": true}}, "cloud_config": {"provider": "aws", "aws": {"dynamodb_table": "tasks"}}}`,
+			expectHistory: true,
+		},
+		{
+			name:          "capabilities left out",
+			data:          `{"agent_card": {"URL": "https://a.example.com"}, "cloud_config": {"provider": "aws", "aws": {"sqs_queue_url": "https://sqs.us-east-1.amazonaws.com/123456789012/q", "push_config_table": "push-configs"}}}`,
+			expectPush:    true,
+			expectHistory: true,
+		},
+		{
+			name:          "queue without push configs",
+			data:          `{"agent_card": {"URL": "https://a.example.com"}, "cloud_config": {"provider": "aws", "aws": {"sqs_queue_url": "https://sqs.us-east-1.amazonaws.com/123456789012/q"}}}`,
+			expectHistory: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := DecodeServerlessConfig([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			capabilities := config.AgentCard.Capabilities
+			if capabilities.Streaming == nil || *capabilities.Streaming != tt.expectStreaming {
+				t.Errorf("expected streaming %v, got %v", tt.expectStreaming, capabilities.Streaming)
+			}
+			if capabilities.PushNotifications == nil || *capabilities.PushNotifications != tt.expectPush {
+				t.Errorf("expected push notifications %v, got %v", tt.expectPush, capabilities.PushNotifications)
+			}
+			if capabilities.StateTransitionHistory == nil || *capabilities.StateTransitionHistory != tt.expectHistory {
+				t.Errorf("expected state transition history %v, got %v", tt.expectHistory, capabilities.StateTransitionHistory)
+			}
+			if err := ValidateAgentCardDeployment(config.AgentCard, LambdaDeploymentFeatures(config)); err != nil {
+				t.Errorf("expected the derived capabilities to match the deployment, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseServerlessConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectError bool
+	}{
+		{
+			name: "valid config",
+			data: `{"agent_id": "a", "agent_card": {"Name": "A", "URL": "https://a.example.com"}, "cloud_config": {"provider": "local"}}`,
+		},
+		{
+			name:        "invalid JSON",
+			data:        `{`,
+			expectError: true,
+		},
+		{
+			name:        "fails validation",
+			data:        `{"agent_id": "a", "agent_card": {"Name": "A", "URL": "http://a.example.com"}, "cloud_config": {"provider": "local"}}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseServerlessConfig([]byte(tt.data))
+			if tt.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package a2a
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// LocalizationConfig translates the agent card's text for the locales a
+// caller asks for with Accept-Language
+type LocalizationConfig struct {
+	// DefaultLocale is the language of the card's own text, served when no
+	// requested locale is configured; it is advertised as Content-Language
+	DefaultLocale string `json:"default_locale,omitempty"`
+	// Locales are the translations by language tag, such as fr or pt-BR
+	Locales map[string]CardLocalization `json:"locales,omitempty"`
+}
+
+// CardLocalization is a card's text in one locale. Text left empty is
+// served as the card has it.
+type CardLocalization struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Skills are the skills' text by skill ID
+	Skills map[string]SkillLocalization `json:"skills,omitempty"`
+}
+
+// SkillLocalization is a skill's text in one locale
+type SkillLocalization struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// languageTagPattern accepts BCP 47 tags such as en, pt-BR or zh-Hant-TW
+var languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// parseLocales decodes the JSON object in A2A_AGENT_CARD_LOCALES
+func parseLocales(value string) (map[string]CardLocalization, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var locales map[string]CardLocalization
+	if err := json.Unmarshal([]byte(value), &locales); err != nil {
+		return nil, fmt.Errorf("A2A_AGENT_CARD_LOCALES must be a JSON object of card translations by language tag: %w", err)
+	}
+	return locales, nil
+}
+
+// ValidateLocalizationConfig checks the language tags and that translated
+// skills are skills of the card
+func ValidateLocalizationConfig(config LocalizationConfig, skills []a2a.AgentSkill) error {
+	var errs ValidationErrors
+	if config.DefaultLocale != "" && !languageTagPattern.MatchString(config.DefaultLocale) {
+		errs.Add("default_locale", ValidationCodeInvalid, fmt.Sprintf("'%s' is not a language tag such as en or pt-BR", config.DefaultLocale))
+	}
+	for _, locale := range slices.Sorted(maps.Keys(config.Locales)) {
+		path := joinFieldPath("locales", locale)
+		if !languageTagPattern.MatchString(locale) {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("'%s' is not a language tag such as en or pt-BR", locale))
+		}
+		if strings.EqualFold(locale, config.DefaultLocale) {
+			errs.Add(path, ValidationCodeDuplicate, "duplicates default_locale, which is the card's own text")
+		}
+		for _, skillID := range slices.Sorted(maps.Keys(config.Locales[locale].Skills)) {
+			if !hasSkill(skills, skillID) {
+				errs.Add(joinFieldPath(path, "skills."+skillID), ValidationCodeInvalid, fmt.Sprintf("'%s' is not a skill of the agent card", skillID))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// Localize returns card with its text translated into locale, or card
+// itself for the default locale
+func (c LocalizationConfig) Localize(card a2a.AgentCard, locale string) a2a.AgentCard {
+	localization, ok := c.Locales[locale]
+	if !ok {
+		return card
+	}
+	card.Name = cmp.Or(localization.Name, card.Name)
+	card.Description = cmp.Or(localization.Description, card.Description)
+	skills := make([]a2a.AgentSkill, len(card.Skills))
+	for i, skill := range card.Skills {
+		if text, ok := localization.Skills[skill.ID]; ok {
+			skill.Name = cmp.Or(text.Name, skill.Name)
+			skill.Description = cmp.Or(text.Description, skill.Description)
+			if len(text.Examples) > 0 {
+				skill.Examples = text.Examples
+			}
+		}
+		skills[i] = skill
+	}
+	card.Skills = skills
+	return card
+}
+
+// Negotiate picks the configured locale that best matches the
+// Accept-Language header in headers, or "" for the card's own text. Ranges
+// are tried by descending quality; a range matches a locale equal to it,
+// then one it truncates to (fr-CA to fr), then one within it (fr to fr-FR).
+func (c LocalizationConfig) Negotiate(headers map[string]string) string {
+	if len(c.Locales) == 0 {
+		return ""
+	}
+	locales := slices.Sorted(maps.Keys(c.Locales))
+	for _, languageRange := range acceptedLanguages(HeaderValue(headers, "Accept-Language")) {
+		if languageRange == "*" || strings.EqualFold(languageRange, c.DefaultLocale) {
+			return ""
+		}
+		for truncated := languageRange; truncated != ""; truncated = truncateLanguageTag(truncated) {
+			if strings.EqualFold(truncated, c.DefaultLocale) {
+				return ""
+			}
+			for _, locale := range locales {
+				if strings.EqualFold(locale, truncated) {
+					return locale
+				}
+			}
+		}
+		for _, locale := range locales {
+			if len(locale) > len(languageRange) && strings.EqualFold(locale[:len(languageRange)+1], languageRange+"-") {
+				return locale
+			}
+		}
+	}
+	return ""
+}
+
+// acceptedLanguages returns the language ranges of an Accept-Language
+// header by descending quality, leaving out those refused with q=0
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		languageRange string
+		quality       float64
+	}
+	var ranges []weighted
+	for _, field := range strings.Split(header, ",") {
+		languageRange, params, _ := strings.Cut(field, ";")
+		languageRange = strings.TrimSpace(languageRange)
+		if languageRange == "" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			ranges = append(ranges, weighted{languageRange, quality})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b weighted) int { return cmp.Compare(b.quality, a.quality) })
+
+	accepted := make([]string, len(ranges))
+	for i, r := range ranges {
+		accepted[i] = r.languageRange
+	}
+	return accepted
+}
+
+// truncateLanguageTag drops a tag's last subtag, and a single-letter
+// subtag left before it, as RFC 4647 lookup does
+func truncateLanguageTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndex(tag, "-"); j >= 0 && len(tag)-j == 2 {
+		tag = tag[:j]
+	}
+	return tag
+}
@@ -0,0 +1,100 @@
+package a2a
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+var testLocalization = LocalizationConfig{
+	DefaultLocale: "en",
+	Locales: map[string]CardLocalization{
+		"fr": {
+			Name:   "Agent de Voyage",
+			Skills: map[string]SkillLocalization{"book": {Description: "Réserve un vol"}},
+		},
+		"pt-BR": {Description: "Agente de viagens"},
+	},
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{acceptLanguage: "", expected: ""},
+		{acceptLanguage: "fr", expected: "fr"},
+		{acceptLanguage: "FR", expected: "fr"},
+		{acceptLanguage: "fr-CA", expected: "fr"},
+		{acceptLanguage: "pt", expected: "pt-BR"},
+		{acceptLanguage: "pt-br", expected: "pt-BR"},
+		{acceptLanguage: "de, fr;q=0.5", expected: "fr"},
+		{acceptLanguage: "fr;q=0.4, pt-BR;q=0.8", expected: "pt-BR"},
+		{acceptLanguage: "en-US, fr;q=0.9", expected: ""},
+		{acceptLanguage: "fr;q=0, de", expected: ""},
+		{acceptLanguage: "*, fr;q=0.5", expected: ""},
+		{acceptLanguage: "de", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			locale := testLocalization.Negotiate(map[string]string{"accept-language": tt.acceptLanguage})
+			if locale != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, locale)
+			}
+		})
+	}
+}
+
+func TestLocalizeAgentCard(t *testing.T) {
+	card := a2a.AgentCard{
+		Name:        "Travel Agent",
+		Description: "Plans trips",
+		Skills: []a2a.AgentSkill{
+			{ID: "book", Name: "Book", Description: "Books a flight"},
+			{ID: "cancel", Name: "Cancel", Description: "Cancels a booking"},
+		},
+	}
+
+	localized := testLocalization.Localize(card, "fr")
+	if localized.Name != "Agent de Voyage" || localized.Description != "Plans trips" {
+		t.Errorf("expected the French name with the default description, got %q, %q", localized.Name, localized.Description)
+	}
+	if localized.Skills[0].Description != "Réserve un vol" || localized.Skills[0].Name != "Book" || localized.Skills[1].Description != "Cancels a booking" {
+		t.Errorf("expected only the translated skill text to change, got %+v", localized.Skills)
+	}
+	if card.Skills[0].Description != "Books a flight" {
+		t.Error("expected the card's own skills to be left alone")
+	}
+	if got := testLocalization.Localize(card, ""); got.Name != card.Name {
+		t.Errorf("expected the default locale to serve the card, got %q", got.Name)
+	}
+}
+
+func TestValidateLocalizationConfig(t *testing.T) {
+	skills := []a2a.AgentSkill{{ID: "book", Name: "Book"}}
+
+	if err := ValidateLocalizationConfig(testLocalization, skills); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config LocalizationConfig
+	}{
+		{name: "bad default", config: LocalizationConfig{DefaultLocale: "english!"}},
+		{name: "bad tag", config: LocalizationConfig{Locales: map[string]CardLocalization{"fr_FR": {}}}},
+		{name: "default translated", config: LocalizationConfig{DefaultLocale: "en", Locales: map[string]CardLocalization{"EN": {}}}},
+		{
+			name:   "unknown skill",
+			config: LocalizationConfig{Locales: map[string]CardLocalization{"fr": {Skills: map[string]SkillLocalization{"refund": {}}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateLocalizationConfig(tt.config, skills); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
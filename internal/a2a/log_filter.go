@@ -0,0 +1,194 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// credentialKeys mark attribute keys whose values are credentials. A key
+// also matches when it ends in one after a separator, e.g. webhook_token or
+// X-A2A-Notification-Token, but not a word containing one, e.g. secrets.
+var credentialKeys = []string{"token", "secret", "password", "authorization", "api_key", "apikey", "credentials", "signature"}
+
+// logURLPattern finds URLs in log text. Webhook URLs often carry a token in
+// the path or query, and HTTP client errors quote the URL they called.
+var logURLPattern = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// LoggingConfig controls what logs may contain, so debug logging can be
+// turned on in production without leaking user content or credentials.
+// Message and artifact content, credential-like keys and URL paths are
+// always redacted; this adds to those rules.
+type LoggingConfig struct {
+	// RedactKeys are further attribute keys whose values are never logged
+	RedactKeys []string `json:"redact_keys,omitempty"`
+	// DebugSampleRatio is the fraction of debug records written. Unset
+	// writes every record.
+	DebugSampleRatio float64 `json:"debug_sample_ratio,omitempty"`
+}
+
+// LoadLoggingConfigFromEnv reads A2A_LOG_REDACT_KEYS and A2A_LOG_DEBUG_SAMPLE_RATIO
+func LoadLoggingConfigFromEnv() (LoggingConfig, error) {
+	config := LoggingConfig{RedactKeys: splitList(getEnvOrDefault("A2A_LOG_REDACT_KEYS", ""))}
+	if value := getEnvOrDefault("A2A_LOG_DEBUG_SAMPLE_RATIO", ""); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return LoggingConfig{}, fmt.Errorf("A2A_LOG_DEBUG_SAMPLE_RATIO must be a number between 0 and 1, got %q", value)
+		}
+		config.DebugSampleRatio = ratio
+	}
+	return config, nil
+}
+
+// ValidateLoggingConfig validates logging configuration
+func ValidateLoggingConfig(config LoggingConfig) error {
+	var errs ValidationErrors
+	if config.DebugSampleRatio < 0 || config.DebugSampleRatio > 1 {
+		errs.Add("debug_sample_ratio", ValidationCodeInvalid, fmt.Sprintf("%g must be between 0 and 1", config.DebugSampleRatio))
+	}
+	for i, key := range config.RedactKeys {
+		if strings.TrimSpace(key) == "" {
+			errs.Add(fmt.Sprintf("redact_keys[%d]", i), ValidationCodeRequired, "must not be empty")
+		}
+	}
+	return errs.Err()
+}
+
+// LogFilter applies a LoggingConfig to every logger built on it. Like a
+// slog.LevelVar it can be changed after those loggers are created, so a
+// config loaded after startup still covers the startup logger.
+type LogFilter struct {
+	rules  atomic.Pointer[logRules]
+	random func() float64
+}
+
+// logRules is a LoggingConfig prepared for lookups on every record
+type logRules struct {
+	redactKeys       map[string]bool
+	debugSampleRatio float64
+}
+
+// NewLogFilter creates a filter applying only the built-in redaction rules
+func NewLogFilter() *LogFilter {
+	filter := &LogFilter{random: rand.Float64}
+	filter.Set(LoggingConfig{})
+	return filter
+}
+
+// Set replaces the configured rules
+func (f *LogFilter) Set(config LoggingConfig) {
+	rules := &logRules{redactKeys: make(map[string]bool, len(config.RedactKeys)), debugSampleRatio: config.DebugSampleRatio}
+	for _, key := range config.RedactKeys {
+		rules.redactKeys[strings.ToLower(key)] = true
+	}
+	f.rules.Store(rules)
+}
+
+// NewFilteredLogger creates a JSON logger like NewLogger whose records pass
+// through filter
+func NewFilteredLogger(w io.Writer, level slog.Leveler, filter *LogFilter) *slog.Logger {
+	return slog.New(&filterHandler{next: slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}), filter: filter})
+}
+
+// filterHandler redacts and samples records before the JSON handler writes them
+type filterHandler struct {
+	next   slog.Handler
+	filter *LogFilter
+}
+
+func (h *filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *filterHandler) Handle(ctx context.Context, record slog.Record) error {
+	rules := h.filter.rules.Load()
+	if record.Level < slog.LevelInfo && rules.debugSampleRatio > 0 && h.filter.random() >= rules.debugSampleRatio {
+		return nil
+	}
+	filtered := slog.NewRecord(record.Time, record.Level, SanitizeText(maskLogURLs(record.Message), maxLogValueBytes), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		filtered.AddAttrs(rules.redact(attr))
+		return true
+	})
+	return h.next.Handle(ctx, filtered)
+}
+
+// WithAttrs redacts attrs with the rules in force when the logger is derived.
+// Request loggers are derived per request, so they pick up config changes.
+func (h *filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rules := h.filter.rules.Load()
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = rules.redact(attr)
+	}
+	return &filterHandler{next: h.next.WithAttrs(redacted), filter: h.filter}
+}
+
+func (h *filterHandler) WithGroup(name string) slog.Handler {
+	return &filterHandler{next: h.next.WithGroup(name), filter: h.filter}
+}
+
+// redact hides the value of attr if it is user content or a credential, and
+// masks URLs in text. Text is sanitized, since much of it comes from clients.
+func (r *logRules) redact(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if r.isRedactedKey(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = r.redact(member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.String(attr.Key, SanitizeText(maskLogURLs(attr.Value.String()), maxLogValueBytes))
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		// Every event and params type carries message or artifact parts
+		case a2a.Event, *a2a.Message, *a2a.Task, []a2a.Message, a2a.Part, []a2a.Part, a2a.Artifact, *a2a.Artifact, a2a.MessageSendParams, *a2a.MessageSendParams:
+			return slog.String(attr.Key, RedactedValue)
+		case error:
+			return slog.String(attr.Key, SanitizeText(maskLogURLs(value.Error()), maxLogValueBytes))
+		}
+	}
+	return attr
+}
+
+func (r *logRules) isRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	if r.redactKeys[key] {
+		return true
+	}
+	for _, credential := range credentialKeys {
+		if key == credential || strings.HasSuffix(key, "_"+credential) || strings.HasSuffix(key, "-"+credential) || strings.HasSuffix(key, "."+credential) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskLogURLs keeps only the scheme and host of every URL in text
+func maskLogURLs(text string) string {
+	return logURLPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parsed, err := url.Parse(match)
+		if err != nil || parsed.Host == "" {
+			return RedactedValue
+		}
+		if parsed.User == nil && strings.Trim(parsed.Path, "/") == "" && parsed.RawQuery == "" && parsed.Fragment == "" {
+			return match
+		}
+		return parsed.Scheme + "://" + parsed.Host + "/" + RedactedValue
+	})
+}
@@ -0,0 +1,190 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestLogFilterRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	filter := NewLogFilter()
+	filter.Set(LoggingConfig{RedactKeys: []string{"Customer_Email"}})
+	logger := NewFilteredLogger(&buf, slog.LevelDebug, filter)
+
+	message := a2a.Message{MessageID: "m1", Parts: []a2a.Part{a2a.TextPart{Text: "my card number is 4111"}}}
+	logger.With("webhook_token", "tok-123").Info("delivering to https://hooks.example.com/services/T000/B000/XXXX",
+		"message", message,
+		"parts", message.Parts,
+		"customer_email", "a@example.com",
+		LogKeyError, errors.New(`Post "https://hooks.example.com/services/T000?key=abc": timeout`),
+		"agent_url", "https://agent.example.com",
+		slog.Group("request", "Authorization", "Bearer abc", "attempt", 2),
+		"secrets", 12.5,
+	)
+
+	line := buf.String()
+	for _, leaked := range []string{"4111", "tok-123", "a@example.com", "T000", "key=abc", "Bearer abc"} {
+		if strings.Contains(line, leaked) {
+			t.Errorf("expected %q to be redacted from %s", leaked, line)
+		}
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid log line %q: %v", line, err)
+	}
+	expected := map[string]any{
+		"msg":            "delivering to https://hooks.example.com/" + RedactedValue,
+		"message":        RedactedValue,
+		"parts":          RedactedValue,
+		"webhook_token":  RedactedValue,
+		"customer_email": RedactedValue,
+		LogKeyError:      `Post "https://hooks.example.com/` + RedactedValue + `": timeout`,
+		// A bare origin names no secret
+		"agent_url": "https://agent.example.com",
+		// Only whole credential words match
+		"secrets": 12.5,
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+	request, _ := record["request"].(map[string]any)
+	if request["Authorization"] != RedactedValue || request["attempt"] != float64(2) {
+		t.Errorf("expected nested credentials redacted, got %v", request)
+	}
+}
+
+func TestLogFilterSanitizesText(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFilteredLogger(&buf, slog.LevelDebug, NewLogFilter())
+
+	task := &a2a.Task{ID: "task-1", History: []a2a.Message{{Parts: []a2a.Part{a2a.TextPart{Text: "my card number is 4111"}}}}}
+	logger.Warn("unparseable request\u2028forged",
+		LogKeyMethod, "tasks/get\n{\"level\":\"ERROR\",\"msg\":\"forged\"}",
+		LogKeyError, errors.New("unknown part kind \"\x1b[31m\""),
+		"task", task,
+		"blob", strings.Repeat("x", 10000),
+	)
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 || strings.Contains(line, "4111") {
+		t.Fatalf("expected one line without message content, got %s", line)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid log line %q: %v", line, err)
+	}
+	expected := map[string]any{
+		"msg":        `unparseable request\u2028forged`,
+		LogKeyMethod: `tasks/get\n{"level":"ERROR","msg":"forged"}`,
+		LogKeyError:  `unknown part kind "\x1b[31m"`,
+		"task":       RedactedValue,
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+	if blob, _ := record["blob"].(string); len(blob) != maxLogValueBytes || !strings.HasSuffix(blob, truncatedSuffix) {
+		t.Errorf("expected the oversized value cut to %d bytes, got %d", maxLogValueBytes, len(blob))
+	}
+}
+
+func TestLogFilterDebugSampling(t *testing.T) {
+	var buf bytes.Buffer
+	filter := NewLogFilter()
+	filter.Set(LoggingConfig{DebugSampleRatio: 0.25})
+	draws := []float64{0.1, 0.5, 0.9, 0.2}
+	filter.random = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+	logger := NewFilteredLogger(&buf, slog.LevelDebug, filter)
+
+	for i := 0; i < 4; i++ {
+		logger.Debug("message received")
+	}
+	// Info and above are never sampled, and draw nothing
+	logger.Info("request failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 sampled debug lines and 1 info line, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[2], `"level":"INFO"`) {
+		t.Errorf("expected info line to be kept, got %s", lines[2])
+	}
+}
+
+func TestLoadLoggingConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		expected    LoggingConfig
+		expectError bool
+	}{
+		{
+			name:     "defaults",
+			expected: LoggingConfig{},
+		},
+		{
+			name:     "redact keys and sampling",
+			envVars:  map[string]string{"A2A_LOG_REDACT_KEYS": "email, phone", "A2A_LOG_DEBUG_SAMPLE_RATIO": "0.1"},
+			expected: LoggingConfig{RedactKeys: []string{"email", "phone"}, DebugSampleRatio: 0.1},
+		},
+		{
+			name:        "unparseable ratio",
+			envVars:     map[string]string{"A2A_LOG_DEBUG_SAMPLE_RATIO": "most"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			config, err := LoadLoggingConfigFromEnv()
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(config.RedactKeys, ",") != strings.Join(tt.expected.RedactKeys, ",") || config.DebugSampleRatio != tt.expected.DebugSampleRatio {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestValidateLoggingConfig(t *testing.T) {
+	if err := ValidateLoggingConfig(LoggingConfig{RedactKeys: []string{"email"}, DebugSampleRatio: 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := ValidateLoggingConfig(LoggingConfig{RedactKeys: []string{" "}, DebugSampleRatio: 1.5})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 validation errors, got %v", err)
+	}
+	if errs[0].Path != "debug_sample_ratio" || errs[1].Path != "redact_keys[0]" {
+		t.Errorf("unexpected paths: %v", errs)
+	}
+}
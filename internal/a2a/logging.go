@@ -0,0 +1,90 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+)
+
+// Log attribute keys shared by every component so CloudWatch queries can
+// filter on the same field names
+const (
+	LogKeyRequestID = "request_id"
+	LogKeyAgentID   = "agent_id"
+	LogKeyMethod    = "method"
+	LogKeyTaskID    = "task_id"
+	LogKeyContextID = "context_id"
+	LogKeyError     = "error"
+)
+
+type loggerContextKey struct{}
+
+// logFieldsContextKey carries the key/value pairs added by WithLogAttrs, so
+// error reports describe a request with the same fields as its log lines
+type logFieldsContextKey struct{}
+
+// ParseLogLevel parses a config log level (debug, info, warn, error).
+// An empty level means info.
+func ParseLogLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	return parsed, nil
+}
+
+// NewLogger creates a JSON logger, the format CloudWatch Logs Insights
+// parses into fields. Pass a *slog.LevelVar to change the level of this
+// logger and every logger derived from it after creation. Records get the
+// built-in redaction rules; use NewFilteredLogger to configure more.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return NewFilteredLogger(w, level, NewLogFilter())
+}
+
+// SetLogLevel sets levelVar from a config log level. Invalid levels fall back
+// to info; config validation reports them separately.
+func SetLogLevel(levelVar *slog.LevelVar, level string) {
+	parsed, _ := ParseLogLevel(level)
+	levelVar.Set(parsed)
+}
+
+// ContextWithLogger returns a context carrying logger
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// LoggerFromContext returns the request-scoped logger, or the default logger
+// when none was attached
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithLogAttrs returns a context whose logger adds args to every record,
+// so later log calls for the same request carry e.g. the task ID
+func WithLogAttrs(ctx context.Context, args ...any) context.Context {
+	fields, _ := ctx.Value(logFieldsContextKey{}).([]any)
+	// Clip so sibling contexts never append into the same backing array
+	ctx = context.WithValue(ctx, logFieldsContextKey{}, append(slices.Clip(fields), args...))
+	return ContextWithLogger(ctx, LoggerFromContext(ctx).With(args...))
+}
+
+// LogFieldsFromContext returns the fields added by WithLogAttrs as strings.
+// A key added twice keeps its latest value.
+func LogFieldsFromContext(ctx context.Context) map[string]string {
+	args, _ := ctx.Value(logFieldsContextKey{}).([]any)
+	fields := make(map[string]string, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		if key, ok := args[i].(string); ok {
+			fields[key] = fmt.Sprint(args[i+1])
+		}
+	}
+	return fields
+}
@@ -0,0 +1,104 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		level       string
+		expected    slog.Level
+		expectError bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			level, err := ParseLogLevel(tt.level)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+			if level != tt.expected {
+				t.Errorf("expected level %v, got %v", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestNewLogger_JSONAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, slog.LevelWarn)
+
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("expected info record to be filtered at warn level, got %s", buf.String())
+	}
+
+	logger.Warn("kept", LogKeyTaskID, "task-1")
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON log line, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "kept" || record[LogKeyTaskID] != "task-1" {
+		t.Errorf("unexpected record: %v", record)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	var levelVar slog.LevelVar
+	SetLogLevel(&levelVar, "warn")
+	// Request loggers are derived before a refresh changes the level
+	logger := NewLogger(&buf, &levelVar).With(LogKeyRequestID, "req-1")
+
+	tests := []struct {
+		level       string
+		expectDebug bool
+	}{
+		{"warn", false},
+		{"debug", true},
+		{"bogus", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			buf.Reset()
+			SetLogLevel(&levelVar, tt.level)
+			logger.Debug("detail")
+			if got := buf.Len() > 0; got != tt.expectDebug {
+				t.Errorf("expected debug record logged %v, got %q", tt.expectDebug, buf.String())
+			}
+		})
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	if LoggerFromContext(context.Background()) != slog.Default() {
+		t.Error("expected default logger when none is attached")
+	}
+
+	var buf bytes.Buffer
+	ctx := ContextWithLogger(context.Background(), NewLogger(&buf, slog.LevelInfo))
+	ctx = WithLogAttrs(ctx, LogKeyRequestID, "req-1")
+	ctx = WithLogAttrs(ctx, LogKeyMethod, "tasks/get")
+	LoggerFromContext(ctx).Info("handled")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("expected JSON log line, got %q: %v", buf.String(), err)
+	}
+	if record[LogKeyRequestID] != "req-1" || record[LogKeyMethod] != "tasks/get" {
+		t.Errorf("expected request fields on record, got %v", record)
+	}
+}
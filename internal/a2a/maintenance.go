@@ -0,0 +1,100 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// IsMaintenanceEvent reports whether a Lambda payload asks for a
+// maintenance pass: {"maintenance": true}, the constant input of a
+// scheduled EventBridge rule. It is told apart from requests and warm-up
+// pings by that input alone.
+func IsMaintenanceEvent(payload []byte) bool {
+	var event struct {
+		Maintenance bool `json:"maintenance"`
+	}
+	return json.Unmarshal(payload, &event) == nil && event.Maintenance
+}
+
+// RunningTaskLister lists the tasks that may be waiting on the agent:
+// every submitted or working one, and possibly others
+type RunningTaskLister interface {
+	RunningTaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error]
+}
+
+// WithTaskSweep has maintenance passes fail the tasks lister lists that are
+// past their deadline, so a task no client reads still times out
+func WithTaskSweep(lister RunningTaskLister) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.sweep = lister
+	}
+}
+
+// MaintenanceReport counts what a maintenance pass did
+type MaintenanceReport struct {
+	// Released is how many scheduled messages were queued to start
+	Released int `json:"released"`
+	// TimedOut is how many tasks were failed for missing their deadline
+	TimedOut int `json:"timed_out"`
+}
+
+// RunMaintenance does the work no request triggers, and is meant to run on
+// a schedule at least every MaxRetryDelay: it releases the scheduled
+// messages starting before the next pass onto the work queue, and times out
+// the swept tasks past their deadline.
+func (h *ServerlessA2AHandler) RunMaintenance(ctx context.Context) (MaintenanceReport, error) {
+	var report MaintenanceReport
+	var errs []error
+	if releaser, ok := h.scheduler.(ScheduledMessageReleaser); ok && h.workQueue != nil {
+		released, err := releaser.ReleaseDue(ctx, h.workQueue, h.clock.Now())
+		report.Released = released
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to release scheduled messages: %w", err))
+		}
+	}
+	if h.sweep != nil {
+		timedOut, err := h.sweepTimedOut(ctx)
+		report.TimedOut = timedOut
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	LoggerFromContext(ctx).Info("maintenance pass complete", "released", report.Released, "timed_out", report.TimedOut)
+	return report, errors.Join(errs...)
+}
+
+// sweepTimedOut times out the listed tasks past their deadline, returning
+// how many it failed. A task that cannot be read or saved is left for the
+// next pass, or its next read, without stopping the sweep.
+func (h *ServerlessA2AHandler) sweepTimedOut(ctx context.Context) (int, error) {
+	timedOut := 0
+	var errs []error
+	for taskID, err := range h.sweep.RunningTaskIDs(ctx) {
+		if err != nil {
+			return timedOut, fmt.Errorf("failed to list running tasks: %w", err)
+		}
+		task, err := h.taskStore.GetTask(ctx, taskID)
+		if errors.Is(err, a2a.ErrTaskNotFound) {
+			// Expired or deleted since it was listed
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get task %s: %w", taskID, err))
+			continue
+		}
+		previous := task.Status.State
+		task, err = h.timeOut(ctx, task)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if task.Status.State == a2a.TaskStateFailed && previous != a2a.TaskStateFailed {
+			timedOut++
+		}
+	}
+	return timedOut, errors.Join(errs...)
+}
@@ -0,0 +1,109 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// Metric names shared by every metrics backend so dashboards port between them
+const (
+	MetricRequests             = "Requests"
+	MetricErrors               = "Errors"
+	MetricTaskTransitions      = "TaskStateTransitions"
+	MetricStorageLatency       = "StorageLatency"
+	MetricNotificationOutcomes = "NotificationDeliveries"
+	MetricInitDuration         = "InitDuration"
+	MetricRetentionPurged      = "RetentionPurged"
+)
+
+// Notification outcomes recorded by RecordNotification
+const (
+	NotificationOutcomeSent   = "sent"
+	NotificationOutcomeFailed = "failed"
+)
+
+// Metrics modes selected by A2A_METRICS
+const (
+	MetricsModeOff        = ""
+	MetricsModeEMF        = "emf"
+	MetricsModePrometheus = "prometheus"
+)
+
+// DefaultMetricsNamespace is the CloudWatch namespace used when A2A_METRICS_NAMESPACE is unset
+const DefaultMetricsNamespace = "A2AServerless"
+
+// Metrics records the operational metrics of the handler, stores and notifier
+type Metrics interface {
+	// RecordRequest counts a JSON-RPC request by method
+	RecordRequest(method string)
+	// RecordError counts a JSON-RPC error response by method and error code
+	RecordError(method string, code int)
+	// RecordTaskTransition counts a task moving between states
+	RecordTaskTransition(from, to a2a.TaskState)
+	// RecordStorageLatency records how long a storage operation took
+	RecordStorageLatency(operation string, latency time.Duration)
+	// RecordNotification counts a push notification delivery attempt by outcome
+	RecordNotification(outcome string)
+	// RecordInitDuration records how long a cold start phase took
+	RecordInitDuration(phase string, duration time.Duration)
+	// RecordRetentionPurge counts items the retention sweeper deleted by
+	// kind: tasks, events or artifacts
+	RecordRetentionPurge(kind string, count int)
+}
+
+// MetricsConfig selects the metrics backend
+type MetricsConfig struct {
+	Mode      string
+	Namespace string
+}
+
+// LoadMetricsConfigFromEnv reads A2A_METRICS and A2A_METRICS_NAMESPACE
+func LoadMetricsConfigFromEnv() (MetricsConfig, error) {
+	config := MetricsConfig{
+		Mode:      getEnvOrDefault("A2A_METRICS", MetricsModeOff),
+		Namespace: getEnvOrDefault("A2A_METRICS_NAMESPACE", DefaultMetricsNamespace),
+	}
+
+	switch config.Mode {
+	case MetricsModeOff, MetricsModeEMF, MetricsModePrometheus:
+	default:
+		return MetricsConfig{}, fmt.Errorf("unsupported A2A_METRICS mode: %s", config.Mode)
+	}
+
+	return config, nil
+}
+
+// NoopMetrics discards every metric
+type NoopMetrics struct{}
+
+func (NoopMetrics) RecordRequest(string)                              {}
+func (NoopMetrics) RecordError(string, int)                           {}
+func (NoopMetrics) RecordTaskTransition(a2a.TaskState, a2a.TaskState) {}
+func (NoopMetrics) RecordStorageLatency(string, time.Duration)        {}
+func (NoopMetrics) RecordNotification(string)                         {}
+func (NoopMetrics) RecordInitDuration(string, time.Duration)          {}
+func (NoopMetrics) RecordRetentionPurge(string, int)                  {}
+
+type metricsContextKey struct{}
+
+// ContextWithMetrics returns a context carrying metrics
+func ContextWithMetrics(ctx context.Context, metrics Metrics) context.Context {
+	return context.WithValue(ctx, metricsContextKey{}, metrics)
+}
+
+// MetricsFromContext returns the metrics in ctx, or NoopMetrics when none were attached
+func MetricsFromContext(ctx context.Context) Metrics {
+	if metrics, ok := ctx.Value(metricsContextKey{}).(Metrics); ok {
+		return metrics
+	}
+	return NoopMetrics{}
+}
+
+// observeStorage records the latency of a storage operation started at start.
+// Use as: defer observeStorage(ctx, "GetTask", time.Now())
+func observeStorage(ctx context.Context, operation string, start time.Time) {
+	MetricsFromContext(ctx).RecordStorageLatency(operation, time.Since(start))
+}
@@ -0,0 +1,99 @@
+package a2a
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Attributes written by stores with a region, next to the item's data
+const (
+	// updatedAtAttr is the write time in Unix nanoseconds
+	updatedAtAttr = "updated_at"
+	// regionAttr is the region that wrote the item
+	regionAttr = "region"
+)
+
+// ErrStaleWrite is returned when a task write loses to a newer write of the
+// same task, typically one replicated from another region of a global table
+var ErrStaleWrite = errors.New("a newer write of the task won")
+
+// WithRegion makes the AWS stores tag every task and event they write with
+// region and the write time, for active-active deployments on DynamoDB
+// global tables. Task writes become last-writer-wins on that time: a write
+// older than the stored task fails with ErrStaleWrite rather than replacing
+// it, and a tie goes to the alphabetically later region so every replica
+// settles on the same task.
+func WithRegion(region string) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.region = region
+	}
+}
+
+// tagRegion adds the region and write time to item, returning the condition
+// that keeps an older write from replacing a newer one and the names and
+// values it uses. It does nothing without a region.
+func (d runtimeDeps) tagRegion(item map[string]types.AttributeValue) (condition *string, names map[string]string, values map[string]types.AttributeValue) {
+	if d.region == "" {
+		return nil, nil, nil
+	}
+	now := &types.AttributeValueMemberN{Value: strconv.FormatInt(d.clock.Now().UnixNano(), 10)}
+	region := &types.AttributeValueMemberS{Value: d.region}
+	item[updatedAtAttr] = now
+	item[regionAttr] = region
+	return aws.String("attribute_not_exists(updated_at) OR updated_at < :updated_at OR (updated_at = :updated_at AND (attribute_not_exists(#region) OR #region <= :region))"),
+		// REGION is a DynamoDB reserved word
+		map[string]string{"#region": regionAttr},
+		map[string]types.AttributeValue{":updated_at": now, ":region": region}
+}
+
+// DefaultQueueCooldown is how long a push notification queue that failed is
+// passed over in favor of the next one
+const DefaultQueueCooldown = 30 * time.Second
+
+// notificationQueue is one queue of a failover list and its health
+type notificationQueue struct {
+	url    string
+	region string
+	// failedUntil is when the queue is tried first again after a failure
+	failedUntil time.Time
+}
+
+// newNotificationQueues lists the primary queue then its failovers
+func newNotificationQueues(primary string, failovers []string) []*notificationQueue {
+	queues := make([]*notificationQueue, 0, 1+len(failovers))
+	for _, queueURL := range append([]string{primary}, failovers...) {
+		queues = append(queues, &notificationQueue{url: queueURL, region: QueueRegion(queueURL)})
+	}
+	return queues
+}
+
+// sendOptions sends to the queue's own region, which may differ from the
+// client's for a failover queue
+func (q *notificationQueue) sendOptions() []func(*sqs.Options) {
+	if q.region == "" {
+		return nil
+	}
+	return []func(*sqs.Options){func(o *sqs.Options) { o.Region = q.region }}
+}
+
+// QueueRegion returns the region in an SQS queue URL such as
+// https://sqs.eu-west-1.amazonaws.com/123456789012/queue, or "" when the
+// URL names none, as with a local endpoint
+func QueueRegion(queueURL string) string {
+	u, err := url.Parse(queueURL)
+	if err != nil {
+		return ""
+	}
+	labels := strings.Split(u.Hostname(), ".")
+	if len(labels) < 4 || labels[0] != "sqs" || labels[2] != "amazonaws" {
+		return ""
+	}
+	return labels[1]
+}
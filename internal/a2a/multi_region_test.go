@@ -0,0 +1,180 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestAWSStoresRegionTag(t *testing.T) {
+	ctx := context.Background()
+	clock := fixedClock{now: time.Unix(1700000000, 5)}
+	task := a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}
+
+	client, requests := recordingDynamoDB(t)
+	opts := []RuntimeOption{WithRegion("eu-west-1"), WithClock(clock)}
+	NewAWSTaskStore(client, "tasks", "", opts...).SaveTask(ctx, task)
+	NewAWSEventStore(client, "events", "", opts...).SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: task.ID})
+	NewAWSTaskStore(client, "tasks", "").SaveTask(ctx, task)
+
+	taskPut, eventPut, untagged := (*requests)[0], (*requests)[1], (*requests)[2]
+	for _, put := range []map[string]any{taskPut, eventPut} {
+		if region := attributeS(put, "Item", "region"); region != "eu-west-1" {
+			t.Errorf("expected the item tagged with the region, got %q", region)
+		}
+		updatedAt, _ := put["Item"].(map[string]any)["updated_at"].(map[string]any)
+		if updatedAt["N"] != "1700000000000000005" {
+			t.Errorf("expected the write time in nanoseconds, got %v", updatedAt)
+		}
+	}
+	condition, _ := taskPut["ConditionExpression"].(string)
+	if !strings.Contains(condition, "updated_at < :updated_at") || attributeS(taskPut, "ExpressionAttributeValues", ":region") != "eu-west-1" {
+		t.Errorf("expected a last-writer-wins condition, got %q", condition)
+	}
+	if names, _ := taskPut["ExpressionAttributeNames"].(map[string]any); names["#region"] != "region" {
+		t.Errorf("expected the reserved word region to be aliased, got %v", names)
+	}
+	if _, ok := eventPut["ConditionExpression"]; ok {
+		t.Error("expected events to be written unconditionally")
+	}
+	if condition, _ := untagged["ConditionExpression"].(string); strings.Contains(condition, "updated_at") || attributeS(untagged, "Item", "region") != "" {
+		t.Errorf("expected no tag or last-writer-wins condition without a region, got %q", condition)
+	}
+}
+
+// staleDynamoDB fails every put as a newer item would
+type staleDynamoDB struct {
+	DynamoDBAPI
+}
+
+func (staleDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return nil, &types.ConditionalCheckFailedException{Message: new(string)}
+}
+
+func TestAWSTaskStoreStaleWrite(t *testing.T) {
+	store := NewAWSTaskStore(staleDynamoDB{}, "tasks", "", WithRegion("us-east-1"))
+	err := store.SaveTask(context.Background(), a2a.Task{ID: "task-1"})
+	if !errors.Is(err, ErrStaleWrite) || !strings.Contains(err.Error(), "task-1") {
+		t.Errorf("expected a stale write of task-1, got %v", err)
+	}
+}
+
+// finishedDynamoDB fails every put as a task stored canceled would
+type finishedDynamoDB struct {
+	DynamoDBAPI
+}
+
+func (finishedDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return nil, &types.ConditionalCheckFailedException{Message: new(string), Item: map[string]types.AttributeValue{
+		"status": &types.AttributeValueMemberS{Value: string(a2a.TaskStateCanceled)},
+	}}
+}
+
+func TestAWSTaskStoreFinishedTask(t *testing.T) {
+	ctx := context.Background()
+	client, requests := recordingDynamoDB(t)
+	NewAWSTaskStore(client, "tasks", "").SaveTask(ctx, a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}})
+	condition, _ := (*requests)[0]["ConditionExpression"].(string)
+	if !strings.Contains(condition, "NOT #status IN") || attributeS((*requests)[0], "ExpressionAttributeValues", ":status") != "completed" {
+		t.Errorf("expected the write conditional on the stored status, got %q", condition)
+	}
+
+	store := NewAWSTaskStore(finishedDynamoDB{}, "tasks", "")
+	err := store.SaveTask(ctx, a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}})
+	if !errors.Is(err, ErrTaskFinished) || !strings.Contains(err.Error(), "canceled") {
+		t.Errorf("expected the canceled task kept, got %v", err)
+	}
+}
+
+func TestQueueRegion(t *testing.T) {
+	tests := map[string]string{
+		"https://sqs.eu-west-1.amazonaws.com/123456789012/notifications":     "eu-west-1",
+		"https://sqs.cn-north-1.amazonaws.com.cn/123456789012/notifications": "cn-north-1",
+		"http://localhost:4566/000000000000/notifications":                   "",
+		"https://queue.example.com/notifications":                            "",
+		"::not a url": "",
+	}
+	for queueURL, expected := range tests {
+		if region := QueueRegion(queueURL); region != expected {
+			t.Errorf("%s: expected %q, got %q", queueURL, expected, region)
+		}
+	}
+}
+
+// regionalSQS fails sends to the regions in down and records where each
+// send went
+type regionalSQS struct {
+	SQSAPI
+	down  map[string]bool
+	sends []string
+}
+
+func (s *regionalSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	options := sqs.Options{Region: "us-east-1"}
+	for _, fn := range optFns {
+		fn(&options)
+	}
+	s.sends = append(s.sends, options.Region)
+	if s.down[options.Region] {
+		return nil, errors.New("service unavailable")
+	}
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func TestAWSSQSPushNotifierFailover(t *testing.T) {
+	ctx := context.Background()
+	client := &regionalSQS{down: map[string]bool{"us-east-1": true}}
+	clock := &fixedClock{now: time.Now()}
+	notifier := NewAWSSQSPushNotifier(client, "https://sqs.us-east-1.amazonaws.com/123/notifications", "", nil).
+		WithFailover("https://sqs.us-west-2.amazonaws.com/123/notifications")
+	notifier.clock = clock
+	event := a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1"}
+
+	if err := notifier.SendNotification(ctx, a2a.PushConfig{URL: "https://hooks.example.com"}, event); err != nil {
+		t.Fatalf("expected the failover queue to take the notification, got %v", err)
+	}
+	// The failed queue is passed over while it cools down, then tried first again
+	notifier.SendNotification(ctx, a2a.PushConfig{URL: "https://hooks.example.com"}, event)
+	clock.now = clock.now.Add(DefaultQueueCooldown)
+	notifier.SendNotification(ctx, a2a.PushConfig{URL: "https://hooks.example.com"}, event)
+	expected := []string{"us-east-1", "us-west-2", "us-west-2", "us-east-1", "us-west-2"}
+	if !slices.Equal(client.sends, expected) {
+		t.Errorf("expected sends to %v, got %v", expected, client.sends)
+	}
+
+	client.down["us-west-2"] = true
+	if err := notifier.SendNotification(ctx, a2a.PushConfig{URL: "https://hooks.example.com"}, event); err == nil || !strings.Contains(err.Error(), "service unavailable") {
+		t.Errorf("expected the last queue's error when every queue fails, got %v", err)
+	}
+}
+
+func TestValidateAWSConfigFailoverQueues(t *testing.T) {
+	primary := "https://sqs.us-east-1.amazonaws.com/123/notifications"
+	config := AWSConfig{
+		Region:            "us-east-1",
+		DynamoDBTable:     "tasks",
+		SQSQueueURL:       primary,
+		FailoverQueueURLs: []string{"https://sqs.us-west-2.amazonaws.com/123/notifications", primary, "not a url"},
+	}
+	var errs ValidationErrors
+	if !errors.As(ValidateAWSConfig(config), &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Path != "failover_queue_urls[1]" || errs[0].Code != ValidationCodeDuplicate || errs[1].Path != "failover_queue_urls[2]" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	config.SQSQueueURL = ""
+	config.FailoverQueueURLs = config.FailoverQueueURLs[:1]
+	if err := ValidateAWSConfig(config); err == nil || !strings.Contains(err.Error(), "requires sqs_queue_url") {
+		t.Errorf("expected failover queues to need a primary, got %v", err)
+	}
+}
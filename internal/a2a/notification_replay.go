@@ -0,0 +1,106 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// NotificationIDHeader carries the ID of the queue message a notification
+// was first sent as, on the webhook call and on replayed messages. It is the
+// same on every delivery and replay of the notification, so receivers can use
+// it as an idempotency key.
+const NotificationIDHeader = "X-A2A-Notification-Id"
+
+// Bounds of the notifications one replay call re-delivers
+const (
+	DefaultNotificationReplayLimit = 10
+	MaxNotificationReplayLimit     = 100
+)
+
+// NotificationReplayParams select the dead-lettered notifications the
+// admin/notifications/replay method re-delivers. At least one of task, config
+// or time range is required, so a replay never re-delivers everything by
+// accident.
+type NotificationReplayParams struct {
+	TaskID   a2a.TaskID `json:"task_id,omitempty"`
+	ConfigID string     `json:"config_id,omitempty"`
+	// Since and Until bound when the notification was first queued
+	Since *time.Time `json:"since,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+	// Limit bounds the notifications re-delivered (default
+	// DefaultNotificationReplayLimit, at most MaxNotificationReplayLimit)
+	Limit int `json:"limit,omitempty"`
+	// DryRun lists what would be re-delivered and leaves it queued
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// Validate checks the params of a replay call
+func (p NotificationReplayParams) Validate() error {
+	var errs ValidationErrors
+	if p.TaskID == "" && p.ConfigID == "" && p.Since == nil && p.Until == nil {
+		errs.Add("task_id", ValidationCodeRequired, "one of task_id, config_id, since or until is required")
+	}
+	if p.Since != nil && p.Until != nil && p.Until.Before(*p.Since) {
+		errs.Add("until", ValidationCodeInvalid, "must not be before since")
+	}
+	if p.Limit < 0 || p.Limit > MaxNotificationReplayLimit {
+		errs.Add("limit", ValidationCodeInvalid, fmt.Sprintf("must be between 0 and %d", MaxNotificationReplayLimit))
+	}
+	return errs.Err()
+}
+
+// matches reports whether a notification for taskID and configID, first
+// queued at sentAt, is selected
+func (p NotificationReplayParams) matches(taskID a2a.TaskID, configID string, sentAt time.Time) bool {
+	if p.TaskID != "" && taskID != p.TaskID {
+		return false
+	}
+	if p.ConfigID != "" && configID != p.ConfigID {
+		return false
+	}
+	if p.Since != nil && sentAt.Before(*p.Since) {
+		return false
+	}
+	if p.Until != nil && sentAt.After(*p.Until) {
+		return false
+	}
+	return true
+}
+
+// ReplayedNotification is one notification a replay re-delivered, or would
+// have in a dry run
+type ReplayedNotification struct {
+	NotificationID string     `json:"notification_id"`
+	TaskID         a2a.TaskID `json:"task_id"`
+	ConfigID       string     `json:"config_id,omitempty"`
+	SentAt         time.Time  `json:"sent_at"`
+}
+
+// NotificationReplayReport is the result of admin/notifications/replay
+type NotificationReplayReport struct {
+	Replayed []ReplayedNotification `json:"replayed"`
+	// Scanned counts the dead-lettered notifications looked at
+	Scanned int  `json:"scanned"`
+	DryRun  bool `json:"dry_run,omitempty"`
+}
+
+// NotificationReplayer re-delivers notifications that failed every delivery.
+// owned reports whether a task belongs to the caller's agent; notifications
+// of other tasks are left alone.
+type NotificationReplayer interface {
+	ReplayNotifications(ctx context.Context, params NotificationReplayParams, owned func(ctx context.Context, taskID a2a.TaskID) bool) (NotificationReplayReport, error)
+}
+
+// NotificationReplayConfig names the dead-letter queue failed notifications
+// are replayed from. Replay is off unless DeadLetterQueueURL is set.
+type NotificationReplayConfig struct {
+	DeadLetterQueueURL string
+}
+
+// LoadNotificationReplayConfigFromEnv reads A2A_NOTIFICATION_DLQ_URL
+func LoadNotificationReplayConfigFromEnv() NotificationReplayConfig {
+	return NotificationReplayConfig{DeadLetterQueueURL: getEnvOrDefault("A2A_NOTIFICATION_DLQ_URL", "")}
+}
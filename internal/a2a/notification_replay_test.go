@@ -0,0 +1,175 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// deadLetterSQS serves the messages of a dead-letter queue, hiding those
+// received until they are released or deleted, and records what is sent
+type deadLetterSQS struct {
+	SQSAPI
+	messages []sqstypes.Message
+	hidden   map[string]bool
+	sent     []*sqs.SendMessageInput
+	sendErr  error
+}
+
+func (s *deadLetterSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	output := &sqs.ReceiveMessageOutput{}
+	for _, message := range s.messages {
+		if len(output.Messages) < int(params.MaxNumberOfMessages) && !s.hidden[*message.ReceiptHandle] {
+			s.hidden[*message.ReceiptHandle] = true
+			output.Messages = append(output.Messages, message)
+		}
+	}
+	return output, nil
+}
+
+func (s *deadLetterSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	if s.sendErr != nil {
+		return nil, s.sendErr
+	}
+	s.sent = append(s.sent, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (s *deadLetterSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	for i, message := range s.messages {
+		if *message.ReceiptHandle == *params.ReceiptHandle {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			break
+		}
+	}
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (s *deadLetterSQS) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	for _, entry := range params.Entries {
+		delete(s.hidden, *entry.ReceiptHandle)
+	}
+	return &sqs.ChangeMessageVisibilityBatchOutput{}, nil
+}
+
+// deadLettered builds a dead-lettered status update of taskID for configID
+func deadLettered(id string, taskID, configID string, sentAt time.Time) sqstypes.Message {
+	return sqstypes.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String("receipt-" + id),
+		Body:          aws.String(`{"push_config":{"id":"` + configID + `","url":"https://hooks.example.com"},"event":{"kind":"status-update","taskId":"` + taskID + `"}}`),
+		Attributes:    map[string]string{"SentTimestamp": strconv.FormatInt(sentAt.UnixMilli(), 10)},
+		MessageAttributes: map[string]sqstypes.MessageAttributeValue{
+			SignatureHeader: {DataType: aws.String("String"), StringValue: aws.String("sha256=abc")},
+		},
+	}
+}
+
+func TestSQSNotificationReplayer(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	newQueue := func() *deadLetterSQS {
+		return &deadLetterSQS{hidden: map[string]bool{}, messages: []sqstypes.Message{
+			deadLettered("m-1", "task-1", "c-1", day),
+			deadLettered("m-2", "task-2", "c-1", day.Add(time.Hour)),
+			deadLettered("m-3", "task-1", "c-2", day.Add(2*time.Hour)),
+			deadLettered("m-4", "task-other", "c-1", day.Add(3*time.Hour)),
+		}}
+	}
+	owned := func(ctx context.Context, taskID a2a.TaskID) bool { return taskID != "task-other" }
+	since := day.Add(30 * time.Minute)
+
+	tests := []struct {
+		name     string
+		params   NotificationReplayParams
+		expected []string
+	}{
+		{name: "by task", params: NotificationReplayParams{TaskID: "task-1"}, expected: []string{"m-1", "m-3"}},
+		{name: "by config", params: NotificationReplayParams{ConfigID: "c-1"}, expected: []string{"m-1", "m-2"}},
+		{name: "by time range", params: NotificationReplayParams{Since: &since}, expected: []string{"m-2", "m-3"}},
+		{name: "limited", params: NotificationReplayParams{ConfigID: "c-1", Limit: 1}, expected: []string{"m-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := newQueue()
+			report, err := NewSQSNotificationReplayer(queue, "dlq", "queue").ReplayNotifications(ctx, tt.params, owned)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(report.Replayed) != len(tt.expected) || len(queue.sent) != len(tt.expected) {
+				t.Fatalf("expected %v replayed, got %+v", tt.expected, report)
+			}
+			for i, id := range tt.expected {
+				sent := queue.sent[i]
+				if report.Replayed[i].NotificationID != id || *sent.MessageAttributes[NotificationIDHeader].StringValue != id {
+					t.Errorf("expected %s to be replayed with its notification ID, got %+v", id, report.Replayed[i])
+				}
+				if *sent.QueueUrl != "queue" || sent.MessageAttributes[SignatureHeader].StringValue == nil {
+					t.Errorf("expected the message to return to the queue with its signature, got %+v", sent)
+				}
+			}
+			if len(queue.messages) != 4-len(tt.expected) || len(queue.hidden) != len(tt.expected) {
+				t.Errorf("expected replayed messages deleted and the rest released, got %d left, %d hidden", len(queue.messages), len(queue.hidden))
+			}
+		})
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		queue := newQueue()
+		report, err := NewSQSNotificationReplayer(queue, "dlq", "queue").ReplayNotifications(ctx, NotificationReplayParams{ConfigID: "c-1", DryRun: true}, owned)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Replayed) != 2 || report.Scanned != 4 || len(queue.sent) != 0 || len(queue.messages) != 4 || len(queue.hidden) != 0 {
+			t.Errorf("expected two listed and everything left queued, got %+v", report)
+		}
+	})
+
+	t.Run("send fails", func(t *testing.T) {
+		queue := newQueue()
+		queue.sendErr = errors.New("throttled")
+		if _, err := NewSQSNotificationReplayer(queue, "dlq", "queue").ReplayNotifications(ctx, NotificationReplayParams{TaskID: "task-1"}, owned); err == nil {
+			t.Fatal("expected the failed send to be reported")
+		}
+		if len(queue.messages) != 4 || len(queue.hidden) != 0 {
+			t.Errorf("expected every message left in the dead-letter queue, got %d left, %d hidden", len(queue.messages), len(queue.hidden))
+		}
+	})
+}
+
+func TestNotificationReplayParamsValidate(t *testing.T) {
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := since.Add(-time.Hour)
+	tests := []struct {
+		name       string
+		params     NotificationReplayParams
+		expectPath string
+	}{
+		{name: "by task", params: NotificationReplayParams{TaskID: "task-1"}},
+		{name: "nothing selected", params: NotificationReplayParams{DryRun: true}, expectPath: "task_id"},
+		{name: "until before since", params: NotificationReplayParams{Since: &since, Until: &until}, expectPath: "until"},
+		{name: "limit too large", params: NotificationReplayParams{TaskID: "task-1", Limit: MaxNotificationReplayLimit + 1}, expectPath: "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			var errs ValidationErrors
+			if tt.expectPath == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &errs) || errs[0].Path != tt.expectPath {
+				t.Errorf("expected an error at %s, got %v", tt.expectPath, err)
+			}
+		})
+	}
+}
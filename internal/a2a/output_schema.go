@@ -0,0 +1,349 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// outputSchemaKeywords are the JSON Schema keywords output schemas are
+// checked against. Others are refused when the config is validated rather
+// than silently not enforced.
+var outputSchemaKeywords = []string{
+	"$schema", "$id", "$comment", "title", "description", "examples", "default",
+	"type", "enum", "const",
+	"properties", "required", "additionalProperties",
+	"items", "minItems", "maxItems",
+	"minLength", "maxLength", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+	"allOf", "anyOf", "oneOf",
+}
+
+// outputSchemaTypes are the values of the type keyword
+var outputSchemaTypes = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// parseOutputSchemas decodes the JSON object in A2A_SKILL_OUTPUT_SCHEMAS
+func parseOutputSchemas(value string) (map[string]map[string]any, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var schemas map[string]map[string]any
+	if err := json.Unmarshal([]byte(value), &schemas); err != nil {
+		return nil, fmt.Errorf("A2A_SKILL_OUTPUT_SCHEMAS must be a JSON object of JSON Schemas by skill ID: %w", err)
+	}
+	return schemas, nil
+}
+
+// ValidateOutputSchemas checks each schema is keyed by one of skills and
+// uses only the keywords outputs are checked against
+func ValidateOutputSchemas(schemas map[string]map[string]any, skills []a2a.AgentSkill) error {
+	var errs ValidationErrors
+	for _, skillID := range slices.Sorted(maps.Keys(schemas)) {
+		if !hasSkill(skills, skillID) {
+			errs.Add(skillID, ValidationCodeInvalid, fmt.Sprintf("'%s' is not a skill of the agent card", skillID))
+		}
+		validateOutputSchema(schemas[skillID], skillID, &errs)
+	}
+	return errs.Err()
+}
+
+// validateOutputSchema checks a schema and the schemas nested in it
+func validateOutputSchema(schema map[string]any, path string, errs *ValidationErrors) {
+	for _, keyword := range slices.Sorted(maps.Keys(schema)) {
+		value := schema[keyword]
+		keywordPath := joinFieldPath(path, keyword)
+		switch keyword {
+		case "type":
+			for _, name := range schemaTypes(value) {
+				if !slices.Contains(outputSchemaTypes, name) {
+					errs.Add(keywordPath, ValidationCodeInvalid, fmt.Sprintf("'%s' must be one of %s", name, strings.Join(outputSchemaTypes, ", ")))
+				}
+			}
+		case "properties":
+			properties, ok := value.(map[string]any)
+			if !ok {
+				errs.Add(keywordPath, ValidationCodeInvalid, "must be an object of schemas")
+				continue
+			}
+			for _, name := range slices.Sorted(maps.Keys(properties)) {
+				validateNestedSchema(properties[name], joinFieldPath(keywordPath, name), errs)
+			}
+		case "additionalProperties":
+			if _, ok := value.(bool); !ok {
+				validateNestedSchema(value, keywordPath, errs)
+			}
+		case "items":
+			validateNestedSchema(value, keywordPath, errs)
+		case "allOf", "anyOf", "oneOf":
+			schemas, ok := value.([]any)
+			if !ok || len(schemas) == 0 {
+				errs.Add(keywordPath, ValidationCodeInvalid, "must be a non-empty array of schemas")
+				continue
+			}
+			for i, nested := range schemas {
+				validateNestedSchema(nested, fmt.Sprintf("%s[%d]", keywordPath, i), errs)
+			}
+		case "pattern":
+			pattern, _ := value.(string)
+			if _, err := regexp.Compile(pattern); err != nil {
+				errs.Add(keywordPath, ValidationCodeInvalid, fmt.Sprintf("must be a regular expression: %v", err))
+			}
+		default:
+			if !slices.Contains(outputSchemaKeywords, keyword) {
+				errs.Add(keywordPath, ValidationCodeUnsupported, "is not a keyword output schemas are checked against")
+			}
+		}
+	}
+}
+
+func validateNestedSchema(value any, path string, errs *ValidationErrors) {
+	nested, ok := value.(map[string]any)
+	if !ok {
+		errs.Add(path, ValidationCodeInvalid, "must be a schema object")
+		return
+	}
+	validateOutputSchema(nested, path, errs)
+}
+
+// schemaTypes returns the type keyword's value as a list
+func schemaTypes(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var names []string
+		for _, name := range value {
+			s, _ := name.(string)
+			names = append(names, s)
+		}
+		return names
+	}
+	return []string{fmt.Sprint(value)}
+}
+
+// jsonValueType names the JSON Schema type of a decoded JSON value, integer
+// for whole numbers
+func jsonValueType(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// checkOutput records where value breaks schema. value must be decoded
+// JSON, so numbers are float64.
+func checkOutput(schema map[string]any, value any, path string, errs *ValidationErrors) {
+	if types, ok := schema["type"]; ok {
+		actual := jsonValueType(value)
+		names := schemaTypes(types)
+		if !slices.Contains(names, actual) && !(actual == "integer" && slices.Contains(names, "number")) {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must be %s, got %s", strings.Join(names, " or "), actual))
+			return
+		}
+	}
+	if values, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(values, func(v any) bool { return jsonEqual(v, value) }) {
+		errs.Add(path, ValidationCodeInvalid, "must be one of the schema's enum values")
+	}
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		errs.Add(path, ValidationCodeInvalid, "must be the schema's const value")
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if name, _ := name.(string); name != "" {
+					if _, ok := value[name]; !ok {
+						errs.Add(joinFieldPath(path, name), ValidationCodeRequired, "is required")
+					}
+				}
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			if property, ok := properties[name].(map[string]any); ok {
+				checkOutput(property, value[name], joinFieldPath(path, name), errs)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs.Add(joinFieldPath(path, name), ValidationCodeUnsupported, "is not a property of the schema")
+				}
+			case map[string]any:
+				checkOutput(additional, value[name], joinFieldPath(path, name), errs)
+			}
+		}
+	case []any:
+		checkBound(schema, "minItems", "maxItems", float64(len(value)), "items", path, errs)
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				checkOutput(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case string:
+		checkBound(schema, "minLength", "maxLength", float64(len([]rune(value))), "characters", path, errs)
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(value) {
+				errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must match %s", pattern))
+			}
+		}
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must be at least %v", minimum))
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must be at most %v", maximum))
+		}
+		if minimum, ok := schema["exclusiveMinimum"].(float64); ok && value <= minimum {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must be more than %v", minimum))
+		}
+		if maximum, ok := schema["exclusiveMaximum"].(float64); ok && value >= maximum {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must be less than %v", maximum))
+		}
+	}
+
+	if schemas, ok := schema["allOf"].([]any); ok {
+		for _, nested := range schemas {
+			if nested, ok := nested.(map[string]any); ok {
+				checkOutput(nested, value, path, errs)
+			}
+		}
+	}
+	if schemas, ok := schema["anyOf"].([]any); ok && matchingSchemas(schemas, value) == 0 {
+		errs.Add(path, ValidationCodeInvalid, "must match at least one of the anyOf schemas")
+	}
+	if schemas, ok := schema["oneOf"].([]any); ok {
+		if matched := matchingSchemas(schemas, value); matched != 1 {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must match exactly one of the oneOf schemas, matched %d", matched))
+		}
+	}
+}
+
+// checkBound records a count outside a schema's minimum and maximum keywords
+func checkBound(schema map[string]any, minKeyword, maxKeyword string, count float64, unit, path string, errs *ValidationErrors) {
+	if minimum, ok := schema[minKeyword].(float64); ok && count < minimum {
+		errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must have at least %v %s", minimum, unit))
+	}
+	if maximum, ok := schema[maxKeyword].(float64); ok && count > maximum {
+		errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("must have at most %v %s", maximum, unit))
+	}
+}
+
+// matchingSchemas counts the schemas value matches
+func matchingSchemas(schemas []any, value any) int {
+	matched := 0
+	for _, nested := range schemas {
+		nested, ok := nested.(map[string]any)
+		if !ok {
+			continue
+		}
+		var errs ValidationErrors
+		if checkOutput(nested, value, "", &errs); len(errs) == 0 {
+			matched++
+		}
+	}
+	return matched
+}
+
+// jsonEqual compares decoded JSON values
+func jsonEqual(a, b any) bool {
+	x, errA := json.Marshal(a)
+	y, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(x) == string(y)
+}
+
+// checkArtifacts checks the data parts of a task's artifacts against the
+// output schema of the message's skill
+func (h *ServerlessA2AHandler) checkArtifacts(message a2a.Message, task a2a.Task) error {
+	skillID, _ := message.Metadata[SkillMetadataKey].(string)
+	schema, ok := h.config.OutputSchemas[skillID]
+	if !ok || skillID == "" {
+		return nil
+	}
+	var errs ValidationErrors
+	for i, artifact := range task.Artifacts {
+		for j, part := range artifact.Parts {
+			data, ok := part.(a2a.DataPart)
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("artifacts[%d].parts[%d].data", i, j)
+			// Executors may write any Go values; the schema describes their JSON
+			encoded, err := json.Marshal(data.Data)
+			var value any
+			if err == nil {
+				err = json.Unmarshal(encoded, &value)
+			}
+			if err != nil {
+				errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("cannot be encoded: %v", err))
+				continue
+			}
+			checkOutput(schema, value, path, &errs)
+		}
+	}
+	return errs.Err()
+}
+
+// checkOutputSchema fails a task completed with output breaking its skill's
+// schema. The failed status carries the violations as data, and replaces the
+// completed status in the executor's events.
+func (h *ServerlessA2AHandler) checkOutputSchema(ctx context.Context, message a2a.Message, task *a2a.Task, events []a2a.Event, now time.Time) {
+	if task.Status.State != a2a.TaskStateCompleted {
+		return
+	}
+	err := h.checkArtifacts(message, *task)
+	if err == nil {
+		return
+	}
+	// Stored as plain JSON values, as the task will read back
+	var violations []any
+	for _, violation := range err.(ValidationErrors) {
+		violations = append(violations, map[string]any{"path": violation.Path, "code": string(violation.Code), "message": violation.Message})
+	}
+	skillID, _ := message.Metadata[SkillMetadataKey].(string)
+	task.Status = a2a.TaskStatus{
+		State: a2a.TaskStateFailed,
+		Message: &a2a.Message{
+			Kind:      "message",
+			MessageID: h.ids.NewID(),
+			Role:      a2a.MessageRoleAgent,
+			TaskID:    &task.ID,
+			ContextID: &task.ContextID,
+			Parts: []a2a.Part{
+				a2a.TextPart{Kind: "text", Text: fmt.Sprintf("output does not match the output schema of skill %s: %v", skillID, err)},
+				a2a.DataPart{Kind: "data", Data: map[string]any{"violations": violations}},
+			},
+		},
+		Timestamp: &now,
+	}
+	for i, event := range events {
+		if e, ok := event.(a2a.TaskStatusUpdateEvent); ok && e.Status.State == a2a.TaskStateCompleted {
+			e.Status = task.Status
+			events[i] = e
+		}
+	}
+	LoggerFromContext(ctx).Warn("executor output does not match its skill's schema", "skill", skillID, LogKeyError, err)
+}
@@ -0,0 +1,159 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// dataExecutor completes its task with data as an artifact
+type dataExecutor struct {
+	data map[string]any
+}
+
+func (e *dataExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	artifact := a2a.TaskArtifactUpdateEvent{Artifact: a2a.Artifact{ArtifactID: "result", Parts: []a2a.Part{a2a.DataPart{Kind: "data", Data: e.data}}}}
+	if err := queue.Write(ctx, artifact); err != nil {
+		return err
+	}
+	return queue.Write(ctx, a2a.TaskStatusUpdateEvent{Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true})
+}
+
+func (e *dataExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+// decodeSchema reads a schema as the config would
+func decodeSchema(t *testing.T, schema string) map[string]any {
+	t.Helper()
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(schema), &decoded); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	return decoded
+}
+
+func TestValidateOutputSchemas(t *testing.T) {
+	skills := []a2a.AgentSkill{{ID: "invoice"}}
+	tests := []struct {
+		name   string
+		schema string
+		skill  string
+		paths  []string
+	}{
+		{name: "valid", skill: "invoice", schema: `{"type": "object", "properties": {"total": {"type": "number", "minimum": 0}, "lines": {"type": "array", "items": {"type": "string"}}}, "required": ["total"]}`},
+		{name: "unknown skill", skill: "quote", schema: `{"type": "object"}`, paths: []string{"quote"}},
+		{name: "unknown type", skill: "invoice", schema: `{"properties": {"total": {"type": "decimal"}}}`, paths: []string{"invoice.properties.total.type"}},
+		{name: "unsupported keyword", skill: "invoice", schema: `{"items": {"$ref": "#/defs/line"}}`, paths: []string{"invoice.items.$ref"}},
+		{name: "bad pattern", skill: "invoice", schema: `{"anyOf": [{"pattern": "("}]}`, paths: []string{"invoice.anyOf[0].pattern"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOutputSchemas(map[string]map[string]any{tt.skill: decodeSchema(t, tt.schema)}, skills)
+			if len(tt.paths) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var errs ValidationErrors
+			errors.As(err, &errs)
+			if len(errs) != len(tt.paths) {
+				t.Fatalf("expected errors at %v, got %v", tt.paths, err)
+			}
+			for i, path := range tt.paths {
+				if errs[i].Path != path {
+					t.Errorf("expected an error at %s, got %s", path, errs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"total": {"type": "number", "minimum": 0},
+			"count": {"type": "integer"},
+			"currency": {"enum": ["EUR", "USD"]},
+			"lines": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z]+$"}},
+			"id": {"oneOf": [{"type": "string"}, {"type": "integer"}]}
+		},
+		"required": ["total"],
+		"additionalProperties": false
+	}`
+	tests := []struct {
+		name  string
+		value string
+		paths []string
+	}{
+		{name: "valid", value: `{"total": 12.5, "count": 3, "currency": "EUR", "lines": ["tea"], "id": 7}`},
+		{name: "missing required", value: `{"count": 1}`, paths: []string{"total"}},
+		{name: "wrong types", value: `{"total": "12", "count": 1.5}`, paths: []string{"count", "total"}},
+		{name: "out of range", value: `{"total": -1, "currency": "GBP"}`, paths: []string{"currency", "total"}},
+		{name: "array limits", value: `{"total": 1, "lines": ["a", "B", "c"]}`, paths: []string{"lines", "lines[1]"}},
+		{name: "extra property", value: `{"total": 1, "note": "x"}`, paths: []string{"note"}},
+		{name: "no oneOf match", value: `{"total": 1, "id": true}`, paths: []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("invalid value: %v", err)
+			}
+			var errs ValidationErrors
+			checkOutput(decodeSchema(t, schema), value, "", &errs)
+			if len(errs) != len(tt.paths) {
+				t.Fatalf("expected errors at %v, got %v", tt.paths, errs)
+			}
+			for i, path := range tt.paths {
+				if errs[i].Path != path {
+					t.Errorf("expected an error at %s, got %s", path, errs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerChecksOutputSchema(t *testing.T) {
+	ctx := context.Background()
+	config := ServerlessConfig{OutputSchemas: map[string]map[string]any{
+		"invoice": decodeSchema(t, `{"type": "object", "properties": {"total": {"type": "number"}}, "required": ["total"]}`),
+	}}
+	send := func(data map[string]any, skillID string) (a2a.Task, *eventLog) {
+		t.Helper()
+		events := &eventLog{}
+		h := NewServerlessA2AHandler(config, &memoryStores{}, events, nil, WithExecutor(&dataExecutor{data: data}))
+		message := a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser, Metadata: map[string]any{SkillMetadataKey: skillID}}
+		result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: message})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.(a2a.Task), events
+	}
+
+	if task, _ := send(map[string]any{"total": 12}, "invoice"); task.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected matching output to complete the task, got %s", task.Status.State)
+	}
+	if task, _ := send(map[string]any{"sum": 12}, "summarize"); task.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected a skill without a schema to complete the task, got %s", task.Status.State)
+	}
+
+	task, events := send(map[string]any{"total": "twelve"}, "invoice")
+	if task.Status.State != a2a.TaskStateFailed || task.Status.Message == nil || len(task.Status.Message.Parts) != 2 {
+		t.Fatalf("expected the task failed with a diagnostic message, got %+v", task.Status)
+	}
+	violations, _ := task.Status.Message.Parts[1].(a2a.DataPart).Data["violations"].([]any)
+	if len(violations) != 1 || violations[0].(map[string]any)["path"] != "artifacts[0].parts[0].data.total" {
+		t.Errorf("expected the violation at the artifact's total, got %v", violations)
+	}
+	if len(task.Artifacts) != 1 {
+		t.Errorf("expected the artifact kept for diagnosis, got %+v", task.Artifacts)
+	}
+	final, _ := events.events[len(events.events)-1].(a2a.TaskStatusUpdateEvent)
+	if final.Status.State != a2a.TaskStateFailed || !final.Final {
+		t.Errorf("expected the final event to report the failure, got %+v", events.events)
+	}
+}
@@ -0,0 +1,148 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// DecodeParams decodes JSON-RPC params into target, rejecting members target
+// has no field for and values of the wrong type. Errors are ValidationErrors
+// naming the offending field, for the data of an invalid params error.
+func DecodeParams(params interface{}, target interface{}) error {
+	if params == nil {
+		return nil
+	}
+	// Decoded requests carry their params as sent; empty ones are none
+	if raw, ok := params.(json.RawMessage); ok {
+		if len(raw) == 0 {
+			return nil
+		}
+		return decodeStrict(raw, target)
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ValidationErrors{{Code: ValidationCodeInvalid, Message: fmt.Sprintf("params cannot be encoded: %v", err)}}
+	}
+	return decodeStrict(data, target)
+}
+
+// DecodeMessageSendParams decodes and validates message/send params,
+// including the message's parts
+func DecodeMessageSendParams(params interface{}) (a2a.MessageSendParams, error) {
+	var p struct {
+		a2a.MessageSendParams
+		Message messageJSON
+	}
+	if err := DecodeParams(params, &p); err != nil {
+		return a2a.MessageSendParams{}, err
+	}
+	sendParams := p.MessageSendParams
+	sendParams.Message = p.Message.message()
+	return sendParams, ValidateMessageSendParams(sendParams)
+}
+
+// decodeStrict is json.Unmarshal with DisallowUnknownFields, its errors
+// turned into field errors
+func decodeStrict(data []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(target)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return ValidationErrors{{Path: typeErr.Field, Code: ValidationCodeInvalid, Message: fmt.Sprintf("must be %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value)}}
+	}
+	// encoding/json reports unknown members only by name
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return ValidationErrors{{Path: strings.Trim(name, `"`), Code: ValidationCodeUnsupported, Message: "is not a known field"}}
+	}
+	return ValidationErrors{{Code: ValidationCodeInvalid, Message: err.Error()}}
+}
+
+// jsonTypeName names a Go type the way a JSON client would know it
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	default:
+		return "a number"
+	}
+}
+
+// ValidateMessageSendParams checks the message/send params the handler relies on
+func ValidateMessageSendParams(params a2a.MessageSendParams) error {
+	var errs ValidationErrors
+	message := params.Message
+	if message.MessageID == "" {
+		errs.Add("Message.MessageID", ValidationCodeRequired, "is required")
+	}
+	if message.Role != a2a.MessageRoleUser && message.Role != a2a.MessageRoleAgent {
+		errs.Add("Message.Role", ValidationCodeInvalid, fmt.Sprintf("must be %s or %s", a2a.MessageRoleUser, a2a.MessageRoleAgent))
+	}
+	if message.Kind != "" && message.Kind != "message" {
+		errs.Add("Message.Kind", ValidationCodeInvalid, "must be message")
+	}
+	if len(message.Parts) == 0 {
+		errs.Add("Message.Parts", ValidationCodeRequired, "must hold at least one part")
+	}
+	if config := params.Config; config != nil {
+		if config.HistoryLength != nil && *config.HistoryLength < 0 {
+			errs.Add("Config.HistoryLength", ValidationCodeInvalid, "must not be negative")
+		}
+		if config.PushConfig != nil && config.PushConfig.URL == "" {
+			errs.Add("Config.PushConfig.URL", ValidationCodeRequired, "is required")
+		}
+	}
+	if timeout, ok := params.Metadata[TimeoutMetadataKey]; ok {
+		// Unlike the config's, a timeout sent must be set
+		if value, _ := timeout.(string); value == "" {
+			errs.Add("Metadata."+TimeoutMetadataKey, ValidationCodeInvalid, "must be a positive duration such as 15m or 1d")
+		} else {
+			errs.Merge("Metadata."+TimeoutMetadataKey, ValidateTaskTimeout(value))
+		}
+	}
+	errs.Merge("Metadata", validateStart(params.Metadata))
+	if priority, ok := params.Metadata[PriorityMetadataKey]; ok {
+		value, _ := priority.(string)
+		errs.Merge("Metadata."+PriorityMetadataKey, ValidateTaskPriority(TaskPriority(value)))
+	}
+	return errs.Err()
+}
+
+// ValidateTaskQueryParams checks tasks/get params
+func ValidateTaskQueryParams(params a2a.TaskQueryParams) error {
+	var errs ValidationErrors
+	if params.ID == "" {
+		errs.Add("ID", ValidationCodeRequired, "is required")
+	}
+	if params.HistoryLength != nil && *params.HistoryLength < 0 {
+		errs.Add("HistoryLength", ValidationCodeInvalid, "must not be negative")
+	}
+	return errs.Err()
+}
+
+// ValidateTaskIDParams checks params that name a task, such as tasks/cancel
+func ValidateTaskIDParams(params a2a.TaskIDParams) error {
+	if params.ID == "" {
+		return ValidationErrors{{Path: "ID", Code: ValidationCodeRequired, Message: "is required"}}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package a2a
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// rawParams decodes params as the JSON-RPC request decoder leaves them
+func rawParams(t *testing.T, params string) interface{} {
+	t.Helper()
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(params), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestDecodeMessageSendParams(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     string
+		expectPath []string
+	}{
+		{name: "valid", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Config":{"HistoryLength":2}}`},
+		{name: "unknown member", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Configuration":{}}`, expectPath: []string{"Configuration"}},
+		{name: "unknown nested member", params: `{"Message":{"MessageId":"m-1","Role":"user","Sender":"me","Parts":[{"Kind":"text","Text":"hi"}]}}`, expectPath: []string{"Sender"}},
+		{name: "mistyped member", params: `{"Message":{"MessageID":7,"Role":"user","Parts":[{"Kind":"text","Text":"hi"}]}}`, expectPath: []string{"Message.MessageID"}},
+		{name: "missing message", params: `{}`, expectPath: []string{"Message.MessageID", "Message.Role", "Message.Parts"}},
+		{name: "unknown role", params: `{"Message":{"MessageID":"m-1","Role":"system","Parts":[{"Kind":"text","Text":"hi"}]}}`, expectPath: []string{"Message.Role"}},
+		{name: "negative history", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Config":{"HistoryLength":-1,"PushConfig":{}}}`, expectPath: []string{"Config.HistoryLength", "Config.PushConfig.URL"}},
+		{name: "invalid timeout", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Metadata":{"timeout":"soon"}}`, expectPath: []string{"Metadata.timeout"}},
+		{name: "timeout not a string", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Metadata":{"timeout":90}}`, expectPath: []string{"Metadata.timeout"}},
+		{name: "invalid delay", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Metadata":{"delay":"later"}}`, expectPath: []string{"Metadata.delay"}},
+		{name: "delay and start", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Metadata":{"delay":"90s","startAt":"tomorrow"}}`, expectPath: []string{"Metadata.startAt", "Metadata.startAt"}},
+		{name: "unknown priority", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Metadata":{"priority":"urgent"}}`, expectPath: []string{"Metadata.priority"}},
+		{name: "unknown part kind", params: `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"video"}]}}`, expectPath: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := DecodeMessageSendParams(rawParams(t, tt.params))
+			if len(tt.expectPath) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if params.Message.MessageID != "m-1" || len(params.Message.Parts) != 1 || *params.Config.HistoryLength != 2 {
+					t.Errorf("unexpected params: %+v", params)
+				}
+				return
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != len(tt.expectPath) {
+				t.Fatalf("expected %d field errors, got %v", len(tt.expectPath), err)
+			}
+			for i, path := range tt.expectPath {
+				if errs[i].Path != path {
+					t.Errorf("expected error %d at %q, got %q", i, path, errs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeParams(t *testing.T) {
+	var query a2a.TaskQueryParams
+	if err := DecodeParams(rawParams(t, `{"ID":"task-1","HistoryLength":3}`), &query); err != nil || query.ID != "task-1" {
+		t.Fatalf("unexpected result %+v: %v", query, err)
+	}
+	if err := DecodeParams(nil, &query); err != nil {
+		t.Errorf("expected absent params to decode, got %v", err)
+	}
+
+	// Params of a decoded request arrive as they were sent
+	var request JSONRPCRequest
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":{"ID":"task-2"}}`), &request); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := request.Params.(json.RawMessage); !ok {
+		t.Fatalf("expected raw params, got %T", request.Params)
+	}
+	if err := DecodeParams(request.Params, &query); err != nil || query.ID != "task-2" {
+		t.Errorf("unexpected result %+v: %v", query, err)
+	}
+
+	err := DecodeParams(rawParams(t, `{"ID":["task-1"]}`), &query)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || errs[0].Path != "ID" || errs[0].Message != "must be a string, got array" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := ValidateTaskQueryParams(a2a.TaskQueryParams{}); err == nil {
+		t.Error("expected an error for a query without an ID")
+	}
+	if err := ValidateTaskIDParams(a2a.TaskIDParams{}); err == nil {
+		t.Error("expected an error for params without an ID")
+	}
+}
@@ -0,0 +1,186 @@
+package a2a
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// The SDK's a2a.Part is an interface, so encoding/json cannot decode messages,
+// tasks or artifacts that carry parts. The shadow types below decode parts by
+// their Kind discriminator and otherwise defer to the SDK structs; an outer
+// field hides the embedded one of the same name.
+
+type partsJSON []a2a.Part
+
+func (p *partsJSON) UnmarshalJSON(data []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	parts := make([]a2a.Part, 0, len(raws))
+	for i, raw := range raws {
+		part, err := UnmarshalPart(raw)
+		if err != nil {
+			return fmt.Errorf("part %d: %w", i, err)
+		}
+		parts = append(parts, part)
+	}
+	*p = parts
+	return nil
+}
+
+type messageJSON struct {
+	a2a.Message
+	Parts partsJSON
+}
+
+func (m messageJSON) message() a2a.Message {
+	message := m.Message
+	message.Parts = m.Parts
+	return message
+}
+
+type artifactJSON struct {
+	a2a.Artifact
+	Parts partsJSON
+}
+
+type taskJSON struct {
+	a2a.Task
+	Artifacts []artifactJSON
+	History   []messageJSON
+	Status    struct {
+		a2a.TaskStatus
+		Message *messageJSON
+	}
+}
+
+// UnmarshalPart decodes a part into the SDK type named by its Kind
+func UnmarshalPart(data []byte) (a2a.Part, error) {
+	var kind struct {
+		Kind string
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+
+	switch kind.Kind {
+	case "text":
+		var part a2a.TextPart
+		err := json.Unmarshal(data, &part)
+		return part, err
+	case "data":
+		var part a2a.DataPart
+		err := json.Unmarshal(data, &part)
+		return part, err
+	case "file":
+		var part a2a.FilePart
+		err := json.Unmarshal(data, &part)
+		return part, err
+	default:
+		return nil, fmt.Errorf("unknown part kind %q", kind.Kind)
+	}
+}
+
+// UnmarshalMessage decodes a message including its parts
+func UnmarshalMessage(data []byte) (a2a.Message, error) {
+	var m messageJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return a2a.Message{}, err
+	}
+	return m.message(), nil
+}
+
+// UnmarshalTask decodes a task including the parts of its history, status
+// message and artifacts
+func UnmarshalTask(data []byte) (a2a.Task, error) {
+	var t taskJSON
+	if err := json.Unmarshal(data, &t); err != nil {
+		return a2a.Task{}, err
+	}
+
+	task := t.Task
+	task.Status = t.Status.TaskStatus
+	if t.Status.Message != nil {
+		message := t.Status.Message.message()
+		task.Status.Message = &message
+	}
+	task.History = nil
+	for _, m := range t.History {
+		task.History = append(task.History, m.message())
+	}
+	task.Artifacts = nil
+	for _, a := range t.Artifacts {
+		artifact := a.Artifact
+		artifact.Parts = a.Parts
+		task.Artifacts = append(task.Artifacts, artifact)
+	}
+	return task, nil
+}
+
+// UnmarshalEvent decodes a streamed event into the SDK type named by its
+// Kind, including the parts of any message or artifact it carries
+func UnmarshalEvent(data []byte) (a2a.Event, error) {
+	var kind struct {
+		Kind string
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return nil, err
+	}
+
+	switch kind.Kind {
+	case "task":
+		return UnmarshalTask(data)
+	case "message":
+		return UnmarshalMessage(data)
+	case "status-update":
+		var e struct {
+			a2a.TaskStatusUpdateEvent
+			Status struct {
+				a2a.TaskStatus
+				Message *messageJSON
+			}
+		}
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		event := e.TaskStatusUpdateEvent
+		event.Status = e.Status.TaskStatus
+		if e.Status.Message != nil {
+			message := e.Status.Message.message()
+			event.Status.Message = &message
+		}
+		return event, nil
+	case "artifact-update":
+		var e struct {
+			a2a.TaskArtifactUpdateEvent
+			Artifact artifactJSON
+		}
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+		event := e.TaskArtifactUpdateEvent
+		event.Artifact = e.Artifact.Artifact
+		event.Artifact.Parts = e.Artifact.Parts
+		return event, nil
+	default:
+		return nil, fmt.Errorf("unknown event kind %q", kind.Kind)
+	}
+}
+
+// UnmarshalMessageSendParams decodes message/send params including the
+// message's parts
+func UnmarshalMessageSendParams(data []byte) (a2a.MessageSendParams, error) {
+	var p struct {
+		a2a.MessageSendParams
+		Message messageJSON
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return a2a.MessageSendParams{}, err
+	}
+	params := p.MessageSendParams
+	params.Message = p.Message.message()
+	return params, nil
+}
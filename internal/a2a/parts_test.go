@@ -0,0 +1,105 @@
+package a2a
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestUnmarshalTaskParts(t *testing.T) {
+	mimeType := "text/plain"
+	message := a2a.Message{
+		Kind:      "message",
+		MessageID: "msg-1",
+		Role:      a2a.MessageRoleUser,
+		Parts: []a2a.Part{
+			a2a.TextPart{Kind: "text", Text: "hello"},
+			a2a.DataPart{Kind: "data", Data: map[string]any{"n": float64(1)}},
+			a2a.FilePart{Kind: "file", File: a2a.FilePartFile{URI: "https://example.com/a.txt", MimeType: &mimeType}},
+		},
+	}
+	task := a2a.Task{
+		ID:        "task-1",
+		Kind:      "task",
+		History:   []a2a.Message{message},
+		Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted, Message: &message},
+		Artifacts: []a2a.Artifact{{ArtifactID: "a-1", Parts: message.Parts}},
+	}
+
+	data, _ := json.Marshal(task)
+	got, err := UnmarshalTask(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, task) {
+		t.Errorf("expected %+v, got %+v", task, got)
+	}
+
+	data, _ = json.Marshal(a2a.MessageSendParams{Message: message, Metadata: map[string]any{"k": "v"}})
+	params, err := UnmarshalMessageSendParams(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(params.Message, message) || params.Metadata["k"] != "v" {
+		t.Errorf("unexpected params %+v", params)
+	}
+}
+
+func TestUnmarshalEvent(t *testing.T) {
+	message := a2a.Message{Kind: "message", MessageID: "msg-1", Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "working"}}}
+	events := []a2a.Event{
+		a2a.Task{ID: "task-1", Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateSubmitted}},
+		message,
+		a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking, Message: &message}},
+		a2a.TaskArtifactUpdateEvent{Kind: "artifact-update", TaskID: "task-1", Artifact: a2a.Artifact{ArtifactID: "a-1", Parts: message.Parts}},
+	}
+	for _, event := range events {
+		data, _ := json.Marshal(event)
+		got, err := UnmarshalEvent(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, event) {
+			t.Errorf("expected %+v, got %+v", event, got)
+		}
+	}
+	if _, err := UnmarshalEvent([]byte(`{"Kind":"heartbeat"}`)); err == nil {
+		t.Error("expected an unknown kind to be refused")
+	}
+}
+
+func TestUnmarshalPartUnknownKind(t *testing.T) {
+	if _, err := UnmarshalMessage([]byte(`{"Parts":[{"Kind":"video"}]}`)); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func BenchmarkUnmarshalMessageSendParams(b *testing.B) {
+	data := []byte(`{"Message":{"Kind":"message","MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hello"},{"Kind":"data","Data":{"n":1}}]}}`)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := UnmarshalMessageSendParams(data); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func FuzzUnmarshalMessageSendParams(f *testing.F) {
+	f.Add([]byte(`{"Message":{"Kind":"message","MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hello"}]}}`))
+	f.Add([]byte(`{"Message":{"Parts":[{"Kind":"file","File":{"URI":"https://example.com/a.txt"}},{"Kind":"data","Data":{"n":1}}]}}`))
+	f.Add([]byte(`{"Message":{"Parts":[null,{"Kind":"unknown"}]}}`))
+	f.Add([]byte(`{"Configuration":{"HistoryLength":-1},"Metadata":null}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		params, err := UnmarshalMessageSendParams(data)
+		if err != nil {
+			return
+		}
+		for i, part := range params.Message.Parts {
+			if part == nil {
+				t.Fatalf("part %d decoded as nil from %q", i, data)
+			}
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// TaskPriority ranks queued work, so urgent interactive messages are not
+// stuck behind batch ones
+type TaskPriority string
+
+const (
+	PriorityHigh   TaskPriority = "high"
+	PriorityNormal TaskPriority = "normal"
+	PriorityLow    TaskPriority = "low"
+)
+
+// PriorityMetadataKey is the message/send params metadata entry setting the
+// call's priority, overriding its skill's
+const PriorityMetadataKey = "priority"
+
+// taskPriorities are the priorities in order, for validation messages
+var taskPriorities = []TaskPriority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// ValidateTaskPriority checks that a priority is high, normal or low
+func ValidateTaskPriority(priority TaskPriority) error {
+	var errs ValidationErrors
+	if !slices.Contains(taskPriorities, priority) {
+		errs.Add("", ValidationCodeInvalid, fmt.Sprintf("'%s' must be one of high, normal or low", priority))
+	}
+	return errs.Err()
+}
+
+// parseSkillPriorities decodes the JSON object in A2A_SKILL_PRIORITIES
+func parseSkillPriorities(value string) (map[string]TaskPriority, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var priorities map[string]TaskPriority
+	if err := json.Unmarshal([]byte(value), &priorities); err != nil {
+		return nil, fmt.Errorf("A2A_SKILL_PRIORITIES must be a JSON object of priorities by skill ID: %w", err)
+	}
+	return priorities, nil
+}
+
+// ValidateSkillPriorities checks each priority is valid and keyed by one of
+// skills
+func ValidateSkillPriorities(priorities map[string]TaskPriority, skills []a2a.AgentSkill) error {
+	var errs ValidationErrors
+	for _, skillID := range slices.Sorted(maps.Keys(priorities)) {
+		if !hasSkill(skills, skillID) {
+			errs.Add(skillID, ValidationCodeInvalid, fmt.Sprintf("'%s' is not a skill of the agent card", skillID))
+		}
+		errs.Merge(skillID, ValidateTaskPriority(priorities[skillID]))
+	}
+	return errs.Err()
+}
+
+// messagePriority returns the priority a message was sent at, else its
+// skill's, else normal
+func (h *ServerlessA2AHandler) messagePriority(params a2a.MessageSendParams) TaskPriority {
+	if priority, _ := params.Metadata[PriorityMetadataKey].(string); priority != "" {
+		return TaskPriority(priority)
+	}
+	skillID, _ := params.Message.Metadata[SkillMetadataKey].(string)
+	if priority, ok := h.config.Priorities[skillID]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// PriorityQueues is a MessageQueue routing each message to the queue of its
+// priority, so each can be consumed with its own concurrency
+type PriorityQueues struct {
+	queues map[TaskPriority]MessageQueue
+}
+
+// NewPriorityQueues routes messages by priority. A nil high or low queue
+// takes those messages to the normal one.
+func NewPriorityQueues(high, normal, low MessageQueue) *PriorityQueues {
+	queues := map[TaskPriority]MessageQueue{PriorityHigh: high, PriorityNormal: normal, PriorityLow: low}
+	for priority, queue := range queues {
+		if queue == nil {
+			queues[priority] = normal
+		}
+	}
+	return &PriorityQueues{queues: queues}
+}
+
+// QueueMessage implements MessageQueue
+func (q *PriorityQueues) QueueMessage(ctx context.Context, params a2a.MessageSendParams, delay time.Duration) error {
+	priority, _ := params.Metadata[PriorityMetadataKey].(string)
+	queue, ok := q.queues[TaskPriority(priority)]
+	if !ok {
+		queue = q.queues[PriorityNormal]
+	}
+	return queue.QueueMessage(ctx, params, delay)
+}
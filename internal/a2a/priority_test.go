@@ -0,0 +1,77 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestHandlerQueuesWorkByPriority(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	high, normal := &deadLetterSQS{}, &deadLetterSQS{}
+	queues := NewPriorityQueues(NewSQSMessageQueue(high, "high"), NewSQSMessageQueue(normal, "normal"), nil)
+	config := ServerlessConfig{Priorities: map[string]TaskPriority{"report": PriorityLow}}
+	h := NewServerlessA2AHandler(config, stores, stores, nil, WithWorkQueue(queues))
+
+	send := func(params a2a.MessageSendParams) a2a.Task {
+		t.Helper()
+		params.Message.MessageID, params.Message.Role = "m-1", a2a.MessageRoleUser
+		result, err := h.OnSendMessage(ctx, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.(a2a.Task)
+	}
+	urgent := send(a2a.MessageSendParams{Metadata: map[string]any{PriorityMetadataKey: "high"}})
+	// A low priority skill goes to the normal queue, as there is no low one
+	send(a2a.MessageSendParams{Message: a2a.Message{Metadata: map[string]any{SkillMetadataKey: "report"}}})
+	send(a2a.MessageSendParams{})
+
+	if len(high.sent) != 1 || len(normal.sent) != 2 {
+		t.Fatalf("expected 1 high and 2 normal messages, got %d and %d", len(high.sent), len(normal.sent))
+	}
+	var queued a2a.MessageSendParams
+	if err := json.Unmarshal([]byte(*high.sent[0].MessageBody), &queued); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued.Message.TaskID == nil || *queued.Message.TaskID != urgent.ID || queued.Metadata[PriorityMetadataKey] != "high" {
+		t.Errorf("expected the message queued on its task at high priority, got %+v", queued)
+	}
+
+	// A worker with an executor runs the queued message on its task
+	stores.tasks = stores.tasks[:1]
+	executor := &failingExecutor{}
+	worker := NewServerlessA2AHandler(config, stores, stores, nil, WithExecutor(executor), WithWorkQueue(queues))
+	if err := worker.ProcessQueuedMessage(ctx, *high.sent[0].MessageBody); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := stores.tasks[len(stores.tasks)-1]
+	if executor.calls != 1 || task.ID != urgent.ID || len(task.History) != 1 || len(high.sent) != 1 {
+		t.Errorf("expected one execution of the queued task and nothing queued again, got %d and %+v", executor.calls, task)
+	}
+	if err := worker.ProcessQueuedMessage(ctx, `{"message": {}}`); err == nil {
+		t.Error("expected an error for a message without a task")
+	}
+
+	// A failed queue fails the call, so the message is not silently lost
+	failing := NewServerlessA2AHandler(config, stores, stores, nil, WithWorkQueue(NewSQSMessageQueue(&deadLetterSQS{sendErr: errors.New("throttled")}, "normal")))
+	if _, err := failing.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-2", Role: a2a.MessageRoleUser}}); err == nil {
+		t.Error("expected an error when the message cannot be queued")
+	}
+}
+
+func TestValidateSkillPriorities(t *testing.T) {
+	skills := []a2a.AgentSkill{{ID: "report"}}
+	if err := ValidateSkillPriorities(map[string]TaskPriority{"report": PriorityLow}, skills); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var errs ValidationErrors
+	errors.As(ValidateSkillPriorities(map[string]TaskPriority{"chat": PriorityHigh, "report": "urgent"}, skills), &errs)
+	if len(errs) != 2 || errs[0].Path != "chat" || errs[1].Path != "report" {
+		t.Errorf("expected errors at chat and report, got %v", errs)
+	}
+}
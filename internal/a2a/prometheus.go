@@ -0,0 +1,104 @@
+package a2a
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusMetrics keeps the same counters and latencies as the EMF path in
+// a Prometheus registry, for hosts that stay up long enough to be scraped.
+// Unlike EMFMetrics it lives for the whole process, so create one at startup.
+type PrometheusMetrics struct {
+	registry       *prometheus.Registry
+	requests       *prometheus.CounterVec
+	errors         *prometheus.CounterVec
+	transitions    *prometheus.CounterVec
+	storageLatency *prometheus.HistogramVec
+	notifications  *prometheus.CounterVec
+	initDuration   *prometheus.GaugeVec
+	purged         *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics creates a recorder with its own registry, so tests
+// and several agents in one process never collide on the global one
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "a2a_requests_total",
+			Help: "JSON-RPC requests by method.",
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "a2a_errors_total",
+			Help: "JSON-RPC error responses by method and error code.",
+		}, []string{"method", "code"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "a2a_task_state_transitions_total",
+			Help: "Task state transitions.",
+		}, []string{"from_state", "to_state"}),
+		storageLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "a2a_storage_latency_seconds",
+			Help:    "Storage operation latency.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "a2a_notification_deliveries_total",
+			Help: "Push notification delivery attempts by outcome.",
+		}, []string{"outcome"}),
+		initDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "a2a_init_duration_seconds",
+			Help: "Duration of each startup phase.",
+		}, []string{"phase"}),
+		purged: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "a2a_retention_purged_total",
+			Help: "Items deleted by the retention sweeper by kind.",
+		}, []string{"kind"}),
+	}
+	m.registry.MustRegister(m.requests, m.errors, m.transitions, m.storageLatency, m.notifications, m.initDuration, m.purged)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format; mount it at /metrics
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RecordRequest implements Metrics
+func (m *PrometheusMetrics) RecordRequest(method string) {
+	m.requests.WithLabelValues(method).Inc()
+}
+
+// RecordError implements Metrics
+func (m *PrometheusMetrics) RecordError(method string, code int) {
+	m.errors.WithLabelValues(method, strconv.Itoa(code)).Inc()
+}
+
+// RecordTaskTransition implements Metrics
+func (m *PrometheusMetrics) RecordTaskTransition(from, to a2a.TaskState) {
+	m.transitions.WithLabelValues(taskStateLabel(from), taskStateLabel(to)).Inc()
+}
+
+// RecordStorageLatency implements Metrics
+func (m *PrometheusMetrics) RecordStorageLatency(operation string, latency time.Duration) {
+	m.storageLatency.WithLabelValues(operation).Observe(latency.Seconds())
+}
+
+// RecordNotification implements Metrics
+func (m *PrometheusMetrics) RecordNotification(outcome string) {
+	m.notifications.WithLabelValues(outcome).Inc()
+}
+
+// RecordInitDuration implements Metrics
+func (m *PrometheusMetrics) RecordInitDuration(phase string, duration time.Duration) {
+	m.initDuration.WithLabelValues(phase).Set(duration.Seconds())
+}
+
+// RecordRetentionPurge implements Metrics
+func (m *PrometheusMetrics) RecordRetentionPurge(kind string, count int) {
+	m.purged.WithLabelValues(kind).Add(float64(count))
+}
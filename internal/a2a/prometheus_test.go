@@ -0,0 +1,39 @@
+package a2a
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestPrometheusMetricsHandler(t *testing.T) {
+	metrics := NewPrometheusMetrics()
+	metrics.RecordRequest("tasks/get")
+	metrics.RecordRequest("tasks/get")
+	metrics.RecordError("tasks/get", -32000)
+	metrics.RecordTaskTransition("", a2a.TaskStateSubmitted)
+	metrics.RecordStorageLatency("GetTask", 3*time.Millisecond)
+	metrics.RecordNotification(NotificationOutcomeFailed)
+	metrics.RecordInitDuration(InitPhaseTotal, 250*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	for _, want := range []string{
+		`a2a_requests_total{method="tasks/get"} 2`,
+		`a2a_errors_total{code="-32000",method="tasks/get"} 1`,
+		`a2a_task_state_transitions_total{from_state="none",to_state="submitted"} 1`,
+		`a2a_storage_latency_seconds_count{operation="GetTask"} 1`,
+		`a2a_notification_deliveries_total{outcome="failed"} 1`,
+		`a2a_init_duration_seconds{phase="total"} 0.25`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected %q in scrape, got:\n%s", want, body)
+		}
+	}
+}
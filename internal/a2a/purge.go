@@ -0,0 +1,318 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// ErrPurgeUnsupported is returned when a store cannot delete what a purge
+// asks of it
+var ErrPurgeUnsupported = errors.New("the store cannot purge")
+
+// EventPurger is an event store that can delete a task's events
+type EventPurger interface {
+	DeleteEvents(ctx context.Context, taskID a2a.TaskID) (int, error)
+}
+
+// AuditPurger is an audit log whose records can be deleted. Records streamed
+// to Firehose are erased where they are delivered instead.
+type AuditPurger interface {
+	AuditReader
+	DeleteAudit(ctx context.Context, records []AuditRecord) error
+}
+
+// CapturePurger is a capture store whose captures can be deleted by
+// request ID
+type CapturePurger interface {
+	DeleteCapture(ctx context.Context, requestID string) error
+}
+
+// DeliveryPurger is a delivery log whose attempts can be deleted by task
+type DeliveryPurger interface {
+	DeleteDeliveries(ctx context.Context, taskID a2a.TaskID) (int, error)
+}
+
+// PurgeStores are the stores a purge erases from besides the handler's
+// own; each is nil when not configured
+type PurgeStores struct {
+	AuditLog   AuditLog
+	Captures   CaptureStore
+	Deliveries DeliveryLog
+}
+
+// PurgeQuery selects what the admin purge method erases: every task of a
+// context, or every task a subject owns along with the subject's audit
+// records. Exactly one is set.
+type PurgeQuery struct {
+	ContextID string `json:"context_id,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+}
+
+// PurgeReport records what a purge deleted
+type PurgeReport struct {
+	Tasks []a2a.TaskID `json:"tasks"`
+	// Artifacts counts the artifacts of the deleted tasks; artifact content
+	// lives only in tasks and their events, so it went with them
+	Artifacts    int `json:"artifacts"`
+	Events       int `json:"events"`
+	AuditRecords int `json:"audit_records"`
+	// PushConfigs counts the push notification configs of the tasks, which
+	// are only stored with a push config store
+	PushConfigs int `json:"push_configs"`
+	// Deliveries counts the recorded webhook delivery attempts of the tasks
+	Deliveries int `json:"deliveries"`
+	// Skipped names data the purge could not reach, for erasing elsewhere
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// Purge deletes everything stored about a context or a subject, for
+// erasure requests: the tasks, their events, push configs and delivery
+// attempts and archived copies, the audit records of the tasks and of the
+// subject, and the captures of the audited requests. A subject's tasks are found through the
+// audit log, which must then be queryable, and are the tasks whose owner is
+// the subject. Each task is deleted last, after everything else about it,
+// so a purge that fails part way can be run again. What cannot be reached
+// is named in the report's Skipped.
+func (h *ServerlessA2AHandler) Purge(ctx context.Context, query PurgeQuery, stores PurgeStores) (PurgeReport, error) {
+	report := PurgeReport{Tasks: []a2a.TaskID{}}
+	events, ok := h.eventStore.(EventPurger)
+	if !ok {
+		return report, fmt.Errorf("events: %w", ErrPurgeUnsupported)
+	}
+	audit, _ := stores.AuditLog.(AuditPurger)
+	if stores.AuditLog != nil && audit == nil {
+		report.Skipped = append(report.Skipped, "audit records streamed to Firehose")
+	}
+	// Captures are keyed by request, so only the audited requests are found
+	captures, _ := stores.Captures.(CapturePurger)
+	if stores.Captures != nil && (captures == nil || audit == nil) {
+		report.Skipped = append(report.Skipped, "request captures, which expire after A2A_CAPTURE_TTL")
+	}
+	deliveries, _ := stores.Deliveries.(DeliveryPurger)
+	if stores.Deliveries != nil && deliveries == nil {
+		report.Skipped = append(report.Skipped, "webhook delivery attempts, which expire after A2A_DELIVERY_TTL")
+	}
+	// Deduplication keys name the sender's message IDs, which a purge cannot
+	// rebuild from what is stored
+	if h.dedup != nil {
+		report.Skipped = append(report.Skipped, "remembered message IDs and replies, which expire after A2A_DEDUP_WINDOW")
+	}
+	p := purge{tasks: h.taskStore, events: events, audit: audit, captures: captures, deliveries: deliveries, pushConfigs: h.pushConfigs, report: &report}
+	if store, ok := h.taskStore.(ArchivedTaskStore); ok {
+		p.archive = store.Archive()
+	}
+
+	switch {
+	case query.ContextID != "":
+		// The archive is keyed by task, so a context's tasks archived and
+		// not since restored are not listed
+		if p.archive != nil {
+			report.Skipped = append(report.Skipped, "archived tasks of the context that were not restored, under A2A_ARCHIVE_S3_URI")
+		}
+		return report, p.context(ctx, query.ContextID)
+	case query.Subject != "":
+		if audit == nil {
+			return report, fmt.Errorf("finding a subject's tasks needs a queryable audit log: %w", ErrPurgeUnsupported)
+		}
+		return report, p.subject(ctx, query.Subject)
+	}
+	return report, errors.New("context_id or subject is required")
+}
+
+// purge is one run of Purge
+type purge struct {
+	tasks  TaskStore
+	events EventPurger
+	// audit is nil without a queryable audit log
+	audit AuditPurger
+	// captures and deliveries are nil when not configured or not deletable
+	captures   CapturePurger
+	deliveries DeliveryPurger
+	// archive is nil unless the task store restores tasks from one
+	archive TaskArchive
+	// pushConfigs is nil without a push config store
+	pushConfigs PushConfigStore
+	report      *PurgeReport
+}
+
+// context purges every task of a context. The listing is repeated until it
+// comes back with nothing new, since a store may return one page at a time.
+func (p *purge) context(ctx context.Context, contextID string) error {
+	purged := make(map[a2a.TaskID]bool)
+	for {
+		tasks, err := p.tasks.ListTasks(ctx, contextID)
+		if err != nil {
+			return fmt.Errorf("failed to list tasks of context %s: %w", contextID, err)
+		}
+		progressed := false
+		for _, task := range tasks {
+			if purged[task.ID] {
+				continue
+			}
+			purged[task.ID], progressed = true, true
+			if err := p.task(ctx, task); err != nil {
+				return err
+			}
+		}
+		if !progressed {
+			return nil
+		}
+	}
+}
+
+// subject purges the tasks the subject owns, then the subject's remaining
+// audit records: those of tasks it does not own and of calls without a
+// task. The listing is repeated until it comes back with nothing new.
+func (p *purge) subject(ctx context.Context, subject string) error {
+	// owned holds whether the subject owns each task its records name
+	owned := make(map[string]bool)
+	seen := make(map[string]bool)
+	for {
+		records, err := p.audit.ListAudit(ctx, AuditQuery{Subject: subject, Limit: MaxAuditLimit})
+		if err != nil {
+			return fmt.Errorf("failed to list audit records: %w", err)
+		}
+		var remaining []AuditRecord
+		progressed := false
+		for _, record := range records {
+			key := auditRecordKey(record)
+			if seen[key] {
+				continue
+			}
+			seen[key], progressed = true, true
+			if record.TaskID == "" {
+				remaining = append(remaining, record)
+				continue
+			}
+			isOwner, checked := owned[record.TaskID]
+			if !checked {
+				if isOwner, err = p.ownedTask(ctx, a2a.TaskID(record.TaskID), subject); err != nil {
+					return err
+				}
+				owned[record.TaskID] = isOwner
+			}
+			// Purging an owned task deleted this record with the rest of its audit
+			if !isOwner {
+				remaining = append(remaining, record)
+			}
+		}
+		if !progressed {
+			return nil
+		}
+		if err := p.deleteAudit(ctx, remaining); err != nil {
+			return err
+		}
+	}
+}
+
+// ownedTask purges a task if subject owns it, reporting whether it did. A
+// task already gone is not the subject's to purge.
+func (p *purge) ownedTask(ctx context.Context, taskID a2a.TaskID, subject string) (bool, error) {
+	task, err := p.tasks.GetTask(ctx, taskID)
+	if errors.Is(err, a2a.ErrTaskNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	if owner, _ := task.Metadata[TaskOwnerMetadataKey].(string); owner != subject {
+		return false, nil
+	}
+	return true, p.task(ctx, task)
+}
+
+// task purges one task: its events, its push notification configs and
+// delivery attempts, its audit records, its archived copy, then the task
+func (p *purge) task(ctx context.Context, task a2a.Task) error {
+	deleted, err := p.events.DeleteEvents(ctx, task.ID)
+	p.report.Events += deleted
+	if err != nil {
+		return fmt.Errorf("failed to delete events of task %s: %w", task.ID, err)
+	}
+
+	if p.pushConfigs != nil {
+		configs, err := p.pushConfigs.ListPushConfigs(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		for _, config := range configs {
+			if err := p.pushConfigs.DeletePushConfig(ctx, task.ID, pushConfigID(task.ID, config.Config.ID)); err != nil {
+				return err
+			}
+			p.report.PushConfigs++
+		}
+	}
+
+	if p.deliveries != nil {
+		deleted, err := p.deliveries.DeleteDeliveries(ctx, task.ID)
+		p.report.Deliveries += deleted
+		if err != nil {
+			return fmt.Errorf("failed to delete delivery attempts of task %s: %w", task.ID, err)
+		}
+	}
+
+	if p.audit != nil {
+		seen := make(map[string]bool)
+		for {
+			records, err := p.audit.ListAudit(ctx, AuditQuery{TaskID: string(task.ID), Limit: MaxAuditLimit})
+			if err != nil {
+				return fmt.Errorf("failed to list audit records of task %s: %w", task.ID, err)
+			}
+			var fresh []AuditRecord
+			for _, record := range records {
+				if key := auditRecordKey(record); !seen[key] {
+					seen[key] = true
+					fresh = append(fresh, record)
+				}
+			}
+			if len(fresh) == 0 {
+				break
+			}
+			if err := p.deleteAudit(ctx, fresh); err != nil {
+				return fmt.Errorf("failed to delete audit records of task %s: %w", task.ID, err)
+			}
+		}
+	}
+
+	if p.archive != nil {
+		if err := p.archive.DeleteArchivedTask(ctx, task.ID); err != nil {
+			return fmt.Errorf("failed to delete archived task %s: %w", task.ID, err)
+		}
+	}
+
+	if err := p.tasks.DeleteTask(ctx, task.ID); err != nil {
+		return fmt.Errorf("failed to delete task %s: %w", task.ID, err)
+	}
+	p.report.Tasks = append(p.report.Tasks, task.ID)
+	p.report.Artifacts += len(task.Artifacts)
+	return nil
+}
+
+// deleteAudit deletes audit records after any captures of their requests,
+// which can only be found through them
+func (p *purge) deleteAudit(ctx context.Context, records []AuditRecord) error {
+	if p.captures != nil {
+		for _, record := range records {
+			if record.RequestID == "" {
+				continue
+			}
+			if err := p.captures.DeleteCapture(ctx, record.RequestID); err != nil {
+				return fmt.Errorf("failed to delete capture of request %s: %w", record.RequestID, err)
+			}
+		}
+	}
+	if err := p.audit.DeleteAudit(ctx, records); err != nil {
+		return err
+	}
+	p.report.AuditRecords += len(records)
+	return nil
+}
+
+// auditRecordKey identifies an audit record, as its table key does
+func auditRecordKey(record AuditRecord) string {
+	return record.TaskID + "\x00" + record.Timestamp.UTC().Format(time.RFC3339Nano) + "#" + record.RequestID
+}
@@ -0,0 +1,382 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// memoryAuditPurger keeps audit records in a slice, lists them by task or
+// subject and deletes them by key
+type memoryAuditPurger struct {
+	records []AuditRecord
+}
+
+func (l *memoryAuditPurger) RecordAudit(ctx context.Context, record AuditRecord) error {
+	l.records = append(l.records, record)
+	return nil
+}
+
+func (l *memoryAuditPurger) ListAudit(ctx context.Context, query AuditQuery) ([]AuditRecord, error) {
+	var records []AuditRecord
+	for _, record := range l.records {
+		if (query.TaskID != "" && record.TaskID == query.TaskID) || (query.Subject != "" && record.Subject == query.Subject) {
+			records = append(records, record)
+		}
+	}
+	return records[:min(len(records), query.Limit)], nil
+}
+
+func (l *memoryAuditPurger) DeleteAudit(ctx context.Context, records []AuditRecord) error {
+	l.records = slices.DeleteFunc(l.records, func(record AuditRecord) bool {
+		return slices.ContainsFunc(records, func(deleted AuditRecord) bool { return auditRecordKey(deleted) == auditRecordKey(record) })
+	})
+	return nil
+}
+
+// memoryStores keeps tasks in the order they were saved and events in a
+// slice. pkg/a2atest has fuller fakes, but it imports this package.
+type memoryStores struct {
+	tasks  []a2a.Task
+	events []a2a.TaskStatusUpdateEvent
+}
+
+func (s *memoryStores) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	for _, task := range s.tasks {
+		if task.ID == taskID {
+			return task, nil
+		}
+	}
+	return a2a.Task{}, a2a.ErrTaskNotFound
+}
+
+func (s *memoryStores) SaveTask(ctx context.Context, task a2a.Task) error {
+	s.tasks = append(s.tasks, task)
+	return nil
+}
+
+func (s *memoryStores) DeleteTask(ctx context.Context, taskID a2a.TaskID) error {
+	s.tasks = slices.DeleteFunc(s.tasks, func(task a2a.Task) bool { return task.ID == taskID })
+	return nil
+}
+
+func (s *memoryStores) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	var tasks []a2a.Task
+	for _, task := range s.tasks {
+		if task.ContextID == contextID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *memoryStores) SaveEvent(ctx context.Context, event a2a.Event) error {
+	s.events = append(s.events, event.(a2a.TaskStatusUpdateEvent))
+	return nil
+}
+
+func (s *memoryStores) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	var events []a2a.Event
+	for _, event := range s.events {
+		if event.TaskID == taskID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (s *memoryStores) MarkEventProcessed(ctx context.Context, eventID string) error {
+	return nil
+}
+
+func (s *memoryStores) DeleteEvents(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	before := len(s.events)
+	s.events = slices.DeleteFunc(s.events, func(event a2a.TaskStatusUpdateEvent) bool { return event.TaskID == taskID })
+	return before - len(s.events), nil
+}
+
+// taskIDs lists the IDs of the tasks left
+func (s *memoryStores) taskIDs() []a2a.TaskID {
+	var ids []a2a.TaskID
+	for _, task := range s.tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// purgeFixture stores three tasks: alice owns task-1 in ctx-1 and task-3 in
+// ctx-2, and bob owns task-2 in ctx-1, which alice also called
+func purgeFixture(t *testing.T) (*memoryStores, *memoryAuditPurger, *ServerlessA2AHandler) {
+	t.Helper()
+	ctx := context.Background()
+	stores := &memoryStores{}
+	for _, task := range []struct {
+		id               a2a.TaskID
+		contextID, owner string
+	}{{"task-1", "ctx-1", "alice"}, {"task-2", "ctx-1", "bob"}, {"task-3", "ctx-2", "alice"}} {
+		stores.SaveTask(ctx, a2a.Task{ID: task.id, ContextID: task.contextID, Metadata: map[string]any{TaskOwnerMetadataKey: task.owner}, Artifacts: []a2a.Artifact{{ArtifactID: "report"}}})
+	}
+	for _, id := range []a2a.TaskID{"task-1", "task-1", "task-2", "task-3"} {
+		stores.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: id})
+	}
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	audit := &memoryAuditPurger{records: []AuditRecord{
+		{Timestamp: at, RequestID: "req-1", Subject: "alice", Method: "message/send", TaskID: "task-1"},
+		{Timestamp: at, RequestID: "req-2", Subject: "bob", Method: "tasks/get", TaskID: "task-1"},
+		{Timestamp: at, RequestID: "req-3", Subject: "bob", Method: "message/send", TaskID: "task-2"},
+		{Timestamp: at, RequestID: "req-4", Subject: "alice", Method: "tasks/get", TaskID: "task-2"},
+		{Timestamp: at, RequestID: "req-5", Subject: "alice", Method: "message/send", TaskID: "task-3"},
+		{Timestamp: at, RequestID: "req-6", Subject: "alice", Method: "message/send"},
+	}}
+	return stores, audit, NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil)
+}
+
+// remainingRequests lists the request IDs of the records left
+func remainingRequests(audit *memoryAuditPurger) []string {
+	var ids []string
+	for _, record := range audit.records {
+		ids = append(ids, record.RequestID)
+	}
+	return ids
+}
+
+func TestPurgeContext(t *testing.T) {
+	stores, audit, h := purgeFixture(t)
+	report, err := h.Purge(context.Background(), PurgeQuery{ContextID: "ctx-1"}, PurgeStores{AuditLog: audit})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(report.Tasks, []a2a.TaskID{"task-1", "task-2"}) || report.Events != 3 || report.AuditRecords != 4 || report.Artifacts != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if left := stores.taskIDs(); !slices.Equal(left, []a2a.TaskID{"task-3"}) || len(stores.events) != 1 {
+		t.Errorf("expected only task-3 and its event left, got %v and %d events", left, len(stores.events))
+	}
+	if left := remainingRequests(audit); !slices.Equal(left, []string{"req-5", "req-6"}) {
+		t.Errorf("expected the other context's records left, got %v", left)
+	}
+}
+
+func TestPurgeSubject(t *testing.T) {
+	stores, audit, h := purgeFixture(t)
+	report, err := h.Purge(context.Background(), PurgeQuery{Subject: "alice"}, PurgeStores{AuditLog: audit})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// bob's task-2 stays, without alice's call on it
+	if !slices.Equal(report.Tasks, []a2a.TaskID{"task-1", "task-3"}) || report.Events != 3 || report.AuditRecords != 5 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if left := stores.taskIDs(); !slices.Equal(left, []a2a.TaskID{"task-2"}) || len(stores.events) != 1 {
+		t.Errorf("expected only bob's task and its event left, got %v and %d events", left, len(stores.events))
+	}
+	if left := remainingRequests(audit); !slices.Equal(left, []string{"req-3"}) {
+		t.Errorf("expected only bob's own record left, got %v", left)
+	}
+
+	// Running it again finds nothing more
+	if report, err := h.Purge(context.Background(), PurgeQuery{Subject: "alice"}, PurgeStores{AuditLog: audit}); err != nil || len(report.Tasks) != 0 || report.AuditRecords != 0 {
+		t.Errorf("expected a second purge to do nothing, got %+v, %v", report, err)
+	}
+}
+
+// memoryCaptures keeps captures by request ID
+type memoryCaptures map[string]CapturedExchange
+
+func (c memoryCaptures) SaveCapture(ctx context.Context, exchange CapturedExchange) error {
+	c[exchange.RequestID] = exchange
+	return nil
+}
+
+func (c memoryCaptures) GetCapture(ctx context.Context, requestID string) (CapturedExchange, error) {
+	exchange, ok := c[requestID]
+	if !ok {
+		return CapturedExchange{}, ErrCaptureNotFound
+	}
+	return exchange, nil
+}
+
+func (c memoryCaptures) DeleteCapture(ctx context.Context, requestID string) error {
+	delete(c, requestID)
+	return nil
+}
+
+// memoryDeliveries keeps delivery attempts in a slice
+type memoryDeliveries struct {
+	attempts []DeliveryAttempt
+}
+
+func (l *memoryDeliveries) RecordDelivery(ctx context.Context, attempt DeliveryAttempt) error {
+	l.attempts = append(l.attempts, attempt)
+	return nil
+}
+
+func (l *memoryDeliveries) ListDeliveries(ctx context.Context, params TaskPushDeliveriesParams) ([]DeliveryAttempt, error) {
+	return l.attempts, nil
+}
+
+func (l *memoryDeliveries) DeleteDeliveries(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	before := len(l.attempts)
+	l.attempts = slices.DeleteFunc(l.attempts, func(attempt DeliveryAttempt) bool { return attempt.TaskID == taskID })
+	return before - len(l.attempts), nil
+}
+
+func TestPurgeCapturesAndDeliveries(t *testing.T) {
+	ctx := context.Background()
+	_, audit, h := purgeFixture(t)
+	captures := memoryCaptures{}
+	for _, id := range []string{"req-1", "req-2", "req-3", "req-5", "req-6"} {
+		captures.SaveCapture(ctx, CapturedExchange{RequestID: id})
+	}
+	deliveries := &memoryDeliveries{attempts: []DeliveryAttempt{{TaskID: "task-1"}, {TaskID: "task-1"}, {TaskID: "task-3"}}}
+
+	report, err := h.Purge(ctx, PurgeQuery{ContextID: "ctx-1"}, PurgeStores{AuditLog: audit, Captures: captures, Deliveries: deliveries})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Deliveries != 2 || len(report.Skipped) != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	// The captures of the purged tasks' audited requests go with them
+	if len(captures) != 2 || captures["req-5"].RequestID == "" || captures["req-6"].RequestID == "" {
+		t.Errorf("expected only the other context's captures left, got %v", captures)
+	}
+	if len(deliveries.attempts) != 1 {
+		t.Errorf("expected only task-3's attempt left, got %v", deliveries.attempts)
+	}
+
+	// Stores that cannot be reached are named for erasing elsewhere
+	_, _, h = purgeFixture(t)
+	h = NewServerlessA2AHandler(ServerlessConfig{}, h.taskStore, h.eventStore, nil, WithMessageDeduplication(NewMemoryMessageDeduplicator(), 0))
+	report, err = h.Purge(ctx, PurgeQuery{ContextID: "ctx-1"}, PurgeStores{Captures: captures, Deliveries: deliveriesOnly{}})
+	if err != nil || len(report.Skipped) != 3 {
+		t.Errorf("expected captures, deliveries and deduplication skipped, got %+v, %v", report, err)
+	}
+}
+
+func TestPurgeArchivedTasks(t *testing.T) {
+	ctx := context.Background()
+	stores, audit, _ := purgeFixture(t)
+	objects := &fakeS3{objects: map[string]string{}}
+	archive, _ := NewS3TaskArchive(objects, "s3://bucket/archive", "")
+	archive.PutArchivedTask(ctx, ArchivedTask{Task: a2a.Task{ID: "task-1", ContextID: "ctx-1", Metadata: map[string]any{TaskOwnerMetadataKey: "alice"}}})
+	archive.PutArchivedTask(ctx, ArchivedTask{Task: a2a.Task{ID: "task-3", ContextID: "ctx-2", Metadata: map[string]any{TaskOwnerMetadataKey: "alice"}}})
+	stores.DeleteTask(ctx, "task-3")
+	h := NewServerlessA2AHandler(ServerlessConfig{}, NewEncryptedTaskStore(NewArchivingTaskStore(stores, stores, archive), nil), stores, nil)
+
+	// An archived task found through the audit log is restored, then purged
+	// with its archived copy
+	report, err := h.Purge(ctx, PurgeQuery{Subject: "alice"}, PurgeStores{AuditLog: audit})
+	if err != nil || !slices.Equal(report.Tasks, []a2a.TaskID{"task-1", "task-3"}) {
+		t.Fatalf("unexpected report %+v, %v", report, err)
+	}
+	if len(objects.objects) != 0 || len(stores.tasks) != 1 {
+		t.Errorf("expected the archived copies deleted, got %v and tasks %v", objects.objects, stores.taskIDs())
+	}
+
+	// A context's archived tasks cannot be listed
+	report, err = h.Purge(ctx, PurgeQuery{ContextID: "ctx-1"}, PurgeStores{})
+	if err != nil || len(report.Skipped) != 1 {
+		t.Errorf("expected the archive reported skipped, got %+v, %v", report, err)
+	}
+}
+
+// deliveriesOnly is a delivery log without DeleteDeliveries
+type deliveriesOnly struct {
+	DeliveryLog
+}
+
+// streamedAuditLog is an audit log that cannot be read back, as Firehose
+type streamedAuditLog struct{}
+
+func (streamedAuditLog) RecordAudit(ctx context.Context, record AuditRecord) error { return nil }
+
+func TestPurgeUnsupported(t *testing.T) {
+	ctx := context.Background()
+	_, _, h := purgeFixture(t)
+
+	report, err := h.Purge(ctx, PurgeQuery{ContextID: "ctx-2"}, PurgeStores{AuditLog: streamedAuditLog{}})
+	if err != nil || len(report.Tasks) != 1 || len(report.Skipped) != 1 {
+		t.Errorf("expected the task purged and the streamed records reported skipped, got %+v, %v", report, err)
+	}
+	if _, err := h.Purge(ctx, PurgeQuery{Subject: "alice"}, PurgeStores{}); !errors.Is(err, ErrPurgeUnsupported) {
+		t.Errorf("expected a subject purge to need the audit log, got %v", err)
+	}
+
+	// The encrypting wrapper defers to the store it wraps, and the task stays
+	tasks := &memoryStores{tasks: []a2a.Task{{ID: "task-1", ContextID: "ctx-1"}}}
+	h = NewServerlessA2AHandler(ServerlessConfig{}, tasks, NewEncryptedEventStore(eventsOnly{}, nil), nil)
+	if _, err := h.Purge(ctx, PurgeQuery{ContextID: "ctx-1"}, PurgeStores{}); !errors.Is(err, ErrPurgeUnsupported) || len(tasks.tasks) != 1 {
+		t.Errorf("expected an event store without deletes to stop the purge, got %v", err)
+	}
+}
+
+// eventsOnly is an event store without DeleteEvents
+type eventsOnly struct {
+	EventStore
+}
+
+// deletingDynamoDB answers one page of a Query with items and records the
+// keys deleted
+type deletingDynamoDB struct {
+	DynamoDBAPI
+	items   []map[string]types.AttributeValue
+	deleted []map[string]types.AttributeValue
+}
+
+func (d *deletingDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{Items: d.items}, nil
+}
+
+func (d *deletingDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	d.deleted = append(d.deleted, params.Key)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestAWSStoresPurge(t *testing.T) {
+	ctx := context.Background()
+	client := &deletingDynamoDB{items: []map[string]types.AttributeValue{
+		{"event_id": &types.AttributeValueMemberS{Value: "billing#status_task-1_1"}},
+		{"event_id": &types.AttributeValueMemberS{Value: "billing#status_task-1_2"}},
+	}}
+	deleted, err := NewAWSEventStore(client, "events", "billing#").DeleteEvents(ctx, "task-1")
+	if err != nil || deleted != 2 {
+		t.Fatalf("expected 2 events deleted, got %d, %v", deleted, err)
+	}
+	if key, _ := client.deleted[1]["event_id"].(*types.AttributeValueMemberS); key.Value != "billing#status_task-1_2" {
+		t.Errorf("expected the stored key deleted, got %v", client.deleted[1])
+	}
+
+	// An audit record is deleted by the key it was written with
+	record := AuditRecord{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), RequestID: "req-1", TaskID: "task-1"}
+	written := &itemsDynamoDB{}
+	NewAWSAuditLog(written, "audit", "billing#").RecordAudit(ctx, record)
+	client.deleted = nil
+	if err := NewAWSAuditLog(client, "audit", "billing#").DeleteAudit(ctx, []AuditRecord{record}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, attr := range []string{"audit_key", "recorded_at"} {
+		if client.deleted[0][attr].(*types.AttributeValueMemberS).Value != written.items[0][attr].(*types.AttributeValueMemberS).Value {
+			t.Errorf("expected %s to match the written record", attr)
+		}
+	}
+
+	// A delivery attempt is deleted by its task and time
+	client.items = []map[string]types.AttributeValue{{
+		"task_id":      &types.AttributeValueMemberS{Value: "task-1"},
+		"attempted_at": &types.AttributeValueMemberS{Value: "2025-01-02T03:04:05Z#msg-1"},
+	}}
+	client.deleted = nil
+	if deleted, err := NewAWSDeliveryLog(client, "deliveries", time.Hour).DeleteDeliveries(ctx, "task-1"); err != nil || deleted != 1 {
+		t.Fatalf("expected 1 attempt deleted, got %d, %v", deleted, err)
+	}
+	if key, _ := client.deleted[0]["attempted_at"].(*types.AttributeValueMemberS); key == nil || key.Value != "2025-01-02T03:04:05Z#msg-1" {
+		t.Errorf("expected the attempt's key deleted, got %v", client.deleted[0])
+	}
+}
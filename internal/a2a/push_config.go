@@ -0,0 +1,277 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrPushConfigNotFound is returned for a push notification config the task
+// does not have
+var ErrPushConfigNotFound = errors.New("push notification config not found")
+
+// PushConfigStore keeps the push notification configs clients set on their
+// tasks. A task's events are sent to each of its configs.
+type PushConfigStore interface {
+	// SavePushConfig stores config, replacing the task's config of the same ID
+	SavePushConfig(ctx context.Context, config a2a.TaskPushConfig) error
+	// GetPushConfig returns ErrPushConfigNotFound for a config never set
+	GetPushConfig(ctx context.Context, taskID a2a.TaskID, configID string) (a2a.TaskPushConfig, error)
+	ListPushConfigs(ctx context.Context, taskID a2a.TaskID) ([]a2a.TaskPushConfig, error)
+	// DeletePushConfig succeeds for a config already gone
+	DeletePushConfig(ctx context.Context, taskID a2a.TaskID, configID string) error
+}
+
+// WithPushConfigStore keeps the configs of tasks/pushNotificationConfig/* in
+// store, and sends each event the handler saves to its task's configs through
+// the handler's PushNotifier. Without it those methods fail with
+// PushNotificationNotSupported.
+func WithPushConfigStore(store PushConfigStore) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.pushConfigs = store
+	}
+}
+
+// pushConfigID returns the ID of a config, the task's ID for a config set
+// without one, as the spec has it
+func pushConfigID(taskID a2a.TaskID, configID *string) string {
+	if configID == nil || *configID == "" {
+		return string(taskID)
+	}
+	return *configID
+}
+
+// saveEvent stores an event, then sends it to the push notification configs
+// of its task. Failed notifications are logged; the event is stored either way.
+func (h *ServerlessA2AHandler) saveEvent(ctx context.Context, event a2a.Event) error {
+	if err := h.eventStore.SaveEvent(ctx, event); err != nil {
+		return err
+	}
+	h.notify(ctx, event)
+	return nil
+}
+
+// notify sends event to each push notification config of its task
+func (h *ServerlessA2AHandler) notify(ctx context.Context, event a2a.Event) {
+	if h.pushConfigs == nil || h.pushNotifier == nil {
+		return
+	}
+	taskID := eventTaskID(event)
+	if taskID == "" {
+		return
+	}
+	configs, err := h.pushConfigs.ListPushConfigs(ctx, taskID)
+	if err != nil {
+		LoggerFromContext(ctx).Warn("failed to list push notification configs", LogKeyTaskID, taskID, LogKeyError, err)
+		return
+	}
+	event = PublicEvent(event)
+	for _, config := range configs {
+		if err := h.pushNotifier.SendNotification(ctx, config.Config, event); err != nil {
+			LoggerFromContext(ctx).Warn("failed to send push notification", LogKeyTaskID, taskID, LogKeyError, err)
+		}
+	}
+}
+
+// eventTaskID returns the task an event is about, "" for a message outside
+// any task
+func eventTaskID(event a2a.Event) a2a.TaskID {
+	switch event := event.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		return event.TaskID
+	case a2a.TaskArtifactUpdateEvent:
+		return event.TaskID
+	case a2a.Task:
+		return event.ID
+	case a2a.Message:
+		if event.TaskID != nil {
+			return *event.TaskID
+		}
+	}
+	return ""
+}
+
+// MemoryPushConfigStore implements PushConfigStore in memory. On Lambda
+// each instance only knows the configs set through it.
+type MemoryPushConfigStore struct {
+	mu      sync.Mutex
+	configs map[a2a.TaskID][]a2a.TaskPushConfig
+}
+
+// NewMemoryPushConfigStore creates an in-memory push config store
+func NewMemoryPushConfigStore() *MemoryPushConfigStore {
+	return &MemoryPushConfigStore{configs: make(map[a2a.TaskID][]a2a.TaskPushConfig)}
+}
+
+// SavePushConfig implements PushConfigStore
+func (s *MemoryPushConfigStore) SavePushConfig(ctx context.Context, config a2a.TaskPushConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configID := pushConfigID(config.TaskID, config.Config.ID)
+	configs := slices.DeleteFunc(s.configs[config.TaskID], func(stored a2a.TaskPushConfig) bool {
+		return pushConfigID(stored.TaskID, stored.Config.ID) == configID
+	})
+	s.configs[config.TaskID] = append(configs, config)
+	return nil
+}
+
+// GetPushConfig implements PushConfigStore
+func (s *MemoryPushConfigStore) GetPushConfig(ctx context.Context, taskID a2a.TaskID, configID string) (a2a.TaskPushConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, config := range s.configs[taskID] {
+		if pushConfigID(taskID, config.Config.ID) == configID {
+			return config, nil
+		}
+	}
+	return a2a.TaskPushConfig{}, fmt.Errorf("config %s of task %s: %w", configID, taskID, ErrPushConfigNotFound)
+}
+
+// ListPushConfigs implements PushConfigStore, ordered by config ID as
+// DynamoDB returns them
+func (s *MemoryPushConfigStore) ListPushConfigs(ctx context.Context, taskID a2a.TaskID) ([]a2a.TaskPushConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs := append([]a2a.TaskPushConfig{}, s.configs[taskID]...)
+	slices.SortFunc(configs, func(a, b a2a.TaskPushConfig) int {
+		return strings.Compare(pushConfigID(taskID, a.Config.ID), pushConfigID(taskID, b.Config.ID))
+	})
+	return configs, nil
+}
+
+// DeletePushConfig implements PushConfigStore
+func (s *MemoryPushConfigStore) DeletePushConfig(ctx context.Context, taskID a2a.TaskID, configID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configs[taskID] = slices.DeleteFunc(s.configs[taskID], func(config a2a.TaskPushConfig) bool {
+		return pushConfigID(taskID, config.Config.ID) == configID
+	})
+	return nil
+}
+
+// AWSPushConfigStore is a PushConfigStore on a DynamoDB table with the
+// partition key task_id and the sort key config_id
+type AWSPushConfigStore struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSPushConfigStore creates a push config store on tableName, with keys
+// prefixed as for NewAWSTaskStore
+func NewAWSPushConfigStore(client DynamoDBAPI, tableName, keyPrefix string) *AWSPushConfigStore {
+	return &AWSPushConfigStore{client: client, tableName: tableName, keyPrefix: keyPrefix}
+}
+
+// key returns the item key of a task's config
+func (s *AWSPushConfigStore) key(taskID a2a.TaskID, configID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"task_id":   &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
+		"config_id": &types.AttributeValueMemberS{Value: configID},
+	}
+}
+
+// SavePushConfig implements PushConfigStore
+func (s *AWSPushConfigStore) SavePushConfig(ctx context.Context, config a2a.TaskPushConfig) error {
+	defer observeStorage(ctx, "SavePushConfig", time.Now())
+
+	data, err := json.Marshal(config.Config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push notification config: %w", err)
+	}
+	item := s.key(config.TaskID, pushConfigID(config.TaskID, config.Config.ID))
+	item["config"] = &types.AttributeValueMemberS{Value: string(data)}
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save push notification config of task %s: %w", config.TaskID, err)
+	}
+	return nil
+}
+
+// GetPushConfig implements PushConfigStore
+func (s *AWSPushConfigStore) GetPushConfig(ctx context.Context, taskID a2a.TaskID, configID string) (a2a.TaskPushConfig, error) {
+	defer observeStorage(ctx, "GetPushConfig", time.Now())
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       s.key(taskID, configID),
+	})
+	if err != nil {
+		return a2a.TaskPushConfig{}, fmt.Errorf("failed to get push notification config %s of task %s: %w", configID, taskID, err)
+	}
+	if result.Item == nil {
+		return a2a.TaskPushConfig{}, fmt.Errorf("config %s of task %s: %w", configID, taskID, ErrPushConfigNotFound)
+	}
+	return readPushConfig(taskID, result.Item)
+}
+
+// ListPushConfigs implements PushConfigStore, page by page
+func (s *AWSPushConfigStore) ListPushConfigs(ctx context.Context, taskID a2a.TaskID) ([]a2a.TaskPushConfig, error) {
+	defer observeStorage(ctx, "ListPushConfigs", time.Now())
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("task_id = :task_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":task_id": &types.AttributeValueMemberS{Value: s.keyPrefix + string(taskID)},
+		},
+	}
+	configs := []a2a.TaskPushConfig{}
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list push notification configs of task %s: %w", taskID, err)
+		}
+		for _, item := range result.Items {
+			config, err := readPushConfig(taskID, item)
+			if err != nil {
+				return nil, err
+			}
+			configs = append(configs, config)
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return configs, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// DeletePushConfig implements PushConfigStore
+func (s *AWSPushConfigStore) DeletePushConfig(ctx context.Context, taskID a2a.TaskID, configID string) error {
+	defer observeStorage(ctx, "DeletePushConfig", time.Now())
+
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       s.key(taskID, configID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete push notification config %s of task %s: %w", configID, taskID, err)
+	}
+	return nil
+}
+
+// readPushConfig decodes a stored config of taskID
+func readPushConfig(taskID a2a.TaskID, item map[string]types.AttributeValue) (a2a.TaskPushConfig, error) {
+	data, ok := item["config"].(*types.AttributeValueMemberS)
+	if !ok {
+		return a2a.TaskPushConfig{}, fmt.Errorf("push notification config of task %s has no config attribute", taskID)
+	}
+	config := a2a.TaskPushConfig{TaskID: taskID}
+	if err := json.Unmarshal([]byte(data.Value), &config.Config); err != nil {
+		return a2a.TaskPushConfig{}, fmt.Errorf("failed to unmarshal push notification config of task %s: %w", taskID, err)
+	}
+	return config, nil
+}
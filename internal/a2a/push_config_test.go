@@ -0,0 +1,156 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// recordingNotifier keeps the notifications it is asked to send
+type recordingNotifier struct {
+	configs []a2a.PushConfig
+	events  []a2a.Event
+}
+
+func (n *recordingNotifier) SendNotification(ctx context.Context, config a2a.PushConfig, event a2a.Event) error {
+	n.configs = append(n.configs, config)
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestHandlerPushConfigs(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	notifier := &recordingNotifier{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, notifier, WithPushConfigStore(NewMemoryPushConfigStore()))
+
+	// A config sent with the message is set under the task's ID
+	result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{
+		Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser},
+		Config:  &a2a.MessageSendConfig{PushConfig: &a2a.PushConfig{URL: "https://hooks.example.com/a2a"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	config, err := h.OnGetTaskPushConfig(ctx, a2a.GetTaskPushConfigParams{TaskID: task.ID})
+	if err != nil || config.Config.URL != "https://hooks.example.com/a2a" || *config.Config.ID != string(task.ID) {
+		t.Fatalf("expected the message's config under the task ID, got %+v: %v", config, err)
+	}
+
+	// Each event of the task is pushed to each of its configs
+	configID := "second"
+	if _, err := h.OnSetTaskPushConfig(ctx, a2a.TaskPushConfig{TaskID: task.ID, Config: a2a.PushConfig{ID: &configID, URL: "https://hooks.example.com/other"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: task.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.events) != 2 || notifier.configs[0].URL == notifier.configs[1].URL {
+		t.Fatalf("expected the cancellation pushed to both configs, got %+v", notifier.configs)
+	}
+	if event := notifier.events[0].(a2a.TaskStatusUpdateEvent); event.Status.State != a2a.TaskStateCanceled || !event.Final {
+		t.Errorf("expected the final canceled event, got %+v", event)
+	}
+
+	// A deleted config is no longer found
+	if err := h.OnDeleteTaskPushConfig(ctx, a2a.DeleteTaskPushConfigParams{TaskID: task.ID, ConfigID: configID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = h.OnGetTaskPushConfig(ctx, a2a.GetTaskPushConfigParams{TaskID: task.ID, ConfigID: &configID})
+	if code, _, _ := A2AErrorCode(err); !errors.Is(err, ErrPushConfigNotFound) || code != JSONRPCErrorInvalidParams {
+		t.Errorf("expected a deleted config not found as invalid params, got %v", err)
+	}
+	if configs, err := h.OnListTaskPushConfig(ctx, a2a.ListTaskPushConfigParams{TaskID: task.ID}); err != nil || len(configs) != 1 {
+		t.Errorf("expected one config left, got %+v: %v", configs, err)
+	}
+
+	// A config is only set on a task that exists
+	_, err = h.OnSetTaskPushConfig(ctx, a2a.TaskPushConfig{TaskID: "missing", Config: a2a.PushConfig{URL: "https://hooks.example.com/a2a"}})
+	if !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+
+	// The purge of a task deletes its configs
+	report, err := h.Purge(ctx, PurgeQuery{ContextID: task.ContextID}, PurgeStores{})
+	if err != nil || report.PushConfigs != 1 {
+		t.Errorf("expected the config purged, got %+v: %v", report, err)
+	}
+
+	// Without a store push notifications are not supported
+	_, err = NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, notifier).OnListTaskPushConfig(ctx, a2a.ListTaskPushConfigParams{TaskID: task.ID})
+	if !errors.Is(err, a2a.ErrPushNotificationNotSupported) {
+		t.Errorf("expected ErrPushNotificationNotSupported, got %v", err)
+	}
+}
+
+// pushConfigDynamoDB keeps items by task and config ID
+type pushConfigDynamoDB struct {
+	DynamoDBAPI
+	items map[string]map[string]types.AttributeValue
+}
+
+func pushConfigItemKey(key map[string]types.AttributeValue) string {
+	return key["task_id"].(*types.AttributeValueMemberS).Value + "/" + key["config_id"].(*types.AttributeValueMemberS).Value
+}
+
+func (d *pushConfigDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	d.items[pushConfigItemKey(params.Item)] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (d *pushConfigDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: d.items[pushConfigItemKey(params.Key)]}, nil
+}
+
+func (d *pushConfigDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	taskID := params.ExpressionAttributeValues[":task_id"].(*types.AttributeValueMemberS).Value
+	output := &dynamodb.QueryOutput{}
+	for key, item := range d.items {
+		if strings.HasPrefix(key, taskID+"/") {
+			output.Items = append(output.Items, item)
+		}
+	}
+	return output, nil
+}
+
+func (d *pushConfigDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(d.items, pushConfigItemKey(params.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestAWSPushConfigStore(t *testing.T) {
+	ctx := context.Background()
+	client := &pushConfigDynamoDB{items: make(map[string]map[string]types.AttributeValue)}
+	store := NewAWSPushConfigStore(client, "push-configs", "billing#")
+
+	token := "secret"
+	if err := store.SavePushConfig(ctx, a2a.TaskPushConfig{TaskID: "task-1", Config: a2a.PushConfig{URL: "https://hooks.example.com/a2a", Token: &token}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := client.items["billing#task-1/task-1"]; !ok {
+		t.Fatalf("expected a config without an ID kept under the prefixed task and its ID, got %v", client.items)
+	}
+	config, err := store.GetPushConfig(ctx, "task-1", "task-1")
+	if err != nil || config.TaskID != "task-1" || config.Config.URL != "https://hooks.example.com/a2a" || *config.Config.Token != token {
+		t.Errorf("expected the config read back, got %+v: %v", config, err)
+	}
+	if configs, err := store.ListPushConfigs(ctx, "task-1"); err != nil || len(configs) != 1 {
+		t.Errorf("expected the task's config listed, got %+v: %v", configs, err)
+	}
+
+	if err := store.DeletePushConfig(ctx, "task-1", "task-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := store.GetPushConfig(ctx, "task-1", "task-1"); !errors.Is(err, ErrPushConfigNotFound) {
+		t.Errorf("expected ErrPushConfigNotFound, got %v", err)
+	}
+	if configs, err := store.ListPushConfigs(ctx, "task-1"); err != nil || configs == nil || len(configs) != 0 {
+		t.Errorf("expected an empty list, got %#v: %v", configs, err)
+	}
+}
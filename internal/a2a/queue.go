@@ -0,0 +1,256 @@
+package a2a
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// MessageQueue holds messages to execute after a delay, on their task.
+// Whatever consumes it passes each message to
+// ServerlessA2AHandler.ProcessQueuedMessage.
+type MessageQueue interface {
+	QueueMessage(ctx context.Context, params a2a.MessageSendParams, delay time.Duration) error
+}
+
+// WithWorkQueue has a handler without an executor queue each message that
+// leaves its task waiting on the agent, for workers whose handlers have one.
+// Without a work queue the task is left working for another function to find.
+func WithWorkQueue(queue MessageQueue) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.workQueue = queue
+	}
+}
+
+// WorkQueueConfig names the SQS queues messages are queued on: by handlers
+// without an executor, for workers with one, and by workers, for retries.
+// Queueing is off unless URL or RetryURL is set.
+type WorkQueueConfig struct {
+	// URL is the work queue's, which takes normal priority messages and,
+	// without queues of their own, high and low priority ones
+	URL string
+	// HighURL and LowURL are the queues of high and low priority messages
+	HighURL string
+	LowURL  string
+	// RetryURL is the queue failed executions are retried through, the work
+	// queue when unset
+	RetryURL string
+	// Concurrency bounds the messages of one batch a worker executes at
+	// once, and HighConcurrency and LowConcurrency those from the high and
+	// low priority queues
+	Concurrency     int
+	HighConcurrency int
+	LowConcurrency  int
+}
+
+// LoadWorkQueueConfigFromEnv reads A2A_WORK_QUEUE_URL, the high and low
+// priority queues of A2A_WORK_QUEUE_HIGH_URL and A2A_WORK_QUEUE_LOW_URL,
+// A2A_RETRY_QUEUE_URL and the concurrency of each in A2A_WORK_CONCURRENCY,
+// A2A_WORK_CONCURRENCY_HIGH and A2A_WORK_CONCURRENCY_LOW. The high and low
+// concurrency default to the work queue's.
+func LoadWorkQueueConfigFromEnv() (WorkQueueConfig, error) {
+	config := WorkQueueConfig{
+		URL:      getEnvOrDefault("A2A_WORK_QUEUE_URL", ""),
+		HighURL:  getEnvOrDefault("A2A_WORK_QUEUE_HIGH_URL", ""),
+		LowURL:   getEnvOrDefault("A2A_WORK_QUEUE_LOW_URL", ""),
+		RetryURL: getEnvOrDefault("A2A_RETRY_QUEUE_URL", ""),
+	}
+	if config.URL == "" && (config.HighURL != "" || config.LowURL != "") {
+		return WorkQueueConfig{}, fmt.Errorf("A2A_WORK_QUEUE_HIGH_URL and A2A_WORK_QUEUE_LOW_URL need A2A_WORK_QUEUE_URL for normal priority messages")
+	}
+	var err error
+	if config.Concurrency, err = concurrencyFromEnv("A2A_WORK_CONCURRENCY", DefaultBatchConcurrency); err != nil {
+		return WorkQueueConfig{}, err
+	}
+	if config.HighConcurrency, err = concurrencyFromEnv("A2A_WORK_CONCURRENCY_HIGH", config.Concurrency); err != nil {
+		return WorkQueueConfig{}, err
+	}
+	if config.LowConcurrency, err = concurrencyFromEnv("A2A_WORK_CONCURRENCY_LOW", config.Concurrency); err != nil {
+		return WorkQueueConfig{}, err
+	}
+	return config, nil
+}
+
+// concurrencyFromEnv reads a positive integer from name, fallback when unset
+func concurrencyFromEnv(name string, fallback int) (int, error) {
+	value := getEnvOrDefault(name, "")
+	if value == "" {
+		return fallback, nil
+	}
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", name, value)
+	}
+	return concurrency, nil
+}
+
+// RuntimeOptions returns the options queueing on the configured queues
+// through client. With a high or low priority queue, messages are routed
+// by priority.
+func (c WorkQueueConfig) RuntimeOptions(client SQSAPI) []RuntimeOption {
+	var opts []RuntimeOption
+	if c.URL != "" {
+		var queue MessageQueue = NewSQSMessageQueue(client, c.URL)
+		if c.HighURL != "" || c.LowURL != "" {
+			queue = NewPriorityQueues(c.sqsQueue(client, c.HighURL), queue, c.sqsQueue(client, c.LowURL))
+		}
+		opts = append(opts, WithWorkQueue(queue))
+	}
+	if retryURL := cmp.Or(c.RetryURL, c.URL); retryURL != "" {
+		opts = append(opts, WithRetryQueue(NewSQSMessageQueue(client, retryURL)))
+	}
+	return opts
+}
+
+// sqsQueue returns the queue at queueURL, nil when it is unset
+func (c WorkQueueConfig) sqsQueue(client SQSAPI, queueURL string) MessageQueue {
+	if queueURL == "" {
+		return nil
+	}
+	return NewSQSMessageQueue(client, queueURL)
+}
+
+// queuePriority returns the priority of the queue whose ARN is source:
+// high or low for those queues, normal for the work and retry queues
+func (c WorkQueueConfig) queuePriority(source string) TaskPriority {
+	// An ARN ends in the queue's name, as its URL does
+	name := source[strings.LastIndex(source, ":")+1:]
+	switch {
+	case c.HighURL != "" && name == path.Base(c.HighURL):
+		return PriorityHigh
+	case c.LowURL != "" && name == path.Base(c.LowURL):
+		return PriorityLow
+	}
+	return PriorityNormal
+}
+
+// SQSMessageQueue is a MessageQueue on an SQS queue, using its message delay
+type SQSMessageQueue struct {
+	client   SQSAPI
+	queueURL string
+}
+
+// NewSQSMessageQueue creates a message queue sending to queueURL
+func NewSQSMessageQueue(client SQSAPI, queueURL string) *SQSMessageQueue {
+	return &SQSMessageQueue{client: client, queueURL: queueURL}
+}
+
+// QueueMessage implements MessageQueue
+func (q *SQSMessageQueue) QueueMessage(ctx context.Context, params a2a.MessageSendParams, delay time.Duration) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued message: %w", err)
+	}
+	// Rounded up, so a message never runs before its delay
+	seconds := int32(math.Ceil(min(delay, MaxRetryDelay).Seconds()))
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:     aws.String(q.queueURL),
+		MessageBody:  aws.String(string(body)),
+		DelaySeconds: seconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to queue message: %w", err)
+	}
+	return nil
+}
+
+// ProcessQueuedMessage executes a message from a work or retry queue. Its
+// task already holds the message in its history; a task canceled, failed
+// or timed out meanwhile is left as it is. An error is returned only when
+// the message could not be handled, for the queue to redeliver it.
+func (h *ServerlessA2AHandler) ProcessQueuedMessage(ctx context.Context, body string) error {
+	params, err := UnmarshalMessageSendParams([]byte(body))
+	if err != nil {
+		return fmt.Errorf("invalid queued message: %w", err)
+	}
+	if params.Message.TaskID == nil {
+		return errors.New("invalid queued message: no task ID")
+	}
+	return h.processOnce(ctx, params)
+}
+
+// WorkQueueConsumer executes the messages a worker receives from the work,
+// priority and retry queues, through a handler with an executor
+type WorkQueueConsumer struct {
+	config     WorkQueueConfig
+	processors map[TaskPriority]*BatchProcessor
+}
+
+// NewWorkQueueConsumer creates a consumer passing each message to h, as
+// many at once as config allows for the queue it came from. Duplicate
+// deliveries are only caught by the handler's WithMessageDeduplication.
+func NewWorkQueueConsumer(h *ServerlessA2AHandler, config WorkQueueConfig) *WorkQueueConsumer {
+	process := func(ctx context.Context, record BatchRecord) error {
+		return h.ProcessQueuedMessage(ctx, record.Body)
+	}
+	return &WorkQueueConsumer{
+		config: config,
+		processors: map[TaskPriority]*BatchProcessor{
+			PriorityHigh:   NewBatchProcessor(process, config.HighConcurrency, nil),
+			PriorityNormal: NewBatchProcessor(process, config.Concurrency, nil),
+			PriorityLow:    NewBatchProcessor(process, config.LowConcurrency, nil),
+		},
+	}
+}
+
+// Process executes every record and returns the IDs of those that failed,
+// in batch order, for the queue to redeliver. The records of each queue run
+// at that queue's concurrency, beside those of the others.
+func (c *WorkQueueConsumer) Process(ctx context.Context, records []BatchRecord) []string {
+	batches := make(map[TaskPriority][]BatchRecord)
+	for _, record := range records {
+		priority := c.config.queuePriority(record.Source)
+		batches[priority] = append(batches[priority], record)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := make(map[string]bool)
+	for priority, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, messageID := range c.processors[priority].Process(ctx, batch) {
+				mu.Lock()
+				failed[messageID] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var failures []string
+	for _, record := range records {
+		if failed[record.MessageID] {
+			failures = append(failures, record.MessageID)
+		}
+	}
+	return failures
+}
+
+// queuedMessage returns params as queued for task: on the task, with the
+// priority it was sent at and an ID of its own
+func (h *ServerlessA2AHandler) queuedMessage(params a2a.MessageSendParams, task a2a.Task) a2a.MessageSendParams {
+	priority := h.messagePriority(params)
+	params.Message.TaskID, params.Message.ContextID = &task.ID, &task.ContextID
+	params.Metadata = maps.Clone(params.Metadata)
+	if params.Metadata == nil {
+		params.Metadata = make(map[string]any)
+	}
+	params.Metadata[PriorityMetadataKey] = string(priority)
+	params.Metadata[QueueIDMetadataKey] = h.ids.NewID()
+	return params
+}
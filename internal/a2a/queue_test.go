@@ -0,0 +1,115 @@
+package a2a
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestLoadWorkQueueConfigFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+	config, err := LoadWorkQueueConfigFromEnv()
+	if err != nil || config.URL != "" || config.Concurrency != DefaultBatchConcurrency || config.RuntimeOptions(&deadLetterSQS{}) != nil {
+		t.Errorf("expected no queues by default, got %+v, %v", config, err)
+	}
+
+	t.Setenv("A2A_WORK_QUEUE_URL", "https://sqs.us-east-1.amazonaws.com/123456789012/work")
+	t.Setenv("A2A_WORK_CONCURRENCY", "4")
+	config, err = LoadWorkQueueConfigFromEnv()
+	if err != nil || config.URL == "" || config.Concurrency != 4 {
+		t.Errorf("expected the environment to be read, got %+v, %v", config, err)
+	}
+	// Retries go through the work queue unless they have their own
+	if opts := config.RuntimeOptions(&deadLetterSQS{}); len(opts) != 2 {
+		t.Errorf("expected work and retry queue options, got %d", len(opts))
+	}
+
+	// Priority queues take the work queue's concurrency unless given theirs
+	t.Setenv("A2A_WORK_QUEUE_HIGH_URL", "https://sqs.us-east-1.amazonaws.com/123456789012/work-high")
+	t.Setenv("A2A_WORK_CONCURRENCY_HIGH", "20")
+	config, err = LoadWorkQueueConfigFromEnv()
+	if err != nil || config.HighURL == "" || config.HighConcurrency != 20 || config.LowConcurrency != 4 {
+		t.Errorf("expected the high priority queue read, got %+v, %v", config, err)
+	}
+
+	for _, name := range []string{"A2A_WORK_CONCURRENCY", "A2A_WORK_CONCURRENCY_LOW"} {
+		for _, value := range []string{"0", "many"} {
+			t.Setenv(name, value)
+			if _, err := LoadWorkQueueConfigFromEnv(); err == nil {
+				t.Errorf("expected %s=%q to be rejected", name, value)
+			}
+		}
+		t.Setenv(name, "4")
+	}
+	t.Setenv("A2A_WORK_QUEUE_URL", "")
+	if _, err := LoadWorkQueueConfigFromEnv(); err == nil {
+		t.Error("expected a high priority queue without a work queue to be rejected")
+	}
+}
+
+func TestWorkQueueConsumer(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	config := WorkQueueConfig{URL: "work", Concurrency: 1}
+	front := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, config.RuntimeOptions(queue)...)
+	if _, err := front.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queue.sent) != 1 || *queue.sent[0].QueueUrl != "work" {
+		t.Fatalf("expected the message queued for a worker, got %+v", queue.sent)
+	}
+
+	executor := &failingExecutor{}
+	worker := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, append(config.RuntimeOptions(queue), WithExecutor(executor))...)
+	failed := NewWorkQueueConsumer(worker, config).Process(ctx, []BatchRecord{
+		{MessageID: "1", Body: *queue.sent[0].MessageBody},
+		{MessageID: "2", Body: "not json"},
+	})
+	if executor.calls != 1 || len(failed) != 1 || failed[0] != "2" {
+		t.Errorf("expected the queued message executed and the bad one reported, got %d executions and %v", executor.calls, failed)
+	}
+}
+
+func TestWorkQueueConsumerByPriority(t *testing.T) {
+	ctx := context.Background()
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	config := WorkQueueConfig{
+		URL:             "https://sqs.us-east-1.amazonaws.com/123456789012/work",
+		HighURL:         "https://sqs.us-east-1.amazonaws.com/123456789012/work-high",
+		Concurrency:     1,
+		HighConcurrency: 1,
+		LowConcurrency:  1,
+	}
+	front := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, config.RuntimeOptions(queue)...)
+	for _, priority := range []string{"high", "low", "high"} {
+		params := a2a.MessageSendParams{Message: a2a.Message{MessageID: front.ids.NewID(), Role: a2a.MessageRoleUser}, Metadata: map[string]any{PriorityMetadataKey: priority}}
+		if _, err := front.OnSendMessage(ctx, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Low priority has no queue of its own
+	if len(queue.sent) != 3 || *queue.sent[0].QueueUrl != config.HighURL || *queue.sent[1].QueueUrl != config.URL {
+		t.Fatalf("expected the messages routed by priority, got %+v", queue.sent)
+	}
+
+	if priority := config.queuePriority("arn:aws:sqs:us-east-1:123456789012:work-high"); priority != PriorityHigh {
+		t.Errorf("expected the high priority queue recognized, got %s", priority)
+	}
+	executor := &failingExecutor{}
+	worker := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(executor))
+	// The stores are not safe for concurrent use, so only the high priority
+	// records, one at a time, reach them
+	failed := NewWorkQueueConsumer(worker, config).Process(ctx, []BatchRecord{
+		{MessageID: "1", Body: "not json", Source: "arn:aws:sqs:us-east-1:123456789012:work"},
+		{MessageID: "2", Body: *queue.sent[0].MessageBody, Source: "arn:aws:sqs:us-east-1:123456789012:work-high"},
+		{MessageID: "3", Body: "not json", Source: "arn:aws:sqs:us-east-1:123456789012:work-high"},
+		{MessageID: "4", Body: *queue.sent[2].MessageBody, Source: "arn:aws:sqs:us-east-1:123456789012:work-high"},
+	})
+	if executor.calls != 2 || len(failed) != 2 || failed[0] != "1" || failed[1] != "3" {
+		t.Errorf("expected the high priority messages executed and the bad ones reported in order, got %d executions and %v", executor.calls, failed)
+	}
+}
@@ -0,0 +1,315 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQuotaExceeded is returned when a call would take the tenant or the
+// caller past a daily or monthly quota
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota scopes: the agent as a whole, which is the tenant in a multi-agent
+// deployment, and each caller of it by principal subject. Every API key
+// caller shares the subject apiKey.
+const (
+	QuotaScopeTenant = "tenant"
+	QuotaScopeCaller = "caller"
+)
+
+// Quota periods, counted in calendar windows in UTC
+const (
+	QuotaPeriodDay   = "day"
+	QuotaPeriodMonth = "month"
+)
+
+// quotaPeriods lists the periods every charge is counted in
+var quotaPeriods = []string{QuotaPeriodDay, QuotaPeriodMonth}
+
+// Usage counts what a tenant or caller used in one window
+type Usage struct {
+	Requests int64 `json:"requests"`
+	// Tasks counts messages that started a new task
+	Tasks int64 `json:"tasks"`
+	// Bytes counts the size of the messages sent, which are stored with
+	// their tasks and events
+	Bytes int64 `json:"bytes"`
+}
+
+// QuotaLimits caps the usage of one window. A zero limit leaves its counter
+// unlimited.
+type QuotaLimits Usage
+
+// exceededBy names the first counter that adding delta to used takes past
+// its limit, or returns "" when every counter stays within its limit
+func (l QuotaLimits) exceededBy(used, delta Usage) string {
+	for _, counter := range []struct {
+		name               string
+		limit, used, delta int64
+	}{
+		{"requests", l.Requests, used.Requests, delta.Requests},
+		{"tasks", l.Tasks, used.Tasks, delta.Tasks},
+		{"bytes", l.Bytes, used.Bytes, delta.Bytes},
+	} {
+		if counter.limit > 0 && counter.delta > 0 && counter.used+counter.delta > counter.limit {
+			return fmt.Sprintf("%s limit of %d", counter.name, counter.limit)
+		}
+	}
+	return ""
+}
+
+// QuotaPolicy holds the limits of each period for one scope
+type QuotaPolicy struct {
+	Daily   QuotaLimits `json:"daily"`
+	Monthly QuotaLimits `json:"monthly"`
+}
+
+// limits returns the policy's limits for period
+func (p QuotaPolicy) limits(period string) QuotaLimits {
+	if period == QuotaPeriodMonth {
+		return p.Monthly
+	}
+	return p.Daily
+}
+
+// QuotaConfig selects where usage is counted and the limits of each scope.
+// Quotas are off unless Table is set; a table without limits only counts.
+type QuotaConfig struct {
+	Table  string      `json:"-"`
+	Tenant QuotaPolicy `json:"tenant"`
+	Caller QuotaPolicy `json:"caller"`
+}
+
+// Enabled reports whether a quota table is configured
+func (c QuotaConfig) Enabled() bool {
+	return c.Table != ""
+}
+
+// policy returns the limits of scope
+func (c QuotaConfig) policy(scope string) QuotaPolicy {
+	if scope == QuotaScopeCaller {
+		return c.Caller
+	}
+	return c.Tenant
+}
+
+// LoadQuotaConfigFromEnv reads A2A_QUOTA_TABLE and A2A_QUOTA_LIMITS, a JSON
+// object such as {"caller": {"daily": {"requests": 1000, "tasks": 100}}}
+// with tenant and caller policies of daily and monthly limits
+func LoadQuotaConfigFromEnv() (QuotaConfig, error) {
+	config := QuotaConfig{}
+	if value := getEnvOrDefault("A2A_QUOTA_LIMITS", ""); value != "" {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return QuotaConfig{}, fmt.Errorf("A2A_QUOTA_LIMITS: %w", err)
+		}
+		for _, policy := range []QuotaPolicy{config.Tenant, config.Caller} {
+			for _, limits := range []QuotaLimits{policy.Daily, policy.Monthly} {
+				if limits.Requests < 0 || limits.Tasks < 0 || limits.Bytes < 0 {
+					return QuotaConfig{}, errors.New("A2A_QUOTA_LIMITS: limits cannot be negative")
+				}
+			}
+		}
+	}
+	config.Table = getEnvOrDefault("A2A_QUOTA_TABLE", "")
+	if config.Table == "" && config != (QuotaConfig{}) {
+		return QuotaConfig{}, errors.New("A2A_QUOTA_LIMITS requires A2A_QUOTA_TABLE")
+	}
+	return config, nil
+}
+
+// UsageKey identifies the usage of one scope and ID in one window, such as
+// the caller alice on 2025-01-02
+type UsageKey struct {
+	Scope  string
+	ID     string
+	Period string
+	// Window is the date of a day or the year and month of a month
+	Window string
+}
+
+// String names the key in errors
+func (k UsageKey) String() string {
+	return fmt.Sprintf("%s %s on %s", k.Scope, k.ID, k.Window)
+}
+
+// UsageCounter is one window's usage to charge, its limits, and when its
+// count may be dropped
+type UsageCounter struct {
+	Key       UsageKey
+	Limits    QuotaLimits
+	ExpiresAt time.Time
+}
+
+// QuotaStore keeps usage counts shared by every instance
+type QuotaStore interface {
+	// AddUsage adds delta to every counter, or to none of them and returns
+	// an error wrapping ErrQuotaExceeded when that would take a counter
+	// past its limits
+	AddUsage(ctx context.Context, counters []UsageCounter, delta Usage) error
+	// GetUsage returns the usage counted under key, zero when none was
+	GetUsage(ctx context.Context, key UsageKey) (Usage, error)
+	// ResetUsage drops the usage counted under key
+	ResetUsage(ctx context.Context, key UsageKey) error
+}
+
+// QuotaQuery selects the usage the admin quota methods read or reset
+type QuotaQuery struct {
+	Scope string `json:"scope"`
+	// ID is the caller's subject. The tenant scope is always this agent.
+	ID string `json:"id,omitempty"`
+	// Period limits a reset to one period; without it every period is reset
+	Period string `json:"period,omitempty"`
+}
+
+// Validate checks the scope and period and that a caller is named
+func (q QuotaQuery) Validate() error {
+	switch q.Scope {
+	case QuotaScopeTenant:
+	case QuotaScopeCaller:
+		if q.ID == "" {
+			return errors.New("id is required for the caller scope")
+		}
+	default:
+		return fmt.Errorf("scope must be %s or %s, got %q", QuotaScopeTenant, QuotaScopeCaller, q.Scope)
+	}
+	switch q.Period {
+	case "", QuotaPeriodDay, QuotaPeriodMonth:
+		return nil
+	}
+	return fmt.Errorf("period must be %s or %s, got %q", QuotaPeriodDay, QuotaPeriodMonth, q.Period)
+}
+
+// QuotaUsage reports one window's usage against its limits
+type QuotaUsage struct {
+	Scope    string      `json:"scope"`
+	ID       string      `json:"id"`
+	Period   string      `json:"period"`
+	Window   string      `json:"window"`
+	Usage    Usage       `json:"usage"`
+	Limits   QuotaLimits `json:"limits"`
+	ResetsAt time.Time   `json:"resets_at"`
+}
+
+// Quotas charges calls to the usage of the tenant and the caller in the
+// current day and month
+type Quotas struct {
+	store  QuotaStore
+	config QuotaConfig
+	tenant string
+	clock  Clock
+}
+
+// NewQuotas creates quotas counting in store, with tenant naming the agent
+// whose usage the tenant scope counts
+func NewQuotas(store QuotaStore, config QuotaConfig, tenant string, opts ...RuntimeOption) *Quotas {
+	return &Quotas{
+		store:  store,
+		config: config,
+		tenant: tenant,
+		clock:  newRuntimeDeps(opts).clock,
+	}
+}
+
+// Charge adds delta to the tenant's usage and, unless subject is empty, to
+// the caller's. Nothing is added when that would pass any of their limits.
+func (q *Quotas) Charge(ctx context.Context, subject string, delta Usage) error {
+	now := q.clock.Now()
+	scopes := [][2]string{{QuotaScopeTenant, q.tenant}}
+	if subject != "" {
+		scopes = append(scopes, [2]string{QuotaScopeCaller, subject})
+	}
+
+	counters := make([]UsageCounter, 0, len(scopes)*len(quotaPeriods))
+	for _, scope := range scopes {
+		for _, period := range quotaPeriods {
+			window, start, end := quotaWindow(period, now)
+			counters = append(counters, UsageCounter{
+				Key:    UsageKey{Scope: scope[0], ID: scope[1], Period: period, Window: window},
+				Limits: q.config.policy(scope[0]).limits(period),
+				// Kept a window past its end, well clear of any clock skew
+				ExpiresAt: end.Add(end.Sub(start)),
+			})
+		}
+	}
+	return q.store.AddUsage(ctx, counters, delta)
+}
+
+// Usage reports the current usage of the query's scope in each period
+func (q *Quotas) Usage(ctx context.Context, query QuotaQuery) ([]QuotaUsage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	id := q.scopeID(query)
+	now := q.clock.Now()
+	usages := make([]QuotaUsage, 0, len(quotaPeriods))
+	for _, period := range quotaPeriods {
+		window, _, end := quotaWindow(period, now)
+		usage, err := q.store.GetUsage(ctx, UsageKey{Scope: query.Scope, ID: id, Period: period, Window: window})
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, QuotaUsage{
+			Scope:    query.Scope,
+			ID:       id,
+			Period:   period,
+			Window:   window,
+			Usage:    usage,
+			Limits:   q.config.policy(query.Scope).limits(period),
+			ResetsAt: end,
+		})
+	}
+	return usages, nil
+}
+
+// Reset drops the current usage of the query's scope in its period, or in
+// every period without one, then reports the usage as Usage does
+func (q *Quotas) Reset(ctx context.Context, query QuotaQuery) ([]QuotaUsage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	periods := quotaPeriods
+	if query.Period != "" {
+		periods = []string{query.Period}
+	}
+	now := q.clock.Now()
+	for _, period := range periods {
+		window, _, _ := quotaWindow(period, now)
+		if err := q.store.ResetUsage(ctx, UsageKey{Scope: query.Scope, ID: q.scopeID(query), Period: period, Window: window}); err != nil {
+			return nil, err
+		}
+	}
+	return q.Usage(ctx, query)
+}
+
+// scopeID returns the ID the query's usage is counted under
+func (q *Quotas) scopeID(query QuotaQuery) string {
+	if query.Scope == QuotaScopeTenant {
+		return q.tenant
+	}
+	return query.ID
+}
+
+// quotaWindow returns the name of the window of period holding t, and when
+// the window starts and ends
+func quotaWindow(period string, t time.Time) (string, time.Time, time.Time) {
+	start := quotaWindowStart(period, t)
+	if period == QuotaPeriodMonth {
+		return start.Format("2006-01"), start, start.AddDate(0, 1, 0)
+	}
+	return start.Format("2006-01-02"), start, start.AddDate(0, 0, 1)
+}
+
+// quotaWindowStart returns when the window of period holding t starts
+func quotaWindowStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == QuotaPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
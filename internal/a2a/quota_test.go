@@ -0,0 +1,221 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// usageDynamoDB keeps usage items in memory, applying the quota store's ADD
+// updates and the :max_ bounds of their conditions
+type usageDynamoDB struct {
+	DynamoDBAPI
+	items   map[string]Usage
+	updates []*dynamodb.UpdateItemInput
+}
+
+func (d *usageDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	d.updates = append(d.updates, params)
+	key := params.Key["quota_key"].(*types.AttributeValueMemberS).Value
+	number := func(name string) int64 {
+		value, ok := params.ExpressionAttributeValues[name].(*types.AttributeValueMemberN)
+		if !ok {
+			return -1
+		}
+		n, _ := strconv.ParseInt(value.Value, 10, 64)
+		return n
+	}
+
+	usage := d.items[key]
+	if params.ConditionExpression != nil {
+		for _, counter := range []struct {
+			name string
+			used int64
+		}{{"requests", usage.Requests}, {"tasks", usage.Tasks}, {"bytes", usage.Bytes}} {
+			if max := number(":max_" + counter.name); max >= 0 && counter.used > max {
+				return nil, &types.ConditionalCheckFailedException{Message: new(string), Item: map[string]types.AttributeValue{
+					"requests": &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Requests, 10)},
+					"tasks":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Tasks, 10)},
+					"bytes":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Bytes, 10)},
+				}}
+			}
+		}
+	}
+	d.items[key] = Usage{Requests: usage.Requests + number(":requests"), Tasks: usage.Tasks + number(":tasks"), Bytes: usage.Bytes + number(":bytes")}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (d *usageDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	usage, ok := d.items[params.Key["quota_key"].(*types.AttributeValueMemberS).Value]
+	if !ok {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+		"requests": &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Requests, 10)},
+		"tasks":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Tasks, 10)},
+		"bytes":    &types.AttributeValueMemberN{Value: strconv.FormatInt(usage.Bytes, 10)},
+	}}, nil
+}
+
+func (d *usageDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(d.items, params.Key["quota_key"].(*types.AttributeValueMemberS).Value)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestQuotasCharge(t *testing.T) {
+	ctx := context.Background()
+	client := &usageDynamoDB{items: map[string]Usage{}}
+	config := QuotaConfig{
+		Table:  "quota",
+		Tenant: QuotaPolicy{Monthly: QuotaLimits{Requests: 100}},
+		Caller: QuotaPolicy{Daily: QuotaLimits{Requests: 2, Bytes: 50}},
+	}
+	clock := &fixedClock{now: time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC)}
+	quotas := NewQuotas(NewAWSQuotaStore(client, "quota", "billing#"), config, "billing", WithClock(clock))
+
+	for range 2 {
+		if err := quotas.Charge(ctx, "alice", Usage{Requests: 1, Tasks: 1, Bytes: 10}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expected := map[string]Usage{
+		"billing#tenant#billing#2025-01-02": {Requests: 2, Tasks: 2, Bytes: 20},
+		"billing#tenant#billing#2025-01":    {Requests: 2, Tasks: 2, Bytes: 20},
+		"billing#caller#alice#2025-01-02":   {Requests: 2, Tasks: 2, Bytes: 20},
+		"billing#caller#alice#2025-01":      {Requests: 2, Tasks: 2, Bytes: 20},
+	}
+	for key, usage := range expected {
+		if client.items[key] != usage {
+			t.Errorf("expected %s to hold %+v, got %+v", key, usage, client.items[key])
+		}
+	}
+	// Day counts are kept a day past their window, month counts a month
+	if expiresAt := client.updates[0].ExpressionAttributeValues[":expires_at"].(*types.AttributeValueMemberN).Value; expiresAt != strconv.FormatInt(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC).Unix(), 10) {
+		t.Errorf("unexpected day expiry %s", expiresAt)
+	}
+
+	// alice's third request of the day is refused, and the tenant's counts
+	// taken before the refusal are given back
+	err := quotas.Charge(ctx, "alice", Usage{Requests: 1})
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "caller alice on 2025-01-02: requests limit of 2") {
+		t.Fatalf("expected alice's daily requests refused, got %v", err)
+	}
+	if usage := client.items["billing#tenant#billing#2025-01"]; usage.Requests != 2 {
+		t.Errorf("expected the refused request given back, got %+v", usage)
+	}
+	// bob is counted apart, and unauthenticated calls only count to the tenant
+	if err := quotas.Charge(ctx, "bob", Usage{Requests: 1}); err != nil {
+		t.Errorf("expected bob's quota to be his own, got %v", err)
+	}
+	if err := quotas.Charge(ctx, "", Usage{Requests: 1}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// A message larger than a whole limit is refused without a write
+	updates := len(client.updates)
+	if err := quotas.Charge(ctx, "carol", Usage{Requests: 1, Bytes: 51}); !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "bytes limit of 50") {
+		t.Errorf("expected carol's message refused, got %v", err)
+	}
+	if writes := len(client.updates) - updates; writes != 4 { // the tenant's two counts, and back
+		t.Errorf("expected only the tenant's counts written and given back, got %d writes", writes)
+	}
+
+	// The next day starts a new daily count
+	clock.now = clock.now.Add(time.Hour)
+	if err := quotas.Charge(ctx, "alice", Usage{Requests: 1}); err != nil {
+		t.Errorf("expected a new day to reset the daily count, got %v", err)
+	}
+}
+
+func TestQuotasUsageAndReset(t *testing.T) {
+	ctx := context.Background()
+	client := &usageDynamoDB{items: map[string]Usage{}}
+	config := QuotaConfig{Table: "quota", Caller: QuotaPolicy{Daily: QuotaLimits{Requests: 5}}}
+	quotas := NewQuotas(NewAWSQuotaStore(client, "quota", ""), config, "billing", WithClock(fixedClock{now: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}))
+	quotas.Charge(ctx, "alice", Usage{Requests: 1, Bytes: 10})
+
+	usages, err := quotas.Usage(ctx, QuotaQuery{Scope: QuotaScopeCaller, ID: "alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	day, month := usages[0], usages[1]
+	if day.Window != "2025-01-02" || day.Usage != (Usage{Requests: 1, Bytes: 10}) || day.Limits.Requests != 5 || !day.ResetsAt.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected day usage %+v", day)
+	}
+	if month.Window != "2025-01" || month.Usage.Requests != 1 || !month.ResetsAt.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected month usage %+v", month)
+	}
+	// The tenant scope is always this agent's
+	if usages, _ := quotas.Usage(ctx, QuotaQuery{Scope: QuotaScopeTenant, ID: "other"}); usages[0].ID != "billing" || usages[0].Usage.Requests != 1 {
+		t.Errorf("expected the agent's own usage, got %+v", usages[0])
+	}
+
+	usages, err = quotas.Reset(ctx, QuotaQuery{Scope: QuotaScopeCaller, ID: "alice", Period: QuotaPeriodDay})
+	if err != nil || usages[0].Usage != (Usage{}) || usages[1].Usage.Requests != 1 {
+		t.Errorf("expected only the day reset, got %+v, %v", usages, err)
+	}
+}
+
+func TestQuotaQueryValidate(t *testing.T) {
+	tests := []struct {
+		query     QuotaQuery
+		expectErr string
+	}{
+		{query: QuotaQuery{Scope: QuotaScopeTenant}},
+		{query: QuotaQuery{Scope: QuotaScopeCaller, ID: "alice", Period: QuotaPeriodMonth}},
+		{query: QuotaQuery{Scope: QuotaScopeCaller}, expectErr: "id is required"},
+		{query: QuotaQuery{Scope: "agent"}, expectErr: "scope must be"},
+		{query: QuotaQuery{Scope: QuotaScopeTenant, Period: "week"}, expectErr: "period must be"},
+	}
+	for _, tt := range tests {
+		err := tt.query.Validate()
+		if tt.expectErr == "" && err != nil || tt.expectErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectErr)) {
+			t.Errorf("%+v: expected %q, got %v", tt.query, tt.expectErr, err)
+		}
+	}
+}
+
+func TestLoadQuotaConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		expect    QuotaConfig
+		expectErr string
+	}{
+		{name: "off by default"},
+		{
+			name:   "limits",
+			env:    map[string]string{"A2A_QUOTA_TABLE": "quota", "A2A_QUOTA_LIMITS": `{"caller":{"daily":{"requests":1000,"tasks":100}}}`},
+			expect: QuotaConfig{Table: "quota", Caller: QuotaPolicy{Daily: QuotaLimits{Requests: 1000, Tasks: 100}}},
+		},
+		{name: "count only", env: map[string]string{"A2A_QUOTA_TABLE": "quota"}, expect: QuotaConfig{Table: "quota"}},
+		{name: "limits need a table", env: map[string]string{"A2A_QUOTA_LIMITS": `{"tenant":{"monthly":{"bytes":1}}}`}, expectErr: "requires A2A_QUOTA_TABLE"},
+		{name: "unknown period", env: map[string]string{"A2A_QUOTA_TABLE": "quota", "A2A_QUOTA_LIMITS": `{"caller":{"weekly":{"requests":1}}}`}, expectErr: "unknown field"},
+		{name: "negative limit", env: map[string]string{"A2A_QUOTA_TABLE": "quota", "A2A_QUOTA_LIMITS": `{"caller":{"daily":{"requests":-1}}}`}, expectErr: "cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("A2A_QUOTA_TABLE", tt.env["A2A_QUOTA_TABLE"])
+			t.Setenv("A2A_QUOTA_LIMITS", tt.env["A2A_QUOTA_LIMITS"])
+			config, err := LoadQuotaConfigFromEnv()
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config != tt.expect {
+				t.Errorf("expected %+v, got %+v", tt.expect, config)
+			}
+		})
+	}
+}
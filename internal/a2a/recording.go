@@ -0,0 +1,289 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Lambda event sources a recording can hold
+const (
+	RecordedSourceAPIGateway = "apigateway"
+	RecordedSourceSQS        = "sqs"
+)
+
+// maxRecordingBytes bounds how much of one recorded event is read back
+const maxRecordingBytes = 16 << 20
+
+// RecordedEvent is one Lambda event as it was received, with credentials
+// redacted. Payload is the source's event JSON, e.g. an
+// events.APIGatewayProxyRequest.
+type RecordedEvent struct {
+	Source     string          `json:"source"`
+	RecordedAt time.Time       `json:"recorded_at"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// EventRecorder stores incoming Lambda events so they can be replayed
+type EventRecorder interface {
+	RecordEvent(ctx context.Context, event RecordedEvent) error
+}
+
+// S3RecordingAPI is the subset of the S3 client used to write and read recordings
+type S3RecordingAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// LoadEventRecorderFromEnv returns the recorder selected by A2A_RECORD_EVENTS,
+// a directory or an s3://bucket/prefix URI, or nil when recording is off.
+// Recordings hold message content, so the destination must be as tightly
+// controlled as the task table.
+func LoadEventRecorderFromEnv(client S3RecordingAPI) (EventRecorder, error) {
+	location := getEnvOrDefault("A2A_RECORD_EVENTS", "")
+	if location == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(location, "s3://") {
+		return NewFileEventRecorder(location), nil
+	}
+	bucket, prefix, err := parseS3Location(location)
+	if err != nil {
+		return nil, fmt.Errorf("A2A_RECORD_EVENTS: %w", err)
+	}
+	return NewS3EventRecorder(client, bucket, prefix), nil
+}
+
+// FileEventRecorder writes each event to a JSON file under a directory. On
+// Lambda only /tmp is writable.
+type FileEventRecorder struct {
+	dir string
+}
+
+// NewFileEventRecorder creates a recorder writing under dir
+func NewFileEventRecorder(dir string) *FileEventRecorder {
+	return &FileEventRecorder{dir: dir}
+}
+
+// RecordEvent implements EventRecorder
+func (r *FileEventRecorder) RecordEvent(ctx context.Context, event RecordedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded event: %w", err)
+	}
+	name := filepath.Join(r.dir, filepath.FromSlash(recordingKey(event)))
+	if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recorded event: %w", err)
+	}
+	return nil
+}
+
+// S3EventRecorder writes each event to an S3 object under a prefix
+type S3EventRecorder struct {
+	client S3RecordingAPI
+	bucket string
+	prefix string
+}
+
+// NewS3EventRecorder creates a recorder writing to bucket under prefix
+func NewS3EventRecorder(client S3RecordingAPI, bucket, prefix string) *S3EventRecorder {
+	return &S3EventRecorder{client: client, bucket: bucket, prefix: prefix}
+}
+
+// RecordEvent implements EventRecorder
+func (r *S3EventRecorder) RecordEvent(ctx context.Context, event RecordedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode recorded event: %w", err)
+	}
+	key := path.Join(r.prefix, recordingKey(event))
+	_, err = r.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(r.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put recorded event s3://%s/%s: %w", r.bucket, key, err)
+	}
+	return nil
+}
+
+// unsafeKeyChars are replaced in request IDs used in file and object names
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// recordingKey names an event so that listing a source's recordings returns
+// them in the order they were received
+func recordingKey(event RecordedEvent) string {
+	name := event.RecordedAt.UTC().Format("20060102T150405.000000000Z")
+	if event.RequestID != "" {
+		name += "-" + unsafeKeyChars.ReplaceAllString(event.RequestID, "_")
+	}
+	return event.Source + "/" + name + ".json"
+}
+
+// LoadRecordedEvents reads the events at location: one recorded file, a
+// directory of them, or an s3://bucket/prefix URI. Events are returned in
+// the order they were recorded. client is only used for S3.
+func LoadRecordedEvents(ctx context.Context, location string, client S3RecordingAPI) ([]RecordedEvent, error) {
+	var events []RecordedEvent
+	decode := func(name string, data []byte) error {
+		var event RecordedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("invalid recorded event %s: %w", name, err)
+		}
+		events = append(events, event)
+		return nil
+	}
+
+	if strings.HasPrefix(location, "s3://") {
+		if err := loadS3Recordings(ctx, client, location, decode); err != nil {
+			return nil, err
+		}
+	} else {
+		err := filepath.WalkDir(location, func(name string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || (name != location && filepath.Ext(name) != ".json") {
+				return err
+			}
+			data, err := os.ReadFile(name)
+			if err != nil {
+				return fmt.Errorf("failed to read recorded event: %w", err)
+			}
+			return decode(name, data)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b RecordedEvent) int {
+		return a.RecordedAt.Compare(b.RecordedAt)
+	})
+	return events, nil
+}
+
+// loadS3Recordings passes every object under an s3://bucket/prefix URI to decode
+func loadS3Recordings(ctx context.Context, client S3RecordingAPI, location string, decode func(name string, data []byte) error) error {
+	bucket, prefix, err := parseS3Location(location)
+	if err != nil {
+		return err
+	}
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{Bucket: aws.String(bucket), Prefix: aws.String(prefix)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list recordings in s3://%s/%s: %w", bucket, prefix, err)
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			if !strings.HasSuffix(key, ".json") {
+				continue
+			}
+			output, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+			if err != nil {
+				return fmt.Errorf("failed to get recording s3://%s/%s: %w", bucket, key, err)
+			}
+			data, err := io.ReadAll(io.LimitReader(output.Body, maxRecordingBytes))
+			output.Body.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read recording s3://%s/%s: %w", bucket, key, err)
+			}
+			if err := decode("s3://"+bucket+"/"+key, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseS3Location splits an s3://bucket/prefix URI; the prefix may be empty
+func parseS3Location(value string) (string, string, error) {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "s3" || parsed.Host == "" {
+		return "", "", fmt.Errorf("must look like s3://bucket/prefix, got %q", value)
+	}
+	return parsed.Host, strings.TrimPrefix(parsed.Path, "/"), nil
+}
+
+// RedactHeaders returns a copy of headers with credential values, cookies and
+// API keys replaced by RedactedValue, for recording requests
+func RedactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if isCredentialHeader(name) {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// RedactMultiValueHeaders is RedactHeaders for headers with several values
+func RedactMultiValueHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if isCredentialHeader(name) {
+			values = []string{RedactedValue}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// isCredentialHeader matches the credential names redacted from logs, plus
+// the usual API key and cookie headers
+func isCredentialHeader(name string) bool {
+	lower := strings.ToLower(name)
+	if lower == "cookie" || lower == "set-cookie" || strings.Contains(lower, "api-key") {
+		return true
+	}
+	return (&logRules{}).isRedactedKey(lower)
+}
+
+// RedactNotificationBody returns a queued PushNotification body with the push
+// config's token and credentials replaced by RedactedValue. A body that does
+// not decode is returned unchanged.
+func RedactNotificationBody(body string) string {
+	var notification PushNotification
+	if err := json.Unmarshal([]byte(body), &notification); err != nil {
+		return body
+	}
+	redacted := RedactedValue
+	if notification.PushConfig.Token != nil {
+		notification.PushConfig.Token = &redacted
+	}
+	if auth := notification.PushConfig.Auth; auth != nil && auth.Credentials != nil {
+		authCopy := *auth
+		authCopy.Credentials = &redacted
+		notification.PushConfig.Auth = &authCopy
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
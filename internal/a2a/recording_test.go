@@ -0,0 +1,197 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)] = string(body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	prefix := aws.ToString(params.Bucket) + "/" + aws.ToString(params.Prefix)
+	var keys []string
+	for name := range f.objects {
+		if strings.HasPrefix(name, prefix) {
+			keys = append(keys, strings.TrimPrefix(name, aws.ToString(params.Bucket)+"/"))
+		}
+	}
+	sort.Strings(keys)
+	output := &s3.ListObjectsV2Output{}
+	for _, key := range keys {
+		output.Contents = append(output.Contents, s3types.Object{Key: aws.String(key)})
+	}
+	return output, nil
+}
+
+func testRecordedEvents() []RecordedEvent {
+	start := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	return []RecordedEvent{
+		{Source: RecordedSourceSQS, RecordedAt: start.Add(time.Second), RequestID: "msg/1", Payload: json.RawMessage(`{"Records":[]}`)},
+		{Source: RecordedSourceAPIGateway, RecordedAt: start, RequestID: "req-1", Payload: json.RawMessage(`{"httpMethod":"POST"}`)},
+	}
+}
+
+func TestEventRecorders(t *testing.T) {
+	dir := t.TempDir()
+	objects := &fakeS3{objects: map[string]string{"other/prefix/x.json": "not a recording"}}
+
+	tests := []struct {
+		name     string
+		recorder EventRecorder
+		location string
+	}{
+		{name: "file", recorder: NewFileEventRecorder(dir), location: dir},
+		{name: "s3", recorder: NewS3EventRecorder(objects, "bucket", "recordings"), location: "s3://bucket/recordings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, event := range testRecordedEvents() {
+				if err := tt.recorder.RecordEvent(context.Background(), event); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			loaded, err := LoadRecordedEvents(context.Background(), tt.location, objects)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(loaded) != 2 || loaded[0].RequestID != "req-1" || loaded[1].RequestID != "msg/1" {
+				t.Fatalf("expected both events in recorded order, got %+v", loaded)
+			}
+			if string(loaded[0].Payload) != `{"httpMethod":"POST"}` {
+				t.Errorf("expected the payload unchanged, got %s", loaded[0].Payload)
+			}
+		})
+	}
+
+	// A single recorded file can be replayed on its own
+	single := filepath.Join(dir, RecordedSourceAPIGateway, "20250801T120000.000000000Z-req-1.json")
+	loaded, err := LoadRecordedEvents(context.Background(), single, nil)
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("expected one event from %s, got %+v, %v", single, loaded, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("not json"), 0o600)
+	if _, err := LoadRecordedEvents(context.Background(), dir, nil); err == nil {
+		t.Error("expected an error for an invalid recording")
+	}
+}
+
+func TestLoadEventRecorderFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		location    string
+		expectNil   bool
+		expectError bool
+		expectType  string
+	}{
+		{name: "unset", expectNil: true},
+		{name: "directory", location: "/tmp/recordings", expectType: "file"},
+		{name: "s3", location: "s3://bucket/prefix", expectType: "s3"},
+		{name: "s3 without bucket", location: "s3:///prefix", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			if tt.location != "" {
+				os.Setenv("A2A_RECORD_EVENTS", tt.location)
+			}
+
+			recorder, err := LoadEventRecorderFromEnv(&fakeS3{})
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch recorder.(type) {
+			case nil:
+				if !tt.expectNil {
+					t.Error("expected a recorder")
+				}
+			case *FileEventRecorder:
+				if tt.expectType != "file" {
+					t.Errorf("expected %s recorder, got file", tt.expectType)
+				}
+			case *S3EventRecorder:
+				if tt.expectType != "s3" {
+					t.Errorf("expected %s recorder, got s3", tt.expectType)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := RedactHeaders(map[string]string{
+		"authorization": "Bearer secret",
+		"x-api-key":     "secret",
+		"Cookie":        "session=secret",
+		"content-type":  "application/json",
+	})
+	for _, name := range []string{"authorization", "x-api-key", "Cookie"} {
+		if headers[name] != RedactedValue {
+			t.Errorf("expected %s to be redacted, got %q", name, headers[name])
+		}
+	}
+	if headers["content-type"] != "application/json" {
+		t.Errorf("expected content-type to be kept, got %q", headers["content-type"])
+	}
+
+	multi := RedactMultiValueHeaders(map[string][]string{"Authorization": {"a", "b"}, "Accept": {"*/*"}})
+	if len(multi["Authorization"]) != 1 || multi["Authorization"][0] != RedactedValue || multi["Accept"][0] != "*/*" {
+		t.Errorf("unexpected multi-value headers %v", multi)
+	}
+}
+
+func TestRedactNotificationBody(t *testing.T) {
+	token, credentials := "push-token", "bearer-secret"
+	body, _ := json.Marshal(PushNotification{
+		PushConfig: a2a.PushConfig{URL: "https://example.com/hook", Token: &token, Auth: &a2a.PushAuthInfo{Schemes: []string{"Bearer"}, Credentials: &credentials}},
+		Event:      json.RawMessage(`{"TaskID":"task-1"}`),
+	})
+
+	redacted := RedactNotificationBody(string(body))
+	if strings.Contains(redacted, token) || strings.Contains(redacted, credentials) {
+		t.Errorf("expected the token and credentials to be redacted, got %s", redacted)
+	}
+	var notification PushNotification
+	if err := json.Unmarshal([]byte(redacted), &notification); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notification.PushConfig.URL != "https://example.com/hook" || string(notification.Event) != `{"TaskID":"task-1"}` {
+		t.Errorf("expected the URL and event to be kept, got %+v", notification)
+	}
+
+	if got := RedactNotificationBody("not json"); got != "not json" {
+		t.Errorf("expected an undecodable body unchanged, got %q", got)
+	}
+}
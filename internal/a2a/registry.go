@@ -0,0 +1,238 @@
+package a2a
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// AgentConfig describes one agent served by a multi-agent deployment. The
+// agent is served under /agents/{id}.
+type AgentConfig struct {
+	ID        string        `json:"id"`
+	AgentCard a2a.AgentCard `json:"agent_card"`
+	// StoragePrefix namespaces this agent's keys in the shared task and event
+	// tables. Defaults to the agent ID followed by "#".
+	StoragePrefix string `json:"storage_prefix,omitempty"`
+	// Retention bounds how long this agent's tasks and events are kept
+	Retention RetentionConfig `json:"retention,omitempty"`
+	// TaskTimeout fails this agent's stuck tasks, as for ServerlessConfig
+	TaskTimeout string `json:"task_timeout,omitempty"`
+	// Retries are this agent's retry policies, as for ServerlessConfig
+	Retries map[string]RetryPolicy `json:"retries,omitempty"`
+	// Priorities are this agent's skill priorities, as for ServerlessConfig
+	Priorities map[string]TaskPriority `json:"priorities,omitempty"`
+	// OutputSchemas are this agent's skill output schemas, as for
+	// ServerlessConfig
+	OutputSchemas map[string]map[string]any `json:"output_schemas,omitempty"`
+	// Localization translates this agent's card, as for ServerlessConfig
+	Localization LocalizationConfig `json:"localization,omitempty"`
+}
+
+// agentIDPattern keeps IDs usable as a single URL path segment
+var agentIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// AgentRegistryConfig configures several agents sharing one deployment's
+// cloud resources
+type AgentRegistryConfig struct {
+	Agents      []AgentConfig       `json:"agents"`
+	CloudConfig CloudProviderConfig `json:"cloud_config"`
+	LogLevel    string              `json:"log_level"`
+	Logging     LoggingConfig       `json:"logging,omitempty"`
+	Security    SecurityConfig      `json:"security"`
+	Secrets     SecretsConfig       `json:"secrets"`
+	// Admin places each agent's admin API under its /agents/{id} path
+	Admin AdminConfig `json:"admin,omitempty"`
+}
+
+// AgentRegistry holds the agents of a multi-agent deployment in config order
+type AgentRegistry struct {
+	config AgentRegistryConfig
+	byID   map[string]int
+}
+
+// NewAgentRegistry validates a registry config and indexes its agents by ID
+func NewAgentRegistry(config AgentRegistryConfig) (*AgentRegistry, error) {
+	if err := ValidateAgentRegistryConfig(config); err != nil {
+		return nil, fmt.Errorf("agent registry validation failed: %w", err)
+	}
+
+	agents := make([]AgentConfig, len(config.Agents))
+	byID := make(map[string]int, len(config.Agents))
+	for i, agent := range config.Agents {
+		agent.StoragePrefix = agentStoragePrefix(agent)
+		agents[i] = agent
+		byID[agent.ID] = i
+	}
+	config.Agents = agents
+
+	return &AgentRegistry{config: config, byID: byID}, nil
+}
+
+// Get returns the agent with the given ID
+func (r *AgentRegistry) Get(agentID string) (AgentConfig, bool) {
+	i, ok := r.byID[agentID]
+	if !ok {
+		return AgentConfig{}, false
+	}
+	return r.config.Agents[i], true
+}
+
+// Agents returns every registered agent in config order
+func (r *AgentRegistry) Agents() []AgentConfig {
+	agents := make([]AgentConfig, len(r.config.Agents))
+	copy(agents, r.config.Agents)
+	return agents
+}
+
+// LogLevel returns the log level shared by every agent
+func (r *AgentRegistry) LogLevel() string {
+	return r.config.LogLevel
+}
+
+// Logging returns the logging config shared by every agent
+func (r *AgentRegistry) Logging() LoggingConfig {
+	return r.config.Logging
+}
+
+// ServerlessConfig returns the single-agent view of a registered agent, so
+// code written for one agent can serve any agent in the registry
+func (r *AgentRegistry) ServerlessConfig(agentID string) (ServerlessConfig, bool) {
+	agent, ok := r.Get(agentID)
+	if !ok {
+		return ServerlessConfig{}, false
+	}
+	config := ServerlessConfig{
+		AgentID:       agent.ID,
+		AgentCard:     agent.AgentCard,
+		CloudConfig:   r.config.CloudConfig,
+		LogLevel:      r.config.LogLevel,
+		Logging:       r.config.Logging,
+		Security:      r.config.Security,
+		Secrets:       r.config.Secrets,
+		Retention:     agent.Retention,
+		TaskTimeout:   agent.TaskTimeout,
+		Retries:       agent.Retries,
+		Priorities:    agent.Priorities,
+		OutputSchemas: agent.OutputSchemas,
+		Localization:  agent.Localization,
+		Admin:         r.config.Admin,
+	}
+	// Same as a single-agent config file: the card advertises the shared schemes
+	if config.Security.hasSchemes() {
+		WithSecurity(config.Security)(&config.AgentCard)
+	}
+	// and the subsystems the deployment wires are for the capabilities
+	WithDeploymentCapabilities(LambdaDeploymentFeatures(config))(&config.AgentCard)
+	return config, true
+}
+
+// ValidateAgentRegistryConfig validates a multi-agent config
+func ValidateAgentRegistryConfig(config AgentRegistryConfig) error {
+	var errs ValidationErrors
+	if len(config.Agents) == 0 {
+		errs.Add("agents", ValidationCodeRequired, "must list at least one agent")
+	}
+
+	ids := make(map[string]bool)
+	var prefixes []string
+	for i, agent := range config.Agents {
+		path := fmt.Sprintf("agents[%d]", i)
+		if agent.ID == "" {
+			errs.Add(path+".id", ValidationCodeRequired, "is required")
+		} else if !agentIDPattern.MatchString(agent.ID) {
+			errs.Add(path+".id", ValidationCodeInvalid, fmt.Sprintf("'%s' may only contain letters, digits, '.', '_' and '-'", agent.ID))
+		} else if ids[agent.ID] {
+			errs.Add(path+".id", ValidationCodeDuplicate, fmt.Sprintf("'%s' is duplicated", agent.ID))
+		}
+		ids[agent.ID] = true
+
+		if agent.AgentCard.Name == "" {
+			errs.Add(path+".agent_card.Name", ValidationCodeRequired, "is required")
+		}
+		if agent.AgentCard.URL == "" {
+			errs.Add(path+".agent_card.URL", ValidationCodeRequired, "is required")
+		} else {
+			errs.Merge(path, ValidateAgentURL(agent.AgentCard.URL))
+		}
+		errs.Merge(path+".retention", ValidateRetentionConfig(agent.Retention))
+		errs.Merge(path+".task_timeout", ValidateTaskTimeout(agent.TaskTimeout))
+		errs.Merge(path+".retries", ValidateRetryPolicies(agent.Retries, agent.AgentCard.Skills))
+		errs.Merge(path+".priorities", ValidateSkillPriorities(agent.Priorities, agent.AgentCard.Skills))
+		errs.Merge(path+".output_schemas", ValidateOutputSchemas(agent.OutputSchemas, agent.AgentCard.Skills))
+		errs.Merge(path+".localization", ValidateLocalizationConfig(agent.Localization, agent.AgentCard.Skills))
+
+		// Keys are the prefix followed by an arbitrary ID, so when one prefix
+		// starts with another ("a#" and "a#b") the shorter one's keys include
+		// the longer one's and the agents could read each other's tasks
+		if agent.ID != "" {
+			prefix := agentStoragePrefix(agent)
+			for _, other := range prefixes {
+				if strings.HasPrefix(prefix, other) || strings.HasPrefix(other, prefix) {
+					errs.Add(path+".storage_prefix", ValidationCodeConflict, fmt.Sprintf("'%s' overlaps '%s' used by another agent", prefix, other))
+					break
+				}
+			}
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	errs.Merge("logging", ValidateLoggingConfig(config.Logging))
+	errs.Merge("security", ValidateSecurityConfig(config.Security))
+	errs.Merge("admin", ValidateAdminConfig(config.Admin))
+	if config.Security.APIKeyHeader != "" && config.Secrets.APIKey == "" {
+		errs.Add("secrets.api_key", ValidationCodeRequired, "is required when security.api_key_header is set")
+	}
+	if config.Security.SignatureHeader != "" && config.Secrets.RequestSigningKey == "" {
+		errs.Add("secrets.request_signing_key", ValidationCodeRequired, "is required when security.signature_header is set")
+	}
+	errs.Merge("", validateContentEncryptionKey(config.Secrets.ContentEncryptionKey))
+	errs.Merge("cloud_config", ValidateCloudProviderConfig(config.CloudConfig))
+	return errs.Err()
+}
+
+// ParseAgentRegistryConfig parses a JSON-encoded registry config and builds the registry
+func ParseAgentRegistryConfig(data []byte) (*AgentRegistry, error) {
+	var config AgentRegistryConfig
+	if err := FromJSON(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid agent registry JSON: %w", err)
+	}
+	return NewAgentRegistry(config)
+}
+
+// LoadAgentRegistryFromEnv loads the registry from the file named by
+// A2A_AGENT_REGISTRY_FILE. Returns nil when it is unset, meaning the
+// deployment serves a single agent.
+func LoadAgentRegistryFromEnv() (*AgentRegistry, error) {
+	path := getEnvOrDefault("A2A_AGENT_REGISTRY_FILE", "")
+	if path == "" {
+		return nil, nil
+	}
+	// The registry replaces the single-agent config, so both would be ambiguous
+	for _, name := range configSourceEnvVars {
+		if os.Getenv(name) != "" {
+			return nil, fmt.Errorf("A2A_AGENT_REGISTRY_FILE cannot be combined with %s", name)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent registry file: %w", err)
+	}
+	registry, err := ParseAgentRegistryConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return registry, nil
+}
+
+// agentStoragePrefix returns the configured storage prefix or the ID-based default
+func agentStoragePrefix(agent AgentConfig) string {
+	if agent.StoragePrefix != "" {
+		return agent.StoragePrefix
+	}
+	return agent.ID + "#"
+}
@@ -0,0 +1,199 @@
+package a2a
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testRegistryJSON = `{
+	"agents": [
+		{"id": "billing", "agent_card": {"Name": "Billing", "URL": "https://agents.example.com/billing"}},
+		{"id": "support", "agent_card": {"Name": "Support", "URL": "https://agents.example.com/support"}, "storage_prefix": "sup/"}
+	],
+	"cloud_config": {"provider": "local"},
+	"log_level": "debug",
+	"security": {"api_key_header": "X-API-Key"},
+	"secrets": {"api_key": "shared-key"}
+}`
+
+func TestParseAgentRegistryConfig(t *testing.T) {
+	registry, err := ParseAgentRegistryConfig([]byte(testRegistryJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	agents := registry.Agents()
+	if len(agents) != 2 || agents[0].ID != "billing" || agents[1].ID != "support" {
+		t.Fatalf("expected agents in config order, got %+v", agents)
+	}
+
+	billing, ok := registry.Get("billing")
+	if !ok {
+		t.Fatal("expected billing agent to be registered")
+	}
+	if billing.StoragePrefix != "billing#" {
+		t.Errorf("expected default storage prefix 'billing#', got '%s'", billing.StoragePrefix)
+	}
+	if support, _ := registry.Get("support"); support.StoragePrefix != "sup/" {
+		t.Errorf("expected configured storage prefix 'sup/', got '%s'", support.StoragePrefix)
+	}
+	if _, ok := registry.Get("missing"); ok {
+		t.Error("expected unknown agent to be missing")
+	}
+
+	config, ok := registry.ServerlessConfig("support")
+	if !ok {
+		t.Fatal("expected serverless config for support agent")
+	}
+	if config.AgentID != "support" || config.AgentCard.Name != "Support" || config.LogLevel != "debug" || config.CloudConfig.Provider != "local" {
+		t.Errorf("unexpected serverless config: %+v", config)
+	}
+	if config.Secrets.APIKey != "shared-key" || config.AgentCard.SecuritySchemes[SecuritySchemeAPIKey] == nil {
+		t.Errorf("expected shared security on every agent, got %+v", config)
+	}
+	if err := ValidateServerlessConfig(config); err != nil {
+		t.Errorf("expected per-agent config to be valid, got %v", err)
+	}
+}
+
+func TestValidateAgentRegistryConfig(t *testing.T) {
+	local := CloudProviderConfig{Provider: "local"}
+	agent := func(id, prefix string) AgentConfig {
+		a := AgentConfig{ID: id, StoragePrefix: prefix}
+		a.AgentCard.Name = id
+		a.AgentCard.URL = "https://agents.example.com/" + id
+		return a
+	}
+
+	tests := []struct {
+		name     string
+		config   AgentRegistryConfig
+		expected []string
+	}{
+		{
+			name:   "valid",
+			config: AgentRegistryConfig{Agents: []AgentConfig{agent("a", ""), agent("b", "")}, CloudConfig: local},
+		},
+		{
+			name:     "no agents",
+			config:   AgentRegistryConfig{CloudConfig: local},
+			expected: []string{"agents"},
+		},
+		{
+			name:     "duplicate ID",
+			config:   AgentRegistryConfig{Agents: []AgentConfig{agent("a", ""), agent("a", "other#")}, CloudConfig: local},
+			expected: []string{"agents[1].id"},
+		},
+		{
+			name:     "shared storage prefix",
+			config:   AgentRegistryConfig{Agents: []AgentConfig{agent("a", ""), agent("b", "a#")}, CloudConfig: local},
+			expected: []string{"agents[1].storage_prefix"},
+		},
+		{
+			name:     "overlapping storage prefix",
+			config:   AgentRegistryConfig{Agents: []AgentConfig{agent("a", ""), agent("b", "a#b")}, CloudConfig: local},
+			expected: []string{"agents[1].storage_prefix"},
+		},
+		{
+			name:     "prefix covering a later agent",
+			config:   AgentRegistryConfig{Agents: []AgentConfig{agent("ab", ""), agent("b", "a")}, CloudConfig: local},
+			expected: []string{"agents[1].storage_prefix"},
+		},
+		{
+			name: "invalid retention",
+			config: func() AgentRegistryConfig {
+				a := agent("a", "")
+				a.Retention = RetentionConfig{Tasks: "30d", Events: "a week"}
+				return AgentRegistryConfig{Agents: []AgentConfig{a}, CloudConfig: local}
+			}(),
+			expected: []string{"agents[0].retention.events"},
+		},
+		{
+			name:     "id is not a path segment",
+			config:   AgentRegistryConfig{Agents: []AgentConfig{agent("a/b", "")}, CloudConfig: local},
+			expected: []string{"agents[0].id"},
+		},
+		{
+			name: "api key without secret",
+			config: AgentRegistryConfig{
+				Agents:      []AgentConfig{agent("a", "")},
+				CloudConfig: local,
+				Security:    SecurityConfig{APIKeyHeader: "X-API-Key"},
+			},
+			expected: []string{"secrets.api_key"},
+		},
+		{
+			name: "signature header without secret",
+			config: AgentRegistryConfig{
+				Agents:      []AgentConfig{agent("a", "")},
+				CloudConfig: local,
+				Security:    SecurityConfig{SignatureHeader: "X-Signature"},
+			},
+			expected: []string{"secrets.request_signing_key"},
+		},
+		{
+			name: "missing fields",
+			config: AgentRegistryConfig{
+				Agents:      []AgentConfig{{ID: "a"}},
+				CloudConfig: CloudProviderConfig{Provider: "aws"},
+			},
+			expected: []string{"agents[0].agent_card.Name", "agents[0].agent_card.URL", "cloud_config.aws"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgentRegistryConfig(tt.config)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(errs) != len(tt.expected) {
+				t.Fatalf("expected %d field errors, got %v", len(tt.expected), errs)
+			}
+			for i, path := range tt.expected {
+				if errs[i].Path != path {
+					t.Errorf("field error %d: expected path %s, got %s", i, path, errs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadAgentRegistryFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	registry, err := LoadAgentRegistryFromEnv()
+	if err != nil || registry != nil {
+		t.Fatalf("expected no registry when unset, got %v, %v", registry, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "agents.json")
+	if err := os.WriteFile(path, []byte(testRegistryJSON), 0o600); err != nil {
+		t.Fatalf("failed to write registry file: %v", err)
+	}
+	os.Setenv("A2A_AGENT_REGISTRY_FILE", path)
+
+	registry, err = LoadAgentRegistryFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(registry.Agents()) != 2 {
+		t.Errorf("expected 2 agents, got %d", len(registry.Agents()))
+	}
+
+	os.Setenv("A2A_CONFIG_SECRET_ID", "a2a-config")
+	if _, err := LoadAgentRegistryFromEnv(); err == nil {
+		t.Error("expected error when combined with a config source")
+	}
+}
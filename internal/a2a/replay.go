@@ -0,0 +1,83 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrReplayed is returned by NonceStore.UseNonce for a nonce already used
+var ErrReplayed = errors.New("nonce already used")
+
+// NonceStore remembers the nonces of authenticated requests for as long as
+// a copy of the request would still pass its timestamp or expiry check, so
+// a captured request cannot be sent again within that window.
+type NonceStore interface {
+	// UseNonce records nonce until expiresAt, or returns ErrReplayed when
+	// it is already recorded and has not expired
+	UseNonce(ctx context.Context, nonce string, expiresAt time.Time) error
+}
+
+// MemoryNonceStore implements NonceStore in memory. It only protects one
+// process, so use it for a long-lived server rather than on Lambda, where
+// each instance would keep its own nonces.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	runtimeDeps
+}
+
+// NewMemoryNonceStore creates an empty in-memory nonce store
+func NewMemoryNonceStore(opts ...RuntimeOption) *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces:      make(map[string]time.Time),
+		runtimeDeps: newRuntimeDeps(opts),
+	}
+}
+
+// UseNonce implements NonceStore, dropping expired nonces as it goes
+func (s *MemoryNonceStore) UseNonce(ctx context.Context, nonce string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for seen, expiry := range s.nonces {
+		if !now.Before(expiry) {
+			delete(s.nonces, seen)
+		}
+	}
+	if _, ok := s.nonces[nonce]; ok {
+		return ErrReplayed
+	}
+	s.nonces[nonce] = expiresAt
+	return nil
+}
+
+// AuthenticatorOption configures an Authenticator
+type AuthenticatorOption func(*Authenticator)
+
+// WithNonceStore rejects a signed request whose signature was already
+// accepted, and enables security.single_use_tokens for bearer tokens
+func WithNonceStore(store NonceStore) AuthenticatorOption {
+	return func(a *Authenticator) {
+		a.nonces = store
+	}
+}
+
+// useNonce records a nonce, describing a replay or a store failure as the
+// reason a credential was refused. A request whose nonce cannot be recorded
+// is refused too, since it cannot be told apart from a replay.
+func useNonce(ctx context.Context, store NonceStore, nonce string, expiresAt time.Time) error {
+	if store == nil {
+		return errors.New("replay protection has no nonce store")
+	}
+	if err := store.UseNonce(ctx, nonce, expiresAt); err != nil {
+		if errors.Is(err, ErrReplayed) {
+			return errors.New("request replayed")
+		}
+		return fmt.Errorf("cannot check for replay: %w", err)
+	}
+	return nil
+}
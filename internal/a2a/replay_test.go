@@ -0,0 +1,162 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	ctx := context.Background()
+	clock := &fixedClock{now: time.Unix(1754049600, 0)}
+	store := NewMemoryNonceStore(WithClock(clock))
+
+	if err := store.UseNonce(ctx, "n-1", clock.now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.UseNonce(ctx, "n-1", clock.now.Add(time.Minute)); !errors.Is(err, ErrReplayed) {
+		t.Errorf("expected ErrReplayed, got %v", err)
+	}
+	if err := store.UseNonce(ctx, "n-2", clock.now.Add(time.Minute)); err != nil {
+		t.Errorf("expected another nonce to be accepted, got %v", err)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if err := store.UseNonce(ctx, "n-1", clock.now.Add(time.Minute)); err != nil {
+		t.Errorf("expected an expired nonce to be reusable, got %v", err)
+	}
+}
+
+func TestAWSNonceStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1754049600, 0)
+
+	client, requests := recordingDynamoDB(t)
+	store := NewAWSNonceStore(client, "nonces", "billing#", WithClock(fixedClock{now}))
+	if err := store.UseNonce(ctx, "n-1", now.Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	request := (*requests)[0]
+	if got := attributeS(request, "Item", "nonce"); got != "billing#n-1" {
+		t.Errorf("expected a prefixed nonce key, got %q", got)
+	}
+	if request["ConditionExpression"] != "attribute_not_exists(nonce) OR expires_at <= :now" {
+		t.Errorf("expected a conditional put, got %v", request["ConditionExpression"])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+	}))
+	defer server.Close()
+	rejecting := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	})
+	err := NewAWSNonceStore(rejecting, "nonces", "").UseNonce(ctx, "n-1", now.Add(time.Minute))
+	if !errors.Is(err, ErrReplayed) {
+		t.Errorf("expected ErrReplayed for a failed condition, got %v", err)
+	}
+}
+
+func TestAuthenticateSignatureReplay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1754049600, 0)
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"tasks/get","params":{"id":"task-1"}}`)
+	headers := map[string]string{
+		"X-Signature":           SignRequest("signing-key", now.Unix(), body),
+		"X-Signature-Timestamp": strconv.FormatInt(now.Unix(), 10),
+	}
+
+	authenticator := NewAuthenticator(SecurityConfig{SignatureHeader: "X-Signature", ReplayTable: "nonces"},
+		SecretsConfig{RequestSigningKey: "signing-key"}, nil, WithNonceStore(NewMemoryNonceStore(WithClock(fixedClock{now}))))
+	authenticator.signature.now = func() time.Time { return now }
+
+	if _, err := authenticator.Authenticate(ctx, headers, body); err != nil {
+		t.Fatalf("expected the first request to be accepted, got %v", err)
+	}
+	_, err := authenticator.Authenticate(ctx, headers, body)
+	if !errors.Is(err, ErrUnauthenticated) || !strings.Contains(err.Error(), "request replayed") {
+		t.Errorf("expected the replay to be refused, got %v", err)
+	}
+}
+
+func TestAuthenticateSingleUseTokens(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t)
+	claims := func(jti string) map[string]any {
+		claims := map[string]any{
+			"iss": issuer.server.URL,
+			"sub": "client-1",
+			"aud": "agent",
+			"exp": time.Now().Unix() + 300,
+		}
+		if jti != "" {
+			claims["jti"] = jti
+		}
+		return claims
+	}
+	config := SecurityConfig{
+		OIDCMetadataURL: issuer.server.URL + "/.well-known/openid-configuration",
+		Audience:        "agent",
+		SingleUseTokens: true,
+		ReplayTable:     "nonces",
+	}
+	authenticator := NewAuthenticator(config, SecretsConfig{}, nil, WithNonceStore(NewMemoryNonceStore()))
+	bearer := func(claims map[string]any) map[string]string {
+		return map[string]string{"Authorization": "Bearer " + issuer.sign(t, "test-key", claims)}
+	}
+
+	token := bearer(claims("token-1"))
+	if _, err := authenticator.Authenticate(ctx, token, nil); err != nil {
+		t.Fatalf("expected the first use to be accepted, got %v", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, token, nil); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("expected a reused token to be refused, got %v", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, bearer(claims("token-2")), nil); err != nil {
+		t.Errorf("expected a fresh token to be accepted, got %v", err)
+	}
+	if _, err := authenticator.Authenticate(ctx, bearer(claims("")), nil); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("expected a token without jti to be refused, got %v", err)
+	}
+}
+
+func TestValidateSecurityConfigReplay(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      SecurityConfig
+		expectError string
+	}{
+		{name: "signature replay protection", config: SecurityConfig{SignatureHeader: "X-Signature", ReplayTable: "nonces"}},
+		{name: "single-use tokens", config: SecurityConfig{OIDCMetadataURL: "https://auth.example.com/.well-known/openid-configuration", SingleUseTokens: true, ReplayTable: "nonces"}},
+		{name: "table without a scheme to protect", config: SecurityConfig{APIKeyHeader: "X-API-Key", ReplayTable: "nonces"}, expectError: "replay_table requires signature_header or single_use_tokens"},
+		{name: "single-use without bearer tokens", config: SecurityConfig{SingleUseTokens: true, ReplayTable: "nonces"}, expectError: "single_use_tokens requires oauth2_token_url or oidc_metadata_url"},
+		{name: "single-use without a table", config: SecurityConfig{OIDCMetadataURL: "https://auth.example.com/.well-known/openid-configuration", SingleUseTokens: true}, expectError: "replay_table is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecurityConfig(tt.config)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Kinds of stored data a retention policy covers, as counted by the sweeper
+const (
+	RetentionKindTasks     = "tasks"
+	RetentionKindEvents    = "events"
+	RetentionKindArtifacts = "artifacts"
+)
+
+// RetentionConfig bounds how long an agent's stored data is kept after its
+// last write. Each value is a duration such as 720h, or a number of days
+// such as 30d; empty keeps the data until it is deleted. Artifacts live in
+// their task and in artifact-update events, so the artifacts retention,
+// when set, applies to those instead.
+type RetentionConfig struct {
+	Tasks     string `json:"tasks,omitempty"`
+	Events    string `json:"events,omitempty"`
+	Artifacts string `json:"artifacts,omitempty"`
+}
+
+// LoadRetentionConfigFromEnv reads A2A_RETENTION_TASKS, A2A_RETENTION_EVENTS
+// and A2A_RETENTION_ARTIFACTS
+func LoadRetentionConfigFromEnv() RetentionConfig {
+	return RetentionConfig{
+		Tasks:     getEnvOrDefault("A2A_RETENTION_TASKS", ""),
+		Events:    getEnvOrDefault("A2A_RETENTION_EVENTS", ""),
+		Artifacts: getEnvOrDefault("A2A_RETENTION_ARTIFACTS", ""),
+	}
+}
+
+// ValidateRetentionConfig checks that each retention is a positive duration
+func ValidateRetentionConfig(config RetentionConfig) error {
+	var errs ValidationErrors
+	for _, field := range []struct{ path, value string }{
+		{"tasks", config.Tasks},
+		{"events", config.Events},
+		{"artifacts", config.Artifacts},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := parseRetention(field.value); err != nil {
+			errs.Add(field.path, ValidationCodeInvalid, fmt.Sprintf("'%s' must be a positive duration such as 720h or 30d", field.value))
+		}
+	}
+	return errs.Err()
+}
+
+// Policy returns the retention as durations. Invalid values are rejected by
+// ValidateRetentionConfig, so they keep data here.
+func (c RetentionConfig) Policy() RetentionPolicy {
+	tasks, _ := parseRetention(c.Tasks)
+	events, _ := parseRetention(c.Events)
+	artifacts, _ := parseRetention(c.Artifacts)
+	return RetentionPolicy{Tasks: tasks, Events: events, Artifacts: artifacts}
+}
+
+// parseRetention reads a duration, or a whole number of days with a d suffix
+func parseRetention(value string) (time.Duration, error) {
+	var retention time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		retention = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if retention, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+	if retention <= 0 {
+		return 0, errors.New("retention must be positive")
+	}
+	return retention, nil
+}
+
+// RetentionPolicy is a RetentionConfig as durations; 0 keeps data
+type RetentionPolicy struct {
+	Tasks     time.Duration
+	Events    time.Duration
+	Artifacts time.Duration
+}
+
+// Enabled reports whether any data expires
+func (p RetentionPolicy) Enabled() bool {
+	return p.Tasks > 0 || p.Events > 0 || p.Artifacts > 0
+}
+
+// TaskRetention returns how long task is kept after it is saved, and the
+// kind of retention that applies
+func (p RetentionPolicy) TaskRetention(task a2a.Task) (time.Duration, string) {
+	if p.Artifacts > 0 && len(task.Artifacts) > 0 {
+		return p.Artifacts, RetentionKindArtifacts
+	}
+	return p.Tasks, RetentionKindTasks
+}
+
+// EventRetention returns how long event is kept after it is saved, and the
+// kind of retention that applies
+func (p RetentionPolicy) EventRetention(event a2a.Event) (time.Duration, string) {
+	if p.Artifacts > 0 {
+		switch e := event.(type) {
+		case a2a.TaskArtifactUpdateEvent:
+			return p.Artifacts, RetentionKindArtifacts
+		case a2a.Task:
+			if len(e.Artifacts) > 0 {
+				return p.Artifacts, RetentionKindArtifacts
+			}
+		}
+	}
+	return p.Events, RetentionKindEvents
+}
+
+// WithRetention makes the AWS task and event stores write an expires_at
+// attribute by policy, for the tables' TTL to delete the items
+func WithRetention(policy RetentionPolicy) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.retention = policy
+	}
+}
+
+// expireItem sets an item to expire retention from now; a zero retention
+// keeps it
+func (d runtimeDeps) expireItem(item map[string]types.AttributeValue, retention time.Duration) {
+	if retention > 0 {
+		item["expires_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(d.clock.Now().Add(retention).Unix(), 10)}
+	}
+}
+
+// SweepCounts counts the items a sweep deleted by kind of retention
+type SweepCounts struct {
+	Tasks     int `json:"tasks"`
+	Events    int `json:"events"`
+	Artifacts int `json:"artifacts"`
+}
+
+// Add counts n items of kind
+func (c *SweepCounts) Add(kind string, n int) {
+	switch kind {
+	case RetentionKindTasks:
+		c.Tasks += n
+	case RetentionKindEvents:
+		c.Events += n
+	case RetentionKindArtifacts:
+		c.Artifacts += n
+	}
+}
+
+// ExpiringStore is a store without native TTL, which deletes the items a
+// retention policy no longer keeps when swept
+type ExpiringStore interface {
+	DeleteExpired(ctx context.Context, policy RetentionPolicy, now time.Time) (SweepCounts, error)
+}
+
+// DefaultSweepInterval is how often Sweeper.Run sweeps
+const DefaultSweepInterval = time.Hour
+
+// Sweeper enforces a retention policy on stores without native TTL, such as
+// the in-memory stores; DynamoDB deletes expired items itself
+type Sweeper struct {
+	policy RetentionPolicy
+	stores []ExpiringStore
+	clock  Clock
+}
+
+// NewSweeper creates a sweeper deleting what policy no longer keeps from stores
+func NewSweeper(policy RetentionPolicy, stores []ExpiringStore, opts ...RuntimeOption) *Sweeper {
+	return &Sweeper{policy: policy, stores: stores, clock: newRuntimeDeps(opts).clock}
+}
+
+// Sweep deletes expired items from every store, recording the counts with
+// the metrics in ctx. A failing store does not stop the others.
+func (s *Sweeper) Sweep(ctx context.Context) (SweepCounts, error) {
+	var total SweepCounts
+	var errs []error
+	now := s.clock.Now()
+	for _, store := range s.stores {
+		counts, err := store.DeleteExpired(ctx, s.policy, now)
+		total.Add(RetentionKindTasks, counts.Tasks)
+		total.Add(RetentionKindEvents, counts.Events)
+		total.Add(RetentionKindArtifacts, counts.Artifacts)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	metrics := MetricsFromContext(ctx)
+	for _, kind := range []struct {
+		name  string
+		count int
+	}{{RetentionKindTasks, total.Tasks}, {RetentionKindEvents, total.Events}, {RetentionKindArtifacts, total.Artifacts}} {
+		if kind.count > 0 {
+			metrics.RecordRetentionPurge(kind.name, kind.count)
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// Run sweeps every interval until ctx is done, logging each sweep
+func (s *Sweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		counts, err := s.Sweep(ctx)
+		logger := LoggerFromContext(ctx).With(RetentionKindTasks, counts.Tasks, RetentionKindEvents, counts.Events, RetentionKindArtifacts, counts.Artifacts)
+		if err != nil {
+			logger.Warn("retention sweep failed", LogKeyError, err)
+			continue
+		}
+		logger.Debug("retention sweep completed")
+	}
+}
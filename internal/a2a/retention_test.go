@@ -0,0 +1,120 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestRetentionConfigPolicy(t *testing.T) {
+	config := RetentionConfig{Tasks: "30d", Events: "168h"}
+	if err := ValidateRetentionConfig(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy := config.Policy()
+	if policy != (RetentionPolicy{Tasks: 30 * 24 * time.Hour, Events: 168 * time.Hour}) || !policy.Enabled() {
+		t.Errorf("unexpected policy %+v", policy)
+	}
+	if (RetentionConfig{}).Policy().Enabled() {
+		t.Error("expected no retention by default")
+	}
+
+	err := ValidateRetentionConfig(RetentionConfig{Tasks: "0d", Events: "soon", Artifacts: "-1h"})
+	for _, path := range []string{"tasks", "events", "artifacts"} {
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("expected %s rejected, got %v", path, err)
+		}
+	}
+}
+
+func TestRetentionPolicyKinds(t *testing.T) {
+	policy := RetentionPolicy{Tasks: time.Hour, Events: time.Minute, Artifacts: 24 * time.Hour}
+	withArtifact := a2a.Task{ID: "task-1", Artifacts: []a2a.Artifact{{ArtifactID: "a-1"}}}
+
+	if retention, kind := policy.TaskRetention(a2a.Task{ID: "task-1"}); retention != time.Hour || kind != RetentionKindTasks {
+		t.Errorf("unexpected task retention %s %s", retention, kind)
+	}
+	if retention, kind := policy.TaskRetention(withArtifact); retention != 24*time.Hour || kind != RetentionKindArtifacts {
+		t.Errorf("unexpected task with artifacts retention %s %s", retention, kind)
+	}
+	if retention, kind := policy.EventRetention(a2a.TaskArtifactUpdateEvent{TaskID: "task-1"}); retention != 24*time.Hour || kind != RetentionKindArtifacts {
+		t.Errorf("unexpected artifact event retention %s %s", retention, kind)
+	}
+	if retention, kind := policy.EventRetention(a2a.TaskStatusUpdateEvent{TaskID: "task-1"}); retention != time.Minute || kind != RetentionKindEvents {
+		t.Errorf("unexpected status event retention %s %s", retention, kind)
+	}
+	// Without an artifacts retention, artifacts go with their task and events
+	policy.Artifacts = 0
+	if retention, _ := policy.TaskRetention(withArtifact); retention != time.Hour {
+		t.Errorf("expected the task retention, got %s", retention)
+	}
+}
+
+func TestAWSStoresWriteExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	client, requests := recordingDynamoDB(t)
+	opts := []RuntimeOption{WithClock(fixedClock{now: now}), WithRetention(RetentionPolicy{Tasks: time.Hour})}
+
+	NewAWSTaskStore(client, "tasks", "", opts...).SaveTask(ctx, a2a.Task{ID: "task-1", ContextID: "ctx-1"})
+	NewAWSEventStore(client, "events", "", opts...).SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1"})
+
+	expiresAt := func(request map[string]any) string {
+		attr, _ := request["Item"].(map[string]any)["expires_at"].(map[string]any)
+		value, _ := attr["N"].(string)
+		return value
+	}
+	if got := expiresAt((*requests)[0]); got != strconv.FormatInt(now.Add(time.Hour).Unix(), 10) {
+		t.Errorf("expected the task to expire in an hour, got %q", got)
+	}
+	// Events have no retention here, so they are kept
+	if got := expiresAt((*requests)[1]); got != "" {
+		t.Errorf("expected the event kept, got expires_at %q", got)
+	}
+}
+
+// expiringStore reports fixed counts, or fails with err
+type expiringStore struct {
+	counts SweepCounts
+	err    error
+}
+
+func (s expiringStore) DeleteExpired(ctx context.Context, policy RetentionPolicy, now time.Time) (SweepCounts, error) {
+	return s.counts, s.err
+}
+
+// recordingPurgeMetrics records the retention purges
+type recordingPurgeMetrics struct {
+	NoopMetrics
+	purged map[string]int
+}
+
+func (m *recordingPurgeMetrics) RecordRetentionPurge(kind string, count int) {
+	m.purged[kind] += count
+}
+
+func TestSweeperSweep(t *testing.T) {
+	metrics := &recordingPurgeMetrics{purged: map[string]int{}}
+	ctx := ContextWithMetrics(context.Background(), metrics)
+	sweeper := NewSweeper(RetentionPolicy{Tasks: time.Hour}, []ExpiringStore{
+		expiringStore{err: errors.New("unavailable")},
+		expiringStore{counts: SweepCounts{Tasks: 2, Artifacts: 1}},
+	})
+
+	// A failing store does not keep the others from being swept
+	counts, err := sweeper.Sweep(ctx)
+	if err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("expected the store's error, got %v", err)
+	}
+	if counts != (SweepCounts{Tasks: 2, Artifacts: 1}) {
+		t.Errorf("unexpected counts %+v", counts)
+	}
+	if len(metrics.purged) != 2 || metrics.purged[RetentionKindTasks] != 2 || metrics.purged[RetentionKindArtifacts] != 1 {
+		t.Errorf("unexpected purge metrics %v", metrics.purged)
+	}
+}
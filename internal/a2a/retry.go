@@ -0,0 +1,269 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// TaskAttemptsMetadataKey is the task metadata entry counting the failed
+// executions of its latest message
+const TaskAttemptsMetadataKey = "attempts"
+
+// RetryAllSkills is the retry policies key covering messages for any skill
+// without a policy of its own, and messages naming no skill
+const RetryAllSkills = "*"
+
+const (
+	// DefaultRetryAttempts is how many times a message is executed, at most,
+	// under a policy that does not set max_attempts
+	DefaultRetryAttempts = 3
+	// DefaultRetryBackoff is the delay before a first retry under a policy
+	// that does not set backoff
+	DefaultRetryBackoff = 5 * time.Second
+	// MaxRetryDelay is the longest a retry can wait, the SQS delay limit
+	MaxRetryDelay = 15 * time.Minute
+)
+
+// ErrTransient marks an executor error as worth retrying whatever the
+// policy's retryable_errors, e.g. fmt.Errorf("model overloaded: %w", ErrTransient)
+var ErrTransient = errors.New("transient failure")
+
+// RetryPolicy retries the executions of a skill's messages that fail
+// transiently. Each retry waits twice as long as the one before.
+type RetryPolicy struct {
+	// MaxAttempts counts the first execution; 0 means DefaultRetryAttempts
+	// and 1 never retries
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// Backoff is the delay before the first retry, such as 10s
+	Backoff string `json:"backoff,omitempty"`
+	// MaxBackoff caps the delay, at most and by default MaxRetryDelay
+	MaxBackoff string `json:"max_backoff,omitempty"`
+	// RetryableErrors are substrings of the executor errors to retry, on
+	// top of those wrapping ErrTransient
+	RetryableErrors []string `json:"retryable_errors,omitempty"`
+}
+
+// parseRetryPolicies decodes the JSON object in A2A_RETRY_POLICIES
+func parseRetryPolicies(value string) (map[string]RetryPolicy, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var policies map[string]RetryPolicy
+	if err := json.Unmarshal([]byte(value), &policies); err != nil {
+		return nil, fmt.Errorf("A2A_RETRY_POLICIES must be a JSON object of policies by skill ID: %w", err)
+	}
+	return policies, nil
+}
+
+// ValidateRetryPolicies checks each policy is keyed by one of skills or
+// RetryAllSkills, and sets usable attempts and delays
+func ValidateRetryPolicies(policies map[string]RetryPolicy, skills []a2a.AgentSkill) error {
+	var errs ValidationErrors
+	for _, skillID := range slices.Sorted(maps.Keys(policies)) {
+		policy, path := policies[skillID], skillID
+		if skillID != RetryAllSkills && !hasSkill(skills, skillID) {
+			errs.Add(path, ValidationCodeInvalid, fmt.Sprintf("'%s' is not a skill of the agent card or %s", skillID, RetryAllSkills))
+		}
+		if policy.MaxAttempts < 0 {
+			errs.Add(path+".max_attempts", ValidationCodeInvalid, "must not be negative")
+		}
+		backoff, maxBackoff := DefaultRetryBackoff, MaxRetryDelay
+		if policy.Backoff != "" {
+			var err error
+			if backoff, err = parseRetention(policy.Backoff); err != nil {
+				errs.Add(path+".backoff", ValidationCodeInvalid, fmt.Sprintf("'%s' must be a positive duration such as 10s", policy.Backoff))
+			}
+		}
+		if policy.MaxBackoff != "" {
+			var err error
+			if maxBackoff, err = parseRetention(policy.MaxBackoff); err != nil || maxBackoff > MaxRetryDelay {
+				errs.Add(path+".max_backoff", ValidationCodeInvalid, fmt.Sprintf("'%s' must be a positive duration of at most %s", policy.MaxBackoff, MaxRetryDelay))
+			}
+		}
+		if backoff > maxBackoff && maxBackoff > 0 {
+			errs.Add(path+".backoff", ValidationCodeConflict, "must not be longer than max_backoff")
+		}
+		for i, pattern := range policy.RetryableErrors {
+			if pattern == "" {
+				errs.Add(fmt.Sprintf("%s.retryable_errors[%d]", path, i), ValidationCodeInvalid, "must not be empty")
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// hasSkill reports whether skills holds one with id
+func hasSkill(skills []a2a.AgentSkill, id string) bool {
+	return slices.ContainsFunc(skills, func(skill a2a.AgentSkill) bool { return skill.ID == id })
+}
+
+// Attempts returns how many times a message is executed, at most
+func (p RetryPolicy) Attempts() int {
+	if p.MaxAttempts == 0 {
+		return DefaultRetryAttempts
+	}
+	return p.MaxAttempts
+}
+
+// Retryable reports whether an executor error is transient
+func (p RetryPolicy) Retryable(err error) bool {
+	if errors.Is(err, ErrTransient) {
+		return true
+	}
+	for _, pattern := range p.RetryableErrors {
+		if strings.Contains(err.Error(), pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns how long the retry after failed attempt waits: the backoff,
+// doubled for each attempt after the first, up to the max backoff. Invalid
+// values are rejected by ValidateRetryPolicies, so they use the defaults.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	backoff, err := parseRetention(p.Backoff)
+	if err != nil {
+		backoff = DefaultRetryBackoff
+	}
+	maxBackoff, err := parseRetention(p.MaxBackoff)
+	if err != nil || maxBackoff > MaxRetryDelay {
+		maxBackoff = MaxRetryDelay
+	}
+	delay := float64(backoff) * math.Pow(2, float64(attempt-1))
+	if delay > float64(maxBackoff) {
+		return maxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// WithRetryQueue has executions that fail transiently retried through queue,
+// under the config's retry policies. Without a queue, or a policy for the
+// message's skill, an executor error fails the message/send call.
+func WithRetryQueue(queue MessageQueue) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.retryQueue = queue
+	}
+}
+
+// retryPolicy returns the policy for the skill a message names, or for all
+// skills
+func (h *ServerlessA2AHandler) retryPolicy(message a2a.Message) (RetryPolicy, bool) {
+	skillID, _ := message.Metadata[SkillMetadataKey].(string)
+	if policy, ok := h.config.Retries[skillID]; ok && skillID != "" {
+		return policy, true
+	}
+	policy, ok := h.config.Retries[RetryAllSkills]
+	return policy, ok
+}
+
+// taskAttempts returns how many executions of the task's latest message
+// have failed
+func taskAttempts(task a2a.Task) int {
+	// A number read back from JSON is a float64
+	switch attempts := task.Metadata[TaskAttemptsMetadataKey].(type) {
+	case int:
+		return attempts
+	case float64:
+		return int(attempts)
+	}
+	return 0
+}
+
+// executionFailed settles a message whose executor failed. Without a retry
+// queue or a policy for the message, the error fails the call as before.
+// Otherwise a transient failure with attempts left is queued to run again
+// and leaves the task working; any other fails the task. Either way the
+// task is saved and returned with the attempts counted in its metadata.
+func (h *ServerlessA2AHandler) executionFailed(ctx context.Context, params a2a.MessageSendParams, task a2a.Task, retried bool, execErr error) (a2a.SendMessageResult, error) {
+	policy, ok := h.retryPolicy(params.Message)
+	if !ok || h.retryQueue == nil {
+		return nil, execErr
+	}
+	logger := LoggerFromContext(ctx)
+	attempt := 1
+	if retried {
+		attempt = taskAttempts(task) + 1
+	}
+	now := h.clock.Now()
+
+	var text string
+	state := a2a.TaskStateFailed
+	if attempt < policy.Attempts() && policy.Retryable(execErr) {
+		delay := policy.Delay(attempt)
+		if err := h.retryQueue.QueueMessage(ctx, h.queuedMessage(params, task), delay); err != nil {
+			logger.Warn("failed to queue retry", LogKeyError, err)
+		} else {
+			logger.Warn("execution failed, retrying", "attempt", attempt, "delay", delay, LogKeyError, execErr)
+			state, text = a2a.TaskStateWorking, fmt.Sprintf("attempt %d failed, retrying in %s", attempt, delay)
+		}
+	}
+	if state == a2a.TaskStateFailed {
+		logger.Warn("execution failed", "attempt", attempt, LogKeyError, execErr)
+		text = fmt.Sprintf("agent failed on attempt %d", attempt)
+	}
+
+	previous := task.Status.State
+	if !retried {
+		task.History = append(task.History, params.Message)
+	}
+	if task.Kind == "" {
+		task.Kind = "task"
+	}
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]any)
+	}
+	task.Metadata[TaskAttemptsMetadataKey] = attempt
+	task.Status = a2a.TaskStatus{
+		State: state,
+		Message: &a2a.Message{
+			Kind:      "message",
+			MessageID: h.ids.NewID(),
+			Role:      a2a.MessageRoleAgent,
+			TaskID:    &task.ID,
+			ContextID: &task.ContextID,
+			Parts:     []a2a.Part{a2a.TextPart{Kind: "text", Text: text}},
+		},
+		Timestamp: &now,
+	}
+	h.setDeadline(&task, params, now)
+	if err := h.taskStore.SaveTask(ctx, task); errors.Is(err, ErrTaskFinished) {
+		// A retry already queued finds the task finished and skips it
+		return h.finishedTask(ctx, task.ID)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+	MetricsFromContext(ctx).RecordTaskTransition(previous, task.Status.State)
+
+	saved := 1
+	if err := h.saveEvent(ctx, a2a.TaskStatusUpdateEvent{
+		Kind:      "status-update",
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Status:    task.Status,
+		Final:     state == a2a.TaskStateFailed,
+	}); err != nil {
+		// Log error but don't fail the request
+		logger.Warn("failed to save status event", LogKeyError, err)
+		saved = 0
+	}
+	// A new task is counted from no state, as in sendMessage
+	if params.Message.TaskID == nil {
+		previous = ""
+	}
+	h.recordStats(ctx, previous, task, saved)
+
+	if params.Config != nil {
+		task = limitHistory(task, params.Config.HistoryLength)
+	}
+	return task, nil
+}
@@ -0,0 +1,175 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// failingExecutor fails with each of errs in turn, then succeeds
+type failingExecutor struct {
+	errs  []error
+	calls int
+}
+
+func (e *failingExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	e.calls++
+	if e.calls <= len(e.errs) {
+		return e.errs[e.calls-1]
+	}
+	return nil
+}
+
+func (e *failingExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	policy := RetryPolicy{Backoff: "10s", MaxBackoff: "30s", RetryableErrors: []string{"throttled"}}
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 3: 30 * time.Second, 10: 30 * time.Second} {
+		if delay := policy.Delay(attempt); delay != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempt, want, delay)
+		}
+	}
+	if delay := (RetryPolicy{}).Delay(1); delay != DefaultRetryBackoff {
+		t.Errorf("expected the default backoff, got %s", delay)
+	}
+	if policy.Attempts() != DefaultRetryAttempts {
+		t.Errorf("expected %d attempts, got %d", DefaultRetryAttempts, policy.Attempts())
+	}
+	for err, want := range map[error]bool{
+		errors.New("request throttled"):                  true,
+		fmt.Errorf("model overloaded: %w", ErrTransient): true,
+		errors.New("bad input"):                          false,
+	} {
+		if policy.Retryable(err) != want {
+			t.Errorf("%v: expected retryable %v", err, want)
+		}
+	}
+}
+
+func TestValidateRetryPolicies(t *testing.T) {
+	skills := []a2a.AgentSkill{{ID: "summarize"}}
+	tests := []struct {
+		name     string
+		policies map[string]RetryPolicy
+		paths    []string
+	}{
+		{name: "valid", policies: map[string]RetryPolicy{"summarize": {MaxAttempts: 5, Backoff: "2s", MaxBackoff: "1m"}, RetryAllSkills: {}}},
+		{name: "unknown skill", policies: map[string]RetryPolicy{"translate": {}}, paths: []string{"translate"}},
+		{name: "bad values", policies: map[string]RetryPolicy{"summarize": {MaxAttempts: -1, Backoff: "soon", MaxBackoff: "1h", RetryableErrors: []string{""}}},
+			paths: []string{"summarize.max_attempts", "summarize.backoff", "summarize.max_backoff", "summarize.retryable_errors[0]"}},
+		{name: "backoff over max", policies: map[string]RetryPolicy{RetryAllSkills: {Backoff: "2m", MaxBackoff: "1m"}}, paths: []string{"*.backoff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRetryPolicies(tt.policies, skills)
+			if len(tt.paths) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var errs ValidationErrors
+			errors.As(err, &errs)
+			if len(errs) != len(tt.paths) {
+				t.Fatalf("expected errors at %v, got %v", tt.paths, err)
+			}
+			for i, path := range tt.paths {
+				if errs[i].Path != path {
+					t.Errorf("expected an error at %s, got %s", path, errs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerRetriesFailedExecutions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	config := ServerlessConfig{Retries: map[string]RetryPolicy{RetryAllSkills: {Backoff: "10s"}}}
+	send := func(h *ServerlessA2AHandler) (a2a.Task, error) {
+		result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+		if err != nil {
+			return a2a.Task{}, err
+		}
+		return result.(a2a.Task), nil
+	}
+
+	// A transient failure is queued to run again and leaves the task working
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	executor := &failingExecutor{errs: []error{ErrTransient, ErrTransient, ErrTransient}}
+	h := NewServerlessA2AHandler(config, stores, stores, nil, WithClock(fixedClock{now: now}), WithExecutor(executor), WithRetryQueue(NewSQSMessageQueue(queue, "retries")))
+	task, err := send(h)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Status.State != a2a.TaskStateWorking || taskAttempts(task) != 1 || len(task.History) != 1 {
+		t.Fatalf("expected the task working after one attempt, got %+v", task)
+	}
+	if len(queue.sent) != 1 || queue.sent[0].DelaySeconds != 10 || !strings.Contains(*queue.sent[0].MessageBody, string(task.ID)) {
+		t.Fatalf("expected the message queued for 10 s on its task, got %+v", queue.sent)
+	}
+
+	// Each retry doubles the delay, until the attempts run out and the task fails
+	for attempt, delay := range []int32{20, 0} {
+		stores.tasks = stores.tasks[len(stores.tasks)-1:]
+		if err := h.ProcessQueuedMessage(ctx, *queue.sent[attempt].MessageBody); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if delay != 0 && queue.sent[attempt+1].DelaySeconds != delay {
+			t.Errorf("expected a retry in %d s, got %d", delay, queue.sent[attempt+1].DelaySeconds)
+		}
+	}
+	task = stores.tasks[len(stores.tasks)-1]
+	if task.Status.State != a2a.TaskStateFailed || taskAttempts(task) != 3 || len(task.History) != 1 || len(queue.sent) != 2 {
+		t.Errorf("expected the task failed after 3 attempts with its message once, got %+v and %d retries", task, len(queue.sent))
+	}
+	if last := stores.events[len(stores.events)-1]; !last.Final || last.Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected a final failed event, got %+v", last)
+	}
+
+	// A retry that succeeds carries on as a message would
+	stores = &memoryStores{}
+	queue = &deadLetterSQS{}
+	h = NewServerlessA2AHandler(config, stores, stores, nil, WithExecutor(&failingExecutor{errs: []error{errors.New("bad input")}}), WithRetryQueue(NewSQSMessageQueue(queue, "retries")))
+	if task, err = send(h); err != nil || task.Status.State != a2a.TaskStateFailed {
+		t.Fatalf("expected an error not marked transient to fail the task, got %s: %v", task.Status.State, err)
+	}
+	h = NewServerlessA2AHandler(config, stores, stores, nil, WithExecutor(&failingExecutor{errs: []error{ErrTransient}}), WithRetryQueue(NewSQSMessageQueue(queue, "retries")))
+	if task, err = send(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stores.tasks = stores.tasks[len(stores.tasks)-1:]
+	if err := h.ProcessQueuedMessage(ctx, *queue.sent[0].MessageBody); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task = stores.tasks[len(stores.tasks)-1]; task.Status.State != a2a.TaskStateWorking || task.Status.Message != nil || len(task.History) != 1 {
+		t.Errorf("expected the retried task working without a failure message, got %+v", task)
+	}
+
+	// A task canceled while its retry waits is left canceled
+	executor = &failingExecutor{errs: []error{ErrTransient}}
+	h = NewServerlessA2AHandler(config, stores, stores, nil, WithExecutor(executor), WithRetryQueue(NewSQSMessageQueue(queue, "retries")))
+	if task, err = send(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stores.tasks = stores.tasks[len(stores.tasks)-1:]
+	if _, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: task.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stores.tasks = stores.tasks[len(stores.tasks)-1:]
+	if err := h.ProcessQueuedMessage(ctx, *queue.sent[len(queue.sent)-1].MessageBody); err != nil || executor.calls != 1 {
+		t.Errorf("expected the retry skipped, got %d executions: %v", executor.calls, err)
+	}
+
+	// Without a policy, a failure fails the call as before
+	h = NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithExecutor(&failingExecutor{errs: []error{ErrTransient}}), WithRetryQueue(NewSQSMessageQueue(queue, "retries")))
+	if _, err := send(h); !errors.Is(err, ErrTransient) {
+		t.Errorf("expected the executor's error, got %v", err)
+	}
+}
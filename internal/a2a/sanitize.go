@@ -0,0 +1,103 @@
+package a2a
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxErrorDataBytes bounds the data of a JSON-RPC error. Error data often
+	// quotes what the client sent, which is echoed back but never needs to be
+	// long.
+	MaxErrorDataBytes = 256
+	// maxLogValueBytes bounds every string logged, so one client cannot fill
+	// the log group with a single oversized value
+	maxLogValueBytes = 2048
+)
+
+// truncatedSuffix marks text cut by SanitizeText
+const truncatedSuffix = "...[truncated]"
+
+// SanitizeText makes client-supplied text safe to log or echo. Control
+// characters, including newlines and the Unicode line separators that could
+// forge a log line, are escaped as Go escapes; invalid UTF-8 is replaced;
+// and text longer than maxBytes is cut at a character boundary and marked.
+func SanitizeText(text string, maxBytes int) string {
+	if isSafeText(text) && len(text) <= maxBytes {
+		return text
+	}
+
+	var sanitized strings.Builder
+	for _, r := range strings.ToValidUTF8(text, string(utf8.RuneError)) {
+		if isUnsafeRune(r) {
+			// QuoteRuneToASCII gives e.g. '\n' or '\u2028'; keep what is inside the quotes
+			quoted := strconv.QuoteRuneToASCII(r)
+			sanitized.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			sanitized.WriteRune(r)
+		}
+		if sanitized.Len() > maxBytes {
+			break
+		}
+	}
+
+	result := sanitized.String()
+	if len(result) <= maxBytes {
+		return result
+	}
+	cut := max(maxBytes-len(truncatedSuffix), 0)
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+	return result[:cut] + truncatedSuffix
+}
+
+// SanitizeErrorData prepares the data of a JSON-RPC error for the response.
+// Strings and errors are sanitized and cut to MaxErrorDataBytes, and
+// ValidationErrors are kept as field errors with each path and message
+// sanitized. Any other value is dropped, since structured data may carry
+// message parts.
+func SanitizeErrorData(data interface{}) interface{} {
+	switch value := data.(type) {
+	case string:
+		return SanitizeText(value, MaxErrorDataBytes)
+	case ValidationErrors:
+		sanitized := make(ValidationErrors, len(value))
+		for i, fieldErr := range value {
+			fieldErr.Path = SanitizeText(fieldErr.Path, MaxErrorDataBytes)
+			fieldErr.Message = SanitizeText(fieldErr.Message, MaxErrorDataBytes)
+			sanitized[i] = fieldErr
+		}
+		return sanitized
+	case error:
+		return SanitizeText(value.Error(), MaxErrorDataBytes)
+	default:
+		return nil
+	}
+}
+
+func isSafeText(text string) bool {
+	if !utf8.ValidString(text) {
+		return false
+	}
+	for _, r := range text {
+		if isUnsafeRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUnsafeRune reports control characters and the characters that break or
+// reorder lines when displayed
+func isUnsafeRune(r rune) bool {
+	switch r {
+	// Line and paragraph separators, and the bidirectional overrides and
+	// isolates that can make a line display differently from what it holds
+	case '\u2028', '\u2029', '\u202a', '\u202b', '\u202c', '\u202d', '\u202e', '\u2066', '\u2067', '\u2068', '\u2069':
+		return true
+	}
+	return unicode.IsControl(r)
+}
@@ -0,0 +1,61 @@
+package a2a
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		expected string
+	}{
+		{name: "plain text", text: `unknown part kind "video"`, maxBytes: 64, expected: `unknown part kind "video"`},
+		{name: "forged log line", text: "tasks/get\n{\"level\":\"ERROR\"}", maxBytes: 64, expected: `tasks/get\n{"level":"ERROR"}`},
+		{name: "control characters", text: "a\r\tb\x00c\x1b[31m", maxBytes: 64, expected: `a\r\tb\x00c\x1b[31m`},
+		{name: "unicode line separator", text: "a\u2028b", maxBytes: 64, expected: `a\u2028b`},
+		{name: "bidi override", text: "invoice\u202efdp.exe", maxBytes: 64, expected: `invoice\u202efdp.exe`},
+		{name: "invalid utf-8", text: "a\xffb", maxBytes: 64, expected: "a\ufffdb"},
+		{name: "oversized", text: strings.Repeat("x", 100), maxBytes: 32, expected: strings.Repeat("x", 32-len(truncatedSuffix)) + truncatedSuffix},
+		{name: "cut on a character boundary", text: strings.Repeat("\u00e9", 40), maxBytes: 32, expected: strings.Repeat("\u00e9", 9) + truncatedSuffix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeText(tt.text, tt.maxBytes)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if len(got) > tt.maxBytes || !utf8.ValidString(got) {
+				t.Errorf("expected valid UTF-8 within %d bytes, got %q", tt.maxBytes, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeErrorData(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected interface{}
+	}{
+		{name: "nil", data: nil, expected: nil},
+		{name: "string", data: "bad\nmethod", expected: `bad\nmethod`},
+		{name: "error", data: errors.New("task x\r\n not found"), expected: `task x\r\n not found`},
+		{name: "oversized string", data: strings.Repeat("m", 1000), expected: strings.Repeat("m", MaxErrorDataBytes-len(truncatedSuffix)) + truncatedSuffix},
+		{name: "message parts are dropped", data: []a2a.Part{a2a.TextPart{Text: "my card number is 4111"}}, expected: nil},
+		{name: "structured data is dropped", data: map[string]string{"text": "hi"}, expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeErrorData(tt.data); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
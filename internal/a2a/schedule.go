@@ -0,0 +1,334 @@
+package a2a
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DelayMetadataKey is the message/send params metadata entry delaying the
+// message's execution, a duration such as 90s or 2h
+const DelayMetadataKey = "delay"
+
+// StartAtMetadataKey is the message/send params metadata entry setting the
+// RFC 3339 time to execute the message at
+const StartAtMetadataKey = "startAt"
+
+// TaskStartAtMetadataKey is the task metadata entry holding the RFC 3339
+// time a task held in submitted is to start
+const TaskStartAtMetadataKey = "start_at"
+
+// MessageScheduler queues messages at a set time, for starts further off
+// than a queue's delay allows
+type MessageScheduler interface {
+	ScheduleMessage(ctx context.Context, params a2a.MessageSendParams, at time.Time) error
+}
+
+// WithScheduler has messages starting more than MaxRetryDelay from now
+// queued by scheduler; sooner ones are delayed on the work queue
+func WithScheduler(scheduler MessageScheduler) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.scheduler = scheduler
+	}
+}
+
+// validateStart checks the delay or start time a message/send sets
+func validateStart(metadata map[string]any) error {
+	var errs ValidationErrors
+	delay, hasDelay := metadata[DelayMetadataKey]
+	startAt, hasStartAt := metadata[StartAtMetadataKey]
+	if hasDelay {
+		value, _ := delay.(string)
+		if _, err := parseRetention(value); err != nil {
+			errs.Add(DelayMetadataKey, ValidationCodeInvalid, "must be a positive duration such as 90s or 2h")
+		}
+	}
+	if hasStartAt {
+		value, _ := startAt.(string)
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			errs.Add(StartAtMetadataKey, ValidationCodeInvalid, "must be an RFC 3339 time")
+		}
+	}
+	if hasDelay && hasStartAt {
+		errs.Add(StartAtMetadataKey, ValidationCodeConflict, "cannot be set with "+DelayMetadataKey)
+	}
+	return errs.Err()
+}
+
+// requestStart returns when a message/send asks to be executed, false when
+// it asks for no delay. Invalid values are rejected by
+// ValidateMessageSendParams.
+func requestStart(params a2a.MessageSendParams, now time.Time) (time.Time, bool) {
+	if value, _ := params.Metadata[StartAtMetadataKey].(string); value != "" {
+		at, err := time.Parse(time.RFC3339, value)
+		return at, err == nil && at.After(now)
+	}
+	value, _ := params.Metadata[DelayMetadataKey].(string)
+	delay, err := parseRetention(value)
+	return now.Add(delay), err == nil
+}
+
+// hold saves a task submitted with its message until the message's start
+// time, and queues the message to be executed then. Its deadline, if it has
+// one, runs from the start.
+func (h *ServerlessA2AHandler) hold(ctx context.Context, params a2a.MessageSendParams, task a2a.Task, at time.Time) (a2a.Task, error) {
+	now := h.clock.Now()
+	delay := at.Sub(now)
+	if h.workQueue == nil || delay > MaxRetryDelay && h.scheduler == nil {
+		return a2a.Task{}, fmt.Errorf("cannot start task %s at %s: no work queue or scheduler for it", task.ID, at.Format(time.RFC3339))
+	}
+
+	previous := task.Status.State
+	task.History = append(task.History, params.Message)
+	task.Status = a2a.TaskStatus{State: a2a.TaskStateSubmitted, Timestamp: &now}
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]any)
+	}
+	task.Metadata[TaskStartAtMetadataKey] = at.UTC().Format(time.RFC3339)
+	h.setDeadline(&task, params, at)
+	if task.Kind == "" {
+		task.Kind = "task"
+	}
+	if err := h.taskStore.SaveTask(ctx, task); errors.Is(err, ErrTaskFinished) {
+		return h.finishedTask(ctx, task.ID)
+	} else if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to save task: %w", err)
+	}
+	MetricsFromContext(ctx).RecordTaskTransition(previous, task.Status.State)
+	// Saved now, as a scheduler need not keep the config until the start
+	h.savePushConfig(ctx, task.ID, params)
+	// A new task is counted from no state, as in sendMessage
+	if params.Message.TaskID == nil {
+		previous = ""
+	}
+	h.recordStats(ctx, previous, task, 0)
+
+	queued := h.queuedMessage(params, task)
+	var err error
+	if delay > MaxRetryDelay {
+		err = h.scheduler.ScheduleMessage(ctx, queued, at)
+	} else {
+		err = h.workQueue.QueueMessage(ctx, queued, delay)
+	}
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to queue task %s for %s: %w", task.ID, at.Format(time.RFC3339), err)
+	}
+	LoggerFromContext(ctx).Info("task held until its start", "start_at", task.Metadata[TaskStartAtMetadataKey])
+
+	if params.Config != nil {
+		task = limitHistory(task, params.Config.HistoryLength)
+	}
+	return task, nil
+}
+
+// ScheduledMessageReleaser is a MessageScheduler that holds messages
+// itself until a maintenance pass releases them onto the work queue
+type ScheduledMessageReleaser interface {
+	// ReleaseDue queues the messages starting within MaxRetryDelay of now
+	// on queue, delayed until their start, and forgets them. It returns how
+	// many it released.
+	ReleaseDue(ctx context.Context, queue MessageQueue, now time.Time) (int, error)
+}
+
+// ScheduleConfig names the DynamoDB table scheduled messages wait in.
+// Scheduling is off unless Table is set.
+type ScheduleConfig struct {
+	Table string
+}
+
+// LoadScheduleConfigFromEnv reads A2A_SCHEDULE_TABLE
+func LoadScheduleConfigFromEnv() ScheduleConfig {
+	return ScheduleConfig{Table: getEnvOrDefault("A2A_SCHEDULE_TABLE", "")}
+}
+
+// RuntimeOptions returns the option scheduling messages in the table
+// through client, under keyPrefix, none when no table is set
+func (c ScheduleConfig) RuntimeOptions(client DynamoDBAPI, keyPrefix string) []RuntimeOption {
+	if c.Table == "" {
+		return nil
+	}
+	return []RuntimeOption{WithScheduler(NewAWSMessageScheduler(client, c.Table, keyPrefix))}
+}
+
+// scheduleTimeLayout keeps start times one width, so they sort as strings
+const scheduleTimeLayout = "2006-01-02T15:04:05Z"
+
+// ScheduleShards is how many partition keys an agent's scheduled messages
+// are spread over, so a burst of reminders for the same time does not
+// land on one key
+const ScheduleShards = 16
+
+// AWSMessageScheduler is a MessageScheduler keeping messages in a DynamoDB
+// table until a maintenance pass finds them within the work queue's delay
+// and releases them onto it. It stands in for one-off EventBridge Scheduler
+// schedules, whose SDK module this one does not depend on: the DynamoDB
+// client the handler already holds is enough. An agent's messages are
+// spread over ScheduleShards partition keys, each sorted by start time, so
+// a pass reads only the messages due.
+//
+// Only a message's IDs are kept, not its parts: its task, saved when it
+// was held, has it in its history, where content encryption applies, and
+// the worker executes it from there.
+type AWSMessageScheduler struct {
+	client    DynamoDBAPI
+	tableName string
+	keyPrefix string
+}
+
+// NewAWSMessageScheduler creates a scheduler on tableName. keyPrefix keeps
+// agents sharing the table apart; a single-agent deployment passes "".
+func NewAWSMessageScheduler(client DynamoDBAPI, tableName, keyPrefix string) *AWSMessageScheduler {
+	return &AWSMessageScheduler{client: client, tableName: tableName, keyPrefix: keyPrefix}
+}
+
+// ScheduleMessage implements MessageScheduler. The item is keyed by the
+// start and by the task and message, so scheduling a message twice
+// schedules it once.
+func (s *AWSMessageScheduler) ScheduleMessage(ctx context.Context, params a2a.MessageSendParams, at time.Time) error {
+	defer observeStorage(ctx, "ScheduleMessage", time.Now())
+
+	message, err := json.Marshal(scheduledMessage(params))
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduled message: %w", err)
+	}
+	var taskID a2a.TaskID
+	if params.Message.TaskID != nil {
+		taskID = *params.Message.TaskID
+	}
+	name := sha256.Sum256([]byte(string(taskID) + "/" + params.Message.MessageID))
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]types.AttributeValue{
+			"schedule_key": &types.AttributeValueMemberS{Value: s.shardKey(int(name[0]) % ScheduleShards)},
+			"start_at":     &types.AttributeValueMemberS{Value: at.UTC().Format(scheduleTimeLayout) + "#" + hex.EncodeToString(name[:16])},
+			"message":      &types.AttributeValueMemberS{Value: string(message)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to schedule message: %w", err)
+	}
+	return nil
+}
+
+func (s *AWSMessageScheduler) shardKey(shard int) string {
+	return s.keyPrefix + "messages#" + strconv.Itoa(shard)
+}
+
+// scheduledMessage is what is kept of a queued message until its start:
+// its IDs and how it was sent, without the parts its task's history holds
+// or the push config saved with the task
+func scheduledMessage(params a2a.MessageSendParams) a2a.MessageSendParams {
+	params.Message = a2a.Message{
+		Kind:      params.Message.Kind,
+		MessageID: params.Message.MessageID,
+		Role:      params.Message.Role,
+		TaskID:    params.Message.TaskID,
+		ContextID: params.Message.ContextID,
+	}
+	if params.Config != nil {
+		config := *params.Config
+		config.PushConfig = nil
+		params.Config = &config
+	}
+	return params
+}
+
+// heldMessage returns the message a task was held with, from its history,
+// for a scheduled message kept by its IDs alone. Any other message is
+// returned as it is.
+func heldMessage(task a2a.Task, message a2a.Message) a2a.Message {
+	if len(message.Parts) > 0 {
+		return message
+	}
+	for i := len(task.History) - 1; i >= 0; i-- {
+		if task.History[i].MessageID == message.MessageID {
+			return task.History[i]
+		}
+	}
+	return message
+}
+
+// ReleaseDue implements ScheduledMessageReleaser, shard by shard. A
+// message is only forgotten once queued, so one that fails to queue is
+// released by the next pass; deduplication by its queueId catches one
+// queued twice.
+func (s *AWSMessageScheduler) ReleaseDue(ctx context.Context, queue MessageQueue, now time.Time) (int, error) {
+	defer observeStorage(ctx, "ReleaseDue", time.Now())
+
+	// Every start in the due second sorts before its next one
+	due := now.Add(MaxRetryDelay).Add(time.Second).UTC().Format(scheduleTimeLayout)
+	released := 0
+	for shard := range ScheduleShards {
+		n, err := s.releaseShard(ctx, queue, now, s.shardKey(shard), due)
+		released += n
+		if err != nil {
+			return released, err
+		}
+	}
+	return released, nil
+}
+
+// releaseShard releases the messages of one shard starting before due
+func (s *AWSMessageScheduler) releaseShard(ctx context.Context, queue MessageQueue, now time.Time, shardKey, due string) (int, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("schedule_key = :key AND start_at < :due"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":key": &types.AttributeValueMemberS{Value: shardKey},
+			":due": &types.AttributeValueMemberS{Value: due},
+		},
+	}
+	released := 0
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return released, fmt.Errorf("failed to query scheduled messages: %w", err)
+		}
+		for _, item := range result.Items {
+			key, _ := item["start_at"].(*types.AttributeValueMemberS)
+			message, _ := item["message"].(*types.AttributeValueMemberS)
+			if key == nil || message == nil {
+				continue
+			}
+			startAt, _, _ := strings.Cut(key.Value, "#")
+			at, err := time.Parse(scheduleTimeLayout, startAt)
+			if err != nil {
+				continue
+			}
+			params, err := UnmarshalMessageSendParams([]byte(message.Value))
+			if err != nil {
+				LoggerFromContext(ctx).Warn("unreadable scheduled message skipped", "start_at", key.Value, LogKeyError, err)
+				continue
+			}
+			if err := queue.QueueMessage(ctx, params, max(at.Sub(now), 0)); err != nil {
+				return released, fmt.Errorf("failed to release scheduled message: %w", err)
+			}
+			_, err = s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+				TableName: aws.String(s.tableName),
+				Key: map[string]types.AttributeValue{
+					"schedule_key": item["schedule_key"],
+					"start_at":     key,
+				},
+			})
+			if err != nil {
+				return released, fmt.Errorf("failed to delete released message: %w", err)
+			}
+			released++
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return released, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
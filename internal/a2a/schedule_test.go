@@ -0,0 +1,219 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// recordingScheduler keeps the times messages are scheduled for
+type recordingScheduler struct {
+	at []time.Time
+}
+
+func (s *recordingScheduler) ScheduleMessage(ctx context.Context, params a2a.MessageSendParams, at time.Time) error {
+	s.at = append(s.at, at)
+	return nil
+}
+
+func TestHandlerHoldsDelayedMessages(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	scheduler := &recordingScheduler{}
+	executor := &failingExecutor{}
+	h := NewServerlessA2AHandler(ServerlessConfig{TaskTimeout: "15m"}, stores, stores, nil, WithClock(fixedClock{now: now}),
+		WithExecutor(executor), WithWorkQueue(NewSQSMessageQueue(queue, "work")), WithScheduler(scheduler))
+	send := func(metadata map[string]any) a2a.Task {
+		t.Helper()
+		result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}, Metadata: metadata})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.(a2a.Task)
+	}
+
+	// A short delay is the queue's, and the task waits submitted
+	task := send(map[string]any{DelayMetadataKey: "90s"})
+	if task.Status.State != a2a.TaskStateSubmitted || task.Metadata[TaskStartAtMetadataKey] != "2025-08-01T12:01:30Z" || executor.calls != 0 {
+		t.Fatalf("expected the task held submitted until 12:01:30, got %+v after %d executions", task, executor.calls)
+	}
+	if task.Metadata[TaskDeadlineMetadataKey] != "2025-08-01T12:16:30Z" {
+		t.Errorf("expected the deadline to run from the start, got %v", task.Metadata[TaskDeadlineMetadataKey])
+	}
+	if len(queue.sent) != 1 || queue.sent[0].DelaySeconds != 90 {
+		t.Fatalf("expected the message queued for 90 s, got %+v", queue.sent)
+	}
+
+	// A later start is scheduled
+	send(map[string]any{StartAtMetadataKey: "2025-08-01T14:00:00Z"})
+	if len(scheduler.at) != 1 || !scheduler.at[0].Equal(now.Add(2*time.Hour)) || len(queue.sent) != 1 {
+		t.Errorf("expected the message scheduled for 14:00, got %v", scheduler.at)
+	}
+
+	// When the message comes off the queue the task starts
+	stores.tasks = stores.tasks[:1]
+	if err := h.ProcessQueuedMessage(ctx, *queue.sent[0].MessageBody); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task = stores.tasks[len(stores.tasks)-1]
+	if executor.calls != 1 || task.Status.State != a2a.TaskStateWorking || task.Metadata[TaskStartAtMetadataKey] != nil || len(task.History) != 1 {
+		t.Errorf("expected the held task executed once, got %+v", task)
+	}
+
+	// A start in the past runs now, and one nothing can queue fails
+	if task := send(map[string]any{StartAtMetadataKey: "2025-08-01T11:00:00Z"}); executor.calls != 2 || task.Status.State != a2a.TaskStateWorking {
+		t.Errorf("expected a past start executed at once, got %s", task.Status.State)
+	}
+	unqueued := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now}), WithWorkQueue(NewSQSMessageQueue(queue, "work")))
+	if _, err := unqueued.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-2", Role: a2a.MessageRoleUser}, Metadata: map[string]any{DelayMetadataKey: "1d"}}); err == nil {
+		t.Error("expected an error for a day's delay without a scheduler")
+	}
+}
+
+// scheduleDynamoDB keeps scheduled items, answering a Query with those
+// under its key starting before its due time
+type scheduleDynamoDB struct {
+	itemsDynamoDB
+	deleted []string
+	queries int
+}
+
+func (d *scheduleDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	d.queries++
+	key := params.ExpressionAttributeValues[":key"].(*types.AttributeValueMemberS).Value
+	due := params.ExpressionAttributeValues[":due"].(*types.AttributeValueMemberS).Value
+	output := &dynamodb.QueryOutput{}
+	for _, item := range d.items {
+		if item["schedule_key"].(*types.AttributeValueMemberS).Value == key && item["start_at"].(*types.AttributeValueMemberS).Value < due {
+			output.Items = append(output.Items, item)
+		}
+	}
+	return output, nil
+}
+
+func (d *scheduleDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	key := params.Key["start_at"].(*types.AttributeValueMemberS).Value
+	d.deleted = append(d.deleted, key)
+	d.items = slices.DeleteFunc(d.items, func(item map[string]types.AttributeValue) bool {
+		return item["start_at"].(*types.AttributeValueMemberS).Value == key
+	})
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestAWSMessageScheduler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	client := &scheduleDynamoDB{}
+	scheduler := NewAWSMessageScheduler(client, "schedules", "billing#")
+	for i, at := range []time.Time{now.Add(2 * time.Hour), now.Add(20 * time.Minute)} {
+		taskID := a2a.TaskID(fmt.Sprintf("task-%d", i))
+		params := a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", TaskID: &taskID, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "remind me"}}}}
+		if err := scheduler.ScheduleMessage(ctx, params, at); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	item := client.items[0]
+	if !strings.HasPrefix(item["schedule_key"].(*types.AttributeValueMemberS).Value, "billing#messages#") || !strings.HasPrefix(item["start_at"].(*types.AttributeValueMemberS).Value, "2025-08-01T14:00:00Z#") {
+		t.Errorf("expected the message kept under one of the agent's keys by its start, got %v", item)
+	}
+	if message := item["message"].(*types.AttributeValueMemberS).Value; strings.Contains(message, "remind me") || !strings.Contains(message, `"m-1"`) {
+		t.Errorf("expected only the message's IDs kept, got %s", message)
+	}
+	keys := make(map[string]bool)
+	for i := range 64 {
+		taskID := a2a.TaskID(fmt.Sprintf("spread-%d", i))
+		other := &scheduleDynamoDB{}
+		NewAWSMessageScheduler(other, "schedules", "").ScheduleMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", TaskID: &taskID}}, now)
+		keys[other.items[0]["schedule_key"].(*types.AttributeValueMemberS).Value] = true
+	}
+	if len(keys) < 2 {
+		t.Errorf("expected messages starting together spread over keys, got %v", keys)
+	}
+
+	// Only the message due before the next pass is queued, for the rest of
+	// its delay
+	queue := &deadLetterSQS{}
+	released, err := scheduler.ReleaseDue(ctx, NewSQSMessageQueue(queue, "work"), now.Add(10*time.Minute))
+	if err != nil || released != 1 || len(queue.sent) != 1 || queue.sent[0].DelaySeconds != 600 {
+		t.Fatalf("expected the 12:20 message queued for 10 minutes, got %d %+v: %v", released, queue.sent, err)
+	}
+	if !strings.Contains(*queue.sent[0].MessageBody, "task-1") || len(client.items) != 1 {
+		t.Errorf("expected the released message forgotten, got %s and %d left", *queue.sent[0].MessageBody, len(client.items))
+	}
+
+	// A message that fails to queue is kept for the next pass
+	failing := NewSQSMessageQueue(&deadLetterSQS{sendErr: errors.New("throttled")}, "work")
+	if released, err := scheduler.ReleaseDue(ctx, failing, now.Add(2*time.Hour)); err == nil || released != 0 || len(client.items) != 1 {
+		t.Errorf("expected the message kept after a failed release, got %d: %v", released, err)
+	}
+	client.queries = 0
+	scheduler.ReleaseDue(ctx, NewSQSMessageQueue(queue, "work"), now)
+	if client.queries != ScheduleShards {
+		t.Errorf("expected one query per shard, got %d", client.queries)
+	}
+}
+
+// messageExecutor keeps the text of the messages it executes
+type messageExecutor struct {
+	texts []string
+}
+
+func (e *messageExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	for _, part := range reqCtx.Request.Message.Parts {
+		if text, ok := part.(a2a.TextPart); ok {
+			e.texts = append(e.texts, text.Text)
+		}
+	}
+	return nil
+}
+
+func (e *messageExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestRunMaintenanceReleasesScheduledMessages(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	stores := &memoryStores{}
+	queue := &deadLetterSQS{}
+	client := &scheduleDynamoDB{}
+	options := append([]RuntimeOption{WithClock(fixedClock{now: now}), WithWorkQueue(NewSQSMessageQueue(queue, "work"))},
+		ScheduleConfig{Table: "schedules"}.RuntimeOptions(client, "")...)
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, options...)
+	remind := a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "remind me"}}}
+	if _, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: remind, Metadata: map[string]any{DelayMetadataKey: "1h"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.items) != 1 || len(queue.sent) != 0 {
+		t.Fatalf("expected the message scheduled, got %d items and %d queued", len(client.items), len(queue.sent))
+	}
+
+	executor := &messageExecutor{}
+	later := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now.Add(50 * time.Minute)}), WithExecutor(executor),
+		WithWorkQueue(NewSQSMessageQueue(queue, "work")), WithScheduler(NewAWSMessageScheduler(client, "schedules", "")))
+	if report, err := later.RunMaintenance(ctx); err != nil || report.Released != 1 || len(queue.sent) != 1 || queue.sent[0].DelaySeconds != 600 {
+		t.Fatalf("expected the message released to start in 10 minutes, got %+v %+v: %v", report, queue.sent, err)
+	}
+
+	// The worker executes the message from its task's history
+	if err := later.ProcessQueuedMessage(ctx, *queue.sent[0].MessageBody); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(executor.texts, []string{"remind me"}) {
+		t.Errorf("expected the held message executed, got %v", executor.texts)
+	}
+	if IsMaintenanceEvent([]byte(`{"source": "aws.events", "detail-type": "Scheduled Event"}`)) || !IsMaintenanceEvent([]byte(`{"maintenance": true}`)) {
+		t.Error("expected only the maintenance input to ask for a pass")
+	}
+}
@@ -0,0 +1,184 @@
+package a2a
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// JSONSchemaDraft is the JSON Schema dialect produced by ConfigJSONSchema
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// requiredSchemaFields lists the JSON fields the validators require for each
+// config type, so the schema flags the same omissions before deploy
+var requiredSchemaFields = map[reflect.Type][]string{
+	reflect.TypeOf(ServerlessConfig{}):    {"agent_id", "agent_card", "cloud_config"},
+	reflect.TypeOf(a2a.AgentCard{}):       {"Name", "URL"},
+	reflect.TypeOf(CloudProviderConfig{}): {"provider"},
+	reflect.TypeOf(AWSConfig{}):           {"region", "dynamodb_table"},
+	reflect.TypeOf(GCPConfig{}):           {"project_id", "firestore_db", "pubsub_topic", "region"},
+	reflect.TypeOf(AzureConfig{}):         {"region", "cosmosdb_endpoint", "cosmosdb_database", "servicebus_namespace", "servicebus_queue"},
+	reflect.TypeOf(AgentRegistryConfig{}): {"agents", "cloud_config"},
+	reflect.TypeOf(AgentConfig{}):         {"id", "agent_card"},
+}
+
+// enumSchemaFields restricts string fields to the values the loaders accept
+var enumSchemaFields = map[reflect.Type]map[string][]string{
+	reflect.TypeOf(CloudProviderConfig{}): {"provider": {"aws", "gcp", "azure", "local"}},
+	// Kind tells the protocol's events and parts apart
+	reflect.TypeOf(a2a.Task{}):                    {"Kind": {"task"}},
+	reflect.TypeOf(a2a.Message{}):                 {"Kind": {"message"}},
+	reflect.TypeOf(a2a.TaskStatusUpdateEvent{}):   {"Kind": {"status-update"}},
+	reflect.TypeOf(a2a.TaskArtifactUpdateEvent{}): {"Kind": {"artifact-update"}},
+	reflect.TypeOf(a2a.TextPart{}):                {"Kind": {"text"}},
+	reflect.TypeOf(a2a.FilePart{}):                {"Kind": {"file"}},
+	reflect.TypeOf(a2a.DataPart{}):                {"Kind": {"data"}},
+}
+
+// enumSchemaTypes restricts named string types wherever they appear
+var enumSchemaTypes = map[reflect.Type][]string{
+	reflect.TypeOf(a2a.TransportProtocol("")): {
+		string(a2a.TransportProtocolJSONRPC),
+		string(a2a.TransportProtocolGRPC),
+		string(a2a.TransportProtocolHTTPJSON),
+	},
+}
+
+// interfaceSchemaTypes lists the types an interface holds when it is
+// serialized, so the schema names them instead of accepting any value
+var interfaceSchemaTypes = map[reflect.Type][]reflect.Type{
+	reflect.TypeOf((*a2a.Part)(nil)).Elem(): {
+		reflect.TypeOf(a2a.TextPart{}),
+		reflect.TypeOf(a2a.FilePart{}),
+		reflect.TypeOf(a2a.DataPart{}),
+	},
+}
+
+// ConfigJSONSchema describes the ServerlessConfig file format, as accepted by
+// ParseServerlessConfig and the A2A_CONFIG_SECRET_ID secret
+func ConfigJSONSchema() map[string]any {
+	schema := reflectSchema(reflect.TypeOf(ServerlessConfig{}), nil)
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "A2A serverless configuration"
+	return schema
+}
+
+// AgentRegistryJSONSchema describes the A2A_AGENT_REGISTRY_FILE format
+func AgentRegistryJSONSchema() map[string]any {
+	schema := reflectSchema(reflect.TypeOf(AgentRegistryConfig{}), nil)
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = "A2A serverless agent registry"
+	return schema
+}
+
+// SchemaPath converts a validation path like cloud_config.aws.region or
+// agents[0].id into a JSON pointer into schema. Segments must match property
+// names exactly, as validation paths use the JSON keys; ok is false when the
+// path does not exist in the schema, such as a path rooted at an env variable.
+func SchemaPath(schema map[string]any, fieldPath string) (string, bool) {
+	pointer := "#"
+	node := schema
+	for _, segment := range strings.Split(fieldPath, ".") {
+		name, _, _ := strings.Cut(segment, "[")
+		if name != "" {
+			properties, _ := node["properties"].(map[string]any)
+			next, ok := properties[name].(map[string]any)
+			if !ok {
+				return "", false
+			}
+			pointer += "/properties/" + name
+			node = next
+		}
+		// Every index addresses the same item schema
+		for i := strings.Count(segment, "["); i > 0; i-- {
+			items, ok := node["items"].(map[string]any)
+			if !ok {
+				return "", false
+			}
+			pointer += "/items"
+			node = items
+		}
+	}
+	return pointer, true
+}
+
+// reflectSchema builds a schema for t following encoding/json naming rules.
+// seen guards against recursive types, which the config format never needs.
+func reflectSchema(t reflect.Type, seen []reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if values, ok := enumSchemaTypes[t]; ok {
+		return map[string]any{"type": "string", "enum": values}
+	}
+	for _, s := range seen {
+		if s == t {
+			return map[string]any{}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": reflectSchema(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": reflectSchema(t.Elem(), seen)}
+	case reflect.Struct:
+		return structSchema(t, append(seen, t))
+	case reflect.Interface:
+		types, ok := interfaceSchemaTypes[t]
+		if !ok {
+			return map[string]any{}
+		}
+		variants := make([]any, len(types))
+		for i, variant := range types {
+			variants[i] = reflectSchema(variant, seen)
+		}
+		return map[string]any{"oneOf": variants}
+	default:
+		// interface{} fields such as security schemes accept any JSON value
+		return map[string]any{}
+	}
+}
+
+// structSchema builds an object schema from a struct's exported fields
+func structSchema(t reflect.Type, seen []reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+
+		property := reflectSchema(field.Type, seen)
+		if values, ok := enumSchemaFields[t][name]; ok {
+			property["enum"] = values
+		}
+		properties[name] = property
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if required, ok := requiredSchemaFields[t]; ok {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,76 @@
+package a2a
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConfigJSONSchema(t *testing.T) {
+	schema := ConfigJSONSchema()
+
+	if schema["$schema"] != JSONSchemaDraft {
+		t.Errorf("expected $schema %s, got %v", JSONSchemaDraft, schema["$schema"])
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"agent_id", "agent_card", "cloud_config"}) {
+		t.Errorf("unexpected top-level required fields: %v", schema["required"])
+	}
+
+	properties := schema["properties"].(map[string]any)
+	cloud := properties["cloud_config"].(map[string]any)["properties"].(map[string]any)
+	provider := cloud["provider"].(map[string]any)
+	if !reflect.DeepEqual(provider["enum"], []string{"aws", "gcp", "azure", "local"}) {
+		t.Errorf("expected provider enum, got %v", provider["enum"])
+	}
+
+	aws := cloud["aws"].(map[string]any)
+	if _, ok := aws["properties"].(map[string]any)["sqs_queue_url"]; !ok {
+		t.Errorf("expected aws schema to use json tag names, got %v", aws["properties"])
+	}
+
+	// The SDK card has no json tags, so the schema uses Go field names like encoding/json
+	card := properties["agent_card"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := card["Name"]; !ok {
+		t.Errorf("expected agent card schema to use Go field names, got %v", card)
+	}
+	if _, ok := card["PreferredTransport"].(map[string]any)["enum"]; !ok {
+		t.Error("expected transport enum on PreferredTransport")
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Errorf("expected schema to serialize, got %v", err)
+	}
+}
+
+func TestSchemaPath(t *testing.T) {
+	config := ConfigJSONSchema()
+	registry := AgentRegistryJSONSchema()
+
+	tests := []struct {
+		schema   map[string]any
+		path     string
+		expected string
+	}{
+		{config, "agent_id", "#/properties/agent_id"},
+		{config, "cloud_config.aws.sqs_queue_url", "#/properties/cloud_config/properties/aws/properties/sqs_queue_url"},
+		{config, "agent_card.URL", "#/properties/agent_card/properties/URL"},
+		{config, "agent_card.Capabilities.PushNotifications", "#/properties/agent_card/properties/Capabilities/properties/PushNotifications"},
+		{config, "agent_card.AdditionalInterfaces[0].Transport", "#/properties/agent_card/properties/AdditionalInterfaces/items/properties/Transport"},
+		{registry, "agents[1].agent_card.Name", "#/properties/agents/items/properties/agent_card/properties/Name"},
+		// Near misses are not guessed at
+		{config, "agent_card.url", ""},
+		{config, "agent_card.capabilities.push_notifications", ""},
+		{config, "unknown.field", ""},
+		{config, "agent_id[0]", ""},
+		{config, "A2A_AGENT_SKILLS[1].id", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := SchemaPath(tt.schema, tt.path)
+			if got != tt.expected || ok != (tt.expected != "") {
+				t.Errorf("expected %q, got %q (found %v)", tt.expected, got, ok)
+			}
+		})
+	}
+}
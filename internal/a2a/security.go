@@ -0,0 +1,240 @@
+package a2a
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// Security scheme names published on the agent card
+const (
+	SecuritySchemeAPIKey = "apiKey"
+	SecuritySchemeOAuth2 = "oauth2"
+	SecuritySchemeOIDC   = "openIdConnect"
+)
+
+// SecurityConfig describes how inbound requests are authenticated. The agent
+// card advertises it and the Authenticator enforces it; each configured
+// scheme is accepted on its own.
+type SecurityConfig struct {
+	APIKeyHeader           string   `json:"api_key_header,omitempty"`
+	OAuth2TokenURL         string   `json:"oauth2_token_url,omitempty"`
+	OAuth2AuthorizationURL string   `json:"oauth2_authorization_url,omitempty"`
+	OAuth2Scopes           []string `json:"oauth2_scopes,omitempty"`
+	// OAuth2JWKSURL and OAuth2Issuer verify OAuth2 access tokens, which must be JWTs
+	OAuth2JWKSURL   string `json:"oauth2_jwks_url,omitempty"`
+	OAuth2Issuer    string `json:"oauth2_issuer,omitempty"`
+	OIDCMetadataURL string `json:"oidc_metadata_url,omitempty"`
+	// Audience, when set, must appear in the aud claim of bearer tokens
+	Audience string `json:"audience,omitempty"`
+	// AdminSubjects are the authenticated subjects that may access every
+	// task under OwnTasksOnly; admin methods are served only on the admin API
+	AdminSubjects []string `json:"admin_subjects,omitempty"`
+	// SignatureHeader accepts requests signed with secrets.request_signing_key,
+	// see SignRequest. SignatureTolerance, a duration such as "5m", bounds the
+	// age of the signed timestamp (default DefaultSignatureTolerance).
+	SignatureHeader    string `json:"signature_header,omitempty"`
+	SignatureTolerance string `json:"signature_tolerance,omitempty"`
+	// IAM accepts callers API Gateway authenticated with IAM authorization,
+	// identified by their ARN
+	IAM bool `json:"iam,omitempty"`
+	// MethodPolicies, when set, are the only grants of JSON-RPC methods to
+	// authenticated callers
+	MethodPolicies []MethodPolicy `json:"method_policies,omitempty"`
+	// OwnTasksOnly lets callers get, cancel and continue only the tasks they
+	// created; admins may access any task
+	OwnTasksOnly bool `json:"own_tasks_only,omitempty"`
+	// ReplayTable is a DynamoDB table, with TTL on expires_at, recording the
+	// signatures of accepted signed requests so none is accepted twice
+	ReplayTable string `json:"replay_table,omitempty"`
+	// SingleUseTokens accepts each bearer token once, by its jti, for
+	// callers that mint a token per request. Requires ReplayTable.
+	SingleUseTokens bool `json:"single_use_tokens,omitempty"`
+	// StrictJSONRPC refuses requests the lenient parser would accept: members
+	// other than jsonrpc, method, params and id, ids that are not a string,
+	// number or null, and params that are not an object or array
+	StrictJSONRPC bool `json:"strict_jsonrpc,omitempty"`
+}
+
+// LoadSecurityConfigFromEnv loads the security configuration from environment variables
+func LoadSecurityConfigFromEnv() (SecurityConfig, error) {
+	methodPolicies, err := parseMethodPolicies(getEnvOrDefault("A2A_AUTH_METHOD_POLICIES", ""))
+	if err != nil {
+		return SecurityConfig{}, err
+	}
+	return SecurityConfig{
+		APIKeyHeader:           getEnvOrDefault("A2A_AUTH_API_KEY_HEADER", ""),
+		OAuth2TokenURL:         getEnvOrDefault("A2A_AUTH_OAUTH2_TOKEN_URL", ""),
+		OAuth2AuthorizationURL: getEnvOrDefault("A2A_AUTH_OAUTH2_AUTHORIZATION_URL", ""),
+		OAuth2Scopes:           splitList(getEnvOrDefault("A2A_AUTH_OAUTH2_SCOPES", "")),
+		OAuth2JWKSURL:          getEnvOrDefault("A2A_AUTH_OAUTH2_JWKS_URL", ""),
+		OAuth2Issuer:           getEnvOrDefault("A2A_AUTH_OAUTH2_ISSUER", ""),
+		OIDCMetadataURL:        getEnvOrDefault("A2A_AUTH_OIDC_METADATA_URL", ""),
+		Audience:               getEnvOrDefault("A2A_AUTH_AUDIENCE", ""),
+		AdminSubjects:          splitList(getEnvOrDefault("A2A_AUTH_ADMIN_SUBJECTS", "")),
+		SignatureHeader:        getEnvOrDefault("A2A_AUTH_SIGNATURE_HEADER", ""),
+		SignatureTolerance:     getEnvOrDefault("A2A_AUTH_SIGNATURE_TOLERANCE", ""),
+		IAM:                    getEnvOrDefaultBool("A2A_AUTH_IAM", false),
+		MethodPolicies:         methodPolicies,
+		OwnTasksOnly:           getEnvOrDefaultBool("A2A_AUTH_OWN_TASKS_ONLY", false),
+		ReplayTable:            getEnvOrDefault("A2A_AUTH_REPLAY_TABLE", ""),
+		SingleUseTokens:        getEnvOrDefaultBool("A2A_AUTH_SINGLE_USE_TOKENS", false),
+		StrictJSONRPC:          getEnvOrDefaultBool("A2A_STRICT_JSONRPC", false),
+	}, nil
+}
+
+// splitList splits a comma-separated env value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ValidateSecurityConfig validates security configuration
+func ValidateSecurityConfig(config SecurityConfig) error {
+	var errs ValidationErrors
+	if config.OAuth2AuthorizationURL != "" && config.OAuth2TokenURL == "" {
+		errs.Add("oauth2_token_url", ValidationCodeRequired, "is required when oauth2_authorization_url is set")
+	}
+	if len(config.OAuth2Scopes) > 0 && config.OAuth2TokenURL == "" {
+		errs.Add("oauth2_token_url", ValidationCodeRequired, "is required when oauth2_scopes are set")
+	}
+	// Publishing a scheme the server cannot verify would accept any bearer token
+	if config.OAuth2TokenURL != "" && config.OAuth2JWKSURL == "" {
+		errs.Add("oauth2_jwks_url", ValidationCodeRequired, "is required to verify tokens when oauth2_token_url is set")
+	}
+	if config.SignatureTolerance != "" {
+		if tolerance, err := time.ParseDuration(config.SignatureTolerance); err != nil || tolerance <= 0 {
+			errs.Add("signature_tolerance", ValidationCodeInvalid, fmt.Sprintf("'%s' must be a positive duration such as 5m", config.SignatureTolerance))
+		} else if config.SignatureHeader == "" {
+			errs.Add("signature_header", ValidationCodeRequired, "is required when signature_tolerance is set")
+		}
+	}
+	// Without a scheme there is no subject to compare against
+	if len(config.AdminSubjects) > 0 && !config.hasSchemes() {
+		errs.Add("admin_subjects", ValidationCodeConflict, "require an authentication scheme to be configured")
+	}
+	if len(config.MethodPolicies) > 0 && !config.hasSchemes() {
+		errs.Add("method_policies", ValidationCodeConflict, "require an authentication scheme to be configured")
+	}
+	if config.OwnTasksOnly && !config.hasSchemes() {
+		errs.Add("own_tasks_only", ValidationCodeConflict, "requires an authentication scheme to be configured")
+	}
+	errs.Merge("", validateMethodPolicies(config.MethodPolicies))
+	if config.ReplayTable != "" && config.SignatureHeader == "" && !config.SingleUseTokens {
+		errs.Add("replay_table", ValidationCodeConflict, "requires signature_header or single_use_tokens")
+	}
+	if config.SingleUseTokens {
+		if config.OAuth2TokenURL == "" && config.OIDCMetadataURL == "" {
+			errs.Add("single_use_tokens", ValidationCodeConflict, "requires oauth2_token_url or oidc_metadata_url")
+		}
+		if config.ReplayTable == "" {
+			errs.Add("replay_table", ValidationCodeRequired, "is required when single_use_tokens is set")
+		}
+	}
+
+	urls := []struct{ name, value string }{
+		{"oauth2_token_url", config.OAuth2TokenURL},
+		{"oauth2_authorization_url", config.OAuth2AuthorizationURL},
+		{"oauth2_jwks_url", config.OAuth2JWKSURL},
+		{"oidc_metadata_url", config.OIDCMetadataURL},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			continue
+		}
+		// The A2A spec requires TLS for every auth endpoint
+		parsed, err := url.Parse(u.value)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			errs.Add(u.name, ValidationCodeInvalid, "must be an absolute https URL")
+		}
+	}
+
+	return errs.Err()
+}
+
+// BuildSecuritySchemes converts security configuration into agent card
+// securitySchemes and security requirements
+func BuildSecuritySchemes(config SecurityConfig) (map[string]any, []map[string][]string) {
+	schemes := make(map[string]any)
+	var requirements []map[string][]string
+
+	if config.APIKeyHeader != "" {
+		schemes[SecuritySchemeAPIKey] = a2a.APIKeySecurityScheme{
+			Type: "apiKey",
+			In:   a2a.APIKeySecuritySchemeInHeader,
+			Name: config.APIKeyHeader,
+		}
+		requirements = append(requirements, map[string][]string{SecuritySchemeAPIKey: {}})
+	}
+
+	if config.OAuth2TokenURL != "" {
+		scopes := make(map[string]string, len(config.OAuth2Scopes))
+		for _, scope := range config.OAuth2Scopes {
+			scopes[scope] = ""
+		}
+
+		var flows a2a.OAuthFlows
+		if config.OAuth2AuthorizationURL != "" {
+			flows.AuthzCode = &a2a.AuthzCodeOAuthFlow{
+				AuthzURL: config.OAuth2AuthorizationURL,
+				TokenURL: config.OAuth2TokenURL,
+				Scopes:   scopes,
+			}
+		} else {
+			// Agent-to-agent calls have no user in the loop, so client credentials is the default flow
+			flows.ClientCredentials = &a2a.ClientCredentialsOAuthFlow{
+				TokenURL: config.OAuth2TokenURL,
+				Scopes:   scopes,
+			}
+		}
+
+		schemes[SecuritySchemeOAuth2] = a2a.OAuth2SecurityScheme{
+			Type:  "oauth2",
+			Flows: flows,
+		}
+		requirements = append(requirements, map[string][]string{SecuritySchemeOAuth2: config.OAuth2Scopes})
+	}
+
+	if config.OIDCMetadataURL != "" {
+		schemes[SecuritySchemeOIDC] = a2a.OpenIDConnectSecurityScheme{
+			Type:             "openIdConnect",
+			OpenIDConnectURL: config.OIDCMetadataURL,
+		}
+		requirements = append(requirements, map[string][]string{SecuritySchemeOIDC: {}})
+	}
+
+	if len(schemes) == 0 {
+		return nil, nil
+	}
+	return schemes, requirements
+}
+
+// hasSchemes reports whether any security scheme is configured
+func (c SecurityConfig) hasSchemes() bool {
+	return c.APIKeyHeader != "" || c.OAuth2TokenURL != "" || c.OIDCMetadataURL != "" || c.SignatureHeader != "" || c.IAM
+}
+
+// signatureTolerance returns the allowed age of a signed request. Invalid
+// values are rejected by ValidateSecurityConfig, so they fall back here.
+func (c SecurityConfig) signatureTolerance() time.Duration {
+	if tolerance, err := time.ParseDuration(c.SignatureTolerance); err == nil && tolerance > 0 {
+		return tolerance
+	}
+	return DefaultSignatureTolerance
+}
+
+// WithSecurity publishes the auth schemes described by a security config
+func WithSecurity(config SecurityConfig) agentcard.Option {
+	return func(card *a2a.AgentCard) {
+		card.SecuritySchemes, card.Security = BuildSecuritySchemes(config)
+	}
+}
@@ -0,0 +1,220 @@
+package a2a
+
+import (
+	"os"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestValidateSecurityConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      SecurityConfig
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:   "empty config",
+			config: SecurityConfig{},
+		},
+		{
+			name: "all schemes",
+			config: SecurityConfig{
+				APIKeyHeader:           "X-API-Key",
+				OAuth2TokenURL:         "https://auth.example.com/token",
+				OAuth2AuthorizationURL: "https://auth.example.com/authorize",
+				OAuth2Scopes:           []string{"agent:invoke"},
+				OAuth2JWKSURL:          "https://auth.example.com/jwks.json",
+				OIDCMetadataURL:        "https://auth.example.com/.well-known/openid-configuration",
+			},
+		},
+		{
+			name:        "token URL without JWKS",
+			config:      SecurityConfig{OAuth2TokenURL: "https://auth.example.com/token"},
+			expectError: true,
+			errorMsg:    "oauth2_jwks_url is required to verify tokens when oauth2_token_url is set",
+		},
+		{
+			name:        "authorization URL without token URL",
+			config:      SecurityConfig{OAuth2AuthorizationURL: "https://auth.example.com/authorize"},
+			expectError: true,
+			errorMsg:    "oauth2_token_url is required when oauth2_authorization_url is set",
+		},
+		{
+			name:        "scopes without token URL",
+			config:      SecurityConfig{OAuth2Scopes: []string{"agent:invoke"}},
+			expectError: true,
+			errorMsg:    "oauth2_token_url is required when oauth2_scopes are set",
+		},
+		{
+			name:        "plain http token URL",
+			config:      SecurityConfig{OAuth2TokenURL: "http://auth.example.com/token", OAuth2JWKSURL: "https://auth.example.com/jwks.json"},
+			expectError: true,
+			errorMsg:    "oauth2_token_url must be an absolute https URL",
+		},
+		{
+			name:   "admin subjects with a scheme",
+			config: SecurityConfig{APIKeyHeader: "X-API-Key", AdminSubjects: []string{"apiKey"}},
+		},
+		{
+			name:        "admin subjects without a scheme",
+			config:      SecurityConfig{AdminSubjects: []string{"ops@example.com"}},
+			expectError: true,
+			errorMsg:    "admin_subjects require an authentication scheme to be configured",
+		},
+		{
+			name:   "signature header with tolerance",
+			config: SecurityConfig{SignatureHeader: "X-Signature", SignatureTolerance: "2m", AdminSubjects: []string{SecuritySchemeSignature}},
+		},
+		{
+			name:        "invalid signature tolerance",
+			config:      SecurityConfig{SignatureHeader: "X-Signature", SignatureTolerance: "soon"},
+			expectError: true,
+			errorMsg:    "signature_tolerance 'soon' must be a positive duration such as 5m",
+		},
+		{
+			name:        "signature tolerance without header",
+			config:      SecurityConfig{SignatureTolerance: "2m"},
+			expectError: true,
+			errorMsg:    "signature_header is required when signature_tolerance is set",
+		},
+		{
+			name:        "relative OIDC URL",
+			config:      SecurityConfig{OIDCMetadataURL: "/.well-known/openid-configuration"},
+			expectError: true,
+			errorMsg:    "oidc_metadata_url must be an absolute https URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecurityConfig(tt.config)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+					return
+				}
+				if err.Error() != tt.errorMsg {
+					t.Errorf("expected error message '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildSecuritySchemes(t *testing.T) {
+	// No schemes configured leaves the card fields empty
+	schemes, requirements := BuildSecuritySchemes(SecurityConfig{})
+	if schemes != nil || requirements != nil {
+		t.Errorf("expected nil schemes and requirements, got %v, %v", schemes, requirements)
+	}
+
+	// API key
+	schemes, requirements = BuildSecuritySchemes(SecurityConfig{APIKeyHeader: "X-API-Key"})
+	apiKey, ok := schemes[SecuritySchemeAPIKey].(a2a.APIKeySecurityScheme)
+	if !ok {
+		t.Fatalf("expected APIKeySecurityScheme, got %T", schemes[SecuritySchemeAPIKey])
+	}
+	if apiKey.Name != "X-API-Key" || apiKey.In != a2a.APIKeySecuritySchemeInHeader || apiKey.Type != "apiKey" {
+		t.Errorf("unexpected API key scheme: %+v", apiKey)
+	}
+	if len(requirements) != 1 {
+		t.Errorf("expected 1 requirement, got %d", len(requirements))
+	}
+
+	// OAuth2 without an authorization URL uses client credentials
+	schemes, requirements = BuildSecuritySchemes(SecurityConfig{
+		OAuth2TokenURL: "https://auth.example.com/token",
+		OAuth2Scopes:   []string{"agent:invoke"},
+	})
+	oauth, ok := schemes[SecuritySchemeOAuth2].(a2a.OAuth2SecurityScheme)
+	if !ok {
+		t.Fatalf("expected OAuth2SecurityScheme, got %T", schemes[SecuritySchemeOAuth2])
+	}
+	if oauth.Flows.ClientCredentials == nil || oauth.Flows.AuthzCode != nil {
+		t.Errorf("expected client credentials flow only, got %+v", oauth.Flows)
+	}
+	if _, ok := oauth.Flows.ClientCredentials.Scopes["agent:invoke"]; !ok {
+		t.Errorf("expected scope agent:invoke, got %v", oauth.Flows.ClientCredentials.Scopes)
+	}
+	if scopes := requirements[0][SecuritySchemeOAuth2]; len(scopes) != 1 || scopes[0] != "agent:invoke" {
+		t.Errorf("expected requirement scopes [agent:invoke], got %v", scopes)
+	}
+
+	// OAuth2 with an authorization URL uses the authorization code flow
+	schemes, _ = BuildSecuritySchemes(SecurityConfig{
+		OAuth2TokenURL:         "https://auth.example.com/token",
+		OAuth2AuthorizationURL: "https://auth.example.com/authorize",
+	})
+	oauth = schemes[SecuritySchemeOAuth2].(a2a.OAuth2SecurityScheme)
+	if oauth.Flows.AuthzCode == nil || oauth.Flows.AuthzCode.AuthzURL != "https://auth.example.com/authorize" {
+		t.Errorf("expected authorization code flow, got %+v", oauth.Flows)
+	}
+
+	// All schemes are alternatives to each other
+	schemes, requirements = BuildSecuritySchemes(SecurityConfig{
+		APIKeyHeader:    "X-API-Key",
+		OAuth2TokenURL:  "https://auth.example.com/token",
+		OIDCMetadataURL: "https://auth.example.com/.well-known/openid-configuration",
+	})
+	if len(schemes) != 3 || len(requirements) != 3 {
+		t.Errorf("expected 3 schemes and 3 requirements, got %d and %d", len(schemes), len(requirements))
+	}
+	oidc := schemes[SecuritySchemeOIDC].(a2a.OpenIDConnectSecurityScheme)
+	if oidc.OpenIDConnectURL != "https://auth.example.com/.well-known/openid-configuration" {
+		t.Errorf("unexpected OIDC URL: %s", oidc.OpenIDConnectURL)
+	}
+}
+
+func TestWithSecurity(t *testing.T) {
+	card := agentcard.New("Test Agent", "https://agent.example.com",
+		WithSecurity(SecurityConfig{APIKeyHeader: "X-API-Key"}),
+	)
+	if _, ok := card.SecuritySchemes[SecuritySchemeAPIKey]; !ok {
+		t.Errorf("expected API key security scheme, got %+v", card.SecuritySchemes)
+	}
+	if len(card.Security) != 1 {
+		t.Errorf("expected one security requirement, got %+v", card.Security)
+	}
+}
+
+func TestLoadServerlessConfig_Security(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	os.Setenv("A2A_AGENT_ID", "secure-agent")
+	os.Setenv("A2A_AGENT_NAME", "Secure Agent")
+	os.Setenv("A2A_AGENT_URL", "https://secure.example.com")
+	os.Setenv("A2A_AUTH_API_KEY_HEADER", "X-API-Key")
+	os.Setenv("A2A_AUTH_OAUTH2_TOKEN_URL", "https://auth.example.com/token")
+	os.Setenv("A2A_AUTH_OAUTH2_SCOPES", "agent:invoke, agent:read")
+	os.Setenv("A2A_AUTH_OAUTH2_JWKS_URL", "https://auth.example.com/jwks.json")
+	os.Setenv("A2A_API_KEY", "inbound-key")
+	os.Setenv("A2A_AUTH_ADMIN_SUBJECTS", "ops@example.com, ,apiKey")
+
+	config, err := NewConfigLoader().LoadServerlessConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(config.Security.OAuth2Scopes) != 2 || config.Security.OAuth2Scopes[1] != "agent:read" {
+		t.Errorf("expected trimmed scopes, got %v", config.Security.OAuth2Scopes)
+	}
+	if len(config.Security.AdminSubjects) != 2 || config.Security.AdminSubjects[1] != "apiKey" {
+		t.Errorf("expected trimmed admin subjects, got %v", config.Security.AdminSubjects)
+	}
+	if len(config.AgentCard.SecuritySchemes) != 2 || len(config.AgentCard.Security) != 2 {
+		t.Errorf("expected 2 schemes on agent card, got %v", config.AgentCard.SecuritySchemes)
+	}
+
+	os.Setenv("A2A_AUTH_OIDC_METADATA_URL", "http://insecure.example.com")
+	if _, err := NewConfigLoader().LoadServerlessConfig(); err == nil {
+		t.Error("expected error for non-https OIDC metadata URL")
+	}
+}
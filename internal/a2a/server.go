@@ -2,9 +2,10 @@ package a2a
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"iter"
-	"time"
+	"slices"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
@@ -16,11 +17,34 @@ type ServerlessA2AHandler struct {
 	taskStore    TaskStore
 	eventStore   EventStore
 	pushNotifier PushNotifier
+	runtimeDeps
+}
+
+// ErrTaskFinished is returned by TaskStore.SaveTask for a write that would
+// move a finished task to another state, such as a completion landing after
+// a cancel
+var ErrTaskFinished = errors.New("task already finished")
+
+// ErrTaskNotResumable is returned for a message sent to a task that has
+// finished, which takes no more input
+var ErrTaskNotResumable = errors.New("task is finished and cannot take more messages")
+
+// CheckTaskSave returns ErrTaskFinished when saving next over stored would
+// move a finished task to another state, for TaskStore implementations
+// that cannot make the write conditional themselves
+func CheckTaskSave(stored, next a2a.Task) error {
+	if isFinalState(stored.Status.State) && next.Status.State != stored.Status.State {
+		return fmt.Errorf("task %s is %s: %w", stored.ID, stored.Status.State, ErrTaskFinished)
+	}
+	return nil
 }
 
 // TaskStore defines the interface for task persistence in serverless environments
 type TaskStore interface {
 	GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error)
+	// SaveTask stores task, failing with ErrTaskFinished rather than
+	// replacing a stored task that is completed, failed, canceled or
+	// rejected with one in another state
 	SaveTask(ctx context.Context, task a2a.Task) error
 	DeleteTask(ctx context.Context, taskID a2a.TaskID) error
 	ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error)
@@ -39,12 +63,13 @@ type PushNotifier interface {
 }
 
 // NewServerlessA2AHandler creates a new serverless A2A handler
-func NewServerlessA2AHandler(config ServerlessConfig, taskStore TaskStore, eventStore EventStore, pushNotifier PushNotifier) *ServerlessA2AHandler {
+func NewServerlessA2AHandler(config ServerlessConfig, taskStore TaskStore, eventStore EventStore, pushNotifier PushNotifier, opts ...RuntimeOption) *ServerlessA2AHandler {
 	return &ServerlessA2AHandler{
 		config:       config,
 		taskStore:    taskStore,
 		eventStore:   eventStore,
 		pushNotifier: pushNotifier,
+		runtimeDeps:  newRuntimeDeps(opts),
 	}
 }
 
@@ -57,16 +82,27 @@ func (h *ServerlessA2AHandler) OnGetTask(ctx context.Context, query a2a.TaskQuer
 	if err != nil {
 		return a2a.Task{}, fmt.Errorf("failed to get task %s: %w", query.ID, err)
 	}
-
-	// Limit history if requested
-	if query.HistoryLength != nil && *query.HistoryLength > 0 {
-		historyLen := *query.HistoryLength
-		if len(task.History) > historyLen {
-			task.History = task.History[len(task.History)-historyLen:]
-		}
+	task, err = h.timeOut(ctx, task)
+	if err != nil {
+		return a2a.Task{}, err
 	}
 
-	return task, nil
+	return limitHistory(task, query.HistoryLength), nil
+}
+
+// limitHistory trims the history of a task returned to the client to the
+// requested historyLength: nil keeps all of it, 0 none and N the last N
+// messages. The stored task keeps its full history.
+func limitHistory(task a2a.Task, historyLength *int) a2a.Task {
+	if historyLength == nil || *historyLength < 0 {
+		return task
+	}
+	if *historyLength == 0 {
+		task.History = []a2a.Message{}
+	} else if len(task.History) > *historyLength {
+		task.History = task.History[len(task.History)-*historyLength:]
+	}
+	return task
 }
 
 // OnCancelTask handles the 'tasks/cancel' protocol method
@@ -76,17 +112,34 @@ func (h *ServerlessA2AHandler) OnCancelTask(ctx context.Context, id a2a.TaskIDPa
 		return a2a.Task{}, fmt.Errorf("failed to get task %s: %w", id.ID, err)
 	}
 
+	// Only a task still in progress can be canceled
+	switch task.Status.State {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return a2a.Task{}, fmt.Errorf("task %s is %s: %w", id.ID, task.Status.State, a2a.ErrTaskNotCancelable)
+	}
+
 	// Update task status to canceled
-	now := time.Now()
+	previous := task.Status.State
+	now := h.clock.Now()
 	task.Status = a2a.TaskStatus{
 		State:     a2a.TaskStateCanceled,
 		Timestamp: &now,
 	}
 
 	err = h.taskStore.SaveTask(ctx, task)
+	if errors.Is(err, ErrTaskFinished) {
+		// The task finished since it was read; a cancel that lost to
+		// another cancel still succeeded
+		finished, err := h.finishedTask(ctx, id.ID)
+		if err != nil || finished.Status.State == a2a.TaskStateCanceled {
+			return finished, err
+		}
+		return a2a.Task{}, fmt.Errorf("task %s is %s: %w", id.ID, finished.Status.State, a2a.ErrTaskNotCancelable)
+	}
 	if err != nil {
 		return a2a.Task{}, fmt.Errorf("failed to save canceled task %s: %w", id.ID, err)
 	}
+	MetricsFromContext(ctx).RecordTaskTransition(previous, task.Status.State)
 
 	// Create and store status update event
 	statusEvent := a2a.TaskStatusUpdateEvent{
@@ -97,36 +150,123 @@ func (h *ServerlessA2AHandler) OnCancelTask(ctx context.Context, id a2a.TaskIDPa
 		Final:     true,
 	}
 
-	err = h.eventStore.SaveEvent(ctx, statusEvent)
+	saved := 1
+	err = h.saveEvent(ctx, statusEvent)
 	if err != nil {
 		// Log error but don't fail the request
-		// In a real implementation, you'd use proper logging
-		fmt.Printf("Warning: failed to save status event for task %s: %v\n", id.ID, err)
+		LoggerFromContext(ctx).Warn("failed to save status event", LogKeyTaskID, id.ID, LogKeyError, err)
+		saved = 0
+	}
+	h.recordStats(ctx, previous, task, saved)
+
+	return task, nil
+}
+
+// savePushConfig saves the push config a message was sent with for its
+// task, logging a failure rather than failing the message
+func (h *ServerlessA2AHandler) savePushConfig(ctx context.Context, taskID a2a.TaskID, message a2a.MessageSendParams) {
+	if message.Config == nil || message.Config.PushConfig == nil || h.pushConfigs == nil {
+		return
+	}
+	config := a2a.TaskPushConfig{TaskID: taskID, Config: *message.Config.PushConfig}
+	configID := pushConfigID(taskID, config.Config.ID)
+	config.Config.ID = &configID
+	if err := h.pushConfigs.SavePushConfig(ctx, config); err != nil {
+		LoggerFromContext(ctx).Warn("failed to save push notification config", LogKeyError, err)
 	}
+}
 
+// finishedTask reads a task back after a write to it failed with
+// ErrTaskFinished, to return the state it finished in
+func (h *ServerlessA2AHandler) finishedTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	task, err := h.taskStore.GetTask(ctx, taskID)
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to get finished task %s: %w", taskID, err)
+	}
 	return task, nil
 }
 
 // OnSendMessage handles the 'message/send' protocol method (non-streaming)
 func (h *ServerlessA2AHandler) OnSendMessage(ctx context.Context, message a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	if h.dedup != nil {
+		return h.sendOnce(ctx, message)
+	}
+	return h.sendMessage(ctx, message, false)
+}
+
+// sendMessage handles a message, either as sent or, when retried is set, as
+// a delivery from the retry queue of a message already in its task's history
+func (h *ServerlessA2AHandler) sendMessage(ctx context.Context, message a2a.MessageSendParams, retried bool) (a2a.SendMessageResult, error) {
 	// This is a simplified implementation - in a real serverless environment,
 	// you would likely queue the message for processing by another function
 	
 	var task a2a.Task
 	var err error
 
+	if message.Config != nil && message.Config.PushConfig != nil {
+		if err := h.config.Webhooks.ValidateURL(message.Config.PushConfig.URL); err != nil {
+			return nil, fmt.Errorf("push notification config: %w", err)
+		}
+	}
+
 	if message.Message.TaskID != nil {
 		// Continue existing task
 		task, err = h.taskStore.GetTask(ctx, *message.Message.TaskID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get existing task %s: %w", *message.Message.TaskID, err)
 		}
+		if h.executor != nil && h.contextLock != nil {
+			unlock, err := h.lockContext(ctx, task.ContextID)
+			if err != nil {
+				return nil, err
+			}
+			defer unlock()
+			// Another execution may have moved the task on while the lock
+			// was awaited
+			if task, err = h.taskStore.GetTask(ctx, *message.Message.TaskID); err != nil {
+				return nil, fmt.Errorf("failed to get existing task %s: %w", *message.Message.TaskID, err)
+			}
+		}
+		if !retried && isFinalState(task.Status.State) {
+			return nil, fmt.Errorf("task %s is %s: %w", task.ID, task.Status.State, ErrTaskNotResumable)
+		}
+		if retried {
+			// Work that was canceled, or gave up on, while the message was queued
+			// is not started again
+			if task, err = h.timeOut(ctx, task); err != nil {
+				return nil, err
+			}
+			if !awaitsAgent(task.Status.State) {
+				LoggerFromContext(ctx).Info("queued message skipped, task is "+string(task.Status.State), LogKeyTaskID, task.ID)
+				return task, nil
+			}
+			// A held task's start has come
+			delete(task.Metadata, TaskStartAtMetadataKey)
+			message.Message = heldMessage(task, message.Message)
+		}
 	} else {
-		// Create new task
-		now := time.Now()
+		// Create new task, in the client's context when it names one. Other
+		// tasks may be running in that context, so it is locked first; a
+		// context made up here is the task's own.
+		var contextID string
+		if message.Message.ContextID != nil && *message.Message.ContextID != "" {
+			contextID = *message.Message.ContextID
+			if h.executor != nil && h.contextLock != nil {
+				unlock, err := h.lockContext(ctx, contextID)
+				if err != nil {
+					return nil, err
+				}
+				defer unlock()
+			}
+		}
+		taskID := a2a.TaskID(h.ids.NewID())
+		if contextID == "" {
+			contextID = h.ids.NewID()
+		}
+		now := h.clock.Now()
 		task = a2a.Task{
-			ID:        a2a.TaskID(fmt.Sprintf("task_%d", now.UnixNano())),
-			ContextID: generateContextID(),
+			ID:        taskID,
+			ContextID: contextID,
 			Kind:      "task",
 			History:   []a2a.Message{},
 			Status: a2a.TaskStatus{
@@ -135,37 +275,157 @@ func (h *ServerlessA2AHandler) OnSendMessage(ctx context.Context, message a2a.Me
 			},
 			Metadata: make(map[string]any),
 		}
+		// Recorded whether or not own_tasks_only is set, so turning it on
+		// later covers tasks created before
+		if principal, ok := PrincipalFromContext(ctx); ok && principal.Subject != "" {
+			task.Metadata[TaskOwnerMetadataKey] = principal.Subject
+		}
+	}
+
+	// Stores and executors called below log with the task's identifiers
+	ctx = WithLogAttrs(ctx, LogKeyTaskID, task.ID, LogKeyContextID, task.ContextID)
+	AnnotateSpan(ctx, SpanAttrTaskID.String(string(task.ID)), SpanAttrContextID.String(task.ContextID))
+	LoggerFromContext(ctx).Debug("message received")
+
+	// A message to run later holds its task in submitted until then
+	if !retried {
+		if at, ok := requestStart(message, h.clock.Now()); ok {
+			return h.hold(ctx, message, task, at)
+		}
 	}
 
-	// Add message to task history
-	task.History = append(task.History, message.Message)
+	var events []a2a.Event
+	if h.executor != nil {
+		var reply *a2a.Message
+		reply, events, err = h.execute(ctx, message, task)
+		if errors.Is(err, ErrTaskCanceled) {
+			// The cancellation stands; the task is returned as it was
+			// canceled, without the executor's work
+			LoggerFromContext(ctx).Info("execution stopped, task canceled")
+			canceled, err := h.taskStore.GetTask(ctx, task.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get canceled task %s: %w", task.ID, err)
+			}
+			if message.Config != nil {
+				canceled = limitHistory(canceled, message.Config.HistoryLength)
+			}
+			return canceled, nil
+		}
+		if err != nil {
+			return h.executionFailed(ctx, message, task, retried, err)
+		}
+		if reply != nil {
+			// Answered directly, so there is no task to keep
+			return *reply, nil
+		}
+	}
+
+	// Add message to task history, once however often it is executed
+	if !retried {
+		task.History = append(task.History, message.Message)
+	}
 
 	// Update task status to working
-	now := time.Now()
+	previous := task.Status.State
+	now := h.clock.Now()
 	task.Status = a2a.TaskStatus{
 		State:     a2a.TaskStateWorking,
 		Timestamp: &now,
 	}
+	for i, event := range events {
+		events[i] = applyEvent(&task, event, now)
+	}
+	h.checkOutputSchema(ctx, message.Message, &task, events, now)
+	h.setDeadline(&task, message, now)
+	if task.Kind == "" {
+		task.Kind = "task"
+	}
 
-	// Save updated task
+	// Save updated task. A task canceled or timed out while the executor
+	// ran stays as it was. The sender is told its message was not taken; a
+	// queued message has nobody waiting, so its task is returned as it is.
 	err = h.taskStore.SaveTask(ctx, task)
+	if errors.Is(err, ErrTaskFinished) {
+		LoggerFromContext(ctx).Info("task finished meanwhile, result dropped", LogKeyError, err)
+		if !retried {
+			return nil, fmt.Errorf("%w: %w", ErrTaskNotResumable, err)
+		}
+		finished, err := h.finishedTask(ctx, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		if message.Config != nil {
+			finished = limitHistory(finished, message.Config.HistoryLength)
+		}
+		return finished, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to save task: %w", err)
 	}
+	MetricsFromContext(ctx).RecordTaskTransition(previous, task.Status.State)
+
+	// A config sent with the message is set before the task's events, so
+	// they are pushed to it
+	h.savePushConfig(ctx, task.ID, message)
+
+	saved := 0
+	for _, event := range events {
+		if err := h.saveEvent(ctx, event); err != nil {
+			// Log error but don't fail the request
+			LoggerFromContext(ctx).Warn("failed to save executor event", LogKeyError, err)
+			continue
+		}
+		saved++
+	}
+	// A new task is counted from no state, not the submitted state it
+	// was never saved in
+	if message.Message.TaskID == nil {
+		previous = ""
+	}
+	h.recordStats(ctx, previous, task, saved)
+
+	if h.executor == nil && h.workQueue != nil && !retried && awaitsAgent(task.Status.State) {
+		if err := h.workQueue.QueueMessage(ctx, h.queuedMessage(message, task), 0); err != nil {
+			return nil, fmt.Errorf("failed to queue task %s: %w", task.ID, err)
+		}
+	}
 
-	// In a real implementation, you would process the message here
-	// For now, we'll just return the task
+	if message.Config != nil {
+		task = limitHistory(task, message.Config.HistoryLength)
+	}
 	return task, nil
 }
 
 // OnResubscribeToTask handles the `tasks/resubscribe` protocol method
 func (h *ServerlessA2AHandler) OnResubscribeToTask(ctx context.Context, id a2a.TaskIDParams) iter.Seq2[a2a.Event, error] {
+	return h.ResubscribeToTaskAfter(ctx, id, "")
+}
+
+// ResubscribeToTaskAfter replays a task's events after the one whose
+// EventID is lastEventID, for a client reconnecting with Last-Event-ID.
+// Every event is replayed when lastEventID is "" or not among them.
+func (h *ServerlessA2AHandler) ResubscribeToTaskAfter(ctx context.Context, id a2a.TaskIDParams, lastEventID string) iter.Seq2[a2a.Event, error] {
 	return func(yield func(a2a.Event, error) bool) {
+		// A task past its deadline is timed out first, so its final event
+		// is replayed
+		if task, err := h.taskStore.GetTask(ctx, id.ID); err == nil {
+			if _, err := h.timeOut(ctx, task); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+
 		events, err := h.eventStore.GetEvents(ctx, id.ID)
 		if err != nil {
 			yield(nil, fmt.Errorf("failed to get events for task %s: %w", id.ID, err))
 			return
 		}
+		orderChunks(events)
+		if lastEventID != "" {
+			if seen := slices.IndexFunc(events, func(event a2a.Event) bool { return EventID(event) == lastEventID }); seen >= 0 {
+				events = events[seen+1:]
+			}
+		}
 
 		for _, event := range events {
 			if !yield(event, nil) {
@@ -203,41 +463,55 @@ func (h *ServerlessA2AHandler) OnSendMessageStream(ctx context.Context, message
 			// In a real implementation, you would continue yielding events
 			// as the task progresses
 		}
+		if reply, ok := result.(a2a.Message); ok {
+			yield(reply, nil)
+		}
 	}
 }
 
-// OnGetTaskPushConfig handles the `tasks/pushNotificationConfig/get` protocol method
+// OnGetTaskPushConfig handles the `tasks/pushNotificationConfig/get` protocol
+// method. Without a config ID it gets the config set without one.
 func (h *ServerlessA2AHandler) OnGetTaskPushConfig(ctx context.Context, params a2a.GetTaskPushConfigParams) (a2a.TaskPushConfig, error) {
-	// This would typically be stored in a database
-	// For now, return an empty config
-	return a2a.TaskPushConfig{
-		TaskID: params.TaskID,
-		Config: a2a.PushConfig{},
-	}, nil
+	if h.pushConfigs == nil {
+		return a2a.TaskPushConfig{}, a2a.ErrPushNotificationNotSupported
+	}
+	return h.pushConfigs.GetPushConfig(ctx, params.TaskID, pushConfigID(params.TaskID, params.ConfigID))
 }
 
 // OnListTaskPushConfig handles the `tasks/pushNotificationConfig/list` protocol method
 func (h *ServerlessA2AHandler) OnListTaskPushConfig(ctx context.Context, params a2a.ListTaskPushConfigParams) ([]a2a.TaskPushConfig, error) {
-	// This would typically be stored in a database
-	// For now, return an empty list
-	return []a2a.TaskPushConfig{}, nil
+	if h.pushConfigs == nil {
+		return nil, a2a.ErrPushNotificationNotSupported
+	}
+	return h.pushConfigs.ListPushConfigs(ctx, params.TaskID)
 }
 
-// OnSetTaskPushConfig handles the `tasks/pushNotificationConfig/set` protocol method
+// OnSetTaskPushConfig handles the `tasks/pushNotificationConfig/set` protocol
+// method. A config set without an ID gets the task's ID.
 func (h *ServerlessA2AHandler) OnSetTaskPushConfig(ctx context.Context, params a2a.TaskPushConfig) (a2a.TaskPushConfig, error) {
-	// This would typically be stored in a database
-	// For now, just return the input
+	if h.pushConfigs == nil {
+		return a2a.TaskPushConfig{}, a2a.ErrPushNotificationNotSupported
+	}
+	// Webhooks are called from inside the deployment, so only allowed hosts are accepted
+	if err := h.config.Webhooks.ValidateURL(params.Config.URL); err != nil {
+		return a2a.TaskPushConfig{}, fmt.Errorf("push notification config: %w", err)
+	}
+	if _, err := h.taskStore.GetTask(ctx, params.TaskID); err != nil {
+		return a2a.TaskPushConfig{}, fmt.Errorf("failed to get task %s: %w", params.TaskID, err)
+	}
+
+	configID := pushConfigID(params.TaskID, params.Config.ID)
+	params.Config.ID = &configID
+	if err := h.pushConfigs.SavePushConfig(ctx, params); err != nil {
+		return a2a.TaskPushConfig{}, err
+	}
 	return params, nil
 }
 
 // OnDeleteTaskPushConfig handles the `tasks/pushNotificationConfig/delete` protocol method
 func (h *ServerlessA2AHandler) OnDeleteTaskPushConfig(ctx context.Context, params a2a.DeleteTaskPushConfigParams) error {
-	// This would typically delete from a database
-	// For now, just return success
-	return nil
+	if h.pushConfigs == nil {
+		return a2a.ErrPushNotificationNotSupported
+	}
+	return h.pushConfigs.DeletePushConfig(ctx, params.TaskID, params.ConfigID)
 }
-
-// generateContextID generates a unique context ID
-func generateContextID() string {
-	return fmt.Sprintf("ctx_%d", time.Now().UnixNano())
-}
\ No newline at end of file
@@ -0,0 +1,23 @@
+package a2a
+
+import "testing"
+
+func TestSignPayload(t *testing.T) {
+	// Vector from RFC 4231 test case 2
+	got := SignPayload("Jefe", []byte("what do ya want for nothing?"))
+	expected := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestSignRequest(t *testing.T) {
+	// The timestamp is signed with the body, so neither can be swapped
+	got := SignRequest("key", 1754049600, []byte(`{"id":1}`))
+	if expected := SignPayload("key", []byte(`1754049600.{"id":1}`)); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if SignatureTimestampHeader("X-Signature") != "X-Signature-Timestamp" {
+		t.Errorf("unexpected timestamp header %s", SignatureTimestampHeader("X-Signature"))
+	}
+}
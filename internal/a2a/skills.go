@@ -0,0 +1,166 @@
+package a2a
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// SkillConfig is the config file representation of an a2a.AgentSkill.
+// The SDK type has no JSON tags, so this gives the config format stable snake_case keys.
+type SkillConfig struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"input_modes,omitempty"`
+	OutputModes []string `json:"output_modes,omitempty"`
+	// Security restricts the skill to callers meeting one of the
+	// requirements, as described at Authenticator.CanUseSkill
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+// ParseSkillsConfig parses a JSON array of skills into SDK agent skills
+func ParseSkillsConfig(data []byte) ([]a2a.AgentSkill, error) {
+	var configs []SkillConfig
+	if err := FromJSON(data, &configs); err != nil {
+		return nil, fmt.Errorf("invalid skills JSON: %w", err)
+	}
+
+	if err := ValidateSkillsConfig(configs); err != nil {
+		return nil, err
+	}
+
+	skills := make([]a2a.AgentSkill, 0, len(configs))
+	for _, c := range configs {
+		skills = append(skills, a2a.AgentSkill{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Tags:        c.Tags,
+			Examples:    c.Examples,
+			InputModes:  c.InputModes,
+			OutputModes: c.OutputModes,
+			Security:    c.Security,
+		})
+	}
+
+	return skills, nil
+}
+
+// ValidateSkillsConfig validates skill definitions. Paths are relative to the
+// skills array, e.g. [1].id; callers root them at where the array came from.
+func ValidateSkillsConfig(configs []SkillConfig) error {
+	var errs ValidationErrors
+	// An empty list would replace the default skill and leave the card advertising nothing
+	if len(configs) == 0 {
+		errs.Add("", ValidationCodeRequired, "must list at least one skill")
+	}
+	seen := make(map[string]bool)
+	for i, c := range configs {
+		path := fmt.Sprintf("[%d]", i)
+		if c.ID == "" {
+			errs.Add(path+".id", ValidationCodeRequired, "is required")
+		}
+		if c.Name == "" {
+			errs.Add(path+".name", ValidationCodeRequired, "is required")
+		}
+		if c.ID != "" && seen[c.ID] {
+			errs.Add(path+".id", ValidationCodeDuplicate, fmt.Sprintf("'%s' is duplicated", c.ID))
+		}
+		seen[c.ID] = true
+		for j, requirement := range c.Security {
+			for scheme := range requirement {
+				if !slices.Contains(securitySchemeNames, scheme) {
+					errs.Add(fmt.Sprintf("%s.security[%d]", path, j), ValidationCodeInvalid,
+						fmt.Sprintf("'%s' must be one of %s", scheme, strings.Join(securitySchemeNames, ", ")))
+				}
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// LoadSkillsFromEnv loads skills from A2A_AGENT_SKILLS (inline JSON) or
+// A2A_AGENT_SKILLS_FILE (path to a JSON file). Returns nil when neither is set.
+// Errors are rooted at the variable, e.g. A2A_AGENT_SKILLS[1].id.
+func LoadSkillsFromEnv() ([]a2a.AgentSkill, error) {
+	if inline := os.Getenv("A2A_AGENT_SKILLS"); inline != "" {
+		skills, err := ParseSkillsConfig([]byte(inline))
+		if err != nil {
+			var errs ValidationErrors
+			errs.Merge("A2A_AGENT_SKILLS", err)
+			return nil, errs
+		}
+		return skills, nil
+	}
+
+	if path := os.Getenv("A2A_AGENT_SKILLS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read skills file: %w", err)
+		}
+		skills, err := ParseSkillsConfig(data)
+		if err != nil {
+			var errs ValidationErrors
+			errs.Merge("A2A_AGENT_SKILLS_FILE", err)
+			return nil, fmt.Errorf("%s: %w", path, errs)
+		}
+		return skills, nil
+	}
+
+	return nil, nil
+}
+
+// SkillProvider is implemented by executors, or other code handling skills,
+// that define their skills themselves, so the served card lists exactly what
+// the code can do
+type SkillProvider interface {
+	Skills() []a2a.AgentSkill
+}
+
+// WithSkillProvider publishes provider's skills on the agent card, as for an
+// executor that implements SkillProvider. It may be given for each skill
+// handler.
+func WithSkillProvider(provider SkillProvider) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.skillProviders = append(d.skillProviders, provider)
+	}
+}
+
+// MergeSkills adds provided skills to configured ones. A provided skill
+// replaces a configured one with its ID, keeping its place; the others are
+// appended in order, a later one with the same ID replacing an earlier one.
+// Skills without an ID are skipped.
+func MergeSkills(configured, provided []a2a.AgentSkill) []a2a.AgentSkill {
+	merged := slices.Clone(configured)
+	for _, skill := range provided {
+		if skill.ID == "" {
+			continue
+		}
+		i := slices.IndexFunc(merged, func(s a2a.AgentSkill) bool { return s.ID == skill.ID })
+		if i < 0 {
+			merged = append(merged, skill)
+			continue
+		}
+		merged[i] = skill
+	}
+	return merged
+}
+
+// AgentCard returns card with the skills the executor and skill providers
+// define merged in, the card the handler's agent serves
+func (h *ServerlessA2AHandler) AgentCard(card a2a.AgentCard) a2a.AgentCard {
+	providers := h.skillProviders
+	if provider, ok := h.executor.(SkillProvider); ok {
+		providers = append([]SkillProvider{provider}, providers...)
+	}
+	for _, provider := range providers {
+		card.Skills = MergeSkills(card.Skills, provider.Skills())
+	}
+	return card
+}
@@ -0,0 +1,259 @@
+package a2a
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+const testSkillsJSON = `[
+	{
+		"id": "summarize",
+		"name": "Summarizer",
+		"description": "Summarizes documents",
+		"tags": ["text", "summary"],
+		"examples": ["Summarize this article"],
+		"input_modes": ["text/plain", "application/pdf"],
+		"output_modes": ["text/plain"]
+	},
+	{"id": "translate", "name": "Translator", "security": [{"oauth2": ["translate.use"]}]}
+]`
+
+func TestParseSkillsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		expectError bool
+		errorMsg    string
+		expectedIDs []string
+	}{
+		{
+			name:        "multiple skills",
+			data:        testSkillsJSON,
+			expectedIDs: []string{"summarize", "translate"},
+		},
+		{
+			name:        "empty list",
+			data:        `[]`,
+			expectError: true,
+			errorMsg:    "must list at least one skill",
+		},
+		{
+			name:        "missing id",
+			data:        `[{"name": "No ID"}]`,
+			expectError: true,
+			errorMsg:    "[0].id is required",
+		},
+		{
+			name:        "missing name",
+			data:        `[{"id": "no-name"}]`,
+			expectError: true,
+			errorMsg:    "[0].name is required",
+		},
+		{
+			name:        "duplicate id",
+			data:        `[{"id": "a", "name": "A"}, {"id": "a", "name": "Also A"}]`,
+			expectError: true,
+			errorMsg:    "[1].id 'a' is duplicated",
+		},
+		{
+			name:        "unknown security scheme",
+			data:        `[{"id": "a", "name": "A", "security": [{"basic": []}]}]`,
+			expectError: true,
+			errorMsg:    "[0].security[0] 'basic' must be one of",
+		},
+		{
+			name:        "invalid JSON",
+			data:        `{"id": "not-an-array"}`,
+			expectError: true,
+			errorMsg:    "invalid skills JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			skills, err := ParseSkillsConfig([]byte(tt.data))
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+					return
+				}
+				if !containsString(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if len(skills) != len(tt.expectedIDs) {
+				t.Fatalf("expected %d skills, got %d", len(tt.expectedIDs), len(skills))
+			}
+			for i, id := range tt.expectedIDs {
+				if skills[i].ID != id {
+					t.Errorf("expected skills[%d].ID '%s', got '%s'", i, id, skills[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSkillsConfig_Fields(t *testing.T) {
+	skills, err := ParseSkillsConfig([]byte(testSkillsJSON))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	skill := skills[0]
+	if skill.Name != "Summarizer" || skill.Description != "Summarizes documents" {
+		t.Errorf("unexpected name/description: %+v", skill)
+	}
+	if len(skill.Tags) != 2 || len(skill.Examples) != 1 {
+		t.Errorf("expected 2 tags and 1 example, got %v and %v", skill.Tags, skill.Examples)
+	}
+	if len(skill.InputModes) != 2 || skill.InputModes[1] != "application/pdf" {
+		t.Errorf("unexpected input modes: %v", skill.InputModes)
+	}
+	if len(skill.OutputModes) != 1 || skill.OutputModes[0] != "text/plain" {
+		t.Errorf("unexpected output modes: %v", skill.OutputModes)
+	}
+	if security := skills[1].Security; len(security) != 1 || security[0][SecuritySchemeOAuth2][0] != "translate.use" {
+		t.Errorf("unexpected security: %v", security)
+	}
+}
+
+func TestLoadSkillsFromEnv(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	// Nothing configured
+	skills, err := LoadSkillsFromEnv()
+	if err != nil || skills != nil {
+		t.Errorf("expected nil skills and no error, got %v, %v", skills, err)
+	}
+
+	// From file
+	path := filepath.Join(t.TempDir(), "skills.json")
+	if err := os.WriteFile(path, []byte(testSkillsJSON), 0o600); err != nil {
+		t.Fatalf("failed to write skills file: %v", err)
+	}
+	os.Setenv("A2A_AGENT_SKILLS_FILE", path)
+	skills, err = LoadSkillsFromEnv()
+	if err != nil || len(skills) != 2 {
+		t.Errorf("expected 2 skills from file, got %d (%v)", len(skills), err)
+	}
+
+	// Inline JSON takes precedence over the file
+	os.Setenv("A2A_AGENT_SKILLS", `[{"id": "inline", "name": "Inline"}]`)
+	skills, err = LoadSkillsFromEnv()
+	if err != nil || len(skills) != 1 || skills[0].ID != "inline" {
+		t.Errorf("expected inline skill, got %v (%v)", skills, err)
+	}
+
+	// Errors name the variable the skills came from
+	os.Setenv("A2A_AGENT_SKILLS", `[{"id": "a", "name": "A"}, {"name": "No ID"}]`)
+	_, err = LoadSkillsFromEnv()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "A2A_AGENT_SKILLS[1].id" {
+		t.Errorf("expected A2A_AGENT_SKILLS[1].id error, got %v", err)
+	}
+	os.Unsetenv("A2A_AGENT_SKILLS")
+	os.WriteFile(path, []byte(`[]`), 0o600)
+	_, err = LoadSkillsFromEnv()
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Path != "A2A_AGENT_SKILLS_FILE" {
+		t.Errorf("expected A2A_AGENT_SKILLS_FILE error, got %v", err)
+	}
+
+	// Missing file
+	os.Setenv("A2A_AGENT_SKILLS_FILE", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := LoadSkillsFromEnv(); err == nil {
+		t.Error("expected error for missing skills file")
+	}
+}
+
+func TestLoadAgentCard_Skills(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	os.Setenv("A2A_AGENT_NAME", "Skilled Agent")
+	os.Setenv("A2A_AGENT_URL", "https://skilled.example.com")
+	os.Setenv("A2A_AGENT_SKILLS", testSkillsJSON)
+
+	card, err := NewConfigLoader().loadAgentCard()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(card.Skills) != 2 {
+		t.Errorf("expected 2 skills on agent card, got %d", len(card.Skills))
+	}
+
+	os.Setenv("A2A_AGENT_SKILLS", `[{"id": ""}]`)
+	if _, err := NewConfigLoader().loadAgentCard(); err == nil {
+		t.Error("expected error for invalid skills")
+	}
+}
+
+// skillExecutor is an executor defining its own skills
+type skillExecutor struct {
+	replyExecutor
+	skills []a2a.AgentSkill
+}
+
+func (e *skillExecutor) Skills() []a2a.AgentSkill {
+	return e.skills
+}
+
+// skillList is a SkillProvider for a fixed list
+type skillList []a2a.AgentSkill
+
+func (l skillList) Skills() []a2a.AgentSkill {
+	return l
+}
+
+func TestMergeSkills(t *testing.T) {
+	configured := []a2a.AgentSkill{{ID: "summarize", Name: "Summarizer"}, {ID: "translate", Name: "Translator"}}
+	merged := MergeSkills(configured, []a2a.AgentSkill{
+		{ID: "classify", Name: "Classifier"},
+		{ID: "summarize", Name: "Summarizer v2"},
+		{Name: "Nameless"},
+		{ID: "classify", Name: "Classifier v2"},
+	})
+	var names []string
+	for _, skill := range merged {
+		names = append(names, skill.ID+"="+skill.Name)
+	}
+	if want := "summarize=Summarizer v2,translate=Translator,classify=Classifier v2"; strings.Join(names, ",") != want {
+		t.Errorf("expected %s, got %v", want, names)
+	}
+	if configured[0].Name != "Summarizer" {
+		t.Errorf("expected the configured skills left unchanged, got %+v", configured)
+	}
+}
+
+func TestHandlerAgentCardMergesProvidedSkills(t *testing.T) {
+	card := a2a.AgentCard{Name: "Agent", Skills: []a2a.AgentSkill{{ID: "summarize", Name: "Configured"}}}
+	executor := &skillExecutor{skills: []a2a.AgentSkill{{ID: "summarize", Name: "From code"}}}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, &memoryStores{}, &memoryStores{}, nil,
+		WithExecutor(executor), WithSkillProvider(skillList{{ID: "translate", Name: "Translator"}}))
+
+	served := h.AgentCard(card)
+	if len(served.Skills) != 2 || served.Skills[0].Name != "From code" || served.Skills[1].ID != "translate" {
+		t.Errorf("expected the executor's and provider's skills on the card, got %+v", served.Skills)
+	}
+	if len(card.Skills) != 1 || card.Skills[0].Name != "Configured" {
+		t.Errorf("expected the given card left unchanged, got %+v", card.Skills)
+	}
+
+	// Without providers the card is served as configured
+	if plain := NewServerlessA2AHandler(ServerlessConfig{}, &memoryStores{}, &memoryStores{}, nil).AgentCard(card); len(plain.Skills) != 1 {
+		t.Errorf("expected only the configured skill, got %+v", plain.Skills)
+	}
+}
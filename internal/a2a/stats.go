@@ -0,0 +1,219 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// Bounds of the hours one admin/stats/get call reports
+const (
+	DefaultStatsHours = 24
+	MaxStatsHours     = 168
+)
+
+// statsTTL is how long hourly stats are kept: the longest report, and a day
+// to spare
+const statsTTL = (MaxStatsHours + 24) * time.Hour
+
+// StatsParams select the hours admin/stats/get reports, ending with the
+// current one
+type StatsParams struct {
+	// Hours is how many hours are reported (default DefaultStatsHours, at
+	// most MaxStatsHours)
+	Hours int `json:"hours,omitempty"`
+}
+
+// Validate checks the params of a stats call
+func (p StatsParams) Validate() error {
+	var errs ValidationErrors
+	if p.Hours < 0 || p.Hours > MaxStatsHours {
+		errs.Add("hours", ValidationCodeInvalid, fmt.Sprintf("must be between 0 and %d", MaxStatsHours))
+	}
+	return errs.Err()
+}
+
+// HourlyStats counts what the agent did in one hour, in UTC
+type HourlyStats struct {
+	Hour   time.Time `json:"hour"`
+	Events int64     `json:"events"`
+	// Finished counts tasks reaching a final state: completed, failed,
+	// canceled or rejected
+	Finished int64 `json:"finished"`
+	// CompletionMillis sums how long the Timed of the finished tasks took
+	// from creation. Only tasks with UUIDv7 IDs tell when they were created.
+	CompletionMillis int64 `json:"completion_ms"`
+	Timed            int64 `json:"timed"`
+}
+
+// QueueDepth is the approximate number of messages in one queue
+type QueueDepth struct {
+	Queue    string `json:"queue"`
+	Visible  int64  `json:"visible"`
+	InFlight int64  `json:"in_flight"`
+	Delayed  int64  `json:"delayed"`
+}
+
+// Queues reported by admin/stats/get
+const (
+	QueueNotifications = "notifications"
+	QueueDeadLetter    = "dead_letter"
+)
+
+// AgentStats is the result of admin/stats/get
+type AgentStats struct {
+	// States counts the agent's tasks by their last state. Tasks removed by
+	// retention or admin/purge stay counted.
+	States map[a2a.TaskState]int64 `json:"states"`
+	// Hours lists each hour reported, oldest first
+	Hours                   []HourlyStats `json:"hours"`
+	EventsPerHour           float64       `json:"events_per_hour"`
+	AverageCompletionMillis int64         `json:"average_completion_ms"`
+	// Queues is left out when the queues cannot be read
+	Queues []QueueDepth `json:"queues,omitempty"`
+}
+
+// StatsDelta is what one call adds to the stats
+type StatsDelta struct {
+	// From and To move a task between states. From is empty for a new task;
+	// both are empty when no task changed state.
+	From, To a2a.TaskState
+	Events   int64
+	// Completion is how long a task that finished took from creation; Timed
+	// is false when that is unknown
+	Completion time.Duration
+	Timed      bool
+}
+
+// finished reports whether the delta takes a task to a final state
+func (d StatsDelta) finished() bool {
+	return isFinalState(d.To) && !isFinalState(d.From)
+}
+
+// StatsStore keeps stats shared by every instance
+type StatsStore interface {
+	// AddStats adds delta to the state counts and to the hour of at
+	AddStats(ctx context.Context, at time.Time, delta StatsDelta) error
+	// GetStats returns the state counts and the hours from from to to that
+	// have any stats, oldest first
+	GetStats(ctx context.Context, from, to time.Time) (map[a2a.TaskState]int64, []HourlyStats, error)
+}
+
+// QueueDepthReader reads how many messages wait in the agent's queues
+type QueueDepthReader interface {
+	QueueDepths(ctx context.Context) ([]QueueDepth, error)
+}
+
+// StatsConfig names the table stats are counted in. Stats are off unless
+// Table is set.
+type StatsConfig struct {
+	Table string
+}
+
+// LoadStatsConfigFromEnv reads A2A_STATS_TABLE
+func LoadStatsConfigFromEnv() StatsConfig {
+	return StatsConfig{Table: getEnvOrDefault("A2A_STATS_TABLE", "")}
+}
+
+// WithStats counts task transitions and saved events in store, for
+// admin/stats/get to report
+func WithStats(store StatsStore) RuntimeOption {
+	return func(d *runtimeDeps) {
+		d.stats = store
+	}
+}
+
+// Stats reports an agent's stats from its store and queues
+type Stats struct {
+	store  StatsStore
+	queues QueueDepthReader
+	clock  Clock
+}
+
+// NewStats creates stats reading store and, unless it is nil, queues
+func NewStats(store StatsStore, queues QueueDepthReader, opts ...RuntimeOption) *Stats {
+	return &Stats{
+		store:  store,
+		queues: queues,
+		clock:  newRuntimeDeps(opts).clock,
+	}
+}
+
+// Get reports the state counts, each of the hours params select, and the
+// queue depths. Queues that cannot be read are logged and left out, so the
+// rest of the stats are still served.
+func (s *Stats) Get(ctx context.Context, params StatsParams) (AgentStats, error) {
+	if err := params.Validate(); err != nil {
+		return AgentStats{}, err
+	}
+	hours := params.Hours
+	if hours == 0 {
+		hours = DefaultStatsHours
+	}
+	to := s.clock.Now().UTC().Truncate(time.Hour)
+	from := to.Add(-time.Duration(hours-1) * time.Hour)
+
+	states, counted, err := s.store.GetStats(ctx, from, to)
+	if err != nil {
+		return AgentStats{}, err
+	}
+	stats := AgentStats{States: states, Hours: make([]HourlyStats, hours)}
+	if stats.States == nil {
+		stats.States = map[a2a.TaskState]int64{}
+	}
+	for i := range stats.Hours {
+		stats.Hours[i].Hour = from.Add(time.Duration(i) * time.Hour)
+	}
+	var events, completion, timed int64
+	for _, hour := range counted {
+		if i := int(hour.Hour.Sub(from) / time.Hour); i >= 0 && i < hours {
+			stats.Hours[i] = hour
+		}
+		events += hour.Events
+		completion += hour.CompletionMillis
+		timed += hour.Timed
+	}
+	stats.EventsPerHour = float64(events) / float64(hours)
+	if timed > 0 {
+		stats.AverageCompletionMillis = completion / timed
+	}
+
+	if s.queues != nil {
+		queues, err := s.queues.QueueDepths(ctx)
+		if err != nil {
+			LoggerFromContext(ctx).Warn("failed to read queue depths", LogKeyError, err)
+		}
+		stats.Queues = queues
+	}
+	return stats, nil
+}
+
+// recordStats adds a call's transition of task and the events it saved to
+// the stats, when they are counted. A failure is logged rather than failing
+// a call that has already been served.
+func (h *ServerlessA2AHandler) recordStats(ctx context.Context, from a2a.TaskState, task a2a.Task, events int) {
+	if h.stats == nil {
+		return
+	}
+	now := h.clock.Now()
+	delta := StatsDelta{From: from, To: task.Status.State, Events: int64(events)}
+	if delta.finished() {
+		if created, ok := idTime(string(task.ID)); ok && !now.Before(created) {
+			delta.Completion, delta.Timed = now.Sub(created), true
+		}
+	}
+	if err := h.stats.AddStats(ctx, now, delta); err != nil {
+		LoggerFromContext(ctx).Warn("failed to record stats", LogKeyError, err)
+	}
+}
+
+// finishedStates are the states a task is done with
+var finishedStates = []a2a.TaskState{a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected}
+
+// isFinalState reports whether a task in state is done with
+func isFinalState(state a2a.TaskState) bool {
+	return slices.Contains(finishedStates, state)
+}
@@ -0,0 +1,234 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// statsDynamoDB keeps stats items in memory, applying the stats store's ADD
+// updates by the names they alias
+type statsDynamoDB struct {
+	DynamoDBAPI
+	items map[[2]string]map[string]int64
+}
+
+func (d *statsDynamoDB) key(key map[string]types.AttributeValue) [2]string {
+	return [2]string{key["stats_key"].(*types.AttributeValueMemberS).Value, key["bucket"].(*types.AttributeValueMemberS).Value}
+}
+
+func (d *statsDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	key := d.key(params.Key)
+	if d.items[key] == nil {
+		d.items[key] = map[string]int64{}
+	}
+	adds, _, _ := strings.Cut(strings.TrimPrefix(*params.UpdateExpression, "ADD "), " SET ")
+	for _, add := range strings.Split(adds, ", ") {
+		name, value, _ := strings.Cut(add, " ")
+		n, _ := strconv.ParseInt(params.ExpressionAttributeValues[value].(*types.AttributeValueMemberN).Value, 10, 64)
+		d.items[key][params.ExpressionAttributeNames[name]] += n
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (d *statsDynamoDB) item(key [2]string) map[string]types.AttributeValue {
+	item := map[string]types.AttributeValue{
+		"stats_key": &types.AttributeValueMemberS{Value: key[0]},
+		"bucket":    &types.AttributeValueMemberS{Value: key[1]},
+	}
+	for name, n := range d.items[key] {
+		item[name] = &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+	}
+	return item
+}
+
+func (d *statsDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	key := d.key(params.Key)
+	if d.items[key] == nil {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{Item: d.item(key)}, nil
+}
+
+func (d *statsDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	value := func(name string) string {
+		return params.ExpressionAttributeValues[name].(*types.AttributeValueMemberS).Value
+	}
+	output := &dynamodb.QueryOutput{}
+	for key := range d.items {
+		if key[0] == value(":stats_key") && key[1] >= value(":from") && key[1] <= value(":to") {
+			output.Items = append(output.Items, d.item(key))
+		}
+	}
+	return output, nil
+}
+
+// depthSQS reports the same depth for every queue, or err
+type depthSQS struct {
+	SQSAPI
+	err error
+}
+
+func (s *depthSQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{
+		"ApproximateNumberOfMessages":           "3",
+		"ApproximateNumberOfMessagesNotVisible": "2",
+		"ApproximateNumberOfMessagesDelayed":    "1",
+	}}, nil
+}
+
+func TestIDTime(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 123e6, time.UTC)
+	created, ok := idTime(NewUUIDv7Generator(fixedClock{now: now}).NewID())
+	if !ok || !created.Equal(now) {
+		t.Errorf("expected %v, got %v %v", now, created, ok)
+	}
+	for _, id := range []string{"task-1", "0b7e6a4e-8c3c-4d3e-9a39-5f1d2c3b4a59"} {
+		if _, ok := idTime(id); ok {
+			t.Errorf("expected no time in %s", id)
+		}
+	}
+}
+
+func TestStatsParamsValidate(t *testing.T) {
+	for hours, valid := range map[int]bool{0: true, 24: true, MaxStatsHours: true, -1: false, MaxStatsHours + 1: false} {
+		err := StatsParams{Hours: hours}.Validate()
+		if valid != (err == nil) {
+			t.Errorf("hours %d: unexpected result %v", hours, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "hours") {
+			t.Errorf("hours %d: expected an error at hours, got %v", hours, err)
+		}
+	}
+}
+
+func TestAWSStatsStore(t *testing.T) {
+	ctx := context.Background()
+	client := &statsDynamoDB{items: map[[2]string]map[string]int64{}}
+	store := NewAWSStatsStore(client, "stats", "billing#")
+	now := time.Date(2025, 8, 1, 12, 30, 0, 0, time.UTC)
+
+	for _, add := range []struct {
+		at    time.Time
+		delta StatsDelta
+	}{
+		{now.Add(-time.Hour), StatsDelta{To: a2a.TaskStateSubmitted, Events: 1}},
+		{now.Add(-time.Hour), StatsDelta{To: a2a.TaskStateInputRequired, Events: 2}},
+		{now, StatsDelta{From: a2a.TaskStateInputRequired, To: a2a.TaskStateCompleted, Events: 3, Completion: 90 * time.Second, Timed: true}},
+		// No transition and nothing saved writes nothing
+		{now.Add(-48 * time.Hour), StatsDelta{From: a2a.TaskStateWorking, To: a2a.TaskStateWorking}},
+	} {
+		if err := store.AddStats(ctx, add.at, add.delta); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := client.items[[2]string{"billing#hours", "2025-07-30T12"}]; ok {
+		t.Error("expected no item for a delta adding nothing")
+	}
+
+	states, hours, err := store.GetStats(ctx, now.Add(-2*time.Hour), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[a2a.TaskState]int64{a2a.TaskStateSubmitted: 1, a2a.TaskStateInputRequired: 0, a2a.TaskStateCompleted: 1}
+	if len(states) != len(want) {
+		t.Errorf("expected states %v, got %v", want, states)
+	}
+	for state, n := range want {
+		if states[state] != n {
+			t.Errorf("expected %d %s, got %d", n, state, states[state])
+		}
+	}
+	events := map[int]int64{}
+	for _, hour := range hours {
+		events[hour.Hour.Hour()] = hour.Events
+		if hour.Hour.Hour() == 12 && (hour.Finished != 1 || hour.CompletionMillis != 90000 || hour.Timed != 1) {
+			t.Errorf("expected one task finished in 90 s, got %+v", hour)
+		}
+	}
+	if events[11] != 3 || events[12] != 3 || len(hours) != 2 {
+		t.Errorf("expected 3 events in each hour, got %+v", hours)
+	}
+}
+
+func TestStatsGet(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 30, 0, 0, time.UTC)
+	client := &statsDynamoDB{items: map[[2]string]map[string]int64{}}
+	store := NewAWSStatsStore(client, "stats", "")
+	store.AddStats(ctx, now.Add(-2*time.Hour), StatsDelta{To: a2a.TaskStateCompleted, Events: 4, Completion: 2 * time.Second, Timed: true})
+	store.AddStats(ctx, now, StatsDelta{To: a2a.TaskStateFailed, Events: 2, Completion: 4 * time.Second, Timed: true})
+	// An hour before the report is left out
+	store.AddStats(ctx, now.Add(-5*time.Hour), StatsDelta{Events: 100})
+
+	stats, err := NewStats(store, NewSQSQueueDepths(&depthSQS{}, "queue", "dlq"), WithClock(fixedClock{now: now})).Get(ctx, StatsParams{Hours: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Hours) != 4 || !stats.Hours[0].Hour.Equal(time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected 4 hours from 09:00, got %+v", stats.Hours)
+	}
+	if stats.Hours[1].Events != 4 || stats.Hours[2].Events != 0 || stats.Hours[3].Events != 2 {
+		t.Errorf("expected the counted hours in place, got %+v", stats.Hours)
+	}
+	if stats.EventsPerHour != 1.5 || stats.AverageCompletionMillis != 3000 {
+		t.Errorf("expected 1.5 events per hour and 3 s to complete, got %v and %d", stats.EventsPerHour, stats.AverageCompletionMillis)
+	}
+	if stats.States[a2a.TaskStateCompleted] != 1 || stats.States[a2a.TaskStateFailed] != 1 {
+		t.Errorf("expected the state counts, got %v", stats.States)
+	}
+	wantQueues := []QueueDepth{{Queue: QueueNotifications, Visible: 3, InFlight: 2, Delayed: 1}, {Queue: QueueDeadLetter, Visible: 3, InFlight: 2, Delayed: 1}}
+	if len(stats.Queues) != 2 || stats.Queues[0] != wantQueues[0] || stats.Queues[1] != wantQueues[1] {
+		t.Errorf("expected %+v, got %+v", wantQueues, stats.Queues)
+	}
+
+	// Unreadable queues are left out, and nothing counted reports zeroes
+	empty := NewAWSStatsStore(&statsDynamoDB{items: map[[2]string]map[string]int64{}}, "stats", "")
+	stats, err = NewStats(empty, NewSQSQueueDepths(&depthSQS{err: errors.New("access denied")}, "queue", "")).Get(ctx, StatsParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Hours) != DefaultStatsHours || stats.Queues != nil || stats.States == nil || stats.EventsPerHour != 0 {
+		t.Errorf("expected %d empty hours and no queues, got %+v", DefaultStatsHours, stats)
+	}
+}
+
+func TestHandlerRecordsStats(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	client := &statsDynamoDB{items: map[[2]string]map[string]int64{}}
+	store := NewAWSStatsStore(client, "stats", "")
+	stores := &memoryStores{}
+	h := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now}), WithStats(store))
+
+	result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	if _, err := h.OnCancelTask(ctx, a2a.TaskIDParams{ID: task.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	states, hours, err := store.GetStats(ctx, now, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if states[task.Status.State] != 0 || states[a2a.TaskStateCanceled] != 1 {
+		t.Errorf("expected the task counted as canceled only, got %v", states)
+	}
+	if len(hours) != 1 || hours[0].Finished != 1 || hours[0].Timed != 1 || hours[0].Events != int64(len(stores.events)) {
+		t.Errorf("expected the canceled task and its %d events counted, got %+v", len(stores.events), hours)
+	}
+}
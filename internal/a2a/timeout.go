@@ -0,0 +1,133 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// TimeoutMetadataKey is the message/send params metadata entry setting the
+// task's deadline for that call, a duration such as 90s or 2h, overriding
+// the agent's task_timeout
+const TimeoutMetadataKey = "timeout"
+
+// TaskDeadlineMetadataKey is the task metadata entry holding the RFC 3339
+// time by which a submitted or working task must progress, or be failed
+const TaskDeadlineMetadataKey = "deadline"
+
+// TaskLastSeenMetadataKey is the task metadata entry holding the RFC 3339
+// time work on the task was last seen: its last message or heartbeat. The
+// deadline is always the task's timeout after it.
+const TaskLastSeenMetadataKey = "last_seen"
+
+// ValidateTaskTimeout checks that a task timeout is empty or a positive
+// duration
+func ValidateTaskTimeout(timeout string) error {
+	var errs ValidationErrors
+	if timeout != "" {
+		if _, err := parseRetention(timeout); err != nil {
+			errs.Add("", ValidationCodeInvalid, fmt.Sprintf("'%s' must be a positive duration such as 15m or 1d", timeout))
+		}
+	}
+	return errs.Err()
+}
+
+// requestTimeout returns the timeout a message/send call sets, 0 when it
+// sets none. Invalid values are rejected by ValidateMessageSendParams.
+func requestTimeout(params a2a.MessageSendParams) time.Duration {
+	value, _ := params.Metadata[TimeoutMetadataKey].(string)
+	timeout, _ := parseRetention(value)
+	return timeout
+}
+
+// awaitsAgent reports whether a task in state is waiting on the agent, and
+// so can miss its deadline. Tasks waiting on the client, or finished, cannot.
+func awaitsAgent(state a2a.TaskState) bool {
+	return state == a2a.TaskStateSubmitted || state == a2a.TaskStateWorking
+}
+
+// setDeadline gives a task left waiting on the agent a deadline timeout from
+// now, by the call's own timeout or else the agent's. Any other task, or one
+// without a timeout, has its deadline cleared.
+func (h *ServerlessA2AHandler) setDeadline(task *a2a.Task, params a2a.MessageSendParams, now time.Time) {
+	timeout := requestTimeout(params)
+	if timeout == 0 {
+		timeout, _ = parseRetention(h.config.TaskTimeout)
+	}
+	if timeout == 0 || !awaitsAgent(task.Status.State) {
+		delete(task.Metadata, TaskDeadlineMetadataKey)
+		delete(task.Metadata, TaskLastSeenMetadataKey)
+		return
+	}
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]any)
+	}
+	task.Metadata[TaskLastSeenMetadataKey] = now.UTC().Format(time.RFC3339)
+	task.Metadata[TaskDeadlineMetadataKey] = now.Add(timeout).UTC().Format(time.RFC3339)
+}
+
+// taskDeadline returns the deadline of a task, false when it has none
+func taskDeadline(task a2a.Task) (time.Time, bool) {
+	return metadataTime(task, TaskDeadlineMetadataKey)
+}
+
+// metadataTime reads an RFC 3339 time from the task's metadata
+func metadataTime(task a2a.Task, key string) (time.Time, bool) {
+	value, _ := task.Metadata[key].(string)
+	at, err := time.Parse(time.RFC3339, value)
+	return at, err == nil
+}
+
+// timeOut fails a task still waiting on the agent after its deadline,
+// storing the failure and its final event, and returns the task as it now
+// is. Tasks are timed out as they are read, and by maintenance passes with
+// WithTaskSweep for those no one reads.
+func (h *ServerlessA2AHandler) timeOut(ctx context.Context, task a2a.Task) (a2a.Task, error) {
+	deadline, ok := taskDeadline(task)
+	now := h.clock.Now()
+	if !ok || !awaitsAgent(task.Status.State) || !now.After(deadline) {
+		return task, nil
+	}
+
+	previous := task.Status.State
+	task.Status = a2a.TaskStatus{
+		State: a2a.TaskStateFailed,
+		Message: &a2a.Message{
+			Kind:      "message",
+			MessageID: h.ids.NewID(),
+			Role:      a2a.MessageRoleAgent,
+			TaskID:    &task.ID,
+			ContextID: &task.ContextID,
+			Parts:     []a2a.Part{a2a.TextPart{Kind: "text", Text: fmt.Sprintf("task timed out: still %s at its deadline, %s", previous, deadline.Format(time.RFC3339))}},
+		},
+		Timestamp: &now,
+	}
+	delete(task.Metadata, TaskDeadlineMetadataKey)
+	delete(task.Metadata, TaskLastSeenMetadataKey)
+	if err := h.taskStore.SaveTask(ctx, task); errors.Is(err, ErrTaskFinished) {
+		// Finished, by a cancel or the agent, since it was read
+		return h.finishedTask(ctx, task.ID)
+	} else if err != nil {
+		return a2a.Task{}, fmt.Errorf("failed to save timed out task %s: %w", task.ID, err)
+	}
+	MetricsFromContext(ctx).RecordTaskTransition(previous, task.Status.State)
+	LoggerFromContext(ctx).Info("task timed out", LogKeyTaskID, task.ID)
+
+	saved := 1
+	if err := h.saveEvent(ctx, a2a.TaskStatusUpdateEvent{
+		Kind:      "status-update",
+		TaskID:    task.ID,
+		ContextID: task.ContextID,
+		Status:    task.Status,
+		Final:     true,
+	}); err != nil {
+		// Log error but don't fail the request
+		LoggerFromContext(ctx).Warn("failed to save status event", LogKeyTaskID, task.ID, LogKeyError, err)
+		saved = 0
+	}
+	h.recordStats(ctx, previous, task, saved)
+	return task, nil
+}
@@ -0,0 +1,122 @@
+package a2a
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestHandlerTimesOutTasks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	send := func(h *ServerlessA2AHandler, metadata map[string]any) a2a.Task {
+		t.Helper()
+		result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-1", Role: a2a.MessageRoleUser}, Metadata: metadata})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result.(a2a.Task)
+	}
+
+	// The agent's timeout sets the deadline, and the task is kept until then
+	stores := &memoryStores{}
+	sent := send(NewServerlessA2AHandler(ServerlessConfig{TaskTimeout: "15m"}, stores, stores, nil, WithClock(fixedClock{now: now})), nil)
+	if deadline := sent.Metadata[TaskDeadlineMetadataKey]; deadline != "2025-08-01T12:15:00Z" {
+		t.Fatalf("expected a deadline 15 minutes on, got %v", deadline)
+	}
+	before := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now.Add(15 * time.Minute)}))
+	if task, err := before.OnGetTask(ctx, a2a.TaskQueryParams{ID: sent.ID}); err != nil || task.Status.State != a2a.TaskStateWorking {
+		t.Fatalf("expected the task working until its deadline, got %s: %v", task.Status.State, err)
+	}
+
+	// Past it, reading the task fails it with a final event
+	after := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now.Add(16 * time.Minute)}))
+	task, err := after.OnGetTask(ctx, a2a.TaskQueryParams{ID: sent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if task.Status.State != a2a.TaskStateFailed || task.Status.Message == nil || task.Metadata[TaskDeadlineMetadataKey] != nil {
+		t.Errorf("expected the task failed with a message and no deadline, got %+v", task)
+	}
+	if last := stores.tasks[len(stores.tasks)-1]; last.Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected the failed task saved, got %s", last.Status.State)
+	}
+	if len(stores.events) != 1 || !stores.events[0].Final || stores.events[0].Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected one final failed event, got %+v", stores.events)
+	}
+
+	// A call's own timeout overrides the agent's, and a task waiting on the
+	// client has no deadline
+	stores = &memoryStores{}
+	h := NewServerlessA2AHandler(ServerlessConfig{TaskTimeout: "15m"}, stores, stores, nil, WithClock(fixedClock{now: now}))
+	if deadline := send(h, map[string]any{TimeoutMetadataKey: "1d"}).Metadata[TaskDeadlineMetadataKey]; deadline != "2025-08-02T12:00:00Z" {
+		t.Errorf("expected the call's deadline, got %v", deadline)
+	}
+	waiting := a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateInputRequired}, Metadata: map[string]any{}}
+	h.setDeadline(&waiting, a2a.MessageSendParams{}, now)
+	if _, ok := waiting.Metadata[TaskDeadlineMetadataKey]; ok {
+		t.Errorf("expected no deadline while waiting on the client, got %v", waiting.Metadata)
+	}
+}
+
+func TestValidateTaskTimeout(t *testing.T) {
+	for timeout, valid := range map[string]bool{"": true, "90s": true, "1d": true, "0s": false, "-5m": false, "soon": false} {
+		if err := ValidateTaskTimeout(timeout); valid != (err == nil) {
+			t.Errorf("%q: unexpected result %v", timeout, err)
+		}
+	}
+	if err := ValidateServerlessConfig(ServerlessConfig{TaskTimeout: "soon"}); err == nil || !strings.Contains(err.Error(), "task_timeout") {
+		t.Errorf("expected an error at task_timeout, got %v", err)
+	}
+}
+
+// taskIDList lists its task IDs as the running ones
+type taskIDList []a2a.TaskID
+
+func (l taskIDList) RunningTaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return func(yield func(a2a.TaskID, error) bool) {
+		for _, id := range l {
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (l taskIDList) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return l.RunningTaskIDs(ctx)
+}
+
+func TestRunMaintenanceTimesOutTasks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	stores := &memoryStores{}
+	h := NewServerlessA2AHandler(ServerlessConfig{TaskTimeout: "15m"}, stores, stores, nil, WithClock(fixedClock{now: now}))
+	var sent []a2a.TaskID
+	for _, timeout := range []string{"10m", "1h"} {
+		result, err := h.OnSendMessage(ctx, a2a.MessageSendParams{Message: a2a.Message{MessageID: "m-" + timeout, Role: a2a.MessageRoleUser}, Metadata: map[string]any{TimeoutMetadataKey: timeout}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sent = append(sent, result.(a2a.Task).ID)
+	}
+
+	// Only the task past its deadline is failed, with its final event, and
+	// a task gone since it was listed is skipped
+	later := NewServerlessA2AHandler(ServerlessConfig{}, stores, stores, nil, WithClock(fixedClock{now: now.Add(30 * time.Minute)}),
+		WithTaskSweep(append(taskIDList{"gone"}, sent...)))
+	report, err := later.RunMaintenance(ctx)
+	if err != nil || report.TimedOut != 1 {
+		t.Fatalf("expected one task timed out, got %+v: %v", report, err)
+	}
+	if last := stores.tasks[len(stores.tasks)-1]; last.ID != sent[0] || last.Status.State != a2a.TaskStateFailed {
+		t.Errorf("expected the 10 minute task saved failed, got %s %s", last.ID, last.Status.State)
+	}
+	if len(stores.events) != 1 || !stores.events[0].Final || stores.events[0].TaskID != sent[0] {
+		t.Errorf("expected one final event for the timed out task, got %+v", stores.events)
+	}
+}
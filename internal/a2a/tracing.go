@@ -0,0 +1,218 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"net/textproto"
+	"strconv"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies spans created by this module
+const TracerName = "github.com/a2aproject/a2a-serverless"
+
+// Tracing modes selected by A2A_TRACING
+const (
+	TracingModeOff  = ""
+	TracingModeOTel = "otel"
+	TracingModeXRay = "xray"
+)
+
+// TracingConfig configures trace export. The OTLP endpoint, headers and
+// protocol come from the standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Mode        string
+	ServiceName string
+	SampleRatio float64
+}
+
+// LoadTracingConfigFromEnv reads A2A_TRACING, OTEL_SERVICE_NAME and A2A_TRACING_SAMPLE_RATIO
+func LoadTracingConfigFromEnv() (TracingConfig, error) {
+	config := TracingConfig{
+		Mode:        getEnvOrDefault("A2A_TRACING", TracingModeOff),
+		ServiceName: getEnvOrDefault("OTEL_SERVICE_NAME", "a2a-serverless"),
+		SampleRatio: 1,
+	}
+
+	if value := getEnvOrDefault("A2A_TRACING_SAMPLE_RATIO", ""); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return TracingConfig{}, fmt.Errorf("A2A_TRACING_SAMPLE_RATIO must be a number between 0 and 1, got %q", value)
+		}
+		config.SampleRatio = ratio
+	}
+
+	switch config.Mode {
+	case TracingModeOff, TracingModeOTel, TracingModeXRay:
+	default:
+		return TracingConfig{}, fmt.Errorf("unsupported A2A_TRACING mode: %s", config.Mode)
+	}
+
+	return config, nil
+}
+
+// TracingShutdown flushes and stops the tracer provider
+type TracingShutdown func(ctx context.Context) error
+
+// TracingFlush exports buffered spans. Lambda freezes the process between
+// invocations, so spans must be flushed before each invocation returns.
+type TracingFlush func(ctx context.Context) error
+
+// Tracing is the tracer and propagator installed by SetupTracing. The
+// handler starts server spans from it; spans below those are started from
+// the parent span's provider, so nothing reads a process-wide tracing mode.
+// A nil *Tracing traces nothing.
+type Tracing struct {
+	Provider   trace.TracerProvider
+	Propagator propagation.TextMapPropagator
+	Flush      TracingFlush
+	Shutdown   TracingShutdown
+}
+
+// SetupTracing creates the tracing backend selected by config.Mode. Both
+// modes export over OTLP; xray mode generates X-Ray trace IDs and reads the
+// X-Amzn-Trace-Id header, so spans join the trace started by API Gateway and
+// the ADOT collector can forward them to X-Ray. With tracing off it returns
+// a nil *Tracing.
+func SetupTracing(ctx context.Context, config TracingConfig) (*Tracing, error) {
+	var propagator propagation.TextMapPropagator
+	var options []sdktrace.TracerProviderOption
+	switch config.Mode {
+	case TracingModeOTel:
+		propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	case TracingModeXRay:
+		propagator = xray.Propagator{}
+		options = append(options, sdktrace.WithIDGenerator(xray.NewIDGenerator()))
+	default:
+		return nil, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(append(options,
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)...)
+
+	return &Tracing{
+		Provider:   provider,
+		Propagator: propagator,
+		Flush:      provider.ForceFlush,
+		Shutdown:   provider.Shutdown,
+	}, nil
+}
+
+// EndSpanFunc ends a span started by StartSpan, recording err if it is non-nil
+type EndSpanFunc func(err error)
+
+// ExtractTraceContext continues the trace described by incoming HTTP headers.
+// Header names are matched case-insensitively because API Gateway passes them
+// through as the client sent them.
+func (t *Tracing) ExtractTraceContext(ctx context.Context, headers map[string]string) context.Context {
+	if t == nil {
+		return ctx
+	}
+	carrier := make(headerCarrier, len(headers))
+	for key, value := range headers {
+		carrier.Set(key, value)
+	}
+	return t.Propagator.Extract(ctx, carrier)
+}
+
+// InjectTraceContext returns the headers that continue the trace in ctx,
+// for carrying it across a queue or an outbound HTTP call
+func (t *Tracing) InjectTraceContext(ctx context.Context) map[string]string {
+	if t == nil {
+		return nil
+	}
+	carrier := make(headerCarrier)
+	t.Propagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// StartSpan starts a span from this provider, under the remote parent
+// extracted into ctx if any
+func (t *Tracing) StartSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, EndSpanFunc) {
+	if t == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := t.Provider.Tracer(TracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, func(err error) { EndSpan(span, err) }
+}
+
+// StartSpan starts a child of the span in ctx from that span's provider.
+// Without a parent span, e.g. with tracing off, it does nothing.
+func StartSpan(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, EndSpanFunc) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(TracerName)
+	ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, func(err error) { EndSpan(span, err) }
+}
+
+// AnnotateSpan adds attributes to the current span
+func AnnotateSpan(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// RecordSpanError marks the current span as failed
+func RecordSpanError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// headerCarrier is a propagation carrier keyed by canonical header name
+type headerCarrier map[string]string
+
+// Get returns the value of a header
+func (c headerCarrier) Get(key string) string {
+	return c[textproto.CanonicalMIMEHeaderKey(key)]
+}
+
+// Set stores a header value
+func (c headerCarrier) Set(key, value string) {
+	c[textproto.CanonicalMIMEHeaderKey(key)] = value
+}
+
+// Keys lists the header names in the carrier
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// EndSpan records err on span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Span attribute keys for A2A identifiers
+const (
+	SpanAttrTaskID    = attribute.Key("a2a.task_id")
+	SpanAttrContextID = attribute.Key("a2a.context_id")
+)
@@ -0,0 +1,175 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLoadTracingConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		envVars     map[string]string
+		expected    TracingConfig
+		expectError bool
+	}{
+		{
+			name:     "defaults to off",
+			expected: TracingConfig{Mode: TracingModeOff, ServiceName: "a2a-serverless", SampleRatio: 1},
+		},
+		{
+			name:     "otel with sampling",
+			envVars:  map[string]string{"A2A_TRACING": "otel", "OTEL_SERVICE_NAME": "billing-agent", "A2A_TRACING_SAMPLE_RATIO": "0.25"},
+			expected: TracingConfig{Mode: TracingModeOTel, ServiceName: "billing-agent", SampleRatio: 0.25},
+		},
+		{
+			name:     "xray",
+			envVars:  map[string]string{"A2A_TRACING": "xray"},
+			expected: TracingConfig{Mode: TracingModeXRay, ServiceName: "a2a-serverless", SampleRatio: 1},
+		},
+		{
+			name:        "unknown mode",
+			envVars:     map[string]string{"A2A_TRACING": "zipkin"},
+			expectError: true,
+		},
+		{
+			name:        "ratio out of range",
+			envVars:     map[string]string{"A2A_TRACING": "otel", "A2A_TRACING_SAMPLE_RATIO": "2"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestEnv()
+			defer clearTestEnv()
+			for key, value := range tt.envVars {
+				os.Setenv(key, value)
+			}
+
+			config, err := LoadTracingConfigFromEnv()
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestSetupTracing_Off(t *testing.T) {
+	tracing, err := SetupTracing(context.Background(), TracingConfig{Mode: TracingModeOff})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tracing != nil {
+		t.Fatalf("expected no tracing, got %+v", tracing)
+	}
+
+	// A nil *Tracing is safe to use and records nothing
+	ctx := tracing.ExtractTraceContext(context.Background(), map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	ctx, end := tracing.StartSpan(ctx, "tasks/get", trace.SpanKindServer)
+	defer end(nil)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		t.Error("expected no span context with tracing off")
+	}
+}
+
+func TestTracingExtractTraceContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		propagator    propagation.TextMapPropagator
+		headers       map[string]string
+		expectTraceID string
+	}{
+		{
+			// API Gateway keeps the client's header casing
+			name:          "otel traceparent",
+			propagator:    propagation.TraceContext{},
+			headers:       map[string]string{"TraceParent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:          "xray trace header",
+			propagator:    xray.Propagator{},
+			headers:       map[string]string{"x-amzn-trace-id": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"},
+			expectTraceID: "5759e988bd862e3fe1be46a994272793",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tracing := &Tracing{
+				Provider:   sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+				Propagator: tt.propagator,
+			}
+
+			ctx := tracing.ExtractTraceContext(context.Background(), tt.headers)
+			ctx, endServer := tracing.StartSpan(ctx, "tasks/get", trace.SpanKindServer)
+			// Children come from the server span's provider, not a global one
+			_, endChild := StartSpan(ctx, "push_notification enqueue", trace.SpanKindProducer)
+			endChild(nil)
+			endServer(nil)
+
+			spans := recorder.Ended()
+			if len(spans) != 2 {
+				t.Fatalf("expected 2 spans, got %d", len(spans))
+			}
+			for _, span := range spans {
+				if span.SpanContext().TraceID().String() != tt.expectTraceID {
+					t.Errorf("expected %s in trace %s, got %s", span.Name(), tt.expectTraceID, span.SpanContext().TraceID())
+				}
+			}
+			if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+				t.Error("expected enqueue span to be a child of the server span")
+			}
+		})
+	}
+}
+
+func TestStartSpan_NoParent(t *testing.T) {
+	ctx, end := StartSpan(context.Background(), "push_notification enqueue", trace.SpanKindProducer)
+	defer end(nil)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		t.Error("expected no span without a parent span")
+	}
+}
+
+func TestEndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(TracerName)
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	EndSpan(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	EndSpan(failed, errors.New("boom"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 ended spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("expected unset status for successful span, got %v", spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "boom" {
+		t.Errorf("expected error status for failed span, got %v", spans[1].Status())
+	}
+}
@@ -0,0 +1,83 @@
+package a2a
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// TransportConfig names the transports deployed beside the JSON-RPC
+// entrypoint, such as a gRPC service or a REST gateway in front of the same
+// agent. The card's interfaces are derived from it, so they list what is
+// actually deployed rather than fields maintained by hand.
+type TransportConfig struct {
+	// GRPCURL is where the gRPC transport is served, if it is deployed
+	GRPCURL string `json:"grpc_url,omitempty"`
+	// RESTURL is where the HTTP+JSON transport is served, if it is deployed
+	RESTURL string `json:"rest_url,omitempty"`
+}
+
+// configured reports whether any transport beside JSON-RPC is deployed
+func (c TransportConfig) configured() bool {
+	return c.GRPCURL != "" || c.RESTURL != ""
+}
+
+// interfaces lists the configured transports in a stable order
+func (c TransportConfig) interfaces() []a2a.AgentInterface {
+	var interfaces []a2a.AgentInterface
+	if c.GRPCURL != "" {
+		interfaces = append(interfaces, a2a.AgentInterface{Transport: string(a2a.TransportProtocolGRPC), URL: c.GRPCURL})
+	}
+	if c.RESTURL != "" {
+		interfaces = append(interfaces, a2a.AgentInterface{Transport: string(a2a.TransportProtocolHTTPJSON), URL: c.RESTURL})
+	}
+	return interfaces
+}
+
+// LoadTransportConfigFromEnv reads the companion transport URLs
+func LoadTransportConfigFromEnv() TransportConfig {
+	return TransportConfig{
+		GRPCURL: getEnvOrDefault("A2A_GRPC_URL", ""),
+		RESTURL: getEnvOrDefault("A2A_REST_URL", ""),
+	}
+}
+
+// ValidateTransportConfig checks each configured transport URL is absolute
+func ValidateTransportConfig(config TransportConfig) error {
+	var errs ValidationErrors
+	urls := []struct {
+		name  string
+		value string
+	}{
+		{"grpc_url", config.GRPCURL},
+		{"rest_url", config.RESTURL},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			continue
+		}
+		if parsed, err := url.Parse(u.value); err != nil || !parsed.IsAbs() || parsed.Host == "" {
+			errs.Add(u.name, ValidationCodeInvalid, fmt.Sprintf("%q must be an absolute URL such as https://agent.example.com/", u.value))
+		}
+	}
+	return errs.Err()
+}
+
+// WithDeploymentInterfaces publishes the interfaces a deployment serves. The
+// first is the one at the card URL and becomes the preferred transport; the
+// full list, that one included, is published as the additional interfaces
+// when there is more than one, as the A2A spec asks.
+func WithDeploymentInterfaces(features DeploymentFeatures) agentcard.Option {
+	return func(card *a2a.AgentCard) {
+		if len(features.Interfaces) == 0 {
+			return
+		}
+		card.PreferredTransport = a2a.TransportProtocol(features.Interfaces[0].Transport)
+		card.AdditionalInterfaces = nil
+		if len(features.Interfaces) > 1 {
+			card.AdditionalInterfaces = append([]a2a.AgentInterface(nil), features.Interfaces...)
+		}
+	}
+}
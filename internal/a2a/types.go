@@ -3,10 +3,16 @@ package a2a
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	// Import the official A2A SDK types
 	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 )
 
 // ServerlessConfig holds configuration for A2A serverless operations
@@ -15,36 +21,116 @@ type ServerlessConfig struct {
 	AgentCard   a2a.AgentCard           `json:"agent_card"`
 	CloudConfig CloudProviderConfig     `json:"cloud_config"`
 	LogLevel    string                  `json:"log_level"`
+	Logging     LoggingConfig           `json:"logging,omitempty"`
+	Webhooks    WebhookConfig           `json:"webhooks,omitempty"`
+	Transports  TransportConfig         `json:"transports,omitempty"`
+	Secrets     SecretsConfig           `json:"secrets"`
+	Security    SecurityConfig          `json:"security"`
+	// Retention bounds how long the agent's tasks, events and artifacts are kept
+	Retention RetentionConfig `json:"retention,omitempty"`
+	// TaskTimeout fails tasks still submitted or working this long after
+	// their last message, such as 15m or 1d; empty never times them out
+	TaskTimeout string `json:"task_timeout,omitempty"`
+	// Retries are the retry policies for transient executor failures by
+	// skill ID, or RetryAllSkills; they need a retry queue
+	Retries map[string]RetryPolicy `json:"retries,omitempty"`
+	// Priorities are the queue priorities of messages for each skill ID,
+	// unless a message sets its own; others are normal
+	Priorities map[string]TaskPriority `json:"priorities,omitempty"`
+	// OutputSchemas are JSON Schemas by skill ID that the data parts of a
+	// task's artifacts must match for the executor to complete it
+	OutputSchemas map[string]map[string]any `json:"output_schemas,omitempty"`
+	// Localization translates the agent card for callers' Accept-Language
+	Localization LocalizationConfig `json:"localization,omitempty"`
+	// Admin places the admin API, which secrets.admin_api_key turns on
+	Admin AdminConfig `json:"admin,omitempty"`
+}
+
+// SecretsConfig holds credentials that may be set inline or as Secrets Manager ARNs
+type SecretsConfig struct {
+	APIKey            SecretString `json:"api_key,omitempty"`
+	WebhookSigningKey SecretString `json:"webhook_signing_key,omitempty"`
+	// RequestSigningKey verifies signed inbound requests, see security.signature_header
+	RequestSigningKey SecretString `json:"request_signing_key,omitempty"`
+	// ContentEncryptionKey is a base64 AES-256 key; when set, message text and
+	// file content is encrypted before it is stored, see ContentCipher
+	ContentEncryptionKey SecretString `json:"content_encryption_key,omitempty"`
+	// AdminAPIKey authenticates the admin API, see admin; unset, it is off
+	AdminAPIKey SecretString `json:"admin_api_key,omitempty"`
 }
 
 // AWSConfig holds AWS service configuration
 type AWSConfig struct {
-	SQSQueueURL     string `json:"sqs_queue_url"`
-	DynamoDBTable   string `json:"dynamodb_table"`
+	SQSQueueURL   string `json:"sqs_queue_url"`
+	DynamoDBTable string `json:"dynamodb_table"`
+	EventsTable   string `json:"events_table,omitempty"`
+	// PushConfigTable keeps the tasks' push notification configs; push
+	// notifications are only served with it and SQSQueueURL
+	PushConfigTable string `json:"push_config_table,omitempty"`
+	// CompressAbove gzips stored task and event JSON longer than this many
+	// bytes; 0 stores it uncompressed
+	CompressAbove int `json:"compress_above,omitempty"`
+	// AuditTable or AuditFirehoseStream enables the audit log; only a table can be queried
+	AuditTable          string       `json:"audit_table,omitempty"`
+	AuditFirehoseStream string       `json:"audit_firehose_stream,omitempty"`
+	Region              string       `json:"region"`
+	AccessKeyID         SecretString `json:"access_key_id,omitempty"`
+	SecretAccessKey     SecretString `json:"secret_access_key,omitempty"`
+	// GlobalTables tags task and event writes with Region and makes task
+	// writes last-writer-wins, for active-active deployments on DynamoDB
+	// global tables
+	GlobalTables bool `json:"global_tables,omitempty"`
+	// FailoverQueueURLs receive push notifications, in order, while
+	// SQSQueueURL is failing; usually the queues of other regions
+	FailoverQueueURLs []string `json:"failover_queue_urls,omitempty"`
+	// RoleARN is assumed for the DynamoDB and SQS clients, so the tables and
+	// queues can live in another account; ExternalID is passed when the
+	// role's trust policy asks for one
+	RoleARN    string `json:"role_arn,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+// GCPConfig holds GCP service configuration
+type GCPConfig struct {
+	ProjectID       string `json:"project_id"`
+	FirestoreDB     string `json:"firestore_db"`
+	PubSubTopic     string `json:"pubsub_topic"`
 	Region          string `json:"region"`
-	AccessKeyID     string `json:"access_key_id,omitempty"`
-	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	CredentialsPath string `json:"credentials_path,omitempty"`
+}
+
+// AzureConfig holds Azure service configuration
+type AzureConfig struct {
+	Region              string       `json:"region"`
+	CosmosDBEndpoint    string       `json:"cosmosdb_endpoint"`
+	CosmosDBDatabase    string       `json:"cosmosdb_database"`
+	ServiceBusNamespace string       `json:"servicebus_namespace"`
+	ServiceBusQueue     string       `json:"servicebus_queue"`
+	TenantID            string       `json:"tenant_id,omitempty"`
+	ClientID            string       `json:"client_id,omitempty"`
+	ClientSecret        SecretString `json:"client_secret,omitempty"`
 }
 
 // CloudProviderConfig holds configuration for different cloud providers
 type CloudProviderConfig struct {
-	Provider string     `json:"provider"` // "aws", "gcp", "local"
-	AWS      *AWSConfig `json:"aws,omitempty"`
-	// Future: GCP, Azure configs can be added here
+	Provider string       `json:"provider"` // "aws", "gcp", "azure", "local"
+	AWS      *AWSConfig   `json:"aws,omitempty"`
+	GCP      *GCPConfig   `json:"gcp,omitempty"`
+	Azure    *AzureConfig `json:"azure,omitempty"`
 }
 
 // JSONRPCRequest represents a JSON-RPC 2.0 request
 type JSONRPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"` // Always "2.0"
 	Method  string      `json:"method"`  // A2A method name
-	Params  interface{} `json:"params"`  // Method parameters
+	Params  interface{} `json:"params"`  // Method parameters, a json.RawMessage when decoded
 	ID      interface{} `json:"id"`      // Request ID
 }
 
 // JSONRPCResponse represents a JSON-RPC 2.0 response
 type JSONRPCResponse struct {
 	JSONRPC string        `json:"jsonrpc"`           // Always "2.0"
-	Result  interface{}   `json:"result,omitempty"`  // Success result
+	Result  interface{}   `json:"result,omitempty"`  // Success result, a json.RawMessage when decoded
 	Error   *JSONRPCError `json:"error,omitempty"`   // Error details
 	ID      interface{}   `json:"id"`                // Request ID
 }
@@ -56,6 +142,19 @@ type JSONRPCError struct {
 	Data    interface{} `json:"data,omitempty"`    // Additional error data
 }
 
+// DeploymentFeatures describes what the running deployment actually serves,
+// so the agent card can be checked against it
+type DeploymentFeatures struct {
+	Transports        []a2a.TransportProtocol `json:"transports"`
+	Streaming         bool                    `json:"streaming"`
+	PushNotifications bool                    `json:"push_notifications"`
+	// StateTransitionHistory is served when every status update is kept
+	// for tasks/resubscribe to replay
+	StateTransitionHistory bool `json:"state_transition_history"`
+	// Interfaces are where each transport is served, the card URL's first
+	Interfaces []a2a.AgentInterface `json:"interfaces,omitempty"`
+}
+
 // TaskStorage represents serverless-specific task storage metadata
 type TaskStorage struct {
 	TaskID       a2a.TaskID        `json:"task_id"`
@@ -77,52 +176,326 @@ type EventStorage struct {
 	Metadata     map[string]string `json:"metadata,omitempty"`
 }
 
-// ValidateServerlessConfig validates serverless configuration
+// ValidateServerlessConfig validates serverless configuration, reporting every
+// invalid field as a ValidationErrors
 func ValidateServerlessConfig(config ServerlessConfig) error {
+	var errs ValidationErrors
 	if config.AgentID == "" {
-		return fmt.Errorf("agent_id is required")
+		errs.Add("agent_id", ValidationCodeRequired, "is required")
 	}
 	if config.AgentCard.Name == "" {
-		return fmt.Errorf("agent_card.name is required")
+		errs.Add("agent_card.Name", ValidationCodeRequired, "is required")
 	}
 	if config.AgentCard.URL == "" {
-		return fmt.Errorf("agent_card.url is required")
+		errs.Add("agent_card.URL", ValidationCodeRequired, "is required")
+	} else {
+		errs.Merge("", ValidateAgentURL(config.AgentCard.URL))
+	}
+	errs.Merge("", validateProtocolVersion(config.AgentCard.ProtocolVersion))
+	if _, err := ParseLogLevel(config.LogLevel); err != nil {
+		errs.Add("log_level", ValidationCodeInvalid, fmt.Sprintf("'%s' must be one of debug, info, warn or error", config.LogLevel))
+	}
+	errs.Merge("logging", ValidateLoggingConfig(config.Logging))
+	errs.Merge("webhooks", ValidateWebhookConfig(config.Webhooks))
+	errs.Merge("transports", ValidateTransportConfig(config.Transports))
+	errs.Merge("security", ValidateSecurityConfig(config.Security))
+	errs.Merge("retention", ValidateRetentionConfig(config.Retention))
+	errs.Merge("task_timeout", ValidateTaskTimeout(config.TaskTimeout))
+	errs.Merge("retries", ValidateRetryPolicies(config.Retries, config.AgentCard.Skills))
+	errs.Merge("priorities", ValidateSkillPriorities(config.Priorities, config.AgentCard.Skills))
+	errs.Merge("output_schemas", ValidateOutputSchemas(config.OutputSchemas, config.AgentCard.Skills))
+	errs.Merge("localization", ValidateLocalizationConfig(config.Localization, config.AgentCard.Skills))
+	errs.Merge("admin", ValidateAdminConfig(config.Admin))
+	// The key and the header it arrives in only work as a pair
+	if config.Security.APIKeyHeader != "" && config.Secrets.APIKey == "" {
+		errs.Add("secrets.api_key", ValidationCodeRequired, "is required when security.api_key_header is set")
+	}
+	if config.Secrets.APIKey != "" && config.Security.APIKeyHeader == "" {
+		errs.Add("security.api_key_header", ValidationCodeRequired, "is required when secrets.api_key is set")
+	}
+	if config.Security.SignatureHeader != "" && config.Secrets.RequestSigningKey == "" {
+		errs.Add("secrets.request_signing_key", ValidationCodeRequired, "is required when security.signature_header is set")
+	}
+	if config.Secrets.RequestSigningKey != "" && config.Security.SignatureHeader == "" {
+		errs.Add("security.signature_header", ValidationCodeRequired, "is required when secrets.request_signing_key is set")
+	}
+	errs.Merge("", validateContentEncryptionKey(config.Secrets.ContentEncryptionKey))
+	errs.Merge("cloud_config", ValidateCloudProviderConfig(config.CloudConfig))
+	return errs.Err()
+}
+
+// ValidateAgentURL checks the agent URL is an absolute https URL. Plain http is
+// only accepted for loopback hosts so local development still works.
+func ValidateAgentURL(agentURL string) error {
+	invalid := func(message string) error {
+		return FieldError{Path: "agent_card.URL", Code: ValidationCodeInvalid, Message: message}
+	}
+
+	parsed, err := url.Parse(agentURL)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return invalid(fmt.Sprintf("%q must be an absolute URL such as https://agent.example.com/", agentURL))
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			return nil
+		}
+		return invalid(fmt.Sprintf("%q must use https; http is only allowed for localhost", agentURL))
+	default:
+		return invalid(fmt.Sprintf("%q must use https, got scheme %q", agentURL, parsed.Scheme))
+	}
+}
+
+// ValidateAgentCardDeployment checks that the capabilities and transports the
+// agent card advertises are actually served by the deployment
+func ValidateAgentCardDeployment(card a2a.AgentCard, features DeploymentFeatures) error {
+	var errs ValidationErrors
+	if card.Capabilities.Streaming != nil && *card.Capabilities.Streaming && !features.Streaming {
+		errs.Add("agent_card.Capabilities.Streaming", ValidationCodeConflict,
+			"is true but no streaming entrypoint is enabled: disable the streaming capability or deploy a streaming entrypoint")
+	}
+	if card.Capabilities.PushNotifications != nil && *card.Capabilities.PushNotifications && !features.PushNotifications {
+		errs.Add("agent_card.Capabilities.PushNotifications", ValidationCodeConflict,
+			"is true but no notification queue and push config store are configured: disable the push notifications capability or configure both")
+	}
+	if card.Capabilities.StateTransitionHistory != nil && *card.Capabilities.StateTransitionHistory && !features.StateTransitionHistory {
+		errs.Add("agent_card.Capabilities.StateTransitionHistory", ValidationCodeConflict,
+			"is true but no event store keeps status updates: disable the state transition history capability or configure an event store")
+	}
+
+	// An unset preferred transport means JSONRPC per the A2A spec
+	preferred := card.PreferredTransport
+	if preferred == "" {
+		preferred = a2a.TransportProtocolJSONRPC
+	}
+	if !servesTransport(features, preferred) {
+		errs.Add("agent_card.PreferredTransport", ValidationCodeConflict,
+			fmt.Sprintf("is %s but the deployment only serves %v", preferred, features.Transports))
+	}
+	for i, iface := range card.AdditionalInterfaces {
+		if !servesTransport(features, a2a.TransportProtocol(iface.Transport)) {
+			errs.Add(fmt.Sprintf("agent_card.AdditionalInterfaces[%d].Transport", i), ValidationCodeConflict,
+				fmt.Sprintf("is %s at %s but the deployment only serves %v", iface.Transport, iface.URL, features.Transports))
+		}
+	}
+
+	return errs.Err()
+}
+
+// ValidateAgentCard checks a card against the A2A AgentCard schema: the
+// fields it requires, the transports it defines, and security requirements
+// that name a declared scheme. Unlike ValidateServerlessConfig it holds a
+// card to everything a client may rely on, not just what the agent needs
+// to start.
+func ValidateAgentCard(card a2a.AgentCard) error {
+	var errs ValidationErrors
+	required := []struct {
+		path  string
+		value string
+	}{
+		{"agent_card.Name", card.Name},
+		{"agent_card.Description", card.Description},
+		{"agent_card.URL", card.URL},
+		{"agent_card.Version", card.Version},
+		{"agent_card.ProtocolVersion", card.ProtocolVersion},
+	}
+	for _, field := range required {
+		if field.value == "" {
+			errs.Add(field.path, ValidationCodeRequired, "is required")
+		}
+	}
+	if len(card.DefaultInputModes) == 0 {
+		errs.Add("agent_card.DefaultInputModes", ValidationCodeRequired, "must list at least one media type")
+	}
+	if len(card.DefaultOutputModes) == 0 {
+		errs.Add("agent_card.DefaultOutputModes", ValidationCodeRequired, "must list at least one media type")
+	}
+
+	if card.PreferredTransport != "" && !knownTransport(card.PreferredTransport) {
+		errs.Add("agent_card.PreferredTransport", ValidationCodeUnsupported, fmt.Sprintf("'%s' is not a transport the spec defines", card.PreferredTransport))
+	}
+	for i, iface := range card.AdditionalInterfaces {
+		path := fmt.Sprintf("agent_card.AdditionalInterfaces[%d]", i)
+		if iface.URL == "" {
+			errs.Add(path+".URL", ValidationCodeRequired, "is required")
+		}
+		if !knownTransport(a2a.TransportProtocol(iface.Transport)) {
+			errs.Add(path+".Transport", ValidationCodeUnsupported, fmt.Sprintf("'%s' is not a transport the spec defines", iface.Transport))
+		}
+	}
+
+	skillIDs := make(map[string]bool, len(card.Skills))
+	for i, skill := range card.Skills {
+		path := fmt.Sprintf("agent_card.Skills[%d]", i)
+		if skill.ID == "" {
+			errs.Add(path+".ID", ValidationCodeRequired, "is required")
+		} else if skillIDs[skill.ID] {
+			errs.Add(path+".ID", ValidationCodeDuplicate, fmt.Sprintf("'%s' is used by an earlier skill", skill.ID))
+		}
+		skillIDs[skill.ID] = true
+		if skill.Name == "" {
+			errs.Add(path+".Name", ValidationCodeRequired, "is required")
+		}
+		if skill.Description == "" {
+			errs.Add(path+".Description", ValidationCodeRequired, "is required")
+		}
+		if skill.Tags == nil {
+			errs.Add(path+".Tags", ValidationCodeRequired, "is required")
+		}
+	}
+
+	for i, requirement := range card.Security {
+		for _, scheme := range slices.Sorted(maps.Keys(requirement)) {
+			if _, ok := card.SecuritySchemes[scheme]; !ok {
+				errs.Add(fmt.Sprintf("agent_card.Security[%d]", i), ValidationCodeInvalid, fmt.Sprintf("names '%s', which is not in SecuritySchemes", scheme))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// knownTransport reports whether the spec defines transport
+func knownTransport(transport a2a.TransportProtocol) bool {
+	switch transport {
+	case a2a.TransportProtocolJSONRPC, a2a.TransportProtocolGRPC, a2a.TransportProtocolHTTPJSON:
+		return true
+	}
+	return false
+}
+
+// servesTransport checks if a transport is in the deployment's transport list
+func servesTransport(features DeploymentFeatures, transport a2a.TransportProtocol) bool {
+	for _, t := range features.Transports {
+		if t == transport {
+			return true
+		}
 	}
-	return ValidateCloudProviderConfig(config.CloudConfig)
+	return false
 }
 
 // ValidateCloudProviderConfig validates cloud provider configuration
 func ValidateCloudProviderConfig(config CloudProviderConfig) error {
+	var errs ValidationErrors
 	if config.Provider == "" {
-		return fmt.Errorf("provider is required")
+		errs.Add("provider", ValidationCodeRequired, "is required")
+		return errs.Err()
 	}
-	
+
 	switch config.Provider {
 	case "aws":
 		if config.AWS == nil {
-			return fmt.Errorf("aws configuration is required when provider is 'aws'")
+			errs.Add("aws", ValidationCodeRequired, "is required when provider is 'aws'")
+			break
+		}
+		errs.Merge("aws", ValidateAWSConfig(*config.AWS))
+	case "gcp":
+		if config.GCP == nil {
+			errs.Add("gcp", ValidationCodeRequired, "is required when provider is 'gcp'")
+			break
+		}
+		errs.Merge("gcp", ValidateGCPConfig(*config.GCP))
+	case "azure":
+		if config.Azure == nil {
+			errs.Add("azure", ValidationCodeRequired, "is required when provider is 'azure'")
+			break
 		}
-		return ValidateAWSConfig(*config.AWS)
+		errs.Merge("azure", ValidateAzureConfig(*config.Azure))
 	case "local":
 		// Local provider doesn't need additional validation
-		return nil
 	default:
-		return fmt.Errorf("unsupported provider: %s", config.Provider)
+		errs.Add("provider", ValidationCodeUnsupported, fmt.Sprintf("'%s' is not supported", config.Provider))
 	}
+	return errs.Err()
 }
 
 // ValidateAWSConfig validates AWS configuration
 func ValidateAWSConfig(config AWSConfig) error {
+	var errs ValidationErrors
 	if config.Region == "" {
-		return fmt.Errorf("region is required")
-	}
-	if config.SQSQueueURL == "" {
-		return fmt.Errorf("sqs_queue_url is required")
+		errs.Add("region", ValidationCodeRequired, "is required")
 	}
 	if config.DynamoDBTable == "" {
-		return fmt.Errorf("dynamodb_table is required")
+		errs.Add("dynamodb_table", ValidationCodeRequired, "is required")
 	}
-	return nil
+	// A lone key would silently fall back to the execution role
+	if (config.AccessKeyID == "") != (config.SecretAccessKey == "") {
+		errs.Add("secret_access_key", ValidationCodeConflict, "must be set together with access_key_id")
+	}
+	if config.AuditTable != "" && config.AuditFirehoseStream != "" {
+		errs.Add("audit_firehose_stream", ValidationCodeConflict, "cannot be set together with audit_table")
+	}
+	if config.CompressAbove < 0 {
+		errs.Add("compress_above", ValidationCodeInvalid, "must not be negative")
+	}
+	if len(config.FailoverQueueURLs) > 0 && config.SQSQueueURL == "" {
+		errs.Add("failover_queue_urls", ValidationCodeConflict, "requires sqs_queue_url")
+	}
+	seen := map[string]bool{config.SQSQueueURL: true}
+	for i, queueURL := range config.FailoverQueueURLs {
+		path := fmt.Sprintf("failover_queue_urls[%d]", i)
+		if u, err := url.Parse(queueURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			errs.Add(path, ValidationCodeInvalid, "must be an https SQS queue URL")
+		} else if seen[queueURL] {
+			errs.Add(path, ValidationCodeDuplicate, "is already the primary or an earlier failover queue")
+		}
+		seen[queueURL] = true
+	}
+	if config.RoleARN != "" {
+		if roleARN, err := arn.Parse(config.RoleARN); err != nil || roleARN.Service != "iam" || !strings.HasPrefix(roleARN.Resource, "role/") {
+			errs.Add("role_arn", ValidationCodeInvalid, "must be an IAM role ARN")
+		}
+	}
+	if config.ExternalID != "" && config.RoleARN == "" {
+		errs.Add("external_id", ValidationCodeConflict, "requires role_arn")
+	}
+	return errs.Err()
+}
+
+// ValidateGCPConfig validates GCP configuration
+func ValidateGCPConfig(config GCPConfig) error {
+	var errs ValidationErrors
+	if config.ProjectID == "" {
+		errs.Add("project_id", ValidationCodeRequired, "is required")
+	}
+	if config.FirestoreDB == "" {
+		errs.Add("firestore_db", ValidationCodeRequired, "is required")
+	}
+	if config.PubSubTopic == "" {
+		errs.Add("pubsub_topic", ValidationCodeRequired, "is required")
+	}
+	if config.Region == "" {
+		errs.Add("region", ValidationCodeRequired, "is required")
+	}
+	return errs.Err()
+}
+
+// ValidateAzureConfig validates Azure configuration
+func ValidateAzureConfig(config AzureConfig) error {
+	var errs ValidationErrors
+	if config.Region == "" {
+		errs.Add("region", ValidationCodeRequired, "is required")
+	}
+	if config.CosmosDBEndpoint == "" {
+		errs.Add("cosmosdb_endpoint", ValidationCodeRequired, "is required")
+	}
+	if config.CosmosDBDatabase == "" {
+		errs.Add("cosmosdb_database", ValidationCodeRequired, "is required")
+	}
+	if config.ServiceBusNamespace == "" {
+		errs.Add("servicebus_namespace", ValidationCodeRequired, "is required")
+	}
+	if config.ServiceBusQueue == "" {
+		errs.Add("servicebus_queue", ValidationCodeRequired, "is required")
+	}
+	// A client secret is useless without the identity it belongs to
+	if config.ClientSecret != "" && (config.TenantID == "" || config.ClientID == "") {
+		errs.Add("client_secret", ValidationCodeConflict, "requires tenant_id and client_id")
+	}
+	return errs.Err()
 }
 
 // ValidateJSONRPCRequest validates a JSON-RPC request
@@ -136,10 +509,15 @@ func ValidateJSONRPCRequest(req JSONRPCRequest) error {
 	if req.ID == nil {
 		return fmt.Errorf("id is required")
 	}
+	switch req.ID.(type) {
+	case bool, map[string]interface{}, []interface{}:
+		return fmt.Errorf("id must be a string or number")
+	}
 	return nil
 }
 
-// ToJSON serializes any struct to JSON bytes
+// ToJSON serializes any struct to JSON bytes. SecretString fields are
+// written as RedactedValue, so the output is safe to log or return.
 func ToJSON(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
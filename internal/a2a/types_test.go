@@ -47,7 +47,7 @@ func TestValidateServerlessConfig(t *testing.T) {
 	invalidConfig.AgentCard.Name = ""
 	err = ValidateServerlessConfig(invalidConfig)
 	if err == nil {
-		t.Error("Expected error for missing agent_card.name")
+		t.Error("Expected error for missing agent_card.Name")
 	}
 	
 	// Test missing agent card URL
@@ -55,7 +55,7 @@ func TestValidateServerlessConfig(t *testing.T) {
 	invalidConfig.AgentCard.URL = ""
 	err = ValidateServerlessConfig(invalidConfig)
 	if err == nil {
-		t.Error("Expected error for missing agent_card.url")
+		t.Error("Expected error for missing agent_card.URL")
 	}
 }
 
@@ -80,12 +80,12 @@ func TestValidateAWSConfig(t *testing.T) {
 		t.Error("Expected error for missing region")
 	}
 	
-	// Test missing SQS queue URL
-	invalidConfig = validConfig
-	invalidConfig.SQSQueueURL = ""
-	err = ValidateAWSConfig(invalidConfig)
-	if err == nil {
-		t.Error("Expected error for missing sqs_queue_url")
+	// The SQS queue is optional; without it push notifications are not offered
+	withoutQueue := validConfig
+	withoutQueue.SQSQueueURL = ""
+	err = ValidateAWSConfig(withoutQueue)
+	if err != nil {
+		t.Errorf("Expected config without sqs_queue_url to pass validation, got error: %v", err)
 	}
 	
 	// Test missing DynamoDB table
@@ -95,6 +95,53 @@ func TestValidateAWSConfig(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for missing dynamodb_table")
 	}
+
+	// Test access key without its secret
+	invalidConfig = validConfig
+	invalidConfig.AccessKeyID = "AKIAEXAMPLE"
+	err = ValidateAWSConfig(invalidConfig)
+	if err == nil {
+		t.Error("Expected error for access_key_id without secret_access_key")
+	}
+
+	// Test two audit destinations
+	invalidConfig = validConfig
+	invalidConfig.AuditTable = "a2a-audit"
+	invalidConfig.AuditFirehoseStream = "a2a-audit"
+	err = ValidateAWSConfig(invalidConfig)
+	if err == nil {
+		t.Error("Expected error for audit_table with audit_firehose_stream")
+	}
+
+	// Test a negative compression threshold
+	invalidConfig = validConfig
+	invalidConfig.CompressAbove = -1
+	err = ValidateAWSConfig(invalidConfig)
+	if err == nil {
+		t.Error("Expected error for negative compress_above")
+	}
+
+	// Test a role that is not an IAM role ARN
+	invalidConfig = validConfig
+	invalidConfig.RoleARN = "arn:aws:iam::123456789012:user/agent"
+	err = ValidateAWSConfig(invalidConfig)
+	if err == nil {
+		t.Error("Expected error for a role_arn naming a user")
+	}
+
+	// Test an external ID without a role
+	invalidConfig = validConfig
+	invalidConfig.ExternalID = "agent-1"
+	err = ValidateAWSConfig(invalidConfig)
+	if err == nil {
+		t.Error("Expected error for external_id without role_arn")
+	}
+
+	validConfig.RoleARN = "arn:aws:iam::123456789012:role/a2a-data"
+	validConfig.ExternalID = "agent-1"
+	if err := ValidateAWSConfig(validConfig); err != nil {
+		t.Errorf("Expected an assumed role to be valid, got %v", err)
+	}
 }
 
 func TestValidateCloudProviderConfig(t *testing.T) {
@@ -407,4 +454,258 @@ func TestTaskStorageJSONSerialization(t *testing.T) {
 	if deserializedStorage.StorageKey != storage.StorageKey {
 		t.Errorf("Expected StorageKey %s, got %s", storage.StorageKey, deserializedStorage.StorageKey)
 	}
-}
\ No newline at end of file
+}
+func TestValidateAgentURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		expectError bool
+	}{
+		{"https URL", "https://agent.example.com/a2a", false},
+		{"https URL with port", "https://agent.example.com:8443/", false},
+		{"http localhost", "http://localhost:8080/", false},
+		{"http loopback IP", "http://127.0.0.1:8080/", false},
+		{"http public host", "http://agent.example.com/", true},
+		{"relative URL", "/a2a", true},
+		{"missing host", "https:///a2a", true},
+		{"unsupported scheme", "ftp://agent.example.com/", true},
+		{"not a URL", "agent.example.com", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgentURL(tt.url)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error for %q but got none", tt.url)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error for %q: %v", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestValidateAgentCardDeployment(t *testing.T) {
+	jsonrpcOnly := DeploymentFeatures{Transports: []a2a.TransportProtocol{a2a.TransportProtocolJSONRPC}}
+	everything := DeploymentFeatures{
+		Transports:        []a2a.TransportProtocol{a2a.TransportProtocolJSONRPC},
+		Streaming:              true,
+		PushNotifications:      true,
+		StateTransitionHistory: true,
+	}
+	streaming := true
+	push := true
+	history := true
+
+	tests := []struct {
+		name        string
+		card        a2a.AgentCard
+		features    DeploymentFeatures
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:     "no capabilities declared",
+			card:     a2a.AgentCard{},
+			features: jsonrpcOnly,
+		},
+		{
+			name:     "streaming served",
+			card:     a2a.AgentCard{Capabilities: a2a.AgentCapabilities{Streaming: &streaming}},
+			features: everything,
+		},
+		{
+			name:        "streaming declared but not served",
+			card:        a2a.AgentCard{Capabilities: a2a.AgentCapabilities{Streaming: &streaming}},
+			features:    jsonrpcOnly,
+			expectError: true,
+			errorMsg:    "agent_card.Capabilities.Streaming is true",
+		},
+		{
+			name:        "push notifications declared but not served",
+			card:        a2a.AgentCard{Capabilities: a2a.AgentCapabilities{PushNotifications: &push}},
+			features:    jsonrpcOnly,
+			expectError: true,
+			errorMsg:    "agent_card.Capabilities.PushNotifications is true",
+		},
+		{
+			name:        "state transition history declared but not kept",
+			card:        a2a.AgentCard{Capabilities: a2a.AgentCapabilities{StateTransitionHistory: &history}},
+			features:    jsonrpcOnly,
+			expectError: true,
+			errorMsg:    "agent_card.Capabilities.StateTransitionHistory is true",
+		},
+		{
+			name:        "preferred transport not served",
+			card:        a2a.AgentCard{PreferredTransport: a2a.TransportProtocolGRPC},
+			features:    jsonrpcOnly,
+			expectError: true,
+			errorMsg:    "agent_card.PreferredTransport is GRPC",
+		},
+		{
+			name: "additional interface not served",
+			card: a2a.AgentCard{AdditionalInterfaces: []a2a.AgentInterface{
+				{Transport: string(a2a.TransportProtocolHTTPJSON), URL: "https://agent.example.com/rest"},
+			}},
+			features:    jsonrpcOnly,
+			expectError: true,
+			errorMsg:    "agent_card.AdditionalInterfaces[0].Transport is HTTP+JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgentCardDeployment(tt.card, tt.features)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+					return
+				}
+				if !containsString(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error message to contain '%s', got '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateAgentCard(t *testing.T) {
+	valid := func() a2a.AgentCard {
+		return a2a.AgentCard{
+			Name:               "Agent",
+			Description:        "Answers questions",
+			URL:                "https://agent.example.com",
+			Version:            "1.0.0",
+			ProtocolVersion:    "0.3",
+			DefaultInputModes:  []string{"text/plain"},
+			DefaultOutputModes: []string{"text/plain"},
+			Skills:             []a2a.AgentSkill{{ID: "qa", Name: "Q&A", Description: "Answers questions", Tags: []string{}}},
+			SecuritySchemes:    map[string]any{"apiKey": map[string]any{"type": "apiKey"}},
+			Security:           []map[string][]string{{"apiKey": {}}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*a2a.AgentCard)
+		expectErr []string
+	}{
+		{name: "valid", modify: func(*a2a.AgentCard) {}},
+		{
+			name:      "required fields missing",
+			modify:    func(card *a2a.AgentCard) { card.Description, card.ProtocolVersion, card.DefaultOutputModes = "", "", nil },
+			expectErr: []string{"agent_card.Description is required", "agent_card.ProtocolVersion is required", "agent_card.DefaultOutputModes must list"},
+		},
+		{
+			name:      "unknown transport",
+			modify:    func(card *a2a.AgentCard) { card.PreferredTransport = "SOAP" },
+			expectErr: []string{"agent_card.PreferredTransport 'SOAP' is not a transport"},
+		},
+		{
+			name: "incomplete skills",
+			modify: func(card *a2a.AgentCard) {
+				card.Skills = append(card.Skills, a2a.AgentSkill{ID: "qa"})
+			},
+			expectErr: []string{"agent_card.Skills[1].ID 'qa' is used", "agent_card.Skills[1].Name is required", "agent_card.Skills[1].Tags is required"},
+		},
+		{
+			name:      "undeclared security scheme",
+			modify:    func(card *a2a.AgentCard) { card.Security = append(card.Security, map[string][]string{"oauth": {}}) },
+			expectErr: []string{"agent_card.Security[1] names 'oauth'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := valid()
+			tt.modify(&card)
+			err := ValidateAgentCard(card)
+			if len(tt.expectErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expect := range tt.expectErr {
+				if !containsString(err.Error(), expect) {
+					t.Errorf("expected error to contain %q, got %q", expect, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAzureConfig(t *testing.T) {
+	valid := AzureConfig{
+		Region:              "eastus",
+		CosmosDBEndpoint:    "https://test.documents.azure.com:443/",
+		CosmosDBDatabase:    "a2a",
+		ServiceBusNamespace: "test-ns",
+		ServiceBusQueue:     "a2a-events",
+	}
+	if err := ValidateAzureConfig(valid); err != nil {
+		t.Errorf("Expected valid Azure config to pass validation, got error: %v", err)
+	}
+
+	invalid := valid
+	invalid.CosmosDBEndpoint = ""
+	if err := ValidateAzureConfig(invalid); err == nil || err.Error() != "cosmosdb_endpoint is required" {
+		t.Errorf("Expected error for missing cosmosdb_endpoint, got %v", err)
+	}
+
+	invalid = valid
+	invalid.ServiceBusQueue = ""
+	if err := ValidateAzureConfig(invalid); err == nil {
+		t.Error("Expected error for missing servicebus_queue")
+	}
+
+	// Client secret needs the identity it belongs to
+	invalid = valid
+	invalid.ClientSecret = "shh"
+	if err := ValidateAzureConfig(invalid); err == nil {
+		t.Error("Expected error for client_secret without tenant_id and client_id")
+	}
+	invalid.TenantID = "tenant"
+	invalid.ClientID = "client"
+	if err := ValidateAzureConfig(invalid); err != nil {
+		t.Errorf("Expected service principal config to pass validation, got error: %v", err)
+	}
+}
+
+func TestValidateCloudProviderConfig_GCPAndAzure(t *testing.T) {
+	gcpConfig := CloudProviderConfig{
+		Provider: "gcp",
+		GCP: &GCPConfig{
+			ProjectID:   "test-project",
+			FirestoreDB: "test-db",
+			PubSubTopic: "test-topic",
+			Region:      "us-central1",
+		},
+	}
+	if err := ValidateCloudProviderConfig(gcpConfig); err != nil {
+		t.Errorf("Expected valid GCP provider config to pass validation, got error: %v", err)
+	}
+
+	if err := ValidateCloudProviderConfig(CloudProviderConfig{Provider: "gcp"}); err == nil {
+		t.Error("Expected error for GCP provider without GCP config")
+	}
+	if err := ValidateCloudProviderConfig(CloudProviderConfig{Provider: "azure"}); err == nil {
+		t.Error("Expected error for Azure provider without Azure config")
+	}
+
+	// JSON tags round-trip through the same keys as the AWS section
+	var decoded CloudProviderConfig
+	err := FromJSON([]byte(`{"provider": "azure", "azure": {"region": "eastus", "cosmosdb_endpoint": "https://x", "cosmosdb_database": "db", "servicebus_namespace": "ns", "servicebus_queue": "q"}}`), &decoded)
+	if err != nil {
+		t.Fatalf("Failed to parse Azure config JSON: %v", err)
+	}
+	if err := ValidateCloudProviderConfig(decoded); err != nil {
+		t.Errorf("Expected decoded Azure config to pass validation, got error: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package a2a
+
+import (
+	"errors"
+	"strings"
+)
+
+// ValidationCode is a machine-readable reason a config field is invalid
+type ValidationCode string
+
+const (
+	ValidationCodeRequired    ValidationCode = "required"
+	ValidationCodeInvalid     ValidationCode = "invalid"
+	ValidationCodeUnsupported ValidationCode = "unsupported"
+	ValidationCodeDuplicate   ValidationCode = "duplicate"
+	ValidationCodeConflict    ValidationCode = "conflict"
+)
+
+// FieldError describes one invalid config field
+type FieldError struct {
+	Path    string         `json:"path"`    // e.g. cloud_config.aws.sqs_queue_url
+	Code    ValidationCode `json:"code"`    // machine-readable reason
+	Message string         `json:"message"` // human-readable detail, read after the path
+}
+
+// Error implements the error interface for FieldError
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + " " + e.Message
+}
+
+// ValidationErrors aggregates every invalid field found in one validation pass
+// so deployment tooling can report all problems at once
+type ValidationErrors []FieldError
+
+// Error implements the error interface for ValidationErrors
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records an invalid field
+func (e *ValidationErrors) Add(path string, code ValidationCode, message string) {
+	*e = append(*e, FieldError{Path: path, Code: code, Message: message})
+}
+
+// Merge records the errors from a nested validator under a path prefix
+func (e *ValidationErrors) Merge(prefix string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested ValidationErrors
+	var single FieldError
+	switch {
+	case errors.As(err, &nested):
+	case errors.As(err, &single):
+		nested = ValidationErrors{single}
+	default:
+		e.Add(prefix, ValidationCodeInvalid, err.Error())
+		return
+	}
+
+	for _, fieldErr := range nested {
+		fieldErr.Path = joinFieldPath(prefix, fieldErr.Path)
+		*e = append(*e, fieldErr)
+	}
+}
+
+// Err returns nil when no errors were recorded, so callers never see a typed nil
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// joinFieldPath joins config path segments, keeping index suffixes attached
+func joinFieldPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
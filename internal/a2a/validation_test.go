@@ -0,0 +1,92 @@
+package a2a
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestValidateServerlessConfig_ReportsEveryField(t *testing.T) {
+	config := ServerlessConfig{
+		CloudConfig: CloudProviderConfig{
+			Provider: "aws",
+			AWS:      &AWSConfig{Region: "us-east-1"},
+		},
+		Security: SecurityConfig{OIDCMetadataURL: "http://auth.example.com"},
+		LogLevel: "verbose",
+	}
+
+	err := ValidateServerlessConfig(config)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %T: %v", err, err)
+	}
+
+	expected := []FieldError{
+		{Path: "agent_id", Code: ValidationCodeRequired},
+		{Path: "agent_card.Name", Code: ValidationCodeRequired},
+		{Path: "agent_card.URL", Code: ValidationCodeRequired},
+		{Path: "log_level", Code: ValidationCodeInvalid},
+		{Path: "security.oidc_metadata_url", Code: ValidationCodeInvalid},
+		{Path: "cloud_config.aws.dynamodb_table", Code: ValidationCodeRequired},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d field errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, want := range expected {
+		if errs[i].Path != want.Path || errs[i].Code != want.Code {
+			t.Errorf("field error %d: expected %s [%s], got %s [%s]", i, want.Path, want.Code, errs[i].Path, errs[i].Code)
+		}
+	}
+}
+
+func TestValidationErrors_SurviveWrapping(t *testing.T) {
+	clearTestEnv()
+	defer clearTestEnv()
+
+	t.Setenv("A2A_AGENT_ID", "test-agent")
+	t.Setenv("A2A_AGENT_NAME", "Test Agent")
+	t.Setenv("A2A_AGENT_URL", "ftp://agent.example.com")
+	t.Setenv("CLOUD_PROVIDER", "local")
+
+	_, err := NewConfigLoader().LoadServerlessConfig()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected wrapped ValidationErrors, got %v", err)
+	}
+	if len(errs) != 1 || errs[0].Path != "agent_card.URL" || errs[0].Code != ValidationCodeInvalid {
+		t.Errorf("expected one invalid agent_card.URL error, got %v", errs)
+	}
+}
+
+func TestValidationErrors_Merge(t *testing.T) {
+	var errs ValidationErrors
+	errs.Merge("ignored", nil)
+	errs.Merge("skills", ValidationErrors{{Path: "[1].id", Code: ValidationCodeDuplicate, Message: "'a' is duplicated"}})
+	errs.Merge("agent_card", FieldError{Path: "URL", Code: ValidationCodeInvalid, Message: "must use https"})
+	errs.Merge("cloud_config", fmt.Errorf("boom"))
+
+	expected := []string{"skills[1].id", "agent_card.URL", "cloud_config"}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d field errors, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, path := range expected {
+		if errs[i].Path != path {
+			t.Errorf("field error %d: expected path %s, got %s", i, path, errs[i].Path)
+		}
+	}
+	if errs[2].Code != ValidationCodeInvalid || errs[2].Message != "boom" {
+		t.Errorf("expected plain error to be recorded as invalid, got %+v", errs[2])
+	}
+
+	if got := errs.Error(); got != "skills[1].id 'a' is duplicated; agent_card.URL must use https; cloud_config boom" {
+		t.Errorf("unexpected joined message: %s", got)
+	}
+}
+
+func TestValidationErrors_ErrEmpty(t *testing.T) {
+	var errs ValidationErrors
+	if err := errs.Err(); err != nil {
+		t.Errorf("expected nil error for empty ValidationErrors, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package a2a
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProtocolVersionHeader names the A2A protocol version a client speaks. A
+// request without it is taken to speak the version on the agent card.
+const ProtocolVersionHeader = "A2A-Version"
+
+// DefaultProtocolVersion is served when the agent card names no version
+const DefaultProtocolVersion = "0.3"
+
+// ErrVersionNotSupported is returned for a protocol version the agent does not serve
+var ErrVersionNotSupported = errors.New("protocol version not supported")
+
+// NormalizeProtocolVersion reduces a version such as "1", "0.3" or "0.3.0"
+// to its major and minor number, "1.0" or "0.3". Patch releases do not
+// change the wire format, so they are served alike.
+func NormalizeProtocolVersion(version string) (string, error) {
+	fields := strings.Split(strings.TrimSpace(version), ".")
+	if len(fields) > 3 {
+		return "", fmt.Errorf("%q is not a major.minor.patch version", version)
+	}
+	numbers := []int{0, 0}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("%q is not a major.minor.patch version", version)
+		}
+		if i < 2 {
+			numbers[i] = n
+		}
+	}
+	return fmt.Sprintf("%d.%d", numbers[0], numbers[1]), nil
+}
+
+// RequestedProtocolVersion returns the version named by the
+// ProtocolVersionHeader, or "" when the request names none
+func RequestedProtocolVersion(headers map[string]string) string {
+	return strings.TrimSpace(HeaderValue(headers, ProtocolVersionHeader))
+}
+
+// validateProtocolVersion checks the version an agent card advertises
+func validateProtocolVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	if _, err := NormalizeProtocolVersion(version); err != nil {
+		return FieldError{Path: "agent_card.ProtocolVersion", Code: ValidationCodeInvalid, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package a2a
+
+import (
+	"testing"
+)
+
+func TestNormalizeProtocolVersion(t *testing.T) {
+	tests := []struct {
+		version     string
+		expected    string
+		expectError bool
+	}{
+		{version: "0.3", expected: "0.3"},
+		{version: "0.3.0", expected: "0.3"},
+		{version: "1", expected: "1.0"},
+		{version: "10.2.7", expected: "10.2"},
+		{version: "", expectError: true},
+		{version: "v1", expectError: true},
+		{version: "1.0.0.1", expectError: true},
+		{version: "1.-1", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := NormalizeProtocolVersion(tt.version)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("expected %q, got %q (%v)", tt.expected, got, err)
+			}
+		})
+	}
+}
+
+func TestRequestedProtocolVersion(t *testing.T) {
+	if got := RequestedProtocolVersion(map[string]string{"a2a-version": " 0.3 "}); got != "0.3" {
+		t.Errorf("expected the header to be found case-insensitively, got %q", got)
+	}
+	if got := RequestedProtocolVersion(map[string]string{}); got != "" {
+		t.Errorf("expected no version, got %q", got)
+	}
+	if err := validateProtocolVersion("one"); err == nil {
+		t.Error("expected an invalid card version to be rejected")
+	}
+}
@@ -0,0 +1,42 @@
+package a2a
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// InitializationTypeProvisioned is the AWS_LAMBDA_INITIALIZATION_TYPE of an
+// environment Lambda initializes ahead of traffic for provisioned concurrency
+const InitializationTypeProvisioned = "provisioned-concurrency"
+
+// ProvisionedConcurrency reports whether this environment was initialized
+// for provisioned concurrency. Its init runs before any request arrives, so
+// work done there is off every request's path.
+func ProvisionedConcurrency() bool {
+	return os.Getenv("AWS_LAMBDA_INITIALIZATION_TYPE") == InitializationTypeProvisioned
+}
+
+// IsWarmupEvent reports whether a Lambda payload is a warm-up ping rather
+// than an API Gateway request: an EventBridge scheduled event, a
+// serverless-plugin-warmup ping, or a custom {"warmup": true} payload. A
+// ping needs no storage, so the entrypoint answers it before reaching any.
+func IsWarmupEvent(payload []byte) bool {
+	var event struct {
+		Source     string `json:"source"`
+		DetailType string `json:"detail-type"`
+		Warmup     bool   `json:"warmup"`
+		HTTPMethod string `json:"httpMethod"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil || event.HTTPMethod != "" {
+		return false
+	}
+	switch {
+	case event.Warmup:
+		return true
+	case event.Source == "aws.events" && event.DetailType == "Scheduled Event":
+		return true
+	case event.Source == "serverless-plugin-warmup":
+		return true
+	}
+	return false
+}
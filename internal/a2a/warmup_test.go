@@ -0,0 +1,38 @@
+package a2a
+
+import "testing"
+
+func TestIsWarmupEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		expect  bool
+	}{
+		{name: "scheduled event", payload: `{"version":"0","source":"aws.events","detail-type":"Scheduled Event","detail":{}}`, expect: true},
+		{name: "warmup plugin", payload: `{"source":"serverless-plugin-warmup"}`, expect: true},
+		{name: "custom ping", payload: `{"warmup":true}`, expect: true},
+		{name: "other EventBridge event", payload: `{"source":"aws.events","detail-type":"EC2 Instance State-change Notification"}`},
+		{name: "API Gateway request", payload: `{"httpMethod":"POST","path":"/","body":"{\"warmup\":true}"}`},
+		{name: "API Gateway request with a warmup field", payload: `{"httpMethod":"GET","path":"/","warmup":true}`},
+		{name: "not JSON", payload: `ping`},
+		{name: "empty object", payload: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWarmupEvent([]byte(tt.payload)); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestProvisionedConcurrency(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_INITIALIZATION_TYPE", "on-demand")
+	if ProvisionedConcurrency() {
+		t.Error("expected on-demand init not to count as provisioned")
+	}
+	t.Setenv("AWS_LAMBDA_INITIALIZATION_TYPE", InitializationTypeProvisioned)
+	if !ProvisionedConcurrency() {
+		t.Error("expected provisioned concurrency to be detected")
+	}
+}
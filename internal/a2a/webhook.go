@@ -0,0 +1,195 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CorrelationIDHeader carries the ID of the request that caused a
+// notification, on the queued message and on the webhook call
+const CorrelationIDHeader = "X-A2A-Correlation-Id"
+
+// NotificationTokenHeader carries the token the client set on its push
+// config, so the receiver can tell the notification belongs to it
+const NotificationTokenHeader = "X-A2A-Notification-Token"
+
+// LogKeyCorrelationID is the log field for a correlation ID read off a queue message
+const LogKeyCorrelationID = "correlation_id"
+
+// webhookTimeout bounds one webhook call, so a slow receiver cannot hold the
+// worker until the Lambda timeout
+const webhookTimeout = 10 * time.Second
+
+// PushNotification is the message AWSSQSPushNotifier queues for delivery
+type PushNotification struct {
+	PushConfig a2a.PushConfig  `json:"push_config"`
+	Event      json.RawMessage `json:"event"`
+}
+
+// CorrelationIDFromContext returns the ID tying async work to the request
+// that started it: a correlation ID carried in from a queue message, or
+// else the request ID
+func CorrelationIDFromContext(ctx context.Context) string {
+	fields := LogFieldsFromContext(ctx)
+	if id := fields[LogKeyCorrelationID]; id != "" {
+		return id
+	}
+	return fields[LogKeyRequestID]
+}
+
+// NotificationHeaders returns the headers a notification carries through
+// the queue to its webhook: the trace context, the correlation ID and, with
+// a signing key, the HMAC of body
+func NotificationHeaders(ctx context.Context, tracing *Tracing, signingKey string, body []byte) map[string]string {
+	headers := tracing.InjectTraceContext(ctx)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		headers[CorrelationIDHeader] = id
+	}
+	if signingKey != "" {
+		headers[SignatureHeader] = SignPayload(signingKey, body)
+	}
+	return headers
+}
+
+// WebhookDeliverer posts queued notifications to the webhook URL of their
+// push config
+type WebhookDeliverer struct {
+	client  *http.Client
+	tracing *Tracing
+	// deliveries records each attempt when set
+	deliveries DeliveryLog
+}
+
+// NewWebhookDeliverer creates a deliverer. A nil client uses
+// NewWebhookClient with the default WebhookConfig; a nil tracing propagates
+// no trace context.
+func NewWebhookDeliverer(client *http.Client, tracing *Tracing) *WebhookDeliverer {
+	if client == nil {
+		client = NewWebhookClient(WebhookConfig{})
+	}
+	return &WebhookDeliverer{client: client, tracing: tracing}
+}
+
+// WithDeliveryLog records every delivery attempt in log, successful or not.
+// A failure to record is logged and does not fail the delivery.
+func (d *WebhookDeliverer) WithDeliveryLog(log DeliveryLog) *WebhookDeliverer {
+	d.deliveries = log
+	return d
+}
+
+// Deliver posts a queued notification body to its webhook. headers are the
+// message's attributes as set by NotificationHeaders: the delivery span
+// continues their trace, and the signature, correlation ID and notification
+// ID are forwarded.
+// The body is sent unchanged so the signature still verifies.
+func (d *WebhookDeliverer) Deliver(ctx context.Context, body string, headers map[string]string) (err error) {
+	var notification PushNotification
+	if err := json.Unmarshal([]byte(body), &notification); err != nil {
+		return fmt.Errorf("invalid notification message: %w", err)
+	}
+	if notification.PushConfig.URL == "" {
+		return fmt.Errorf("notification has no webhook URL")
+	}
+	start := time.Now()
+	var statusCode int
+	if d.deliveries != nil {
+		defer func() { d.recordDelivery(ctx, notification, start, statusCode, err) }()
+	}
+
+	ctx = d.tracing.ExtractTraceContext(ctx, headers)
+	correlationID := HeaderValue(headers, CorrelationIDHeader)
+	if correlationID != "" {
+		ctx = WithLogAttrs(ctx, LogKeyCorrelationID, correlationID)
+	}
+	ctx, endSpan := d.tracing.StartSpan(ctx, "push_notification deliver", trace.SpanKindClient,
+		attribute.String("http.request.method", http.MethodPost),
+	)
+	defer func() { endSpan(err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.PushConfig.URL, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// The webhook call is a child of the delivery span, not of the enqueue
+	for name, value := range d.tracing.InjectTraceContext(ctx) {
+		req.Header.Set(name, value)
+	}
+	if correlationID != "" {
+		req.Header.Set(CorrelationIDHeader, correlationID)
+	}
+	if signature := HeaderValue(headers, SignatureHeader); signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	if notificationID := HeaderValue(headers, NotificationIDHeader); notificationID != "" {
+		req.Header.Set(NotificationIDHeader, notificationID)
+	}
+	setWebhookAuth(req, notification.PushConfig)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	statusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// recordDelivery records the outcome of one delivery attempt that started at start
+func (d *WebhookDeliverer) recordDelivery(ctx context.Context, notification PushNotification, start time.Time, statusCode int, err error) {
+	attempt := DeliveryAttempt{
+		TaskID:      notificationTaskID(notification.Event),
+		MessageID:   LogFieldsFromContext(ctx)[LogKeyMessageID],
+		URL:         webhookLogURL(notification.PushConfig.URL),
+		AttemptedAt: start.UTC(),
+		Attempt:     deliveryAttemptFromContext(ctx),
+		StatusCode:  statusCode,
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+	if notification.PushConfig.ID != nil {
+		attempt.ConfigID = *notification.PushConfig.ID
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		// Client errors quote the URL the webhook was called at
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			attempt.Error = strings.ReplaceAll(attempt.Error, urlErr.URL, attempt.URL)
+		}
+	}
+	if attempt.TaskID == "" {
+		return
+	}
+	if recordErr := d.deliveries.RecordDelivery(ctx, attempt); recordErr != nil {
+		LoggerFromContext(ctx).Warn("failed to record delivery attempt", LogKeyError, recordErr)
+	}
+}
+
+// setWebhookAuth adds the credentials the client asked notifications to carry
+func setWebhookAuth(req *http.Request, config a2a.PushConfig) {
+	if config.Token != nil && *config.Token != "" {
+		req.Header.Set(NotificationTokenHeader, *config.Token)
+	}
+	if config.Auth != nil && config.Auth.Credentials != nil && slices.ContainsFunc(config.Auth.Schemes, func(scheme string) bool {
+		return strings.EqualFold(scheme, "Bearer")
+	}) {
+		req.Header.Set("Authorization", "Bearer "+*config.Auth.Credentials)
+	}
+}
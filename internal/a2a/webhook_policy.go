@@ -0,0 +1,263 @@
+package a2a
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrInvalidWebhookURL is returned for a push config URL the agent will not
+// call. It maps to JSON-RPC invalid params.
+var ErrInvalidWebhookURL = errors.New("invalid webhook URL")
+
+// DefaultWebhookMaxRedirects is how many redirects a webhook call follows
+// unless A2A_WEBHOOK_MAX_REDIRECTS says otherwise
+const DefaultWebhookMaxRedirects = 3
+
+// webhookMaxConnsPerHost caps the connections to one webhook receiver
+const webhookMaxConnsPerHost = 16
+
+// WebhookConfig restricts where push notifications may be sent, so a client
+// cannot use the agent to reach hosts only the agent can see. Webhooks must
+// use https and may never resolve to a private, loopback, link-local or
+// cloud metadata address, whatever the host lists say.
+type WebhookConfig struct {
+	// AllowedHosts, when set, are the only hosts webhooks may use. An entry
+	// of the form *.example.com matches any subdomain of example.com.
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+	// DeniedHosts are hosts webhooks may never use, matched as AllowedHosts
+	DeniedHosts []string `json:"denied_hosts,omitempty"`
+	// MaxRedirects caps the redirects one webhook call follows. Unset uses
+	// DefaultWebhookMaxRedirects; a negative value follows none.
+	MaxRedirects int `json:"max_redirects,omitempty"`
+	// ProxyURL, when set, is an egress proxy every webhook call goes
+	// through. Each URL is still checked, but the proxy resolves the names,
+	// so it must refuse internal addresses itself.
+	ProxyURL string `json:"proxy_url,omitempty"`
+}
+
+// blockedWebhookHosts name internal services by host rather than address
+var blockedWebhookHosts = []string{"localhost", "*.localhost", "metadata.google.internal"}
+
+// blockedWebhookPrefixes are the address ranges a webhook may not reach,
+// beyond the private, loopback and link-local ranges netip reports. The
+// link-local range covers the EC2 metadata address 169.254.169.254, and the
+// private IPv6 range covers its IPv6 address fd00:ec2::254.
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which can embed any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("2002::/16"),      // 6to4, which can embed any IPv4 address
+}
+
+// LoadWebhookConfigFromEnv reads A2A_WEBHOOK_ALLOWED_HOSTS and
+// A2A_WEBHOOK_DENIED_HOSTS (comma-separated) and A2A_WEBHOOK_MAX_REDIRECTS
+func LoadWebhookConfigFromEnv() (WebhookConfig, error) {
+	config := WebhookConfig{
+		AllowedHosts: splitList(getEnvOrDefault("A2A_WEBHOOK_ALLOWED_HOSTS", "")),
+		DeniedHosts:  splitList(getEnvOrDefault("A2A_WEBHOOK_DENIED_HOSTS", "")),
+		ProxyURL:     getEnvOrDefault("A2A_WEBHOOK_PROXY_URL", ""),
+	}
+	if value := getEnvOrDefault("A2A_WEBHOOK_MAX_REDIRECTS", ""); value != "" {
+		redirects, err := strconv.Atoi(value)
+		if err != nil {
+			return WebhookConfig{}, fmt.Errorf("A2A_WEBHOOK_MAX_REDIRECTS must be a whole number, got %q", value)
+		}
+		// 0 means unset in the config, so "none" is stored as negative
+		if redirects == 0 {
+			redirects = -1
+		}
+		config.MaxRedirects = redirects
+	}
+	return config, nil
+}
+
+// ValidateWebhookConfig validates webhook configuration
+func ValidateWebhookConfig(config WebhookConfig) error {
+	var errs ValidationErrors
+	lists := []struct {
+		name  string
+		hosts []string
+	}{
+		{"allowed_hosts", config.AllowedHosts},
+		{"denied_hosts", config.DeniedHosts},
+	}
+	for _, list := range lists {
+		for i, host := range list.hosts {
+			if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(host), "*.")) == "" {
+				errs.Add(fmt.Sprintf("%s[%d]", list.name, i), ValidationCodeRequired, "must not be empty")
+			}
+		}
+	}
+	if config.MaxRedirects > 10 {
+		errs.Add("max_redirects", ValidationCodeInvalid, fmt.Sprintf("%d must be at most 10", config.MaxRedirects))
+	}
+	if config.ProxyURL != "" {
+		if proxy, err := url.Parse(config.ProxyURL); err != nil || (proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+			errs.Add("proxy_url", ValidationCodeInvalid, fmt.Sprintf("%q must be an http or https URL such as http://proxy.internal:3128", config.ProxyURL))
+		}
+	}
+	return errs.Err()
+}
+
+// ValidateURL checks a push config URL before it is accepted: it must be an
+// absolute https URL whose host the config allows. A host given as an
+// address must be public; names are resolved and checked again when a
+// notification is delivered, since DNS can change in between.
+func (c WebhookConfig) ValidateURL(webhookURL string) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidWebhookURL, fmt.Sprintf(format, args...))
+	}
+
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || !parsed.IsAbs() || parsed.Hostname() == "" {
+		return invalid("%q must be an absolute URL", webhookURL)
+	}
+	if parsed.Scheme != "https" {
+		return invalid("%q must use https", webhookURL)
+	}
+	if parsed.User != nil {
+		return invalid("%q must not carry credentials; set them in the push config authentication", webhookURL)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if isBlockedWebhookAddr(addr) {
+			return invalid("%s is not a public address", host)
+		}
+	} else if matchesHost(blockedWebhookHosts, host) {
+		return invalid("%s is an internal host", host)
+	}
+	if matchesHost(c.DeniedHosts, host) {
+		return invalid("%s is not allowed", host)
+	}
+	if len(c.AllowedHosts) > 0 && !matchesHost(c.AllowedHosts, host) {
+		return invalid("%s is not an allowed webhook host", host)
+	}
+	return nil
+}
+
+// maxRedirects resolves the configured redirect cap
+func (c WebhookConfig) maxRedirects() int {
+	switch {
+	case c.MaxRedirects < 0:
+		return 0
+	case c.MaxRedirects == 0:
+		return DefaultWebhookMaxRedirects
+	default:
+		return c.MaxRedirects
+	}
+}
+
+// NewWebhookClient returns the HTTP client webhooks are called with. Every
+// request, including each redirect, is checked with config.ValidateURL, and
+// connections are refused to any address ValidateURL would reject. Checking
+// the address actually dialed, after DNS resolution, means a name that
+// resolves to an internal address cannot slip through.
+//
+// The client is meant to be created once and shared: its pool keeps
+// connections to each receiver open between notifications, and caps them
+// so a burst cannot exhaust the function's sockets.
+func NewWebhookClient(config WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout, KeepAlive: 30 * time.Second, Control: dialPublicOnly}
+	transport := &http.Transport{
+		// Without a configured proxy the environment's is ignored: it would
+		// be dialed instead of the webhook, defeating the check
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   webhookTimeout,
+		ResponseHeaderTimeout: webhookTimeout,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   webhookMaxConnsPerHost,
+		MaxConnsPerHost:       webhookMaxConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	// ValidateWebhookConfig has checked the URL. Every connection is to the
+	// proxy, which is usually internal, so the address check is left to it.
+	if proxy, err := url.Parse(config.ProxyURL); config.ProxyURL != "" && err == nil {
+		transport.Proxy = http.ProxyURL(proxy)
+		dialer.Control = nil
+	}
+	maxRedirects := config.maxRedirects()
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: &webhookTransport{config: config, next: transport},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// webhookTransport validates the URL of every request it sends
+type webhookTransport struct {
+	config WebhookConfig
+	next   http.RoundTripper
+}
+
+func (t *webhookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.config.ValidateURL(req.URL.String()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// dialPublicOnly refuses connections to non-public addresses. It runs after
+// resolution, once per address tried.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: cannot check address %q", ErrInvalidWebhookURL, address)
+	}
+	if isBlockedWebhookAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s is not a public address", ErrInvalidWebhookURL, addrPort.Addr())
+	}
+	return nil
+}
+
+// isBlockedWebhookAddr reports addresses that are not publicly routable
+func isBlockedWebhookAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+	return slices.ContainsFunc(blockedWebhookPrefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// matchesHost reports whether host is one of patterns, where *.example.com
+// matches subdomains of example.com
+func matchesHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(pattern), "."))
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newRecordingTracing() (*Tracing, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return &Tracing{
+		Provider:   sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+		Propagator: propagation.TraceContext{},
+	}, recorder
+}
+
+func TestNotificationHeaders(t *testing.T) {
+	tracing, _ := newRecordingTracing()
+	ctx := WithLogAttrs(context.Background(), LogKeyRequestID, "req-1")
+	ctx, endSpan := tracing.StartSpan(ctx, "tasks/send", trace.SpanKindServer)
+	defer endSpan(nil)
+	body := []byte(`{"event":{}}`)
+
+	headers := NotificationHeaders(ctx, tracing, "secret", body)
+	if !strings.Contains(headers["Traceparent"], trace.SpanContextFromContext(ctx).TraceID().String()) {
+		t.Errorf("expected traceparent for the request trace, got %q", headers["Traceparent"])
+	}
+	if headers[CorrelationIDHeader] != "req-1" {
+		t.Errorf("expected correlation ID req-1, got %q", headers[CorrelationIDHeader])
+	}
+	if headers[SignatureHeader] != SignPayload("secret", body) {
+		t.Errorf("expected body signature, got %q", headers[SignatureHeader])
+	}
+
+	// Without tracing or a signing key only the correlation ID is carried
+	headers = NotificationHeaders(ctx, nil, "", body)
+	if len(headers) != 1 || headers[CorrelationIDHeader] != "req-1" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if attributes := notificationAttributes(NotificationHeaders(context.Background(), nil, "", body)); attributes != nil {
+		t.Errorf("expected no message attributes, got %v", attributes)
+	}
+}
+
+func TestWebhookDelivererDeliver(t *testing.T) {
+	var received *http.Request
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(data)
+	}))
+	defer server.Close()
+
+	token, credentials := "client-token", "bearer-secret"
+	body, err := json.Marshal(PushNotification{
+		PushConfig: a2a.PushConfig{
+			URL:   server.URL,
+			Token: &token,
+			Auth:  &a2a.PushAuthInfo{Schemes: []string{"bearer"}, Credentials: &credentials},
+		},
+		Event: json.RawMessage(`{"kind":"status-update"}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The enqueue side of the trace, as the request handler leaves it
+	tracing, recorder := newRecordingTracing()
+	enqueueCtx, endEnqueue := tracing.StartSpan(WithLogAttrs(context.Background(), LogKeyRequestID, "req-1"), "tasks/send", trace.SpanKindServer)
+	headers := NotificationHeaders(enqueueCtx, tracing, "secret", body)
+	endEnqueue(nil)
+	// The worker names the notification after its queue message
+	headers[NotificationIDHeader] = "msg-1"
+
+	deliverer := NewWebhookDeliverer(server.Client(), tracing)
+	if err := deliverer.Deliver(context.Background(), string(body), headers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedBody != string(body) {
+		t.Errorf("expected body to be forwarded unchanged, got %s", receivedBody)
+	}
+	expected := map[string]string{
+		CorrelationIDHeader:     "req-1",
+		SignatureHeader:         SignPayload("secret", body),
+		NotificationTokenHeader: token,
+		NotificationIDHeader:    "msg-1",
+		"Authorization":         "Bearer " + credentials,
+	}
+	for name, value := range expected {
+		if got := received.Header.Get(name); got != value {
+			t.Errorf("expected %s %q, got %q", name, value, got)
+		}
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	enqueue, deliver := spans[0], spans[1]
+	if deliver.Parent().SpanID() != enqueue.SpanContext().SpanID() {
+		t.Error("expected delivery span to continue the enqueuing trace")
+	}
+	traceparent := received.Header.Get("traceparent")
+	if !strings.Contains(traceparent, deliver.SpanContext().SpanID().String()) {
+		t.Errorf("expected webhook traceparent to name the delivery span, got %q", traceparent)
+	}
+}
+
+func TestWebhookDelivererDeliverErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid message", body: "not json"},
+		{name: "no webhook URL", body: `{"push_config":{},"event":{}}`},
+		{name: "non-2xx response", body: `{"push_config":{"URL":"` + server.URL + `"},"event":{}}`},
+	}
+
+	deliverer := NewWebhookDeliverer(server.Client(), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := deliverer.Deliver(context.Background(), tt.body, nil); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// WithAdminAPI serves the admin/* methods on the path of authenticator,
+// to callers with the admin API key. Without it no admin method is served.
+func WithAdminAPI(authenticator *a2aTypes.AdminAuthenticator) Option {
+	return func(h *Handler) {
+		h.admin = authenticator
+	}
+}
+
+type adminEndpointContextKey struct{}
+
+// onAdminEndpoint reports whether the request came in on the admin API
+func onAdminEndpoint(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminEndpointContextKey{}).(bool)
+	return admin
+}
+
+// isAdminRequest reports whether req is a JSON-RPC call to the admin API
+func (h *Handler) isAdminRequest(req Request) bool {
+	return h.admin != nil && req.URL == h.admin.Path()
+}
+
+// handleAdminRequest serves a JSON-RPC call to the admin API. Only the admin
+// API key is checked: credentials of the agent's endpoint are not accepted
+// here, and the admin key is not accepted there.
+func (h *Handler) handleAdminRequest(ctx context.Context, req Request) Response {
+	principal, err := h.admin.Authenticate(req.Headers)
+	if err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("admin request rejected", a2aTypes.LogKeyError, err)
+		return h.HandleError("Unauthorized", http.StatusUnauthorized)
+	}
+	ctx = a2aTypes.ContextWithPrincipal(ctx, principal)
+	ctx = context.WithValue(ctx, adminEndpointContextKey{}, true)
+	return h.handleJSONRPC(ctx, req)
+}
+
+// denyWrongEndpoint answers Method not found to an admin method called on
+// the agent's endpoint, or any other method called on the admin API, so
+// neither endpoint reveals what the other serves
+func (h *Handler) denyWrongEndpoint(ctx context.Context, req a2aTypes.JSONRPCRequest) (Response, bool) {
+	if a2aTypes.IsAdminMethod(req.Method) == onAdminEndpoint(ctx) {
+		return Response{}, false
+	}
+	return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", req.Method, req.ID), true
+}
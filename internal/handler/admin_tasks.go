@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// Admin methods reading every task
+const (
+	taskListMethod   = "admin/tasks/list"
+	taskExportMethod = "admin/tasks/export"
+)
+
+// handleListAllTasks handles the admin/tasks/list method, served only on
+// the admin API. It lists across contexts and callers, which no method of
+// the agent's endpoint does.
+func (h *Handler) handleListAllTasks(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	var params a2aTypes.TaskListParams
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if err := params.Validate(); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+
+	page, err := h.a2aHandler.ListAllTasks(ctx, params)
+	if errors.Is(err, a2aTypes.ErrTaskListingUnsupported) {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "task listing is not enabled here", req.ID)
+	}
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(page, req.ID)
+}
+
+// handleExportTask handles the admin/tasks/export method, served only on
+// the admin API
+func (h *Handler) handleExportTask(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	var params a2aTypes.TaskExportParams
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if params.TaskID == "" {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", "task_id is required", req.ID)
+	}
+	auditTaskID(ctx, string(params.TaskID))
+
+	export, err := h.a2aHandler.ExportTask(ctx, params.TaskID)
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(export, req.ID)
+}
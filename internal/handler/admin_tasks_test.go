@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// sortedTaskLister lists the tasks of a memoryTaskStore by ID
+type sortedTaskLister []a2a.TaskID
+
+func (l sortedTaskLister) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	return func(yield func(a2a.TaskID, error) bool) {
+		for _, id := range l {
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestHandleAdminTasks(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	listing := a2aTypes.WithTaskListing(sortedTaskLister{"task-1", "task-2"})
+
+	tests := []struct {
+		name    string
+		opts    []a2aTypes.RuntimeOption
+		request Request
+		expect  []string
+	}{
+		{name: "lists across contexts", opts: []a2aTypes.RuntimeOption{listing}, request: adminRPCRequest(taskListMethod, `{"limit":1}`), expect: []string{`"ID":"task-1"`, `"next_cursor":"1"`}},
+		{name: "continues from a cursor", opts: []a2aTypes.RuntimeOption{listing}, request: adminRPCRequest(taskListMethod, `{"cursor":"1"}`), expect: []string{`"ID":"task-2"`}},
+		{name: "limit checked", opts: []a2aTypes.RuntimeOption{listing}, request: adminRPCRequest(taskListMethod, `{"limit":1000}`), expect: []string{`"code":-32602`, `"path":"limit"`}},
+		{name: "listing not enabled", request: adminRPCRequest(taskListMethod, `{}`), expect: []string{"task listing is not enabled here"}},
+		{name: "list not served on the agent endpoint", opts: []a2aTypes.RuntimeOption{listing}, request: jsonRPCRequest(taskListMethod, `{}`, key), expect: []string{`"code":-32601`}},
+		{name: "exports a task", request: adminRPCRequest(taskExportMethod, `{"task_id":"task-2"}`), expect: []string{`"task":{`, `"ID":"task-2"`, `"events":[]`, `"push_configs":[]`}},
+		{name: "export needs a task", request: adminRPCRequest(taskExportMethod, `{}`), expect: []string{"task_id is required"}},
+		{name: "export not served on the agent endpoint", request: jsonRPCRequest(taskExportMethod, `{"task_id":"task-2"}`, key), expect: []string{`"code":-32601`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{
+				"task-1": {ID: "task-1", ContextID: "ctx-1"},
+				"task-2": {ID: "task-2", ContextID: "ctx-2"},
+			}}
+			a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, discardEventStore{}, nil, tt.opts...)
+			h := NewHandler(a2aHandler, agentcard.New("Test Agent", "https://agent.example.com"), WithAuthenticator(authenticator), WithAdminAPI(testAdminAPI))
+
+			response := h.HandleRequest(context.Background(), tt.request)
+			for _, expect := range tt.expect {
+				if !strings.Contains(response.Body, expect) {
+					t.Errorf("expected %s, got %s", expect, response.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// testAdminAPI serves the admin API at its default path to adminKey
+var (
+	testAdminAPI = a2aTypes.NewAdminAuthenticator(a2aTypes.AdminConfig{}, "admin-secret")
+	adminKey     = map[string]string{a2aTypes.DefaultAdminAPIKeyHeader: "admin-secret"}
+)
+
+// adminRPCRequest builds a JSON-RPC call to the admin API with adminKey
+func adminRPCRequest(method string, params string) Request {
+	request := jsonRPCRequest(method, params, adminKey)
+	request.URL = a2aTypes.DefaultAdminPath
+	return request
+}
+
+func TestHandleRequestAdminAPI(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	// Admin subjects may access every task, but no longer call admin methods
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key", AdminSubjects: []string{a2aTypes.SecuritySchemeAPIKey}}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	withAdminKey := func(request Request, headers map[string]string) Request {
+		for name, value := range headers {
+			request.Headers[name] = value
+		}
+		return request
+	}
+	agentKeyOnAdminPath := jsonRPCRequest("admin/capture/get", `{"request_id":"req-1"}`, key)
+	agentKeyOnAdminPath.URL = a2aTypes.DefaultAdminPath
+
+	tests := []struct {
+		name         string
+		adminAPI     bool
+		request      Request
+		expectStatus int
+		expectBody   string
+	}{
+		{name: "admin method on the admin API", adminAPI: true, request: adminRPCRequest("admin/capture/get", `{"request_id":"req-1"}`), expectStatus: http.StatusOK, expectBody: "capture is not enabled here"},
+		{name: "admin method on the agent endpoint", adminAPI: true, request: withAdminKey(jsonRPCRequest("admin/capture/get", `{"request_id":"req-1"}`, key), adminKey), expectStatus: http.StatusOK, expectBody: `"code":-32601`},
+		{name: "agent method on the admin API", adminAPI: true, request: adminRPCRequest("tasks/get", `{"ID":"task-1"}`), expectStatus: http.StatusOK, expectBody: `"code":-32601`},
+		{name: "agent key on the admin API", adminAPI: true, request: agentKeyOnAdminPath, expectStatus: http.StatusUnauthorized},
+		{name: "wrong admin key", adminAPI: true, request: withAdminKey(adminRPCRequest("admin/capture/get", `{}`), map[string]string{a2aTypes.DefaultAdminAPIKeyHeader: "guess"}), expectStatus: http.StatusUnauthorized},
+		{name: "admin API off by default", request: withAdminKey(adminRPCRequest("admin/capture/get", `{"request_id":"req-1"}`), key), expectStatus: http.StatusOK, expectBody: `"code":-32601`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(authenticator)
+			if tt.adminAPI {
+				WithAdminAPI(testAdminAPI)(h)
+			}
+
+			response := h.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+		})
+	}
+}
+
+func TestHandleRequestAdminAPISkipsMethodPolicies(t *testing.T) {
+	// Callers of the agent's endpoint may only send messages
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{
+		APIKeyHeader:   "X-API-Key",
+		MethodPolicies: []a2aTypes.MethodPolicy{{Subjects: []string{"*"}, Methods: []string{"message/send"}}},
+	}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	h := newTestHandler(authenticator)
+	WithAdminAPI(testAdminAPI)(h)
+
+	response := h.HandleRequest(context.Background(), adminRPCRequest("admin/capture/get", `{"request_id":"req-1"}`))
+	if response.Status != http.StatusOK || !strings.Contains(response.Body, "capture is not enabled here") {
+		t.Errorf("expected the admin call past the method policies, got %d: %s", response.Status, response.Body)
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// auditContextKey carries the *AuditRecord of the call being handled, which
+// the method handlers fill in as they learn the task and outcome
+type auditContextKey struct{}
+
+// startAudit returns a context carrying a record for the call. Until an
+// error response says otherwise the call is recorded as successful.
+func (h *Handler) startAudit(ctx context.Context, requestID, method string) context.Context {
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	record := &a2aTypes.AuditRecord{
+		Timestamp: time.Now(),
+		RequestID: requestID,
+		Scheme:    principal.Scheme,
+		Subject:   principal.Subject,
+		Method:    method,
+		Outcome:   a2aTypes.AuditOutcomeOK,
+	}
+	return context.WithValue(ctx, auditContextKey{}, record)
+}
+
+// writeAudit persists the call's record. A failed write is logged rather than
+// failing a call that has already taken effect.
+func (h *Handler) writeAudit(ctx context.Context) {
+	record, ok := ctx.Value(auditContextKey{}).(*a2aTypes.AuditRecord)
+	if !ok {
+		return
+	}
+	if err := h.auditLog.RecordAudit(ctx, *record); err != nil {
+		a2aTypes.LoggerFromContext(ctx).Error("failed to write audit record", a2aTypes.LogKeyError, err)
+	}
+}
+
+// auditTaskID records which task the call acted on
+func auditTaskID(ctx context.Context, taskID string) {
+	if record, ok := ctx.Value(auditContextKey{}).(*a2aTypes.AuditRecord); ok {
+		record.TaskID = taskID
+	}
+}
+
+// handleListAudit handles the admin/audit/list method, served only on the
+// admin API
+func (h *Handler) handleListAudit(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	reader, ok := h.auditLog.(a2aTypes.AuditReader)
+	if !ok {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "the audit log is not queryable here", req.ID)
+	}
+
+	var query a2aTypes.AuditQuery
+	if err := a2aTypes.DecodeParams(req.Params, &query); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if query.TaskID == "" && query.Subject == "" {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", "task_id or subject is required", req.ID)
+	}
+
+	records, err := reader.ListAudit(ctx, query)
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(records, req.ID)
+}
+
+// denyNonAdmin returns a Forbidden response, recorded as denied, unless the
+// call came in on the admin API. Admin methods are not routed from the
+// agent's endpoint at all; this keeps each of them safe on its own.
+func (h *Handler) denyNonAdmin(ctx context.Context) (Response, bool) {
+	if onAdminEndpoint(ctx) {
+		return Response{}, false
+	}
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	a2aTypes.LoggerFromContext(ctx).Warn("admin method rejected", "subject", principal.Subject)
+	auditDenied(ctx)
+	return h.HandleError("Forbidden", http.StatusForbidden), true
+}
+
+// auditDenied records the call as refused by an authorization check
+func auditDenied(ctx context.Context) {
+	if record, ok := ctx.Value(auditContextKey{}).(*a2aTypes.AuditRecord); ok {
+		record.Outcome = a2aTypes.AuditOutcomeDenied
+	}
+}
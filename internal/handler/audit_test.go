@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// memoryAuditLog keeps audit records in a slice and lists them by task
+type memoryAuditLog struct {
+	records []a2aTypes.AuditRecord
+}
+
+func (l *memoryAuditLog) RecordAudit(ctx context.Context, record a2aTypes.AuditRecord) error {
+	l.records = append(l.records, record)
+	return nil
+}
+
+func (l *memoryAuditLog) ListAudit(ctx context.Context, query a2aTypes.AuditQuery) ([]a2aTypes.AuditRecord, error) {
+	var records []a2aTypes.AuditRecord
+	for _, record := range l.records {
+		if record.TaskID == query.TaskID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func TestHandleRequestAudit(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+
+	tests := []struct {
+		name         string
+		request      Request
+		expectStatus int
+		expectBody   string
+		expectRecord a2aTypes.AuditRecord
+	}{
+		{
+			name:         "successful call",
+			request:      jsonRPCRequest("tasks/get", `{"id":"task-1"}`, key),
+			expectStatus: http.StatusOK,
+			expectRecord: a2aTypes.AuditRecord{Scheme: "apiKey", Subject: "apiKey", Method: "tasks/get", TaskID: "task-1", Outcome: a2aTypes.AuditOutcomeOK},
+		},
+		{
+			name:         "failed call",
+			request:      jsonRPCRequest("tasks/cancel", `{"id":"task-2"}`, key),
+			expectStatus: http.StatusOK,
+			expectRecord: a2aTypes.AuditRecord{Scheme: "apiKey", Subject: "apiKey", Method: "tasks/cancel", TaskID: "task-2", Outcome: a2aTypes.AuditOutcomeError, ErrorCode: -32000},
+		},
+		{
+			name:         "admin lists a task's records",
+			request:      adminRPCRequest("admin/audit/list", `{"task_id":"task-1"}`),
+			expectStatus: http.StatusOK,
+			expectBody:   `"method":"tasks/get"`,
+			expectRecord: a2aTypes.AuditRecord{Scheme: "adminKey", Subject: "admin", Method: "admin/audit/list", Outcome: a2aTypes.AuditOutcomeOK},
+		},
+		{
+			name:         "admin query needs a key",
+			request:      adminRPCRequest("admin/audit/list", `{}`),
+			expectStatus: http.StatusOK,
+			expectBody:   "task_id or subject is required",
+			expectRecord: a2aTypes.AuditRecord{Scheme: "adminKey", Subject: "admin", Method: "admin/audit/list", Outcome: a2aTypes.AuditOutcomeError, ErrorCode: -32602},
+		},
+		{
+			name:         "not served on the agent endpoint",
+			request:      jsonRPCRequest("admin/audit/list", `{"task_id":"task-1"}`, key),
+			expectStatus: http.StatusOK,
+			expectBody:   `"code":-32601`,
+			expectRecord: a2aTypes.AuditRecord{Scheme: "apiKey", Subject: "apiKey", Method: "admin/audit/list", Outcome: a2aTypes.AuditOutcomeError, ErrorCode: -32601},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every case sees one earlier tasks/get on task-1 to list
+			auditLog := &memoryAuditLog{records: []a2aTypes.AuditRecord{{Method: "tasks/get", TaskID: "task-1", Outcome: a2aTypes.AuditOutcomeOK}}}
+			h := newTestHandler(authenticator)
+			WithAdminAPI(testAdminAPI)(h)
+			h.auditLog = auditLog
+
+			tt.request.RequestID = "req-1"
+			response := h.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+
+			if len(auditLog.records) != 2 {
+				t.Fatalf("expected one new audit record, got %v", auditLog.records[1:])
+			}
+			got := auditLog.records[1]
+			if got.Timestamp.IsZero() || got.RequestID != "req-1" {
+				t.Errorf("expected timestamp and request ID, got %+v", got)
+			}
+			tt.expectRecord.Timestamp, tt.expectRecord.RequestID = got.Timestamp, got.RequestID
+			if got != tt.expectRecord {
+				t.Errorf("expected %+v, got %+v", tt.expectRecord, got)
+			}
+		})
+	}
+}
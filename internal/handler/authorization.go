@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// authenticate identifies the caller: by the IAM identity API Gateway
+// verified when IAM callers are accepted, otherwise by the credentials the
+// request carries
+func (h *Handler) authenticate(ctx context.Context, req Request) (a2aTypes.Principal, error) {
+	if req.CallerARN != "" && h.authenticator.AcceptsIAM() {
+		return a2aTypes.IAMPrincipal(req.CallerARN), nil
+	}
+	return h.authenticator.Authenticate(ctx, req.Headers, []byte(req.Body))
+}
+
+// denyMethod returns a Forbidden response, recorded as denied, unless the
+// method policies grant method to the caller. It runs before dispatch, so
+// unknown methods are checked too and answer the same to everyone.
+func (h *Handler) denyMethod(ctx context.Context, method string) (Response, bool) {
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	if h.authenticator.AuthorizeMethod(principal, method) {
+		return Response{}, false
+	}
+	a2aTypes.LoggerFromContext(ctx).Warn("method not granted", "subject", principal.Subject)
+	auditDenied(ctx)
+	return h.HandleError("Forbidden", http.StatusForbidden), true
+}
+
+// denySkill returns a Forbidden response, recorded as denied, when message
+// is for a skill the caller may not use. A message naming no skill, or one
+// the card does not list, is for the card's first skill, its default, so
+// leaving the skill off does not get around its restriction.
+func (h *Handler) denySkill(ctx context.Context, message a2a.Message) (Response, bool) {
+	skills := h.agentCard().Skills
+	if len(skills) == 0 {
+		return Response{}, false
+	}
+	skillID, _ := message.Metadata[a2aTypes.SkillMetadataKey].(string)
+	skill := skills[0]
+	if i := slices.IndexFunc(skills, func(skill a2a.AgentSkill) bool { return skill.ID == skillID }); i >= 0 {
+		skill = skills[i]
+	}
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	if h.authenticator.CanUseSkill(principal, skill) {
+		return Response{}, false
+	}
+	a2aTypes.LoggerFromContext(ctx).Warn("skill not granted", "skill", skill.ID, "subject", principal.Subject)
+	auditDenied(ctx)
+	return h.HandleError("Forbidden", http.StatusForbidden), true
+}
+
+// denyTask answers as if task did not exist when the caller may not access
+// it, so callers cannot probe for other callers' task IDs
+func (h *Handler) denyTask(ctx context.Context, task a2a.Task, id interface{}) (Response, bool) {
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	if h.authenticator.CanAccessTask(principal, task) {
+		return Response{}, false
+	}
+	response := h.handleServerError(ctx, fmt.Errorf("task %s: %w", task.ID, a2a.ErrTaskNotFound), id)
+	auditDenied(ctx)
+	return response, true
+}
+
+// denyTaskID is denyTask for a call that names a task it has not loaded.
+// The task is only loaded when callers are limited to their own tasks.
+func (h *Handler) denyTaskID(ctx context.Context, taskID a2a.TaskID, id interface{}) (Response, bool) {
+	if !h.authenticator.OwnTasksOnly() {
+		return Response{}, false
+	}
+	task, err := h.a2aHandler.OnGetTask(ctx, a2a.TaskQueryParams{ID: taskID})
+	if err != nil {
+		return h.handleServerError(ctx, err, id), true
+	}
+	return h.denyTask(ctx, task, id)
+}
+
+// extendedCardMethod returns the agent card as the caller may use it
+const extendedCardMethod = "agent/getAuthenticatedExtendedCard"
+
+// handleExtendedCard handles agent/getAuthenticatedExtendedCard: the agent
+// card listing exactly the skills the caller may use, including restricted
+// ones the public card leaves off
+func (h *Handler) handleExtendedCard(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	card := h.agentCard()
+	card.Skills = h.authenticator.SkillsFor(principal, card.Skills)
+	return h.handleJSONRPCSuccess(a2aTypes.RedactAgentCard(card), req.ID)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestHandleRequestMethodPolicies(t *testing.T) {
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{
+		IAM: true,
+		MethodPolicies: []a2aTypes.MethodPolicy{
+			{Subjects: []string{"arn:aws:iam::123456789012:role/reader"}, Methods: []string{"tasks/get"}},
+		},
+	}, a2aTypes.SecretsConfig{}, nil)
+	h := newTestHandler(authenticator)
+
+	tests := []struct {
+		name         string
+		callerARN    string
+		method       string
+		expectStatus int
+	}{
+		{name: "granted method", callerARN: "arn:aws:iam::123456789012:role/reader", method: "tasks/get", expectStatus: http.StatusOK},
+		{name: "method not granted", callerARN: "arn:aws:iam::123456789012:role/reader", method: "tasks/cancel", expectStatus: http.StatusForbidden},
+		{name: "unknown caller", callerARN: "arn:aws:iam::123456789012:role/other", method: "tasks/get", expectStatus: http.StatusForbidden},
+		{name: "no IAM identity", method: "tasks/get", expectStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := jsonRPCRequest(tt.method, `{"ID":"task-1"}`, nil)
+			request.CallerARN = tt.callerARN
+
+			response := h.HandleRequest(context.Background(), request)
+			if response.Status != tt.expectStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+		})
+	}
+}
+
+func TestHandleRequestOwnTasksOnly(t *testing.T) {
+	const (
+		owner = "arn:aws:iam::123456789012:role/owner"
+		other = "arn:aws:iam::123456789012:role/other"
+		admin = "arn:aws:iam::123456789012:role/ops"
+	)
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{IAM: true, OwnTasksOnly: true, AdminSubjects: []string{admin}}, a2aTypes.SecretsConfig{}, nil)
+	h := newTestHandler(authenticator)
+	call := func(callerARN, method, params string) Response {
+		request := jsonRPCRequest(method, params, nil)
+		request.CallerARN = callerARN
+		return h.HandleRequest(context.Background(), request)
+	}
+
+	response := call(owner, "message/send", `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]}}`)
+	var sent struct {
+		Result struct{ ID string }
+	}
+	if err := json.Unmarshal([]byte(response.Body), &sent); err != nil || sent.Result.ID == "" {
+		t.Fatalf("expected a new task, got %s", response.Body)
+	}
+	params := `{"ID":"` + sent.Result.ID + `"}`
+	// The owner is kept for the checks below but never sent
+	if strings.Contains(response.Body, owner) {
+		t.Errorf("expected the owner left off the task, got %s", response.Body)
+	}
+	if response := call(owner, "tasks/get", params); strings.Contains(response.Body, owner) {
+		t.Errorf("expected the owner left off the task, got %s", response.Body)
+	}
+
+	tests := []struct {
+		name       string
+		callerARN  string
+		method     string
+		params     string
+		expectBody string
+	}{
+		{name: "owner gets the task", callerARN: owner, method: "tasks/get", params: params, expectBody: `"result"`},
+		{name: "another caller cannot get it", callerARN: other, method: "tasks/get", params: params, expectBody: `"code":-32001`},
+		{name: "another caller cannot cancel it", callerARN: other, method: "tasks/cancel", params: params, expectBody: `"code":-32001`},
+		{name: "another caller cannot continue it", callerARN: other, method: "message/send", params: `{"Message":{"MessageID":"m-2","Role":"user","TaskID":"` + sent.Result.ID + `","Parts":[{"Kind":"text","Text":"hi"}]}}`, expectBody: `"code":-32001`},
+		{name: "admin gets the task", callerARN: admin, method: "tasks/get", params: params, expectBody: `"result"`},
+		{name: "task without an owner", callerARN: owner, method: "tasks/get", params: `{"ID":"task-1"}`, expectBody: `"code":-32001`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := call(tt.callerARN, tt.method, tt.params)
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+		})
+	}
+}
+
+func TestHandleRequestSkillAccess(t *testing.T) {
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{IAM: true, APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	h := newTestHandlerWithCard(authenticator, agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithSkills([]a2a.AgentSkill{
+		{ID: "general", Name: "General"},
+		{ID: "billing", Name: "Billing", Security: []map[string][]string{{a2aTypes.SecuritySchemeIAM: {}}}},
+	})))
+	iamCaller := func(request Request) Request {
+		request.CallerARN = "arn:aws:iam::123456789012:role/billing"
+		return request
+	}
+	apiKey := map[string]string{"X-API-Key": "secret"}
+
+	// The public card leaves the restricted skill off and points to the extended card
+	response := h.HandleRequest(context.Background(), Request{Method: "GET", URL: "/"})
+	if strings.Contains(response.Body, "billing") || !strings.Contains(response.Body, `"SupportsAuthenticatedExtendedCard":true`) {
+		t.Errorf("unexpected public card: %s", response.Body)
+	}
+
+	send := func(skillID string) string {
+		return `{"Message":{"MessageID":"m-1","Role":"user","Metadata":{"skillId":"` + skillID + `"},"Parts":[{"Kind":"text","Text":"hi"}]}}`
+	}
+	// A message naming no skill is for the first, as is one naming a skill
+	// the card does not list
+	noSkill := `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]}}`
+	restricted := newTestHandlerWithCard(authenticator, agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithSkills([]a2a.AgentSkill{
+		{ID: "billing", Name: "Billing", Security: []map[string][]string{{a2aTypes.SecuritySchemeIAM: {}}}},
+		{ID: "general", Name: "General"},
+	})))
+	tests := []struct {
+		name         string
+		handler      *Handler
+		request      Request
+		expectStatus int
+		expectBody   string
+		rejectBody   string
+	}{
+		{name: "extended card lists usable skills", request: iamCaller(jsonRPCRequest("agent/getAuthenticatedExtendedCard", `{}`, nil)), expectStatus: http.StatusOK, expectBody: `"ID":"billing"`},
+		{name: "extended card hides other skills", request: jsonRPCRequest("agent/getAuthenticatedExtendedCard", `{}`, apiKey), expectStatus: http.StatusOK, expectBody: `"ID":"general"`, rejectBody: "billing"},
+		{name: "send to a permitted skill", request: iamCaller(jsonRPCRequest("message/send", send("billing"), nil)), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{name: "send to a restricted skill", request: jsonRPCRequest("message/send", send("billing"), apiKey), expectStatus: http.StatusForbidden},
+		{name: "send to an open skill", request: jsonRPCRequest("message/send", send("general"), apiKey), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{name: "send to the open default skill", request: jsonRPCRequest("message/send", noSkill, apiKey), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{name: "send without a skill to a restricted default", handler: restricted, request: jsonRPCRequest("message/send", noSkill, apiKey), expectStatus: http.StatusForbidden},
+		{name: "send to an unlisted skill with a restricted default", handler: restricted, request: jsonRPCRequest("message/send", send("unknown"), apiKey), expectStatus: http.StatusForbidden},
+		{name: "send without a skill to a permitted default", handler: restricted, request: iamCaller(jsonRPCRequest("message/send", noSkill, nil)), expectStatus: http.StatusOK, expectBody: `"result"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := h
+			if tt.handler != nil {
+				handler = tt.handler
+			}
+			response := handler.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+			if tt.rejectBody != "" && strings.Contains(response.Body, tt.rejectBody) {
+				t.Errorf("expected body not to contain %s, got %s", tt.rejectBody, response.Body)
+			}
+		})
+	}
+}
+
+func TestPublicAgentCardAllSkillsRestricted(t *testing.T) {
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{IAM: true}, a2aTypes.SecretsConfig{}, nil)
+	card := PublicAgentCard(agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithSkills([]a2a.AgentSkill{
+		{ID: "billing", Name: "Billing", Security: []map[string][]string{{a2aTypes.SecuritySchemeIAM: {}}}},
+	})), authenticator)
+
+	body, err := json.Marshal(card)
+	if err != nil {
+		t.Fatalf("marshal card: %v", err)
+	}
+	// Clients expect a list, even an empty one
+	if !strings.Contains(string(body), `"Skills":[]`) {
+		t.Errorf("expected an empty skills list, got %s", body)
+	}
+}
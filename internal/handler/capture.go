@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// captureMethod is the admin method returning captures. Its own calls are
+// not captured, since their responses are earlier captures.
+const captureMethod = "admin/capture/get"
+
+// captureQuery is the params of admin/capture/get
+type captureQuery struct {
+	RequestID string `json:"request_id"`
+}
+
+// captureExchange saves the call's redacted request and response. Calls
+// without a request ID cannot be looked up, so they are skipped. A failed
+// write is logged rather than failing the call.
+func (h *Handler) captureExchange(ctx context.Context, req Request, response Response) {
+	if req.RequestID == "" {
+		return
+	}
+	exchange := a2aTypes.NewCapturedExchange(req.RequestID, time.Now(), req.Body, response.Status, response.Body)
+	if exchange.Method == captureMethod {
+		return
+	}
+	if err := h.captureStore.SaveCapture(ctx, exchange); err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("failed to capture exchange", a2aTypes.LogKeyError, err)
+	}
+}
+
+// handleGetCapture handles the admin/capture/get method, served only on the
+// admin API
+func (h *Handler) handleGetCapture(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	if h.captureStore == nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "capture is not enabled here", req.ID)
+	}
+
+	var query captureQuery
+	if err := a2aTypes.DecodeParams(req.Params, &query); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if query.RequestID == "" {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", "request_id is required", req.ID)
+	}
+
+	exchange, err := h.captureStore.GetCapture(ctx, query.RequestID)
+	if errors.Is(err, a2aTypes.ErrCaptureNotFound) {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", "no capture for request_id; it may have expired", req.ID)
+	}
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(exchange, req.ID)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// memoryCaptureStore keeps captured exchanges by request ID
+type memoryCaptureStore struct {
+	exchanges map[string]a2aTypes.CapturedExchange
+}
+
+func (s *memoryCaptureStore) SaveCapture(ctx context.Context, exchange a2aTypes.CapturedExchange) error {
+	s.exchanges[exchange.RequestID] = exchange
+	return nil
+}
+
+func (s *memoryCaptureStore) GetCapture(ctx context.Context, requestID string) (a2aTypes.CapturedExchange, error) {
+	exchange, ok := s.exchanges[requestID]
+	if !ok {
+		return a2aTypes.CapturedExchange{}, a2aTypes.ErrCaptureNotFound
+	}
+	return exchange, nil
+}
+
+func TestHandleRequestCapture(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+
+	store := &memoryCaptureStore{exchanges: map[string]a2aTypes.CapturedExchange{}}
+	h := newTestHandler(authenticator)
+	WithCapture(store)(h)
+	WithAdminAPI(testAdminAPI)(h)
+
+	// A call is captured with its push token redacted
+	send := jsonRPCRequest("message/send", `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]},"Config":{"PushConfig":{"URL":"https://hook.example.com","Token":"push-token"}}}`, key)
+	send.RequestID = "req-1"
+	if response := h.HandleRequest(context.Background(), send); response.Status != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", response.Status, response.Body)
+	}
+	exchange, ok := store.exchanges["req-1"]
+	if !ok {
+		t.Fatal("expected the call to be captured")
+	}
+	if exchange.Method != "message/send" || exchange.Status != http.StatusOK || !strings.Contains(string(exchange.Response), `"result"`) {
+		t.Errorf("unexpected capture: %+v", exchange)
+	}
+	if strings.Contains(string(exchange.Request), "push-token") {
+		t.Errorf("expected the push token to be redacted, got %s", exchange.Request)
+	}
+
+	tests := []struct {
+		name         string
+		request      Request
+		expectStatus int
+		expectBody   string
+	}{
+		{name: "admin fetches a capture", request: adminRPCRequest("admin/capture/get", `{"request_id":"req-1"}`), expectStatus: http.StatusOK, expectBody: `"method":"message/send"`},
+		{name: "unknown request", request: adminRPCRequest("admin/capture/get", `{"request_id":"req-missing"}`), expectStatus: http.StatusOK, expectBody: "no capture for request_id"},
+		{name: "request ID required", request: adminRPCRequest("admin/capture/get", `{}`), expectStatus: http.StatusOK, expectBody: "request_id is required"},
+		{name: "not served on the agent endpoint", request: jsonRPCRequest("admin/capture/get", `{"request_id":"req-1"}`, key), expectStatus: http.StatusOK, expectBody: `"code":-32601`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			request.RequestID = "req-admin"
+
+			response := h.HandleRequest(context.Background(), request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+			if _, ok := store.exchanges["req-admin"]; ok {
+				t.Error("expected admin/capture/get calls not to be captured")
+			}
+		})
+	}
+}
+
+func TestHandleRequestCaptureDisabled(t *testing.T) {
+	h := newTestHandler(nil)
+	WithAdminAPI(testAdminAPI)(h)
+
+	response := h.HandleRequest(context.Background(), adminRPCRequest("admin/capture/get", `{"request_id":"req-1"}`))
+	if !strings.Contains(response.Body, "capture is not enabled here") {
+		t.Errorf("expected method not found, got %s", response.Body)
+	}
+}
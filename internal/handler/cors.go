@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// defaultCORSMaxAge is how long browsers cache a preflight unless
+// CORSConfig says otherwise
+const defaultCORSMaxAge = 24 * time.Hour
+
+// CORSConfig sets which web origins may call the agent and what they may
+// send. Without WithCORS every origin is allowed.
+type CORSConfig struct {
+	// AllowedOrigins are the origins browsers may call from, such as
+	// "https://app.example.com". Empty, or containing "*", allows every
+	// origin.
+	AllowedOrigins []string
+	// AllowedHeaders are request headers allowed beyond Content-Type,
+	// Authorization and the API key header
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache a preflight; zero is 24 hours
+	MaxAge time.Duration
+}
+
+// WithCORS answers browsers with the CORS headers config describes, in
+// place of the defaults that allow every origin. A request from an origin
+// not allowed is still served, but without an Access-Control-Allow-Origin
+// header, so browsers withhold the response from the page.
+func WithCORS(config CORSConfig) Option {
+	return func(h *Handler) {
+		h.cors = &config
+	}
+}
+
+// applyCORS replaces the CORS headers of a response to req with those of
+// h.cors
+func (h *Handler) applyCORS(req Request, headers map[string]string) {
+	for name := range headers {
+		if strings.HasPrefix(name, "Access-Control-") {
+			delete(headers, name)
+		}
+	}
+
+	if allowed := h.cors.AllowedOrigins; len(allowed) == 0 || slices.Contains(allowed, "*") {
+		headers["Access-Control-Allow-Origin"] = "*"
+	} else {
+		if origin := a2aTypes.HeaderValue(req.Headers, "Origin"); origin != "" && slices.Contains(allowed, origin) {
+			headers["Access-Control-Allow-Origin"] = origin
+		}
+		// Caches must not serve one origin's answer to another
+		if vary := headers["Vary"]; vary != "" {
+			headers["Vary"] = vary + ", Origin"
+		} else {
+			headers["Vary"] = "Origin"
+		}
+	}
+
+	allowHeaders := []string{"Content-Type", "Authorization"}
+	if header := h.authenticator.APIKeyHeader(); header != "" {
+		allowHeaders = append(allowHeaders, header)
+	}
+	headers["Access-Control-Allow-Methods"] = "GET, POST, OPTIONS"
+	headers["Access-Control-Allow-Headers"] = strings.Join(append(allowHeaders, h.cors.AllowedHeaders...), ", ")
+	if req.Method == "OPTIONS" {
+		maxAge := h.cors.MaxAge
+		if maxAge == 0 {
+			maxAge = defaultCORSMaxAge
+		}
+		headers["Access-Control-Max-Age"] = strconv.Itoa(int(maxAge.Seconds()))
+	}
+}
+
+// corsEventStream applies h.cors to the headers of a live stream, which are
+// sent before HandleRequest returns
+type corsEventStream struct {
+	EventStream
+	h   *Handler
+	req Request
+}
+
+func (s corsEventStream) Start(status int, headers map[string]string) {
+	s.h.applyCORS(s.req, headers)
+	s.EventStream.Start(status, headers)
+}
+
+// withCORSEventStream wraps the live stream of ctx, if any, in a
+// corsEventStream
+func (h *Handler) withCORSEventStream(ctx context.Context, req Request) context.Context {
+	stream, ok := ctx.Value(eventStreamContextKey{}).(EventStream)
+	if !ok {
+		return ctx
+	}
+	return ContextWithEventStream(ctx, corsEventStream{EventStream: stream, h: h, req: req})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestWithCORSRestrictsOrigins(t *testing.T) {
+	h := newTestHandler(nil)
+	WithCORS(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowedHeaders: []string{"X-Tenant"}, MaxAge: time.Hour})(h)
+
+	tests := []struct {
+		name   string
+		req    Request
+		origin string
+	}{
+		{name: "allowed preflight", req: Request{Method: "OPTIONS", URL: "/", Headers: map[string]string{"origin": "https://app.example.com"}}, origin: "https://app.example.com"},
+		{name: "allowed card", req: Request{Method: "GET", URL: "/", Headers: map[string]string{"origin": "https://app.example.com"}}, origin: "https://app.example.com"},
+		{name: "other origin", req: Request{Method: "GET", URL: "/", Headers: map[string]string{"origin": "https://evil.example.com"}}},
+		{name: "header as sent", req: Request{Method: "GET", URL: "/", Headers: map[string]string{"Origin": "https://app.example.com"}}, origin: "https://app.example.com"},
+		{name: "error", req: Request{Method: "PUT", URL: "/", Headers: map[string]string{"origin": "https://app.example.com"}}, origin: "https://app.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := h.HandleRequest(context.Background(), tt.req)
+			if got := response.Headers["Access-Control-Allow-Origin"]; got != tt.origin {
+				t.Errorf("expected origin %q, got %q", tt.origin, got)
+			}
+			if response.Headers["Vary"] != "Origin" {
+				t.Errorf("expected responses to vary by origin, got %q", response.Headers["Vary"])
+			}
+		})
+	}
+
+	preflight := h.HandleRequest(context.Background(), tests[0].req)
+	if got := preflight.Headers["Access-Control-Allow-Headers"]; got != "Content-Type, Authorization, X-Tenant" {
+		t.Errorf("unexpected Access-Control-Allow-Headers %q", got)
+	}
+	if got := preflight.Headers["Access-Control-Max-Age"]; got != "3600" {
+		t.Errorf("expected the configured max age, got %q", got)
+	}
+}
+
+func TestWithCORSAppliesToLiveStreams(t *testing.T) {
+	events := listEventStore{events: []a2a.Event{statusEvent(a2a.TaskStateCompleted, 1)}}
+	h := newStreamingHandler(events, WithCORS(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}))
+	stream := &recordingStream{}
+
+	req := jsonRPCRequest("tasks/resubscribe", `{"ID":"task-1"}`, map[string]string{"origin": "https://app.example.com"})
+	h.HandleRequest(ContextWithEventStream(context.Background(), stream), req)
+	if got := stream.headers["Access-Control-Allow-Origin"]; got != "https://app.example.com" {
+		t.Errorf("expected the stream headers to allow the origin, got %q", got)
+	}
+}
@@ -1,14 +1,22 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Request represents an incoming HTTP request
@@ -17,6 +25,11 @@ type Request struct {
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
+	// RequestID is the platform's ID for the request, added to every log line
+	RequestID string `json:"request_id,omitempty"`
+	// CallerARN is the IAM identity API Gateway verified, set only by the
+	// platform and never from client input
+	CallerARN string `json:"caller_arn,omitempty"`
 }
 
 // Response represents an HTTP response
@@ -26,37 +39,350 @@ type Response struct {
 	Body    string            `json:"body"`
 }
 
+// unknownMethod labels errors raised before the JSON-RPC method is known,
+// and methods this handler does not serve
+const unknownMethod = "unknown"
+
+// knownMethods are the JSON-RPC methods this handler routes
+var knownMethods = map[string]bool{
+	"tasks/get":                           true,
+	"tasks/cancel":                        true,
+	"message/send":                        true,
+	"message/stream":                      true,
+	"tasks/resubscribe":                   true,
+	"tasks/pushNotificationConfig/set":    true,
+	"tasks/pushNotificationConfig/get":    true,
+	"tasks/pushNotificationConfig/list":   true,
+	"tasks/pushNotificationConfig/delete": true,
+	pushDeliveriesMethod:                  true,
+	"admin/audit/list":                    true,
+	captureMethod:                         true,
+	purgeMethod:                           true,
+	quotaGetMethod:                        true,
+	quotaResetMethod:                      true,
+	notificationReplayMethod:              true,
+	statsMethod:                           true,
+	taskListMethod:                        true,
+	taskExportMethod:                      true,
+	configReloadMethod:                    true,
+	extendedCardMethod:                    true,
+}
+
+// boundedMethod returns method if this handler serves it, otherwise
+// unknownMethod, so client input never becomes a span name or metric dimension
+func (h *Handler) boundedMethod(method string) string {
+	if knownMethods[method] || h.methods[method] != nil {
+		return method
+	}
+	return unknownMethod
+}
+
+type methodContextKey struct{}
+
+// methodFromContext returns the JSON-RPC method being handled
+func methodFromContext(ctx context.Context) string {
+	if method, ok := ctx.Value(methodContextKey{}).(string); ok {
+		return method
+	}
+	return unknownMethod
+}
+
 // Handler contains the A2A serverless handler
 type Handler struct {
 	a2aHandler *a2aTypes.ServerlessA2AHandler
-	agentCard  a2a.AgentCard
+	// card is swapped whole by SetAgentCard
+	card          atomic.Pointer[publishedCard]
+	authenticator *a2aTypes.Authenticator
+	tracing       *a2aTypes.Tracing
+	auditLog      a2aTypes.AuditLog
+	captureStore  a2aTypes.CaptureStore
+	// quotas is nil unless usage is counted
+	quotas *a2aTypes.Quotas
+	// deliveryLog is nil unless webhook deliveries are recorded
+	deliveryLog a2aTypes.DeliveryLog
+	// notificationReplayer is nil unless failed notifications can be replayed
+	notificationReplayer a2aTypes.NotificationReplayer
+	// stats is nil unless stats are counted
+	stats *a2aTypes.Stats
+	// reloadConfig is nil unless the config can be reloaded
+	reloadConfig ConfigReloader
+	// admin is nil unless the admin API is served
+	admin *a2aTypes.AdminAuthenticator
+	// adapters holds the protocol versions served, by major.minor
+	adapters map[string]RequestAdapter
+	// strictJSONRPC applies CheckStrictJSONRPCRequest to every request
+	strictJSONRPC bool
+	// sseRetry and sseKeepAlive time streaming responses
+	sseRetry     time.Duration
+	sseKeepAlive time.Duration
+	// localization translates the public card for Accept-Language
+	localization a2aTypes.LocalizationConfig
+	// cors is nil unless WithCORS replaced the default CORS headers
+	cors *CORSConfig
+	// middleware wraps routing, the first listed outermost
+	middleware []Middleware
+	// cardPaths are where the agent card is served
+	cardPaths []string
+	// maxBodySize rejects larger request bodies; 0 accepts any size
+	maxBodySize int
+	// logger replaces the logger of each request's context when set
+	logger *slog.Logger
+	// methods are the JSON-RPC methods added with RegisterMethod
+	methods map[string]MethodFunc
+	// beforeMethod and afterMethod intercept every JSON-RPC method
+	beforeMethod []BeforeMethodHook
+	afterMethod  []AfterMethodHook
+}
+
+// agentCardPaths are where the agent card is served, including the
+// well-known path clients discover
+var agentCardPaths = []string{"/", "/agent-card", "/.well-known/agent.json"}
+
+// publishedCard is the agent card a handler serves, kept with its public
+// serialization so the two always change together
+type publishedCard struct {
+	card a2a.AgentCard
+	// body is the public agent card, serialized once since discovery makes
+	// it the busiest path
+	body string
+	// localized are the serialized translations of body by locale
+	localized map[string]string
+}
+
+// Option configures optional Handler behaviour
+type Option func(*Handler)
+
+// HandlerFunc serves a request
+type HandlerFunc func(ctx context.Context, req Request) Response
+
+// Middleware wraps request handling, to add behaviour such as headers,
+// request filtering or metrics without wrapping the Handler
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// WithMiddleware runs each request through middleware before it is routed,
+// the first listed outermost. Middleware sees requests once their logger,
+// trace context and panic recovery are set up, and its responses get the
+// handler's CORS headers.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(h *Handler) {
+		h.middleware = append(h.middleware, middleware...)
+	}
+}
+
+// WithAgentCardPath also serves the agent card at path, beside the
+// well-known path and the others served by default
+func WithAgentCardPath(path string) Option {
+	return func(h *Handler) {
+		path = "/" + strings.TrimPrefix(path, "/")
+		if !slices.Contains(h.cardPaths, path) {
+			h.cardPaths = append(h.cardPaths, path)
+		}
+	}
+}
+
+// WithMaxBodySize answers requests whose body is longer than bytes with
+// 413 Request Entity Too Large before anything parses them. By default the
+// handler accepts what the platform delivers, 10 MB on API Gateway.
+func WithMaxBodySize(bytes int) Option {
+	return func(h *Handler) {
+		h.maxBodySize = bytes
+	}
+}
+
+// WithLogger logs every request to logger, in place of the logger carried
+// by the request's context
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithCapture keeps a redacted copy of every JSON-RPC request and response in
+// store, for the admin/capture/get method to return by request ID
+func WithCapture(store a2aTypes.CaptureStore) Option {
+	return func(h *Handler) {
+		h.captureStore = store
+	}
+}
+
+// WithStrictJSONRPC refuses requests with members JSON-RPC does not define,
+// ids that are not a string, number or null, or params that are not an
+// object or array, as Invalid Request. By default such requests are served
+// when they can be read.
+func WithStrictJSONRPC() Option {
+	return func(h *Handler) {
+		h.strictJSONRPC = true
+	}
 }
 
-// NewHandler creates a new handler instance with A2A support
-func NewHandler(a2aHandler *a2aTypes.ServerlessA2AHandler, agentCard a2a.AgentCard) *Handler {
-	return &Handler{
-		a2aHandler: a2aHandler,
-		agentCard:  agentCard,
+// WithLocalization serves the public agent card translated into the locale
+// each caller's Accept-Language header best matches
+func WithLocalization(config a2aTypes.LocalizationConfig) Option {
+	return func(h *Handler) {
+		h.localization = config
 	}
 }
 
-// HandleRequest processes incoming requests - routes to A2A or returns agent card
-func (h *Handler) HandleRequest(req Request) Response {
-	ctx := context.Background()
+// WithAuthenticator has JSON-RPC calls pass authenticator, and the card
+// list its security schemes. Without it every request is accepted.
+func WithAuthenticator(authenticator *a2aTypes.Authenticator) Option {
+	return func(h *Handler) {
+		h.authenticator = authenticator
+	}
+}
+
+// WithTracing gives each call a server span from tracing
+func WithTracing(tracing *a2aTypes.Tracing) Option {
+	return func(h *Handler) {
+		h.tracing = tracing
+	}
+}
+
+// WithAuditLog writes a record of each call to auditLog
+func WithAuditLog(auditLog a2aTypes.AuditLog) Option {
+	return func(h *Handler) {
+		h.auditLog = auditLog
+	}
+}
+
+// NewHandler creates a new handler instance with A2A support. The card
+// served lists the skills a2aHandler's executor and skill providers define
+// along with agentCard's.
+func NewHandler(a2aHandler *a2aTypes.ServerlessA2AHandler, agentCard a2a.AgentCard, opts ...Option) *Handler {
+	h := &Handler{
+		a2aHandler:   a2aHandler,
+		adapters:     defaultAdapters(),
+		sseRetry:     defaultSSERetry,
+		sseKeepAlive: defaultSSEKeepAlive,
+		cardPaths:    slices.Clone(agentCardPaths),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.adapters[cardProtocolVersion(agentCard.ProtocolVersion)] = nil
+	// Its body is left empty if it fails, so every card request reports the error
+	published, _ := h.publish(agentCard)
+	h.card.Store(published)
+	return h
+}
+
+// SetAgentCard swaps the card the handler serves, and its serialization,
+// for the requests that start afterwards, so a warm instance picks up card
+// changes without a deployment. The executor's and skill providers' skills
+// are merged in as by NewHandler. A card for another protocol version is
+// refused: the versions served are fixed when the Handler is built.
+func (h *Handler) SetAgentCard(card a2a.AgentCard) error {
+	if served := h.agentCard().ProtocolVersion; cardProtocolVersion(card.ProtocolVersion) != cardProtocolVersion(served) {
+		return fmt.Errorf("agent card protocol version %s differs from the served %s, which needs a new handler", cardProtocolVersion(card.ProtocolVersion), cardProtocolVersion(served))
+	}
+	published, err := h.publish(card)
+	if err != nil {
+		return fmt.Errorf("failed to serialize agent card: %w", err)
+	}
+	h.card.Store(published)
+	return nil
+}
+
+// publish prepares card to be served
+func (h *Handler) publish(card a2a.AgentCard) (*publishedCard, error) {
+	published := &publishedCard{card: h.a2aHandler.AgentCard(card)}
+	body, err := json.Marshal(PublicAgentCard(published.card, h.authenticator))
+	if err != nil {
+		return published, err
+	}
+	published.body = string(body)
+
+	published.localized = make(map[string]string, len(h.localization.Locales))
+	for locale := range h.localization.Locales {
+		body, err := json.Marshal(PublicAgentCard(h.localization.Localize(published.card, locale), h.authenticator))
+		if err != nil {
+			return &publishedCard{card: published.card}, err
+		}
+		published.localized[locale] = string(body)
+	}
+	return published, nil
+}
+
+// agentCard returns the card being served
+func (h *Handler) agentCard() a2a.AgentCard {
+	return h.card.Load().card
+}
+
+// HandleRequest processes incoming requests - routes to A2A or returns agent card.
+// The logger in ctx is extended with request fields and passed down to the stores.
+// A panic while handling is reported and answered with a 500.
+func (h *Handler) HandleRequest(ctx context.Context, req Request) (response Response) {
+	if h.logger != nil {
+		ctx = a2aTypes.ContextWithLogger(ctx, h.logger)
+	}
+	if req.RequestID != "" {
+		ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyRequestID, req.RequestID)
+	}
+	ctx = h.tracing.ExtractTraceContext(ctx, req.Headers)
+
+	// Deferred first so it also covers the response to a panic
+	if h.cors != nil {
+		ctx = h.withCORSEventStream(ctx, req)
+		defer func() {
+			if response.Headers == nil {
+				response.Headers = map[string]string{}
+			}
+			h.applyCORS(req, response.Headers)
+		}()
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			a2aTypes.LoggerFromContext(ctx).Error("panic while handling request", a2aTypes.LogKeyError, recovered)
+			a2aTypes.ReportPanic(ctx, recovered, debug.Stack())
+			response = h.HandleError("Internal server error", http.StatusInternalServerError)
+		}
+	}()
+
+	if h.maxBodySize > 0 && len(req.Body) > h.maxBodySize {
+		return h.HandleError("Request body too large", http.StatusRequestEntityTooLarge)
+	}
 
+	serve := h.route
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		serve = h.middleware[i](serve)
+	}
+	return serve(ctx, req)
+}
+
+// route serves a request by its method and path
+func (h *Handler) route(ctx context.Context, req Request) Response {
 	// Handle CORS preflight requests
 	if req.Method == "OPTIONS" {
 		return h.handleCORS()
 	}
 
-	// Handle agent card requests
-	if req.Method == "GET" && (req.URL == "/" || req.URL == "/agent-card") {
-		return h.handleAgentCard()
+	// Handle agent card requests, including the well-known path clients discover
+	if req.Method == "GET" && slices.Contains(h.cardPaths, req.URL) {
+		return h.handleAgentCard(req)
 	}
 
-	// Handle JSON-RPC A2A requests
-	if req.Method == "POST" && strings.Contains(req.Headers["content-type"], "application/json") {
-		return h.handleJSONRPC(ctx, req)
+	// Handle JSON-RPC A2A requests. The agent card stays public so clients
+	// can discover how to authenticate.
+	if req.Method == "POST" && strings.Contains(a2aTypes.HeaderValue(req.Headers, "Content-Type"), "application/json") {
+		var response Response
+		if h.isAdminRequest(req) {
+			response = h.handleAdminRequest(ctx, req)
+		} else {
+			principal, err := h.authenticate(ctx, req)
+			if err != nil {
+				a2aTypes.LoggerFromContext(ctx).Warn("request rejected", a2aTypes.LogKeyError, err)
+				return h.handleUnauthorized()
+			}
+			if h.authenticator.Enabled() {
+				ctx = a2aTypes.ContextWithPrincipal(ctx, principal)
+			}
+			response = h.handleJSONRPC(ctx, req)
+		}
+		if h.captureStore != nil {
+			h.captureExchange(ctx, req, response)
+		}
+		return response
 	}
 
 	// Default response for unsupported requests
@@ -65,26 +391,50 @@ func (h *Handler) HandleRequest(req Request) Response {
 
 // handleCORS handles CORS preflight requests
 func (h *Handler) handleCORS() Response {
+	allowHeaders := "Content-Type, Authorization"
+	// Browsers only send a custom API key header if the preflight allows it
+	if header := h.authenticator.APIKeyHeader(); header != "" {
+		allowHeaders += ", " + header
+	}
+
 	return Response{
 		Status: http.StatusOK,
 		Headers: map[string]string{
 			"Access-Control-Allow-Origin":  "*",
 			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
-			"Access-Control-Allow-Headers": "Content-Type, Authorization",
+			"Access-Control-Allow-Headers": allowHeaders,
 			"Access-Control-Max-Age":       "86400",
 		},
 		Body: "",
 	}
 }
 
-// handleAgentCard returns the agent card
-func (h *Handler) handleAgentCard() Response {
-	cardBytes, err := json.Marshal(h.agentCard)
-	if err != nil {
+// handleUnauthorized rejects a request without an acceptable credential.
+// The reason is only logged, so callers cannot probe which check failed.
+func (h *Handler) handleUnauthorized() Response {
+	response := h.HandleError("Unauthorized", http.StatusUnauthorized)
+	if h.authenticator.AcceptsBearer() {
+		response.Headers["WWW-Authenticate"] = "Bearer"
+	}
+	return response
+}
+
+// handleAgentCard returns the agent card, without any credentials a
+// configured URL carries. Skills restricted to some callers are left off;
+// they are listed on the extended card for callers who may use them.
+// With WithLocalization, its text is in the locale the caller's Accept-Language
+// best matches.
+func (h *Handler) handleAgentCard(req Request) Response {
+	published := h.card.Load()
+	if published.body == "" {
 		return h.HandleError("Failed to serialize agent card", http.StatusInternalServerError)
 	}
 
-	return Response{
+	body, language := published.body, h.localization.DefaultLocale
+	if locale := h.localization.Negotiate(req.Headers); locale != "" {
+		body, language = published.localized[locale], locale
+	}
+	response := Response{
 		Status: http.StatusOK,
 		Headers: map[string]string{
 			"Content-Type":                 "application/json",
@@ -92,25 +442,108 @@ func (h *Handler) handleAgentCard() Response {
 			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
 			"Access-Control-Allow-Headers": "Content-Type, Authorization",
 		},
-		Body: string(cardBytes),
+		Body: body,
 	}
+	// Caches must key the card on the header it was chosen by
+	if len(h.localization.Locales) > 0 {
+		response.Headers["Vary"] = "Accept-Language"
+	}
+	if language != "" {
+		response.Headers["Content-Language"] = language
+	}
+	return response
+}
+
+// PublicAgentCard returns the card a handler serves anonymous callers: the
+// skills they may use, and a pointer to the extended card when some are
+// left off
+func PublicAgentCard(agentCard a2a.AgentCard, authenticator *a2aTypes.Authenticator) a2a.AgentCard {
+	card := agentCard
+	card.Skills = authenticator.SkillsFor(a2aTypes.Principal{}, agentCard.Skills)
+	if len(card.Skills) < len(agentCard.Skills) {
+		supported := true
+		card.SupportsAuthenticatedExtendedCard = &supported
+	}
+	return a2aTypes.RedactAgentCard(card)
 }
 
 // handleJSONRPC handles JSON-RPC A2A protocol requests
 func (h *Handler) handleJSONRPC(ctx context.Context, req Request) Response {
+	// One pooled copy of the body serves every read of it below. Decoded
+	// params are copied out of it, so it is returned once the request is done.
+	body := a2aTypes.GetBuffer()
+	defer a2aTypes.PutBuffer(body)
+	body.WriteString(req.Body)
+
 	var jsonrpcReq a2aTypes.JSONRPCRequest
-	err := json.Unmarshal([]byte(req.Body), &jsonrpcReq)
+	err := json.Unmarshal(body.Bytes(), &jsonrpcReq)
 	if err != nil {
-		return h.handleJSONRPCError(-32700, "Parse error", nil, nil)
+		// JSON that is not a request object, such as one whose method is a
+		// number, is an invalid request; its id is echoed when it can be read
+		if json.Valid(body.Bytes()) {
+			return h.handleJSONRPCError(ctx, -32600, "Invalid Request", err.Error(), a2aTypes.ExtractRequestID(body.Bytes()))
+		}
+		a2aTypes.LoggerFromContext(ctx).Warn("unparseable JSON-RPC request", a2aTypes.LogKeyError, err)
+		return h.handleJSONRPCError(ctx, -32700, "Parse error", nil, nil)
+	}
+
+	if h.strictJSONRPC {
+		if err := a2aTypes.CheckStrictJSONRPCRequest(body.Bytes()); err != nil {
+			return h.handleJSONRPCError(ctx, -32600, "Invalid Request", err, a2aTypes.ResponseID(jsonrpcReq.ID))
+		}
 	}
 
 	// Validate JSON-RPC request
 	err = a2aTypes.ValidateJSONRPCRequest(jsonrpcReq)
 	if err != nil {
-		return h.handleJSONRPCError(-32600, "Invalid Request", err.Error(), jsonrpcReq.ID)
+		return h.handleJSONRPCError(ctx, -32600, "Invalid Request", err.Error(), a2aTypes.ResponseID(jsonrpcReq.ID))
+	}
+	if response, refused := h.negotiateVersion(ctx, req, &jsonrpcReq); refused {
+		return response
+	}
+
+	// Metrics and spans get the bounded method, since each distinct value is a
+	// new CloudWatch dimension; logs keep what the client sent
+	method := h.boundedMethod(jsonrpcReq.Method)
+	ctx = context.WithValue(ctx, methodContextKey{}, method)
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyMethod, jsonrpcReq.Method)
+	a2aTypes.MetricsFromContext(ctx).RecordRequest(method)
+	a2aTypes.LoggerFromContext(ctx).Debug("handling JSON-RPC request")
+
+	// Failures are recorded on the span by handleServerError
+	ctx, endSpan := h.tracing.StartSpan(ctx, method, trace.SpanKindServer,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+	)
+	defer endSpan(nil)
+
+	if h.auditLog != nil {
+		ctx = h.startAudit(ctx, req.RequestID, method)
+		defer h.writeAudit(ctx)
 	}
 
-	// Route to appropriate A2A method
+	if response, denied := h.denyWrongEndpoint(ctx, jsonrpcReq); denied {
+		return response
+	}
+	// The admin API has its own credential, so neither the method policies
+	// nor the quotas of the agent's callers apply to it
+	if onAdminEndpoint(ctx) {
+		return h.interceptJSONRPC(ctx, req, jsonrpcReq)
+	}
+	if response, denied := h.denyMethod(ctx, jsonrpcReq.Method); denied {
+		return response
+	}
+	if h.quotas != nil {
+		if response, refused := h.chargeQuota(ctx, jsonrpcReq, len(req.Body)); refused {
+			return response
+		}
+	}
+
+	return h.interceptJSONRPC(ctx, req, jsonrpcReq)
+}
+
+// routeJSONRPC dispatches an authorized call to the handler of its method
+func (h *Handler) routeJSONRPC(ctx context.Context, req Request, jsonrpcReq a2aTypes.JSONRPCRequest) Response {
 	switch jsonrpcReq.Method {
 	case "tasks/get":
 		return h.handleGetTask(ctx, jsonrpcReq)
@@ -118,25 +551,60 @@ func (h *Handler) handleJSONRPC(ctx context.Context, req Request) Response {
 		return h.handleCancelTask(ctx, jsonrpcReq)
 	case "message/send":
 		return h.handleSendMessage(ctx, jsonrpcReq)
+	case "message/stream":
+		return h.handleSendMessageStream(ctx, jsonrpcReq)
+	case "tasks/resubscribe":
+		return h.handleResubscribe(ctx, jsonrpcReq, req.Headers)
+	case pushConfigMethodPrefix + "set", pushConfigMethodPrefix + "get", pushConfigMethodPrefix + "list", pushConfigMethodPrefix + "delete", pushDeliveriesMethod:
+		return h.handlePushConfig(ctx, jsonrpcReq)
+	case "admin/audit/list":
+		return h.handleListAudit(ctx, jsonrpcReq)
+	case captureMethod:
+		return h.handleGetCapture(ctx, jsonrpcReq)
+	case purgeMethod:
+		return h.handlePurge(ctx, jsonrpcReq)
+	case quotaGetMethod, quotaResetMethod:
+		return h.handleQuota(ctx, jsonrpcReq)
+	case notificationReplayMethod:
+		return h.handleNotificationReplay(ctx, jsonrpcReq)
+	case statsMethod:
+		return h.handleStats(ctx, jsonrpcReq)
+	case taskListMethod:
+		return h.handleListAllTasks(ctx, jsonrpcReq)
+	case taskExportMethod:
+		return h.handleExportTask(ctx, jsonrpcReq)
+	case configReloadMethod:
+		return h.handleConfigReload(ctx, jsonrpcReq)
+	case extendedCardMethod:
+		return h.handleExtendedCard(ctx, jsonrpcReq)
 	default:
-		return h.handleJSONRPCError(-32601, "Method not found", jsonrpcReq.Method, jsonrpcReq.ID)
+		if fn := h.methods[jsonrpcReq.Method]; fn != nil {
+			return h.handleRegisteredMethod(ctx, fn, jsonrpcReq)
+		}
+		return h.handleJSONRPCError(ctx, -32601, "Method not found", jsonrpcReq.Method, jsonrpcReq.ID)
 	}
 }
 
 // handleGetTask handles the tasks/get method
 func (h *Handler) handleGetTask(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
 	var params a2a.TaskQueryParams
-	if req.Params != nil {
-		paramsBytes, _ := json.Marshal(req.Params)
-		err := json.Unmarshal(paramsBytes, &params)
-		if err != nil {
-			return h.handleJSONRPCError(-32602, "Invalid params", err.Error(), req.ID)
-		}
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
 	}
+	if err := a2aTypes.ValidateTaskQueryParams(params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+	}
+
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyTaskID, params.ID)
+	a2aTypes.AnnotateSpan(ctx, a2aTypes.SpanAttrTaskID.String(string(params.ID)))
+	auditTaskID(ctx, string(params.ID))
 
 	task, err := h.a2aHandler.OnGetTask(ctx, params)
 	if err != nil {
-		return h.handleJSONRPCError(-32000, "Server error", err.Error(), req.ID)
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	if response, denied := h.denyTask(ctx, task, req.ID); denied {
+		return response
 	}
 
 	return h.handleJSONRPCSuccess(task, req.ID)
@@ -145,17 +613,23 @@ func (h *Handler) handleGetTask(ctx context.Context, req a2aTypes.JSONRPCRequest
 // handleCancelTask handles the tasks/cancel method
 func (h *Handler) handleCancelTask(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
 	var params a2a.TaskIDParams
-	if req.Params != nil {
-		paramsBytes, _ := json.Marshal(req.Params)
-		err := json.Unmarshal(paramsBytes, &params)
-		if err != nil {
-			return h.handleJSONRPCError(-32602, "Invalid params", err.Error(), req.ID)
-		}
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+	}
+	if err := a2aTypes.ValidateTaskIDParams(params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
 	}
 
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyTaskID, params.ID)
+	a2aTypes.AnnotateSpan(ctx, a2aTypes.SpanAttrTaskID.String(string(params.ID)))
+	auditTaskID(ctx, string(params.ID))
+
+	if response, denied := h.denyTaskID(ctx, params.ID, req.ID); denied {
+		return response
+	}
 	task, err := h.a2aHandler.OnCancelTask(ctx, params)
 	if err != nil {
-		return h.handleJSONRPCError(-32000, "Server error", err.Error(), req.ID)
+		return h.handleServerError(ctx, err, req.ID)
 	}
 
 	return h.handleJSONRPCSuccess(task, req.ID)
@@ -163,18 +637,35 @@ func (h *Handler) handleCancelTask(ctx context.Context, req a2aTypes.JSONRPCRequ
 
 // handleSendMessage handles the message/send method
 func (h *Handler) handleSendMessage(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
-	var params a2a.MessageSendParams
-	if req.Params != nil {
-		paramsBytes, _ := json.Marshal(req.Params)
-		err := json.Unmarshal(paramsBytes, &params)
-		if err != nil {
-			return h.handleJSONRPCError(-32602, "Invalid params", err.Error(), req.ID)
-		}
+	params, err := a2aTypes.DecodeMessageSendParams(req.Params)
+	if err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
 	}
 
+	if params.Message.TaskID != nil {
+		if response, denied := h.denyTaskID(ctx, *params.Message.TaskID, req.ID); denied {
+			return response
+		}
+	}
+	if response, denied := h.denySkill(ctx, params.Message); denied {
+		return response
+	}
 	result, err := h.a2aHandler.OnSendMessage(ctx, params)
 	if err != nil {
-		return h.handleJSONRPCError(-32000, "Server error", err.Error(), req.ID)
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	switch r := result.(type) {
+	case a2a.Task:
+		// A duplicate message is answered with an earlier task, which is
+		// checked like any other task returned
+		if response, denied := h.denyTask(ctx, r, req.ID); denied {
+			return response
+		}
+		auditTaskID(ctx, string(r.ID))
+	case a2a.Message:
+		if r.TaskID != nil {
+			auditTaskID(ctx, string(*r.TaskID))
+		}
 	}
 
 	return h.handleJSONRPCSuccess(result, req.ID)
@@ -182,8 +673,17 @@ func (h *Handler) handleSendMessage(ctx context.Context, req a2aTypes.JSONRPCReq
 
 // handleJSONRPCSuccess creates a successful JSON-RPC response
 func (h *Handler) handleJSONRPCSuccess(result interface{}, id interface{}) Response {
-	response := a2aTypes.NewJSONRPCResponse(result, id)
-	responseBytes, _ := json.Marshal(response)
+	// Encoded straight into a pooled buffer, since results such as long task
+	// histories would otherwise be held twice more while the body is built
+	buf := a2aTypes.GetBuffer()
+	defer a2aTypes.PutBuffer(buf)
+	// A task's owner is checked above, never sent
+	if task, ok := result.(a2a.Task); ok {
+		result = a2aTypes.PublicTask(task)
+	}
+	if err := a2aTypes.WriteJSONRPCResponse(buf, a2aTypes.NewJSONRPCResponse(result, id)); err != nil {
+		return h.HandleError("Failed to serialize response", http.StatusInternalServerError)
+	}
 
 	return Response{
 		Status: http.StatusOK,
@@ -193,14 +693,37 @@ func (h *Handler) handleJSONRPCSuccess(result interface{}, id interface{}) Respo
 			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
 			"Access-Control-Allow-Headers": "Content-Type, Authorization",
 		},
-		Body: string(responseBytes),
+		Body: string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))),
+	}
+}
+
+// handleServerError logs and reports a failed A2A call, marks its span failed
+// and returns a server error response. Errors the A2A spec assigns a code to
+// get that code instead.
+func (h *Handler) handleServerError(ctx context.Context, err error, id interface{}) Response {
+	// Protocol errors such as an unknown task are the caller's, not ours
+	if code, message, ok := a2aTypes.A2AErrorCode(err); ok {
+		a2aTypes.LoggerFromContext(ctx).Warn("request rejected", a2aTypes.LogKeyError, err)
+		return h.handleJSONRPCError(ctx, code, message, err.Error(), id)
 	}
+	a2aTypes.LoggerFromContext(ctx).Error("request failed", a2aTypes.LogKeyError, err)
+	a2aTypes.ReportError(ctx, err)
+	a2aTypes.RecordSpanError(ctx, err)
+	return h.handleJSONRPCError(ctx, -32000, "Server error", err.Error(), id)
 }
 
-// handleJSONRPCError creates an error JSON-RPC response
-func (h *Handler) handleJSONRPCError(code int, message string, data interface{}, id interface{}) Response {
-	response := a2aTypes.NewJSONRPCErrorResponse(code, message, data, id)
-	responseBytes, _ := json.Marshal(response)
+// handleJSONRPCError creates an error JSON-RPC response and counts it against
+// the request's method. data often quotes the request, so it is sanitized
+// and cut short before it is echoed.
+func (h *Handler) handleJSONRPCError(ctx context.Context, code int, message string, data interface{}, id interface{}) Response {
+	a2aTypes.MetricsFromContext(ctx).RecordError(methodFromContext(ctx), code)
+	if record, ok := ctx.Value(auditContextKey{}).(*a2aTypes.AuditRecord); ok {
+		record.Outcome = a2aTypes.AuditOutcomeError
+		record.ErrorCode = code
+	}
+
+	response := a2aTypes.NewJSONRPCErrorResponse(code, message, a2aTypes.SanitizeErrorData(data), id)
+	responseBody, _ := a2aTypes.MarshalJSONString(response)
 
 	return Response{
 		Status: http.StatusOK, // JSON-RPC errors still return 200 OK
@@ -210,7 +733,7 @@ func (h *Handler) handleJSONRPCError(code int, message string, data interface{},
 			"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
 			"Access-Control-Allow-Headers": "Content-Type, Authorization",
 		},
-		Body: string(responseBytes),
+		Body: responseBody,
 	}
 }
 
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// MethodCall is a JSON-RPC call as interceptors see it
+type MethodCall struct {
+	// Method is the method called
+	Method string
+	// Params are the call's params as sent, nil when it has none. A before
+	// hook may replace them, such as to fill in defaults, and the method
+	// then reads the replacement.
+	Params json.RawMessage
+	// ID is the call's JSON-RPC id
+	ID interface{}
+	// Principal is the authenticated caller; its Scheme is empty when the
+	// agent has no authentication
+	Principal a2aTypes.Principal
+	// Admin reports a call that came in on the admin API
+	Admin bool
+}
+
+// MethodResult is how a call ended, as after hooks see it
+type MethodResult struct {
+	// Status is the HTTP status of the answer. A call refused outside
+	// JSON-RPC, such as an admin method called without admin rights, has
+	// neither Result nor Error.
+	Status int
+	// Result is the serialized result of a call that succeeded. Result and
+	// Error are both nil when the answer was streamed as Server-Sent Events.
+	Result json.RawMessage
+	// Error is the JSON-RPC error answered, nil when the call succeeded
+	Error *a2aTypes.JSONRPCError
+	// Duration is how long the method ran, excluding the hooks; zero when a
+	// before hook refused the call
+	Duration time.Duration
+}
+
+// BeforeMethodHook runs before a call's method. Returning an error refuses
+// the call, which is answered as an error of a registered method is.
+type BeforeMethodHook func(ctx context.Context, call *MethodCall) error
+
+// AfterMethodHook runs once a call is answered. Replacing result.Result or
+// result.Error changes the answer sent, except for streamed answers.
+type AfterMethodHook func(ctx context.Context, call MethodCall, result *MethodResult)
+
+// OnBeforeMethod runs hook before every JSON-RPC method, built in or
+// registered, once the call is authenticated, authorized and charged to its
+// quota. Hooks run in the order added, and the first error stops the call.
+// Hooks are added before the handler serves requests.
+func (h *Handler) OnBeforeMethod(hook BeforeMethodHook) {
+	h.beforeMethod = append(h.beforeMethod, hook)
+}
+
+// OnAfterMethod runs hook after every call OnBeforeMethod hooks see,
+// including those a before hook refused. Hooks run in the order added, each
+// seeing the changes of the one before. Hooks are added before the handler
+// serves requests.
+func (h *Handler) OnAfterMethod(hook AfterMethodHook) {
+	h.afterMethod = append(h.afterMethod, hook)
+}
+
+// interceptJSONRPC runs the interceptors around routeJSONRPC
+func (h *Handler) interceptJSONRPC(ctx context.Context, req Request, jsonrpcReq a2aTypes.JSONRPCRequest) Response {
+	if len(h.beforeMethod) == 0 && len(h.afterMethod) == 0 {
+		return h.routeJSONRPC(ctx, req, jsonrpcReq)
+	}
+
+	params, err := rawParams(jsonrpcReq)
+	if err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err.Error(), jsonrpcReq.ID)
+	}
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	call := MethodCall{
+		Method:    jsonrpcReq.Method,
+		Params:    params,
+		ID:        jsonrpcReq.ID,
+		Principal: principal,
+		Admin:     onAdminEndpoint(ctx),
+	}
+
+	var response Response
+	var duration time.Duration
+	refused := false
+	for _, hook := range h.beforeMethod {
+		if err := hook(ctx, &call); err != nil {
+			response, refused = h.handleMethodError(ctx, err, jsonrpcReq.ID), true
+			break
+		}
+	}
+	if !refused {
+		jsonrpcReq.Params = nil
+		if call.Params != nil {
+			jsonrpcReq.Params = call.Params
+		}
+		start := time.Now()
+		response = h.routeJSONRPC(ctx, req, jsonrpcReq)
+		duration = time.Since(start)
+	}
+
+	if len(h.afterMethod) == 0 {
+		return response
+	}
+	return h.runAfterMethod(ctx, call, response, duration)
+}
+
+// runAfterMethod passes the answer to the after hooks, and re-encodes it
+// with what they leave in the result
+func (h *Handler) runAfterMethod(ctx context.Context, call MethodCall, response Response, duration time.Duration) Response {
+	result := MethodResult{Status: response.Status, Duration: duration}
+	// Streamed answers and HTTP refusals carry no JSON-RPC response
+	answered := response.Status == http.StatusOK && response.Headers["Content-Type"] == "application/json"
+	var decoded struct {
+		Result json.RawMessage        `json:"result"`
+		Error  *a2aTypes.JSONRPCError `json:"error"`
+		ID     json.RawMessage        `json:"id"`
+	}
+	if answered {
+		if err := json.Unmarshal([]byte(response.Body), &decoded); err != nil {
+			answered = false
+		}
+		result.Result, result.Error = decoded.Result, decoded.Error
+	}
+
+	for _, hook := range h.afterMethod {
+		hook(ctx, call, &result)
+	}
+
+	if !answered {
+		return response
+	}
+	if result.Error != nil {
+		body, err := a2aTypes.MarshalJSONString(a2aTypes.NewJSONRPCErrorResponse(result.Error.Code, result.Error.Message, result.Error.Data, decoded.ID))
+		if err != nil {
+			return h.HandleError("Failed to serialize response", http.StatusInternalServerError)
+		}
+		response.Body = body
+		return response
+	}
+	return h.handleJSONRPCSuccess(result.Result, decoded.ID)
+}
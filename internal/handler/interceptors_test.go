@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestMethodInterceptors(t *testing.T) {
+	h := newTestHandler(nil)
+	var seen []string
+	var results []MethodResult
+
+	// Billing refuses calls without an account; validation fills in a default
+	h.OnBeforeMethod(func(ctx context.Context, call *MethodCall) error {
+		seen = append(seen, "before:"+call.Method)
+		if call.Method == "tasks/cancel" {
+			return &a2aTypes.JSONRPCError{Code: -32050, Message: "No billing account"}
+		}
+		return nil
+	})
+	h.OnBeforeMethod(func(ctx context.Context, call *MethodCall) error {
+		if call.Method == "tasks/get" && call.Params == nil {
+			call.Params = json.RawMessage(`{"ID":"task-1"}`)
+		}
+		return nil
+	})
+	h.OnAfterMethod(func(ctx context.Context, call MethodCall, result *MethodResult) {
+		seen = append(seen, "after:"+call.Method)
+		results = append(results, *result)
+	})
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `null`, nil))
+	if !strings.Contains(response.Body, `"ID":"task-1"`) {
+		t.Errorf("expected the method to read the params the hook filled in, got %s", response.Body)
+	}
+	if len(results) != 1 || !strings.Contains(string(results[0].Result), `"ID":"task-1"`) || results[0].Error != nil || results[0].Duration <= 0 {
+		t.Errorf("expected the after hook to see the result and duration, got %+v", results)
+	}
+
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/cancel", `{"ID":"task-1"}`, nil))
+	if !strings.Contains(response.Body, `"code":-32050`) {
+		t.Errorf("expected the hook's error, got %s", response.Body)
+	}
+	if results[1].Error == nil || results[1].Error.Code != -32050 || results[1].Duration != 0 {
+		t.Errorf("expected the after hook to see the refusal, got %+v", results[1])
+	}
+
+	if got := strings.Join(seen, ","); got != "before:tasks/get,after:tasks/get,before:tasks/cancel,after:tasks/cancel" {
+		t.Errorf("unexpected hook order %s", got)
+	}
+}
+
+func TestAfterMethodRewritesAnswer(t *testing.T) {
+	h := newTestHandler(nil)
+	h.OnAfterMethod(func(ctx context.Context, call MethodCall, result *MethodResult) {
+		if result.Error != nil {
+			return
+		}
+		var task map[string]any
+		json.Unmarshal(result.Result, &task)
+		task["Metadata"] = map[string]any{"billed": true}
+		result.Result, _ = json.Marshal(task)
+	})
+	h.OnAfterMethod(func(ctx context.Context, call MethodCall, result *MethodResult) {
+		// Storage details stay out of answers
+		if result.Error != nil && result.Error.Code == a2aTypes.JSONRPCErrorServerError {
+			result.Error = &a2aTypes.JSONRPCError{Code: result.Error.Code, Message: "Try again later"}
+		}
+	})
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, nil))
+	if !strings.Contains(response.Body, `"billed":true`) || !strings.Contains(response.Body, `"id":1`) {
+		t.Errorf("expected the rewritten result, got %s", response.Body)
+	}
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"missing"}`, nil))
+	if !strings.Contains(response.Body, `"message":"Try again later"`) || strings.Contains(response.Body, "failed to get task") {
+		t.Errorf("expected the rewritten error, got %s", response.Body)
+	}
+}
+
+func TestBeforeMethodServerError(t *testing.T) {
+	h := newTestHandler(nil)
+	h.OnBeforeMethod(func(ctx context.Context, call *MethodCall) error {
+		return errors.New("billing backend down")
+	})
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, nil))
+	if !strings.Contains(response.Body, `"code":-32000`) {
+		t.Errorf("expected a server error, got %s", response.Body)
+	}
+}
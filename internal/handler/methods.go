@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// MethodFunc serves a JSON-RPC method registered with RegisterMethod.
+// params holds the call's params as sent, nil when it has none;
+// a2aTypes.DecodeParams decodes them as strictly as the built-in methods
+// do. The result is the call's result.
+type MethodFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// RegisterMethod serves the JSON-RPC method name with fn, behind the same
+// authentication, method policies, quotas, audit, tracing and metrics as
+// the built-in methods. A method starting with admin/ is only served on the
+// admin API. An error fn returns is answered as:
+//   - itself, when it is a *a2aTypes.JSONRPCError
+//   - Invalid params, when it is a2aTypes.ValidationErrors, as DecodeParams returns
+//   - the A2A code of the SDK sentinel it wraps, such as a2a.ErrTaskNotFound
+//   - a server error otherwise
+//
+// Methods are registered before the handler serves requests. Registering a
+// built-in method, a name JSON-RPC reserves, or a method twice fails.
+func (h *Handler) RegisterMethod(name string, fn MethodFunc) error {
+	switch {
+	case name == "" || fn == nil:
+		return errors.New("a registered method needs a name and a function")
+	case strings.HasPrefix(name, "rpc."):
+		return fmt.Errorf("method %s: names starting with rpc. are reserved by JSON-RPC", name)
+	case knownMethods[name]:
+		return fmt.Errorf("method %s is built in", name)
+	case h.methods[name] != nil:
+		return fmt.Errorf("method %s is already registered", name)
+	}
+	if h.methods == nil {
+		h.methods = map[string]MethodFunc{}
+	}
+	h.methods[name] = fn
+	return nil
+}
+
+// handleRegisteredMethod serves a call to a method registered with
+// RegisterMethod
+func (h *Handler) handleRegisteredMethod(ctx context.Context, fn MethodFunc, req a2aTypes.JSONRPCRequest) Response {
+	params, err := rawParams(req)
+	if err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err.Error(), req.ID)
+	}
+
+	result, err := fn(ctx, params)
+	if err != nil {
+		return h.handleMethodError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(result, req.ID)
+}
+
+// handleMethodError answers an error returned by code the deployment adds,
+// a registered method or an interceptor, as RegisterMethod describes
+func (h *Handler) handleMethodError(ctx context.Context, err error, id interface{}) Response {
+	var rpcErr *a2aTypes.JSONRPCError
+	var validationErrs a2aTypes.ValidationErrors
+	switch {
+	case errors.As(err, &rpcErr):
+		return h.handleJSONRPCError(ctx, rpcErr.Code, rpcErr.Message, rpcErr.Data, id)
+	case errors.As(err, &validationErrs):
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", validationErrs, id)
+	default:
+		return h.handleServerError(ctx, err, id)
+	}
+}
+
+// rawParams returns the params of req as sent, nil when it has none
+func rawParams(req a2aTypes.JSONRPCRequest) (json.RawMessage, error) {
+	var params json.RawMessage
+	switch raw := req.Params.(type) {
+	case nil:
+	case json.RawMessage:
+		params = raw
+	default:
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		params = encoded
+	}
+	// A present but null params member is no params
+	if string(params) == "null" {
+		return nil, nil
+	}
+	return params, nil
+}
+
+// registeredMethods returns the registered methods of the admin API, or of
+// the agent's endpoint, in name order for the OpenAPI document
+func (h *Handler) registeredMethods(admin bool) []jsonRPCMethod {
+	var methods []jsonRPCMethod
+	for name := range h.methods {
+		if a2aTypes.IsAdminMethod(name) == admin {
+			methods = append(methods, jsonRPCMethod{name: name, summary: "Registered by the deployment"})
+		}
+	}
+	slices.SortFunc(methods, func(a, b jsonRPCMethod) int { return strings.Compare(a.name, b.name) })
+	return methods
+}
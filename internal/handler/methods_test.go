@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestRegisterMethod(t *testing.T) {
+	h := newTestHandler(nil)
+	WithAdminAPI(testAdminAPI)(h)
+
+	type echoParams struct {
+		Text string `json:"text"`
+	}
+	echo := func(ctx context.Context, params json.RawMessage) (any, error) {
+		var p echoParams
+		if err := a2aTypes.DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		switch p.Text {
+		case "custom":
+			return nil, a2aTypes.NewJSONRPCServerError(-32050, "Quota of the vendor exceeded", "try later")
+		case "missing":
+			return nil, fmt.Errorf("looking up: %w", a2a.ErrTaskNotFound)
+		case "broken":
+			return nil, errors.New("backend down")
+		}
+		return map[string]string{"echo": p.Text}, nil
+	}
+	if err := h.RegisterMethod("vendor/echo", echo); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := h.RegisterMethod("admin/vendor/flush", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return "flushed", nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		request    Request
+		expectBody string
+	}{
+		{name: "result", request: jsonRPCRequest("vendor/echo", `{"text":"hi"}`, nil), expectBody: `"result":{"echo":"hi"}`},
+		{name: "no params", request: jsonRPCRequest("vendor/echo", `null`, nil), expectBody: `"result":{"echo":""}`},
+		{name: "invalid params", request: jsonRPCRequest("vendor/echo", `{"txt":"hi"}`, nil), expectBody: `"code":-32602`},
+		{name: "JSON-RPC error", request: jsonRPCRequest("vendor/echo", `{"text":"custom"}`, nil), expectBody: `"code":-32050`},
+		{name: "A2A error", request: jsonRPCRequest("vendor/echo", `{"text":"missing"}`, nil), expectBody: `"code":-32001`},
+		{name: "server error", request: jsonRPCRequest("vendor/echo", `{"text":"broken"}`, nil), expectBody: `"code":-32000`},
+		{name: "admin method on the admin API", request: adminRPCRequest("admin/vendor/flush", `{}`), expectBody: `"result":"flushed"`},
+		{name: "admin method on the agent endpoint", request: jsonRPCRequest("admin/vendor/flush", `{}`, nil), expectBody: `"code":-32601`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := h.HandleRequest(context.Background(), tt.request)
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+		})
+	}
+
+	if h.boundedMethod("vendor/echo") != "vendor/echo" {
+		t.Error("expected the registered method to be a metric dimension")
+	}
+	paths := h.OpenAPI()["paths"].(map[string]any)
+	agentDescription := paths["/"].(map[string]any)["post"].(map[string]any)["description"].(string)
+	adminDescription := paths[a2aTypes.DefaultAdminPath].(map[string]any)["post"].(map[string]any)["description"].(string)
+	if !strings.Contains(agentDescription, "vendor/echo") || !strings.Contains(adminDescription, "admin/vendor/flush") {
+		t.Errorf("expected the OpenAPI document to list registered methods, got %q and %q", agentDescription, adminDescription)
+	}
+}
+
+func TestRegisterMethodRefusesConflicts(t *testing.T) {
+	h := newTestHandler(nil)
+	fn := func(ctx context.Context, params json.RawMessage) (any, error) { return nil, nil }
+	if err := h.RegisterMethod("vendor/echo", fn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"", "message/send", "rpc.discover", "vendor/echo"} {
+		if err := h.RegisterMethod(name, fn); err == nil {
+			t.Errorf("expected %q to be refused", name)
+		}
+	}
+	if err := h.RegisterMethod("vendor/other", nil); err == nil {
+		t.Error("expected a nil function to be refused")
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// notificationReplayMethod is the admin method re-delivering notifications
+// that failed every delivery
+const notificationReplayMethod = "admin/notifications/replay"
+
+// WithNotificationReplay serves admin/notifications/replay through replayer
+func WithNotificationReplay(replayer a2aTypes.NotificationReplayer) Option {
+	return func(h *Handler) {
+		h.notificationReplayer = replayer
+	}
+}
+
+// handleNotificationReplay handles the admin/notifications/replay method,
+// served only on the admin API. Only notifications of tasks
+// this agent has are replayed, since agents may share a dead-letter queue.
+func (h *Handler) handleNotificationReplay(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	if h.notificationReplayer == nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "notification replay is not enabled here", req.ID)
+	}
+
+	var params a2aTypes.NotificationReplayParams
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if err := params.Validate(); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if params.TaskID != "" {
+		auditTaskID(ctx, string(params.TaskID))
+	}
+
+	// A dead-letter queue holds few tasks, so each is looked up once
+	owned := map[a2a.TaskID]bool{}
+	report, err := h.notificationReplayer.ReplayNotifications(ctx, params, func(ctx context.Context, taskID a2a.TaskID) bool {
+		if known, ok := owned[taskID]; ok {
+			return known
+		}
+		_, err := h.a2aHandler.OnGetTask(ctx, a2a.TaskQueryParams{ID: taskID})
+		owned[taskID] = err == nil
+		return owned[taskID]
+	})
+	logger := a2aTypes.LoggerFromContext(ctx).With("replayed", len(report.Replayed), "scanned", report.Scanned, "dry_run", params.DryRun)
+	if err != nil {
+		logger.Warn("notification replay stopped part way")
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	logger.Info("notifications replayed")
+	return h.handleJSONRPCSuccess(report, req.ID)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// fakeReplayer replays the notifications of the tasks it holds that are
+// owned by the caller
+type fakeReplayer struct {
+	tasks []a2a.TaskID
+}
+
+func (r *fakeReplayer) ReplayNotifications(ctx context.Context, params a2aTypes.NotificationReplayParams, owned func(ctx context.Context, taskID a2a.TaskID) bool) (a2aTypes.NotificationReplayReport, error) {
+	report := a2aTypes.NotificationReplayReport{Replayed: []a2aTypes.ReplayedNotification{}, DryRun: params.DryRun}
+	for _, taskID := range r.tasks {
+		report.Scanned++
+		if (params.TaskID == "" || taskID == params.TaskID) && owned(ctx, taskID) {
+			report.Replayed = append(report.Replayed, a2aTypes.ReplayedNotification{NotificationID: "msg-" + string(taskID), TaskID: taskID})
+		}
+	}
+	return report, nil
+}
+
+func TestHandleNotificationReplay(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, discardEventStore{}, nil)
+	card := agentcard.New("Test Agent", "https://agent.example.com")
+	replayer := &fakeReplayer{tasks: []a2a.TaskID{"task-1", "task-other-agent", "task-1"}}
+
+	tests := []struct {
+		name     string
+		replayer a2aTypes.NotificationReplayer
+		request  Request
+		expect   []string
+	}{
+		{name: "nothing selected", replayer: replayer, request: adminRPCRequest(notificationReplayMethod, `{"limit":5}`), expect: []string{`"code":-32602`}},
+		{name: "by task", replayer: replayer, request: adminRPCRequest(notificationReplayMethod, `{"task_id":"task-1"}`), expect: []string{`"notification_id":"msg-task-1"`, `"scanned":3`}},
+		{name: "other agent's task", replayer: replayer, request: adminRPCRequest(notificationReplayMethod, `{"task_id":"task-other-agent"}`), expect: []string{`"replayed":[]`}},
+		{name: "by time range", replayer: replayer, request: adminRPCRequest(notificationReplayMethod, `{"since":"2026-01-01T00:00:00Z","dry_run":true}`), expect: []string{`"task_id":"task-1"`, `"dry_run":true`}},
+		{name: "until before since", replayer: replayer, request: adminRPCRequest(notificationReplayMethod, `{"since":"2026-01-02T00:00:00Z","until":"2026-01-01T00:00:00Z"}`), expect: []string{`"path":"until"`}},
+		{name: "not served on the agent endpoint", replayer: replayer, request: jsonRPCRequest(notificationReplayMethod, `{"task_id":"task-1"}`, key), expect: []string{`"code":-32601`}},
+		{name: "not enabled", request: adminRPCRequest(notificationReplayMethod, `{"task_id":"task-1"}`), expect: []string{`"code":-32601`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithAuthenticator(authenticator), WithAdminAPI(testAdminAPI)}
+			if tt.replayer != nil {
+				opts = append(opts, WithNotificationReplay(tt.replayer))
+			}
+			h := NewHandler(a2aHandler, card, opts...)
+			response := h.HandleRequest(context.Background(), tt.request)
+			for _, expect := range tt.expect {
+				if !strings.Contains(response.Body, expect) {
+					t.Errorf("expected %s, got %s", expect, response.Body)
+				}
+			}
+			if strings.Contains(response.Body, "task-other-agent") && !strings.Contains(response.Body, `"replayed":[]`) {
+				t.Errorf("expected another agent's notifications to be left alone, got %s", response.Body)
+			}
+		})
+	}
+}
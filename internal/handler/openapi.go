@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"cmp"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// OpenAPIPath is where cmd/server serves the OpenAPI document with -openapi
+const OpenAPIPath = "/openapi.json"
+
+// openAPIVersion is the OpenAPI version of the documents OpenAPI builds
+const openAPIVersion = "3.1.0"
+
+// jsonRPCMethod is a method routeJSONRPC dispatches, as the OpenAPI document
+// lists it
+type jsonRPCMethod struct {
+	name    string
+	summary string
+	// served reports whether the method is answered rather than refused as
+	// not enabled; nil means always
+	served func(h *Handler) bool
+}
+
+// agentMethods are the methods of the agent's endpoint
+var agentMethods = []jsonRPCMethod{
+	{name: "message/send", summary: "Send a message, creating or continuing a task"},
+	{name: "message/stream", summary: "Send a message and receive the task's events as Server-Sent Events", served: (*Handler).streamingEnabled},
+	{name: "tasks/get", summary: "Get a task and its history"},
+	{name: "tasks/cancel", summary: "Cancel a task"},
+	{name: "tasks/resubscribe", summary: "Receive a running task's events as Server-Sent Events", served: (*Handler).streamingEnabled},
+	{name: pushConfigMethodPrefix + "set", summary: "Set a task's push notification config", served: (*Handler).pushNotificationsEnabled},
+	{name: pushConfigMethodPrefix + "get", summary: "Get a task's push notification config", served: (*Handler).pushNotificationsEnabled},
+	{name: pushConfigMethodPrefix + "list", summary: "List a task's push notification configs", served: (*Handler).pushNotificationsEnabled},
+	{name: pushConfigMethodPrefix + "delete", summary: "Delete a task's push notification config", served: (*Handler).pushNotificationsEnabled},
+	{name: pushDeliveriesMethod, summary: "List the webhook deliveries of a task's notifications", served: func(h *Handler) bool {
+		return h.pushNotificationsEnabled() && h.deliveryLog != nil
+	}},
+	{name: extendedCardMethod, summary: "Get the agent card with the skills the caller may use"},
+}
+
+// adminMethods are the methods of the admin API
+var adminMethods = []jsonRPCMethod{
+	{name: "admin/audit/list", summary: "List audit log entries", served: func(h *Handler) bool {
+		_, ok := h.auditLog.(a2aTypes.AuditReader)
+		return ok
+	}},
+	{name: captureMethod, summary: "Get the captured exchange of a request", served: func(h *Handler) bool { return h.captureStore != nil }},
+	{name: purgeMethod, summary: "Delete tasks and their events"},
+	{name: quotaGetMethod, summary: "Get a caller's quota usage", served: func(h *Handler) bool { return h.quotas != nil }},
+	{name: quotaResetMethod, summary: "Reset a caller's quota usage", served: func(h *Handler) bool { return h.quotas != nil }},
+	{name: notificationReplayMethod, summary: "Replay failed notifications from the dead-letter queue", served: func(h *Handler) bool { return h.notificationReplayer != nil }},
+	{name: statsMethod, summary: "Get task and event counts", served: func(h *Handler) bool { return h.stats != nil }},
+	{name: taskListMethod, summary: "List every task, across contexts", served: func(h *Handler) bool { return h.a2aHandler.ListsTasks() }},
+	{name: taskExportMethod, summary: "Export a task with its events and push notification configs"},
+	{name: configReloadMethod, summary: "Load the agent's config again", served: func(h *Handler) bool { return h.reloadConfig != nil }},
+}
+
+// servedMethods returns the methods of methods h answers
+func (h *Handler) servedMethods(methods []jsonRPCMethod) []jsonRPCMethod {
+	var served []jsonRPCMethod
+	for _, method := range methods {
+		if method.served == nil || method.served(h) {
+			served = append(served, method)
+		}
+	}
+	return served
+}
+
+// OpenAPI returns an OpenAPI document describing the HTTP surface h serves:
+// the agent card paths, the JSON-RPC endpoint with the methods enabled on
+// it, and the admin API when it is on. It follows the card being served,
+// so it lists the card's security schemes and server URL.
+func (h *Handler) OpenAPI() map[string]any {
+	card := h.agentCard()
+	paths := map[string]any{}
+	for _, path := range h.cardPaths {
+		paths[path] = map[string]any{"get": h.agentCardOperation(path)}
+	}
+
+	components := map[string]any{
+		"schemas": map[string]any{
+			"AgentCard": map[string]any{
+				"type":        "object",
+				"description": "The A2A agent card",
+			},
+			"JSONRPCRequest":  jsonRPCRequestSchema(),
+			"JSONRPCResponse": jsonRPCResponseSchema(),
+		},
+	}
+	securitySchemes := openAPISecuritySchemes(card.SecuritySchemes)
+
+	agentEndpoint := jsonRPCOperation("callAgent", "Call the agent", append(h.servedMethods(agentMethods), h.registeredMethods(false)...), h.streamingEnabled())
+	if len(card.Security) > 0 {
+		agentEndpoint["security"] = card.Security
+	}
+	if h.authenticator.Enabled() {
+		agentEndpoint["responses"].(map[string]any)["401"] = map[string]any{"description": "No acceptable credential"}
+	}
+	paths["/"].(map[string]any)["post"] = agentEndpoint
+
+	if h.admin != nil {
+		securitySchemes["adminApiKey"] = map[string]any{"type": "apiKey", "in": "header", "name": h.admin.Header()}
+		adminEndpoint := jsonRPCOperation("callAdmin", "Call the admin API", append(h.servedMethods(adminMethods), h.registeredMethods(true)...), false)
+		adminEndpoint["security"] = []map[string][]string{{"adminApiKey": {}}}
+		adminEndpoint["responses"].(map[string]any)["401"] = map[string]any{"description": "No valid admin API key"}
+		paths[h.admin.Path()] = map[string]any{"post": adminEndpoint}
+	}
+	if len(securitySchemes) > 0 {
+		components["securitySchemes"] = securitySchemes
+	}
+
+	document := map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":       card.Name,
+			"description": card.Description,
+			"version":     cmp.Or(card.Version, "unversioned"),
+		},
+		"paths":      paths,
+		"components": components,
+	}
+	if card.URL != "" {
+		document["servers"] = []map[string]any{{"url": strings.TrimSuffix(a2aTypes.RedactAgentCard(card).URL, "/")}}
+	}
+	return document
+}
+
+// agentCardOperation describes serving the agent card at path
+func (h *Handler) agentCardOperation(path string) map[string]any {
+	response := map[string]any{
+		"description": "The agent card, without skills restricted to some callers",
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/AgentCard"}},
+		},
+	}
+	operation := map[string]any{
+		"summary":   "Get the agent card",
+		"responses": map[string]any{"200": response},
+	}
+	if path == "/.well-known/agent.json" {
+		operation["operationId"] = "getAgentCard"
+	}
+	if len(h.localization.Locales) > 0 {
+		operation["parameters"] = []map[string]any{{
+			"name":        "Accept-Language",
+			"in":          "header",
+			"description": "Locales to translate the card into, by preference",
+			"schema":      map[string]any{"type": "string"},
+		}}
+		response["headers"] = map[string]any{
+			"Content-Language": map[string]any{"schema": map[string]any{"type": "string"}},
+		}
+	}
+	return operation
+}
+
+// jsonRPCOperation describes a JSON-RPC endpoint serving methods
+func jsonRPCOperation(operationID, summary string, methods []jsonRPCMethod, streaming bool) map[string]any {
+	names := make([]string, len(methods))
+	var description strings.Builder
+	description.WriteString("A JSON-RPC 2.0 call. Methods:\n")
+	for i, method := range methods {
+		names[i] = method.name
+		description.WriteString("\n- `" + method.name + "`: " + method.summary)
+	}
+
+	requestSchema := map[string]any{
+		"allOf": []any{
+			map[string]any{"$ref": "#/components/schemas/JSONRPCRequest"},
+			map[string]any{"properties": map[string]any{"method": map[string]any{"enum": names}}},
+		},
+	}
+	content := map[string]any{
+		"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/JSONRPCResponse"}},
+	}
+	if streaming {
+		content["text/event-stream"] = map[string]any{
+			"schema": map[string]any{"type": "string", "description": "Server-Sent Events, each data line a JSONRPCResponse"},
+		}
+	}
+
+	return map[string]any{
+		"operationId": operationID,
+		"summary":     summary,
+		"description": description.String(),
+		"requestBody": map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": requestSchema}},
+		},
+		"responses": map[string]any{
+			"200": map[string]any{"description": "The JSON-RPC response, which may carry an error", "content": content},
+			"404": map[string]any{"description": "Not served: the request is not a JSON POST"},
+		},
+	}
+}
+
+// jsonRPCRequestSchema is the JSON-RPC 2.0 request envelope
+func jsonRPCRequestSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"jsonrpc", "method"},
+		"properties": map[string]any{
+			"jsonrpc": map[string]any{"const": "2.0"},
+			"id":      map[string]any{"type": []string{"string", "integer", "null"}},
+			"method":  map[string]any{"type": "string"},
+			"params":  map[string]any{"type": "object"},
+		},
+	}
+}
+
+// jsonRPCResponseSchema is the JSON-RPC 2.0 response envelope
+func jsonRPCResponseSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"jsonrpc", "id"},
+		"properties": map[string]any{
+			"jsonrpc": map[string]any{"const": "2.0"},
+			"id":      map[string]any{"type": []string{"string", "integer", "null"}},
+			"result":  map[string]any{},
+			"error": map[string]any{
+				"type":     "object",
+				"required": []string{"code", "message"},
+				"properties": map[string]any{
+					"code":    map[string]any{"type": "integer"},
+					"message": map[string]any{"type": "string"},
+					"data":    map[string]any{},
+				},
+			},
+		},
+	}
+}
+
+// openAPISecuritySchemes converts the card's security schemes, which carry
+// the a2a types, into OpenAPI security scheme objects. Schemes of other
+// types are left off.
+func openAPISecuritySchemes(schemes map[string]any) map[string]any {
+	converted := map[string]any{}
+	for name, scheme := range schemes {
+		var object map[string]any
+		switch scheme := scheme.(type) {
+		case a2a.APIKeySecurityScheme:
+			object = map[string]any{"type": "apiKey", "in": string(scheme.In), "name": scheme.Name}
+		case a2a.HTTPAuthSecurityScheme:
+			object = map[string]any{"type": "http", "scheme": scheme.Scheme}
+			if scheme.BearerFormat != nil {
+				object["bearerFormat"] = *scheme.BearerFormat
+			}
+		case a2a.OAuth2SecurityScheme:
+			object = map[string]any{"type": "oauth2", "flows": openAPIOAuthFlows(scheme.Flows)}
+		case a2a.OpenIDConnectSecurityScheme:
+			object = map[string]any{"type": "openIdConnect", "openIdConnectUrl": scheme.OpenIDConnectURL}
+		case a2a.MutualTLSSecurityScheme:
+			object = map[string]any{"type": "mutualTLS"}
+		default:
+			continue
+		}
+		converted[name] = object
+	}
+	return converted
+}
+
+// openAPIOAuthFlows converts OAuth 2.0 flows
+func openAPIOAuthFlows(flows a2a.OAuthFlows) map[string]any {
+	converted := map[string]any{}
+	if flow := flows.AuthzCode; flow != nil {
+		converted["authorizationCode"] = map[string]any{"authorizationUrl": flow.AuthzURL, "tokenUrl": flow.TokenURL, "scopes": scopesOrEmpty(flow.Scopes)}
+	}
+	if flow := flows.ClientCredentials; flow != nil {
+		converted["clientCredentials"] = map[string]any{"tokenUrl": flow.TokenURL, "scopes": scopesOrEmpty(flow.Scopes)}
+	}
+	if flow := flows.Implicit; flow != nil {
+		converted["implicit"] = map[string]any{"authorizationUrl": flow.AuthzURL, "scopes": scopesOrEmpty(flow.Scopes)}
+	}
+	if flow := flows.Password; flow != nil {
+		converted["password"] = map[string]any{"tokenUrl": flow.TokenURL, "scopes": scopesOrEmpty(flow.Scopes)}
+	}
+	return converted
+}
+
+// scopesOrEmpty keeps scopes an object, which OpenAPI requires even when empty
+func scopesOrEmpty(scopes map[string]string) map[string]string {
+	if scopes == nil {
+		return map[string]string{}
+	}
+	return scopes
+}
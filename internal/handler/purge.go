@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// purgeMethod is the admin method erasing a context's or a subject's data
+const purgeMethod = "admin/purge"
+
+// handlePurge handles the admin/purge method, served only on the admin
+// API. A purge that fails part way reports the error; running it
+// again finishes it.
+func (h *Handler) handlePurge(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	var query a2aTypes.PurgeQuery
+	if err := a2aTypes.DecodeParams(req.Params, &query); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if (query.ContextID == "") == (query.Subject == "") {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", "exactly one of context_id or subject is required", req.ID)
+	}
+
+	report, err := h.a2aHandler.Purge(ctx, query, a2aTypes.PurgeStores{AuditLog: h.auditLog, Captures: h.captureStore, Deliveries: h.deliveryLog})
+	logger := a2aTypes.LoggerFromContext(ctx).With("tasks", len(report.Tasks), "events", report.Events, "audit_records", report.AuditRecords)
+	if errors.Is(err, a2aTypes.ErrPurgeUnsupported) {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorUnsupportedOperation, "This operation is not supported", err.Error(), req.ID)
+	}
+	if err != nil {
+		logger.Warn("purge stopped part way")
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	logger.Info("purge completed")
+	return h.handleJSONRPCSuccess(report, req.ID)
+}
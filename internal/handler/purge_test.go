@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestHandleRequestPurge(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+
+	tests := []struct {
+		name         string
+		request      Request
+		expectStatus int
+		expectBody   string
+		expectTasks  int
+	}{
+		{name: "admin purges a context", request: adminRPCRequest("admin/purge", `{"context_id":"ctx-1"}`), expectStatus: http.StatusOK, expectBody: `"tasks":["task-1"],"artifacts":0,"events":0`, expectTasks: 1},
+		{name: "a subject needs the audit log", request: adminRPCRequest("admin/purge", `{"subject":"alice"}`), expectStatus: http.StatusOK, expectBody: "-32004", expectTasks: 2},
+		{name: "one selector required", request: adminRPCRequest("admin/purge", `{}`), expectStatus: http.StatusOK, expectBody: "exactly one of context_id or subject", expectTasks: 2},
+		{name: "not both", request: adminRPCRequest("admin/purge", `{"context_id":"ctx-1","subject":"alice"}`), expectStatus: http.StatusOK, expectBody: "exactly one of context_id or subject", expectTasks: 2},
+		{name: "not served on the agent endpoint", request: jsonRPCRequest("admin/purge", `{"context_id":"ctx-1"}`, key), expectStatus: http.StatusOK, expectBody: `"code":-32601`, expectTasks: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{
+				"task-1": {ID: "task-1", ContextID: "ctx-1"},
+				"task-2": {ID: "task-2", ContextID: "ctx-2"},
+			}}
+			a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, discardEventStore{}, nil)
+			h := NewHandler(a2aHandler, agentcard.New("Test Agent", "https://agent.example.com"), WithAuthenticator(authenticator), WithAdminAPI(testAdminAPI))
+
+			response := h.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+			if left := len(tasks.tasks); left != tt.expectTasks {
+				t.Errorf("expected %d tasks left, got %d", tt.expectTasks, left)
+			}
+		})
+	}
+}
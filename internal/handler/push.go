@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// pushConfigMethodPrefix starts every push notification config method
+const pushConfigMethodPrefix = "tasks/pushNotificationConfig/"
+
+// pushDeliveriesMethod lists a task's webhook delivery attempts. It is not
+// an A2A method, so clients of other servers will not find it.
+const pushDeliveriesMethod = pushConfigMethodPrefix + "deliveries"
+
+// WithDeliveryLog serves the webhook delivery attempts recorded in log
+// through the deliveries method
+func WithDeliveryLog(log a2aTypes.DeliveryLog) Option {
+	return func(h *Handler) {
+		h.deliveryLog = log
+	}
+}
+
+// pushNotificationsEnabled reports whether the agent card advertises push notifications
+func (h *Handler) pushNotificationsEnabled() bool {
+	push := h.agentCard().Capabilities.PushNotifications
+	return push != nil && *push
+}
+
+// handlePushConfig handles the tasks/pushNotificationConfig/* methods. An
+// agent whose card does not advertise push notifications answers them with
+// PushNotificationNotSupported rather than Method not found, as the spec asks.
+func (h *Handler) handlePushConfig(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if !h.pushNotificationsEnabled() {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorPushNotificationNotSupported, "Push Notification is not supported", "push notifications are not enabled on the agent card", req.ID)
+	}
+
+	var taskID a2a.TaskID
+	var call func() (interface{}, error)
+	switch req.Method {
+	case pushConfigMethodPrefix + "set":
+		var params a2a.TaskPushConfig
+		if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		taskID = params.TaskID
+		call = func() (interface{}, error) { return h.a2aHandler.OnSetTaskPushConfig(ctx, params) }
+	case pushConfigMethodPrefix + "get":
+		var params a2a.GetTaskPushConfigParams
+		if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		taskID = params.TaskID
+		call = func() (interface{}, error) { return h.a2aHandler.OnGetTaskPushConfig(ctx, params) }
+	case pushConfigMethodPrefix + "list":
+		var params a2a.ListTaskPushConfigParams
+		if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		taskID = params.TaskID
+		call = func() (interface{}, error) { return h.a2aHandler.OnListTaskPushConfig(ctx, params) }
+	case pushConfigMethodPrefix + "delete":
+		var params a2a.DeleteTaskPushConfigParams
+		if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		taskID = params.TaskID
+		// The spec's result is null, which an empty result would leave out
+		call = func() (interface{}, error) {
+			return json.RawMessage("null"), h.a2aHandler.OnDeleteTaskPushConfig(ctx, params)
+		}
+	case pushDeliveriesMethod:
+		if h.deliveryLog == nil {
+			return h.handleJSONRPCError(ctx, -32601, "Method not found", "delivery history is not recorded here", req.ID)
+		}
+		var params a2aTypes.TaskPushDeliveriesParams
+		if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		if err := params.Validate(); err != nil {
+			return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+		}
+		taskID = params.TaskID
+		// Attempts are kept by bare task ID, so the task must be this agent's
+		call = func() (interface{}, error) {
+			if _, err := h.a2aHandler.OnGetTask(ctx, a2a.TaskQueryParams{ID: params.TaskID}); err != nil {
+				return nil, err
+			}
+			return h.deliveryLog.ListDeliveries(ctx, params)
+		}
+	default:
+		return h.handleJSONRPCError(ctx, -32601, "Method not found", req.Method, req.ID)
+	}
+	if taskID == "" {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", a2aTypes.ValidationErrors{{Path: "TaskID", Code: a2aTypes.ValidationCodeRequired, Message: "is required"}}, req.ID)
+	}
+
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyTaskID, taskID)
+	a2aTypes.AnnotateSpan(ctx, a2aTypes.SpanAttrTaskID.String(string(taskID)))
+	auditTaskID(ctx, string(taskID))
+
+	if response, denied := h.denyTaskID(ctx, taskID, req.ID); denied {
+		return response
+	}
+	result, err := call()
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(result, req.ID)
+}
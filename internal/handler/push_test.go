@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestHandlePushConfigDisabled(t *testing.T) {
+	h := newTestHandler(nil)
+	for _, method := range []string{"set", "get", "list", "delete"} {
+		response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/pushNotificationConfig/"+method, `{"TaskID":"task-1"}`, nil))
+		if !strings.Contains(response.Body, `"code":-32003`) {
+			t.Errorf("expected %s to be refused as not supported, got %s", method, response.Body)
+		}
+	}
+}
+
+func TestHandlePushConfig(t *testing.T) {
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, discardEventStore{}, nil,
+		a2aTypes.WithPushConfigStore(a2aTypes.NewMemoryPushConfigStore()))
+	h := NewHandler(a2aHandler, agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithPushNotifications(true)))
+
+	tests := []struct {
+		name         string
+		method       string
+		params       string
+		expectResult string
+		expectCode   string
+	}{
+		{name: "set", method: "set", params: `{"TaskID":"task-1","Config":{"ID":"c-1","URL":"https://hooks.example.com/a2a"}}`, expectResult: `"URL":"https://hooks.example.com/a2a"`},
+		{name: "get", method: "get", params: `{"TaskID":"task-1","ConfigID":"c-1"}`, expectResult: `"URL":"https://hooks.example.com/a2a"`},
+		{name: "list", method: "list", params: `{"TaskID":"task-1"}`, expectResult: `"ID":"c-1"`},
+		{name: "delete", method: "delete", params: `{"TaskID":"task-1","ConfigID":"c-1"}`, expectResult: `"result":null`},
+		{name: "list after delete", method: "list", params: `{"TaskID":"task-1"}`, expectResult: `"result":[]`},
+		{name: "get after delete", method: "get", params: `{"TaskID":"task-1","ConfigID":"c-1"}`, expectCode: `"code":-32602`},
+		{name: "set on unknown task", method: "set", params: `{"TaskID":"task-2","Config":{"URL":"https://hooks.example.com/a2a"}}`, expectCode: `task task-2 not found`},
+		{name: "webhook not allowed", method: "set", params: `{"TaskID":"task-1","Config":{"URL":"http://10.0.0.1/hook"}}`, expectCode: `"code":-32602`},
+		{name: "missing task ID", method: "get", params: `{}`, expectCode: `"path":"TaskID"`},
+		{name: "unknown push method", method: "update", params: `{"TaskID":"task-1"}`, expectCode: `"code":-32601`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/pushNotificationConfig/"+tt.method, tt.params, nil))
+			if tt.expectCode != "" {
+				if !strings.Contains(response.Body, tt.expectCode) {
+					t.Errorf("expected %s, got %s", tt.expectCode, response.Body)
+				}
+				return
+			}
+			if strings.Contains(response.Body, `"error"`) || !strings.Contains(response.Body, tt.expectResult) {
+				t.Errorf("expected %s, got %s", tt.expectResult, response.Body)
+			}
+		})
+	}
+}
+
+// memoryDeliveryLog lists the attempts it holds, newest last
+type memoryDeliveryLog struct {
+	attempts []a2aTypes.DeliveryAttempt
+}
+
+func (l *memoryDeliveryLog) RecordDelivery(ctx context.Context, attempt a2aTypes.DeliveryAttempt) error {
+	l.attempts = append(l.attempts, attempt)
+	return nil
+}
+
+func (l *memoryDeliveryLog) ListDeliveries(ctx context.Context, params a2aTypes.TaskPushDeliveriesParams) ([]a2aTypes.DeliveryAttempt, error) {
+	attempts := []a2aTypes.DeliveryAttempt{}
+	for _, attempt := range slices.Backward(l.attempts) {
+		if attempt.TaskID == params.TaskID && (params.ConfigID == nil || attempt.ConfigID == *params.ConfigID) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+func TestHandlePushDeliveries(t *testing.T) {
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, discardEventStore{}, nil)
+	card := agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithPushNotifications(true))
+	deliveries := &memoryDeliveryLog{attempts: []a2aTypes.DeliveryAttempt{
+		{TaskID: "task-1", ConfigID: "c-1", URL: "https://hooks.example.com/a2a", Attempt: 1, StatusCode: 503, Error: "webhook returned 503"},
+		{TaskID: "task-1", ConfigID: "c-1", URL: "https://hooks.example.com/a2a", Attempt: 2, StatusCode: 200},
+		{TaskID: "task-1", ConfigID: "c-2", URL: "https://other.example.com/a2a", Attempt: 1, StatusCode: 204},
+		{TaskID: "task-2", ConfigID: "c-1", URL: "https://hooks.example.com/a2a", Attempt: 1, StatusCode: 200},
+	}}
+	h := NewHandler(a2aHandler, card, WithDeliveryLog(deliveries))
+
+	tests := []struct {
+		name         string
+		params       string
+		expectResult []string
+		expectCode   string
+	}{
+		{name: "all configs", params: `{"TaskID":"task-1"}`, expectResult: []string{`"status_code":204`, `"status_code":200`, `"error":"webhook returned 503"`}},
+		{name: "one config", params: `{"TaskID":"task-1","ConfigID":"c-2"}`, expectResult: []string{`"config_id":"c-2"`}},
+		{name: "unknown task", params: `{"TaskID":"task-2"}`, expectCode: `"error":{`},
+		{name: "missing task ID", params: `{}`, expectCode: `"path":"TaskID"`},
+		{name: "limit too large", params: `{"TaskID":"task-1","Limit":1000}`, expectCode: `"path":"Limit"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/pushNotificationConfig/deliveries", tt.params, nil))
+			if tt.expectCode != "" {
+				if !strings.Contains(response.Body, tt.expectCode) {
+					t.Errorf("expected %s, got %s", tt.expectCode, response.Body)
+				}
+				return
+			}
+			if strings.Contains(response.Body, `"error":{`) {
+				t.Fatalf("expected a result, got %s", response.Body)
+			}
+			last := -1
+			for _, expect := range tt.expectResult {
+				index := strings.Index(response.Body, expect)
+				if index <= last {
+					t.Errorf("expected %s after the previous attempt, got %s", expect, response.Body)
+				}
+				last = index
+			}
+			if strings.Count(response.Body, `"task_id"`) != len(tt.expectResult) {
+				t.Errorf("expected %d attempts, got %s", len(tt.expectResult), response.Body)
+			}
+		})
+	}
+
+	withoutLog := NewHandler(a2aHandler, card)
+	response := withoutLog.HandleRequest(context.Background(), jsonRPCRequest("tasks/pushNotificationConfig/deliveries", `{"TaskID":"task-1"}`, nil))
+	if !strings.Contains(response.Body, `"code":-32601`) {
+		t.Errorf("expected Method not found without a delivery log, got %s", response.Body)
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// The admin methods reading and resetting quota usage
+const (
+	quotaGetMethod   = "admin/quota/get"
+	quotaResetMethod = "admin/quota/reset"
+)
+
+// WithQuotas charges every JSON-RPC call to the daily and monthly quotas of
+// the tenant and the caller, refusing calls once a quota is used up
+func WithQuotas(quotas *a2aTypes.Quotas) Option {
+	return func(h *Handler) {
+		h.quotas = quotas
+	}
+}
+
+// chargeQuota counts the call against the quotas: a request, and for a
+// message a task when it starts one and the bytes sent. Calls to the admin
+// API are never charged, so a quota can always be inspected and reset. A
+// call whose usage cannot be counted is served rather than refused.
+func (h *Handler) chargeQuota(ctx context.Context, req a2aTypes.JSONRPCRequest, bodySize int) (Response, bool) {
+	delta := a2aTypes.Usage{Requests: 1}
+	if req.Method == "message/send" || req.Method == "message/stream" {
+		delta.Bytes = int64(bodySize)
+		// Params that cannot be read are refused by the method itself
+		if params, err := a2aTypes.DecodeMessageSendParams(req.Params); err == nil && params.Message.TaskID == nil {
+			delta.Tasks = 1
+		}
+	}
+
+	principal, _ := a2aTypes.PrincipalFromContext(ctx)
+	err := h.quotas.Charge(ctx, principal.Subject, delta)
+	if errors.Is(err, a2aTypes.ErrQuotaExceeded) {
+		return h.handleServerError(ctx, err, req.ID), true
+	}
+	if err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("failed to charge quota", a2aTypes.LogKeyError, err)
+	}
+	return Response{}, false
+}
+
+// handleQuota handles the admin/quota/get and admin/quota/reset methods,
+// served only on the admin API
+func (h *Handler) handleQuota(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	if h.quotas == nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "quotas are not enabled here", req.ID)
+	}
+
+	var query a2aTypes.QuotaQuery
+	if err := a2aTypes.DecodeParams(req.Params, &query); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if err := query.Validate(); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+
+	var usages []a2aTypes.QuotaUsage
+	var err error
+	if req.Method == quotaResetMethod {
+		usages, err = h.quotas.Reset(ctx, query)
+		if err == nil {
+			a2aTypes.LoggerFromContext(ctx).Info("quota usage reset", "scope", query.Scope, "id", query.ID, "period", query.Period)
+		}
+	} else {
+		usages, err = h.quotas.Usage(ctx, query)
+	}
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(usages, req.ID)
+}
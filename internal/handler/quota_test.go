@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// memoryQuotaStore counts usage in memory, refusing charges past the
+// counters' request limits, or fails every charge with err
+type memoryQuotaStore struct {
+	usage map[a2aTypes.UsageKey]a2aTypes.Usage
+	err   error
+}
+
+func (s *memoryQuotaStore) AddUsage(ctx context.Context, counters []a2aTypes.UsageCounter, delta a2aTypes.Usage) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, counter := range counters {
+		if limit := counter.Limits.Requests; limit > 0 && s.usage[counter.Key].Requests+delta.Requests > limit {
+			return fmt.Errorf("%s: requests limit of %d: %w", counter.Key, limit, a2aTypes.ErrQuotaExceeded)
+		}
+	}
+	for _, counter := range counters {
+		usage := s.usage[counter.Key]
+		s.usage[counter.Key] = a2aTypes.Usage{Requests: usage.Requests + delta.Requests, Tasks: usage.Tasks + delta.Tasks, Bytes: usage.Bytes + delta.Bytes}
+	}
+	return nil
+}
+
+func (s *memoryQuotaStore) GetUsage(ctx context.Context, key a2aTypes.UsageKey) (a2aTypes.Usage, error) {
+	return s.usage[key], nil
+}
+
+func (s *memoryQuotaStore) ResetUsage(ctx context.Context, key a2aTypes.UsageKey) error {
+	delete(s.usage, key)
+	return nil
+}
+
+// callerUsage returns the usage counted for the caller in period
+func (s *memoryQuotaStore) callerUsage(period string) a2aTypes.Usage {
+	for key, usage := range s.usage {
+		if key.Scope == a2aTypes.QuotaScopeCaller && key.Period == period {
+			return usage
+		}
+	}
+	return a2aTypes.Usage{}
+}
+
+func TestHandleRequestQuota(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	store := &memoryQuotaStore{usage: map[a2aTypes.UsageKey]a2aTypes.Usage{}}
+	config := a2aTypes.QuotaConfig{Table: "quota", Caller: a2aTypes.QuotaPolicy{Daily: a2aTypes.QuotaLimits{Requests: 2}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, &memoryTaskStore{}, discardEventStore{}, nil)
+	h := NewHandler(a2aHandler, agentcard.New("Test Agent", "https://agent.example.com"), WithAuthenticator(authenticator),
+		WithQuotas(a2aTypes.NewQuotas(store, config, "agent")), WithAdminAPI(testAdminAPI))
+
+	// A message starting a task counts a request, a task and its bytes
+	send := jsonRPCRequest("message/send", `{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hello"}]}}`, key)
+	h.HandleRequest(context.Background(), send)
+	if usage := store.callerUsage(a2aTypes.QuotaPeriodDay); usage != (a2aTypes.Usage{Requests: 1, Tasks: 1, Bytes: int64(len(send.Body))}) {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, key))
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, key))
+	if !strings.Contains(response.Body, "-32010") || !strings.Contains(response.Body, "requests limit of 2") {
+		t.Errorf("expected the third call refused, got %s", response.Body)
+	}
+
+	// The admin API is not counted, so the quota can be read and reset
+	response = h.HandleRequest(context.Background(), adminRPCRequest(quotaGetMethod, `{"scope":"caller","id":"apiKey"}`))
+	if !strings.Contains(response.Body, `"period":"day"`) || !strings.Contains(response.Body, `"usage":{"requests":2,"tasks":1`) {
+		t.Errorf("expected the day's usage, got %s", response.Body)
+	}
+	response = h.HandleRequest(context.Background(), adminRPCRequest(quotaResetMethod, `{"scope":"caller","id":"apiKey","period":"day"}`))
+	if !strings.Contains(response.Body, `"usage":{"requests":0,"tasks":0,"bytes":0}`) {
+		t.Errorf("expected the day's usage cleared, got %s", response.Body)
+	}
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, key))
+	if strings.Contains(response.Body, "-32010") {
+		t.Errorf("expected calls served again after a reset, got %s", response.Body)
+	}
+
+	response = h.HandleRequest(context.Background(), adminRPCRequest(quotaGetMethod, `{"scope":"caller"}`))
+	if !strings.Contains(response.Body, "id is required") {
+		t.Errorf("expected invalid params, got %s", response.Body)
+	}
+
+	// A store that cannot count does not refuse calls
+	store.err = errors.New("throttled")
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, key))
+	if strings.Contains(response.Body, "-32010") || strings.Contains(response.Body, "throttled") {
+		t.Errorf("expected the call served, got %s", response.Body)
+	}
+}
+
+func TestHandleRequestQuotaAdmin(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, &memoryTaskStore{}, discardEventStore{}, nil)
+	card := agentcard.New("Test Agent", "https://agent.example.com")
+
+	quotas := a2aTypes.NewQuotas(&memoryQuotaStore{usage: map[a2aTypes.UsageKey]a2aTypes.Usage{}}, a2aTypes.QuotaConfig{Table: "quota"}, "agent")
+	response := NewHandler(a2aHandler, card, WithAuthenticator(authenticator), WithQuotas(quotas), WithAdminAPI(testAdminAPI)).HandleRequest(context.Background(), jsonRPCRequest(quotaResetMethod, `{"scope":"tenant"}`, key))
+	if response.Status != http.StatusOK || !strings.Contains(response.Body, `"code":-32601`) {
+		t.Errorf("expected the agent endpoint not to serve quota resets, got %d: %s", response.Status, response.Body)
+	}
+
+	response = NewHandler(a2aHandler, card, WithAuthenticator(authenticator), WithAdminAPI(testAdminAPI)).HandleRequest(context.Background(), adminRPCRequest(quotaGetMethod, `{"scope":"tenant"}`))
+	if !strings.Contains(response.Body, "quotas are not enabled here") {
+		t.Errorf("expected method not found without quotas, got %s", response.Body)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// configReloadMethod is the admin method loading the agent's config again
+const configReloadMethod = "admin/config/reload"
+
+// ConfigReloader loads the config again and applies it, failing when the
+// new config cannot be loaded or applied
+type ConfigReloader func(ctx context.Context) error
+
+// WithConfigReload serves admin/config/reload through reload
+func WithConfigReload(reload ConfigReloader) Option {
+	return func(h *Handler) {
+		h.reloadConfig = reload
+	}
+}
+
+// ConfigReloadResult is the result of admin/config/reload
+type ConfigReloadResult struct {
+	ReloadedAt time.Time `json:"reloaded_at"`
+}
+
+// handleConfigReload handles the admin/config/reload method, served only on
+// the admin API
+func (h *Handler) handleConfigReload(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	if h.reloadConfig == nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "the config is not reloadable here", req.ID)
+	}
+	if err := h.reloadConfig(ctx); err != nil {
+		a2aTypes.LoggerFromContext(ctx).Warn("config reload failed", a2aTypes.LogKeyError, err)
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	a2aTypes.LoggerFromContext(ctx).Info("config reloaded")
+	return h.handleJSONRPCSuccess(ConfigReloadResult{ReloadedAt: time.Now().UTC()}, req.ID)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestHandleConfigReload(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+
+	tests := []struct {
+		name      string
+		reloadErr error
+		disabled  bool
+		request   Request
+		expect    string
+		reloads   int
+	}{
+		{name: "reloads", request: adminRPCRequest(configReloadMethod, `{}`), expect: `"reloaded_at":`, reloads: 1},
+		{name: "failed reload reported", reloadErr: errors.New("invalid config"), request: adminRPCRequest(configReloadMethod, `{}`), expect: `"code":-32000`, reloads: 1},
+		{name: "not reloadable", disabled: true, request: adminRPCRequest(configReloadMethod, `{}`), expect: "the config is not reloadable here"},
+		{name: "not served on the agent endpoint", request: jsonRPCRequest(configReloadMethod, `{}`, key), expect: `"code":-32601`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloads := 0
+			h := newTestHandler(authenticator)
+			WithAdminAPI(testAdminAPI)(h)
+			if !tt.disabled {
+				WithConfigReload(func(ctx context.Context) error {
+					reloads++
+					return tt.reloadErr
+				})(h)
+			}
+			response := h.HandleRequest(context.Background(), tt.request)
+			if !strings.Contains(response.Body, tt.expect) {
+				t.Errorf("expected %s, got %s", tt.expect, response.Body)
+			}
+			if reloads != tt.reloads {
+				t.Errorf("expected %d reloads, got %d", tt.reloads, reloads)
+			}
+		})
+	}
+}
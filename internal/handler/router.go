@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// agentsPathPrefix is where a multi-agent deployment serves each agent
+const agentsPathPrefix = "/agents/"
+
+// Router serves every agent of a registry from one deployment. Each agent is
+// reached under /agents/{id} and handled by its own Handler, so cards,
+// credentials and storage never cross between agents.
+type Router struct {
+	handlers map[string]*Handler
+}
+
+// NewRouter creates a router over per-agent handlers keyed by agent ID
+func NewRouter(handlers map[string]*Handler) *Router {
+	return &Router{handlers: handlers}
+}
+
+// HandleRequest strips /agents/{id} from the URL and passes the request to
+// that agent's handler, which sees the same paths as a single-agent deployment
+func (r *Router) HandleRequest(ctx context.Context, req Request) Response {
+	agentID, rest, ok := splitAgentPath(req.URL)
+	if !ok {
+		return r.handleNotFound("Unsupported request")
+	}
+	h, ok := r.handlers[agentID]
+	if !ok {
+		return r.handleNotFound("Unknown agent")
+	}
+
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyAgentID, agentID)
+	req.URL = rest
+	return h.HandleRequest(ctx, req)
+}
+
+// handleNotFound answers paths that name no registered agent
+func (r *Router) handleNotFound(message string) Response {
+	// HandleError reads no handler state, and there is no agent to pick one from
+	return (&Handler{}).HandleError(message, http.StatusNotFound)
+}
+
+// splitAgentPath splits /agents/{id}/rest into the ID and /rest
+func splitAgentPath(path string) (string, string, bool) {
+	if !strings.HasPrefix(path, agentsPathPrefix) {
+		return "", "", false
+	}
+	agentID, rest, _ := strings.Cut(strings.TrimPrefix(path, agentsPathPrefix), "/")
+	if agentID == "" {
+		return "", "", false
+	}
+	return agentID, "/" + rest, true
+}
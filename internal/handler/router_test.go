@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestRouter(t *testing.T) {
+	// Only billing's store holds task-1, so a hit proves which agent answered
+	billingStore := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	supportStore := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{}}
+	supportAuth := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "support-key"}, nil)
+	router := NewRouter(map[string]*Handler{
+		"billing": NewHandler(
+			a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, billingStore, discardEventStore{}, nil),
+			agentcard.New("Billing", "https://agents.example.com/agents/billing")),
+		"support": NewHandler(
+			a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, supportStore, discardEventStore{}, nil),
+			agentcard.New("Support", "https://agents.example.com/agents/support"), WithAuthenticator(supportAuth)),
+	})
+	getTask := func(url string, headers map[string]string) Request {
+		req := jsonRPCRequest("tasks/get", `{"id":"task-1"}`, headers)
+		req.URL = url
+		return req
+	}
+
+	tests := []struct {
+		name         string
+		request      Request
+		expectStatus int
+		expectBody   string
+	}{
+		{name: "agent card", request: Request{Method: "GET", URL: "/agents/billing"}, expectStatus: http.StatusOK, expectBody: `"Name":"Billing"`},
+		{name: "agent card path", request: Request{Method: "GET", URL: "/agents/support/agent-card"}, expectStatus: http.StatusOK, expectBody: `"Name":"Support"`},
+		{name: "well-known agent card", request: Request{Method: "GET", URL: "/agents/support/.well-known/agent.json"}, expectStatus: http.StatusOK, expectBody: `"Name":"Support"`},
+		{name: "task from own store", request: getTask("/agents/billing", nil), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{
+			name:         "task not shared across agents",
+			request:      getTask("/agents/support/", map[string]string{"X-API-Key": "support-key"}),
+			expectStatus: http.StatusOK,
+			expectBody:   `"error"`,
+		},
+		{name: "per-agent credentials", request: getTask("/agents/support", nil), expectStatus: http.StatusUnauthorized},
+		{name: "unknown agent", request: getTask("/agents/sales", nil), expectStatus: http.StatusNotFound},
+		{name: "root is not an agent", request: Request{Method: "GET", URL: "/"}, expectStatus: http.StatusNotFound},
+		{name: "empty agent id", request: Request{Method: "GET", URL: "/agents/"}, expectStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := router.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if tt.expectBody != "" && !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+			if !json.Valid([]byte(response.Body)) {
+				t.Errorf("expected JSON body, got %s", response.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"context"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// statsMethod is the admin method reporting the agent's stats
+const statsMethod = "admin/stats/get"
+
+// WithStats serves admin/stats/get from stats
+func WithStats(stats *a2aTypes.Stats) Option {
+	return func(h *Handler) {
+		h.stats = stats
+	}
+}
+
+// handleStats handles the admin/stats/get method, served only on the admin
+// API
+func (h *Handler) handleStats(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if response, denied := h.denyNonAdmin(ctx); denied {
+		return response
+	}
+
+	if h.stats == nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorMethodNotFound, "Method not found", "stats are not enabled here", req.ID)
+	}
+
+	var params a2aTypes.StatsParams
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+	if err := params.Validate(); err != nil {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err, req.ID)
+	}
+
+	stats, err := h.stats.Get(ctx, params)
+	if err != nil {
+		return h.handleServerError(ctx, err, req.ID)
+	}
+	return h.handleJSONRPCSuccess(stats, req.ID)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// fixedStatsStore reports the same states for any range, and hour as its
+// first hour
+type fixedStatsStore struct {
+	states map[a2a.TaskState]int64
+	hour   a2aTypes.HourlyStats
+}
+
+func (s fixedStatsStore) AddStats(ctx context.Context, at time.Time, delta a2aTypes.StatsDelta) error {
+	return nil
+}
+
+func (s fixedStatsStore) GetStats(ctx context.Context, from, to time.Time) (map[a2a.TaskState]int64, []a2aTypes.HourlyStats, error) {
+	hour := s.hour
+	hour.Hour = from
+	return s.states, []a2aTypes.HourlyStats{hour}, nil
+}
+
+func TestHandleStats(t *testing.T) {
+	key := map[string]string{"X-API-Key": "secret"}
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{APIKeyHeader: "X-API-Key"}, a2aTypes.SecretsConfig{APIKey: "secret"}, nil)
+	stats := a2aTypes.NewStats(fixedStatsStore{
+		states: map[a2a.TaskState]int64{a2a.TaskStateCompleted: 7},
+		hour:   a2aTypes.HourlyStats{Events: 12, Finished: 2, CompletionMillis: 5000, Timed: 2},
+	}, nil)
+
+	tests := []struct {
+		name    string
+		stats   *a2aTypes.Stats
+		request Request
+		expect  []string
+	}{
+		{name: "default hours", stats: stats, request: adminRPCRequest(statsMethod, `{}`), expect: []string{`"completed":7`, `"events_per_hour":0.5`, `"average_completion_ms":2500`}},
+		{name: "fewer hours", stats: stats, request: adminRPCRequest(statsMethod, `{"hours":2}`), expect: []string{`"events_per_hour":6`}},
+		{name: "too many hours", stats: stats, request: adminRPCRequest(statsMethod, `{"hours":1000}`), expect: []string{`"code":-32602`, `"path":"hours"`}},
+		{name: "not served on the agent endpoint", stats: stats, request: jsonRPCRequest(statsMethod, `{}`, key), expect: []string{`"code":-32601`}},
+		{name: "not enabled", request: adminRPCRequest(statsMethod, `{}`), expect: []string{"stats are not enabled here"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(authenticator)
+			WithAdminAPI(testAdminAPI)(h)
+			if tt.stats != nil {
+				WithStats(tt.stats)(h)
+			}
+			response := h.HandleRequest(context.Background(), tt.request)
+			for _, expect := range tt.expect {
+				if !strings.Contains(response.Body, expect) {
+					t.Errorf("expected %s, got %s", expect, response.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+const (
+	// defaultSSERetry is the reconnection delay suggested to clients
+	defaultSSERetry = 3 * time.Second
+	// defaultSSEKeepAlive is how long a live stream may stay silent before a
+	// comment is sent, so idle proxies and load balancers keep it open
+	defaultSSEKeepAlive = 15 * time.Second
+)
+
+// EventStream receives a streaming response while it is written, for servers
+// that can send the body before it is complete. Without one the frames are
+// buffered into Response.Body, as API Gateway requires.
+type EventStream interface {
+	// Start sends the status and headers, before the first frame
+	Start(status int, headers map[string]string)
+	io.Writer
+	// Flush sends what has been written to the client
+	Flush()
+}
+
+type eventStreamContextKey struct{}
+
+// ContextWithEventStream has streaming methods write their frames to stream
+// as events arrive. The Response HandleRequest returns then has no body.
+func ContextWithEventStream(ctx context.Context, stream EventStream) context.Context {
+	return context.WithValue(ctx, eventStreamContextKey{}, stream)
+}
+
+// WithStreamTiming sets the retry delay suggested to SSE clients and the
+// idle interval after which a live stream sends a keep-alive comment
+func WithStreamTiming(retry, keepAlive time.Duration) Option {
+	return func(h *Handler) {
+		h.sseRetry = retry
+		h.sseKeepAlive = keepAlive
+	}
+}
+
+// sseWriter frames events as Server-Sent Events. Writes are serialized so
+// keep-alive comments never interleave with an event.
+type sseWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	flush func()
+}
+
+func (s *sseWriter) write(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.w, frame)
+	s.flush()
+}
+
+// event writes one JSON-RPC response as an event. JSON is encoded without
+// newlines, so it always fits on a single data line. The ID is escaped, as
+// a message ID may hold anything; an event without one has no id line.
+func (s *sseWriter) event(id string, response []byte) {
+	if id == "" {
+		s.write(fmt.Sprintf("data: %s\n\n", response))
+		return
+	}
+	s.write(fmt.Sprintf("id: %s\ndata: %s\n\n", url.PathEscape(id), response))
+}
+
+// keepAlive sends a comment every interval until stop is closed
+func (s *sseWriter) keepAlive(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.write(": keep-alive\n\n")
+		case <-stop:
+			return
+		}
+	}
+}
+
+// streamingEnabled reports whether the agent card advertises streaming
+func (h *Handler) streamingEnabled() bool {
+	streaming := h.agentCard().Capabilities.Streaming
+	return streaming != nil && *streaming
+}
+
+// handleSendMessageStream handles the message/stream method
+func (h *Handler) handleSendMessageStream(ctx context.Context, req a2aTypes.JSONRPCRequest) Response {
+	if !h.streamingEnabled() {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorUnsupportedOperation, "Unsupported operation", "streaming is not enabled on the agent card", req.ID)
+	}
+	params, err := a2aTypes.DecodeMessageSendParams(req.Params)
+	if err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+	}
+
+	if params.Message.TaskID != nil {
+		if response, denied := h.denyTaskID(ctx, *params.Message.TaskID, req.ID); denied {
+			return response
+		}
+	}
+	if response, denied := h.denySkill(ctx, params.Message); denied {
+		return response
+	}
+	return h.handleStream(ctx, h.a2aHandler.OnSendMessageStream(ctx, params), req.ID)
+}
+
+// handleResubscribe handles the tasks/resubscribe method. Event IDs are the
+// IDs the events are stored under, so a client that reconnects with
+// Last-Event-ID only receives the events after the last one it saw.
+func (h *Handler) handleResubscribe(ctx context.Context, req a2aTypes.JSONRPCRequest, headers map[string]string) Response {
+	if !h.streamingEnabled() {
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorUnsupportedOperation, "Unsupported operation", "streaming is not enabled on the agent card", req.ID)
+	}
+	var params a2a.TaskIDParams
+	if err := a2aTypes.DecodeParams(req.Params, &params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+	}
+	if err := a2aTypes.ValidateTaskIDParams(params); err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", err, req.ID)
+	}
+	lastEventID, err := url.PathUnescape(strings.TrimSpace(a2aTypes.HeaderValue(headers, "Last-Event-ID")))
+	if err != nil {
+		return h.handleJSONRPCError(ctx, -32602, "Invalid params", "Last-Event-ID must be an event ID from this stream", req.ID)
+	}
+
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyTaskID, params.ID)
+	a2aTypes.AnnotateSpan(ctx, a2aTypes.SpanAttrTaskID.String(string(params.ID)))
+	auditTaskID(ctx, string(params.ID))
+
+	if response, denied := h.denyTaskID(ctx, params.ID, req.ID); denied {
+		return response
+	}
+	return h.handleStream(ctx, h.a2aHandler.ResubscribeToTaskAfter(ctx, params, lastEventID), req.ID)
+}
+
+// handleStream writes events as SSE frames, each holding one JSON-RPC
+// response and identified by its EventID. A failure ends the stream with a
+// JSON-RPC error frame.
+func (h *Handler) handleStream(ctx context.Context, events iter.Seq2[a2a.Event, error], id interface{}) Response {
+	headers := map[string]string{
+		"Content-Type":                 "text/event-stream",
+		"Cache-Control":                "no-cache",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+	}
+
+	var body bytes.Buffer
+	writer := &sseWriter{w: &body, flush: func() {}}
+	stream, live := ctx.Value(eventStreamContextKey{}).(EventStream)
+	if live {
+		stream.Start(http.StatusOK, headers)
+		writer = &sseWriter{w: stream, flush: stream.Flush}
+		if h.sseKeepAlive > 0 {
+			// The comments must stop before the server finishes the response
+			stop, stopped := make(chan struct{}), make(chan struct{})
+			go func() {
+				writer.keepAlive(h.sseKeepAlive, stop)
+				close(stopped)
+			}()
+			defer func() {
+				close(stop)
+				<-stopped
+			}()
+		}
+	}
+
+	writer.write(fmt.Sprintf("retry: %d\n\n", h.sseRetry.Milliseconds()))
+	// Each event is encoded into the same pooled buffer
+	buf := a2aTypes.GetBuffer()
+	defer a2aTypes.PutBuffer(buf)
+	for event, err := range events {
+		if err != nil {
+			writer.event("", []byte(h.handleServerError(ctx, err, id).Body))
+			break
+		}
+		buf.Reset()
+		if err := a2aTypes.WriteJSONRPCResponse(buf, a2aTypes.NewJSONRPCResponse(a2aTypes.PublicEvent(event), id)); err != nil {
+			writer.event("", []byte(h.handleServerError(ctx, err, id).Body))
+			break
+		}
+		writer.event(a2aTypes.EventID(event), bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	}
+
+	return Response{Status: http.StatusOK, Headers: headers, Body: body.String()}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// listEventStore returns the same events for every task, after delay
+type listEventStore struct {
+	discardEventStore
+	events []a2a.Event
+	delay  time.Duration
+	err    error
+}
+
+func (s listEventStore) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	time.Sleep(s.delay)
+	return s.events, s.err
+}
+
+// recordingStream is an EventStream that keeps what was written
+type recordingStream struct {
+	mu      sync.Mutex
+	status  int
+	headers map[string]string
+	body    bytes.Buffer
+	flushes int
+}
+
+func (s *recordingStream) Start(status int, headers map[string]string) {
+	s.status, s.headers = status, headers
+}
+
+func (s *recordingStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.body.Write(p)
+}
+
+func (s *recordingStream) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushes++
+}
+
+func newStreamingHandler(events listEventStore, opts ...Option) *Handler {
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{
+		"task-1": {ID: "task-1", ContextID: "ctx-1", Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}},
+	}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, events, nil)
+	card := agentcard.New("Test Agent", "https://agent.example.com", agentcard.WithStreaming(true))
+	return NewHandler(a2aHandler, card, opts...)
+}
+
+// sseFrames splits an SSE body into its frames
+func sseFrames(t *testing.T, body string) []string {
+	t.Helper()
+	if !strings.HasSuffix(body, "\n\n") {
+		t.Fatalf("expected the body to end with a complete frame, got %q", body)
+	}
+	return strings.Split(strings.TrimSuffix(body, "\n\n"), "\n\n")
+}
+
+// statusEvent is a status update of task-1, at second seconds past the epoch
+func statusEvent(state a2a.TaskState, second int64) a2a.Event {
+	at := time.Unix(second, 0)
+	return a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: state, Timestamp: &at}}
+}
+
+func TestHandleMessageStream(t *testing.T) {
+	h := newStreamingHandler(listEventStore{})
+	resp := h.HandleRequest(context.Background(), jsonRPCRequest("message/stream",
+		`{"Message":{"MessageID":"m-1","Role":"user","Parts":[{"Kind":"text","Text":"hi"}]}}`, nil))
+
+	if resp.Headers["Content-Type"] != "text/event-stream" || resp.Headers["Cache-Control"] != "no-cache" {
+		t.Errorf("expected SSE headers, got %v", resp.Headers)
+	}
+	frames := sseFrames(t, resp.Body)
+	if len(frames) != 2 || frames[0] != "retry: 3000" {
+		t.Fatalf("expected a retry hint and one event, got %q", resp.Body)
+	}
+	id, data, _ := strings.Cut(frames[1], "\n")
+	if !strings.HasPrefix(id, "id: status_") || !strings.HasPrefix(data, "data: ") || strings.Contains(data[len("data: "):], "\n") {
+		t.Fatalf("expected one data line with the event's ID, got %q", frames[1])
+	}
+	var response struct {
+		ID     int
+		Result map[string]any
+	}
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &response); err != nil {
+		t.Fatalf("expected a JSON-RPC response, got %q: %v", data, err)
+	}
+	if response.ID != 1 || response.Result["Kind"] != "status-update" {
+		t.Errorf("expected the status update for request 1, got %+v", response)
+	}
+}
+
+func TestHandleResubscribe(t *testing.T) {
+	events := listEventStore{events: []a2a.Event{
+		statusEvent(a2a.TaskStateSubmitted, 1),
+		statusEvent(a2a.TaskStateWorking, 2),
+		statusEvent(a2a.TaskStateCompleted, 3),
+		a2a.Message{Kind: "message", MessageID: "m 1/\nnext", Role: a2a.MessageRoleAgent},
+	}}
+	first, second, third := "id: status_task-1_1000000000", "id: status_task-1_2000000000", "id: status_task-1_3000000000"
+	// The message ID is escaped onto one line
+	message := "id: m%201%2F%0Anext"
+	tests := []struct {
+		name        string
+		header      string
+		lastEventID string
+		expectIDs   []string
+	}{
+		{name: "from the start", expectIDs: []string{first, second, third, message}},
+		{name: "after the last event seen", lastEventID: "status_task-1_1000000000", expectIDs: []string{second, third, message}},
+		{name: "nothing new", lastEventID: "m%201%2F%0Anext"},
+		// API Gateway passes header names through as the client sent them
+		{name: "header as sent", header: "Last-Event-ID", lastEventID: "status_task-1_3000000000", expectIDs: []string{message}},
+		{name: "unknown event", lastEventID: "status_task-1_9", expectIDs: []string{first, second, third, message}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.lastEventID != "" {
+				header := "last-event-id"
+				if tt.header != "" {
+					header = tt.header
+				}
+				headers[header] = tt.lastEventID
+			}
+			resp := newStreamingHandler(events).HandleRequest(context.Background(), jsonRPCRequest("tasks/resubscribe", `{"ID":"task-1"}`, headers))
+			var ids []string
+			for _, frame := range sseFrames(t, resp.Body)[1:] {
+				id, _, _ := strings.Cut(frame, "\n")
+				ids = append(ids, id)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expectIDs, ",") {
+				t.Errorf("expected events %v, got %v", tt.expectIDs, ids)
+			}
+		})
+	}
+}
+
+func TestHandleStreamErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// Agents whose card does not advertise streaming refuse it
+	disabled := newTestHandler(nil)
+	for _, method := range []string{"message/stream", "tasks/resubscribe"} {
+		resp := disabled.HandleRequest(ctx, jsonRPCRequest(method, `{"ID":"task-1"}`, nil))
+		if !strings.Contains(resp.Body, `"code":-32004`) {
+			t.Errorf("expected %s to be unsupported, got %s", method, resp.Body)
+		}
+	}
+
+	h := newStreamingHandler(listEventStore{err: errors.New("throttled")})
+	resp := h.HandleRequest(ctx, jsonRPCRequest("tasks/resubscribe", `{"ID":"task-1"}`, map[string]string{"last-event-id": "status%zz"}))
+	if resp.Headers["Content-Type"] != "application/json" || !strings.Contains(resp.Body, `"code":-32602`) {
+		t.Errorf("expected invalid params for a bad Last-Event-ID, got %s", resp.Body)
+	}
+
+	// A failure after the stream has started ends it with an error frame
+	resp = h.HandleRequest(ctx, jsonRPCRequest("tasks/resubscribe", `{"ID":"task-1"}`, nil))
+	frames := sseFrames(t, resp.Body)
+	if len(frames) != 2 || !strings.HasPrefix(frames[1], "data: ") || !strings.Contains(frames[1], `"code":-32000`) {
+		t.Errorf("expected an error frame, got %q", resp.Body)
+	}
+}
+
+func TestHandleStreamLive(t *testing.T) {
+	events := listEventStore{events: []a2a.Event{statusEvent(a2a.TaskStateCompleted, 1)}, delay: 50 * time.Millisecond}
+	h := newStreamingHandler(events, WithStreamTiming(time.Second, 10*time.Millisecond))
+	stream := &recordingStream{}
+
+	resp := h.HandleRequest(ContextWithEventStream(context.Background(), stream), jsonRPCRequest("tasks/resubscribe", `{"ID":"task-1"}`, nil))
+	if resp.Body != "" {
+		t.Errorf("expected the frames to go to the stream only, got %q", resp.Body)
+	}
+	if stream.status != 200 || stream.headers["Content-Type"] != "text/event-stream" {
+		t.Errorf("expected the stream to be started with SSE headers, got %d %v", stream.status, stream.headers)
+	}
+	body := stream.body.String()
+	if !strings.HasPrefix(body, "retry: 1000\n\n") || !strings.Contains(body, ": keep-alive\n\n") || !strings.HasSuffix(body, "\n\n") {
+		t.Errorf("expected a retry hint and keep-alive comments while idle, got %q", body)
+	}
+	if !strings.Contains(body, "id: status_task-1_1000000000\ndata: ") || stream.flushes < 3 {
+		t.Errorf("expected each frame to be flushed, got %d flushes for %q", stream.flushes, body)
+	}
+}
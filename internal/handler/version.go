@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// RequestAdapter rewrites a JSON-RPC request sent in another protocol
+// version into the form the handler serves. An error is answered as
+// invalid params.
+type RequestAdapter func(req *a2aTypes.JSONRPCRequest) error
+
+// WithProtocolVersion also serves clients that name version in the
+// A2A-Version header, rewriting their requests with adapt first; a nil adapt
+// serves the version's requests unchanged. The version on the agent card is
+// always served as is.
+func WithProtocolVersion(version string, adapt RequestAdapter) Option {
+	return func(h *Handler) {
+		if normalized, err := a2aTypes.NormalizeProtocolVersion(version); err == nil {
+			h.adapters[normalized] = adapt
+		}
+	}
+}
+
+// defaultAdapters are the older protocol versions every handler serves
+func defaultAdapters() map[string]RequestAdapter {
+	return map[string]RequestAdapter{"0.1": adaptV01Request}
+}
+
+// cardProtocolVersion is the version the handler speaks natively
+func cardProtocolVersion(card string) string {
+	if version, err := a2aTypes.NormalizeProtocolVersion(card); err == nil {
+		return version
+	}
+	return a2aTypes.DefaultProtocolVersion
+}
+
+// negotiateVersion checks the version a request names and adapts the
+// request to it. A version the handler does not serve is refused rather
+// than read as the current one, since its payloads may mean something else.
+func (h *Handler) negotiateVersion(ctx context.Context, req Request, jsonrpcReq *a2aTypes.JSONRPCRequest) (Response, bool) {
+	requested := a2aTypes.RequestedProtocolVersion(req.Headers)
+	if requested == "" {
+		return Response{}, false
+	}
+	version, err := a2aTypes.NormalizeProtocolVersion(requested)
+	adapt, ok := h.adapters[version]
+	if err != nil || !ok {
+		data := fmt.Sprintf("%s %q is not supported; supported versions are %s", a2aTypes.ProtocolVersionHeader, requested, strings.Join(h.protocolVersions(), ", "))
+		return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorVersionNotSupported, "Version not supported", data, jsonrpcReq.ID), true
+	}
+	if adapt != nil {
+		if err := adapt(jsonrpcReq); err != nil {
+			return h.handleJSONRPCError(ctx, a2aTypes.JSONRPCErrorInvalidParams, "Invalid params", err.Error(), jsonrpcReq.ID), true
+		}
+	}
+	return Response{}, false
+}
+
+// protocolVersions lists the versions the handler serves, oldest first
+func (h *Handler) protocolVersions() []string {
+	versions := make([]string, 0, len(h.adapters))
+	for version := range h.adapters {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		var iMajor, iMinor, jMajor, jMinor int
+		fmt.Sscanf(versions[i], "%d.%d", &iMajor, &iMinor)
+		fmt.Sscanf(versions[j], "%d.%d", &jMajor, &jMinor)
+		if iMajor != jMajor {
+			return iMajor < jMajor
+		}
+		return iMinor < jMinor
+	})
+	return versions
+}
+
+// adaptV01Request serves A2A 0.1 clients, whose tasks/send became
+// message/send. Their parts name their kind in "type", their messages have
+// no ID of their own and the session ID is now the context ID. The task ID a
+// 0.1 client picks is not kept: each send starts a task, and the response
+// carries the ID it was given. Members with no counterpart, such as
+// pushNotification, are passed on for strict decoding to name.
+func adaptV01Request(req *a2aTypes.JSONRPCRequest) error {
+	if req.Method != "tasks/send" {
+		return nil
+	}
+	// The legacy shape is rewritten member by member, so it is read generically
+	var params map[string]interface{}
+	raw, _ := req.Params.(json.RawMessage)
+	if err := json.Unmarshal(raw, &params); err != nil || params == nil {
+		return errors.New("tasks/send params must be an object")
+	}
+	message, ok := params["message"].(map[string]interface{})
+	if !ok {
+		return errors.New("tasks/send params must hold a message object")
+	}
+
+	if parts, ok := message["parts"].([]interface{}); ok {
+		for _, part := range parts {
+			if p, ok := part.(map[string]interface{}); ok {
+				if kind, ok := p["type"]; ok {
+					p["kind"] = kind
+					delete(p, "type")
+				}
+			}
+		}
+	}
+	if _, ok := message["messageId"]; !ok {
+		message["messageId"] = params["id"]
+	}
+	if sessionID, ok := params["sessionId"]; ok {
+		message["contextId"] = sessionID
+	}
+
+	adapted := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		switch name {
+		case "id", "sessionId":
+		case "historyLength":
+			adapted["config"] = map[string]interface{}{"historyLength": value}
+		default:
+			adapted[name] = value
+		}
+	}
+	req.Method = "message/send"
+	req.Params = adapted
+	return nil
+}
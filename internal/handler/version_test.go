@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestHandleRequestProtocolVersion(t *testing.T) {
+	h := newTestHandler(nil)
+
+	tests := []struct {
+		name       string
+		version    string
+		expectCode int
+		expectData string
+	}{
+		{name: "no version"},
+		{name: "card version", version: "0.3"},
+		{name: "patch release", version: "0.3.2"},
+		{name: "surrounding spaces", version: " 0.3 "},
+		{name: "unsupported version", version: "2.0", expectCode: a2aTypes.JSONRPCErrorVersionNotSupported, expectData: `A2A-Version \"2.0\" is not supported; supported versions are 0.1, 0.3`},
+		{name: "not a version", version: "latest", expectCode: a2aTypes.JSONRPCErrorVersionNotSupported, expectData: `\"latest\" is not supported`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{}
+			if tt.version != "" {
+				headers["a2a-version"] = tt.version
+			}
+			response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, headers))
+			if tt.expectCode == 0 {
+				if strings.Contains(response.Body, `"error"`) {
+					t.Errorf("expected the request to be served, got %s", response.Body)
+				}
+				return
+			}
+			var decoded a2aTypes.JSONRPCResponse
+			json.Unmarshal([]byte(response.Body), &decoded)
+			if decoded.Error == nil || decoded.Error.Code != tt.expectCode || !strings.Contains(response.Body, tt.expectData) {
+				t.Errorf("expected error %d with %s, got %s", tt.expectCode, tt.expectData, response.Body)
+			}
+			if decoded.ID != float64(1) {
+				t.Errorf("expected the request id to be echoed, got %v", decoded.ID)
+			}
+		})
+	}
+}
+
+func TestHandleRequestV01TasksSend(t *testing.T) {
+	h := newTestHandler(nil)
+	headers := map[string]string{"A2A-Version": "0.1"}
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("tasks/send",
+		`{"id":"client-task","sessionId":"session-1","historyLength":1,"message":{"role":"user","parts":[{"type":"text","text":"hello"}]}}`, headers))
+	var sent struct {
+		Result json.RawMessage
+		Error  *a2aTypes.JSONRPCError
+	}
+	if err := json.Unmarshal([]byte(response.Body), &sent); err != nil || sent.Error != nil {
+		t.Fatalf("expected tasks/send to be served as message/send, got %s", response.Body)
+	}
+	task, err := a2aTypes.UnmarshalTask(sent.Result)
+	if err != nil {
+		t.Fatalf("unexpected result: %v", err)
+	}
+	message := task.History[0]
+	if message.MessageID != "client-task" || message.ContextID == nil || *message.ContextID != "session-1" {
+		t.Errorf("expected the message ID and context from the 0.1 params, got %+v", message)
+	}
+	if part, ok := message.Parts[0].(a2a.TextPart); !ok || part.Text != "hello" {
+		t.Errorf("expected the typed part to decode, got %+v", message.Parts)
+	}
+
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/send", `{"id":"t","message":{"role":"user","parts":[]},"pushNotification":{}}`, headers))
+	if !strings.Contains(response.Body, `"code":-32602`) || !strings.Contains(response.Body, "pushNotification") {
+		t.Errorf("expected the unmapped member to be named, got %s", response.Body)
+	}
+
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/send", `{"id":"t","message":{"role":"user","parts":[{"type":"text","text":"hi"}]}}`, nil))
+	if !strings.Contains(response.Body, `"code":-32601`) {
+		t.Errorf("expected tasks/send to be unknown without a version, got %s", response.Body)
+	}
+}
+
+func TestWithProtocolVersion(t *testing.T) {
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, discardEventStore{}, nil)
+	card := a2a.AgentCard{Name: "Test Agent", URL: "https://agent.example.com", ProtocolVersion: "1.0.0"}
+	renamed := func(req *a2aTypes.JSONRPCRequest) error {
+		if req.Method == "GetTask" {
+			req.Method = "tasks/get"
+		}
+		return nil
+	}
+	h := NewHandler(a2aHandler, card, WithProtocolVersion("0.9", renamed))
+
+	response := h.HandleRequest(context.Background(), jsonRPCRequest("GetTask", `{"ID":"task-1"}`, map[string]string{"A2A-Version": "0.9"}))
+	if strings.Contains(response.Body, `"error"`) {
+		t.Errorf("expected the adapted request to be served, got %s", response.Body)
+	}
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, map[string]string{"A2A-Version": "1.0"}))
+	if strings.Contains(response.Body, `"error"`) {
+		t.Errorf("expected the card version to be served, got %s", response.Body)
+	}
+	response = h.HandleRequest(context.Background(), jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, map[string]string{"A2A-Version": "0.3"}))
+	if !strings.Contains(response.Body, "supported versions are 0.1, 0.9, 1.0") {
+		t.Errorf("expected 0.3 to be refused, got %s", response.Body)
+	}
+}
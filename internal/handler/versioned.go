@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// InterfaceVersion is an interface a VersionRouter serves beside the current one
+type InterfaceVersion struct {
+	// Path is the prefix the interface is served under, such as "/v1"
+	Path string
+	// ProtocolVersion is assumed for requests that name none in the
+	// A2A-Version header, so callers pinned to an older protocol keep working
+	// unchanged. The handler must serve it, natively or through an adapter.
+	ProtocolVersion string
+	// Handler serves the interface, with its own agent card
+	Handler *Handler
+}
+
+// VersionRouter serves an agent's current interface and earlier ones side by
+// side during a migration. Requests under a version's path go to its
+// handler with the prefix stripped; every other request goes to the current
+// handler as is.
+type VersionRouter struct {
+	current  *Handler
+	versions []InterfaceVersion
+}
+
+// NewVersionRouter creates a router serving current at the root and each
+// version under its path
+func NewVersionRouter(current *Handler, versions ...InterfaceVersion) *VersionRouter {
+	for i := range versions {
+		versions[i].Path = "/" + strings.Trim(versions[i].Path, "/")
+	}
+	return &VersionRouter{current: current, versions: versions}
+}
+
+// HandleRequest passes the request to the interface its path names
+func (r *VersionRouter) HandleRequest(ctx context.Context, req Request) Response {
+	for _, version := range r.versions {
+		rest, ok := strings.CutPrefix(req.URL, version.Path)
+		if !ok || rest != "" && !strings.HasPrefix(rest, "/") {
+			continue
+		}
+		if rest == "" {
+			rest = "/"
+		}
+		req.URL = rest
+		if version.ProtocolVersion != "" && a2aTypes.RequestedProtocolVersion(req.Headers) == "" {
+			// Copied so the caller's headers are left as they were sent
+			headers := make(map[string]string, len(req.Headers)+1)
+			for name, value := range req.Headers {
+				headers[name] = value
+			}
+			headers[strings.ToLower(a2aTypes.ProtocolVersionHeader)] = version.ProtocolVersion
+			req.Headers = headers
+		}
+		return version.Handler.HandleRequest(ctx, req)
+	}
+	return r.current.HandleRequest(ctx, req)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestVersionRouter(t *testing.T) {
+	// Both interfaces share the agent's storage
+	store := &memoryTaskStore{tasks: map[a2a.TaskID]a2a.Task{"task-1": {ID: "task-1", Kind: "task"}}}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, store, discardEventStore{}, nil)
+	current := NewHandler(a2aHandler, agentcard.New("Agent v2", "https://agent.example.com"))
+	previous := NewHandler(a2aHandler, agentcard.New("Agent v1", "https://agent.example.com/v1"))
+	router := NewVersionRouter(current, InterfaceVersion{Path: "v1/", ProtocolVersion: "0.1", Handler: previous})
+
+	at := func(url string, req Request) Request {
+		req.URL = url
+		return req
+	}
+	tasksSend := jsonRPCRequest("tasks/send", `{"id":"m-1","message":{"role":"user","parts":[{"type":"text","text":"hello"}]}}`, nil)
+
+	tests := []struct {
+		name         string
+		request      Request
+		expectStatus int
+		expectBody   string
+	}{
+		{name: "current card", request: Request{Method: "GET", URL: "/"}, expectStatus: http.StatusOK, expectBody: `"Name":"Agent v2"`},
+		{name: "previous card", request: Request{Method: "GET", URL: "/v1"}, expectStatus: http.StatusOK, expectBody: `"Name":"Agent v1"`},
+		{name: "previous well-known card", request: Request{Method: "GET", URL: "/v1/.well-known/agent.json"}, expectStatus: http.StatusOK, expectBody: `"Name":"Agent v1"`},
+		{name: "pinned caller adapted without a header", request: at("/v1", tasksSend), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{name: "current interface does not adapt", request: at("/", tasksSend), expectStatus: http.StatusOK, expectBody: `"code":-32601`},
+		{name: "explicit version wins", request: at("/v1/", jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, map[string]string{"a2a-version": "0.3"})), expectStatus: http.StatusOK, expectBody: `"result"`},
+		{name: "shared storage", request: at("/v1", jsonRPCRequest("tasks/get", `{"ID":"task-1"}`, nil)), expectStatus: http.StatusOK, expectBody: `"ID":"task-1"`},
+		{name: "prefix of another path", request: Request{Method: "GET", URL: "/v10"}, expectStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := tt.request.Headers
+			response := router.HandleRequest(context.Background(), tt.request)
+			if response.Status != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, response.Status, response.Body)
+			}
+			if !strings.Contains(response.Body, tt.expectBody) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectBody, response.Body)
+			}
+			if _, ok := headers["a2a-version"]; ok != (tt.name == "explicit version wins") {
+				t.Errorf("expected the caller's headers to be left unchanged, got %v", headers)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Package sentryreport sends the handler's error reports to Sentry. It is
+// kept out of internal/a2a so only programs that report to Sentry link its
+// client.
+package sentryreport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// ErrorReporter sends error reports to Sentry, tagging each event with the
+// request's log fields so it can be matched to its log lines
+type ErrorReporter struct {
+	hub *sentry.Hub
+}
+
+// NewErrorReporter creates a reporter with its own client and hub, so
+// it never touches the global hub other libraries may configure. The DSN,
+// environment and release fall back to SENTRY_DSN, SENTRY_ENVIRONMENT and
+// SENTRY_RELEASE when unset in options.
+func NewErrorReporter(options sentry.ClientOptions) (*ErrorReporter, error) {
+	client, err := sentry.NewClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Sentry client: %w", err)
+	}
+	return &ErrorReporter{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+// ReportError implements a2aTypes.ErrorReporter
+func (r *ErrorReporter) ReportError(ctx context.Context, report a2aTypes.ErrorReport) {
+	// A clone per report keeps concurrent requests from sharing a scope
+	hub := r.hub.Clone()
+	hub.Scope().SetTags(report.Fields)
+	if report.Panic != nil {
+		// Recover builds the stack trace from the deferred call it runs in,
+		// which still has the panicking frames on it
+		hub.RecoverWithContext(ctx, report.Panic)
+		return
+	}
+	hub.CaptureException(report.Err)
+}
+
+// Flush sends buffered events, waiting at most timeout. Lambda freezes the
+// process between invocations, so call it before each invocation returns.
+func (r *ErrorReporter) Flush(timeout time.Duration) bool {
+	return r.hub.Flush(timeout)
+}
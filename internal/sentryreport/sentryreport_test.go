@@ -0,0 +1,42 @@
+package sentryreport
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+func TestErrorReporter(t *testing.T) {
+	transport := &sentry.MockTransport{}
+	reporter, err := NewErrorReporter(sentry.ClientOptions{
+		Dsn:       "https://key@sentry.example.com/1",
+		Transport: transport,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := a2aTypes.ContextWithErrorReporter(context.Background(), reporter)
+	ctx = a2aTypes.WithLogAttrs(ctx, a2aTypes.LogKeyRequestID, "req-1")
+	a2aTypes.ReportError(ctx, errors.New("store unavailable"))
+	func() {
+		defer func() { a2aTypes.ReportPanic(ctx, recover(), nil) }()
+		panic("boom")
+	}()
+	reporter.Flush(0)
+
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Level != sentry.LevelError || events[0].Tags[a2aTypes.LogKeyRequestID] != "req-1" {
+		t.Errorf("unexpected error event: level %s, tags %v", events[0].Level, events[0].Tags)
+	}
+	if events[1].Level != sentry.LevelFatal || events[1].Message != "boom" {
+		t.Errorf("unexpected panic event: level %s, message %q", events[1].Level, events[1].Message)
+	}
+}
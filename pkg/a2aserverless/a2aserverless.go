@@ -0,0 +1,277 @@
+// Package a2aserverless is the public surface of the serverless A2A runtime:
+// its config, the handler that runs an agent executor over task and event
+// stores, and the authentication and tracing the HTTP handler is given. With
+// pkg/store and pkg/handler it lets programs build their own main, as
+// cmd/lambda and cmd/server do. The types are those of the runtime itself,
+// so values pass between the packages without conversion.
+package a2aserverless
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/store"
+)
+
+// ServerlessConfig configures an agent: its card, storage, security and
+// limits. LoadServerlessConfig and LoadLambdaEnvConfig read it from the
+// environment; code can also build it directly.
+type ServerlessConfig = a2aTypes.ServerlessConfig
+
+// SecurityConfig selects the inbound authentication schemes
+type SecurityConfig = a2aTypes.SecurityConfig
+
+// SecretsConfig holds the resolved secrets of a config
+type SecretsConfig = a2aTypes.SecretsConfig
+
+// ServerlessA2AHandler runs A2A methods against the stores it is built over
+type ServerlessA2AHandler = a2aTypes.ServerlessA2AHandler
+
+// RuntimeOption configures a ServerlessA2AHandler. It is the same type as
+// store.Option.
+type RuntimeOption = a2aTypes.RuntimeOption
+
+// Authenticator checks the credentials of inbound JSON-RPC calls
+type Authenticator = a2aTypes.Authenticator
+
+// AuthenticatorOption configures an Authenticator
+type AuthenticatorOption = a2aTypes.AuthenticatorOption
+
+// LocalizationConfig holds translations of the agent card
+type LocalizationConfig = a2aTypes.LocalizationConfig
+
+// TracingConfig configures OpenTelemetry tracing
+type TracingConfig = a2aTypes.TracingConfig
+
+// Tracing starts the spans of a handler
+type Tracing = a2aTypes.Tracing
+
+// AuditLog records the calls a handler serves
+type AuditLog = a2aTypes.AuditLog
+
+// Clock tells the time to the handler and stores
+type Clock = a2aTypes.Clock
+
+// IDGenerator generates task and event IDs
+type IDGenerator = a2aTypes.IDGenerator
+
+// WorkQueueConfig names the SQS queues messages are queued on for workers,
+// and retried through
+type WorkQueueConfig = a2aTypes.WorkQueueConfig
+
+// WorkQueueConsumer executes the messages a worker receives from the work
+// and retry queues
+type WorkQueueConsumer = a2aTypes.WorkQueueConsumer
+
+// ContextLockConfig names the DynamoDB table the executions of a context
+// are serialized through
+type ContextLockConfig = a2aTypes.ContextLockConfig
+
+// DedupConfig names the DynamoDB table received message IDs are remembered
+// in, and for how long
+type DedupConfig = a2aTypes.DedupConfig
+
+// ScheduleConfig names the DynamoDB table messages starting beyond the work
+// queue's delay wait in
+type ScheduleConfig = a2aTypes.ScheduleConfig
+
+// MaintenanceReport counts what a maintenance pass did
+type MaintenanceReport = a2aTypes.MaintenanceReport
+
+// SkillProvider defines skills to publish on the agent card
+type SkillProvider = a2aTypes.SkillProvider
+
+// ErrTaskCanceled is the cause of an executor's context once its task is
+// canceled while it runs
+var ErrTaskCanceled = a2aTypes.ErrTaskCanceled
+
+// ErrTaskFinished is returned for a write that would move a finished task
+// to another state
+var ErrTaskFinished = a2aTypes.ErrTaskFinished
+
+// ErrTaskNotResumable is returned for a message sent to a finished task
+var ErrTaskNotResumable = a2aTypes.ErrTaskNotResumable
+
+// ErrTaskNotRunning is returned for a heartbeat on a task no longer
+// submitted or working
+var ErrTaskNotRunning = a2aTypes.ErrTaskNotRunning
+
+// ErrContextBusy is returned for a message whose context another execution
+// held for the whole lock wait
+var ErrContextBusy = a2aTypes.ErrContextBusy
+
+// ErrDuplicateMessage is returned for a message received again while the
+// first is still being handled
+var ErrDuplicateMessage = a2aTypes.ErrDuplicateMessage
+
+// ErrPushConfigNotFound is returned for a push notification config the task
+// does not have
+var ErrPushConfigNotFound = a2aTypes.ErrPushConfigNotFound
+
+// LoadServerlessConfig reads an agent's config from A2A_* and cloud provider
+// environment variables
+func LoadServerlessConfig() (ServerlessConfig, error) {
+	return a2aTypes.NewConfigLoader().LoadServerlessConfig()
+}
+
+// LoadLambdaEnvConfig reads the config of an agent on Lambda, where storage
+// is DynamoDB in region
+func LoadLambdaEnvConfig(region string) (ServerlessConfig, error) {
+	return a2aTypes.LoadLambdaEnvConfig(region)
+}
+
+// ValidateLambdaConfig reports what a config lacks to run on Lambda
+func ValidateLambdaConfig(config ServerlessConfig) error {
+	return a2aTypes.ValidateLambdaConfig(config)
+}
+
+// NewServerlessA2AHandler creates the handler that runs an agent over
+// taskStore and eventStore. pushNotifier may be nil, which sends no push
+// notifications.
+func NewServerlessA2AHandler(config ServerlessConfig, taskStore store.TaskStore, eventStore store.EventStore, pushNotifier store.PushNotifier, opts ...RuntimeOption) *ServerlessA2AHandler {
+	return a2aTypes.NewServerlessA2AHandler(config, taskStore, eventStore, pushNotifier, opts...)
+}
+
+// WithExecutor has the handler run executor on every message/send. Without
+// an executor a message leaves its task working for another function to
+// pick up.
+func WithExecutor(executor a2asrv.AgentExecutor) RuntimeOption {
+	return a2aTypes.WithExecutor(executor)
+}
+
+// WithWorkQueue has a handler without an executor queue each message that
+// leaves its task waiting on the agent, for a worker whose handler has one
+// to execute
+func WithWorkQueue(queue store.MessageQueue) RuntimeOption {
+	return a2aTypes.WithWorkQueue(queue)
+}
+
+// WithRetryQueue has executions that fail transiently retried through
+// queue, under the config's retry policies
+func WithRetryQueue(queue store.MessageQueue) RuntimeOption {
+	return a2aTypes.WithRetryQueue(queue)
+}
+
+// LoadWorkQueueConfigFromEnv reads the work, priority and retry queues and
+// the concurrency of each from A2A_WORK_QUEUE_*, A2A_RETRY_QUEUE_URL and
+// A2A_WORK_CONCURRENCY*. The config's RuntimeOptions wire its queues into a
+// handler.
+func LoadWorkQueueConfigFromEnv() (WorkQueueConfig, error) {
+	return a2aTypes.LoadWorkQueueConfigFromEnv()
+}
+
+// NewWorkQueueConsumer creates the consumer of a worker, executing each
+// queued message with h, which must have an executor, at the concurrency of
+// the queue it came from.
+// handler.WorkQueueLambdaHandler adapts it for lambda.Start.
+func NewWorkQueueConsumer(h *ServerlessA2AHandler, config WorkQueueConfig) *WorkQueueConsumer {
+	return a2aTypes.NewWorkQueueConsumer(h, config)
+}
+
+// WithScheduler has messages starting beyond the work queue's delay held by
+// scheduler, which store.NewAWSMessageScheduler creates, until a
+// maintenance pass releases them
+func WithScheduler(scheduler store.MessageScheduler) RuntimeOption {
+	return a2aTypes.WithScheduler(scheduler)
+}
+
+// LoadScheduleConfigFromEnv reads A2A_SCHEDULE_TABLE. The config's
+// RuntimeOptions wire its scheduler into a handler.
+func LoadScheduleConfigFromEnv() ScheduleConfig {
+	return a2aTypes.LoadScheduleConfigFromEnv()
+}
+
+// WithContextLock has the handler execute one message at a time per
+// context, waiting up to wait for lock before failing with ErrContextBusy
+func WithContextLock(lock store.ContextLock, wait time.Duration) RuntimeOption {
+	return a2aTypes.WithContextLock(lock, wait)
+}
+
+// LoadContextLockConfigFromEnv reads A2A_CONTEXT_LOCK_TABLE and
+// A2A_CONTEXT_LOCK_WAIT. The config's RuntimeOptions wire its lock into a
+// handler.
+func LoadContextLockConfigFromEnv() (ContextLockConfig, error) {
+	return a2aTypes.LoadContextLockConfigFromEnv()
+}
+
+// WithMessageDeduplication has a messageId received again within window
+// answered with the earlier result rather than handled twice
+func WithMessageDeduplication(dedup store.MessageDeduplicator, window time.Duration) RuntimeOption {
+	return a2aTypes.WithMessageDeduplication(dedup, window)
+}
+
+// LoadDedupConfigFromEnv reads A2A_DEDUP_TABLE and A2A_DEDUP_WINDOW. The
+// config's RuntimeOptions wire its deduplicator into a handler.
+func LoadDedupConfigFromEnv() (DedupConfig, error) {
+	return a2aTypes.LoadDedupConfigFromEnv()
+}
+
+// WithTaskSweep has maintenance passes time out the tasks lister lists that
+// are past their deadline
+func WithTaskSweep(lister store.RunningTaskLister) RuntimeOption {
+	return a2aTypes.WithTaskSweep(lister)
+}
+
+// WithTaskListing serves admin/tasks/list, every task across contexts,
+// from lister
+func WithTaskListing(lister store.TaskLister) RuntimeOption {
+	return a2aTypes.WithTaskListing(lister)
+}
+
+// IsMaintenanceEvent reports whether a Lambda payload is the
+// {"maintenance": true} input asking for ServerlessA2AHandler.RunMaintenance
+func IsMaintenanceEvent(payload []byte) bool {
+	return a2aTypes.IsMaintenanceEvent(payload)
+}
+
+// WithPushConfigStore keeps the configs of tasks/pushNotificationConfig/*
+// in configs and sends each saved event to its task's configs. Without it
+// those methods fail with PushNotificationNotSupported.
+func WithPushConfigStore(configs store.PushConfigStore) RuntimeOption {
+	return a2aTypes.WithPushConfigStore(configs)
+}
+
+// WithSkillProvider publishes provider's skills on the agent card. It may
+// be given once per provider.
+func WithSkillProvider(provider SkillProvider) RuntimeOption {
+	return a2aTypes.WithSkillProvider(provider)
+}
+
+// WithCancelPolling sets how often the task of a running executor is read
+// to see whether it was canceled. Zero turns polling off.
+func WithCancelPolling(interval time.Duration) RuntimeOption {
+	return a2aTypes.WithCancelPolling(interval)
+}
+
+// WithClock sets the clock timestamps are taken from. The default is the
+// system clock.
+func WithClock(clock Clock) RuntimeOption {
+	return a2aTypes.WithClock(clock)
+}
+
+// WithIDGenerator sets the generator of task and event IDs. The default
+// generates UUIDv7s on the configured clock.
+func WithIDGenerator(ids IDGenerator) RuntimeOption {
+	return a2aTypes.WithIDGenerator(ids)
+}
+
+// NewAuthenticator creates an authenticator for the schemes in config,
+// checking credentials against secrets. client fetches OIDC metadata; nil
+// uses a client with a short timeout.
+func NewAuthenticator(config SecurityConfig, secrets SecretsConfig, client *http.Client, opts ...AuthenticatorOption) *Authenticator {
+	return a2aTypes.NewAuthenticator(config, secrets, client, opts...)
+}
+
+// LoadTracingConfigFromEnv reads the tracing config from the environment
+func LoadTracingConfigFromEnv() (TracingConfig, error) {
+	return a2aTypes.LoadTracingConfigFromEnv()
+}
+
+// SetupTracing installs the tracer provider config describes
+func SetupTracing(ctx context.Context, config TracingConfig) (*Tracing, error) {
+	return a2aTypes.SetupTracing(ctx, config)
+}
@@ -0,0 +1,39 @@
+package a2aserverless_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/a2aproject/a2a-serverless/pkg/a2aserverless"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+	"github.com/a2aproject/a2a-serverless/pkg/handler"
+)
+
+// A program's own main builds the handler from the public packages. On
+// Lambda it would pass handler.LambdaHandler(h) to lambda.Start, and use
+// the DynamoDB stores of pkg/store rather than these fakes.
+func Example() {
+	a2aHandler := a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil,
+		a2aserverless.WithExecutor(a2atest.Reply("hello back")))
+	h := handler.NewHandler(a2aHandler, agentcard.New("Embedded Agent", "https://agent.example.com"))
+
+	response := h.HandleRequest(context.Background(), a2atest.SendMessageRequest(a2atest.UserMessage("msg-1", "hello")))
+	fmt.Println(response.Status)
+	// Output: 200
+}
+
+// A worker's main executes the messages a handler without an executor, such
+// as cmd/lambda's, queued on A2A_WORK_QUEUE_URL. It would use the stores of
+// pkg/store, and the SQS client for retries.
+func ExampleNewWorkQueueConsumer() {
+	config, err := a2aserverless.LoadWorkQueueConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	a2aHandler := a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil,
+		a2aserverless.WithExecutor(a2atest.Respond("done")))
+	lambda.Start(handler.WorkQueueLambdaHandler(a2aserverless.NewWorkQueueConsumer(a2aHandler, config)))
+}
@@ -0,0 +1,318 @@
+package a2atest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+)
+
+func TestHandlerWithFakes(t *testing.T) {
+	tasks := NewTaskStore()
+	events := NewEventStore()
+	h := NewHandler(tasks, events, nil)
+	ctx := context.Background()
+
+	task := DecodeTask(t, h.HandleRequest(ctx, SendMessageRequest(UserMessage("msg-1", "hello"))))
+	AssertTaskState(t, tasks, task.ID, a2a.TaskStateWorking)
+	if got := task.History[0].Parts[0].(a2a.TextPart).Text; got != "hello" {
+		t.Errorf("expected the sent text in history, got %q", got)
+	}
+
+	DecodeTask(t, h.HandleRequest(ctx, CancelTaskRequest(task.ID)))
+	AssertTaskState(t, tasks, task.ID, a2a.TaskStateCanceled)
+	AssertEventKinds(t, events, task.ID, "status-update")
+	AssertFinalState(t, events, task.ID, a2a.TaskStateCanceled)
+
+	// Scripted store failures surface as server errors
+	tasks.GetErr = errors.New("throttled")
+	if rpcErr := DecodeError(t, h.HandleRequest(ctx, GetTaskRequest(task.ID))); rpcErr.Code != a2aTypes.JSONRPCErrorServerError {
+		t.Errorf("expected a server error, got %v", rpcErr)
+	}
+}
+
+func TestHandlerHistoryLength(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewTaskStore()
+	h := NewHandler(tasks, NewEventStore(), nil)
+	send := func(historyLength *int) a2a.Task {
+		return DecodeTask(t, h.HandleRequest(ctx, JSONRPCRequest("message/send", a2a.MessageSendParams{
+			Message: UserMessage("msg-1", "hello"),
+			Config:  &a2a.MessageSendConfig{HistoryLength: historyLength},
+		})))
+	}
+
+	zero, one := 0, 1
+	if task := send(nil); len(task.History) != 1 {
+		t.Errorf("expected the full history without historyLength, got %d messages", len(task.History))
+	}
+	task := send(&zero)
+	if len(task.History) != 0 {
+		t.Errorf("expected no history for historyLength 0, got %d messages", len(task.History))
+	}
+	if stored := tasks.Tasks(); len(stored) != 2 || len(stored[1].History) != 1 {
+		t.Errorf("expected the stored task to keep its history, got %+v", stored)
+	}
+	if got := DecodeTask(t, h.HandleRequest(ctx, JSONRPCRequest("tasks/get", a2a.TaskQueryParams{ID: task.ID, HistoryLength: &zero}))); len(got.History) != 0 {
+		t.Errorf("expected tasks/get to return no history for historyLength 0, got %d messages", len(got.History))
+	}
+	if got := DecodeTask(t, h.HandleRequest(ctx, JSONRPCRequest("tasks/get", a2a.TaskQueryParams{ID: task.ID, HistoryLength: &one}))); len(got.History) != 1 {
+		t.Errorf("expected tasks/get to return 1 message, got %d", len(got.History))
+	}
+}
+
+func TestHandlerWithExecutor(t *testing.T) {
+	ctx := context.Background()
+
+	// A direct reply is the result, and no task is kept
+	tasks := NewTaskStore()
+	h := NewHandler(tasks, NewEventStore(), nil, a2aTypes.WithExecutor(Reply("hi there")))
+	reply := DecodeMessage(t, h.HandleRequest(ctx, SendMessageRequest(UserMessage("msg-1", "hello"))))
+	if reply.Role != a2a.MessageRoleAgent || reply.Parts[0].(a2a.TextPart).Text != "hi there" {
+		t.Errorf("expected the agent's reply, got %+v", reply)
+	}
+	if len(tasks.Tasks()) != 0 {
+		t.Errorf("expected no task to be stored, got %+v", tasks.Tasks())
+	}
+
+	// Task events complete the task they are applied to
+	tasks = NewTaskStore()
+	events := NewEventStore()
+	h = NewHandler(tasks, events, nil, a2aTypes.WithExecutor(Respond("done")))
+	task := DecodeTask(t, h.HandleRequest(ctx, SendMessageRequest(UserMessage("msg-1", "hello"))))
+	AssertTaskState(t, tasks, task.ID, a2a.TaskStateCompleted)
+	AssertEventKinds(t, events, task.ID, "artifact-update", "status-update")
+	if len(task.Artifacts) != 1 || task.Artifacts[0].Parts[0].(a2a.TextPart).Text != "done" {
+		t.Errorf("expected the response artifact, got %+v", task.Artifacts)
+	}
+
+	// A reply within an existing task joins its history
+	tasks = NewTaskStore(a2a.Task{ID: "task-1", ContextID: "ctx-1", Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateInputRequired}})
+	h = NewHandler(tasks, NewEventStore(), nil, a2aTypes.WithExecutor(Reply("noted")))
+	message := UserMessage("msg-2", "more")
+	taskID := a2a.TaskID("task-1")
+	message.TaskID = &taskID
+	task = DecodeTask(t, h.HandleRequest(ctx, SendMessageRequest(message)))
+	if len(task.History) != 2 || task.History[1].Role != a2a.MessageRoleAgent {
+		t.Errorf("expected the reply in the task history, got %+v", task.History)
+	}
+
+	// A failing executor is a server error
+	failing := Respond("done")
+	failing.Err = errors.New("model unavailable")
+	h = NewHandler(NewTaskStore(), NewEventStore(), nil, a2aTypes.WithExecutor(failing))
+	if rpcErr := DecodeError(t, h.HandleRequest(ctx, SendMessageRequest(UserMessage("msg-1", "hello")))); rpcErr.Code != a2aTypes.JSONRPCErrorServerError {
+		t.Errorf("expected a server error, got %v", rpcErr)
+	}
+}
+
+// blockingExecutor writes a working status and then waits for its context
+// to end, recording what a write after that returns
+type blockingExecutor struct {
+	started  chan struct{}
+	writeErr error
+}
+
+func (e *blockingExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	if err := queue.Write(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}); err != nil {
+		return err
+	}
+	close(e.started)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		return errors.New("never canceled")
+	}
+	e.writeErr = queue.Write(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true})
+	return ctx.Err()
+}
+
+func (e *blockingExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestHandlerCancelsExecution(t *testing.T) {
+	ctx := context.Background()
+	taskID := a2a.TaskID("task-1")
+	message := UserMessage("msg-2", "more")
+	message.TaskID = &taskID
+	inputRequired := a2a.Task{ID: taskID, ContextID: "ctx-1", Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateInputRequired}}
+
+	// A cancel stored while the executor runs stops it
+	tasks, events := NewTaskStore(inputRequired), NewEventStore()
+	executor := &blockingExecutor{started: make(chan struct{})}
+	h := NewHandler(tasks, events, nil, a2aTypes.WithExecutor(executor), a2aTypes.WithCancelPolling(5*time.Millisecond))
+	sent := make(chan a2a.Task)
+	go func() { sent <- DecodeTask(t, h.HandleRequest(ctx, SendMessageRequest(message))) }()
+	<-executor.started
+	DecodeTask(t, h.HandleRequest(ctx, CancelTaskRequest(taskID)))
+
+	if task := <-sent; task.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected the canceled task, got %s", task.Status.State)
+	}
+	if !errors.Is(executor.writeErr, a2aTypes.ErrTaskCanceled) {
+		t.Errorf("expected writes after the cancel to fail, got %v", executor.writeErr)
+	}
+	AssertTaskState(t, tasks, taskID, a2a.TaskStateCanceled)
+	AssertEventKinds(t, events, taskID, "status-update")
+	AssertFinalState(t, events, taskID, a2a.TaskStateCanceled)
+
+	// Without polling, a cancel during a short execution still stands
+	tasks, events = NewTaskStore(inputRequired), NewEventStore()
+	var h2 *handler.Handler
+	cancelFirst := &cancelingExecutor{cancel: func() { DecodeTask(t, h2.HandleRequest(ctx, CancelTaskRequest(taskID))) }}
+	h2 = NewHandler(tasks, events, nil, a2aTypes.WithExecutor(cancelFirst), a2aTypes.WithCancelPolling(0))
+	if task := DecodeTask(t, h2.HandleRequest(ctx, SendMessageRequest(message))); task.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected the canceled task, got %s", task.Status.State)
+	}
+	AssertTaskState(t, tasks, taskID, a2a.TaskStateCanceled)
+	AssertEventKinds(t, events, taskID, "status-update")
+}
+
+// cancelingExecutor cancels its own task, then completes it
+type cancelingExecutor struct {
+	cancel func()
+}
+
+func (e *cancelingExecutor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	e.cancel()
+	return queue.Write(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}, Final: true})
+}
+
+func (e *cancelingExecutor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	return nil
+}
+
+func TestExecutor(t *testing.T) {
+	executor := Respond("done")
+	queue := &Queue{}
+	reqCtx := a2asrv.RequestContext{TaskID: "task-1", ContextID: "ctx-1"}
+
+	if err := executor.Execute(context.Background(), reqCtx, queue); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	written := queue.Events()
+	if len(written) != 2 || EventTaskID(written[0]) != "task-1" || EventTaskID(written[1]) != "task-1" {
+		t.Fatalf("expected events stamped with the task ID, got %+v", written)
+	}
+	if len(executor.Requests()) != 1 {
+		t.Errorf("expected one recorded request, got %d", len(executor.Requests()))
+	}
+
+	if err := executor.Cancel(context.Background(), reqCtx, queue); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	canceled := queue.Events()[2].(a2a.TaskStatusUpdateEvent)
+	if canceled.Status.State != a2a.TaskStateCanceled || !canceled.Final {
+		t.Errorf("expected a final canceled status, got %+v", canceled)
+	}
+
+	queue.Err = errors.New("queue closed")
+	if err := executor.Execute(context.Background(), reqCtx, queue); err == nil {
+		t.Error("expected the queue error")
+	}
+}
+
+func TestPushNotifier(t *testing.T) {
+	notifier := &PushNotifier{}
+	config := a2a.PushConfig{URL: "https://hooks.example.com"}
+	if err := notifier.SendNotification(context.Background(), config, a2a.Task{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	AssertNotified(t, notifier, 1)
+
+	notifier.Err = errors.New("unreachable")
+	if err := notifier.SendNotification(context.Background(), config, a2a.Task{ID: "task-1"}); err == nil {
+		t.Error("expected error but got none")
+	}
+	AssertNotified(t, notifier, 1)
+}
+
+func TestEventStoreDeleteEvents(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewTaskStore(a2a.Task{ID: "task-1", ContextID: "ctx-1"}, a2a.Task{ID: "task-2", ContextID: "ctx-2"})
+	events := NewEventStore()
+	for _, id := range []a2a.TaskID{"task-1", "task-2", "task-1"} {
+		events.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: id})
+	}
+
+	h := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, events, nil)
+	report, err := h.Purge(ctx, a2aTypes.PurgeQuery{ContextID: "ctx-1"}, a2aTypes.PurgeStores{})
+	if err != nil || report.Events != 2 {
+		t.Fatalf("expected the task's 2 events purged, got %+v, %v", report, err)
+	}
+	AssertEventKinds(t, events, "task-2", "status-update")
+	if len(events.Events()) != 1 || len(tasks.Tasks()) != 1 {
+		t.Errorf("expected the other task and its event kept, got %d events", len(events.Events()))
+	}
+}
+
+func TestStoresDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	tasks := NewTaskStore(a2a.Task{ID: "task-1"}, a2a.Task{ID: "task-2", Artifacts: []a2a.Artifact{{ArtifactID: "a-1"}}})
+	events := NewEventStore()
+	events.SaveEvent(ctx, a2a.TaskStatusUpdateEvent{Kind: "status-update", TaskID: "task-1"})
+	events.SaveEvent(ctx, a2a.TaskArtifactUpdateEvent{Kind: "artifact-update", TaskID: "task-2"})
+
+	policy := a2aTypes.RetentionPolicy{Tasks: time.Hour, Events: time.Minute, Artifacts: 24 * time.Hour}
+	sweeper := a2aTypes.NewSweeper(policy, []a2aTypes.ExpiringStore{tasks, events}, a2aTypes.WithClock(clockAt(time.Now().Add(2*time.Hour))))
+	counts, err := sweeper.Sweep(ctx)
+	if err != nil || counts != (a2aTypes.SweepCounts{Tasks: 1, Events: 1}) {
+		t.Fatalf("expected a task and an event swept, got %+v, %v", counts, err)
+	}
+	// Artifacts are kept for their own, longer retention
+	if got := tasks.Tasks(); len(got) != 1 || got[0].ID != "task-2" {
+		t.Errorf("expected the task with artifacts kept, got %+v", got)
+	}
+	AssertEventKinds(t, events, "task-2", "artifact-update")
+
+	counts, _ = a2aTypes.NewSweeper(policy, []a2aTypes.ExpiringStore{tasks, events}, a2aTypes.WithClock(clockAt(time.Now().Add(25*time.Hour)))).Sweep(ctx)
+	if counts != (a2aTypes.SweepCounts{Artifacts: 2}) || len(tasks.Tasks()) != 0 || len(events.Events()) != 0 {
+		t.Errorf("expected the artifacts swept, got %+v", counts)
+	}
+}
+
+func TestTaskStoreRunningTaskIDs(t *testing.T) {
+	ctx := context.Background()
+	deadline := map[string]any{a2aTypes.TaskDeadlineMetadataKey: "2025-08-01T12:00:00Z"}
+	tasks := NewTaskStore(
+		a2a.Task{ID: "task-1", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}, Metadata: deadline},
+		a2a.Task{ID: "task-2", Status: a2a.TaskStatus{State: a2a.TaskStateCompleted}},
+	)
+	events := NewEventStore()
+
+	// A maintenance pass given the store fails the running task past its
+	// deadline, and leaves the finished one
+	h := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, events, nil,
+		a2aTypes.WithTaskSweep(tasks), a2aTypes.WithClock(clockAt(time.Date(2025, 8, 1, 13, 0, 0, 0, time.UTC))))
+	report, err := h.RunMaintenance(ctx)
+	if err != nil || report.TimedOut != 1 {
+		t.Fatalf("expected one task timed out, got %+v: %v", report, err)
+	}
+	AssertTaskState(t, tasks, "task-1", a2a.TaskStateFailed)
+	AssertTaskState(t, tasks, "task-2", a2a.TaskStateCompleted)
+	AssertFinalState(t, events, "task-1", a2a.TaskStateFailed)
+}
+
+func TestTaskStoreTaskIDs(t *testing.T) {
+	tasks := NewTaskStore(
+		a2a.Task{ID: "task-1", ContextID: "ctx-1"},
+		a2a.Task{ID: "task-2", ContextID: "ctx-2"},
+	)
+	h := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, NewEventStore(), nil, a2aTypes.WithTaskListing(tasks))
+	page, err := h.ListAllTasks(context.Background(), a2aTypes.TaskListParams{})
+	if err != nil || len(page.Tasks) != 2 || page.Tasks[0].ID != "task-1" {
+		t.Errorf("expected both tasks in the order saved, got %+v: %v", page, err)
+	}
+}
+
+// clockAt is a clock stopped at now
+type clockAt time.Time
+
+func (c clockAt) Now() time.Time { return time.Time(c) }
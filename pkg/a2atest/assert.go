@@ -0,0 +1,79 @@
+package a2atest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// EventKind returns the Kind discriminator of an event, which the SDK only
+// sets when the event was built with it, so it is derived from the type
+func EventKind(event a2a.Event) string {
+	switch event.(type) {
+	case a2a.Task:
+		return "task"
+	case a2a.Message:
+		return "message"
+	case a2a.TaskStatusUpdateEvent:
+		return "status-update"
+	case a2a.TaskArtifactUpdateEvent:
+		return "artifact-update"
+	}
+	return ""
+}
+
+// AssertEventKinds fails the test unless the events stored for taskID have
+// exactly the given kinds, in order
+func AssertEventKinds(t testing.TB, store *EventStore, taskID a2a.TaskID, kinds ...string) {
+	t.Helper()
+	var got []string
+	for _, event := range store.Events() {
+		if EventTaskID(event) == taskID {
+			got = append(got, EventKind(event))
+		}
+	}
+	if !slices.Equal(got, kinds) {
+		t.Errorf("expected events %v for task %s, got %v", kinds, taskID, got)
+	}
+}
+
+// AssertFinalState fails the test unless the last status update stored for
+// taskID is final and in state
+func AssertFinalState(t testing.TB, store *EventStore, taskID a2a.TaskID, state a2a.TaskState) {
+	t.Helper()
+	events := store.Events()
+	for i := len(events) - 1; i >= 0; i-- {
+		update, ok := events[i].(a2a.TaskStatusUpdateEvent)
+		if !ok || update.TaskID != taskID {
+			continue
+		}
+		if !update.Final || update.Status.State != state {
+			t.Errorf("expected final state %s for task %s, got %s (final %v)", state, taskID, update.Status.State, update.Final)
+		}
+		return
+	}
+	t.Errorf("expected final state %s for task %s, got no status updates", state, taskID)
+}
+
+// AssertTaskState fails the test unless the stored task is in state
+func AssertTaskState(t testing.TB, store *TaskStore, taskID a2a.TaskID, state a2a.TaskState) {
+	t.Helper()
+	task, err := store.GetTask(context.Background(), taskID)
+	if err != nil {
+		t.Errorf("expected task %s in state %s: %v", taskID, state, err)
+		return
+	}
+	if task.Status.State != state {
+		t.Errorf("expected task %s in state %s, got %s", taskID, state, task.Status.State)
+	}
+}
+
+// AssertNotified fails the test unless count notifications were sent
+func AssertNotified(t testing.TB, notifier *PushNotifier, count int) {
+	t.Helper()
+	if got := len(notifier.Notifications()); got != count {
+		t.Errorf("expected %d push notifications, got %d", count, got)
+	}
+}
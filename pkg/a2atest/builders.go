@@ -0,0 +1,122 @@
+package a2atest
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// AgentURL is the card URL of handlers built by NewHandler
+const AgentURL = "https://agent.example.com"
+
+// NewHandler builds the serverless handler over the given fakes, with no
+// authentication and a card named "Test Agent". A nil notifier sends nothing;
+// opts such as a2a.WithExecutor configure the serverless handler.
+func NewHandler(tasks *TaskStore, events *EventStore, notifier *PushNotifier, opts ...a2aTypes.RuntimeOption) *handler.Handler {
+	var pushNotifier a2aTypes.PushNotifier
+	if notifier != nil {
+		pushNotifier = notifier
+	}
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, tasks, events, pushNotifier, opts...)
+	return handler.NewHandler(a2aHandler, agentcard.New("Test Agent", AgentURL))
+}
+
+// TextPart creates a text part
+func TextPart(text string) a2a.TextPart {
+	return a2a.TextPart{Kind: "text", Text: text}
+}
+
+// UserMessage creates a user message with one text part
+func UserMessage(id, text string) a2a.Message {
+	return a2a.Message{Kind: "message", MessageID: id, Role: a2a.MessageRoleUser, Parts: []a2a.Part{TextPart(text)}}
+}
+
+// JSONRPCRequest builds a POST carrying a JSON-RPC call, with the lower-case
+// headers API Gateway sends
+func JSONRPCRequest(method string, params any) handler.Request {
+	body, err := a2aTypes.SerializeJSONRPCRequest(a2aTypes.NewJSONRPCRequest(method, params, 1))
+	if err != nil {
+		panic(fmt.Sprintf("a2atest: unencodable params: %v", err))
+	}
+	return handler.Request{
+		Method:  "POST",
+		URL:     "/",
+		Headers: map[string]string{"content-type": "application/json"},
+		Body:    string(body),
+	}
+}
+
+// SendMessageRequest builds a message/send call
+func SendMessageRequest(message a2a.Message) handler.Request {
+	return JSONRPCRequest("message/send", a2a.MessageSendParams{Message: message})
+}
+
+// GetTaskRequest builds a tasks/get call
+func GetTaskRequest(taskID a2a.TaskID) handler.Request {
+	return JSONRPCRequest("tasks/get", a2a.TaskQueryParams{ID: taskID})
+}
+
+// CancelTaskRequest builds a tasks/cancel call
+func CancelTaskRequest(taskID a2a.TaskID) handler.Request {
+	return JSONRPCRequest("tasks/cancel", a2a.TaskIDParams{ID: taskID})
+}
+
+// DecodeTask fails the test unless resp is a JSON-RPC success carrying a task
+func DecodeTask(t testing.TB, resp handler.Response) a2a.Task {
+	t.Helper()
+	rpcResp := decodeResponse(t, resp)
+	if rpcResp.Error != nil {
+		t.Fatalf("expected a task, got JSON-RPC error %v", rpcResp.Error)
+	}
+	result, _ := rpcResp.ResultJSON()
+	task, err := a2aTypes.UnmarshalTask(result)
+	if err != nil {
+		t.Fatalf("expected a task, got %s: %v", result, err)
+	}
+	return task
+}
+
+// DecodeMessage fails the test unless resp carries a direct Message result,
+// and returns it
+func DecodeMessage(t testing.TB, resp handler.Response) a2a.Message {
+	t.Helper()
+	rpcResp := decodeResponse(t, resp)
+	if rpcResp.Error != nil {
+		t.Fatalf("expected a message, got JSON-RPC error %v", rpcResp.Error)
+	}
+	result, _ := rpcResp.ResultJSON()
+	var kind struct{ Kind string }
+	if err := json.Unmarshal(result, &kind); err != nil || kind.Kind != "message" {
+		t.Fatalf("expected a message result, got %s", result)
+	}
+	message, err := a2aTypes.UnmarshalMessage(result)
+	if err != nil {
+		t.Fatalf("expected a message, got %s: %v", result, err)
+	}
+	return message
+}
+
+// DecodeError fails the test unless resp is a JSON-RPC error, and returns it
+func DecodeError(t testing.TB, resp handler.Response) *a2aTypes.JSONRPCError {
+	t.Helper()
+	rpcResp := decodeResponse(t, resp)
+	if rpcResp.Error == nil {
+		t.Fatalf("expected a JSON-RPC error, got result %s", rpcResp.Result)
+	}
+	return rpcResp.Error
+}
+
+func decodeResponse(t testing.TB, resp handler.Response) a2aTypes.JSONRPCResponse {
+	t.Helper()
+	rpcResp, err := a2aTypes.ParseJSONRPCResponse([]byte(resp.Body))
+	if err != nil {
+		t.Fatalf("expected a JSON-RPC response, got HTTP %d %q: %v", resp.Status, resp.Body, err)
+	}
+	return rpcResp
+}
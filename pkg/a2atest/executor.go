@@ -0,0 +1,151 @@
+package a2atest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// Executor is an a2asrv.AgentExecutor that writes canned events. Events with
+// no task or context ID are stamped with the request's, so scripts need not
+// repeat them.
+type Executor struct {
+	// Events are written by every Execute call
+	Events []a2a.Event
+	// Err is returned by Execute after the events are written
+	Err error
+	// CancelErr is returned by Cancel instead of writing a canceled status
+	CancelErr error
+
+	mu       sync.Mutex
+	requests []a2asrv.RequestContext
+	canceled []a2asrv.RequestContext
+}
+
+// Verify that Executor implements the AgentExecutor interface
+var _ a2asrv.AgentExecutor = (*Executor)(nil)
+
+// Respond creates an executor that completes every task with text as its
+// only artifact
+func Respond(text string) *Executor {
+	lastChunk := true
+	return &Executor{Events: []a2a.Event{
+		a2a.TaskArtifactUpdateEvent{
+			Kind:      "artifact-update",
+			Artifact:  a2a.Artifact{ArtifactID: "response", Parts: []a2a.Part{TextPart(text)}},
+			LastChunk: &lastChunk,
+		},
+		a2a.TaskStatusUpdateEvent{
+			Kind:   "status-update",
+			Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Final:  true,
+		},
+	}}
+}
+
+// Reply creates an executor that answers every message directly with text,
+// without creating a task
+func Reply(text string) *Executor {
+	return &Executor{Events: []a2a.Event{
+		a2a.Message{Kind: "message", MessageID: "reply", Role: a2a.MessageRoleAgent, Parts: []a2a.Part{TextPart(text)}},
+	}}
+}
+
+// Execute implements a2asrv.AgentExecutor
+func (e *Executor) Execute(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	e.mu.Lock()
+	e.requests = append(e.requests, reqCtx)
+	e.mu.Unlock()
+
+	for _, event := range e.Events {
+		if err := queue.Write(ctx, stamp(event, reqCtx)); err != nil {
+			return err
+		}
+	}
+	return e.Err
+}
+
+// Cancel implements a2asrv.AgentExecutor
+func (e *Executor) Cancel(ctx context.Context, reqCtx a2asrv.RequestContext, queue a2asrv.EventWriter) error {
+	e.mu.Lock()
+	e.canceled = append(e.canceled, reqCtx)
+	e.mu.Unlock()
+
+	if e.CancelErr != nil {
+		return e.CancelErr
+	}
+	return queue.Write(ctx, a2a.TaskStatusUpdateEvent{
+		Kind:      "status-update",
+		TaskID:    reqCtx.TaskID,
+		ContextID: reqCtx.ContextID,
+		Status:    a2a.TaskStatus{State: a2a.TaskStateCanceled},
+		Final:     true,
+	})
+}
+
+// Requests returns the request contexts Execute was called with
+func (e *Executor) Requests() []a2asrv.RequestContext {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]a2asrv.RequestContext(nil), e.requests...)
+}
+
+// Canceled returns the request contexts Cancel was called with
+func (e *Executor) Canceled() []a2asrv.RequestContext {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]a2asrv.RequestContext(nil), e.canceled...)
+}
+
+// stamp fills in the task and context IDs an event leaves empty
+func stamp(event a2a.Event, reqCtx a2asrv.RequestContext) a2a.Event {
+	switch e := event.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		if e.TaskID == "" {
+			e.TaskID = reqCtx.TaskID
+		}
+		if e.ContextID == "" {
+			e.ContextID = reqCtx.ContextID
+		}
+		return e
+	case a2a.TaskArtifactUpdateEvent:
+		if e.TaskID == "" {
+			e.TaskID = reqCtx.TaskID
+		}
+		if e.ContextID == "" {
+			e.ContextID = reqCtx.ContextID
+		}
+		return e
+	}
+	return event
+}
+
+// Queue is an a2asrv.EventWriter that keeps the events written to it, for
+// testing executors directly
+type Queue struct {
+	// Err makes every write fail with it
+	Err error
+
+	mu     sync.Mutex
+	events []a2a.Event
+}
+
+// Write implements a2asrv.EventWriter
+func (q *Queue) Write(ctx context.Context, event a2a.Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.Err != nil {
+		return q.Err
+	}
+	q.events = append(q.events, event)
+	return nil
+}
+
+// Events returns the events written so far
+func (q *Queue) Events() []a2a.Event {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]a2a.Event(nil), q.events...)
+}
@@ -0,0 +1,385 @@
+// Package a2atest provides in-memory fakes of the serverless handler's
+// dependencies, request builders and assertions, so agents can be unit-tested
+// without AWS. Fakes are safe for concurrent use.
+package a2atest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// TaskStore keeps tasks in memory. Setting one of its error fields makes the
+// matching method fail with it.
+type TaskStore struct {
+	GetErr    error
+	SaveErr   error
+	DeleteErr error
+	ListErr   error
+
+	mu    sync.Mutex
+	tasks map[a2a.TaskID]a2a.Task
+	order []a2a.TaskID
+	// saved holds when each task was last saved, for DeleteExpired
+	saved map[a2a.TaskID]time.Time
+}
+
+// NewTaskStore creates a task store holding tasks
+func NewTaskStore(tasks ...a2a.Task) *TaskStore {
+	s := &TaskStore{tasks: make(map[a2a.TaskID]a2a.Task), saved: make(map[a2a.TaskID]time.Time)}
+	for _, task := range tasks {
+		s.put(task)
+	}
+	return s
+}
+
+// GetTask implements the handler's TaskStore
+func (s *TaskStore) GetTask(ctx context.Context, taskID a2a.TaskID) (a2a.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.GetErr != nil {
+		return a2a.Task{}, s.GetErr
+	}
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return a2a.Task{}, fmt.Errorf("task %s: %w", taskID, a2a.ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+// SaveTask implements the handler's TaskStore, refusing to move a finished
+// task to another state as the AWS store does
+func (s *TaskStore) SaveTask(ctx context.Context, task a2a.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SaveErr != nil {
+		return s.SaveErr
+	}
+	if stored, ok := s.tasks[task.ID]; ok {
+		if err := a2aTypes.CheckTaskSave(stored, task); err != nil {
+			return err
+		}
+	}
+	s.put(task)
+	return nil
+}
+
+// SaveTaskHeartbeat implements the handler's TaskHeartbeatStore, updating
+// only the heartbeat of a submitted or working task as the AWS store does
+func (s *TaskStore) SaveTaskHeartbeat(ctx context.Context, task a2a.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SaveErr != nil {
+		return s.SaveErr
+	}
+	stored, ok := s.tasks[task.ID]
+	if !ok || (stored.Status.State != a2a.TaskStateSubmitted && stored.Status.State != a2a.TaskStateWorking) {
+		return fmt.Errorf("task %s: %w", task.ID, a2aTypes.ErrTaskNotRunning)
+	}
+	metadata := maps.Clone(stored.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	for _, key := range []string{a2aTypes.TaskLastSeenMetadataKey, a2aTypes.TaskDeadlineMetadataKey} {
+		if value, ok := task.Metadata[key]; ok {
+			metadata[key] = value
+		}
+	}
+	stored.Metadata = metadata
+	s.put(stored)
+	return nil
+}
+
+// DeleteTask implements the handler's TaskStore
+func (s *TaskStore) DeleteTask(ctx context.Context, taskID a2a.TaskID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.DeleteErr != nil {
+		return s.DeleteErr
+	}
+	delete(s.tasks, taskID)
+	delete(s.saved, taskID)
+	for i, id := range s.order {
+		if id == taskID {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ListTasks implements the handler's TaskStore, in the order tasks were first saved
+func (s *TaskStore) ListTasks(ctx context.Context, contextID string) ([]a2a.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ListErr != nil {
+		return nil, s.ListErr
+	}
+	var tasks []a2a.Task
+	for _, id := range s.order {
+		if task := s.tasks[id]; contextID == "" || task.ContextID == contextID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// RunningTaskIDs lists the submitted and working tasks, as maintenance
+// passes given the store with WithTaskSweep ask
+func (s *TaskStore) RunningTaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	s.mu.Lock()
+	var running []a2a.TaskID
+	for _, id := range s.order {
+		if state := s.tasks[id].Status.State; state == a2a.TaskStateSubmitted || state == a2a.TaskStateWorking {
+			running = append(running, id)
+		}
+	}
+	s.mu.Unlock()
+	return func(yield func(a2a.TaskID, error) bool) {
+		for _, id := range running {
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+// TaskIDs lists every task in the order saved, as admin/tasks/list asks
+// of the store given with WithTaskListing
+func (s *TaskStore) TaskIDs(ctx context.Context) iter.Seq2[a2a.TaskID, error] {
+	s.mu.Lock()
+	ids := slices.Clone(s.order)
+	s.mu.Unlock()
+	return func(yield func(a2a.TaskID, error) bool) {
+		for _, id := range ids {
+			if !yield(id, nil) {
+				return
+			}
+		}
+	}
+}
+
+// DeleteExpired deletes the tasks policy no longer keeps at now, as the
+// retention sweeper asks of stores without native TTL
+func (s *TaskStore) DeleteExpired(ctx context.Context, policy a2aTypes.RetentionPolicy, now time.Time) (a2aTypes.SweepCounts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var counts a2aTypes.SweepCounts
+	if s.DeleteErr != nil {
+		return counts, s.DeleteErr
+	}
+	kept := s.order[:0]
+	for _, id := range s.order {
+		retention, kind := policy.TaskRetention(s.tasks[id])
+		if retention > 0 && !now.Before(s.saved[id].Add(retention)) {
+			delete(s.tasks, id)
+			delete(s.saved, id)
+			counts.Add(kind, 1)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.order = kept
+	return counts, nil
+}
+
+// Tasks returns every stored task in the order they were first saved
+func (s *TaskStore) Tasks() []a2a.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := make([]a2a.Task, 0, len(s.order))
+	for _, id := range s.order {
+		tasks = append(tasks, s.tasks[id])
+	}
+	return tasks
+}
+
+func (s *TaskStore) put(task a2a.Task) {
+	if _, ok := s.tasks[task.ID]; !ok {
+		s.order = append(s.order, task.ID)
+	}
+	s.tasks[task.ID] = task
+	s.saved[task.ID] = time.Now()
+}
+
+// EventStore keeps events in memory in the order they were saved. Setting one
+// of its error fields makes the matching method fail with it.
+type EventStore struct {
+	SaveErr   error
+	GetErr    error
+	MarkErr   error
+	DeleteErr error
+
+	mu        sync.Mutex
+	events    []a2a.Event
+	processed map[string]bool
+	// saved holds when each event was saved, for DeleteExpired
+	saved []time.Time
+}
+
+// NewEventStore creates an empty event store
+func NewEventStore() *EventStore {
+	return &EventStore{processed: make(map[string]bool)}
+}
+
+// SaveEvent implements the handler's EventStore
+func (s *EventStore) SaveEvent(ctx context.Context, event a2a.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.SaveErr != nil {
+		return s.SaveErr
+	}
+	s.events = append(s.events, event)
+	s.saved = append(s.saved, time.Now())
+	return nil
+}
+
+// GetEvents implements the handler's EventStore
+func (s *EventStore) GetEvents(ctx context.Context, taskID a2a.TaskID) ([]a2a.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.GetErr != nil {
+		return nil, s.GetErr
+	}
+	var events []a2a.Event
+	for _, event := range s.events {
+		if EventTaskID(event) == taskID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// MarkEventProcessed implements the handler's EventStore
+func (s *EventStore) MarkEventProcessed(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.MarkErr != nil {
+		return s.MarkErr
+	}
+	s.processed[eventID] = true
+	return nil
+}
+
+// DeleteEvents deletes the events of a task, as the handler's purge asks of
+// event stores
+func (s *EventStore) DeleteEvents(ctx context.Context, taskID a2a.TaskID) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.DeleteErr != nil {
+		return 0, s.DeleteErr
+	}
+	before := len(s.events)
+	s.deleteEvents(func(i int) bool { return EventTaskID(s.events[i]) == taskID }, nil)
+	return before - len(s.events), nil
+}
+
+// DeleteExpired deletes the events policy no longer keeps at now, as the
+// retention sweeper asks of stores without native TTL
+func (s *EventStore) DeleteExpired(ctx context.Context, policy a2aTypes.RetentionPolicy, now time.Time) (a2aTypes.SweepCounts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var counts a2aTypes.SweepCounts
+	if s.DeleteErr != nil {
+		return counts, s.DeleteErr
+	}
+	s.deleteEvents(func(i int) bool {
+		retention, _ := policy.EventRetention(s.events[i])
+		return retention > 0 && !now.Before(s.saved[i].Add(retention))
+	}, func(i int) {
+		_, kind := policy.EventRetention(s.events[i])
+		counts.Add(kind, 1)
+	})
+	return counts, nil
+}
+
+// deleteEvents deletes the events at the indexes del reports, in place,
+// calling deleted, when set, with each index before it goes
+func (s *EventStore) deleteEvents(del func(i int) bool, deleted func(i int)) {
+	kept := 0
+	for i := range s.events {
+		if del(i) {
+			if deleted != nil {
+				deleted(i)
+			}
+			continue
+		}
+		s.events[kept], s.saved[kept] = s.events[i], s.saved[i]
+		kept++
+	}
+	clear(s.events[kept:])
+	s.events, s.saved = s.events[:kept], s.saved[:kept]
+}
+
+// Events returns every saved event, for all tasks
+func (s *EventStore) Events() []a2a.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]a2a.Event(nil), s.events...)
+}
+
+// Processed reports whether eventID was marked processed
+func (s *EventStore) Processed(eventID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[eventID]
+}
+
+// Notification is a push notification sent through PushNotifier
+type Notification struct {
+	Config a2a.PushConfig
+	Event  a2a.Event
+}
+
+// PushNotifier records notifications instead of sending them. Setting Err
+// makes every send fail with it.
+type PushNotifier struct {
+	Err error
+
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+// SendNotification implements the handler's PushNotifier
+func (n *PushNotifier) SendNotification(ctx context.Context, config a2a.PushConfig, event a2a.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.Err != nil {
+		return n.Err
+	}
+	n.notifications = append(n.notifications, Notification{Config: config, Event: event})
+	return nil
+}
+
+// Notifications returns the notifications sent so far
+func (n *PushNotifier) Notifications() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.notifications...)
+}
+
+// EventTaskID returns the ID of the task an event belongs to, empty for a
+// message outside any task
+func EventTaskID(event a2a.Event) a2a.TaskID {
+	switch e := event.(type) {
+	case a2a.Task:
+		return e.ID
+	case a2a.TaskStatusUpdateEvent:
+		return e.TaskID
+	case a2a.TaskArtifactUpdateEvent:
+		return e.TaskID
+	case a2a.Message:
+		if e.TaskID != nil {
+			return *e.TaskID
+		}
+	}
+	return ""
+}
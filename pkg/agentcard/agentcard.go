@@ -0,0 +1,128 @@
+// Package agentcard builds A2A agent cards in code. It lives outside internal/
+// so programs embedding the serverless handler can describe their own agents.
+package agentcard
+
+import (
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// Option configures an agent card built by New
+type Option func(*a2a.AgentCard)
+
+// New builds an agent card for code that constructs cards directly.
+// Cards default to version 1.0.0 over JSONRPC; capabilities stay unset unless
+// an option declares them, so the card only advertises what was asked for.
+func New(name, url string, opts ...Option) a2a.AgentCard {
+	card := a2a.AgentCard{
+		Name:               name,
+		URL:                url,
+		Version:            "1.0.0",
+		PreferredTransport: a2a.TransportProtocolJSONRPC,
+	}
+	for _, opt := range opts {
+		opt(&card)
+	}
+	return card
+}
+
+// WithDescription sets the card description
+func WithDescription(description string) Option {
+	return func(card *a2a.AgentCard) {
+		card.Description = description
+	}
+}
+
+// WithVersion sets the agent version
+func WithVersion(version string) Option {
+	return func(card *a2a.AgentCard) {
+		card.Version = version
+	}
+}
+
+// WithProtocolVersion sets the A2A protocol version the agent implements
+func WithProtocolVersion(version string) Option {
+	return func(card *a2a.AgentCard) {
+		card.ProtocolVersion = version
+	}
+}
+
+// WithProvider sets the organization that runs the agent
+func WithProvider(org, url string) Option {
+	return func(card *a2a.AgentCard) {
+		card.Provider = &a2a.AgentProvider{Org: org, URL: url}
+	}
+}
+
+// WithDocumentationURL sets the agent's documentation link
+func WithDocumentationURL(url string) Option {
+	return func(card *a2a.AgentCard) {
+		card.DocumentationURL = &url
+	}
+}
+
+// WithIconURL sets the agent's icon
+func WithIconURL(url string) Option {
+	return func(card *a2a.AgentCard) {
+		card.IconURL = &url
+	}
+}
+
+// WithSkill appends a skill to the card
+func WithSkill(skill a2a.AgentSkill) Option {
+	return func(card *a2a.AgentCard) {
+		card.Skills = append(card.Skills, skill)
+	}
+}
+
+// WithSkills replaces the card's skills
+func WithSkills(skills []a2a.AgentSkill) Option {
+	return func(card *a2a.AgentCard) {
+		card.Skills = skills
+	}
+}
+
+// WithStreaming declares whether the agent supports streaming
+func WithStreaming(enabled bool) Option {
+	return func(card *a2a.AgentCard) {
+		card.Capabilities.Streaming = &enabled
+	}
+}
+
+// WithPushNotifications declares whether the agent supports push notifications
+func WithPushNotifications(enabled bool) Option {
+	return func(card *a2a.AgentCard) {
+		card.Capabilities.PushNotifications = &enabled
+	}
+}
+
+// WithStateTransitionHistory declares whether the agent keeps task state history
+func WithStateTransitionHistory(enabled bool) Option {
+	return func(card *a2a.AgentCard) {
+		card.Capabilities.StateTransitionHistory = &enabled
+	}
+}
+
+// WithPreferredTransport sets the transport served at the card URL
+func WithPreferredTransport(transport a2a.TransportProtocol) Option {
+	return func(card *a2a.AgentCard) {
+		card.PreferredTransport = transport
+	}
+}
+
+// WithAdditionalInterface advertises another transport served at url
+func WithAdditionalInterface(transport a2a.TransportProtocol, url string) Option {
+	return func(card *a2a.AgentCard) {
+		card.AdditionalInterfaces = append(card.AdditionalInterfaces, a2a.AgentInterface{
+			Transport: string(transport),
+			URL:       url,
+		})
+	}
+}
+
+// WithDefaultModes sets the media types accepted and produced across all skills
+func WithDefaultModes(inputModes, outputModes []string) Option {
+	return func(card *a2a.AgentCard) {
+		card.DefaultInputModes = inputModes
+		card.DefaultOutputModes = outputModes
+	}
+}
@@ -0,0 +1,60 @@
+package agentcard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	card := New("Test Agent", "https://agent.example.com")
+
+	if card.Name != "Test Agent" || card.URL != "https://agent.example.com" {
+		t.Errorf("expected name and URL to be set, got %q %q", card.Name, card.URL)
+	}
+	if card.Version != "1.0.0" {
+		t.Errorf("expected default version '1.0.0', got '%s'", card.Version)
+	}
+	if card.PreferredTransport != a2a.TransportProtocolJSONRPC {
+		t.Errorf("expected JSONRPC transport, got '%s'", card.PreferredTransport)
+	}
+	if !reflect.DeepEqual(a2a.AgentCapabilities{}, card.Capabilities) {
+		t.Errorf("expected no capabilities to be declared, got %+v", card.Capabilities)
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	skill := a2a.AgentSkill{ID: "search", Name: "Search"}
+	card := New("Test Agent", "https://agent.example.com",
+		WithDescription("Finds things"),
+		WithVersion("2.1.0"),
+		WithProvider("Example Org", "https://example.com"),
+		WithSkill(skill),
+		WithSkill(a2a.AgentSkill{ID: "summarize", Name: "Summarize"}),
+		WithStreaming(true),
+		WithPushNotifications(false),
+		WithAdditionalInterface(a2a.TransportProtocolHTTPJSON, "https://agent.example.com/rest"),
+	)
+
+	if card.Description != "Finds things" || card.Version != "2.1.0" {
+		t.Errorf("expected description and version to be set, got %q %q", card.Description, card.Version)
+	}
+	if card.Provider == nil || card.Provider.Org != "Example Org" {
+		t.Errorf("expected provider 'Example Org', got %+v", card.Provider)
+	}
+	if len(card.Skills) != 2 || card.Skills[0].ID != "search" || card.Skills[1].ID != "summarize" {
+		t.Errorf("expected skills to be appended in order, got %+v", card.Skills)
+	}
+	enabled, disabled := true, false
+	expectedCapabilities := a2a.AgentCapabilities{
+		Streaming:         &enabled,
+		PushNotifications: &disabled,
+	}
+	if !reflect.DeepEqual(expectedCapabilities, card.Capabilities) {
+		t.Errorf("expected capabilities %+v, got %+v", expectedCapabilities, card.Capabilities)
+	}
+	if len(card.AdditionalInterfaces) != 1 || card.AdditionalInterfaces[0].Transport != string(a2a.TransportProtocolHTTPJSON) {
+		t.Errorf("expected HTTP+JSON additional interface, got %+v", card.AdditionalInterfaces)
+	}
+}
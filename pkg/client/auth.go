@@ -0,0 +1,380 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// Security scheme types credentials can satisfy, as named on agent cards
+const (
+	SchemeTypeAPIKey = "apiKey"
+	SchemeTypeOAuth2 = "oauth2"
+)
+
+// DefaultSigV4Service is the signing name of API Gateway, which fronts IAM-protected agents
+const DefaultSigV4Service = "execute-api"
+
+// maxTokenBytes bounds how much of a token response is read
+const maxTokenBytes = 64 << 10
+
+// tokenExpiryMargin refreshes OAuth2 tokens early, so a token never expires
+// between being attached and being checked
+const tokenExpiryMargin = time.Minute
+
+// SecurityScheme is the part of a card's security scheme that credentials
+// read. Fields match the card JSON case-insensitively, so both the spec's
+// names and the Go SDK's encoding decode.
+type SecurityScheme struct {
+	Type  string
+	In    string
+	Name  string
+	Flows struct {
+		ClientCredentials *struct {
+			TokenURL string
+		}
+	}
+}
+
+// Credentials attach authentication to outbound calls
+type Credentials interface {
+	// SchemeType is the card scheme type the credentials satisfy. Empty
+	// means they are attached to every call, whatever the card declares.
+	SchemeType() string
+	// Authorize adds credentials to req, whose body is body. scheme is the
+	// card's declaration of the scheme being satisfied, nil when the client
+	// has no card or SchemeType is empty.
+	Authorize(ctx context.Context, req *http.Request, body []byte, scheme *SecurityScheme) error
+}
+
+// WithCredentials sets the credentials attached to calls. A discovered
+// client uses the first of the card's security requirements the credentials
+// satisfy; a client created by New attaches all of them.
+func WithCredentials(credentials ...Credentials) Option {
+	return func(c *Client) {
+		c.credentials = credentials
+	}
+}
+
+// boundCredentials pairs credentials with the card scheme they satisfy
+type boundCredentials struct {
+	credentials Credentials
+	scheme      *SecurityScheme
+}
+
+// selectCredentials picks the credentials to attach for card. Credentials
+// with no scheme type come last, since SigV4 signs the headers set before it.
+func selectCredentials(card *a2a.AgentCard, credentials []Credentials) ([]boundCredentials, error) {
+	var selected, always []boundCredentials
+	for _, cred := range credentials {
+		if cred.SchemeType() == "" {
+			always = append(always, boundCredentials{credentials: cred})
+		} else if card == nil {
+			selected = append(selected, boundCredentials{credentials: cred})
+		}
+	}
+	if card == nil || len(card.Security) == 0 {
+		return append(selected, always...), nil
+	}
+
+	// Each requirement is an alternative; every scheme it names must be met
+	var names []string
+	for _, requirement := range card.Security {
+		bound, ok := satisfyRequirement(card, requirement, credentials)
+		if ok {
+			return append(bound, always...), nil
+		}
+		for name := range requirement {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("agent %q requires one of the security schemes %v but no matching credentials were given", card.Name, names)
+}
+
+// satisfyRequirement binds a credential to every scheme named by requirement
+func satisfyRequirement(card *a2a.AgentCard, requirement map[string][]string, credentials []Credentials) ([]boundCredentials, bool) {
+	var bound []boundCredentials
+	for name := range requirement {
+		scheme, ok := decodeScheme(card.SecuritySchemes[name])
+		if !ok {
+			return nil, false
+		}
+		matched := false
+		for _, cred := range credentials {
+			if cred.SchemeType() == scheme.Type {
+				bound = append(bound, boundCredentials{credentials: cred, scheme: scheme})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, false
+		}
+	}
+	return bound, true
+}
+
+// decodeScheme reads a card scheme, which is a decoded JSON object on a
+// fetched card or an SDK scheme struct on one built in code
+func decodeScheme(value any) (*SecurityScheme, bool) {
+	if value == nil {
+		return nil, false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var scheme SecurityScheme
+	if err := json.Unmarshal(data, &scheme); err != nil || scheme.Type == "" {
+		return nil, false
+	}
+	return &scheme, true
+}
+
+// APIKeyCredentials send an API key in a header
+type APIKeyCredentials struct {
+	Key string
+	// Header defaults to the one named by the card's apiKey scheme
+	Header string
+}
+
+// SchemeType implements Credentials
+func (c APIKeyCredentials) SchemeType() string {
+	return SchemeTypeAPIKey
+}
+
+// Authorize implements Credentials
+func (c APIKeyCredentials) Authorize(ctx context.Context, req *http.Request, body []byte, scheme *SecurityScheme) error {
+	header := c.Header
+	if header == "" && scheme != nil {
+		if !strings.EqualFold(scheme.In, "header") {
+			return fmt.Errorf("API keys sent in %q are not supported", scheme.In)
+		}
+		header = scheme.Name
+	}
+	if header == "" {
+		return fmt.Errorf("API key header is not set and no agent card names one")
+	}
+	req.Header.Set(header, c.Key)
+	return nil
+}
+
+// OAuth2ClientCredentials fetches bearer tokens with the OAuth2 client
+// credentials grant. Tokens are cached until shortly before they expire, so
+// warm invocations reuse them.
+type OAuth2ClientCredentials struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	scopes       []string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu       sync.Mutex
+	token    string
+	tokenFor string
+	expiry   time.Time
+}
+
+// NewOAuth2ClientCredentials creates client credentials. An empty tokenURL
+// uses the token URL of the card's client credentials flow.
+func NewOAuth2ClientCredentials(clientID, clientSecret, tokenURL string, scopes ...string) *OAuth2ClientCredentials {
+	return &OAuth2ClientCredentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		scopes:       scopes,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		now:          time.Now,
+	}
+}
+
+// SchemeType implements Credentials
+func (c *OAuth2ClientCredentials) SchemeType() string {
+	return SchemeTypeOAuth2
+}
+
+// Authorize implements Credentials
+func (c *OAuth2ClientCredentials) Authorize(ctx context.Context, req *http.Request, body []byte, scheme *SecurityScheme) error {
+	tokenURL := c.tokenURL
+	if tokenURL == "" && scheme != nil && scheme.Flows.ClientCredentials != nil {
+		tokenURL = scheme.Flows.ClientCredentials.TokenURL
+	}
+	if tokenURL == "" {
+		return fmt.Errorf("OAuth2 token URL is not set and the agent card has no client credentials flow")
+	}
+
+	token, err := c.accessToken(ctx, tokenURL)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// accessToken returns the cached token for tokenURL or fetches a new one
+func (c *OAuth2ClientCredentials) accessToken(ctx context.Context, tokenURL string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.tokenFor == tokenURL && (c.expiry.IsZero() || c.now().Before(c.expiry)) {
+		return c.token, nil
+	}
+	// The client secret is sent in the clear otherwise
+	if !secureURL(tokenURL) {
+		return "", fmt.Errorf("OAuth2 token URL %q must use https", tokenURL)
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("invalid OAuth2 token URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch OAuth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read OAuth2 token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("OAuth2 token request returned HTTP %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("invalid OAuth2 token response")
+	}
+
+	c.token = token.AccessToken
+	c.tokenFor = tokenURL
+	// A token without expires_in is reused until the agent rejects it
+	c.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		c.expiry = c.now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	}
+	return c.token, nil
+}
+
+// secureURL accepts https URLs, and http only for loopback hosts
+func secureURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	if parsed.Scheme == "https" {
+		return true
+	}
+	host := parsed.Hostname()
+	ip := net.ParseIP(host)
+	return parsed.Scheme == "http" && (host == "localhost" || (ip != nil && ip.IsLoopback()))
+}
+
+// SigV4Credentials sign calls with AWS Signature Version 4, for agents behind
+// API Gateway IAM authorization or Lambda function URLs with AWS_IAM auth.
+// Cards cannot declare IAM auth, so these are attached to every call.
+type SigV4Credentials struct {
+	credentials aws.CredentialsProvider
+	region      string
+	service     string
+	signer      *v4.Signer
+	now         func() time.Time
+}
+
+// NewSigV4Credentials signs with the credentials and region of cfg. service
+// is the signing name, DefaultSigV4Service when empty ("lambda" for
+// function URLs).
+func NewSigV4Credentials(cfg aws.Config, service string) *SigV4Credentials {
+	if service == "" {
+		service = DefaultSigV4Service
+	}
+	return &SigV4Credentials{
+		credentials: cfg.Credentials,
+		region:      cfg.Region,
+		service:     service,
+		signer:      v4.NewSigner(),
+		now:         time.Now,
+	}
+}
+
+// SchemeType implements Credentials
+func (c *SigV4Credentials) SchemeType() string {
+	return ""
+}
+
+// Authorize implements Credentials
+func (c *SigV4Credentials) Authorize(ctx context.Context, req *http.Request, body []byte, scheme *SecurityScheme) error {
+	if c.credentials == nil {
+		return fmt.Errorf("no AWS credentials to sign with")
+	}
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), c.service, c.region, c.now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	return nil
+}
+
+// SignatureCredentials sign calls with a shared key, for agents that verify
+// request signatures (security.signature_header). Cards cannot declare
+// HMAC signing, so these are attached to every call.
+type SignatureCredentials struct {
+	Key    string
+	Header string
+	now    func() time.Time
+}
+
+// NewSignatureCredentials signs with key in header, e.g. "X-Signature". The
+// signing time goes in the header's "-Timestamp" companion.
+func NewSignatureCredentials(key, header string) *SignatureCredentials {
+	return &SignatureCredentials{Key: key, Header: header, now: time.Now}
+}
+
+// SchemeType implements Credentials
+func (c *SignatureCredentials) SchemeType() string {
+	return ""
+}
+
+// Authorize implements Credentials
+func (c *SignatureCredentials) Authorize(ctx context.Context, req *http.Request, body []byte, scheme *SecurityScheme) error {
+	if c.Header == "" {
+		return fmt.Errorf("signature header is not set")
+	}
+	timestamp := c.now().Unix()
+	req.Header.Set(c.Header, a2aTypes.SignRequest(c.Key, timestamp, body))
+	req.Header.Set(a2aTypes.SignatureTimestampHeader(c.Header), strconv.FormatInt(timestamp, 10))
+	return nil
+}
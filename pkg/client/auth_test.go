@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// newSecuredAgent serves a card declaring security and records the headers
+// of every JSON-RPC call
+func newSecuredAgent(t *testing.T, security a2aTypes.SecurityConfig) (*httptest.Server, *[]http.Header) {
+	var calls []http.Header
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(agentcard.New("Secured Agent", server.URL, a2aTypes.WithSecurity(security)))
+			return
+		}
+		calls = append(calls, r.Header.Clone())
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"ID":"task-1","Kind":"task"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestAPIKeyCredentialsFromCard(t *testing.T) {
+	server, calls := newSecuredAgent(t, a2aTypes.SecurityConfig{APIKeyHeader: "X-Agent-Key"})
+
+	c, err := Discover(context.Background(), server.URL,
+		WithCardCache(NewCardCache(time.Minute)),
+		WithCredentials(APIKeyCredentials{Key: "key-1"}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.GetTask(context.Background(), a2a.TaskQueryParams{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := (*calls)[0].Get("X-Agent-Key"); got != "key-1" {
+		t.Errorf("expected the key in the card's header, got %q", got)
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	fetches := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		clientID, secret, _ := r.BasicAuth()
+		r.ParseForm()
+		if clientID != "client-1" || secret != "secret-1" || r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("scope") != "agents:call" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, fetches)
+	}))
+	defer tokenServer.Close()
+
+	server, calls := newSecuredAgent(t, a2aTypes.SecurityConfig{
+		OAuth2TokenURL: tokenServer.URL,
+		OAuth2Scopes:   []string{"agents:call"},
+		OAuth2JWKSURL:  "https://auth.example.com/jwks",
+	})
+
+	oauth := NewOAuth2ClientCredentials("client-1", "secret-1", "", "agents:call")
+	now := time.Unix(0, 0)
+	oauth.now = func() time.Time { return now }
+	c, err := Discover(context.Background(), server.URL,
+		WithCardCache(NewCardCache(time.Minute)),
+		// Only the credentials matching the card's scheme are attached
+		WithCredentials(APIKeyCredentials{Key: "unused", Header: "X-Agent-Key"}, oauth),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	call := func() {
+		t.Helper()
+		if _, err := c.GetTask(context.Background(), a2a.TaskQueryParams{ID: "task-1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	call()
+	now = now.Add(30 * time.Minute)
+	call()
+	// Past the expiry margin a new token is fetched
+	now = now.Add(30 * time.Minute)
+	call()
+
+	expected := []string{"Bearer token-1", "Bearer token-1", "Bearer token-2"}
+	for i, want := range expected {
+		if got := (*calls)[i].Get("Authorization"); got != want {
+			t.Errorf("call %d: expected %q, got %q", i, want, got)
+		}
+		if (*calls)[i].Get("X-Agent-Key") != "" {
+			t.Errorf("call %d: expected no API key", i)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("expected 2 token fetches, got %d", fetches)
+	}
+}
+
+func TestSigV4Credentials(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"ID":"task-1","Kind":"task"}}`)
+	}))
+	defer server.Close()
+
+	sigv4 := NewSigV4Credentials(aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	}, "")
+	c := New(server.URL, WithCredentials(sigv4))
+	if _, err := c.CancelTask(context.Background(), a2a.TaskIDParams{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	authorization := received.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(authorization, "/us-east-1/execute-api/aws4_request") {
+		t.Errorf("expected a SigV4 signature for execute-api, got %q", authorization)
+	}
+	if received.Get("X-Amz-Date") == "" {
+		t.Error("expected X-Amz-Date to be set")
+	}
+}
+
+func TestSignatureCredentials(t *testing.T) {
+	authenticator := a2aTypes.NewAuthenticator(a2aTypes.SecurityConfig{SignatureHeader: "X-Signature"}, a2aTypes.SecretsConfig{RequestSigningKey: "signing-key"}, nil)
+	var principal a2aTypes.Principal
+	var authErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers := map[string]string{}
+		for name := range r.Header {
+			headers[name] = r.Header.Get(name)
+		}
+		principal, authErr = authenticator.Authenticate(r.Context(), headers, body)
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"ID":"task-1","Kind":"task"}}`)
+	}))
+	defer server.Close()
+
+	c := New(server.URL, WithCredentials(NewSignatureCredentials("signing-key", "X-Signature")))
+	if _, err := c.GetTask(context.Background(), a2a.TaskQueryParams{ID: "task-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if authErr != nil || principal.Scheme != a2aTypes.SecuritySchemeSignature {
+		t.Errorf("expected the agent to accept the signature, got %+v, %v", principal, authErr)
+	}
+}
+
+func TestDiscoverRequiresMatchingCredentials(t *testing.T) {
+	server, _ := newSecuredAgent(t, a2aTypes.SecurityConfig{
+		OAuth2TokenURL: "https://auth.example.com/token",
+		OAuth2JWKSURL:  "https://auth.example.com/jwks",
+	})
+
+	_, err := Discover(context.Background(), server.URL,
+		WithCardCache(NewCardCache(time.Minute)),
+		WithCredentials(APIKeyCredentials{Key: "key-1", Header: "X-Agent-Key"}),
+	)
+	if err == nil || !strings.Contains(err.Error(), "oauth2") {
+		t.Errorf("expected an unsatisfied oauth2 requirement, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Package client calls remote A2A agents over JSON-RPC, so executors running
+// in the serverless handler can delegate work to other agents. It lives
+// outside internal/ for the same reason as pkg/agentcard.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// A2A methods called by the client
+const (
+	MethodSendMessage = "message/send"
+	MethodGetTask     = "tasks/get"
+	MethodCancelTask  = "tasks/cancel"
+)
+
+// defaultTimeout bounds a call when no HTTP client is given, so a slow agent
+// cannot hold the caller until its Lambda timeout
+const defaultTimeout = 30 * time.Second
+
+// maxResponseBytes bounds how much of a response body is read
+const maxResponseBytes = 10 << 20
+
+// RPCError is a JSON-RPC error returned by the remote agent. Use errors.As to
+// read its code.
+type RPCError = a2aTypes.JSONRPCError
+
+// Client calls one remote agent's JSON-RPC endpoint
+type Client struct {
+	url         string
+	httpClient  *http.Client
+	nextID      atomic.Int64
+	credentials []Credentials
+	// card and cardCache are only set by Discover
+	card      *a2a.AgentCard
+	cardCache *CardCache
+}
+
+// Option configures a Client built by New
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for calls, e.g. one with a
+// tracing or signing transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New creates a client for the agent whose JSON-RPC endpoint is url, the URL
+// on its agent card
+func New(url string, opts ...Option) *Client {
+	c := &Client{url: url, httpClient: &http.Client{Timeout: defaultTimeout}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Card returns the agent card the client was discovered from, or nil for a
+// client created by New
+func (c *Client) Card() *a2a.AgentCard {
+	return c.card
+}
+
+// SendMessage calls message/send. The result is an a2a.Task or, for agents
+// that answer directly, an a2a.Message.
+func (c *Client) SendMessage(ctx context.Context, params a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	var raw json.RawMessage
+	if err := c.call(ctx, MethodSendMessage, params, &raw); err != nil {
+		return nil, err
+	}
+	return decodeSendMessageResult(raw)
+}
+
+// GetTask calls tasks/get
+func (c *Client) GetTask(ctx context.Context, params a2a.TaskQueryParams) (a2a.Task, error) {
+	return c.callTask(ctx, MethodGetTask, params)
+}
+
+// CancelTask calls tasks/cancel
+func (c *Client) CancelTask(ctx context.Context, params a2a.TaskIDParams) (a2a.Task, error) {
+	return c.callTask(ctx, MethodCancelTask, params)
+}
+
+// callTask calls a method whose result is a task
+func (c *Client) callTask(ctx context.Context, method string, params any) (a2a.Task, error) {
+	var raw json.RawMessage
+	if err := c.call(ctx, method, params, &raw); err != nil {
+		return a2a.Task{}, err
+	}
+	task, err := a2aTypes.UnmarshalTask(raw)
+	if err != nil {
+		return a2a.Task{}, fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return task, nil
+}
+
+// post sends one JSON-RPC request with its credentials attached. The caller
+// closes the response body.
+func (c *Client) post(ctx context.Context, method string, params any, accept string) (*http.Response, error) {
+	body, err := a2aTypes.SerializeJSONRPCRequest(a2aTypes.NewJSONRPCRequest(method, params, c.nextID.Add(1)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid agent URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+
+	selected, err := selectCredentials(c.card, c.credentials)
+	if err != nil {
+		return nil, err
+	}
+	for _, bound := range selected {
+		if err := bound.credentials.Authorize(ctx, req, body, bound.scheme); err != nil {
+			return nil, fmt.Errorf("failed to authorize %s: %w", method, err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	return resp, nil
+}
+
+// call sends one JSON-RPC request and decodes its result into result. A
+// JSON-RPC error from the agent is returned as an *RPCError.
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	resp, err := c.post(ctx, method, params, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+	// JSON-RPC errors arrive with 200, so any other status is a transport failure
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned HTTP %d", method, resp.StatusCode)
+	}
+
+	// Not wrapped: a malformed response must not read as an error from the agent
+	rpcResp, err := a2aTypes.ParseJSONRPCResponse(data)
+	if err != nil {
+		return fmt.Errorf("invalid %s response: %v", method, err)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+
+	resultBytes, err := rpcResp.ResultJSON()
+	if err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	if err := json.Unmarshal(resultBytes, result); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return nil
+}
+
+// decodeSendMessageResult picks the result type from its Kind discriminator
+func decodeSendMessageResult(raw json.RawMessage) (a2a.SendMessageResult, error) {
+	var kind struct {
+		Kind string
+	}
+	if err := json.Unmarshal(raw, &kind); err != nil {
+		return nil, fmt.Errorf("invalid %s result: %w", MethodSendMessage, err)
+	}
+
+	switch kind.Kind {
+	case "task":
+		task, err := a2aTypes.UnmarshalTask(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s result: %w", MethodSendMessage, err)
+		}
+		return task, nil
+	case "message":
+		message, err := a2aTypes.UnmarshalMessage(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s result: %w", MethodSendMessage, err)
+		}
+		return message, nil
+	default:
+		return nil, fmt.Errorf("invalid %s result: unknown kind %q", MethodSendMessage, kind.Kind)
+	}
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// newAgentServer serves the serverless handler over HTTP, passing lower-case
+// header names as API Gateway does. Its card points at the server itself and
+// is built with opts.
+func newAgentServer(t *testing.T, opts ...agentcard.Option) *httptest.Server {
+	var h *handler.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers := make(map[string]string)
+		for name := range r.Header {
+			headers[strings.ToLower(name)] = r.Header.Get(name)
+		}
+		resp := h.HandleRequest(r.Context(), handler.Request{Method: r.Method, URL: r.URL.Path, Headers: headers, Body: string(body)})
+		for name, value := range resp.Headers {
+			w.Header().Set(name, value)
+		}
+		w.WriteHeader(resp.Status)
+		io.WriteString(w, resp.Body)
+	}))
+	t.Cleanup(server.Close)
+
+	a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil)
+	h = handler.NewHandler(a2aHandler, agentcard.New("Remote Agent", server.URL, opts...))
+	return server
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	server := newAgentServer(t)
+	c := New(server.URL, WithHTTPClient(server.Client()))
+	ctx := context.Background()
+
+	message := a2a.Message{MessageID: "msg-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "hello"}}}
+	result, err := c.SendMessage(ctx, a2a.MessageSendParams{Message: message})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sent, ok := result.(a2a.Task)
+	if !ok {
+		t.Fatalf("expected a task, got %T", result)
+	}
+	if sent.Status.State != a2a.TaskStateWorking || len(sent.History) != 1 || sent.History[0].MessageID != "msg-1" || len(sent.History[0].Parts) != 1 {
+		t.Errorf("unexpected task %+v", sent)
+	}
+
+	got, err := c.GetTask(ctx, a2a.TaskQueryParams{ID: sent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != sent.ID || got.ContextID != sent.ContextID {
+		t.Errorf("expected task %s, got %+v", sent.ID, got)
+	}
+
+	canceled, err := c.CancelTask(ctx, a2a.TaskIDParams{ID: sent.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if canceled.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected canceled task, got %s", canceled.Status.State)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := newAgentServer(t)
+	c := New(server.URL, WithHTTPClient(server.Client()))
+
+	// The agent's JSON-RPC error comes back typed
+	_, err := c.GetTask(context.Background(), a2a.TaskQueryParams{ID: "missing"})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != a2aTypes.JSONRPCErrorTaskNotFound {
+		t.Errorf("expected task not found, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "HTTP failure", status: http.StatusBadGateway, body: `{"message":"Internal server error"}`},
+		{name: "not JSON-RPC", status: http.StatusOK, body: `<html></html>`},
+		{name: "unknown result kind", status: http.StatusOK, body: `{"jsonrpc":"2.0","id":1,"result":{"Kind":"status-update"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			_, err := New(server.URL).SendMessage(context.Background(), a2a.MessageSendParams{})
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if errors.As(err, &rpcErr) {
+				t.Errorf("expected a transport error, got JSON-RPC error %v", err)
+			}
+		})
+	}
+}
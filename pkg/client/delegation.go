@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// DelegationMetadataKey is the parent task metadata key that records its
+// sub-tasks, so a later invocation can resume the delegation from the stored task
+const DelegationMetadataKey = "delegations"
+
+// SourceTaskMetadataKey is set on merged artifacts to the sub-task they came from
+const SourceTaskMetadataKey = "source_task_id"
+
+// Subtask is a task delegated to another agent
+type Subtask struct {
+	AgentURL string        `json:"agent_url"`
+	TaskID   a2a.TaskID    `json:"task_id"`
+	State    a2a.TaskState `json:"state"`
+}
+
+// Done reports whether the sub-task reached a terminal state
+func (s Subtask) Done() bool {
+	switch s.State {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+// Delegation hands work for a parent task to other agents and merges their
+// artifacts into the parent as the sub-tasks finish. Its state lives in the
+// parent's metadata and history; the caller saves the parent after each call.
+type Delegation struct {
+	parent   *a2a.Task
+	clients  map[string]*Client
+	subtasks []Subtask
+}
+
+// NewDelegation starts, or resumes, the delegation recorded on parent.
+// clients are the agents recorded sub-tasks may be polled on, matched by URL.
+func NewDelegation(parent *a2a.Task, clients ...*Client) (*Delegation, error) {
+	d := &Delegation{parent: parent, clients: make(map[string]*Client)}
+	for _, c := range clients {
+		d.clients[c.url] = c
+	}
+	// A stored task holds the sub-tasks as decoded JSON, so round-trip them
+	if recorded, ok := parent.Metadata[DelegationMetadataKey]; ok {
+		data, err := json.Marshal(recorded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delegations: %w", err)
+		}
+		if err := json.Unmarshal(data, &d.subtasks); err != nil {
+			return nil, fmt.Errorf("invalid delegations on task %s: %w", parent.ID, err)
+		}
+	}
+	return d, nil
+}
+
+// Subtasks returns the delegated sub-tasks in the order they were sent
+func (d *Delegation) Subtasks() []Subtask {
+	return append([]Subtask(nil), d.subtasks...)
+}
+
+// Done reports whether every sub-task reached a terminal state
+func (d *Delegation) Done() bool {
+	for _, s := range d.subtasks {
+		if !s.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Delegate sends message to the agent behind c as a new sub-task of the
+// parent. The message references the parent task, and the parent's history
+// gains an agent message referencing the sub-task. An agent that answers with
+// a message rather than a task is treated as a completed sub-task.
+func (d *Delegation) Delegate(ctx context.Context, c *Client, message a2a.Message) (Subtask, error) {
+	message.ReferenceTasks = append(message.ReferenceTasks, d.parent.ID)
+	result, err := c.SendMessage(ctx, a2a.MessageSendParams{Message: message})
+	if err != nil {
+		return Subtask{}, fmt.Errorf("failed to delegate to %s: %w", c.url, err)
+	}
+
+	var task a2a.Task
+	switch r := result.(type) {
+	case a2a.Task:
+		task = r
+	case a2a.Message:
+		task = a2a.Task{
+			ID:     a2a.TaskID(r.MessageID),
+			Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Artifacts: []a2a.Artifact{{
+				ArtifactID: r.MessageID,
+				Parts:      r.Parts,
+			}},
+		}
+	}
+
+	d.clients[c.url] = c
+	contextID, parentID := d.parent.ContextID, d.parent.ID
+	d.subtasks = append(d.subtasks, Subtask{AgentURL: c.url, TaskID: task.ID, State: a2a.TaskStateSubmitted})
+	d.parent.History = append(d.parent.History, a2a.Message{
+		Kind:           "message",
+		MessageID:      fmt.Sprintf("%s-delegation-%d", d.parent.ID, len(d.subtasks)),
+		Role:           a2a.MessageRoleAgent,
+		ContextID:      &contextID,
+		TaskID:         &parentID,
+		ReferenceTasks: []a2a.TaskID{task.ID},
+		Parts:          []a2a.Part{a2a.TextPart{Kind: "text", Text: "Delegated to " + c.url}},
+	})
+	d.Complete(task)
+	return d.subtasks[len(d.subtasks)-1], nil
+}
+
+// Poll fetches every unfinished sub-task and merges those that finished. It
+// returns whether all sub-tasks are done. Sub-tasks are polled on the clients
+// given to NewDelegation or used to Delegate; one without a client is an error.
+func (d *Delegation) Poll(ctx context.Context) (bool, error) {
+	for _, s := range d.subtasks {
+		if s.Done() {
+			continue
+		}
+		c, ok := d.clients[s.AgentURL]
+		if !ok {
+			return false, fmt.Errorf("no client for agent %s of sub-task %s", s.AgentURL, s.TaskID)
+		}
+		task, err := c.GetTask(ctx, a2a.TaskQueryParams{ID: s.TaskID})
+		if err != nil {
+			return false, fmt.Errorf("failed to poll sub-task %s: %w", s.TaskID, err)
+		}
+		d.Complete(task)
+	}
+	return d.Done(), nil
+}
+
+// Complete records the latest state of a sub-task, e.g. one delivered by a
+// push notification, merging its artifacts into the parent once it finishes.
+// It returns false when task is not a sub-task of this delegation.
+func (d *Delegation) Complete(task a2a.Task) bool {
+	for i, s := range d.subtasks {
+		if s.TaskID != task.ID {
+			continue
+		}
+		// Merge once, on the transition to a terminal state
+		wasDone := s.Done()
+		d.subtasks[i].State = task.Status.State
+		if !wasDone && d.subtasks[i].Done() {
+			d.merge(task)
+		}
+		d.save()
+		return true
+	}
+	return false
+}
+
+// merge appends the sub-task's artifacts to the parent, tagged with their source
+func (d *Delegation) merge(task a2a.Task) {
+	for _, artifact := range task.Artifacts {
+		metadata := make(map[string]any, len(artifact.Metadata)+1)
+		for k, v := range artifact.Metadata {
+			metadata[k] = v
+		}
+		metadata[SourceTaskMetadataKey] = string(task.ID)
+		artifact.Metadata = metadata
+		d.parent.Artifacts = append(d.parent.Artifacts, artifact)
+	}
+}
+
+// save records the sub-tasks on the parent's metadata
+func (d *Delegation) save() {
+	if d.parent.Metadata == nil {
+		d.parent.Metadata = make(map[string]any)
+	}
+	d.parent.Metadata[DelegationMetadataKey] = d.Subtasks()
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// newDownstreamAgent answers message/send with a working task and tasks/get
+// with whatever tasks holds, recording the messages it was sent
+func newDownstreamAgent(t *testing.T, tasks map[a2a.TaskID]a2a.Task) (*httptest.Server, *[]map[string]any) {
+	var sent []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params map[string]any
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var result any
+		switch req.Method {
+		case MethodSendMessage:
+			sent = append(sent, req.Params["Message"].(map[string]any))
+			id := a2a.TaskID(fmt.Sprintf("sub-%d", len(sent)))
+			result = a2a.Task{ID: id, Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}
+		case MethodGetTask:
+			result = tasks[a2a.TaskID(req.Params["ID"].(string))]
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server, &sent
+}
+
+func completedTask(id a2a.TaskID, text string) a2a.Task {
+	return a2a.Task{
+		ID:     id,
+		Kind:   "task",
+		Status: a2a.TaskStatus{State: a2a.TaskStateCompleted},
+		Artifacts: []a2a.Artifact{{
+			ArtifactID: "result",
+			Parts:      []a2a.Part{a2a.TextPart{Kind: "text", Text: text}},
+		}},
+	}
+}
+
+func TestDelegationPoll(t *testing.T) {
+	tasks := map[a2a.TaskID]a2a.Task{}
+	server, sent := newDownstreamAgent(t, tasks)
+	c := New(server.URL)
+	ctx := context.Background()
+
+	parent := &a2a.Task{ID: "parent-1", ContextID: "ctx-1"}
+	d, err := NewDelegation(parent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, text := range []string{"research", "summarize"} {
+		message := a2a.Message{MessageID: text, Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: text}}}
+		if _, err := d.Delegate(ctx, c, message); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Sent messages reference the parent; the parent's history references the sub-tasks
+	if refs := (*sent)[0]["ReferenceTasks"].([]any); len(refs) != 1 || refs[0] != "parent-1" {
+		t.Errorf("expected the sent message to reference the parent, got %v", refs)
+	}
+	if len(parent.History) != 2 || parent.History[1].ReferenceTasks[0] != "sub-2" {
+		t.Errorf("expected history referencing the sub-tasks, got %+v", parent.History)
+	}
+
+	tasks["sub-1"] = completedTask("sub-1", "found it")
+	tasks["sub-2"] = a2a.Task{ID: "sub-2", Kind: "task", Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}
+	if done, err := d.Poll(ctx); err != nil || done {
+		t.Fatalf("expected one sub-task outstanding, got done=%v err=%v", done, err)
+	}
+
+	// A later invocation resumes from the stored parent
+	stored, _ := json.Marshal(parent.Metadata)
+	resumedParent := *parent
+	json.Unmarshal(stored, &resumedParent.Metadata)
+	resumed, err := NewDelegation(&resumedParent, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tasks["sub-2"] = completedTask("sub-2", "in short")
+	if done, err := resumed.Poll(ctx); err != nil || !done {
+		t.Fatalf("expected all sub-tasks done, got done=%v err=%v", done, err)
+	}
+
+	if len(resumedParent.Artifacts) != 2 {
+		t.Fatalf("expected 2 merged artifacts, got %d", len(resumedParent.Artifacts))
+	}
+	for i, want := range []string{"sub-1", "sub-2"} {
+		if got := resumedParent.Artifacts[i].Metadata[SourceTaskMetadataKey]; got != want {
+			t.Errorf("artifact %d: expected source %s, got %v", i, want, got)
+		}
+	}
+	if text := resumedParent.Artifacts[0].Parts[0].(a2a.TextPart).Text; text != "found it" {
+		t.Errorf("expected the sub-task's parts, got %q", text)
+	}
+}
+
+func TestDelegationComplete(t *testing.T) {
+	server, _ := newDownstreamAgent(t, nil)
+	parent := &a2a.Task{ID: "parent-1"}
+	d, _ := NewDelegation(parent)
+	if _, err := d.Delegate(context.Background(), New(server.URL), a2a.Message{MessageID: "msg-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d.Complete(completedTask("unrelated", "x")) {
+		t.Error("expected an unrelated task to be ignored")
+	}
+	// A repeated notification merges the artifacts only once
+	for range 2 {
+		if !d.Complete(completedTask("sub-1", "done")) {
+			t.Fatal("expected the sub-task to be recognized")
+		}
+	}
+	if !d.Done() || len(parent.Artifacts) != 1 {
+		t.Errorf("expected one merged artifact, got done=%v artifacts=%d", d.Done(), len(parent.Artifacts))
+	}
+}
+
+func TestDelegationPollWithoutClient(t *testing.T) {
+	parent := &a2a.Task{ID: "parent-1", Metadata: map[string]any{
+		DelegationMetadataKey: []any{map[string]any{"agent_url": "https://agent.example.com", "task_id": "sub-1", "state": "working"}},
+	}}
+	d, err := NewDelegation(parent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := d.Poll(context.Background()); err == nil {
+		t.Error("expected error but got none")
+	}
+}
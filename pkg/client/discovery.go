@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// AgentCardPath is where an agent publishes its card, relative to its base URL
+const AgentCardPath = "/.well-known/agent.json"
+
+// DefaultCardCacheTTL is how long a discovered card is reused before it is fetched again
+const DefaultCardCacheTTL = 5 * time.Minute
+
+// maxCardBytes bounds how much of an agent card response is read
+const maxCardBytes = 1 << 20
+
+// defaultCardCache is shared by every Discover call in the process, so warm
+// invocations reuse cards fetched by earlier ones
+var defaultCardCache = NewCardCache(DefaultCardCacheTTL)
+
+// CardCache keeps discovered agent cards by base URL for a TTL
+type CardCache struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cachedCard
+}
+
+type cachedCard struct {
+	card      a2a.AgentCard
+	fetchedAt time.Time
+}
+
+// NewCardCache creates a card cache that fetches cards again after ttl
+func NewCardCache(ttl time.Duration) *CardCache {
+	return &CardCache{ttl: ttl, now: time.Now, entries: make(map[string]cachedCard)}
+}
+
+// get returns the card for baseURL, fetching it when missing or expired
+func (c *CardCache) get(ctx context.Context, httpClient *http.Client, baseURL string) (a2a.AgentCard, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entry, cached := c.entries[baseURL]
+	if cached && now.Sub(entry.fetchedAt) < c.ttl {
+		return entry.card, nil
+	}
+
+	card, err := fetchAgentCard(ctx, httpClient, baseURL)
+	if err != nil {
+		if !cached {
+			return a2a.AgentCard{}, err
+		}
+		// A card that was valid a moment ago beats failing the delegation, so
+		// keep it and retry after another TTL
+		a2aTypes.LoggerFromContext(ctx).Warn("agent card refresh failed, using cached card", "agent_url", baseURL, a2aTypes.LogKeyError, err)
+		c.entries[baseURL] = cachedCard{card: entry.card, fetchedAt: now}
+		return entry.card, nil
+	}
+
+	c.entries[baseURL] = cachedCard{card: card, fetchedAt: now}
+	return card, nil
+}
+
+// WithCardCache sets the cache Discover reads cards through, in place of the
+// process-wide one
+func WithCardCache(cache *CardCache) Option {
+	return func(c *Client) {
+		c.cardCache = cache
+	}
+}
+
+// Discover fetches the agent card published under baseURL, validates it and
+// returns a client for the JSON-RPC endpoint the card advertises, whether
+// that is the card URL or one of its additional interfaces. It fails when
+// the card requires security schemes no given credentials satisfy.
+func Discover(ctx context.Context, baseURL string, opts ...Option) (*Client, error) {
+	c := New("", opts...)
+	cache := c.cardCache
+	if cache == nil {
+		cache = defaultCardCache
+	}
+
+	card, err := cache.get(ctx, c.httpClient, strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := jsonRPCEndpoint(card)
+	if err != nil {
+		return nil, err
+	}
+	// Fail here rather than on every call when no credentials meet the card
+	if _, err := selectCredentials(&card, c.credentials); err != nil {
+		return nil, err
+	}
+
+	c.url = endpoint
+	c.card = &card
+	return c, nil
+}
+
+// fetchAgentCard fetches and validates the card published under baseURL
+func fetchAgentCard(ctx context.Context, httpClient *http.Client, baseURL string) (a2a.AgentCard, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+AgentCardPath, nil)
+	if err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("invalid agent URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("failed to fetch agent card: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return a2a.AgentCard{}, fmt.Errorf("agent card request returned HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCardBytes))
+	if err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("failed to read agent card: %w", err)
+	}
+	var card a2a.AgentCard
+	if err := json.Unmarshal(data, &card); err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("invalid agent card JSON: %w", err)
+	}
+	if err := validateAgentCard(card); err != nil {
+		return a2a.AgentCard{}, fmt.Errorf("invalid agent card: %w", err)
+	}
+	return card, nil
+}
+
+// validateAgentCard checks the fields a client needs to call the agent
+func validateAgentCard(card a2a.AgentCard) error {
+	var errs a2aTypes.ValidationErrors
+	if card.Name == "" {
+		errs.Add("agent_card.Name", a2aTypes.ValidationCodeRequired, "is required")
+	}
+	if card.URL == "" {
+		errs.Add("agent_card.URL", a2aTypes.ValidationCodeRequired, "is required")
+	} else {
+		errs.Merge("", a2aTypes.ValidateAgentURL(card.URL))
+	}
+	for i, iface := range card.AdditionalInterfaces {
+		if err := a2aTypes.ValidateAgentURL(iface.URL); err != nil {
+			errs.Add(fmt.Sprintf("agent_card.AdditionalInterfaces[%d].URL", i), a2aTypes.ValidationCodeInvalid, "must be an absolute https URL")
+		}
+	}
+	return errs.Err()
+}
+
+// jsonRPCEndpoint returns the URL the card advertises for JSON-RPC, the only
+// transport this client speaks. The preferred transport wins when it is
+// JSON-RPC; otherwise an additional interface is used.
+func jsonRPCEndpoint(card a2a.AgentCard) (string, error) {
+	// An unset preferred transport means JSONRPC per the A2A spec
+	preferred := card.PreferredTransport
+	if preferred == "" || preferred == a2a.TransportProtocolJSONRPC {
+		return card.URL, nil
+	}
+	for _, iface := range card.AdditionalInterfaces {
+		if a2a.TransportProtocol(iface.Transport) == a2a.TransportProtocolJSONRPC {
+			return iface.URL, nil
+		}
+	}
+	return "", fmt.Errorf("agent %q offers no %s interface", card.Name, a2a.TransportProtocolJSONRPC)
+}
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestDiscoverRoundTrip(t *testing.T) {
+	server := newAgentServer(t)
+
+	// A trailing slash on the base URL is ignored
+	c, err := Discover(context.Background(), server.URL+"/", WithHTTPClient(server.Client()), WithCardCache(NewCardCache(time.Minute)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Card() == nil || c.Card().Name != "Remote Agent" {
+		t.Fatalf("expected the remote card, got %+v", c.Card())
+	}
+	if _, err := c.SendMessage(context.Background(), a2a.MessageSendParams{Message: a2a.Message{MessageID: "msg-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "hi"}}}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDiscoverSelectsEndpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		card           func(baseURL string) a2a.AgentCard
+		expectEndpoint string
+		expectError    bool
+	}{
+		{
+			name: "preferred JSON-RPC",
+			card: func(baseURL string) a2a.AgentCard {
+				return agentcard.New("Agent", baseURL+"/rpc")
+			},
+			expectEndpoint: "/rpc",
+		},
+		{
+			name: "unset transport means JSON-RPC",
+			card: func(baseURL string) a2a.AgentCard {
+				card := agentcard.New("Agent", baseURL+"/rpc")
+				card.PreferredTransport = ""
+				return card
+			},
+			expectEndpoint: "/rpc",
+		},
+		{
+			name: "JSON-RPC as an additional interface",
+			card: func(baseURL string) a2a.AgentCard {
+				card := agentcard.New("Agent", baseURL+"/grpc")
+				card.PreferredTransport = a2a.TransportProtocolGRPC
+				card.AdditionalInterfaces = []a2a.AgentInterface{
+					{Transport: string(a2a.TransportProtocolHTTPJSON), URL: baseURL + "/v1"},
+					{Transport: string(a2a.TransportProtocolJSONRPC), URL: baseURL + "/jsonrpc"},
+				}
+				return card
+			},
+			expectEndpoint: "/jsonrpc",
+		},
+		{
+			name: "no JSON-RPC interface",
+			card: func(baseURL string) a2a.AgentCard {
+				card := agentcard.New("Agent", baseURL+"/grpc")
+				card.PreferredTransport = a2a.TransportProtocolGRPC
+				return card
+			},
+			expectError: true,
+		},
+		{
+			name: "missing name",
+			card: func(baseURL string) a2a.AgentCard {
+				return agentcard.New("", baseURL)
+			},
+			expectError: true,
+		},
+		{
+			name: "plain http to a remote host",
+			card: func(baseURL string) a2a.AgentCard {
+				return agentcard.New("Agent", "http://agent.example.com")
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != AgentCardPath {
+					http.NotFound(w, r)
+					return
+				}
+				json.NewEncoder(w).Encode(tt.card(server.URL))
+			}))
+			defer server.Close()
+
+			c, err := Discover(context.Background(), server.URL, WithCardCache(NewCardCache(time.Minute)))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.url != server.URL+tt.expectEndpoint {
+				t.Errorf("expected endpoint %s, got %s", server.URL+tt.expectEndpoint, c.url)
+			}
+		})
+	}
+}
+
+func TestCardCache(t *testing.T) {
+	fetches := 0
+	available := true
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(agentcard.New("Agent", server.URL))
+	}))
+	defer server.Close()
+
+	now := time.Unix(0, 0)
+	cache := NewCardCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	discover := func() error {
+		_, err := Discover(context.Background(), server.URL, WithCardCache(cache))
+		return err
+	}
+
+	if err := discover(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Warm invocations within the TTL reuse the card
+	now = now.Add(30 * time.Second)
+	if err := discover(); err != nil || fetches != 1 {
+		t.Fatalf("expected a cached card, got %d fetches, err %v", fetches, err)
+	}
+
+	// An outage after expiry keeps the last good card
+	available = false
+	now = now.Add(time.Minute)
+	if err := discover(); err != nil || fetches != 2 {
+		t.Errorf("expected the stale card after a refetch, got %d fetches, err %v", fetches, err)
+	}
+
+	// Without a cached card the outage is an error
+	if _, err := Discover(context.Background(), server.URL, WithCardCache(NewCardCache(time.Minute))); err == nil {
+		t.Error("expected error but got none")
+	}
+}
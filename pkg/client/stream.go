@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// Streaming methods
+const (
+	// MethodSendStreamingMessage is the streaming counterpart of message/send
+	MethodSendStreamingMessage = "message/stream"
+	// MethodResubscribeTask streams the events of a task already created
+	MethodResubscribeTask = "tasks/resubscribe"
+)
+
+// SendStreamingMessage calls message/stream and yields each event the agent
+// sends: the task, its status and artifact updates, or a message. The stream
+// ends after the first error, which is an *RPCError when the agent sent
+// one. Stopping the iteration early closes the connection.
+//
+// The HTTP client's timeout covers the whole stream, so pass one without a
+// timeout, or a longer one, for streams that outlast the default.
+func (c *Client) SendStreamingMessage(ctx context.Context, params a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	return c.stream(ctx, MethodSendStreamingMessage, params)
+}
+
+// ResubscribeTask calls tasks/resubscribe and yields the task's events, from
+// the start of its event log, as SendStreamingMessage does
+func (c *Client) ResubscribeTask(ctx context.Context, params a2a.TaskIDParams) iter.Seq2[a2a.Event, error] {
+	return c.stream(ctx, MethodResubscribeTask, params)
+}
+
+// stream calls a streaming method and yields the events it sends
+func (c *Client) stream(ctx context.Context, method string, params any) iter.Seq2[a2a.Event, error] {
+	return func(yield func(a2a.Event, error) bool) {
+		resp, err := c.post(ctx, method, params, "text/event-stream")
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			yield(nil, fmt.Errorf("%s returned HTTP %d", method, resp.StatusCode))
+			return
+		}
+
+		// An agent refusing the call, say for lack of streaming, answers with
+		// a plain JSON-RPC error rather than a stream
+		if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+			data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to read %s response: %w", method, err))
+				return
+			}
+			event, err := decodeStreamedResponse(method, data)
+			yield(event, err)
+			return
+		}
+
+		for data, err := range sseData(resp.Body) {
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to read %s stream: %w", method, err))
+				return
+			}
+			event, err := decodeStreamedResponse(method, data)
+			if !yield(event, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// decodeStreamedResponse decodes one JSON-RPC response of method carrying an event
+func decodeStreamedResponse(method string, data []byte) (a2a.Event, error) {
+	rpcResp, err := a2aTypes.ParseJSONRPCResponse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s response: %v", method, err)
+	}
+	if rpcResp.Error != nil {
+		return nil, rpcResp.Error
+	}
+	resultBytes, err := rpcResp.ResultJSON()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	event, err := a2aTypes.UnmarshalEvent(resultBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return event, nil
+}
+
+// sseData yields the data of each Server-Sent Event in r, its data lines
+// joined by newlines. Comments, such as keep-alives, and the other fields
+// are skipped.
+func sseData(r io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64<<10), maxResponseBytes)
+		var data []byte
+		var hasData bool
+		for scanner.Scan() {
+			line := scanner.Bytes()
+			if len(line) == 0 {
+				if hasData && !yield(data, nil) {
+					return
+				}
+				data, hasData = nil, false
+				continue
+			}
+			field, value, _ := bytes.Cut(line, []byte(":"))
+			if string(field) != "data" {
+				continue
+			}
+			value = bytes.TrimPrefix(value, []byte(" "))
+			if hasData {
+				data = append(data, '\n')
+			}
+			data = append(data, value...)
+			hasData = true
+		}
+		if err := scanner.Err(); err != nil {
+			yield(nil, err)
+			return
+		}
+		// A stream may end without the blank line after its last event
+		if hasData {
+			yield(data, nil)
+		}
+	}
+}
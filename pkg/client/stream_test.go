@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func TestSendStreamingMessage(t *testing.T) {
+	server := newAgentServer(t, agentcard.WithStreaming(true))
+	c := New(server.URL, WithHTTPClient(server.Client()))
+
+	message := a2a.Message{MessageID: "msg-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "hello"}}}
+	var events []a2a.Event
+	for event, err := range c.SendStreamingMessage(context.Background(), a2a.MessageSendParams{Message: message}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		t.Fatal("expected events")
+	}
+	status, ok := events[0].(a2a.TaskStatusUpdateEvent)
+	if !ok || status.TaskID == "" || status.Status.State != a2a.TaskStateWorking {
+		t.Errorf("expected the task to start working first, got %+v", events[0])
+	}
+}
+
+func TestSendStreamingMessageRefused(t *testing.T) {
+	server := newAgentServer(t)
+	c := New(server.URL, WithHTTPClient(server.Client()))
+
+	var calls int
+	for event, err := range c.SendStreamingMessage(context.Background(), a2a.MessageSendParams{Message: a2a.Message{MessageID: "msg-1", Role: a2a.MessageRoleUser}}) {
+		calls++
+		var rpcErr *RPCError
+		if event != nil || !errors.As(err, &rpcErr) {
+			t.Errorf("expected an RPC error from an agent without streaming, got %v %v", event, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected one error, got %d results", calls)
+	}
+}
+
+func TestResubscribeTask(t *testing.T) {
+	server := newAgentServer(t, agentcard.WithStreaming(true))
+	c := New(server.URL, WithHTTPClient(server.Client()))
+	ctx := context.Background()
+
+	message := a2a.Message{MessageID: "msg-1", Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Kind: "text", Text: "hello"}}}
+	result, err := c.SendMessage(ctx, a2a.MessageSendParams{Message: message})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := result.(a2a.Task)
+	// Canceling stores the task's final status update
+	if _, err := c.CancelTask(ctx, a2a.TaskIDParams{ID: task.ID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var events []a2a.Event
+	for event, err := range c.ResubscribeTask(ctx, a2a.TaskIDParams{ID: task.ID}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected the stored event, got %v", events)
+	}
+	if status, ok := events[0].(a2a.TaskStatusUpdateEvent); !ok || status.Status.State != a2a.TaskStateCanceled || !status.Final {
+		t.Errorf("expected the final canceled status, got %+v", events[0])
+	}
+}
+
+func TestSSEData(t *testing.T) {
+	stream := "retry: 3000\n\n: keep-alive\n\nid: 1\ndata: {\"a\":1}\n\nid: 2\ndata: first\ndata:second\n\ndata: unterminated"
+	var got []string
+	for data, err := range sseData(strings.NewReader(stream)) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, string(data))
+	}
+	expected := []string{`{"a":1}`, "first\nsecond", "unterminated"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
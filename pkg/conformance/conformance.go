@@ -0,0 +1,339 @@
+// Package conformance checks an A2A handler against the protocol scenarios of
+// the A2A specification: discovery, message/send, tasks/get history limits,
+// cancel semantics, JSON-RPC error codes and push notification config CRUD.
+// Forks run it from their own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func(t *testing.T) conformance.Handler {
+//			return newMyHandler(t)
+//		})
+//	}
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+)
+
+// AgentCardPath is where the agent card must be served
+const AgentCardPath = "/.well-known/agent.json"
+
+// Handler is the request handler under test, such as a handler.Handler or
+// handler.Router
+type Handler interface {
+	HandleRequest(ctx context.Context, req handler.Request) handler.Response
+}
+
+// Run runs every scenario as a subtest. newHandler is called once per
+// scenario, so scenarios never see each other's tasks.
+func Run(t *testing.T, newHandler func(t *testing.T) Handler) {
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, h Handler)
+	}{
+		{"discovery", testDiscovery},
+		{"message/send", testSendMessage},
+		{"tasks/get history", testHistoryLength},
+		{"tasks/cancel", testCancel},
+		{"error codes", testErrorCodes},
+		{"push notification config", testPushConfig},
+	}
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			scenario.run(t, newHandler(t))
+		})
+	}
+}
+
+// response is a decoded JSON-RPC response, with the result kept raw so it can
+// be decoded into the type the method returns
+type response struct {
+	ID     any
+	Result json.RawMessage
+	Error  *a2aTypes.JSONRPCError
+}
+
+// call sends a JSON-RPC request with the given raw body
+func call(t *testing.T, h Handler, body string) response {
+	t.Helper()
+	resp := h.HandleRequest(context.Background(), handler.Request{
+		Method:  http.MethodPost,
+		URL:     "/",
+		Headers: map[string]string{"content-type": "application/json"},
+		Body:    body,
+	})
+	// The spec carries JSON-RPC errors in a 200 response
+	if resp.Status != http.StatusOK {
+		t.Fatalf("expected HTTP 200, got %d: %s", resp.Status, resp.Body)
+	}
+	var decoded response
+	if err := json.Unmarshal([]byte(resp.Body), &decoded); err != nil {
+		t.Fatalf("expected a JSON-RPC response, got %q: %v", resp.Body, err)
+	}
+	// A null result is a result: tasks/pushNotificationConfig/delete answers
+	// with one
+	if (decoded.Error == nil) == (len(decoded.Result) == 0) {
+		t.Fatalf("expected exactly one of result and error, got %s", resp.Body)
+	}
+	return decoded
+}
+
+// callMethod sends method with params and fails unless it succeeds
+func callMethod(t *testing.T, h Handler, method string, params any) json.RawMessage {
+	t.Helper()
+	resp := call(t, h, a2atest.JSONRPCRequest(method, params).Body)
+	if resp.Error != nil {
+		t.Fatalf("%s: unexpected error %v", method, resp.Error)
+	}
+	return resp.Result
+}
+
+// expectError sends method with params and fails unless the error has code
+func expectError(t *testing.T, h Handler, method string, params any, code int) {
+	t.Helper()
+	resp := call(t, h, a2atest.JSONRPCRequest(method, params).Body)
+	if resp.Error == nil || resp.Error.Code != code {
+		t.Errorf("%s: expected error %d, got %v", method, code, resp.Error)
+	}
+}
+
+func decodeTask(t *testing.T, result json.RawMessage) a2a.Task {
+	t.Helper()
+	task, err := a2aTypes.UnmarshalTask(result)
+	if err != nil {
+		t.Fatalf("expected a task, got %s: %v", result, err)
+	}
+	if task.ID == "" || task.ContextID == "" {
+		t.Fatalf("expected a task with an ID and context ID, got %s", result)
+	}
+	return task
+}
+
+// sendMessage sends message and returns the task it created or continued
+func sendMessage(t *testing.T, h Handler, message a2a.Message) a2a.Task {
+	t.Helper()
+	result := callMethod(t, h, "message/send", a2a.MessageSendParams{Message: message})
+	var kind struct {
+		Kind string
+	}
+	json.Unmarshal(result, &kind)
+	if kind.Kind != "task" {
+		t.Skipf("message/send answered with kind %q; the scenario needs a task", kind.Kind)
+	}
+	return decodeTask(t, result)
+}
+
+func testDiscovery(t *testing.T, h Handler) {
+	card := fetchCard(t, h)
+	for field, value := range map[string]string{"name": card.Name, "url": card.URL, "version": card.Version} {
+		if value == "" {
+			t.Errorf("expected the card to have a %s", field)
+		}
+	}
+}
+
+// fetchCard fetches the agent card from its well-known path
+func fetchCard(t *testing.T, h Handler) a2a.AgentCard {
+	t.Helper()
+	resp := h.HandleRequest(context.Background(), handler.Request{Method: http.MethodGet, URL: AgentCardPath, Headers: map[string]string{}})
+	if resp.Status != http.StatusOK {
+		t.Fatalf("expected HTTP 200 for %s, got %d", AgentCardPath, resp.Status)
+	}
+	if contentType := headerValue(resp.Headers, "Content-Type"); !strings.Contains(contentType, "application/json") {
+		t.Errorf("expected a JSON content type, got %q", contentType)
+	}
+
+	var card a2a.AgentCard
+	if err := json.Unmarshal([]byte(resp.Body), &card); err != nil {
+		t.Fatalf("expected an agent card, got %q: %v", resp.Body, err)
+	}
+	return card
+}
+
+func testSendMessage(t *testing.T, h Handler) {
+	task := sendMessage(t, h, a2atest.UserMessage("msg-1", "hello"))
+	if !validState(task.Status.State) {
+		t.Errorf("expected a valid task state, got %q", task.Status.State)
+	}
+	if !hasMessage(task.History, "msg-1") {
+		t.Errorf("expected msg-1 in the task history, got %+v", task.History)
+	}
+
+	// A message naming the task continues it in the same context
+	if terminal(task.Status.State) {
+		return
+	}
+	next := a2atest.UserMessage("msg-2", "more")
+	next.TaskID = &task.ID
+	continued := sendMessage(t, h, next)
+	if continued.ID != task.ID || continued.ContextID != task.ContextID {
+		t.Errorf("expected task %s in context %s, got %s in %s", task.ID, task.ContextID, continued.ID, continued.ContextID)
+	}
+	if !hasMessage(continued.History, "msg-2") {
+		t.Errorf("expected msg-2 in the task history, got %+v", continued.History)
+	}
+}
+
+func testHistoryLength(t *testing.T, h Handler) {
+	task := sendMessage(t, h, a2atest.UserMessage("msg-1", "one"))
+	for i := 2; i <= 3 && !terminal(task.Status.State); i++ {
+		message := a2atest.UserMessage(fmt.Sprintf("msg-%d", i), "again")
+		message.TaskID = &task.ID
+		task = sendMessage(t, h, message)
+	}
+
+	full := decodeTask(t, callMethod(t, h, "tasks/get", a2a.TaskQueryParams{ID: task.ID}))
+	if len(full.History) < 2 {
+		t.Skipf("the task kept %d history messages; the scenario needs 2", len(full.History))
+	}
+
+	// historyLength keeps the most recent messages
+	historyLength := 1
+	limited := decodeTask(t, callMethod(t, h, "tasks/get", a2a.TaskQueryParams{ID: task.ID, HistoryLength: &historyLength}))
+	if len(limited.History) != 1 {
+		t.Fatalf("expected 1 history message, got %d", len(limited.History))
+	}
+	if last := full.History[len(full.History)-1]; limited.History[0].MessageID != last.MessageID {
+		t.Errorf("expected the latest message %s, got %s", last.MessageID, limited.History[0].MessageID)
+	}
+
+	// historyLength 0 asks for no history at all
+	historyLength = 0
+	empty := decodeTask(t, callMethod(t, h, "tasks/get", a2a.TaskQueryParams{ID: task.ID, HistoryLength: &historyLength}))
+	if len(empty.History) != 0 {
+		t.Errorf("expected no history for historyLength 0, got %d messages", len(empty.History))
+	}
+}
+
+func testCancel(t *testing.T, h Handler) {
+	task := sendMessage(t, h, a2atest.UserMessage("msg-1", "hello"))
+	if terminal(task.Status.State) {
+		t.Skipf("the task finished as %s before it could be canceled", task.Status.State)
+	}
+
+	canceled := decodeTask(t, callMethod(t, h, "tasks/cancel", a2a.TaskIDParams{ID: task.ID}))
+	if canceled.ID != task.ID || canceled.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected task %s canceled, got %s %s", task.ID, canceled.ID, canceled.Status.State)
+	}
+	stored := decodeTask(t, callMethod(t, h, "tasks/get", a2a.TaskQueryParams{ID: task.ID}))
+	if stored.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("expected the stored task canceled, got %s", stored.Status.State)
+	}
+
+	// A task in a terminal state cannot be canceled again
+	expectError(t, h, "tasks/cancel", a2a.TaskIDParams{ID: task.ID}, a2aTypes.JSONRPCErrorTaskNotCancelable)
+	expectError(t, h, "tasks/cancel", a2a.TaskIDParams{ID: "conformance-missing-task"}, a2aTypes.JSONRPCErrorTaskNotFound)
+}
+
+func testErrorCodes(t *testing.T, h Handler) {
+	tests := []struct {
+		name     string
+		body     string
+		code     int
+		expectID any
+	}{
+		{name: "invalid JSON", body: `{"jsonrpc":"2.0","id":1,`, code: a2aTypes.JSONRPCErrorParseError},
+		{name: "wrong version", body: `{"jsonrpc":"1.0","id":"req-1","method":"tasks/get","params":{}}`, code: a2aTypes.JSONRPCErrorInvalidRequest, expectID: "req-1"},
+		{name: "missing method", body: `{"jsonrpc":"2.0","id":"req-2"}`, code: a2aTypes.JSONRPCErrorInvalidRequest, expectID: "req-2"},
+		{name: "method of the wrong type", body: `{"jsonrpc":"2.0","id":"req-6","method":6}`, code: a2aTypes.JSONRPCErrorInvalidRequest, expectID: "req-6"},
+		{name: "not a request object", body: `["tasks/get"]`, code: a2aTypes.JSONRPCErrorInvalidRequest},
+		{name: "unknown method", body: `{"jsonrpc":"2.0","id":"req-3","method":"tasks/unknown","params":{}}`, code: a2aTypes.JSONRPCErrorMethodNotFound, expectID: "req-3"},
+		{name: "params of the wrong type", body: `{"jsonrpc":"2.0","id":4,"method":"tasks/get","params":"task-1"}`, code: a2aTypes.JSONRPCErrorInvalidParams, expectID: float64(4)},
+		{name: "unknown task", body: `{"jsonrpc":"2.0","id":5,"method":"tasks/get","params":{"id":"conformance-missing-task"}}`, code: a2aTypes.JSONRPCErrorTaskNotFound, expectID: float64(5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := call(t, h, tt.body)
+			if resp.Error == nil || resp.Error.Code != tt.code {
+				t.Fatalf("expected error %d, got %v", tt.code, resp.Error)
+			}
+			// Errors echo the request ID, or null when it could not be read
+			if resp.ID != tt.expectID {
+				t.Errorf("expected ID %v, got %v", tt.expectID, resp.ID)
+			}
+		})
+	}
+}
+
+func testPushConfig(t *testing.T, h Handler) {
+	task := sendMessage(t, h, a2atest.UserMessage("msg-1", "hello"))
+	configID := "conformance-config"
+	config := a2a.TaskPushConfig{TaskID: task.ID, Config: a2a.PushConfig{ID: &configID, URL: "https://hooks.example.com/a2a"}}
+
+	resp := call(t, h, a2atest.JSONRPCRequest("tasks/pushNotificationConfig/set", config).Body)
+	if resp.Error != nil {
+		// Only an agent whose card claims push notifications must serve them
+		advertised := fetchCard(t, h).Capabilities.PushNotifications
+		switch resp.Error.Code {
+		case a2aTypes.JSONRPCErrorMethodNotFound, a2aTypes.JSONRPCErrorPushNotificationNotSupported:
+			if advertised == nil || !*advertised {
+				t.Skipf("the handler does not support push notifications: %v", resp.Error)
+			}
+		}
+		t.Fatalf("tasks/pushNotificationConfig/set: unexpected error %v", resp.Error)
+	}
+
+	var got a2a.TaskPushConfig
+	json.Unmarshal(callMethod(t, h, "tasks/pushNotificationConfig/get", a2a.GetTaskPushConfigParams{TaskID: task.ID, ConfigID: &configID}), &got)
+	if got.Config.URL != config.Config.URL {
+		t.Errorf("expected the stored URL %s, got %+v", config.Config.URL, got)
+	}
+
+	var listed []a2a.TaskPushConfig
+	json.Unmarshal(callMethod(t, h, "tasks/pushNotificationConfig/list", a2a.ListTaskPushConfigParams{TaskID: task.ID}), &listed)
+	if len(listed) != 1 {
+		t.Errorf("expected 1 config, got %d", len(listed))
+	}
+
+	call(t, h, a2atest.JSONRPCRequest("tasks/pushNotificationConfig/delete", a2a.DeleteTaskPushConfigParams{TaskID: task.ID, ConfigID: configID}).Body)
+	json.Unmarshal(callMethod(t, h, "tasks/pushNotificationConfig/list", a2a.ListTaskPushConfigParams{TaskID: task.ID}), &listed)
+	if len(listed) != 0 {
+		t.Errorf("expected no configs after delete, got %d", len(listed))
+	}
+}
+
+// headerValue reads a header whatever the case of its name
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+func hasMessage(history []a2a.Message, messageID string) bool {
+	for _, message := range history {
+		if message.MessageID == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+func validState(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateSubmitted, a2a.TaskStateWorking, a2a.TaskStateInputRequired, a2a.TaskStateAuthRequired,
+		a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
+
+func terminal(state a2a.TaskState) bool {
+	switch state {
+	case a2a.TaskStateCompleted, a2a.TaskStateFailed, a2a.TaskStateCanceled, a2a.TaskStateRejected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package conformance
+
+import (
+	"testing"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+// TestServerlessHandlerConformance runs the scenarios against a handler
+// with push notifications on, so the push config scenario runs rather than
+// skips: its card claims them, and a failure is a conformance failure.
+func TestServerlessHandlerConformance(t *testing.T) {
+	Run(t, func(t *testing.T) Handler {
+		a2aHandler := a2aTypes.NewServerlessA2AHandler(a2aTypes.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), &a2atest.PushNotifier{},
+			a2aTypes.WithPushConfigStore(a2aTypes.NewMemoryPushConfigStore()))
+		card := agentcard.New("Test Agent", a2atest.AgentURL, agentcard.WithPushNotifications(true))
+		return handler.NewHandler(a2aHandler, card)
+	})
+}
@@ -0,0 +1,190 @@
+// Package handler is the public surface of the serverless runtime's HTTP
+// layer: the Handler that serves an agent's card and JSON-RPC endpoint, the
+// routers that put several agents or interface versions behind one
+// deployment, and adapters to API Gateway and net/http. Together with
+// pkg/a2aserverless and pkg/store it lets programs build their own Lambda or
+// binary around the handler cmd/lambda and cmd/server run.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	internalHandler "github.com/a2aproject/a2a-serverless/internal/handler"
+	"github.com/a2aproject/a2a-serverless/pkg/a2aserverless"
+)
+
+// Handler serves one agent
+type Handler = internalHandler.Handler
+
+// Request is an HTTP request, shaped like the ones API Gateway delivers:
+// header names are lower case
+type Request = internalHandler.Request
+
+// Response is an HTTP response
+type Response = internalHandler.Response
+
+// Option configures optional Handler behaviour
+type Option = internalHandler.Option
+
+// Router serves every agent of a registry under /agents/{id}
+type Router = internalHandler.Router
+
+// VersionRouter serves an agent's current interface and earlier ones side
+// by side
+type VersionRouter = internalHandler.VersionRouter
+
+// InterfaceVersion is an interface a VersionRouter serves beside the
+// current one
+type InterfaceVersion = internalHandler.InterfaceVersion
+
+// RequestAdapter rewrites a request sent in another protocol version
+type RequestAdapter = internalHandler.RequestAdapter
+
+// EventStream receives a streaming response while it is written
+type EventStream = internalHandler.EventStream
+
+// HandlerFunc serves a request
+type HandlerFunc = internalHandler.HandlerFunc
+
+// Middleware wraps request handling
+type Middleware = internalHandler.Middleware
+
+// CORSConfig sets which web origins may call the agent
+type CORSConfig = internalHandler.CORSConfig
+
+// MethodFunc serves a JSON-RPC method registered with
+// Handler.RegisterMethod. An error it returns is answered as itself when it
+// is a *JSONRPCError, and otherwise as the built-in methods' errors are.
+type MethodFunc = internalHandler.MethodFunc
+
+// MethodCall is a JSON-RPC call as the hooks of Handler.OnBeforeMethod and
+// Handler.OnAfterMethod see it
+type MethodCall = internalHandler.MethodCall
+
+// MethodResult is how a call ended, as after hooks see it
+type MethodResult = internalHandler.MethodResult
+
+// BeforeMethodHook runs before a call's method and may refuse it
+type BeforeMethodHook = internalHandler.BeforeMethodHook
+
+// AfterMethodHook runs once a call is answered and may change the answer
+type AfterMethodHook = internalHandler.AfterMethodHook
+
+// Principal is the authenticated caller of a call
+type Principal = a2aTypes.Principal
+
+// JSONRPCError is a JSON-RPC error a MethodFunc can return to choose the
+// code, message and data of its answer
+type JSONRPCError = a2aTypes.JSONRPCError
+
+// RequestHandler is what the adapters serve: a Handler, a Router or a
+// VersionRouter
+type RequestHandler interface {
+	HandleRequest(ctx context.Context, req Request) Response
+}
+
+// OpenAPIPath is where an OpenAPI document of a Handler is conventionally
+// served
+const OpenAPIPath = internalHandler.OpenAPIPath
+
+// NewHandler creates a handler serving agentCard and running calls on
+// a2aHandler
+func NewHandler(a2aHandler *a2aserverless.ServerlessA2AHandler, agentCard a2a.AgentCard, opts ...Option) *Handler {
+	return internalHandler.NewHandler(a2aHandler, agentCard, opts...)
+}
+
+// NewRouter creates a router over per-agent handlers keyed by agent ID
+func NewRouter(handlers map[string]*Handler) *Router {
+	return internalHandler.NewRouter(handlers)
+}
+
+// NewVersionRouter creates a router serving current at the root and each
+// version under its path
+func NewVersionRouter(current *Handler, versions ...InterfaceVersion) *VersionRouter {
+	return internalHandler.NewVersionRouter(current, versions...)
+}
+
+// ContextWithEventStream has streaming methods write their frames to stream
+// as events arrive, rather than buffering them into Response.Body
+func ContextWithEventStream(ctx context.Context, stream EventStream) context.Context {
+	return internalHandler.ContextWithEventStream(ctx, stream)
+}
+
+// WithAuthenticator has JSON-RPC calls pass authenticator. Without it
+// every request is accepted.
+func WithAuthenticator(authenticator *a2aserverless.Authenticator) Option {
+	return internalHandler.WithAuthenticator(authenticator)
+}
+
+// WithTracing gives each call a server span from tracing
+func WithTracing(tracing *a2aserverless.Tracing) Option {
+	return internalHandler.WithTracing(tracing)
+}
+
+// WithAuditLog writes a record of each call to auditLog
+func WithAuditLog(auditLog a2aserverless.AuditLog) Option {
+	return internalHandler.WithAuditLog(auditLog)
+}
+
+// WithStrictJSONRPC refuses requests that bend the JSON-RPC 2.0 envelope
+func WithStrictJSONRPC() Option {
+	return internalHandler.WithStrictJSONRPC()
+}
+
+// WithLocalization serves translations of the card chosen by Accept-Language
+func WithLocalization(config a2aserverless.LocalizationConfig) Option {
+	return internalHandler.WithLocalization(config)
+}
+
+// WithStreamTiming sets the retry delay suggested to SSE clients and the
+// idle interval after which a live stream sends a keep-alive comment
+func WithStreamTiming(retry, keepAlive time.Duration) Option {
+	return internalHandler.WithStreamTiming(retry, keepAlive)
+}
+
+// WithProtocolVersion also serves clients that name version in the
+// A2A-Version header, rewriting their requests with adapt first
+func WithProtocolVersion(version string, adapt RequestAdapter) Option {
+	return internalHandler.WithProtocolVersion(version, adapt)
+}
+
+// WithCORS answers browsers with the CORS headers config describes, in
+// place of the defaults that allow every origin
+func WithCORS(config CORSConfig) Option {
+	return internalHandler.WithCORS(config)
+}
+
+// WithMiddleware runs each request through middleware before it is routed,
+// the first listed outermost
+func WithMiddleware(middleware ...Middleware) Option {
+	return internalHandler.WithMiddleware(middleware...)
+}
+
+// WithAgentCardPath also serves the agent card at path
+func WithAgentCardPath(path string) Option {
+	return internalHandler.WithAgentCardPath(path)
+}
+
+// WithMaxBodySize answers requests whose body is longer than bytes with 413
+func WithMaxBodySize(bytes int) Option {
+	return internalHandler.WithMaxBodySize(bytes)
+}
+
+// WithLogger logs every request to logger, in place of the logger carried
+// by the request's context
+func WithLogger(logger *slog.Logger) Option {
+	return internalHandler.WithLogger(logger)
+}
+
+// DecodeParams decodes the params of a MethodFunc into target as strictly
+// as the built-in methods decode theirs. Its errors are answered as
+// Invalid params.
+func DecodeParams(params json.RawMessage, target any) error {
+	return a2aTypes.DecodeParams(params, target)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/aws/aws-lambda-go/events"
+
+	"github.com/a2aproject/a2a-serverless/pkg/a2aserverless"
+	"github.com/a2aproject/a2a-serverless/pkg/a2atest"
+	"github.com/a2aproject/a2a-serverless/pkg/agentcard"
+)
+
+func newTestHandler() *Handler {
+	a2aHandler := a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil,
+		a2aserverless.WithExecutor(a2atest.Respond("hello back")))
+	return NewHandler(a2aHandler, agentcard.New("Embedded Agent", a2atest.AgentURL))
+}
+
+func TestLambdaHandler(t *testing.T) {
+	serve := LambdaHandler(newTestHandler())
+
+	response, err := serve(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet, Path: "/.well-known/agent.json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var card a2a.AgentCard
+	if err := json.Unmarshal([]byte(response.Body), &card); err != nil || response.StatusCode != http.StatusOK || card.Name != "Embedded Agent" {
+		t.Fatalf("expected the agent card, got %d %s", response.StatusCode, response.Body)
+	}
+
+	request := a2atest.SendMessageRequest(a2atest.UserMessage("msg-1", "hello"))
+	response, err = serve(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: request.Method, Path: request.URL, Headers: request.Headers, Body: request.Body})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	task := a2atest.DecodeTask(t, Response{Status: response.StatusCode, Headers: response.Headers, Body: response.Body})
+	if task.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected the executor to complete the task, got %s", task.Status.State)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	server := httptest.NewServer(NewHTTPHandler(newTestHandler(), slog.New(slog.DiscardHandler)))
+	defer server.Close()
+
+	request := a2atest.SendMessageRequest(a2atest.UserMessage("msg-1", "hello"))
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(request.Body))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	task := a2atest.DecodeTask(t, Response{Status: resp.StatusCode, Body: string(body)})
+	if got := task.History[0].Parts[0].(a2a.TextPart).Text; got != "hello" {
+		t.Errorf("expected the sent text in history, got %q", got)
+	}
+
+	// A body over the limit is refused rather than cut short
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader(strings.Repeat(" ", maxBodyBytes+1)))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for an oversized body, got %d", resp.StatusCode)
+	}
+}
+
+func TestRegisterMethod(t *testing.T) {
+	h := newTestHandler()
+	err := h.RegisterMethod("vendor/lookup", func(ctx context.Context, params json.RawMessage) (any, error) {
+		var p struct {
+			SKU string `json:"sku"`
+		}
+		if err := DecodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.SKU == "" {
+			return nil, &JSONRPCError{Code: -32050, Message: "Unknown SKU"}
+		}
+		return map[string]int{"stock": 3}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	serve := LambdaHandler(h)
+	call := func(params string) string {
+		response, _ := serve(context.Background(), events.APIGatewayProxyRequest{
+			HTTPMethod: http.MethodPost,
+			Path:       "/",
+			Headers:    map[string]string{"content-type": "application/json"},
+			Body:       `{"jsonrpc":"2.0","id":1,"method":"vendor/lookup","params":` + params + `}`,
+		})
+		return response.Body
+	}
+	if body := call(`{"sku":"A1"}`); !strings.Contains(body, `"result":{"stock":3}`) {
+		t.Errorf("expected the method's result, got %s", body)
+	}
+	if body := call(`{}`); !strings.Contains(body, `"code":-32050`) {
+		t.Errorf("expected the method's error, got %s", body)
+	}
+}
+
+func TestLambdaHandlerHeadersAsSent(t *testing.T) {
+	a2aHandler := a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, a2atest.NewTaskStore(), a2atest.NewEventStore(), nil,
+		a2aserverless.WithExecutor(a2atest.Respond("hello back")))
+	h := NewHandler(a2aHandler, agentcard.New("Embedded Agent", a2atest.AgentURL),
+		WithCORS(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}))
+
+	// API Gateway passes header names through as the client sent them
+	request := a2atest.SendMessageRequest(a2atest.UserMessage("msg-1", "hello"))
+	response, _ := LambdaHandler(h)(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodPost,
+		Path:       "/",
+		Headers:    map[string]string{"Content-Type": "application/json", "Origin": "https://app.example.com"},
+		Body:       request.Body,
+	})
+	if !strings.Contains(response.Body, `"result"`) || response.Headers["Access-Control-Allow-Origin"] != "https://app.example.com" {
+		t.Errorf("expected the call answered for the origin, got %v %s", response.Headers, response.Body)
+	}
+}
+
+// recordingQueue keeps the messages queued on it
+type recordingQueue struct {
+	bodies []string
+}
+
+func (q *recordingQueue) QueueMessage(ctx context.Context, params a2a.MessageSendParams, delay time.Duration) error {
+	body, err := json.Marshal(params)
+	q.bodies = append(q.bodies, string(body))
+	return err
+}
+
+func TestWorkQueueLambdaHandler(t *testing.T) {
+	ctx := context.Background()
+	tasks, eventStore := a2atest.NewTaskStore(), a2atest.NewEventStore()
+	queue := &recordingQueue{}
+	front := NewHandler(a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, tasks, eventStore, nil, a2aserverless.WithWorkQueue(queue)),
+		agentcard.New("Embedded Agent", a2atest.AgentURL))
+	task := a2atest.DecodeTask(t, front.HandleRequest(ctx, a2atest.SendMessageRequest(a2atest.UserMessage("msg-1", "hello"))))
+	if len(queue.bodies) != 1 {
+		t.Fatalf("expected the message queued, got %v", queue.bodies)
+	}
+
+	worker := a2aserverless.NewServerlessA2AHandler(a2aserverless.ServerlessConfig{}, tasks, eventStore, nil, a2aserverless.WithExecutor(a2atest.Respond("hello back")))
+	consume := WorkQueueLambdaHandler(a2aserverless.NewWorkQueueConsumer(worker, a2aserverless.WorkQueueConfig{}))
+	response, err := consume(ctx, events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "1", Body: queue.bodies[0]},
+		{MessageId: "2", Body: "not json"},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(response.BatchItemFailures) != 1 || response.BatchItemFailures[0].ItemIdentifier != "2" {
+		t.Errorf("expected only the bad record reported, got %+v", response.BatchItemFailures)
+	}
+	if stored, _ := tasks.GetTask(ctx, task.ID); stored.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("expected the worker to complete the queued task, got %s", stored.Status.State)
+	}
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// maxBodyBytes matches the API Gateway payload limit
+const maxBodyBytes = 10 << 20
+
+// httpAdapter turns net/http requests into handler requests shaped like the
+// ones API Gateway delivers
+type httpAdapter struct {
+	h      RequestHandler
+	logger *slog.Logger
+	nextID atomic.Int64
+}
+
+// NewHTTPHandler serves h over net/http, streaming SSE responses as they are
+// written. Each request logs to logger and is given a sequential ID, as
+// API Gateway gives each its own.
+func NewHTTPHandler(h RequestHandler, logger *slog.Logger) http.Handler {
+	return &httpAdapter{h: h, logger: logger}
+}
+
+func (a *httpAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	// A body cut short would reach the handler as malformed JSON, so it is
+	// refused as the handler's WithMaxBodySize refuses one
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	// API Gateway lower-cases header names, and the handler relies on it
+	headers := make(map[string]string, len(r.Header))
+	for name := range r.Header {
+		headers[strings.ToLower(name)] = r.Header.Get(name)
+	}
+
+	stream := &responseStream{w: w}
+	ctx := a2aTypes.ContextWithLogger(r.Context(), a.logger)
+	ctx = ContextWithEventStream(ctx, stream)
+	resp := a.h.HandleRequest(ctx, Request{
+		Method:    r.Method,
+		URL:       r.URL.Path,
+		Headers:   headers,
+		Body:      string(body),
+		RequestID: fmt.Sprintf("local-%d", a.nextID.Add(1)),
+	})
+
+	if stream.started {
+		return
+	}
+	for name, value := range resp.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(resp.Status)
+	io.WriteString(w, resp.Body)
+}
+
+// responseStream sends SSE frames to the client as the handler writes them,
+// which API Gateway cannot
+type responseStream struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (s *responseStream) Start(status int, headers map[string]string) {
+	for name, value := range headers {
+		s.w.Header().Set(name, value)
+	}
+	s.w.WriteHeader(status)
+	s.started = true
+}
+
+func (s *responseStream) Write(p []byte) (int, error) {
+	return s.w.Write(p)
+}
+
+func (s *responseStream) Flush() {
+	http.NewResponseController(s.w).Flush()
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+	"github.com/a2aproject/a2a-serverless/pkg/a2aserverless"
+)
+
+// LambdaHandler adapts h to API Gateway proxy events, for passing to
+// lambda.Start in a program's own main. The caller ARN is taken from the
+// request context, which API Gateway only fills for IAM-authorized routes.
+// Warm-up pings, config refreshes and the other per-invocation work of
+// cmd/lambda are left to the program.
+func LambdaHandler(h RequestHandler) func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response := h.HandleRequest(ctx, FromAPIGateway(request))
+		return ToAPIGateway(response), nil
+	}
+}
+
+// FromAPIGateway converts an API Gateway proxy request
+func FromAPIGateway(request events.APIGatewayProxyRequest) Request {
+	return Request{
+		Method:    request.HTTPMethod,
+		URL:       request.Path,
+		Headers:   request.Headers,
+		Body:      request.Body,
+		RequestID: request.RequestContext.RequestID,
+		// Only set when the route uses IAM authorization
+		CallerARN: request.RequestContext.Identity.UserArn,
+	}
+}
+
+// ToAPIGateway converts a response to an API Gateway proxy response
+func ToAPIGateway(response Response) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		StatusCode: response.Status,
+		Headers:    response.Headers,
+		Body:       response.Body,
+	}
+}
+
+// WorkQueueLambdaHandler adapts consumer to SQS events, for passing to
+// lambda.Start in the main of a worker consuming the work, priority and
+// retry queues. Records are told apart by the queue they came from.
+// Only the records that failed are reported back for SQS to redeliver, so
+// the event source mapping must enable ReportBatchItemFailures.
+func WorkQueueLambdaHandler(consumer *a2aserverless.WorkQueueConsumer) func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		records := make([]a2aTypes.BatchRecord, len(event.Records))
+		for i, record := range event.Records {
+			records[i] = a2aTypes.BatchRecord{MessageID: record.MessageId, Body: record.Body, Source: record.EventSourceARN}
+		}
+		var response events.SQSEventResponse
+		for _, messageID := range consumer.Process(ctx, records) {
+			response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: messageID})
+		}
+		return response, nil
+	}
+}
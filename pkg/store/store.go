@@ -0,0 +1,200 @@
+// Package store is the public surface of the serverless runtime's
+// persistence: the interfaces a2aserverless.NewServerlessA2AHandler is built
+// over and their DynamoDB and SQS implementations. Programs embedding the
+// handler can use these stores, wrap them, or bring their own.
+package store
+
+import (
+	"context"
+
+	a2aTypes "github.com/a2aproject/a2a-serverless/internal/a2a"
+)
+
+// TaskStore persists tasks
+type TaskStore = a2aTypes.TaskStore
+
+// EventStore persists the events of tasks
+type EventStore = a2aTypes.EventStore
+
+// PushNotifier sends the push notifications of task events
+type PushNotifier = a2aTypes.PushNotifier
+
+// Option configures a store. It is the same type as
+// a2aserverless.RuntimeOption, so one list of options can serve both.
+type Option = a2aTypes.RuntimeOption
+
+// MessageQueue holds messages for workers to execute, after a delay
+type MessageQueue = a2aTypes.MessageQueue
+
+// DynamoDBAPI is the subset of the DynamoDB client the stores use
+type DynamoDBAPI = a2aTypes.DynamoDBAPI
+
+// SQSAPI is the subset of the SQS client the push notifier uses
+type SQSAPI = a2aTypes.SQSAPI
+
+// AWSTaskStore keeps tasks in a DynamoDB table
+type AWSTaskStore = a2aTypes.AWSTaskStore
+
+// AWSEventStore keeps events in a DynamoDB table
+type AWSEventStore = a2aTypes.AWSEventStore
+
+// AWSSQSPushNotifier queues push notifications on SQS for the worker to deliver
+type AWSSQSPushNotifier = a2aTypes.AWSSQSPushNotifier
+
+// SQSMessageQueue is a MessageQueue on an SQS queue
+type SQSMessageQueue = a2aTypes.SQSMessageQueue
+
+// PriorityQueues is a MessageQueue routing each message to the queue of its
+// priority
+type PriorityQueues = a2aTypes.PriorityQueues
+
+// ContentCipher encrypts message and artifact content before it is stored
+type ContentCipher = a2aTypes.ContentCipher
+
+// TaskHeartbeatStore is implemented by task stores that record a heartbeat
+// without rewriting the rest of the task
+type TaskHeartbeatStore = a2aTypes.TaskHeartbeatStore
+
+// MessageScheduler holds messages starting beyond the work queue's delay
+type MessageScheduler = a2aTypes.MessageScheduler
+
+// AWSMessageScheduler is a MessageScheduler on a DynamoDB table, released
+// by maintenance passes
+type AWSMessageScheduler = a2aTypes.AWSMessageScheduler
+
+// ContextLock serializes the executions within a context
+type ContextLock = a2aTypes.ContextLock
+
+// AWSContextLock is a ContextLock on a DynamoDB table
+type AWSContextLock = a2aTypes.AWSContextLock
+
+// MessageDeduplicator remembers the messages a handler received
+type MessageDeduplicator = a2aTypes.MessageDeduplicator
+
+// AWSMessageDeduplicator is a MessageDeduplicator on a DynamoDB table
+type AWSMessageDeduplicator = a2aTypes.AWSMessageDeduplicator
+
+// DynamoDBScanAPI is the DynamoDB call task listers scan with
+type DynamoDBScanAPI = a2aTypes.DynamoDBScanAPI
+
+// DynamoDBBackfillAPI is the DynamoDB calls BackfillEventRecordedAt makes
+type DynamoDBBackfillAPI = a2aTypes.DynamoDBBackfillAPI
+
+// TaskLister lists every task for admin/tasks/list
+type TaskLister = a2aTypes.TaskLister
+
+// RunningTaskLister lists the tasks maintenance passes check for missed
+// deadlines
+type RunningTaskLister = a2aTypes.RunningTaskLister
+
+// AWSTaskLister lists the tasks of an AWSTaskStore's table
+type AWSTaskLister = a2aTypes.AWSTaskLister
+
+// PushConfigStore keeps the push notification configs set on tasks
+type PushConfigStore = a2aTypes.PushConfigStore
+
+// MemoryPushConfigStore is a PushConfigStore in memory
+type MemoryPushConfigStore = a2aTypes.MemoryPushConfigStore
+
+// AWSPushConfigStore is a PushConfigStore on a DynamoDB table
+type AWSPushConfigStore = a2aTypes.AWSPushConfigStore
+
+// NewAWSTaskStore creates a task store on tableName. keyPrefix is prepended
+// to every key so agents can share a table; a single-agent deployment
+// passes "".
+func NewAWSTaskStore(client DynamoDBAPI, tableName string, keyPrefix string, opts ...Option) *AWSTaskStore {
+	return a2aTypes.NewAWSTaskStore(client, tableName, keyPrefix, opts...)
+}
+
+// NewAWSEventStore creates an event store on tableName, with keys prefixed
+// as for NewAWSTaskStore
+func NewAWSEventStore(client DynamoDBAPI, tableName string, keyPrefix string, opts ...Option) *AWSEventStore {
+	return a2aTypes.NewAWSEventStore(client, tableName, keyPrefix, opts...)
+}
+
+// NewAWSSQSPushNotifier creates a push notifier sending to queueURL. With a
+// signing key each message carries an HMAC of its body; tracing may be nil.
+func NewAWSSQSPushNotifier(client SQSAPI, queueURL string, signingKey string, tracing *a2aTypes.Tracing) *AWSSQSPushNotifier {
+	return a2aTypes.NewAWSSQSPushNotifier(client, queueURL, signingKey, tracing)
+}
+
+// NewSQSMessageQueue creates a message queue sending to queueURL, with the
+// queue's message delay
+func NewSQSMessageQueue(client SQSAPI, queueURL string) *SQSMessageQueue {
+	return a2aTypes.NewSQSMessageQueue(client, queueURL)
+}
+
+// NewPriorityQueues routes messages by priority. A nil high or low queue
+// takes those messages to the normal one.
+func NewPriorityQueues(high, normal, low MessageQueue) *PriorityQueues {
+	return a2aTypes.NewPriorityQueues(high, normal, low)
+}
+
+// NewAWSMessageScheduler creates a scheduler on tableName, with keys
+// prefixed as for NewAWSTaskStore
+func NewAWSMessageScheduler(client DynamoDBAPI, tableName, keyPrefix string) *AWSMessageScheduler {
+	return a2aTypes.NewAWSMessageScheduler(client, tableName, keyPrefix)
+}
+
+// NewAWSContextLock creates a context lock on tableName
+func NewAWSContextLock(client DynamoDBAPI, tableName string, opts ...Option) *AWSContextLock {
+	return a2aTypes.NewAWSContextLock(client, tableName, opts...)
+}
+
+// NewAWSMessageDeduplicator creates a deduplicator on tableName, with keys
+// prefixed as for NewAWSTaskStore
+func NewAWSMessageDeduplicator(client DynamoDBAPI, tableName, keyPrefix string, opts ...Option) *AWSMessageDeduplicator {
+	return a2aTypes.NewAWSMessageDeduplicator(client, tableName, keyPrefix, opts...)
+}
+
+// NewAWSTaskLister creates a lister for the table an AWSTaskStore with the
+// same keyPrefix writes
+func NewAWSTaskLister(client DynamoDBScanAPI, tableName, keyPrefix string) *AWSTaskLister {
+	return a2aTypes.NewAWSTaskLister(client, tableName, keyPrefix)
+}
+
+// BackfillEventRecordedAt sets recorded_at on the events of an
+// AWSEventStore's table saved without it, which task_id-index leaves out
+func BackfillEventRecordedAt(ctx context.Context, client DynamoDBBackfillAPI, tableName string) (int, error) {
+	return a2aTypes.BackfillEventRecordedAt(ctx, client, tableName)
+}
+
+// NewMemoryPushConfigStore creates an in-memory push config store
+func NewMemoryPushConfigStore() *MemoryPushConfigStore {
+	return a2aTypes.NewMemoryPushConfigStore()
+}
+
+// NewAWSPushConfigStore creates a push config store on tableName, with keys
+// prefixed as for NewAWSTaskStore
+func NewAWSPushConfigStore(client DynamoDBAPI, tableName, keyPrefix string) *AWSPushConfigStore {
+	return a2aTypes.NewAWSPushConfigStore(client, tableName, keyPrefix)
+}
+
+// WithStorageCompression gzips the task and event JSON the DynamoDB stores
+// write when it is longer than threshold bytes. 0 turns compression off.
+func WithStorageCompression(threshold int) Option {
+	return a2aTypes.WithStorageCompression(threshold)
+}
+
+// NewContentCipher creates a cipher from a base64-encoded 32-byte key
+func NewContentCipher(key string) (*ContentCipher, error) {
+	return a2aTypes.NewContentCipher(key)
+}
+
+// NewEncryptedTaskStore wraps store so message and artifact content is only
+// ever written encrypted
+func NewEncryptedTaskStore(store TaskStore, cipher *ContentCipher) TaskStore {
+	return a2aTypes.NewEncryptedTaskStore(store, cipher)
+}
+
+// NewEncryptedEventStore wraps store so event content is only ever written
+// encrypted
+func NewEncryptedEventStore(store EventStore, cipher *ContentCipher) EventStore {
+	return a2aTypes.NewEncryptedEventStore(store, cipher)
+}
+
+// NewEncryptedMessageDeduplicator wraps dedup so the direct replies it
+// remembers are only ever written encrypted
+func NewEncryptedMessageDeduplicator(dedup MessageDeduplicator, cipher *ContentCipher) MessageDeduplicator {
+	return a2aTypes.NewEncryptedMessageDeduplicator(dedup, cipher)
+}